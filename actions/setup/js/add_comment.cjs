@@ -16,6 +16,7 @@ const { resolveTargetRepoConfig, resolveAndValidateRepo } = require("./repo_help
 const { getMissingInfoSections } = require("./missing_messages_helper.cjs");
 const { getMessages } = require("./messages_core.cjs");
 const { sanitizeContent } = require("./sanitize_content.cjs");
+const { sanitizeUrlDomains, extractDomainsFromUrl } = require("./sanitize_content_core.cjs");
 const { MAX_COMMENT_LENGTH, MAX_MENTIONS, MAX_LINKS, enforceCommentLimits } = require("./comment_limit_helpers.cjs");
 const { logStagedPreviewInfo } = require("./staged_preview.cjs");
 const { ERR_NOT_FOUND } = require("./error_codes.cjs");
@@ -286,6 +287,10 @@ async function main(config = {}) {
   const maxCount = config.max || 20;
   const { defaultTargetRepo, allowedRepos } = resolveTargetRepoConfig(config);
 
+  // Links in comments are restricted to allowed_domains when configured. Links to the
+  // GitHub instance the workflow runs on are always allowed.
+  const allowedLinkDomains = Array.isArray(config.allowed_domains) && config.allowed_domains.length > 0 ? [...config.allowed_domains, ...extractDomainsFromUrl(process.env.GITHUB_SERVER_URL || "https://github.com")] : [];
+
   // Check if we're in staged mode
   const isStaged = process.env.GH_AW_SAFE_OUTPUTS_STAGED === "true";
 
@@ -298,6 +303,9 @@ async function main(config = {}) {
   if (allowedRepos.size > 0) {
     core.info(`Allowed repos: ${Array.from(allowedRepos).join(", ")}`);
   }
+  if (allowedLinkDomains.length > 0) {
+    core.info(`Allowed link domains: ${allowedLinkDomains.join(", ")}`);
+  }
   if (hideOlderCommentsEnabled) {
     core.info("Hide-older-comments is enabled");
   }
@@ -474,6 +482,11 @@ async function main(config = {}) {
     // so they can be @mentioned in the generated comment.
     processedBody = sanitizeContent(processedBody, { allowedAliases: parentAuthors });
 
+    // Redact links to domains outside allowed_domains
+    if (allowedLinkDomains.length > 0) {
+      processedBody = sanitizeUrlDomains(processedBody, allowedLinkDomains);
+    }
+
     // Enforce max limits before processing (validates user-provided content)
     try {
       enforceCommentLimits(processedBody);
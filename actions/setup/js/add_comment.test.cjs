@@ -1572,6 +1572,75 @@ describe("add_comment", () => {
     });
   });
 
+  describe("allowed link domains", () => {
+    let originalAllowedDomains;
+
+    beforeEach(() => {
+      // Domains allowed by the network configuration; allowed_domains restricts comment links further
+      originalAllowedDomains = process.env.GH_AW_ALLOWED_DOMAINS;
+      process.env.GH_AW_ALLOWED_DOMAINS = "docs.example.com,phishing.test,github.com";
+    });
+
+    afterEach(() => {
+      if (originalAllowedDomains === undefined) {
+        delete process.env.GH_AW_ALLOWED_DOMAINS;
+      } else {
+        process.env.GH_AW_ALLOWED_DOMAINS = originalAllowedDomains;
+      }
+    });
+
+    it("should redact links to domains outside allowed_domains", async () => {
+      const addCommentScript = fs.readFileSync(path.join(__dirname, "add_comment.cjs"), "utf8");
+
+      let capturedBody = null;
+      mockGithub.rest.issues.createComment = async params => {
+        capturedBody = params.body;
+        return {
+          data: {
+            id: 12345,
+            html_url: "https://github.com/owner/repo/issues/42#issuecomment-12345",
+          },
+        };
+      };
+
+      const handler = await eval(`(async () => { ${addCommentScript}; return await main({ allowed_domains: ["docs.example.com"] }); })()`);
+
+      const message = {
+        type: "add_comment",
+        body: "See https://docs.example.com/guide and https://github.com/owner/repo/issues/1, not https://phishing.test/login",
+      };
+
+      const result = await handler(message, {});
+
+      expect(result.success).toBe(true);
+      expect(capturedBody).toContain("https://docs.example.com/guide");
+      expect(capturedBody).toContain("https://github.com/owner/repo/issues/1");
+      expect(capturedBody).not.toContain("phishing.test/login");
+    });
+
+    it("should not restrict links when allowed_domains is empty", async () => {
+      const addCommentScript = fs.readFileSync(path.join(__dirname, "add_comment.cjs"), "utf8");
+
+      let capturedBody = null;
+      mockGithub.rest.issues.createComment = async params => {
+        capturedBody = params.body;
+        return {
+          data: {
+            id: 12345,
+            html_url: "https://github.com/owner/repo/issues/42#issuecomment-12345",
+          },
+        };
+      };
+
+      const handler = await eval(`(async () => { ${addCommentScript}; return await main({ allowed_domains: [] }); })()`);
+
+      const result = await handler({ type: "add_comment", body: "See https://phishing.test/login" }, {});
+
+      expect(result.success).toBe(true);
+      expect(capturedBody).toContain("https://phishing.test/login");
+    });
+  });
+
   let enforceCommentLimits;
   let MAX_COMMENT_LENGTH;
   let MAX_MENTIONS;
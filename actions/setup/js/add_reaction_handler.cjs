@@ -0,0 +1,127 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * @typedef {import('./types/handler-factory').HandlerFactoryFunction} HandlerFactoryFunction
+ */
+
+const { getErrorMessage, isLockedError } = require("./error_helpers.cjs");
+const { logStagedPreviewInfo } = require("./staged_preview.cjs");
+const { VALID_REACTIONS, resolveReactionTarget, addReactionToTarget } = require("./reaction_helpers.cjs");
+
+/**
+ * Type constant for handler identification
+ */
+const HANDLER_TYPE = "add_reaction";
+
+/**
+ * Main handler factory for add_reaction
+ * Returns a message handler function that processes individual add_reaction messages
+ * @type {HandlerFactoryFunction}
+ */
+async function main(config = {}) {
+  // Extract configuration
+  const allowedReactions = config.allowed_reactions || [];
+  const maxCount = config.max || 1;
+
+  // Check if we're in staged mode
+  const isStaged = process.env.GH_AW_SAFE_OUTPUTS_STAGED === "true";
+
+  core.info(`Add reaction configuration: max=${maxCount}`);
+  if (allowedReactions.length > 0) {
+    core.info(`Allowed reactions: ${allowedReactions.join(", ")}`);
+  }
+
+  // Track how many items we've processed for max limit
+  let processedCount = 0;
+
+  /**
+   * Message handler function that processes a single add_reaction message
+   * @param {Object} message - The add_reaction message to process
+   * @param {Object} resolvedTemporaryIds - Map of temporary IDs to {repo, number} (unused for add_reaction)
+   * @returns {Promise<Object>} Result with success/error status
+   */
+  return async function handleAddReaction(message, resolvedTemporaryIds) {
+    // Check if we've hit the max limit
+    if (processedCount >= maxCount) {
+      core.warning(`Skipping add_reaction: max count of ${maxCount} reached`);
+      return {
+        success: false,
+        error: `Max count of ${maxCount} reached`,
+      };
+    }
+
+    processedCount++;
+
+    const reaction = String(message.reaction ?? "");
+    if (!VALID_REACTIONS.includes(reaction)) {
+      core.warning(`Invalid reaction "${reaction}". Valid reactions are: ${VALID_REACTIONS.join(", ")}`);
+      return {
+        success: false,
+        error: `Invalid reaction "${reaction}"`,
+      };
+    }
+
+    if (allowedReactions.length > 0 && !allowedReactions.includes(reaction)) {
+      core.warning(`Reaction "${reaction}" is not in allowed-reactions list [${allowedReactions.join(", ")}]. Skipping.`);
+      return {
+        success: false,
+        error: `Reaction "${reaction}" is not in allowed-reactions list`,
+      };
+    }
+
+    const { target, error } = resolveReactionTarget(context.eventName, context.payload, context.repo);
+    if (!target) {
+      core.warning(`Event "${context.eventName}" has no issue, pull request, discussion, or comment to react to: ${error}`);
+      return {
+        success: false,
+        error: `No reactable item for event "${context.eventName}"`,
+      };
+    }
+
+    core.info(`Adding ${reaction} reaction to ${target.description}`);
+
+    // If in staged mode, preview without executing
+    if (isStaged) {
+      logStagedPreviewInfo(`Would add ${reaction} reaction to ${target.description}`);
+      return {
+        success: true,
+        staged: true,
+        previewInfo: {
+          reaction,
+          target: target.description,
+        },
+      };
+    }
+
+    try {
+      await addReactionToTarget(target, reaction, context.repo);
+
+      core.info(`Successfully added ${reaction} reaction to ${target.description}`);
+      return {
+        success: true,
+        reaction,
+        target: target.description,
+      };
+    } catch (error) {
+      // Reactions cannot be added to locked items; treat this as a skip rather than a failure
+      if (isLockedError(error)) {
+        core.info(`Cannot add reaction: ${target.description} is locked`);
+        return {
+          success: true,
+          skipped: true,
+          reason: "locked",
+        };
+      }
+
+      const errorMessage = getErrorMessage(error);
+      core.error(`Failed to add reaction: ${errorMessage}`);
+      return {
+        success: false,
+        error: errorMessage,
+      };
+    }
+  };
+}
+
+module.exports = { main, HANDLER_TYPE };
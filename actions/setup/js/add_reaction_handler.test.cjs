@@ -0,0 +1,187 @@
+// @ts-check
+import { describe, it, expect, beforeEach, afterEach, vi } from "vitest";
+const { main } = require("./add_reaction_handler.cjs");
+
+describe("add_reaction_handler", () => {
+  let mockCore;
+  let mockGithub;
+  let originalGlobals;
+  let originalStaged;
+
+  beforeEach(() => {
+    originalGlobals = { core: global.core, github: global.github, context: global.context };
+    originalStaged = process.env.GH_AW_SAFE_OUTPUTS_STAGED;
+    delete process.env.GH_AW_SAFE_OUTPUTS_STAGED;
+
+    mockCore = {
+      infos: /** @type {string[]} */ [],
+      warnings: /** @type {string[]} */ [],
+      errors: /** @type {string[]} */ [],
+      info: /** @param {string} msg */ msg => mockCore.infos.push(msg),
+      warning: /** @param {string} msg */ msg => mockCore.warnings.push(msg),
+      error: /** @param {string} msg */ msg => mockCore.errors.push(msg),
+    };
+
+    mockGithub = {
+      request: vi.fn().mockResolvedValue({ data: { id: 1 } }),
+      graphql: vi.fn(async query => {
+        if (query.includes("query")) {
+          return { repository: { discussion: { id: "D_looked_up" } } };
+        }
+        return { addReaction: { reaction: { id: "R_1", content: "EYES" } } };
+      }),
+    };
+
+    global.core = mockCore;
+    global.github = mockGithub;
+    global.context = { eventName: "issues", repo: { owner: "test-owner", repo: "test-repo" }, payload: { issue: { number: 42 } } };
+  });
+
+  afterEach(() => {
+    global.core = originalGlobals.core;
+    global.github = originalGlobals.github;
+    global.context = originalGlobals.context;
+    if (originalStaged === undefined) {
+      delete process.env.GH_AW_SAFE_OUTPUTS_STAGED;
+    } else {
+      process.env.GH_AW_SAFE_OUTPUTS_STAGED = originalStaged;
+    }
+  });
+
+  describe("targets", () => {
+    it("should react to an issue", async () => {
+      const handler = await main({});
+      const result = await handler({ reaction: "rocket" }, {});
+
+      expect(result).toEqual({ success: true, reaction: "rocket", target: "issue #42" });
+      expect(mockGithub.request).toHaveBeenCalledWith("POST /repos/test-owner/test-repo/issues/42/reactions", expect.objectContaining({ content: "rocket" }));
+    });
+
+    it("should react to a pull request for pull_request_target", async () => {
+      global.context = { eventName: "pull_request_target", repo: { owner: "test-owner", repo: "test-repo" }, payload: { pull_request: { number: 7 } } };
+      const handler = await main({});
+      const result = await handler({ reaction: "+1" }, {});
+
+      expect(result).toEqual({ success: true, reaction: "+1", target: "pull request #7" });
+      expect(mockGithub.request).toHaveBeenCalledWith("POST /repos/test-owner/test-repo/issues/7/reactions", expect.objectContaining({ content: "+1" }));
+    });
+
+    it("should react to an issue comment", async () => {
+      global.context = { eventName: "issue_comment", repo: { owner: "test-owner", repo: "test-repo" }, payload: { comment: { id: 99 } } };
+      const handler = await main({});
+      await handler({ reaction: "heart" }, {});
+
+      expect(mockGithub.request).toHaveBeenCalledWith("POST /repos/test-owner/test-repo/issues/comments/99/reactions", expect.objectContaining({ content: "heart" }));
+    });
+
+    it("should react to a review comment", async () => {
+      global.context = { eventName: "pull_request_review_comment", repo: { owner: "test-owner", repo: "test-repo" }, payload: { comment: { id: 55 } } };
+      const handler = await main({});
+      await handler({ reaction: "eyes" }, {});
+
+      expect(mockGithub.request).toHaveBeenCalledWith("POST /repos/test-owner/test-repo/pulls/comments/55/reactions", expect.objectContaining({ content: "eyes" }));
+    });
+
+    it("should react to a discussion with the node ID from the payload", async () => {
+      global.context = { eventName: "discussion", repo: { owner: "test-owner", repo: "test-repo" }, payload: { discussion: { number: 3, node_id: "D_payload" } } };
+      const handler = await main({});
+      const result = await handler({ reaction: "hooray" }, {});
+
+      expect(result).toEqual({ success: true, reaction: "hooray", target: "discussion #3" });
+      expect(mockGithub.graphql).toHaveBeenCalledTimes(1);
+      expect(mockGithub.graphql).toHaveBeenCalledWith(expect.stringContaining("mutation"), { subjectId: "D_payload", content: "HOORAY" });
+    });
+
+    it("should look up the discussion node ID when the payload has none", async () => {
+      global.context = { eventName: "discussion", repo: { owner: "test-owner", repo: "test-repo" }, payload: { discussion: { number: 3 } } };
+      const handler = await main({});
+      await handler({ reaction: "laugh" }, {});
+
+      expect(mockGithub.graphql).toHaveBeenCalledWith(expect.stringContaining("query"), { owner: "test-owner", repo: "test-repo", num: 3 });
+      expect(mockGithub.graphql).toHaveBeenCalledWith(expect.stringContaining("mutation"), { subjectId: "D_looked_up", content: "LAUGH" });
+    });
+
+    it("should react to a discussion comment", async () => {
+      global.context = { eventName: "discussion_comment", repo: { owner: "test-owner", repo: "test-repo" }, payload: { comment: { node_id: "DC_1" } } };
+      const handler = await main({});
+      await handler({ reaction: "-1" }, {});
+
+      expect(mockGithub.graphql).toHaveBeenCalledWith(expect.stringContaining("mutation"), { subjectId: "DC_1", content: "THUMBS_DOWN" });
+    });
+
+    it("should fail for events without a reactable item", async () => {
+      global.context = { eventName: "push", repo: { owner: "test-owner", repo: "test-repo" }, payload: {} };
+      const handler = await main({});
+      const result = await handler({ reaction: "eyes" }, {});
+
+      expect(result).toEqual({ success: false, error: 'No reactable item for event "push"' });
+      expect(mockGithub.request).not.toHaveBeenCalled();
+    });
+
+    it("should fail when the payload lacks the item", async () => {
+      global.context = { eventName: "issues", repo: { owner: "test-owner", repo: "test-repo" }, payload: {} };
+      const handler = await main({});
+      const result = await handler({ reaction: "eyes" }, {});
+
+      expect(result).toEqual({ success: false, error: 'No reactable item for event "issues"' });
+      expect(mockCore.warnings.some(msg => msg.includes("Issue number not found"))).toBe(true);
+    });
+  });
+
+  describe("validation", () => {
+    it("should reject unknown reactions", async () => {
+      const handler = await main({});
+      const result = await handler({ reaction: "thumbsup" }, {});
+
+      expect(result).toEqual({ success: false, error: 'Invalid reaction "thumbsup"' });
+      expect(mockGithub.request).not.toHaveBeenCalled();
+    });
+
+    it("should reject reactions outside allowed-reactions", async () => {
+      const handler = await main({ allowed_reactions: ["eyes"] });
+      const result = await handler({ reaction: "rocket" }, {});
+
+      expect(result).toEqual({ success: false, error: 'Reaction "rocket" is not in allowed-reactions list' });
+    });
+
+    it("should stop after the max count", async () => {
+      const handler = await main({ max: 1 });
+
+      const first = await handler({ reaction: "eyes" }, {});
+      expect(first.success).toBe(true);
+
+      const second = await handler({ reaction: "eyes" }, {});
+      expect(second).toEqual({ success: false, error: "Max count of 1 reached" });
+      expect(mockGithub.request).toHaveBeenCalledTimes(1);
+    });
+  });
+
+  describe("execution", () => {
+    it("should preview without reacting in staged mode", async () => {
+      process.env.GH_AW_SAFE_OUTPUTS_STAGED = "true";
+      const handler = await main({});
+      const result = await handler({ reaction: "eyes" }, {});
+
+      expect(result).toEqual({ success: true, staged: true, previewInfo: { reaction: "eyes", target: "issue #42" } });
+      expect(mockGithub.request).not.toHaveBeenCalled();
+    });
+
+    it("should skip locked items", async () => {
+      mockGithub.request.mockRejectedValue(Object.assign(new Error("Issue is locked"), { status: 403 }));
+      const handler = await main({});
+      const result = await handler({ reaction: "eyes" }, {});
+
+      expect(result).toEqual({ success: true, skipped: true, reason: "locked" });
+      expect(mockCore.errors).toHaveLength(0);
+    });
+
+    it("should report API errors", async () => {
+      mockGithub.request.mockRejectedValue(new Error("Server error"));
+      const handler = await main({});
+      const result = await handler({ reaction: "eyes" }, {});
+
+      expect(result).toEqual({ success: false, error: "Server error" });
+      expect(mockCore.errors.some(msg => msg.includes("Server error"))).toBe(true);
+    });
+  });
+});
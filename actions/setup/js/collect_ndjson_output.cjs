@@ -76,10 +76,23 @@ async function main() {
           }
           break;
         case "number":
-          if (typeof value !== "number") {
+        case "integer":
+          if (typeof value !== "number" || (inputType === "integer" && !Number.isInteger(value))) {
             return {
               isValid: false,
-              error: `Line ${lineNum}: ${fieldName} must be a number`,
+              error: `Line ${lineNum}: ${fieldName} must be ${inputType === "integer" ? "an integer" : "a number"}`,
+            };
+          }
+          if (typeof inputSchema.minimum === "number" && value < inputSchema.minimum) {
+            return {
+              isValid: false,
+              error: `Line ${lineNum}: ${fieldName} must be at least ${inputSchema.minimum}`,
+            };
+          }
+          if (typeof inputSchema.maximum === "number" && value > inputSchema.maximum) {
+            return {
+              isValid: false,
+              error: `Line ${lineNum}: ${fieldName} must be at most ${inputSchema.maximum}`,
             };
           }
           break;
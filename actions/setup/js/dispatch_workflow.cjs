@@ -10,6 +10,55 @@ const HANDLER_TYPE = "dispatch_workflow";
 
 const { getErrorMessage } = require("./error_helpers.cjs");
 
+/**
+ * Validate agent-supplied inputs against a workflow_dispatch input schema
+ * @param {Record<string, any>} inputs - Inputs from the agent message
+ * @param {Record<string, {type?: string, required?: boolean, options?: any[], default?: any}>} schema - Input schema keyed by input name
+ * @returns {string[]} Validation errors, empty if the inputs are valid
+ */
+function validateDispatchInputs(inputs, schema) {
+  const errors = [];
+
+  for (const [name, value] of Object.entries(inputs)) {
+    const definition = schema[name];
+    if (!definition) {
+      errors.push(`unknown input "${name}" (expected one of: ${Object.keys(schema).join(", ") || "none"})`);
+      continue;
+    }
+    if (value === null || value === undefined || value === "") {
+      continue;
+    }
+
+    switch (definition.type) {
+      case "boolean":
+        if (typeof value !== "boolean" && value !== "true" && value !== "false") {
+          errors.push(`input "${name}" must be a boolean`);
+        }
+        break;
+      case "number":
+        if (typeof value !== "number" && (typeof value !== "string" || value.trim() === "" || isNaN(Number(value)))) {
+          errors.push(`input "${name}" must be a number`);
+        }
+        break;
+      case "choice":
+        if (Array.isArray(definition.options) && !definition.options.map(String).includes(String(value))) {
+          errors.push(`input "${name}" must be one of: ${definition.options.join(", ")}`);
+        }
+        break;
+    }
+  }
+
+  for (const [name, definition] of Object.entries(schema)) {
+    const value = inputs[name];
+    const missing = value === null || value === undefined || value === "";
+    if (definition.required && missing && definition.default === undefined) {
+      errors.push(`missing required input "${name}"`);
+    }
+  }
+
+  return errors;
+}
+
 /**
  * Main handler factory for dispatch_workflow
  * Returns a message handler function that processes individual dispatch_workflow messages
@@ -20,6 +69,7 @@ async function main(config = {}) {
   const allowedWorkflows = config.workflows || [];
   const maxCount = config.max || 1;
   const workflowFiles = config.workflow_files || {}; // Map of workflow name to file extension
+  const workflowInputs = config.workflow_inputs || {}; // Map of workflow name to input schema
 
   core.info(`Dispatch workflow configuration: max=${maxCount}`);
   if (allowedWorkflows.length > 0) {
@@ -112,6 +162,21 @@ async function main(config = {}) {
       };
     }
 
+    // Validate inputs against the workflow's input schema, when one was resolved at compile time
+    const inputSchema = workflowInputs[workflowName];
+    if (inputSchema) {
+      const agentInputs = item.inputs && typeof item.inputs === "object" ? item.inputs : {};
+      const inputErrors = validateDispatchInputs(agentInputs, inputSchema);
+      if (inputErrors.length > 0) {
+        const error = `Invalid inputs for workflow "${workflowName}": ${inputErrors.join("; ")}`;
+        core.warning(error);
+        return {
+          success: false,
+          error: error,
+        };
+      }
+    }
+
     try {
       // Add 5 second delay between dispatches (except for the first one)
       if (lastDispatchTime > 0) {
@@ -184,4 +249,4 @@ async function main(config = {}) {
   };
 }
 
-module.exports = { main };
+module.exports = { main, validateDispatchInputs };
@@ -1,6 +1,6 @@
 // @ts-check
 import { describe, it, expect, beforeEach, vi } from "vitest";
-import { main } from "./dispatch_workflow.cjs";
+import { main, validateDispatchInputs } from "./dispatch_workflow.cjs";
 
 // Mock dependencies
 global.core = {
@@ -441,4 +441,51 @@ describe("dispatch_workflow handler factory", () => {
       inputs: {},
     });
   });
+
+  it("should reject inputs that do not match the workflow input schema", async () => {
+    const config = {
+      workflows: ["deploy"],
+      workflow_files: {
+        deploy: ".lock.yml",
+      },
+      workflow_inputs: {
+        deploy: {
+          environment: { type: "choice", required: true, options: ["staging", "production"] },
+        },
+      },
+    };
+    const handler = await main(config);
+
+    const result = await handler({ type: "dispatch_workflow", workflow_name: "deploy", inputs: { environment: "qa", force: true } }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain('unknown input "force"');
+    expect(result.error).toContain('input "environment" must be one of: staging, production');
+    expect(github.rest.actions.createWorkflowDispatch).not.toHaveBeenCalled();
+  });
+});
+
+describe("validateDispatchInputs", () => {
+  const schema = {
+    environment: { type: "choice", required: true, options: ["staging", "production"] },
+    dry_run: { type: "boolean", default: false },
+    replicas: { type: "number" },
+    version: { type: "string", required: true, default: "latest" },
+  };
+
+  it("should accept valid inputs", () => {
+    expect(validateDispatchInputs({ environment: "staging", dry_run: "true", replicas: 3 }, schema)).toEqual([]);
+  });
+
+  it("should report missing required inputs without defaults", () => {
+    expect(validateDispatchInputs({}, schema)).toEqual(['missing required input "environment"']);
+  });
+
+  it("should report unknown inputs and type mismatches", () => {
+    expect(validateDispatchInputs({ environment: "production", dry_run: "yes", replicas: "many", extra: "x" }, schema)).toEqual([
+      'input "dry_run" must be a boolean',
+      'input "replicas" must be a number',
+      'unknown input "extra" (expected one of: environment, dry_run, replicas, version)',
+    ]);
+  });
 });
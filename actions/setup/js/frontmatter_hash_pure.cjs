@@ -84,13 +84,14 @@ async function computeFrontmatterHash(workflowPath, options = {}) {
 function extractFrontmatterAndBody(content) {
   const lines = content.split("\n");
 
-  if (lines.length === 0 || lines[0].trim() !== "---") {
+  const closingFence = lines.length > 0 ? FRONTMATTER_CLOSING_FENCES[lines[0].trim()] : undefined;
+  if (!closingFence) {
     return { frontmatterText: "", markdown: content };
   }
 
   let endIndex = -1;
   for (let i = 1; i < lines.length; i++) {
-    if (lines[i].trim() === "---") {
+    if (lines[i].trim() === closingFence) {
       endIndex = i;
       break;
     }
@@ -106,6 +107,12 @@ function extractFrontmatterAndBody(content) {
   return { frontmatterText, markdown };
 }
 
+/**
+ * Closing fences of YAML (---), TOML (+++), and JSON (```json) frontmatter, keyed by opening fence
+ * @type {Record<string, string>}
+ */
+const FRONTMATTER_CLOSING_FENCES = { "---": "---", "+++": "+++", "```json": "```" };
+
 /**
  * Process imports from frontmatter using text-based parsing
  * Only parses enough to extract the imports list
@@ -162,9 +169,47 @@ async function processImportsTextBased(frontmatterText, baseDir, visited = new S
   return { importedFiles, importedFrontmatterTexts };
 }
 
+/**
+ * Matches the imports key of TOML (imports = [) and JSON ("imports": [) frontmatter,
+ * where the list is an array of quoted strings
+ */
+const IMPORTS_ARRAY_KEY_PATTERN = /(?:^|[{,]\s*)"imports"\s*:\s*\[|^"?imports"?\s*=\s*\[/;
+
+/**
+ * Extract the quoted string items of an array, skipping items of nested arrays and objects
+ * @param {string} text - The array text following its opening bracket
+ * @returns {string[]} The quoted items up to the closing bracket
+ */
+function extractQuotedArrayItems(text) {
+  const items = [];
+  let depth = 1;
+  for (let i = 0; i < text.length && depth > 0; i++) {
+    const c = text[i];
+    if (c === '"' || c === "'") {
+      let end = i + 1;
+      while (end < text.length && text[end] !== c) {
+        if (c === '"' && text[end] === "\\") {
+          end++;
+        }
+        end++;
+      }
+      if (depth === 1 && end > i + 1 && end < text.length) {
+        items.push(text.substring(i + 1, end));
+      }
+      i = end;
+    } else if (c === "[" || c === "{") {
+      depth++;
+    } else if (c === "]" || c === "}") {
+      depth--;
+    }
+  }
+  return items;
+}
+
 /**
  * Extract imports field from frontmatter text using simple text parsing
- * Only extracts array items under "imports:" key
+ * Only extracts array items under "imports:" key, or the quoted strings of an imports array
+ * in TOML and JSON frontmatter
  * @param {string} frontmatterText - The frontmatter text
  * @returns {string[]} Array of import paths
  */
@@ -182,6 +227,12 @@ function extractImportsFromText(frontmatterText) {
     // Skip empty lines and comments
     if (!trimmed || trimmed.startsWith("#")) continue;
 
+    // Check if this is the imports key of TOML or JSON frontmatter
+    const arrayKey = IMPORTS_ARRAY_KEY_PATTERN.exec(trimmed);
+    if (arrayKey) {
+      return extractQuotedArrayItems([trimmed.substring(arrayKey.index + arrayKey[0].length), ...lines.slice(i + 1)].join("\n"));
+    }
+
     // Check if this is the imports: key
     if (trimmed.startsWith("imports:")) {
       inImports = true;
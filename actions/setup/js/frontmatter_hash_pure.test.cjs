@@ -40,6 +40,16 @@ Test content here`;
       expect(result.markdown).toBe(content);
     });
 
+    it("should extract TOML and JSON frontmatter", () => {
+      const toml = extractFrontmatterAndBody('+++\nengine = "copilot"\n+++\n\n# Body');
+      expect(toml.frontmatterText).toBe('engine = "copilot"');
+      expect(toml.markdown).toBe("\n# Body");
+
+      const json = extractFrontmatterAndBody('```json\n{"engine": "copilot"}\n```\n\n# Body');
+      expect(json.frontmatterText).toBe('{"engine": "copilot"}');
+      expect(json.markdown).toBe("\n# Body");
+    });
+
     it("should handle frontmatter with imports", () => {
       const content = `---
 engine: copilot
@@ -85,6 +95,13 @@ description: Test`;
       expect(result).toEqual(["shared/test.md", "shared/common.md"]);
     });
 
+    it("should extract imports from TOML and JSON arrays", () => {
+      expect(extractImportsFromText('imports = [\n  "shared/a.md",\n  \'shared/b.md\',\n]\nengine = "copilot"')).toEqual(["shared/a.md", "shared/b.md"]);
+      expect(extractImportsFromText('{\n  "imports": ["shared/a.md", {"path": "shared/b.md"}],\n  "engine": "copilot"\n}')).toEqual(["shared/a.md"]);
+      expect(extractImportsFromText('{"engine": "copilot", "imports": ["shared/a.md"]}')).toEqual(["shared/a.md"]);
+      expect(extractImportsFromText('imports: ["shared/a.md"]')).toEqual([]);
+    });
+
     it("should stop at next top-level key", () => {
       const frontmatterText = `imports:
   - shared/test.md
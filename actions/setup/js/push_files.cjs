@@ -0,0 +1,259 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * @typedef {import('./types/handler-factory').HandlerFactoryFunction} HandlerFactoryFunction
+ */
+
+const { getErrorMessage } = require("./error_helpers.cjs");
+const { globPatternToRegex } = require("./glob_pattern_helpers.cjs");
+const { logStagedPreviewInfo } = require("./staged_preview.cjs");
+
+/**
+ * Type constant for handler identification
+ */
+const HANDLER_TYPE = "push_files";
+
+/**
+ * Branch names that may never be pushed to, in addition to the repository's default branch
+ * and branches with protection rules
+ */
+const PROTECTED_BRANCHES = ["main", "master"];
+
+/**
+ * Normalize a repository-relative file path, rejecting paths that escape the repository
+ * @param {string} filePath - Path requested by the agent
+ * @returns {string|null} Normalized path, or null if the path is not a plain relative path
+ */
+function normalizeFilePath(filePath) {
+  if (typeof filePath !== "string" || filePath === "" || filePath.startsWith("/") || filePath.includes("\\")) {
+    return null;
+  }
+  const segments = filePath.split("/").filter(segment => segment !== "" && segment !== ".");
+  if (segments.length === 0 || segments.includes("..")) {
+    return null;
+  }
+  return segments.join("/");
+}
+
+/**
+ * Path prefix that is off limits unless allowed-paths explicitly covers it, since files under
+ * it (such as workflows) control what runs in the repository
+ */
+const GITHUB_DIR = ".github";
+
+/**
+ * Check whether a normalized path is a prefix or under it
+ * @param {string} filePath - Normalized repository-relative path
+ * @param {string} prefix - Path prefix, with or without a trailing slash
+ * @returns {boolean}
+ */
+function isUnderPrefix(filePath, prefix) {
+  const normalizedPrefix = prefix.replace(/\/+$/, "");
+  return filePath === normalizedPrefix || filePath.startsWith(`${normalizedPrefix}/`);
+}
+
+/**
+ * Check whether a normalized file path is under one of the allowed path prefixes
+ * @param {string} filePath - Normalized repository-relative path
+ * @param {string[]} allowedPaths - Allowed path prefixes (empty allows any path outside .github/)
+ * @returns {boolean}
+ */
+function isPathAllowed(filePath, allowedPaths) {
+  if (allowedPaths.length === 0) {
+    return !isUnderPrefix(filePath.toLowerCase(), GITHUB_DIR);
+  }
+  return allowedPaths.some(prefix => isUnderPrefix(filePath, prefix));
+}
+
+/**
+ * Main handler factory for push_files
+ * Returns a message handler function that processes individual push_files messages
+ * @type {HandlerFactoryFunction}
+ */
+async function main(config = {}) {
+  // Extract configuration
+  const allowedBranches = config.allowed_branches || [];
+  const allowedPaths = config.allowed_paths || [];
+  const maxCount = config.max || 1;
+  const branchPatterns = allowedBranches.map(pattern => globPatternToRegex(pattern));
+
+  // Check if we're in staged mode
+  const isStaged = process.env.GH_AW_SAFE_OUTPUTS_STAGED === "true";
+
+  core.info(`Push files configuration: max=${maxCount}`);
+  core.info(`Allowed branches: ${allowedBranches.join(", ") || "(none)"}`);
+  core.info(`Allowed paths: ${allowedPaths.join(", ") || `(any path outside ${GITHUB_DIR}/)`}`);
+
+  // Track how many items we've processed for max limit
+  let processedCount = 0;
+
+  /**
+   * Message handler function that processes a single push_files message
+   * @param {Object} message - The push_files message to process
+   * @param {Object} resolvedTemporaryIds - Map of temporary IDs to {repo, number} (unused for push_files)
+   * @returns {Promise<Object>} Result with success/error status
+   */
+  return async function handlePushFiles(message, resolvedTemporaryIds) {
+    // Check if we've hit the max limit
+    if (processedCount >= maxCount) {
+      core.warning(`Skipping push_files: max count of ${maxCount} reached`);
+      return {
+        success: false,
+        error: `Max count of ${maxCount} reached`,
+      };
+    }
+
+    processedCount++;
+
+    const branch = String(message.branch ?? "").trim();
+    if (!branch) {
+      return {
+        success: false,
+        error: "No branch specified",
+      };
+    }
+
+    if (PROTECTED_BRANCHES.includes(branch)) {
+      core.warning(`Refusing to push to protected branch "${branch}"`);
+      return {
+        success: false,
+        error: `Branch "${branch}" is protected`,
+      };
+    }
+
+    if (!branchPatterns.some(pattern => pattern.test(branch))) {
+      core.warning(`Branch "${branch}" does not match allowed-branches [${allowedBranches.join(", ")}]. Skipping.`);
+      return {
+        success: false,
+        error: `Branch "${branch}" is not in allowed-branches`,
+      };
+    }
+
+    const requestedFiles = Array.isArray(message.files) ? message.files : [];
+    if (requestedFiles.length === 0) {
+      return {
+        success: false,
+        error: "No files specified",
+      };
+    }
+
+    /** @type {{path: string, content: string}[]} */
+    const files = [];
+    for (const file of requestedFiles) {
+      const filePath = normalizeFilePath(file?.path);
+      if (!filePath) {
+        core.warning(`Invalid file path ${JSON.stringify(file?.path)}. Paths must be relative to the repository root.`);
+        return {
+          success: false,
+          error: `Invalid file path ${JSON.stringify(file?.path)}`,
+        };
+      }
+      if (!isPathAllowed(filePath, allowedPaths)) {
+        const reason = allowedPaths.length > 0 ? `is not in allowed-paths` : `is under ${GITHUB_DIR}/, which requires allowed-paths`;
+        core.warning(`File "${filePath}" ${reason}. Skipping.`);
+        return {
+          success: false,
+          error: `File "${filePath}" ${reason}`,
+        };
+      }
+      if (typeof file.content !== "string") {
+        return {
+          success: false,
+          error: `File "${filePath}" has no content`,
+        };
+      }
+      files.push({ path: filePath, content: file.content });
+    }
+
+    const commitMessage = String(message.message ?? "").trim() || `Update ${files.length} file(s)`;
+    const { owner, repo } = context.repo;
+
+    core.info(`Pushing ${files.length} file(s) to ${owner}/${repo}@${branch}`);
+
+    // If in staged mode, preview without executing
+    if (isStaged) {
+      logStagedPreviewInfo(`Would push ${files.length} file(s) to branch ${branch}: ${files.map(file => file.path).join(", ")}`);
+      return {
+        success: true,
+        staged: true,
+        previewInfo: {
+          branch,
+          files: files.map(file => file.path),
+        },
+      };
+    }
+
+    try {
+      const { data: repository } = await github.rest.repos.get({ owner, repo });
+      if (branch === repository.default_branch) {
+        core.warning(`Refusing to push to the default branch "${branch}"`);
+        return {
+          success: false,
+          error: `Branch "${branch}" is the default branch`,
+        };
+      }
+
+      // Resolve the commit to build on: the branch head, or the default branch for a new branch
+      let baseSha;
+      let branchExists = true;
+      try {
+        const { data: existing } = await github.rest.repos.getBranch({ owner, repo, branch });
+        if (existing.protected) {
+          core.warning(`Refusing to push to protected branch "${branch}"`);
+          return {
+            success: false,
+            error: `Branch "${branch}" is protected`,
+          };
+        }
+        baseSha = existing.commit.sha;
+      } catch (error) {
+        if (/** @type {any} */ (error)?.status !== 404) {
+          throw error;
+        }
+        branchExists = false;
+        const { data: defaultBranch } = await github.rest.repos.getBranch({ owner, repo, branch: repository.default_branch });
+        baseSha = defaultBranch.commit.sha;
+      }
+
+      const { data: baseCommit } = await github.rest.git.getCommit({ owner, repo, commit_sha: baseSha });
+      const { data: tree } = await github.rest.git.createTree({
+        owner,
+        repo,
+        base_tree: baseCommit.tree.sha,
+        tree: files.map(file => ({ path: file.path, mode: "100644", type: "blob", content: file.content })),
+      });
+      const { data: commit } = await github.rest.git.createCommit({
+        owner,
+        repo,
+        message: commitMessage,
+        tree: tree.sha,
+        parents: [baseSha],
+      });
+
+      if (branchExists) {
+        // Not forced: the push fails if the branch moved since it was read
+        await github.rest.git.updateRef({ owner, repo, ref: `heads/${branch}`, sha: commit.sha, force: false });
+      } else {
+        await github.rest.git.createRef({ owner, repo, ref: `refs/heads/${branch}`, sha: commit.sha });
+      }
+
+      core.info(`Successfully pushed ${commit.sha} to ${branch}`);
+      return {
+        success: true,
+        branch,
+        commit_sha: commit.sha,
+        files: files.map(file => file.path),
+      };
+    } catch (error) {
+      const errorMessage = getErrorMessage(error);
+      core.error(`Failed to push files to ${branch}: ${errorMessage}`);
+      return {
+        success: false,
+        error: errorMessage,
+      };
+    }
+  };
+}
+
+module.exports = { main, HANDLER_TYPE, normalizeFilePath, isPathAllowed };
@@ -0,0 +1,233 @@
+// @ts-check
+import { describe, it, expect, beforeEach, afterEach, vi } from "vitest";
+const { main, normalizeFilePath, isPathAllowed } = require("./push_files.cjs");
+
+describe("push_files", () => {
+  let mockCore;
+  let mockGithub;
+  let originalGlobals;
+  let originalStaged;
+
+  /** @type {Record<string, any>} */
+  let branches;
+
+  beforeEach(() => {
+    originalGlobals = { core: global.core, github: global.github, context: global.context };
+    originalStaged = process.env.GH_AW_SAFE_OUTPUTS_STAGED;
+    delete process.env.GH_AW_SAFE_OUTPUTS_STAGED;
+
+    mockCore = {
+      infos: /** @type {string[]} */ [],
+      warnings: /** @type {string[]} */ [],
+      errors: /** @type {string[]} */ [],
+      info: /** @param {string} msg */ msg => mockCore.infos.push(msg),
+      warning: /** @param {string} msg */ msg => mockCore.warnings.push(msg),
+      error: /** @param {string} msg */ msg => mockCore.errors.push(msg),
+    };
+
+    branches = {
+      main: { protected: true, commit: { sha: "main-sha" } },
+      "agent/existing": { protected: false, commit: { sha: "existing-sha" } },
+      "agent/locked": { protected: true, commit: { sha: "locked-sha" } },
+    };
+
+    mockGithub = {
+      rest: {
+        repos: {
+          get: vi.fn().mockResolvedValue({ data: { default_branch: "main" } }),
+          getBranch: vi.fn(async ({ branch }) => {
+            if (!branches[branch]) {
+              throw Object.assign(new Error("Branch not found"), { status: 404 });
+            }
+            return { data: branches[branch] };
+          }),
+        },
+        git: {
+          getCommit: vi.fn(async ({ commit_sha }) => ({ data: { sha: commit_sha, tree: { sha: `${commit_sha}-tree` } } })),
+          createTree: vi.fn().mockResolvedValue({ data: { sha: "new-tree" } }),
+          createCommit: vi.fn().mockResolvedValue({ data: { sha: "new-commit" } }),
+          createRef: vi.fn().mockResolvedValue({ data: {} }),
+          updateRef: vi.fn().mockResolvedValue({ data: {} }),
+        },
+      },
+    };
+
+    global.core = mockCore;
+    global.github = mockGithub;
+    global.context = { repo: { owner: "test-owner", repo: "test-repo" } };
+  });
+
+  afterEach(() => {
+    global.core = originalGlobals.core;
+    global.github = originalGlobals.github;
+    global.context = originalGlobals.context;
+    if (originalStaged === undefined) {
+      delete process.env.GH_AW_SAFE_OUTPUTS_STAGED;
+    } else {
+      process.env.GH_AW_SAFE_OUTPUTS_STAGED = originalStaged;
+    }
+  });
+
+  describe("normalizeFilePath", () => {
+    it("should normalize relative paths", () => {
+      expect(normalizeFilePath("docs/api.md")).toBe("docs/api.md");
+      expect(normalizeFilePath("./docs//api.md")).toBe("docs/api.md");
+    });
+
+    it("should reject paths with .. segments", () => {
+      expect(normalizeFilePath("../outside.md")).toBeNull();
+      expect(normalizeFilePath("docs/../../outside.md")).toBeNull();
+    });
+
+    it("should reject absolute paths", () => {
+      expect(normalizeFilePath("/etc/passwd")).toBeNull();
+    });
+
+    it("should reject paths with backslashes", () => {
+      expect(normalizeFilePath("docs\\api.md")).toBeNull();
+    });
+
+    it("should reject empty and non-string paths", () => {
+      expect(normalizeFilePath("")).toBeNull();
+      expect(normalizeFilePath("./")).toBeNull();
+      expect(normalizeFilePath(undefined)).toBeNull();
+    });
+  });
+
+  describe("isPathAllowed", () => {
+    it("should allow files under an allowed prefix", () => {
+      expect(isPathAllowed("generated/a.md", ["generated/"])).toBe(true);
+      expect(isPathAllowed("generated/a.md", ["generated"])).toBe(true);
+      expect(isPathAllowed("generated", ["generated/"])).toBe(true);
+    });
+
+    it("should reject files outside the allowed prefixes", () => {
+      expect(isPathAllowed("generated-other/a.md", ["generated/"])).toBe(false);
+      expect(isPathAllowed("src/a.go", ["generated/", "docs/api"])).toBe(false);
+    });
+
+    it("should allow any path outside .github/ without allowed-paths", () => {
+      expect(isPathAllowed("src/a.go", [])).toBe(true);
+      expect(isPathAllowed(".githubx/a.md", [])).toBe(true);
+    });
+
+    it("should reject .github/ without allowed-paths", () => {
+      expect(isPathAllowed(".github/workflows/ci.yml", [])).toBe(false);
+      expect(isPathAllowed(".GitHub/workflows/ci.yml", [])).toBe(false);
+    });
+
+    it("should allow .github/ when allowed-paths covers it", () => {
+      expect(isPathAllowed(".github/agents/a.md", [".github/agents/"])).toBe(true);
+    });
+  });
+
+  describe("handler", () => {
+    it("should create a new branch from the default branch", async () => {
+      const handler = await main({ allowed_branches: ["agent/*"] });
+      const result = await handler({ branch: "agent/new", message: "Add docs", files: [{ path: "docs/a.md", content: "A" }] }, {});
+
+      expect(result).toEqual({ success: true, branch: "agent/new", commit_sha: "new-commit", files: ["docs/a.md"] });
+      expect(mockGithub.rest.git.getCommit).toHaveBeenCalledWith({ owner: "test-owner", repo: "test-repo", commit_sha: "main-sha" });
+      expect(mockGithub.rest.git.createTree).toHaveBeenCalledWith(
+        expect.objectContaining({ base_tree: "main-sha-tree", tree: [{ path: "docs/a.md", mode: "100644", type: "blob", content: "A" }] })
+      );
+      expect(mockGithub.rest.git.createCommit).toHaveBeenCalledWith(expect.objectContaining({ message: "Add docs", tree: "new-tree", parents: ["main-sha"] }));
+      expect(mockGithub.rest.git.createRef).toHaveBeenCalledWith({ owner: "test-owner", repo: "test-repo", ref: "refs/heads/agent/new", sha: "new-commit" });
+      expect(mockGithub.rest.git.updateRef).not.toHaveBeenCalled();
+    });
+
+    it("should update an existing branch without forcing", async () => {
+      const handler = await main({ allowed_branches: ["agent/*"] });
+      const result = await handler({ branch: "agent/existing", files: [{ path: "docs/a.md", content: "A" }] }, {});
+
+      expect(result.success).toBe(true);
+      expect(mockGithub.rest.git.createCommit).toHaveBeenCalledWith(expect.objectContaining({ message: "Update 1 file(s)", parents: ["existing-sha"] }));
+      expect(mockGithub.rest.git.updateRef).toHaveBeenCalledWith({ owner: "test-owner", repo: "test-repo", ref: "heads/agent/existing", sha: "new-commit", force: false });
+      expect(mockGithub.rest.git.createRef).not.toHaveBeenCalled();
+    });
+
+    it("should reject main and master", async () => {
+      const handler = await main({ allowed_branches: ["*"], max: 2 });
+
+      for (const branch of ["main", "master"]) {
+        const result = await handler({ branch, files: [{ path: "a.md", content: "A" }] }, {});
+        expect(result).toEqual({ success: false, error: `Branch "${branch}" is protected` });
+      }
+      expect(mockGithub.rest.repos.get).not.toHaveBeenCalled();
+    });
+
+    it("should reject the default branch", async () => {
+      mockGithub.rest.repos.get.mockResolvedValue({ data: { default_branch: "agent/default" } });
+      const handler = await main({ allowed_branches: ["agent/*"] });
+      const result = await handler({ branch: "agent/default", files: [{ path: "a.md", content: "A" }] }, {});
+
+      expect(result).toEqual({ success: false, error: 'Branch "agent/default" is the default branch' });
+      expect(mockGithub.rest.git.createCommit).not.toHaveBeenCalled();
+    });
+
+    it("should reject branches with protection rules", async () => {
+      const handler = await main({ allowed_branches: ["agent/*"] });
+      const result = await handler({ branch: "agent/locked", files: [{ path: "a.md", content: "A" }] }, {});
+
+      expect(result).toEqual({ success: false, error: 'Branch "agent/locked" is protected' });
+      expect(mockGithub.rest.git.createCommit).not.toHaveBeenCalled();
+    });
+
+    it("should reject branches outside allowed-branches", async () => {
+      const handler = await main({ allowed_branches: ["agent/*"] });
+      const result = await handler({ branch: "feature/x", files: [{ path: "a.md", content: "A" }] }, {});
+
+      expect(result).toEqual({ success: false, error: 'Branch "feature/x" is not in allowed-branches' });
+    });
+
+    it("should reject invalid and disallowed file paths", async () => {
+      const handler = await main({ allowed_branches: ["agent/*"], allowed_paths: ["generated/"], max: 5 });
+
+      const traversal = await handler({ branch: "agent/new", files: [{ path: "generated/../../etc/passwd", content: "x" }] }, {});
+      expect(traversal).toEqual({ success: false, error: 'Invalid file path "generated/../../etc/passwd"' });
+
+      const outside = await handler({ branch: "agent/new", files: [{ path: "src/a.go", content: "x" }] }, {});
+      expect(outside).toEqual({ success: false, error: 'File "src/a.go" is not in allowed-paths' });
+      expect(mockGithub.rest.git.createCommit).not.toHaveBeenCalled();
+    });
+
+    it("should reject .github/ files without allowed-paths", async () => {
+      const handler = await main({ allowed_branches: ["agent/*"] });
+      const result = await handler({ branch: "agent/new", files: [{ path: ".github/workflows/ci.yml", content: "x" }] }, {});
+
+      expect(result).toEqual({ success: false, error: 'File ".github/workflows/ci.yml" is under .github/, which requires allowed-paths' });
+      expect(mockGithub.rest.git.createCommit).not.toHaveBeenCalled();
+    });
+
+    it("should stop after the max count", async () => {
+      const handler = await main({ allowed_branches: ["agent/*"], max: 1 });
+
+      const first = await handler({ branch: "agent/new", files: [{ path: "a.md", content: "A" }] }, {});
+      expect(first.success).toBe(true);
+
+      const second = await handler({ branch: "agent/new", files: [{ path: "b.md", content: "B" }] }, {});
+      expect(second).toEqual({ success: false, error: "Max count of 1 reached" });
+      expect(mockGithub.rest.git.createCommit).toHaveBeenCalledTimes(1);
+    });
+
+    it("should preview without pushing in staged mode", async () => {
+      process.env.GH_AW_SAFE_OUTPUTS_STAGED = "true";
+      const handler = await main({ allowed_branches: ["agent/*"] });
+      const result = await handler({ branch: "agent/new", files: [{ path: "a.md", content: "A" }] }, {});
+
+      expect(result).toEqual({ success: true, staged: true, previewInfo: { branch: "agent/new", files: ["a.md"] } });
+      expect(mockGithub.rest.repos.get).not.toHaveBeenCalled();
+      expect(mockGithub.rest.git.createRef).not.toHaveBeenCalled();
+      expect(mockCore.infos.some(msg => msg.includes("Staged Mode Preview"))).toBe(true);
+    });
+
+    it("should report API errors", async () => {
+      mockGithub.rest.git.updateRef.mockRejectedValue(new Error("Update is not a fast forward"));
+      const handler = await main({ allowed_branches: ["agent/*"] });
+      const result = await handler({ branch: "agent/existing", files: [{ path: "a.md", content: "A" }] }, {});
+
+      expect(result).toEqual({ success: false, error: "Update is not a fast forward" });
+      expect(mockCore.errors.some(msg => msg.includes("Update is not a fast forward"))).toBe(true);
+    });
+  });
+});
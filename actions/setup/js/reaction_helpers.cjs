@@ -0,0 +1,177 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * Reaction Helpers
+ *
+ * Resolves the item that triggered a workflow to the place a reaction is posted and adds
+ * reactions to it. Shared by the activation step (add_reaction.cjs) and the add_reaction
+ * safe output handler (add_reaction_handler.cjs) so both react to the same items.
+ */
+
+const { ERR_NOT_FOUND, ERR_VALIDATION } = require("./error_codes.cjs");
+
+/**
+ * Reactions supported by GitHub, mapped to the GraphQL ReactionContent enum used for discussions
+ * @type {Record<string, string>}
+ */
+const REACTION_CONTENT = {
+  "+1": "THUMBS_UP",
+  "-1": "THUMBS_DOWN",
+  laugh: "LAUGH",
+  confused: "CONFUSED",
+  heart: "HEART",
+  hooray: "HOORAY",
+  rocket: "ROCKET",
+  eyes: "EYES",
+};
+
+/** @type {string[]} Reaction names accepted by GitHub */
+const VALID_REACTIONS = Object.keys(REACTION_CONTENT);
+
+/**
+ * @typedef {Object} ReactionTarget
+ * @property {string} [endpoint] - REST reactions endpoint for issues, pull requests, and comments
+ * @property {string} [subjectId] - GraphQL node ID for discussions and discussion comments
+ * @property {number} [discussionNumber] - Discussion number, used to look up the node ID when the payload has none
+ * @property {string} description - Human-readable description of the item
+ */
+
+/**
+ * Resolve where a reaction on the triggering item should be posted.
+ * @param {string} eventName - Name of the triggering event
+ * @param {any} payload - Event payload
+ * @param {{owner: string, repo: string}} repository - Repository the event belongs to
+ * @returns {{target: ReactionTarget, error?: undefined} | {target?: undefined, error: string}} The target, or an error when the event has no reactable item
+ */
+function resolveReactionTarget(eventName, payload, { owner, repo }) {
+  switch (eventName) {
+    case "issues": {
+      const issueNumber = payload?.issue?.number;
+      if (!issueNumber) {
+        return { error: `${ERR_NOT_FOUND}: Issue number not found in event payload` };
+      }
+      return { target: { endpoint: `/repos/${owner}/${repo}/issues/${issueNumber}/reactions`, description: `issue #${issueNumber}` } };
+    }
+
+    case "issue_comment": {
+      const commentId = payload?.comment?.id;
+      if (!commentId) {
+        return { error: `${ERR_VALIDATION}: Comment ID not found in event payload` };
+      }
+      return { target: { endpoint: `/repos/${owner}/${repo}/issues/comments/${commentId}/reactions`, description: `comment ${commentId}` } };
+    }
+
+    case "pull_request":
+    case "pull_request_target": {
+      const prNumber = payload?.pull_request?.number;
+      if (!prNumber) {
+        return { error: `${ERR_NOT_FOUND}: Pull request number not found in event payload` };
+      }
+      // PRs are "issues" for the reactions endpoint
+      return { target: { endpoint: `/repos/${owner}/${repo}/issues/${prNumber}/reactions`, description: `pull request #${prNumber}` } };
+    }
+
+    case "pull_request_review_comment": {
+      const reviewCommentId = payload?.comment?.id;
+      if (!reviewCommentId) {
+        return { error: `${ERR_VALIDATION}: Review comment ID not found in event payload` };
+      }
+      return { target: { endpoint: `/repos/${owner}/${repo}/pulls/comments/${reviewCommentId}/reactions`, description: `review comment ${reviewCommentId}` } };
+    }
+
+    case "discussion": {
+      const discussionNumber = payload?.discussion?.number;
+      if (!discussionNumber) {
+        return { error: `${ERR_NOT_FOUND}: Discussion number not found in event payload` };
+      }
+      // Discussions use the GraphQL API; the node ID is looked up when the payload has none
+      return { target: { subjectId: payload.discussion.node_id, discussionNumber, description: `discussion #${discussionNumber}` } };
+    }
+
+    case "discussion_comment": {
+      const commentNodeId = payload?.comment?.node_id;
+      if (!commentNodeId) {
+        return { error: `${ERR_NOT_FOUND}: Discussion comment node ID not found in event payload` };
+      }
+      return { target: { subjectId: commentNodeId, description: `discussion comment ${commentNodeId}` } };
+    }
+
+    default:
+      return { error: `${ERR_VALIDATION}: Unsupported event type: ${eventName}` };
+  }
+}
+
+/**
+ * Add a reaction to a resolved target, using the REST API for issues, pull requests, and
+ * comments and the GraphQL API for discussions and discussion comments.
+ * @param {ReactionTarget} target - Where to post the reaction
+ * @param {string} reaction - Reaction name, one of VALID_REACTIONS
+ * @param {{owner: string, repo: string}} repository - Repository the target belongs to
+ * @returns {Promise<string>} ID of the created reaction, or "" when the API returned none
+ */
+async function addReactionToTarget(target, reaction, { owner, repo }) {
+  if (target.endpoint) {
+    const response = await github.request(`POST ${target.endpoint}`, {
+      content: reaction,
+      headers: {
+        Accept: "application/vnd.github+json",
+      },
+    });
+    const reactionId = response?.data?.id;
+    return reactionId ? reactionId.toString() : "";
+  }
+
+  const reactionContent = REACTION_CONTENT[reaction];
+  if (!reactionContent) {
+    throw new Error(`${ERR_VALIDATION}: Invalid reaction type for GraphQL: ${reaction}`);
+  }
+
+  const subjectId = target.subjectId || (await getDiscussionNodeId(owner, repo, Number(target.discussionNumber)));
+  const result = await github.graphql(
+    `
+    mutation($subjectId: ID!, $content: ReactionContent!) {
+      addReaction(input: { subjectId: $subjectId, content: $content }) {
+        reaction {
+          id
+          content
+        }
+      }
+    }`,
+    { subjectId, content: reactionContent }
+  );
+  return result?.addReaction?.reaction?.id || "";
+}
+
+/**
+ * Get the node ID for a discussion
+ * @param {string} owner - Repository owner
+ * @param {string} repo - Repository name
+ * @param {number} discussionNumber - Discussion number
+ * @returns {Promise<string>} Discussion node ID
+ */
+async function getDiscussionNodeId(owner, repo, discussionNumber) {
+  const { repository } = await github.graphql(
+    `
+    query($owner: String!, $repo: String!, $num: Int!) {
+      repository(owner: $owner, name: $repo) {
+        discussion(number: $num) {
+          id
+        }
+      }
+    }`,
+    { owner, repo, num: discussionNumber }
+  );
+
+  if (!repository || !repository.discussion) {
+    throw new Error(`${ERR_NOT_FOUND}: Discussion #${discussionNumber} not found in ${owner}/${repo}`);
+  }
+  return repository.discussion.id;
+}
+
+module.exports = {
+  REACTION_CONTENT,
+  VALID_REACTIONS,
+  resolveReactionTarget,
+  addReactionToTarget,
+};
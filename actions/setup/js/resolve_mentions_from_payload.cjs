@@ -8,6 +8,38 @@
 const { resolveMentionsLazily, isPayloadUserBot } = require("./resolve_mentions.cjs");
 const { getErrorMessage } = require("./error_helpers.cjs");
 
+/**
+ * Expand GitHub team slugs ("org/team") into the logins of their members.
+ * Expansion failures (missing team, insufficient token scope) are logged as
+ * warnings and the team is skipped.
+ * @param {string[]} teams - Team slugs in "org/team" form
+ * @param {any} github - GitHub API client
+ * @param {any} core - GitHub Actions core
+ * @returns {Promise<string[]>} Member logins (bots excluded)
+ */
+async function resolveTeamMembers(teams, github, core) {
+  const members = [];
+  for (const team of teams) {
+    const [org, teamSlug] = team.split("/");
+    if (!org || !teamSlug) {
+      core.warning(`[MENTIONS] Ignoring invalid team slug: ${team}`);
+      continue;
+    }
+    try {
+      const teamMembers = await github.paginate(github.rest.teams.listMembersInOrg, { org, team_slug: teamSlug, per_page: 100 });
+      for (const member of teamMembers) {
+        if (member?.login && !isPayloadUserBot(member)) {
+          members.push(member.login);
+        }
+      }
+      core.info(`[MENTIONS] Expanded team @${team} to ${teamMembers.length} member(s)`);
+    } catch (error) {
+      core.warning(`[MENTIONS] Failed to expand team @${team}: ${getErrorMessage(error)}`);
+    }
+  }
+  return members;
+}
+
 /**
  * Resolve allowed mentions from the current GitHub event context
  * @param {any} context - GitHub Actions context
@@ -38,11 +70,13 @@ async function resolveAllowedMentionsFromPayload(context, github, core, mentions
   const allowTeamMembers = mentionsConfig?.allowTeamMembers !== false; // default: true
   const allowContext = mentionsConfig?.allowContext !== false; // default: true
   const allowedList = mentionsConfig?.allowed || [];
+  const teamList = mentionsConfig?.teams || [];
+  const deniedSet = new Set((mentionsConfig?.denied || []).map(login => login.toLowerCase()));
   const maxMentions = mentionsConfig?.max || 50;
 
   try {
     const { owner, repo } = context.repo;
-    const knownAuthors = [];
+    let knownAuthors = [];
 
     // Extract known authors from the event payload (if allow-context is enabled)
     if (allowContext) {
@@ -157,12 +191,26 @@ async function resolveAllowedMentionsFromPayload(context, github, core, mentions
     // Add allowed list to known authors (these are always allowed regardless of configuration)
     knownAuthors.push(...allowedList);
 
+    // Add members of allowed teams when allow-team-members is enabled
+    if (allowTeamMembers && teamList.length > 0) {
+      knownAuthors.push(...(await resolveTeamMembers(teamList, github, core)));
+    }
+
     // Add extra known authors (e.g. pre-fetched target issue authors for explicit item_number)
     if (extraKnownAuthors && extraKnownAuthors.length > 0) {
       core.info(`[MENTIONS] Adding ${extraKnownAuthors.length} extra known author(s): ${extraKnownAuthors.join(", ")}`);
       knownAuthors.push(...extraKnownAuthors);
     }
 
+    // Denied logins are never allowed, whichever source they came from
+    if (deniedSet.size > 0) {
+      const deniedAuthors = knownAuthors.filter(author => deniedSet.has(author.toLowerCase()));
+      if (deniedAuthors.length > 0) {
+        core.info(`[MENTIONS] Excluding denied user(s): ${[...new Set(deniedAuthors)].join(", ")}`);
+      }
+      knownAuthors = knownAuthors.filter(author => !deniedSet.has(author.toLowerCase()));
+    }
+
     // If allow-team-members is disabled, only use known authors (context + allowed list)
     if (!allowTeamMembers) {
       core.info(`[MENTIONS] Team members disabled - only allowing context (${knownAuthors.length} users)`);
@@ -203,4 +251,5 @@ async function resolveAllowedMentionsFromPayload(context, github, core, mentions
 
 module.exports = {
   resolveAllowedMentionsFromPayload,
+  resolveTeamMembers,
 };
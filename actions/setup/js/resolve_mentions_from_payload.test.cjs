@@ -0,0 +1,128 @@
+// @ts-check
+import { describe, it, expect, beforeEach, vi } from "vitest";
+const { resolveAllowedMentionsFromPayload, resolveTeamMembers } = require("./resolve_mentions_from_payload.cjs");
+
+describe("resolve_mentions_from_payload", () => {
+  let mockCore;
+  let mockGithub;
+  let context;
+
+  /** @type {Record<string, any[][]>} Pages of members per "org/team" */
+  let teamPages;
+
+  beforeEach(() => {
+    mockCore = {
+      infos: /** @type {string[]} */ [],
+      warnings: /** @type {string[]} */ [],
+      info: /** @param {string} msg */ msg => mockCore.infos.push(msg),
+      warning: /** @param {string} msg */ msg => mockCore.warnings.push(msg),
+    };
+
+    teamPages = {
+      "acme/core": [[{ login: "alice", type: "User" }, { login: "bob", type: "User" }], [{ login: "carol", type: "User" }]],
+    };
+
+    const listMembersInOrg = vi.fn(async ({ org, team_slug, page }) => {
+      const pages = teamPages[`${org}/${team_slug}`];
+      if (!pages) {
+        throw Object.assign(new Error("Not Found"), { status: 404 });
+      }
+      return { data: pages[page - 1] || [] };
+    });
+
+    mockGithub = {
+      // Walks pages until an empty one, like octokit's paginate
+      paginate: vi.fn(async (method, params) => {
+        const all = [];
+        for (let page = 1; ; page++) {
+          const { data } = await method({ ...params, page });
+          if (data.length === 0) {
+            return all;
+          }
+          all.push(...data);
+        }
+      }),
+      rest: {
+        teams: { listMembersInOrg },
+        repos: {
+          listCollaborators: vi.fn().mockResolvedValue({ data: [] }),
+          getCollaboratorPermissionLevel: vi.fn().mockResolvedValue({ data: { permission: "none" } }),
+        },
+        users: { getByUsername: vi.fn(async ({ username }) => ({ data: { login: username, type: "User" } })) },
+      },
+    };
+
+    context = { eventName: "push", repo: { owner: "test-owner", repo: "test-repo" }, payload: {} };
+  });
+
+  describe("resolveTeamMembers", () => {
+    it("should collect members from every page", async () => {
+      const members = await resolveTeamMembers(["acme/core"], mockGithub, mockCore);
+
+      expect(members).toEqual(["alice", "bob", "carol"]);
+      expect(mockGithub.paginate).toHaveBeenCalledWith(mockGithub.rest.teams.listMembersInOrg, { org: "acme", team_slug: "core", per_page: 100 });
+      expect(mockGithub.rest.teams.listMembersInOrg).toHaveBeenCalledTimes(3);
+      expect(mockCore.infos).toContain("[MENTIONS] Expanded team @acme/core to 3 member(s)");
+    });
+
+    it("should exclude bot members", async () => {
+      teamPages["acme/bots"] = [[{ login: "helper[bot]", type: "Bot" }, { login: "dave", type: "User" }]];
+      const members = await resolveTeamMembers(["acme/bots"], mockGithub, mockCore);
+
+      expect(members).toEqual(["dave"]);
+    });
+
+    it("should skip teams that fail to expand and keep the others", async () => {
+      const members = await resolveTeamMembers(["acme/missing", "acme/core"], mockGithub, mockCore);
+
+      expect(members).toEqual(["alice", "bob", "carol"]);
+      expect(mockCore.warnings).toContain("[MENTIONS] Failed to expand team @acme/missing: Not Found");
+    });
+
+    it("should skip invalid team slugs without calling the API", async () => {
+      const members = await resolveTeamMembers(["acme", "/core"], mockGithub, mockCore);
+
+      expect(members).toEqual([]);
+      expect(mockGithub.paginate).not.toHaveBeenCalled();
+      expect(mockCore.warnings).toEqual(["[MENTIONS] Ignoring invalid team slug: acme", "[MENTIONS] Ignoring invalid team slug: /core"]);
+    });
+  });
+
+  describe("resolveAllowedMentionsFromPayload with teams", () => {
+    it("should not expand teams when allow-team-members is disabled", async () => {
+      const allowed = await resolveAllowedMentionsFromPayload(context, mockGithub, mockCore, { allowTeamMembers: false, allowed: ["erin"], teams: ["acme/core"] });
+
+      expect(allowed).toEqual(["erin"]);
+      expect(mockGithub.paginate).not.toHaveBeenCalled();
+      expect(mockGithub.rest.repos.listCollaborators).not.toHaveBeenCalled();
+    });
+
+    it("should allow team members that are not collaborators when allow-team-members is enabled", async () => {
+      const allowed = await resolveAllowedMentionsFromPayload(context, mockGithub, mockCore, { teams: ["acme/core"] });
+
+      expect(allowed).toEqual(["alice", "bob", "carol"]);
+      expect(mockGithub.rest.repos.listCollaborators).toHaveBeenCalled();
+      expect(mockGithub.rest.repos.getCollaboratorPermissionLevel).not.toHaveBeenCalled();
+    });
+
+    it("should exclude denied team members", async () => {
+      const allowed = await resolveAllowedMentionsFromPayload(context, mockGithub, mockCore, { teams: ["acme/core"], denied: ["Bob"] });
+
+      expect(allowed).toEqual(["alice", "carol"]);
+    });
+
+    it("should keep the allowed list when team expansion fails", async () => {
+      const allowed = await resolveAllowedMentionsFromPayload(context, mockGithub, mockCore, { allowed: ["erin"], teams: ["acme/missing"] });
+
+      expect(allowed).toEqual(["erin"]);
+      expect(mockCore.warnings.some(msg => msg.includes("Failed to expand team @acme/missing"))).toBe(true);
+    });
+
+    it("should not expand teams when mentions are disabled", async () => {
+      const allowed = await resolveAllowedMentionsFromPayload(context, mockGithub, mockCore, { enabled: false, teams: ["acme/core"] });
+
+      expect(allowed).toEqual([]);
+      expect(mockGithub.paginate).not.toHaveBeenCalled();
+    });
+  });
+});
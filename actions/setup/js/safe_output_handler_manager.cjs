@@ -47,6 +47,8 @@ const HANDLER_MAP = {
   close_pull_request: "./close_pull_request.cjs",
   mark_pull_request_as_ready_for_review: "./mark_pull_request_as_ready_for_review.cjs",
   hide_comment: "./hide_comment.cjs",
+  add_reaction: "./add_reaction_handler.cjs",
+  push_files: "./push_files.cjs",
   add_reviewer: "./add_reviewer.cjs",
   assign_milestone: "./assign_milestone.cjs",
   assign_to_user: "./assign_to_user.cjs",
@@ -32,6 +32,8 @@ async function main(config = {}) {
   const maxCount = config.max || 1;
   const targetConfig = config.target || "triggering";
   const buffer = config._prReviewBuffer;
+  // Events permitted by the workflow configuration; the compiler excludes APPROVE unless allow-approve is set
+  const allowedEvents = Array.isArray(config.allowed_events) ? new Set(config.allowed_events.map(e => String(e).toUpperCase())) : null;
 
   if (!buffer) {
     core.warning("submit_pull_request_review: No PR review buffer provided in config");
@@ -40,7 +42,7 @@ async function main(config = {}) {
     };
   }
 
-  core.info(`Submit PR review handler initialized: max=${maxCount}, target=${targetConfig}`);
+  core.info(`Submit PR review handler initialized: max=${maxCount}, target=${targetConfig}, allowed_events=${allowedEvents ? [...allowedEvents].join(",") : "all"}`);
 
   let processedCount = 0;
 
@@ -69,6 +71,15 @@ async function main(config = {}) {
       };
     }
 
+    if (allowedEvents && !allowedEvents.has(event)) {
+      const allowedList = [...allowedEvents].join(", ");
+      core.warning(`Review event ${event} is not allowed by the workflow configuration. Allowed events: ${allowedList}`);
+      return {
+        success: false,
+        error: `Review event ${event} is not allowed. Allowed events: ${allowedList}`,
+      };
+    }
+
     // Body is required for REQUEST_CHANGES per GitHub API docs;
     // optional for APPROVE and COMMENT
     const body = message.body || "";
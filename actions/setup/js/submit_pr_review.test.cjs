@@ -155,6 +155,27 @@ describe("submit_pr_review (Handler Factory Architecture)", () => {
     expect(result.error).toContain("Invalid review event");
   });
 
+  it("should reject events not in allowed_events", async () => {
+    const { main } = require("./submit_pr_review.cjs");
+    const restrictedHandler = await main({ max: 1, allowed_events: ["COMMENT", "REQUEST_CHANGES"], _prReviewBuffer: buffer });
+
+    const result = await restrictedHandler({ type: "submit_pull_request_review", body: "LGTM", event: "approve" }, {});
+
+    expect(result.success).toBe(false);
+    expect(result.error).toContain("Review event APPROVE is not allowed");
+    expect(buffer.hasReviewMetadata()).toBe(false);
+  });
+
+  it("should accept events listed in allowed_events", async () => {
+    const { main } = require("./submit_pr_review.cjs");
+    const restrictedHandler = await main({ max: 1, allowed_events: ["COMMENT", "REQUEST_CHANGES"], _prReviewBuffer: buffer });
+
+    const result = await restrictedHandler({ type: "submit_pull_request_review", body: "Please fix", event: "REQUEST_CHANGES" }, {});
+
+    expect(result.success).toBe(true);
+    expect(result.event).toBe("REQUEST_CHANGES");
+  });
+
   it("should allow empty body for APPROVE event", async () => {
     const message = {
       type: "submit_pull_request_review",
@@ -326,6 +326,28 @@ interface HideCommentItem extends BaseSafeOutputItem {
   reason?: "SPAM" | "ABUSE" | "OFF_TOPIC" | "OUTDATED" | "RESOLVED";
 }
 
+/**
+ * JSONL item for adding a reaction to the triggering item
+ */
+interface AddReactionItem extends BaseSafeOutputItem {
+  type: "add_reaction";
+  /** Reaction to add to the issue, pull request, discussion, or comment that triggered the workflow */
+  reaction: "+1" | "-1" | "laugh" | "confused" | "heart" | "hooray" | "rocket" | "eyes";
+}
+
+/**
+ * JSONL item for committing files to a branch without opening a pull request
+ */
+interface PushFilesItem extends BaseSafeOutputItem {
+  type: "push_files";
+  /** Branch to commit to; created from the default branch if missing */
+  branch: string;
+  /** Commit message */
+  message: string;
+  /** Files to write, with repository-relative paths and their full content */
+  files: { path: string; content: string }[];
+}
+
 /**
  * JSONL item for replying to a pull request review comment
  */
@@ -405,6 +427,8 @@ type SafeOutputItem =
   | NoOpItem
   | LinkSubIssueItem
   | HideCommentItem
+  | AddReactionItem
+  | PushFilesItem
   | ReplyToPullRequestReviewCommentItem
   | CreateProjectItem
   | AutofixCodeScanningAlertItem
@@ -446,6 +470,8 @@ export {
   NoOpItem,
   LinkSubIssueItem,
   HideCommentItem,
+  AddReactionItem,
+  PushFilesItem,
   ReplyToPullRequestReviewCommentItem,
   AutofixCodeScanningAlertItem,
   ResolvePullRequestReviewThreadItem,
@@ -240,6 +240,7 @@ Examples:
   ` + string(constants.CLIExtensionPrefix) + ` compile --dir custom/workflows  # Compile from custom directory
   ` + string(constants.CLIExtensionPrefix) + ` compile --watch ci-doctor     # Watch and auto-compile
   ` + string(constants.CLIExtensionPrefix) + ` compile --trial --logical-repo owner/repo  # Compile for trial mode
+  ` + string(constants.CLIExtensionPrefix) + ` compile --changed-since origin/main  # Compile only workflows changed since origin/main
   ` + string(constants.CLIExtensionPrefix) + ` compile --dependabot        # Generate Dependabot manifests
   ` + string(constants.CLIExtensionPrefix) + ` compile --dependabot --force  # Force overwrite existing dependabot.yml`,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -266,14 +267,24 @@ Examples:
 		fix, _ := cmd.Flags().GetBool("fix")
 		stats, _ := cmd.Flags().GetBool("stats")
 		failFast, _ := cmd.Flags().GetBool("fail-fast")
+		annotations, _ := cmd.Flags().GetBool("annotations")
+		checkMountSources, _ := cmd.Flags().GetBool("check-mount-sources")
+		allowedMCPImages, _ := cmd.Flags().GetStringArray("allowed-mcp-image")
+		changedSince, _ := cmd.Flags().GetString("changed-since")
 		noCheckUpdate, _ := cmd.Flags().GetBool("no-check-update")
 		verbose, _ := cmd.Flags().GetBool("verbose")
+		repoMirrorSpecs, _ := cmd.Flags().GetStringArray("repo-mirror")
+		offline, _ := cmd.Flags().GetBool("offline")
 		if err := validateEngine(engineOverride); err != nil {
 			return err
 		}
+		repoMirrors, err := parser.ParseRepoMirrors(repoMirrorSpecs)
+		if err != nil {
+			return err
+		}
 
 		// Check for updates (non-blocking, runs once per day)
-		cli.CheckForUpdatesAsync(cmd.Context(), noCheckUpdate, verbose)
+		cli.CheckForUpdatesAsync(cmd.Context(), noCheckUpdate || offline, verbose)
 
 		// If --fix is specified, run fix --write first
 		if fix {
@@ -318,6 +329,12 @@ Examples:
 			JSONOutput:             jsonOutput,
 			Stats:                  stats,
 			FailFast:               failFast,
+			Annotations:            annotations,
+			CheckMountSources:      checkMountSources,
+			AllowedMCPImages:       allowedMCPImages,
+			ChangedSince:           changedSince,
+			RepoMirrors:            repoMirrors,
+			Offline:                offline,
 		}
 		if _, err := cli.CompileWorkflows(cmd.Context(), config); err != nil {
 			// Return error as-is without additional formatting
@@ -558,8 +575,14 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	compileCmd.Flags().Bool("fix", false, "Apply automatic codemod fixes to workflows before compiling")
 	compileCmd.Flags().BoolP("json", "j", false, "Output results in JSON format")
 	compileCmd.Flags().Bool("stats", false, "Display statistics table sorted by file size (shows jobs, steps, scripts, and shells)")
-	compileCmd.Flags().Bool("fail-fast", false, "Stop at the first validation error instead of collecting all errors")
+	compileCmd.Flags().Bool("fail-fast", false, "Stop at the first failed workflow and the first validation error instead of collecting all errors")
+	compileCmd.Flags().Bool("annotations", false, "Emit GitHub Actions error annotations for compilation failures (enabled automatically when GITHUB_ACTIONS=true)")
+	compileCmd.Flags().Bool("check-mount-sources", false, "Warn when the host source of an MCP bind mount does not exist on this machine (an error with --strict)")
+	compileCmd.Flags().StringArray("allowed-mcp-image", nil, "Only allow MCP servers to use this container image, or any image under a prefix ending in '/' (repeatable)")
+	compileCmd.Flags().String("changed-since", "", "Only compile workflows that changed, or whose imports or includes changed, since this git ref (e.g. origin/main)")
 	compileCmd.Flags().Bool("no-check-update", false, "Skip checking for gh-aw updates")
+	compileCmd.Flags().StringArray("repo-mirror", nil, "Read imports from a repository in a local directory instead of GitHub (owner/repo=path, repeatable)")
+	compileCmd.Flags().Bool("offline", false, "Fail instead of fetching imports from repositories without a --repo-mirror from the network")
 	compileCmd.MarkFlagsMutuallyExclusive("dir", "workflows-dir")
 
 	// Register completions for compile command
@@ -610,6 +633,7 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	prCmd := cli.NewPRCommand()
 	secretsCmd := cli.NewSecretsCommand()
 	fixCmd := cli.NewFixCommand()
+	validateCmd := cli.NewValidateCommand()
 	upgradeCmd := cli.NewUpgradeCommand()
 	completionCmd := cli.NewCompletionCommand()
 	hashCmd := cli.NewHashCommand()
@@ -631,6 +655,7 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	statusCmd.GroupID = "development"
 	listCmd.GroupID = "development"
 	fixCmd.GroupID = "development"
+	validateCmd.GroupID = "development"
 
 	// Execution Commands
 	runCmd.GroupID = "execution"
@@ -675,6 +700,7 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(secretsCmd)
 	rootCmd.AddCommand(fixCmd)
+	rootCmd.AddCommand(validateCmd)
 	rootCmd.AddCommand(completionCmd)
 	rootCmd.AddCommand(hashCmd)
 	rootCmd.AddCommand(projectCmd)
@@ -9,7 +9,9 @@ import (
 
 	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/fileutil"
 	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
 	"github.com/github/gh-aw/pkg/tty"
 	"github.com/github/gh-aw/pkg/workflow"
 	"github.com/spf13/cobra"
@@ -33,6 +35,43 @@ type AddOptions struct {
 	NoStopAfter            bool
 	StopAfter              string
 	DisableSecurityScanner bool
+	MaxIncludeFiles        int               // Maximum number of remote include files fetched per workflow (0 uses the default)
+	RootedIncludePrefixes  []string          // Relative include prefixes resolved under .github/ (defaults to shared/)
+	Refresh                bool              // Re-resolve floating refs of already-added workflows and re-download changed files
+	NamespaceShared        bool              // Save shared includes and imports under shared/<owner>-<repo>/ to avoid collisions between sources
+	MaxFileSize            int64             // Maximum size in bytes of each downloaded workflow, include, or import file (0 uses the default)
+	PinRefs                bool              // Rewrite floating refs of imports and includes to the commit SHAs that were fetched
+	Provenance             bool              // Write a <name>.provenance.json file recording the source, commit, and fetched files
+	InlineIncludes         bool              // Replace @include directives with the fetched content instead of saving separate files
+	Stats                  bool              // Report the number of GitHub API calls made while fetching
+	NoSymlinks             bool              // Refuse local workflow files that are, or resolve outside the repository through, symbolic links
+	Frozen                 bool              // Fetch exactly the commits and content recorded in the fetch lock, failing on any difference
+	AllowedSources         []string          // Only fetch from these owners or owner/repo slugs, narrowing GH_AW_ALLOWED_SOURCES when it is set
+	AllowHTTPIncludes      bool              // Fetch includes with http:// and https:// URLs into shared/<host>/ (refused by default)
+	UserAgent              string            // User-Agent sent with GitHub API requests (defaults to gh-aw/<version>)
+	RequestHeaders         map[string]string // Extra headers sent with GitHub API requests, e.g. for an egress proxy
+	MaxConcurrentRequests  int               // Maximum GitHub requests in flight at once across all workflows (0 means unlimited)
+	Scope                  string            // Repository directory that repo-only specs are expanded from and remote workflows must be in
+	CacheDir               string            // Directory caching downloaded files across runs by commit SHA ("" disables the cache)
+	Prune                  bool              // Remove previously fetched include and import files that no workflow references anymore
+
+	// Air-gapped mode: fetches from mirrored repositories are read from the local filesystem
+	RepoMirrors map[string]string // Local directories that replace GitHub for the given owner/repo slugs
+	Offline     bool              // Fail instead of using the network for repositories without a mirror
+
+	// TransformContent optionally rewrites each fetched include and import file saved next to
+	// the workflow, e.g. for templating or redaction. Inlined includes are not transformed.
+	// It is only available to library callers.
+	TransformContent ContentTransform
+
+	// OnFetchEvent optionally receives the progress messages of fetching workflows, includes,
+	// and imports as structured events instead of printing them to stderr. It is only
+	// available to library callers.
+	OnFetchEvent FetchEventHandler
+
+	// fetchOptions holds the fetch options shared by every workflow being added, built once
+	// from the fields above by AddWorkflows or AddResolvedWorkflows (see newAddFetchOptions)
+	fetchOptions *remoteFetchOptions
 }
 
 // AddWorkflowsResult contains the result of adding workflows
@@ -71,21 +110,47 @@ Examples:
   ` + string(constants.CLIExtensionPrefix) + ` add githubnext/agentics/ci-doctor --push         # Add and push changes
   ` + string(constants.CLIExtensionPrefix) + ` add ./my-workflow.md                             # Add local workflow
   ` + string(constants.CLIExtensionPrefix) + ` add ./*.md                                       # Add all local workflows
+  ` + string(constants.CLIExtensionPrefix) + ` add ./path/to/dir                                # Add the agentic workflows in a local directory
   ` + string(constants.CLIExtensionPrefix) + ` add githubnext/agentics/ci-doctor --dir shared   # Add to .github/workflows/shared/
 
 Workflow specifications:
+  - Two parts: "owner/repo[@version]" (adds the repository's only agentic workflow)
   - Three parts: "owner/repo/workflow-name[@version]" (implicitly looks in workflows/ directory)
   - Four+ parts: "owner/repo/workflows/workflow-name.md[@version]" (requires explicit .md extension)
-  - GitHub URL: "https://github.com/owner/repo/blob/branch/path/to/workflow.md"
+  - GitHub URL: "https://github.com/owner/repo/blob/branch/path/to/workflow.md" (permalinks pin the commit)
+  - Repository URL: "https://github.com/owner/repo[/tree/branch]" (like the two-part form)
   - Local file: "./path/to/workflow.md" (adds a workflow from local filesystem)
   - Local wildcard: "./*.md" or "./dir/*.md" (adds all .md files matching pattern)
-  - Version can be tag, branch, or SHA (for remote workflows)
+  - Local directory: "./dir" (adds the agentic workflows directly in dir, skipping shared components)
+  - Version can be tag, branch, or SHA (for remote workflows); a name used by both a branch and
+    a tag is rejected as ambiguous, so qualify it as refs/heads/<name> or refs/tags/<name>
 
 The -n flag allows you to specify a custom name for the workflow file (only applies to the first workflow when adding multiple).
 The --dir flag allows you to specify a subdirectory under .github/workflows/ where the workflow will be added.
 The --create-pull-request flag (or --pr) automatically creates a pull request with the workflow changes.
 The --push flag automatically commits and pushes changes after successful workflow addition.
 The --force flag overwrites existing workflow files.
+The --refresh flag re-resolves the branch of already-added workflows and re-downloads files that
+changed upstream; workflows pinned to a commit SHA or version tag are left untouched.
+The --namespace-shared flag saves shared include and import files under shared/<owner>-<repo>/
+and rewrites references to them, so shared files from different repositories do not collide.
+The --pin-refs flag rewrites branch and tag refs of workflowspec imports and includes in the added
+workflow to the commit SHAs that were fetched, so a floating install becomes reproducible.
+The --prune flag removes include and import files that an earlier add fetched (as recorded in
+.github/aw/fetch-lock.json) and that no workflow references anymore.
+The --provenance flag writes <name>.provenance.json next to the workflow, recording the source
+spec, resolved commit, fetch time, and the include and import files fetched with their SHAs.
+It is rewritten on every reinstall; add *.provenance.json to .gitignore to keep it out of commits.
+The --inline-includes flag replaces @include directives with the content they reference,
+recursively, producing a single self-contained workflow file instead of separate include files.
+Conditional includes and includes of files with frontmatter are still saved as separate files.
+The --repo-mirror flag (owner/repo=path, repeatable) reads a repository from a local directory
+instead of GitHub; a git mirror is read at the requested ref. With --offline, repositories without
+a mirror fail instead of being fetched from the network, for air-gapped environments.
+The --stats flag reports how many GitHub API calls were made to fetch workflows, includes, and
+imports (also shown with --verbose).
+The --quiet flag suppresses success and progress messages but still reports warnings and errors,
+for scripts that only want to hear about problems. --verbose takes precedence.
 The --non-interactive flag skips the guided setup and uses traditional behavior.
 
 Note: To create a new workflow from scratch, use the 'new' command instead.`,
@@ -106,25 +171,86 @@ Note: To create a new workflow from scratch, use the 'new' command instead.`,
 			forceFlag, _ := cmd.Flags().GetBool("force")
 			appendText, _ := cmd.Flags().GetString("append")
 			verbose, _ := cmd.Flags().GetBool("verbose")
+			quiet, _ := cmd.Flags().GetBool("quiet")
 			noGitattributes, _ := cmd.Flags().GetBool("no-gitattributes")
 			workflowDir, _ := cmd.Flags().GetString("dir")
 			noStopAfter, _ := cmd.Flags().GetBool("no-stop-after")
 			stopAfter, _ := cmd.Flags().GetString("stop-after")
 			nonInteractive, _ := cmd.Flags().GetBool("non-interactive")
 			disableSecurityScanner, _ := cmd.Flags().GetBool("disable-security-scanner")
+			maxIncludeFiles, _ := cmd.Flags().GetInt("max-include-files")
+			rootedIncludePrefixes, _ := cmd.Flags().GetStringSlice("rooted-include-prefix")
+			refreshFlag, _ := cmd.Flags().GetBool("refresh")
+			namespaceShared, _ := cmd.Flags().GetBool("namespace-shared")
+			maxFileSize, _ := cmd.Flags().GetInt64("max-file-size")
+			pinRefs, _ := cmd.Flags().GetBool("pin-refs")
+			provenance, _ := cmd.Flags().GetBool("provenance")
+			inlineIncludes, _ := cmd.Flags().GetBool("inline-includes")
+			stats, _ := cmd.Flags().GetBool("stats")
+			noSymlinks, _ := cmd.Flags().GetBool("no-symlinks")
+			frozen, _ := cmd.Flags().GetBool("frozen")
+			allowedSourcePatterns, _ := cmd.Flags().GetStringSlice("allowed-source")
+			allowHTTPIncludesFlag, _ := cmd.Flags().GetBool("allow-http-includes")
+			repoMirrorSpecs, _ := cmd.Flags().GetStringArray("repo-mirror")
+			offline, _ := cmd.Flags().GetBool("offline")
+			userAgent, _ := cmd.Flags().GetString("user-agent")
+			headerSpecs, _ := cmd.Flags().GetStringArray("header")
+			maxConcurrentRequests, _ := cmd.Flags().GetInt("max-concurrent-requests")
+			scope, _ := cmd.Flags().GetString("scope")
+			cacheDir, _ := cmd.Flags().GetString("cache-dir")
+			useCache, _ := cmd.Flags().GetBool("cache")
+			prune, _ := cmd.Flags().GetBool("prune")
 			if err := validateEngine(engineOverride); err != nil {
 				return err
 			}
+			repoMirrors, err := parser.ParseRepoMirrors(repoMirrorSpecs)
+			if err != nil {
+				return err
+			}
+			requestHeaders, err := parser.ParseRequestHeaders(headerSpecs)
+			if err != nil {
+				return err
+			}
+
+			// Cache downloaded files across runs only when requested
+			if useCache && cacheDir == "" {
+				if cacheDir, err = parser.DefaultContentCacheDir(); err != nil {
+					return err
+				}
+			}
 
 			// Determine if we should use interactive mode
 			// Interactive mode is the default for TTY unless:
 			// - --non-interactive flag is set
-			// - Any of the batch/automation flags are set (--create-pull-request, --force, --name, --append)
+			// - Any of the batch/automation flags are set (--create-pull-request, --force, --refresh, --name, --append, --offline, --repo-mirror, --no-symlinks, --frozen, --allowed-source, --allow-http-includes, --user-agent, --header, --max-concurrent-requests, --scope, --cache, --cache-dir, --quiet, --stats)
+			// - Any of the fetch flags that the interactive flow does not apply are set (--pin-refs, --namespace-shared, --provenance, --inline-includes, --max-include-files, --max-file-size, --rooted-include-prefix, --prune)
 			// - Not a TTY (piped input/output)
 			// - In CI environment
 			useInteractive := !nonInteractive &&
 				!prFlag &&
 				!forceFlag &&
+				!refreshFlag &&
+				!offline &&
+				len(repoMirrorSpecs) == 0 &&
+				!noSymlinks &&
+				!frozen &&
+				!quiet &&
+				len(allowedSourcePatterns) == 0 &&
+				!allowHTTPIncludesFlag &&
+				userAgent == "" &&
+				len(headerSpecs) == 0 &&
+				maxConcurrentRequests == 0 &&
+				scope == "" &&
+				cacheDir == "" &&
+				!stats &&
+				!pinRefs &&
+				!namespaceShared &&
+				!provenance &&
+				!inlineIncludes &&
+				!cmd.Flags().Changed("max-include-files") &&
+				!cmd.Flags().Changed("max-file-size") &&
+				len(rootedIncludePrefixes) == 0 &&
+				!prune &&
 				nameFlag == "" &&
 				appendText == "" &&
 				tty.IsStdoutTerminal() &&
@@ -139,6 +265,7 @@ Note: To create a new workflow from scratch, use the 'new' command instead.`,
 			// Handle normal (non-interactive) mode
 			opts := AddOptions{
 				Verbose:                verbose,
+				Quiet:                  quiet && !verbose,
 				EngineOverride:         engineOverride,
 				Name:                   nameFlag,
 				Force:                  forceFlag,
@@ -150,8 +277,29 @@ Note: To create a new workflow from scratch, use the 'new' command instead.`,
 				NoStopAfter:            noStopAfter,
 				StopAfter:              stopAfter,
 				DisableSecurityScanner: disableSecurityScanner,
+				MaxIncludeFiles:        maxIncludeFiles,
+				RootedIncludePrefixes:  rootedIncludePrefixes,
+				Refresh:                refreshFlag,
+				NamespaceShared:        namespaceShared,
+				MaxFileSize:            maxFileSize,
+				PinRefs:                pinRefs,
+				Provenance:             provenance,
+				InlineIncludes:         inlineIncludes,
+				Stats:                  stats,
+				NoSymlinks:             noSymlinks,
+				Frozen:                 frozen,
+				AllowedSources:         allowedSourcePatterns,
+				AllowHTTPIncludes:      allowHTTPIncludesFlag,
+				RepoMirrors:            repoMirrors,
+				Offline:                offline,
+				UserAgent:              userAgent,
+				RequestHeaders:         requestHeaders,
+				MaxConcurrentRequests:  maxConcurrentRequests,
+				Scope:                  scope,
+				CacheDir:               cacheDir,
+				Prune:                  prune,
 			}
-			_, err := AddWorkflows(workflows, opts)
+			_, err = AddWorkflows(workflows, opts)
 			return err
 		},
 	}
@@ -176,6 +324,24 @@ Note: To create a new workflow from scratch, use the 'new' command instead.`,
 	// Add force flag to add command
 	cmd.Flags().BoolP("force", "f", false, "Overwrite existing workflow files without confirmation")
 
+	// Add refresh flag to add command
+	cmd.Flags().Bool("refresh", false, "Re-resolve the branch of already-added workflows and re-download files that changed upstream (pinned SHAs and tags are left untouched)")
+
+	// Add namespace-shared flag to add command
+	cmd.Flags().Bool("namespace-shared", false, "Save shared include and import files under shared/<owner>-<repo>/ so files from different repositories do not collide")
+
+	// Add pin-refs flag to add command
+	cmd.Flags().Bool("pin-refs", false, "Rewrite branch and tag refs of workflowspec imports and includes to the commit SHAs that were fetched")
+
+	// Add prune flag to add command
+	cmd.Flags().Bool("prune", false, "Remove previously fetched include and import files that no workflow references anymore")
+
+	// Add provenance flag to add command
+	cmd.Flags().Bool("provenance", false, "Write a <name>.provenance.json file next to the workflow recording its source, commit, and fetched files")
+
+	// Add inline-includes flag to add command
+	cmd.Flags().Bool("inline-includes", false, "Replace @include directives with the fetched content to produce a single self-contained workflow file")
+
 	// Add append flag to add command
 	cmd.Flags().String("append", "", "Append extra content to the end of agentic workflow on installation")
 
@@ -197,6 +363,51 @@ Note: To create a new workflow from scratch, use the 'new' command instead.`,
 	// Add disable-security-scanner flag to add command
 	cmd.Flags().Bool("disable-security-scanner", false, "Disable security scanning of workflow markdown content")
 
+	// Add max-include-files flag to add command
+	cmd.Flags().Int("max-include-files", defaultMaxIncludeFiles, "Maximum number of remote include files fetched per workflow, including nested includes")
+
+	// Add max-file-size flag to add command
+	cmd.Flags().Int64("max-file-size", parser.DefaultMaxDownloadSize, "Maximum size in bytes of each downloaded workflow, include, or import file")
+
+	// Add rooted-include-prefix flag to add command
+	cmd.Flags().StringSlice("rooted-include-prefix", nil, "Relative include prefix resolved under .github/ instead of the workflow directory (repeatable, default: shared/)")
+
+	// Add stats flag to add command
+	cmd.Flags().Bool("stats", false, "Report the number of GitHub API calls made while fetching workflows, includes, and imports")
+
+	// Add quiet flag to add command
+	cmd.Flags().BoolP("quiet", "q", false, "Only report warnings and errors")
+
+	// Add no-symlinks flag to add command
+	cmd.Flags().Bool("no-symlinks", false, "Refuse local workflow files that are symbolic links or resolve outside the repository through one")
+
+	// Add frozen flag to add command
+	cmd.Flags().Bool("frozen", false, "Fetch includes and imports at the commits recorded in .github/aw/fetch-lock.json and fail if any file differs from the lock")
+
+	// Add allowed-source flag to add command
+	cmd.Flags().StringSlice("allowed-source", nil, "Only fetch workflows, includes, and imports from this owner or owner/repo (repeatable; narrows $GH_AW_ALLOWED_SOURCES when it is set)")
+
+	// Add allow-http-includes flag to add command
+	cmd.Flags().Bool("allow-http-includes", false, "Fetch @include directives with http:// and https:// URLs and save them under shared/<host>/")
+
+	// Add repo-mirror and offline flags to add command
+	cmd.Flags().StringArray("repo-mirror", nil, "Read a repository from a local directory instead of GitHub (owner/repo=path, repeatable)")
+	cmd.Flags().Bool("offline", false, "Fail instead of fetching repositories without a --repo-mirror from the network")
+
+	// Add user-agent and header flags to add command
+	cmd.Flags().String("user-agent", "", "User-Agent sent with GitHub API requests (default: gh-aw/<version>)")
+	cmd.Flags().StringArray("header", nil, "Extra header sent with GitHub API requests, e.g. for an egress proxy (\"Name: value\", repeatable)")
+
+	// Add max-concurrent-requests flag to add command
+	cmd.Flags().Int("max-concurrent-requests", 0, "Maximum number of GitHub requests in flight at once across all workflows being added (0 means unlimited)")
+
+	// Add scope flag to add command
+	cmd.Flags().String("scope", "", "Only add workflows in this repository directory; owner/repo adds every workflow directly in it")
+
+	// Add cache and cache-dir flags to add command
+	cmd.Flags().Bool("cache", false, "Cache downloaded files on disk across runs by commit SHA, in the user cache directory unless --cache-dir is set")
+	cmd.Flags().String("cache-dir", "", "Directory caching downloaded files across runs by commit SHA (implies --cache)")
+
 	// Register completions for add command
 	RegisterEngineFlagCompletion(cmd)
 	RegisterDirFlagCompletion(cmd, "dir")
@@ -208,8 +419,29 @@ Note: To create a new workflow from scratch, use the 'new' command instead.`,
 // with optional repository installation and PR creation.
 // Returns AddWorkflowsResult containing PR number (if created) and other metadata.
 func AddWorkflows(workflows []string, opts AddOptions) (*AddWorkflowsResult, error) {
+	// Surface the remaining GitHub API quota once all fetching is done
+	defer reportGitHubRateLimit(opts.Verbose, opts.OnFetchEvent)
+
+	// Report the API calls made by this add once all fetching is done
+	defer reportGitHubAPICalls(parser.CurrentAPICallStats(), opts.Verbose || opts.Stats, opts.OnFetchEvent)
+
+	// Share one fetcher, and so its downloads, request limit, and frozen refs, across all workflows
+	fetchOpts, err := newAddFetchOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	opts.fetchOptions = &fetchOpts
+
+	// Expand repo-only specs to the workflows in the requested directory
+	if opts.Scope != "" {
+		workflows, err = expandScopedWorkflows(workflows, opts.Scope, fetchOpts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Resolve workflows first - fetches content directly from GitHub
-	resolved, err := ResolveWorkflows(workflows, opts.Verbose)
+	resolved, err := resolveWorkflows(workflows, fetchOpts)
 	if err != nil {
 		return nil, err
 	}
@@ -225,6 +457,15 @@ func AddResolvedWorkflows(workflowStrings []string, resolved *ResolvedWorkflows,
 
 	result := &AddWorkflowsResult{}
 
+	// Callers that resolved the workflows themselves fetch dependencies with the same configuration
+	if opts.fetchOptions == nil {
+		fetchOpts, err := newAddFetchOptions(opts)
+		if err != nil {
+			return nil, err
+		}
+		opts.fetchOptions = &fetchOpts
+	}
+
 	// If creating a PR, check prerequisites
 	if opts.CreatePR {
 		// Check if GitHub CLI is available
@@ -266,6 +507,62 @@ func AddResolvedWorkflows(workflowStrings []string, resolved *ResolvedWorkflows,
 	return result, addWorkflows(resolved.Workflows, opts)
 }
 
+// newAddFetchOptions builds the fetch options shared by every workflow added with opts: the
+// allowed sources, the fetch lock in frozen mode, and a fetcher sending the configured request
+// headers, reading repository mirrors, bounding the requests in flight, and caching downloads
+func newAddFetchOptions(opts AddOptions) (remoteFetchOptions, error) {
+	// Refuse to fetch from repositories outside the allowed sources
+	allowedSources, err := parseAllowedSources(opts.AllowedSources)
+	if err != nil {
+		return remoteFetchOptions{}, err
+	}
+	fetchOpts := remoteFetchOptions{
+		LogLevel:          fetchLogLevel(opts.Verbose, false),
+		OnEvent:           opts.OnFetchEvent,
+		MaxFileSize:       opts.MaxFileSize,
+		AllowedSources:    allowedSources,
+		NoSymlinks:        opts.NoSymlinks,
+		AllowHTTPIncludes: opts.AllowHTTPIncludes,
+	}
+	config := parser.FetchConfig{
+		UserAgent:             opts.UserAgent,
+		RequestHeaders:        opts.RequestHeaders,
+		RepoMirrors:           opts.RepoMirrors,
+		Offline:               opts.Offline,
+		MaxConcurrentRequests: opts.MaxConcurrentRequests,
+		CacheDir:              opts.CacheDir,
+	}
+
+	// Fetch exactly what the fetch lock records, without resolving floating refs
+	if opts.Frozen {
+		if opts.Refresh {
+			return remoteFetchOptions{}, errors.New("--frozen cannot be combined with --refresh")
+		}
+		gitRoot, err := findGitRoot()
+		if err != nil {
+			return remoteFetchOptions{}, fmt.Errorf("--frozen requires a git repository: %w", err)
+		}
+		if fetchOpts.Frozen, err = loadFrozenFetch(gitRoot); err != nil {
+			return remoteFetchOptions{}, err
+		}
+		config.FrozenRefs = fetchOpts.Frozen.lock.ResolvedRefs()
+	}
+
+	if fetchOpts.Fetcher, err = parser.NewFetcher(config); err != nil {
+		return remoteFetchOptions{}, err
+	}
+	return fetchOpts, nil
+}
+
+// addFetchOptions returns the fetch options shared by the workflows added with opts, building
+// them when AddWorkflows or AddResolvedWorkflows has not
+func addFetchOptions(opts AddOptions) (remoteFetchOptions, error) {
+	if opts.fetchOptions != nil {
+		return *opts.fetchOptions, nil
+	}
+	return newAddFetchOptions(opts)
+}
+
 // addWorkflows handles workflow addition using pre-fetched content
 func addWorkflows(workflows []*ResolvedWorkflow, opts AddOptions) error {
 	// Create file tracker for all operations
@@ -315,6 +612,17 @@ func addWorkflowsWithTracking(workflows []*ResolvedWorkflow, tracker *FileTracke
 		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Successfully added all %d workflows", len(workflows))))
 	}
 
+	// Remove previously fetched includes/imports that no workflow references anymore
+	if opts.Prune && tracker != nil {
+		workflowsDir := filepath.Join(tracker.gitRoot, ".github/workflows")
+		if _, err := pruneOrphanedFetchedFiles(tracker.gitRoot, workflowsDir, tracker, opts.Verbose); err != nil {
+			addLog.Printf("Failed to prune orphaned fetched files: %v", err)
+			if opts.Verbose {
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to prune orphaned fetched files: %v", err)))
+			}
+		}
+	}
+
 	// If --push is enabled, commit and push changes
 	if opts.Push {
 		addLog.Print("Push enabled - preparing to commit and push changes")
@@ -442,6 +750,20 @@ func addWorkflowWithTracking(resolved *ResolvedWorkflow, tracker *FileTracker, o
 
 	// Check if a workflow with this name already exists
 	existingFile := filepath.Join(githubWorkflowsDir, workflowName+".md")
+	if _, err := os.Stat(existingFile); err == nil && opts.Refresh && !opts.Force && !IsLocalWorkflowPath(workflowSpec.WorkflowPath) {
+		commitSHA := ""
+		if sourceInfo != nil {
+			commitSHA = sourceInfo.CommitSHA
+		}
+		refresh, reason := checkWorkflowRefresh(existingFile, workflowSpec, commitSHA)
+		if !refresh {
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Skipping refresh of '%s': %s", workflowName, reason)))
+			return nil
+		}
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Refreshing '%s': %s", workflowName, reason)))
+		// Overwrite the workflow; includes and imports are only rewritten when their content changed
+		opts.Force = true
+	}
 	if _, err := os.Stat(existingFile); err == nil && !opts.Force {
 		if opts.FromWildcard {
 			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Workflow '%s' already exists in .github/workflows/. Skipping.", workflowName)))
@@ -451,20 +773,88 @@ func addWorkflowWithTracking(resolved *ResolvedWorkflow, tracker *FileTracker, o
 	}
 
 	// For remote workflows, fetch and save include dependencies directly from the source
-	if !isLocalWorkflowPath(workflowSpec.WorkflowPath) {
-		if err := fetchAndSaveRemoteIncludes(string(sourceContent), workflowSpec, githubWorkflowsDir, opts.Verbose, opts.Force, tracker); err != nil {
-			if opts.Verbose {
+	if !IsLocalWorkflowPath(workflowSpec.WorkflowPath) {
+		// In quiet mode only warnings are reported; verbose mode also reports progress
+		logLevel := fetchLogLevel(opts.Verbose, opts.Quiet)
+		fetchOpts, err := addFetchOptions(opts)
+		if err != nil {
+			return err
+		}
+		fetchOpts.LogLevel = logLevel
+		fetchOpts.Force = opts.Force
+		fetchOpts.Tracker = tracker
+		fetchOpts.MaxIncludeFiles = opts.MaxIncludeFiles
+		fetchOpts.RootedIncludePrefixes = opts.RootedIncludePrefixes
+		fetchOpts.NamespaceShared = opts.NamespaceShared
+		fetchOpts.Transform = opts.TransformContent
+
+		if opts.InlineIncludes {
+			inlined, err := inlineRemoteIncludes(string(sourceContent), workflowSpec, fetchOpts)
+			if err != nil {
+				return fmt.Errorf("failed to inline includes: %w", err)
+			}
+			sourceContent = []byte(inlined)
+		}
+
+		// Compilation reads included files, not directories, so include each file of an
+		// included directory individually
+		expanded, err := expandDirectoryIncludeDirectives(string(sourceContent), workflowSpec, false, fetchOpts)
+		if err != nil {
+			if isFatalFetchError(err) {
+				return err
+			}
+			if logLevel.showWarnings() {
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to expand include directories: %v", err)))
+			}
+		} else {
+			sourceContent = []byte(expanded)
+		}
+
+		// After inlining, only conditional includes and includes with frontmatter remain to be
+		// saved as separate files
+		installedIncludes, err := fetchAndSaveRemoteIncludes(string(sourceContent), workflowSpec, githubWorkflowsDir, fetchOpts)
+		if err != nil {
+			if errors.Is(err, ErrIncludeBudgetExceeded) || isFatalFetchError(err) {
+				return err
+			}
+			if logLevel.showWarnings() {
 				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to fetch include dependencies: %v", err)))
 			}
 		}
 		// Also fetch and save frontmatter 'imports:' dependencies so they are available
 		// locally during compilation. Keeping these as relative paths (not workflowspecs)
 		// ensures the compiler resolves them from disk rather than downloading from GitHub.
-		if err := fetchAndSaveRemoteFrontmatterImports(string(sourceContent), workflowSpec, githubWorkflowsDir, opts.Verbose, opts.Force, tracker); err != nil {
-			if opts.Verbose {
+		installedImports, err := fetchAndSaveRemoteFrontmatterImports(string(sourceContent), workflowSpec, githubWorkflowsDir, fetchOpts)
+		if err != nil {
+			if isFatalFetchError(err) {
+				return err
+			}
+			if logLevel.showWarnings() {
 				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to fetch frontmatter import dependencies: %v", err)))
 			}
 		}
+
+		// Record the commits the fetched files came from so their refs can be pinned later
+		knownCommits := make(map[string]string)
+		if sourceInfo != nil && sourceInfo.CommitSHA != "" {
+			ref := workflowSpec.Version
+			if ref == "" {
+				ref = "main"
+			}
+			knownCommits[workflowSpec.RepoSlug+"@"+ref] = sourceInfo.CommitSHA
+		}
+		// Frozen fetches leave the fetch lock unchanged
+		if fetchOpts.Frozen == nil {
+			recordFetchedCommitSHAs(tracker, fetchOpts.Fetcher, knownCommits)
+		}
+
+		installed := append(installedIncludes, installedImports...)
+		if sourceInfo != nil {
+			sourceInfo.Imports = installed
+		}
+		if len(installed) > 0 && !opts.Quiet {
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Installed %d dependency file(s) for %s", len(installed), workflowName)))
+		}
 	} else if sourceInfo != nil && sourceInfo.IsLocal {
 		// For local workflows, collect and copy include dependencies from local paths
 		// The source directory is derived from the workflow's path
@@ -512,6 +902,17 @@ func addWorkflowWithTracking(resolved *ResolvedWorkflow, tracker *FileTracker, o
 		// Note: frontmatter 'imports:' are intentionally kept as relative paths here.
 		// fetchAndSaveRemoteFrontmatterImports already downloaded those files locally, so
 		// the compiler can resolve them from disk without any GitHub API calls.
+		// With --namespace-shared they are rewritten to the namespaced copies.
+		if opts.NamespaceShared && !IsLocalWorkflowPath(workflowSpec.WorkflowPath) {
+			namespacedContent, err := namespaceFrontmatterImports(content, workflowName+".md", sharedNamespace(workflowSpec.RepoSlug))
+			if err != nil {
+				if opts.Verbose {
+					fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to namespace imports: %v", err)))
+				}
+			} else {
+				content = namespacedContent
+			}
+		}
 
 		// Process @include directives and replace with workflowspec
 		// For local workflows, use the workflow's directory as the base path
@@ -529,6 +930,18 @@ func addWorkflowWithTracking(resolved *ResolvedWorkflow, tracker *FileTracker, o
 		}
 	}
 
+	// Pin floating refs of imports and includes to the commits that were fetched
+	if opts.PinRefs && tracker != nil && tracker.gitRoot != "" {
+		pinnedContent, err := pinWorkflowRefsFromFetchLock(content, tracker.gitRoot, opts.Verbose)
+		if err != nil {
+			if opts.Verbose {
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to pin refs: %v", err)))
+			}
+		} else {
+			content = pinnedContent
+		}
+	}
+
 	// Handle stop-after field modifications
 	if opts.NoStopAfter {
 		cleanedContent, err := RemoveFieldFromOnTrigger(content, "stop-after")
@@ -589,10 +1002,21 @@ func addWorkflowWithTracking(resolved *ResolvedWorkflow, tracker *FileTracker, o
 	}
 
 	// Write the file
-	if err := os.WriteFile(destFile, []byte(content), 0600); err != nil {
+	if err := fileutil.WriteFileAtomic(destFile, []byte(content), 0600); err != nil {
 		return fmt.Errorf("failed to write destination file '%s': %w", destFile, err)
 	}
 
+	// Record where the workflow and its dependencies came from
+	if opts.Provenance && sourceInfo != nil {
+		provenanceFile, err := writeWorkflowProvenance(destFile, buildWorkflowProvenance(gitRoot, workflowSpec, sourceInfo), tracker)
+		if err != nil {
+			return err
+		}
+		if opts.Verbose {
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Wrote provenance file: "+provenanceFile))
+		}
+	}
+
 	// Show output
 	if !opts.Quiet {
 		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Added workflow: "+destFile))
@@ -604,6 +1028,14 @@ func addWorkflowWithTracking(resolved *ResolvedWorkflow, tracker *FileTracker, o
 		}
 	}
 
+	// Imports are fetched best-effort; report one that is missing before compiling
+	if !IsLocalWorkflowPath(workflowSpec.WorkflowPath) {
+		if err := verifyFrontmatterImportsExist(content, destFile); err != nil {
+			fmt.Fprintln(os.Stderr, console.FormatErrorMessage(err.Error()))
+			return nil
+		}
+	}
+
 	// Compile the workflow
 	if tracker != nil {
 		if err := compileWorkflowWithTracking(destFile, opts.Verbose, opts.Quiet, opts.EngineOverride, tracker); err != nil {
@@ -93,13 +93,14 @@ func TestAddWorkflows(t *testing.T) {
 			errorContains: "at least one workflow",
 		},
 		{
-			name:          "repo-only spec (requires workflow path)",
+			name:          "repo-only spec without discoverable workflows",
 			workflows:     []string{"owner/repo"},
 			expectError:   true,
-			errorContains: "workflow specification must be in format",
+			errorContains: "no agentic workflows found in owner/repo@trunk",
 		},
 	}
 
+	stubDefaultWorkflows(t, "trunk", nil, nil)
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			opts := AddOptions{}
@@ -313,7 +314,7 @@ func TestAddCommandBooleanFlags(t *testing.T) {
 	cmd := NewAddCommand(validateEngineStub)
 	flags := cmd.Flags()
 
-	boolFlags := []string{"create-pull-request", "pr", "force", "no-gitattributes", "no-stop-after"}
+	boolFlags := []string{"create-pull-request", "pr", "force", "no-gitattributes", "no-stop-after", "prune"}
 
 	for _, flagName := range boolFlags {
 		t.Run(flagName, func(t *testing.T) {
@@ -246,6 +246,72 @@ func TestExpandLocalWildcardWorkflows_NoMatches(t *testing.T) {
 	assert.Contains(t, err.Error(), "no workflows to add after expansion")
 }
 
+// TestLocalWorkflowDirectory tests adding workflows from a local directory
+func TestLocalWorkflowDirectory(t *testing.T) {
+	tempDir := testutil.TempDir(t, "test-*")
+	workflow := "---\non: push\n---\n\n# Test Workflow\n"
+	files := map[string]string{
+		"single/only.md":     workflow,
+		"single/README.md":   "# Docs\n",
+		"multi/b.md":         workflow,
+		"multi/a.md":         workflow,
+		"multi/shared.md":    "---\ntools:\n  bash: true\n---\n",
+		"multi/nested/c.md":  workflow,
+		"empty/notes.md":     "# Notes\n",
+		"empty/workflow.txt": workflow,
+	}
+	for name, content := range files {
+		filePath := filepath.Join(tempDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0755), "directory should be created")
+		require.NoError(t, os.WriteFile(filePath, []byte(content), 0644), "file should be written")
+	}
+	t.Chdir(tempDir)
+
+	t.Run("directory spec is parsed as a wildcard", func(t *testing.T) {
+		spec, err := parseWorkflowSpec("./multi")
+		require.NoError(t, err, "directory should be accepted")
+		assert.True(t, spec.IsWildcard, "directory should expand like a wildcard")
+		assert.Equal(t, "./multi", spec.WorkflowPath, "path should be kept")
+
+		_, err = parseWorkflowSpec("./missing")
+		require.Error(t, err, "missing path without .md should still be rejected")
+	})
+
+	t.Run("directory expands to its agentic workflows", func(t *testing.T) {
+		spec, err := parseWorkflowSpec("./multi/")
+		require.NoError(t, err, "directory should be accepted")
+		expanded, err := expandLocalWildcardWorkflows([]*WorkflowSpec{spec}, false)
+		require.NoError(t, err, "directory should expand")
+
+		var paths []string
+		for _, s := range expanded {
+			assert.True(t, IsLocalWorkflowPath(s.WorkflowPath), "%s should stay local", s.WorkflowPath)
+			paths = append(paths, s.WorkflowPath)
+		}
+		assert.Equal(t, []string{"./multi/a.md", "./multi/b.md"}, paths, "only agentic workflows directly in the directory should be added")
+	})
+
+	t.Run("directory with one workflow is fetched directly", func(t *testing.T) {
+		spec := &WorkflowSpec{WorkflowPath: "./single", WorkflowName: "single"}
+		fetched, err := FetchWorkflowFromSource(spec, false)
+		require.NoError(t, err, "single workflow should be fetched")
+		assert.Equal(t, "./single/only.md", fetched.SourcePath, "the directory's workflow should be read")
+		assert.Equal(t, "only", spec.WorkflowName, "workflow name should come from the file")
+	})
+
+	t.Run("directory with several workflows must be narrowed down", func(t *testing.T) {
+		_, err := FetchWorkflowFromSource(&WorkflowSpec{WorkflowPath: "./multi"}, false)
+		require.Error(t, err, "ambiguous directory should fail")
+		assert.Contains(t, err.Error(), "./multi/a.md", "error should list the candidates")
+	})
+
+	t.Run("directory without workflows", func(t *testing.T) {
+		_, err := FetchWorkflowFromSource(&WorkflowSpec{WorkflowPath: "./empty"}, false)
+		require.Error(t, err, "empty directory should fail")
+		assert.Contains(t, err.Error(), "no agentic workflows found", "error should explain the problem")
+	})
+}
+
 // TestAddWorkflowWithTracking_WildcardDuplicateHandling tests that when adding workflows from wildcard,
 // existing workflows emit warnings and are skipped instead of erroring
 func TestAddWorkflowWithTracking_WildcardDuplicateHandling(t *testing.T) {
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var addWorkflowRefreshLog = logger.New("cli:add_workflow_refresh")
+
+// checkWorkflowRefresh decides whether an already-added workflow should be refreshed by
+// 'add --refresh'. Workflows requested at a pinned ref (commit SHA or version tag) are left
+// untouched. For floating refs (branches or the default branch), resolvedSHA is the commit
+// the ref resolves to now; the workflow is refreshed when it differs from the commit
+// recorded in the installed workflow's source field.
+// Returns whether to refresh and a short human-readable reason.
+func checkWorkflowRefresh(existingFile string, spec *WorkflowSpec, resolvedSHA string) (bool, string) {
+	addWorkflowRefreshLog.Printf("Checking refresh: file=%s, spec=%s, resolved=%s", existingFile, spec.String(), resolvedSHA)
+
+	if spec.Version != "" && !isBranchRef(spec.Version) {
+		return false, fmt.Sprintf("pinned to %s", shortRef(spec.Version))
+	}
+
+	recordedSHA := installedSourceRef(existingFile)
+	if resolvedSHA == "" {
+		// The ref could not be resolved; refresh anyway and let content comparison decide
+		return true, "could not resolve the current commit, re-downloading"
+	}
+	if recordedSHA == "" {
+		return true, "no recorded source commit, updating to " + shortRef(resolvedSHA)
+	}
+	if recordedSHA == resolvedSHA {
+		return false, "already up to date at " + shortRef(resolvedSHA)
+	}
+	return true, fmt.Sprintf("updating %s → %s", shortRef(recordedSHA), shortRef(resolvedSHA))
+}
+
+// installedSourceRef returns the ref recorded in the source field of an installed workflow,
+// or an empty string when the file has no parseable source field
+func installedSourceRef(workflowPath string) string {
+	content, err := os.ReadFile(workflowPath)
+	if err != nil {
+		addWorkflowRefreshLog.Printf("Failed to read %s: %v", workflowPath, err)
+		return ""
+	}
+
+	result, err := parser.ExtractFrontmatterFromContent(string(content))
+	if err != nil {
+		addWorkflowRefreshLog.Printf("Failed to parse frontmatter in %s: %v", workflowPath, err)
+		return ""
+	}
+
+	source, ok := result.Frontmatter["source"].(string)
+	if !ok || strings.TrimSpace(source) == "" {
+		return ""
+	}
+
+	sourceSpec, err := parseSourceSpec(strings.TrimSpace(source))
+	if err != nil {
+		addWorkflowRefreshLog.Printf("Invalid source field in %s: %v", workflowPath, err)
+		return ""
+	}
+	return sourceSpec.Ref
+}
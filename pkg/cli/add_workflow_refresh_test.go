@@ -0,0 +1,98 @@
+//go:build !integration
+
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckWorkflowRefresh(t *testing.T) {
+	const oldSHA = "1111111111111111111111111111111111111111"
+	const newSHA = "2222222222222222222222222222222222222222"
+
+	dir := t.TempDir()
+	installed := filepath.Join(dir, "ci-doctor.md")
+	writeTestFile(t, installed, "---\non: push\nsource: owner/repo/workflows/ci-doctor.md@"+oldSHA+"\n---\n# CI Doctor\n")
+	noSource := filepath.Join(dir, "no-source.md")
+	writeTestFile(t, noSource, "---\non: push\n---\n# No Source\n")
+
+	tests := []struct {
+		name        string
+		file        string
+		version     string
+		resolvedSHA string
+		refresh     bool
+		reason      string
+	}{
+		{
+			name:        "pinned commit SHA is left untouched",
+			file:        installed,
+			version:     newSHA,
+			resolvedSHA: newSHA,
+			refresh:     false,
+			reason:      "pinned to 2222222",
+		},
+		{
+			name:        "pinned version tag is left untouched",
+			file:        installed,
+			version:     "v1.2.0",
+			resolvedSHA: newSHA,
+			refresh:     false,
+			reason:      "pinned to v1.2.0",
+		},
+		{
+			name:        "branch at recorded commit is up to date",
+			file:        installed,
+			version:     "main",
+			resolvedSHA: oldSHA,
+			refresh:     false,
+			reason:      "already up to date at 1111111",
+		},
+		{
+			name:        "branch moved since install",
+			file:        installed,
+			version:     "main",
+			resolvedSHA: newSHA,
+			refresh:     true,
+			reason:      "updating 1111111 → 2222222",
+		},
+		{
+			name:        "default branch moved since install",
+			file:        installed,
+			resolvedSHA: newSHA,
+			refresh:     true,
+			reason:      "updating 1111111 → 2222222",
+		},
+		{
+			name:        "workflow without source field",
+			file:        noSource,
+			version:     "main",
+			resolvedSHA: newSHA,
+			refresh:     true,
+			reason:      "no recorded source commit",
+		},
+		{
+			name:    "unresolved ref refreshes",
+			file:    installed,
+			version: "main",
+			refresh: true,
+			reason:  "could not resolve",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &WorkflowSpec{
+				RepoSpec:     RepoSpec{RepoSlug: "owner/repo", Version: tt.version},
+				WorkflowPath: "workflows/ci-doctor.md",
+				WorkflowName: "ci-doctor",
+			}
+			refresh, reason := checkWorkflowRefresh(tt.file, spec, tt.resolvedSHA)
+			assert.Equal(t, tt.refresh, refresh, "refresh decision mismatch")
+			assert.Contains(t, reason, tt.reason, "reason should explain the decision")
+		})
+	}
+}
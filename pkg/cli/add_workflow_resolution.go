@@ -46,6 +46,13 @@ type ResolvedWorkflows struct {
 // For remote workflows, content is fetched directly from GitHub without cloning.
 // Wildcards are only supported for local workflows (not remote repositories).
 func ResolveWorkflows(workflows []string, verbose bool) (*ResolvedWorkflows, error) {
+	return resolveWorkflows(workflows, remoteFetchOptions{LogLevel: fetchLogLevel(verbose, false)})
+}
+
+// resolveWorkflows is ResolveWorkflows with configurable fetch options: progress messages are
+// delivered to opts.OnEvent, and workflows larger than opts.MaxFileSize are refused
+func resolveWorkflows(workflows []string, opts remoteFetchOptions) (*ResolvedWorkflows, error) {
+	verbose := opts.LogLevel.showInfo()
 	resolutionLog.Printf("Resolving workflows: count=%d", len(workflows))
 
 	if len(workflows) == 0 {
@@ -68,7 +75,7 @@ func ResolveWorkflows(workflows []string, verbose bool) (*ResolvedWorkflows, err
 		}
 
 		// Wildcards are only supported for local workflows
-		if spec.IsWildcard && !isLocalWorkflowPath(spec.WorkflowPath) {
+		if spec.IsWildcard && !IsLocalWorkflowPath(spec.WorkflowPath) {
 			return nil, fmt.Errorf("wildcards are only supported for local workflows, not remote repositories: %s", workflow)
 		}
 
@@ -82,7 +89,7 @@ func ResolveWorkflows(workflows []string, verbose bool) (*ResolvedWorkflows, err
 		// We successfully determined the current repository, check all workflow specs
 		for _, spec := range parsedSpecs {
 			// Skip local workflow specs
-			if isLocalWorkflowPath(spec.WorkflowPath) {
+			if IsLocalWorkflowPath(spec.WorkflowPath) {
 				continue
 			}
 
@@ -115,11 +122,12 @@ func ResolveWorkflows(workflows []string, verbose bool) (*ResolvedWorkflows, err
 	resolvedWorkflows := make([]*ResolvedWorkflow, 0, len(parsedSpecs))
 	hasWorkflowDispatch := false
 
-	for _, spec := range parsedSpecs {
-		// Fetch workflow content - FetchWorkflowFromSource handles both local and remote
-		fetched, err := FetchWorkflowFromSource(spec, verbose)
-		if err != nil {
-			return nil, fmt.Errorf("workflow '%s' not found: %w", spec.String(), err)
+	// Fetch workflow content - fetchWorkflowsFromSource handles both local and remote,
+	// sharing ref resolutions and downloads across the workflows
+	for _, result := range fetchWorkflowsFromSource(parsedSpecs, opts) {
+		spec, fetched := result.Spec, result.Workflow
+		if result.Err != nil {
+			return nil, fmt.Errorf("workflow '%s' not found: %w", spec.String(), result.Err)
 		}
 
 		// Extract description from content
@@ -163,13 +171,13 @@ func expandLocalWildcardWorkflows(specs []*WorkflowSpec, verbose bool) ([]*Workf
 	expandedWorkflows := []*WorkflowSpec{}
 
 	for _, spec := range specs {
-		if spec.IsWildcard && isLocalWorkflowPath(spec.WorkflowPath) {
+		if spec.IsWildcard && IsLocalWorkflowPath(spec.WorkflowPath) {
 			resolutionLog.Printf("Expanding local wildcard: %s", spec.WorkflowPath)
 			if verbose {
 				fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Discovering local workflows matching %s...", spec.WorkflowPath)))
 			}
 
-			// Expand local wildcard (e.g., ./*.md or ./workflows/*.md)
+			// Expand local wildcard (e.g., ./*.md or ./workflows/*.md) or directory (e.g., ./workflows)
 			discovered, err := expandLocalWildcard(spec)
 			if err != nil {
 				return nil, fmt.Errorf("failed to expand wildcard %s: %w", spec.WorkflowPath, err)
@@ -225,14 +233,25 @@ func checkWorkflowHasDispatchFromContent(content string) bool {
 	}
 }
 
-// expandLocalWildcard expands a local wildcard path (e.g., ./*.md) into individual workflow specs
+// expandLocalWildcard expands a local wildcard path (e.g., ./*.md) or directory (e.g.,
+// ./workflows) into individual workflow specs. A directory expands to the agentic workflows
+// directly inside it.
 func expandLocalWildcard(spec *WorkflowSpec) ([]*WorkflowSpec, error) {
 	pattern := spec.WorkflowPath
 
-	// Use filepath.Glob to expand the pattern
-	matches, err := filepath.Glob(pattern)
+	var matches []string
+	var err error
+	if info, statErr := os.Stat(pattern); statErr == nil && info.IsDir() {
+		matches, err = discoverLocalWorkflows(pattern)
+	} else {
+		// Use filepath.Glob to expand the pattern
+		matches, err = filepath.Glob(pattern)
+		if err != nil {
+			err = fmt.Errorf("invalid wildcard pattern %s: %w", pattern, err)
+		}
+	}
 	if err != nil {
-		return nil, fmt.Errorf("invalid wildcard pattern %s: %w", pattern, err)
+		return nil, err
 	}
 
 	if len(matches) == 0 {
@@ -261,3 +280,88 @@ func expandLocalWildcard(spec *WorkflowSpec) ([]*WorkflowSpec, error) {
 
 	return result, nil
 }
+
+// discoverLocalWorkflows returns the paths of the agentic workflows directly in dir, sorted by
+// name. Markdown files without an 'on' trigger, such as shared components and documentation,
+// are left out. Paths keep the prefix of dir so they are still recognized as local.
+func discoverLocalWorkflows(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read local workflow directory %s: %w", dir, err)
+	}
+
+	prefix := strings.TrimRight(dir, `/\`) + string(filepath.Separator)
+	var workflows []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+			continue
+		}
+		workflowPath := prefix + entry.Name()
+		content, err := os.ReadFile(workflowPath)
+		if err != nil {
+			resolutionLog.Printf("Skipping %s: %v", workflowPath, err)
+			continue
+		}
+		if !isAgenticWorkflowContent(string(content)) {
+			continue
+		}
+		workflows = append(workflows, workflowPath)
+	}
+
+	resolutionLog.Printf("Found %d agentic workflows in %s", len(workflows), dir)
+	return workflows, nil
+}
+
+// expandScopedWorkflows limits the workflows being added to the scope directory of their
+// repository. Repo-only specs (owner/repo[@ref]) expand to every agentic workflow directly in
+// scope, and remote specs naming a workflow outside scope are rejected. Local workflows are
+// not affected.
+func expandScopedWorkflows(workflows []string, scope string, opts remoteFetchOptions) ([]string, error) {
+	dir, err := normalizeWorkflowScope(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	expanded := make([]string, 0, len(workflows))
+	for _, workflow := range workflows {
+		spec, err := parseWorkflowSpec(workflow)
+		if err != nil {
+			return nil, fmt.Errorf("invalid workflow specification '%s': %w", workflow, err)
+		}
+		if IsLocalWorkflowPath(spec.WorkflowPath) {
+			expanded = append(expanded, workflow)
+			continue
+		}
+		if spec.WorkflowPath != "" {
+			if !strings.HasPrefix(spec.WorkflowPath, dir+"/") {
+				return nil, fmt.Errorf("workflow '%s' is outside the scope %s", workflow, dir)
+			}
+			expanded = append(expanded, workflow)
+			continue
+		}
+
+		owner, repo, _ := strings.Cut(spec.RepoSlug, "/")
+		resolutionLog.Printf("Discovering workflows in %s under %s", spec.RepoSlug, dir)
+		if opts.LogLevel.showInfo() {
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Discovering workflows in %s under %s...", spec.RepoSlug, dir)))
+		}
+		discovered, err := listRemoteWorkflows(owner, repo, spec.Version, []string{dir}, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(discovered) == 0 {
+			return nil, fmt.Errorf("no agentic workflows found in %s under %s", spec.RepoSlug, dir)
+		}
+		if opts.LogLevel.showInfo() {
+			fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Found %d workflow(s)", len(discovered))))
+		}
+		for _, info := range discovered {
+			scoped := spec.RepoSlug + "/" + info.Path
+			if spec.Version != "" {
+				scoped += "@" + spec.Version
+			}
+			expanded = append(expanded, scoped)
+		}
+	}
+	return expanded, nil
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/github/gh-aw/pkg/constants"
 	"github.com/github/gh-aw/pkg/fileutil"
 	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
 	"github.com/github/gh-aw/pkg/workflow"
 )
 
@@ -30,12 +31,14 @@ func init() {
 	// Set the default version in the workflow package
 	// This allows workflow.NewCompiler() to auto-detect the version
 	workflow.SetDefaultVersion(version)
+	parser.SetDefaultUserAgent("gh-aw/" + version)
 }
 
 // SetVersionInfo sets the version information for the CLI and workflow package
 func SetVersionInfo(v string) {
 	version = v
 	workflow.SetDefaultVersion(v) // Keep workflow package in sync
+	parser.SetDefaultUserAgent("gh-aw/" + v)
 }
 
 // GetVersion returns the current version
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var compileAnnotationsLog = logger.New("cli:compile_annotations")
+
+// shouldEmitAnnotations reports whether GitHub Actions workflow commands should be
+// emitted for compilation failures, either because --annotations was passed or
+// because we are running inside a GitHub Actions job
+func shouldEmitAnnotations(config CompileConfig) bool {
+	return config.Annotations || os.Getenv("GITHUB_ACTIONS") == "true"
+}
+
+// printCompilationAnnotations prints one ::error (or ::warning) workflow command per error message
+// so that GitHub surfaces compilation failures inline on the pull request diff.
+// Annotations are written to stdout, where the Actions runner parses workflow commands.
+func printCompilationAnnotations(stats *CompilationStats) {
+	if stats == nil || len(stats.FailureDetails) == 0 {
+		return
+	}
+
+	for _, failure := range sortedFailureDetails(stats.FailureDetails) {
+		diagnostics := failure.Diagnostics
+		if diagnostics == nil {
+			diagnostics = parseCompileDiagnostics(failure.Path, failure.ErrorMessages)
+		}
+		if len(diagnostics) == 0 {
+			fmt.Println(formatErrorAnnotation(failure.Path, "compilation failed"))
+			continue
+		}
+		for _, d := range diagnostics {
+			fmt.Println(formatDiagnosticAnnotation(d))
+		}
+	}
+}
+
+// formatErrorAnnotation converts an error message into a workflow command.
+// If the message starts with a "path:line:col:" prefix the position is used, otherwise
+// the annotation is attached to the workflow file as a whole.
+func formatErrorAnnotation(workflowPath string, errMsg string) string {
+	return formatDiagnosticAnnotation(parseCompileDiagnostic(workflowPath, errMsg))
+}
+
+// formatDiagnosticAnnotation converts a parsed diagnostic into an ::error or ::warning
+// workflow command
+func formatDiagnosticAnnotation(d CompileDiagnostic) string {
+	if d.Line > 0 {
+		compileAnnotationsLog.Printf("Emitting annotation for %s at line %d, col %d", d.File, d.Line, d.Col)
+		return fmt.Sprintf("::%s file=%s,line=%d,col=%d::%s",
+			d.Severity, escapeAnnotationProperty(d.File), d.Line, d.Col, escapeAnnotationData(d.Message))
+	}
+
+	compileAnnotationsLog.Printf("Emitting annotation for %s without position", d.File)
+	return fmt.Sprintf("::%s file=%s::%s", d.Severity, escapeAnnotationProperty(d.File), escapeAnnotationData(d.Message))
+}
+
+// escapeAnnotationData escapes a workflow command message
+func escapeAnnotationData(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	s = strings.ReplaceAll(s, "\n", "%0A")
+	return s
+}
+
+// escapeAnnotationProperty escapes a workflow command property value
+func escapeAnnotationProperty(s string) string {
+	s = escapeAnnotationData(s)
+	s = strings.ReplaceAll(s, ":", "%3A")
+	s = strings.ReplaceAll(s, ",", "%2C")
+	return s
+}
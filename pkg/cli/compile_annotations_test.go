@@ -0,0 +1,88 @@
+//go:build !integration
+
+package cli
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatErrorAnnotation(t *testing.T) {
+	tests := []struct {
+		name         string
+		workflowPath string
+		errMsg       string
+		expected     string
+	}{
+		{
+			name:         "message with position",
+			workflowPath: ".github/workflows/test.md",
+			errMsg:       ".github/workflows/test.md:5:3: error: Unknown property: foo",
+			expected:     "::error file=.github/workflows/test.md,line=5,col=3::Unknown property: foo",
+		},
+		{
+			name:         "message with context lines",
+			workflowPath: ".github/workflows/test.md",
+			errMsg:       "test.md:2:1: error: bad value\n  2 | on: nope",
+			expected:     "::error file=test.md,line=2,col=1::bad value%0A  2 | on: nope",
+		},
+		{
+			name:         "message without position",
+			workflowPath: ".github/workflows/test.md",
+			errMsg:       "failed to parse frontmatter",
+			expected:     "::error file=.github/workflows/test.md::failed to parse frontmatter",
+		},
+		{
+			name:         "message with ANSI codes",
+			workflowPath: "test.md",
+			errMsg:       "\x1b[1mtest.md:1:1:\x1b[0m \x1b[31merror:\x1b[0m 100% broken",
+			expected:     "::error file=test.md,line=1,col=1::100%25 broken",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatErrorAnnotation(tt.workflowPath, tt.errMsg)
+			assert.Equal(t, tt.expected, got, "annotation should match")
+		})
+	}
+}
+
+func TestShouldEmitAnnotations(t *testing.T) {
+	t.Setenv("GITHUB_ACTIONS", "")
+	assert.False(t, shouldEmitAnnotations(CompileConfig{}), "should not emit outside Actions")
+	assert.True(t, shouldEmitAnnotations(CompileConfig{Annotations: true}), "should emit with --annotations")
+
+	t.Setenv("GITHUB_ACTIONS", "true")
+	assert.True(t, shouldEmitAnnotations(CompileConfig{}), "should emit when GITHUB_ACTIONS=true")
+}
+
+func TestPrintCompilationAnnotations(t *testing.T) {
+	stats := &CompilationStats{
+		Total:  2,
+		Errors: 2,
+		FailureDetails: []WorkflowFailure{
+			{Path: "b.md", ErrorCount: 1, ErrorMessages: []string{"b.md:3:1: error: second"}},
+			{Path: "a.md", ErrorCount: 1},
+		},
+	}
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	printCompilationAnnotations(stats)
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+
+	expected := "::error file=a.md::compilation failed\n" +
+		"::error file=b.md,line=3,col=1::second\n"
+	assert.Equal(t, expected, buf.String(), "annotations should be printed in path order")
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var compileChangedLog = logger.New("cli:compile_changed_workflows")
+
+// changedFilesSince returns the absolute paths of files changed in the working tree since
+// baseRef: committed and uncommitted changes to tracked files, and untracked files that are
+// not ignored.
+func changedFilesSince(gitRoot, baseRef string) ([]string, error) {
+	if err := exec.Command("git", "-C", gitRoot, "rev-parse", "--verify", "--quiet", baseRef+"^{commit}").Run(); err != nil {
+		return nil, fmt.Errorf("--changed-since: unknown git ref %q", baseRef)
+	}
+
+	diff, err := exec.Command("git", "-C", gitRoot, "diff", "--name-only", "-z", baseRef, "--").Output()
+	if err != nil {
+		return nil, fmt.Errorf("--changed-since: failed to list files changed since %s: %w", baseRef, err)
+	}
+	untracked, err := exec.Command("git", "-C", gitRoot, "ls-files", "--others", "--exclude-standard", "-z").Output()
+	if err != nil {
+		return nil, fmt.Errorf("--changed-since: failed to list untracked files: %w", err)
+	}
+
+	var changed []string
+	for _, name := range bytes.Split(append(diff, untracked...), []byte{0}) {
+		if len(name) == 0 {
+			continue
+		}
+		changed = append(changed, filepath.Join(gitRoot, filepath.FromSlash(string(name))))
+	}
+	compileChangedLog.Printf("Found %d files changed since %s", len(changed), baseRef)
+	return changed, nil
+}
+
+// selectChangedWorkflows filters mdFiles down to the workflows that changed since baseRef,
+// together with the workflows that import or include a changed file (directly or through
+// other shared files). The order of mdFiles is preserved.
+func selectChangedWorkflows(gitRoot, workflowsDir string, mdFiles []string, baseRef string) ([]string, error) {
+	changed, err := changedFilesSince(gitRoot, baseRef)
+	if err != nil {
+		return nil, err
+	}
+
+	graph, err := BuildWorkflowDependencyGraph(workflowsDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, cycle := range graph.Cycles() {
+		for i, file := range cycle {
+			if rel, err := filepath.Rel(gitRoot, file); err == nil {
+				cycle[i] = filepath.ToSlash(rel)
+			}
+		}
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("Import cycle: "+strings.Join(cycle, " -> ")))
+	}
+
+	selected := make(map[string]bool)
+	for _, file := range changed {
+		if strings.HasSuffix(file, ".lock.yml") {
+			continue
+		}
+		selected[file] = true
+		for _, affected := range graph.findAffectedTopLevelWorkflows(file) {
+			compileChangedLog.Printf("%s is affected by changed file %s", affected, file)
+			selected[affected] = true
+		}
+	}
+
+	var workflows []string
+	for _, file := range mdFiles {
+		if selected[filepath.Clean(file)] {
+			workflows = append(workflows, file)
+		}
+	}
+	compileChangedLog.Printf("Selected %d of %d workflows changed since %s", len(workflows), len(mdFiles), baseRef)
+	return workflows, nil
+}
@@ -0,0 +1,72 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectChangedWorkflows(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	gitRoot := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", gitRoot, "-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)...)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v: %s", args, output)
+	}
+	git("init", "-q", "-b", "main")
+
+	workflowsDir := filepath.Join(gitRoot, ".github", "workflows")
+	imported := filepath.Join(workflowsDir, "imported.md")
+	included := filepath.Join(workflowsDir, "included.md")
+	standalone := filepath.Join(workflowsDir, "standalone.md")
+	writeTestFile(t, imported, "---\non: push\nimports:\n  - shared/tools.md\n---\n# Imported\n")
+	writeTestFile(t, included, "---\non: push\n---\n# Included\n\n@include shared/nested.md\n")
+	writeTestFile(t, standalone, "---\non: push\n---\n# Standalone\n")
+	writeTestFile(t, filepath.Join(workflowsDir, "shared", "tools.md"), "# Tools\n")
+	writeTestFile(t, filepath.Join(workflowsDir, "shared", "nested.md"), "---\nimports:\n  - prompt.md\n---\n# Nested\n")
+	writeTestFile(t, filepath.Join(workflowsDir, "shared", "prompt.md"), "# Prompt\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "initial")
+
+	mdFiles := []string{imported, included, standalone}
+
+	selected, err := selectChangedWorkflows(gitRoot, workflowsDir, mdFiles, "HEAD")
+	require.NoError(t, err, "selection should succeed")
+	assert.Empty(t, selected, "nothing changed since HEAD")
+
+	// A changed import selects the workflow importing it
+	writeTestFile(t, filepath.Join(workflowsDir, "shared", "tools.md"), "# Tools v2\n")
+	selected, err = selectChangedWorkflows(gitRoot, workflowsDir, mdFiles, "HEAD")
+	require.NoError(t, err, "selection should succeed")
+	assert.Equal(t, []string{imported}, selected, "changed import should select its importer")
+	git("commit", "-q", "-am", "tools")
+
+	// A committed change to a file imported by an include selects the including workflow
+	writeTestFile(t, filepath.Join(workflowsDir, "shared", "prompt.md"), "# Prompt v2\n")
+	git("commit", "-q", "-am", "prompt")
+	selected, err = selectChangedWorkflows(gitRoot, workflowsDir, mdFiles, "HEAD~1")
+	require.NoError(t, err, "selection should succeed")
+	assert.Equal(t, []string{included}, selected, "nested change should select the including workflow")
+
+	// Changed and new workflows select themselves; both commits are covered by the base ref
+	writeTestFile(t, standalone, "---\non: push\n---\n# Standalone v2\n")
+	added := filepath.Join(workflowsDir, "added.md")
+	writeTestFile(t, added, "---\non: push\n---\n# Added\n")
+	selected, err = selectChangedWorkflows(gitRoot, workflowsDir, append([]string{added}, mdFiles...), "HEAD~2")
+	require.NoError(t, err, "selection should succeed")
+	assert.Equal(t, []string{added, imported, included, standalone}, selected, "selection should keep the order of the workflow files")
+
+	_, err = selectChangedWorkflows(gitRoot, workflowsDir, mdFiles, "does-not-exist")
+	require.Error(t, err, "unknown base ref should fail")
+	assert.Contains(t, err.Error(), "unknown git ref", "error should name the problem")
+}
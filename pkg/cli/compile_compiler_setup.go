@@ -95,6 +95,8 @@ func createAndConfigureCompiler(config CompileConfig) *workflow.Compiler {
 		workflow.WithVerbose(config.Verbose),
 		workflow.WithEngineOverride(config.EngineOverride),
 		workflow.WithFailFast(config.FailFast),
+		workflow.WithCheckMountSources(config.CheckMountSources),
+		workflow.WithAllowedMCPImages(config.AllowedMCPImages),
 	)
 	compileCompilerSetupLog.Print("Created compiler instance")
 
@@ -32,14 +32,23 @@ type CompileConfig struct {
 	ActionMode             string   // Action script inlining mode: inline, dev, or release
 	ActionTag              string   // Override action SHA or tag for actions/setup (overrides action-mode to release)
 	Stats                  bool     // Display statistics table sorted by file size
-	FailFast               bool     // Stop at first error instead of collecting all errors
+	FailFast               bool     // Stop at the first failed workflow and first validation error instead of collecting all errors
+	Annotations            bool     // Emit GitHub Actions error annotations for compilation failures
+	CheckMountSources      bool     // Warn (error in strict mode) when MCP bind mount sources do not exist on this machine
+	AllowedMCPImages       []string // Container images (or "/"-terminated prefixes) custom MCP servers may use; empty allows all
+	ChangedSince           string   // Only compile workflows changed (directly or through imports) since this git ref
+
+	// Air-gapped mode: imports from mirrored repositories are read from the local filesystem
+	RepoMirrors map[string]string // Local directories that replace GitHub for the given owner/repo slugs
+	Offline     bool              // Fail instead of using the network for repositories without a mirror
 }
 
 // WorkflowFailure represents a failed workflow with its error count
 type WorkflowFailure struct {
-	Path          string   // File path of the workflow
-	ErrorCount    int      // Number of errors in this workflow
-	ErrorMessages []string // Actual error messages to display to the user
+	Path          string              // File path of the workflow
+	ErrorCount    int                 // Number of errors in this workflow
+	ErrorMessages []string            // Actual error messages to display to the user
+	Diagnostics   []CompileDiagnostic // ErrorMessages parsed into file, position, severity, and message
 }
 
 // CompilationStats tracks the results of workflow compilation
@@ -49,6 +58,8 @@ type CompilationStats struct {
 	Warnings        int
 	FailedWorkflows []string          // Names of workflows that failed compilation (deprecated, use FailedWorkflowDetails)
 	FailureDetails  []WorkflowFailure // Detailed information about failed workflows
+	Skipped         int               // Workflows not compiled because --fail-fast stopped at an earlier failure
+	Unchanged       int               // Workflows not compiled because neither they nor their imports changed since --changed-since
 }
 
 // CompileValidationError represents a single validation error or warning
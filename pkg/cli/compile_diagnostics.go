@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/stringutil"
+)
+
+var compileDiagnosticsLog = logger.New("cli:compile_diagnostics")
+
+// errorPositionPattern matches the IDE-parseable "file:line:column: type: message" prefix
+// produced by console.FormatError
+var errorPositionPattern = regexp.MustCompile(`^(.+?):(\d+):(\d+):\s*(?:(error|warning):\s*)?(.*)$`)
+
+// CompileDiagnostic is a compilation error message split into its position and text, so
+// editors and other tools can consume it without parsing the message
+type CompileDiagnostic struct {
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"` // 1-based; 0 when the message has no position
+	Col      int    `json:"col,omitempty"`  // 1-based; 0 when the message has no position
+	Severity string `json:"severity"`       // "error" or "warning"
+	Message  string `json:"message"`        // Message without the position prefix, including context lines
+}
+
+// parseCompileDiagnostic parses an error message with an optional "file:line:col:" prefix.
+// Only the first line carries the position; the remaining lines are kept in Message as
+// context. Messages without a prefix are attributed to workflowPath as errors.
+func parseCompileDiagnostic(workflowPath string, errMsg string) CompileDiagnostic {
+	errMsg = strings.TrimSpace(stringutil.StripANSI(errMsg))
+	firstLine, rest, _ := strings.Cut(errMsg, "\n")
+
+	matches := errorPositionPattern.FindStringSubmatch(firstLine)
+	if matches == nil {
+		return CompileDiagnostic{File: workflowPath, Severity: "error", Message: errMsg}
+	}
+	line, lineErr := strconv.Atoi(matches[2])
+	col, colErr := strconv.Atoi(matches[3])
+	if lineErr != nil || colErr != nil {
+		compileDiagnosticsLog.Printf("Position out of range in %q", firstLine)
+		return CompileDiagnostic{File: workflowPath, Severity: "error", Message: errMsg}
+	}
+
+	severity := matches[4]
+	if severity == "" {
+		severity = "error"
+	}
+	message := matches[5]
+	if rest != "" {
+		message += "\n" + rest
+	}
+	return CompileDiagnostic{File: matches[1], Line: line, Col: col, Severity: severity, Message: message}
+}
+
+// parseCompileDiagnostics parses each error message of a failed workflow
+func parseCompileDiagnostics(workflowPath string, errorMessages []string) []CompileDiagnostic {
+	if len(errorMessages) == 0 {
+		return nil
+	}
+	diagnostics := make([]CompileDiagnostic, 0, len(errorMessages))
+	for _, errMsg := range errorMessages {
+		diagnostics = append(diagnostics, parseCompileDiagnostic(workflowPath, errMsg))
+	}
+	return diagnostics
+}
@@ -0,0 +1,65 @@
+//go:build !integration
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCompileDiagnostic(t *testing.T) {
+	tests := []struct {
+		name     string
+		errMsg   string
+		expected CompileDiagnostic
+	}{
+		{
+			name:     "error with position",
+			errMsg:   "test1.md:5:1: error: Invalid field",
+			expected: CompileDiagnostic{File: "test1.md", Line: 5, Col: 1, Severity: "error", Message: "Invalid field"},
+		},
+		{
+			name:     "warning with position",
+			errMsg:   ".github/workflows/a.md:12:7: warning: deprecated field",
+			expected: CompileDiagnostic{File: ".github/workflows/a.md", Line: 12, Col: 7, Severity: "warning", Message: "deprecated field"},
+		},
+		{
+			name:     "position without severity",
+			errMsg:   "shared/tools.md:3:2: unknown tool",
+			expected: CompileDiagnostic{File: "shared/tools.md", Line: 3, Col: 2, Severity: "error", Message: "unknown tool"},
+		},
+		{
+			name:     "context lines are kept",
+			errMsg:   "test.md:2:1: error: bad value\n  2 | on: nope",
+			expected: CompileDiagnostic{File: "test.md", Line: 2, Col: 1, Severity: "error", Message: "bad value\n  2 | on: nope"},
+		},
+		{
+			name:     "ANSI codes are stripped",
+			errMsg:   "\x1b[1mtest.md:1:4:\x1b[0m \x1b[31merror:\x1b[0m broken",
+			expected: CompileDiagnostic{File: "test.md", Line: 1, Col: 4, Severity: "error", Message: "broken"},
+		},
+		{
+			name:     "message without position",
+			errMsg:   "failed to parse frontmatter: yaml: line 3: mapping values are not allowed",
+			expected: CompileDiagnostic{File: ".github/workflows/test.md", Severity: "error", Message: "failed to parse frontmatter: yaml: line 3: mapping values are not allowed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseCompileDiagnostic(".github/workflows/test.md", tt.errMsg)
+			assert.Equal(t, tt.expected, got, "diagnostic should match")
+		})
+	}
+}
+
+func TestTrackWorkflowFailureDiagnostics(t *testing.T) {
+	stats := &CompilationStats{}
+	trackWorkflowFailure(stats, "test1.md", 2, []string{"test1.md:5:1: error: Invalid field", "workflow not found"})
+
+	assert.Equal(t, []CompileDiagnostic{
+		{File: "test1.md", Line: 5, Col: 1, Severity: "error", Message: "Invalid field"},
+		{File: "test1.md", Severity: "error", Message: "workflow not found"},
+	}, stats.FailureDetails[0].Diagnostics, "diagnostics should be parsed from the error messages")
+}
@@ -0,0 +1,37 @@
+//go:build !integration
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileSpecificFiles_FailFast(t *testing.T) {
+	t.Chdir(t.TempDir())
+	files := []string{"missing-one", "missing-two", "missing-three"}
+
+	t.Run("default compiles every workflow", func(t *testing.T) {
+		stats := &CompilationStats{}
+		var results []ValidationResult
+		_, err := compileSpecificFiles(workflow.NewCompiler(), CompileConfig{MarkdownFiles: files, NoEmit: true}, stats, &results)
+		require.Error(t, err, "compilation should fail")
+		assert.Equal(t, 3, stats.Total, "every workflow should be attempted")
+		assert.Equal(t, 3, stats.Errors, "every failure should be collected")
+		assert.Zero(t, stats.Skipped, "no workflow should be skipped")
+	})
+
+	t.Run("fail-fast stops at the first failed workflow", func(t *testing.T) {
+		stats := &CompilationStats{}
+		var results []ValidationResult
+		_, err := compileSpecificFiles(workflow.NewCompiler(), CompileConfig{MarkdownFiles: files, NoEmit: true, FailFast: true}, stats, &results)
+		require.Error(t, err, "compilation should fail")
+		assert.Equal(t, 1, stats.Total, "only the first workflow should be attempted")
+		assert.Equal(t, 1, stats.Errors, "the first failure should be reported")
+		assert.Equal(t, 2, stats.Skipped, "remaining workflows should be counted as not compiled")
+		assert.Len(t, results, 1, "only the failed workflow should have a result")
+	})
+}
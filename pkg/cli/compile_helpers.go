@@ -38,6 +38,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/github/gh-aw/pkg/stringutil"
 
@@ -353,17 +354,27 @@ func trackWorkflowFailure(stats *CompilationStats, workflowPath string, errorCou
 		Path:          workflowPath,
 		ErrorCount:    errorCount,
 		ErrorMessages: errorMessages,
+		Diagnostics:   parseCompileDiagnostics(workflowPath, errorMessages),
 	})
 }
 
 // printCompilationSummary prints a summary of the compilation results
 func printCompilationSummary(stats *CompilationStats) {
 	if stats.Total == 0 {
+		if stats.Unchanged > 0 {
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("No workflows changed, %d unchanged workflow(s) not compiled", stats.Unchanged)))
+		}
 		return
 	}
 
 	summary := fmt.Sprintf("Compiled %d workflow(s): %d error(s), %d warning(s)",
 		stats.Total, stats.Errors, stats.Warnings)
+	if stats.Skipped > 0 {
+		summary += fmt.Sprintf(" (stopped at the first failure, %d workflow(s) not compiled)", stats.Skipped)
+	}
+	if stats.Unchanged > 0 {
+		summary += fmt.Sprintf(" (%d unchanged workflow(s) not compiled)", stats.Unchanged)
+	}
 
 	// Use different formatting based on whether there were errors
 	if stats.Errors > 0 {
@@ -371,15 +382,17 @@ func printCompilationSummary(stats *CompilationStats) {
 
 		// Show agent-friendly list of failed workflow IDs first
 		if len(stats.FailureDetails) > 0 {
+			failures := sortedFailureDetails(stats.FailureDetails)
+
 			fmt.Fprintln(os.Stderr)
 			fmt.Fprintln(os.Stderr, console.FormatErrorMessage("Failed workflows:"))
-			for _, failure := range stats.FailureDetails {
+			for _, failure := range failures {
 				fmt.Fprintf(os.Stderr, "  ✗ %s\n", filepath.Base(failure.Path))
 			}
 			fmt.Fprintln(os.Stderr)
 
 			// Display the actual error messages for each failed workflow
-			for _, failure := range stats.FailureDetails {
+			for _, failure := range failures {
 				for _, errMsg := range failure.ErrorMessages {
 					fmt.Fprintln(os.Stderr, errMsg)
 				}
@@ -388,7 +401,10 @@ func printCompilationSummary(stats *CompilationStats) {
 			// Fallback for backward compatibility if FailureDetails is not populated
 			fmt.Fprintln(os.Stderr)
 			fmt.Fprintln(os.Stderr, console.FormatErrorMessage("Failed workflows:"))
-			for _, workflow := range stats.FailedWorkflows {
+			failedWorkflows := make([]string, len(stats.FailedWorkflows))
+			copy(failedWorkflows, stats.FailedWorkflows)
+			sort.Strings(failedWorkflows)
+			for _, workflow := range failedWorkflows {
 				fmt.Fprintf(os.Stderr, "  ✗ %s\n", workflow)
 			}
 			fmt.Fprintln(os.Stderr)
@@ -399,3 +415,14 @@ func printCompilationSummary(stats *CompilationStats) {
 		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(summary))
 	}
 }
+
+// sortedFailureDetails returns a copy of failures sorted by path so that the
+// summary output is stable across runs regardless of compilation order
+func sortedFailureDetails(failures []WorkflowFailure) []WorkflowFailure {
+	sorted := make([]WorkflowFailure, len(failures))
+	copy(sorted, failures)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Path < sorted[j].Path
+	})
+	return sorted
+}
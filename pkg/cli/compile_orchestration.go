@@ -85,6 +85,10 @@ func compileSpecificFiles(
 				Message: err.Error(),
 			})
 			*validationResults = append(*validationResults, result)
+			if config.FailFast {
+				stopAtFirstFailure(stats, len(config.MarkdownFiles))
+				break
+			}
 			continue
 		}
 		compileOrchestrationLog.Printf("Resolved to: %s", resolvedFile)
@@ -126,6 +130,10 @@ func compileSpecificFiles(
 		}
 
 		*validationResults = append(*validationResults, fileResult.validationResult)
+		if !fileResult.success && config.FailFast {
+			stopAtFirstFailure(stats, len(config.MarkdownFiles))
+			break
+		}
 	}
 
 	// Run batch actionlint on all collected lock files
@@ -233,6 +241,20 @@ func compileAllFilesInDirectory(
 		purgeData = collectPurgeData(workflowsDir, mdFiles, config.Verbose)
 	}
 
+	// Only compile the workflows affected by changes since the base ref.
+	// Purge data is collected first so unchanged lock files are not considered orphaned.
+	if config.ChangedSince != "" {
+		changedFiles, err := selectChangedWorkflows(gitRoot, workflowsDir, mdFiles, config.ChangedSince)
+		if err != nil {
+			return nil, err
+		}
+		stats.Unchanged = len(mdFiles) - len(changedFiles)
+		if config.Verbose {
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Compiling %d of %d workflow files changed since %s", len(changedFiles), len(mdFiles), config.ChangedSince)))
+		}
+		mdFiles = changedFiles
+	}
+
 	// Enable validation automatically when force-refresh-action-pins is used
 	// to verify all resolved action SHAs are valid
 	shouldValidate := config.Validate || config.ForceRefreshActionPins
@@ -284,6 +306,10 @@ func compileAllFilesInDirectory(
 		}
 
 		*validationResults = append(*validationResults, fileResult.validationResult)
+		if !fileResult.success && config.FailFast {
+			stopAtFirstFailure(stats, len(mdFiles))
+			break
+		}
 	}
 
 	// Run batch actionlint
@@ -346,6 +372,12 @@ func compileAllFilesInDirectory(
 	return workflowDataList, nil
 }
 
+// stopAtFirstFailure records the workflows left uncompiled when --fail-fast stops at a failure
+func stopAtFirstFailure(stats *CompilationStats, total int) {
+	stats.Skipped = total - stats.Total
+	compileOrchestrationLog.Printf("Stopping at first failed workflow, %d workflow(s) not compiled", stats.Skipped)
+}
+
 // purgeTrackingData holds data needed for purge operations
 type purgeTrackingData struct {
 	existingLockFiles    []string
@@ -409,6 +441,14 @@ func runPostProcessing(
 	// Update .gitattributes (errors are non-fatal)
 	_ = updateGitAttributes(successCount, actionCache, config.Verbose)
 
+	// Dependabot manifests and the maintenance workflow are derived from every workflow,
+	// so they are left untouched when only the changed workflows were compiled
+	if config.ChangedSince != "" {
+		compileOrchestrationLog.Print("Skipping dependabot and maintenance workflow generation for --changed-since")
+		_ = saveActionCache(actionCache, config.Verbose)
+		return nil
+	}
+
 	// Generate Dependabot manifests if requested
 	if config.Dependabot && !config.NoEmit {
 		gitRoot, err := findGitRoot()
@@ -504,6 +544,11 @@ func outputResults(
 		formatCompilationSummary(stats)
 	}
 
+	// Emit GitHub Actions annotations alongside the human summary
+	if !config.JSONOutput && shouldEmitAnnotations(config) {
+		printCompilationAnnotations(stats)
+	}
+
 	// Display actionlint summary if enabled
 	if config.Actionlint && !config.NoEmit && !config.JSONOutput {
 		formatActionlintOutput()
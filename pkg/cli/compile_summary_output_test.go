@@ -54,6 +54,27 @@ func TestPrintCompilationSummaryWithFailedWorkflows(t *testing.T) {
 			},
 			notExpectedInOutput: []string{},
 		},
+		{
+			name: "fail-fast stop reports skipped workflows",
+			stats: &CompilationStats{
+				Total:   2,
+				Errors:  1,
+				Skipped: 3,
+				FailureDetails: []WorkflowFailure{
+					{
+						Path:          ".github/workflows/broken.md",
+						ErrorCount:    1,
+						ErrorMessages: []string{"broken.md:2:1: error: Invalid field"},
+					},
+				},
+			},
+			expectedInOutput: []string{
+				"Compiled 2 workflow(s): 1 error(s), 0 warning(s) (stopped at the first failure, 3 workflow(s) not compiled)",
+				"✗ broken.md",
+				"broken.md:2:1: error: Invalid field",
+			},
+			notExpectedInOutput: []string{},
+		},
 		{
 			name: "single failed workflow with FailureDetails",
 			stats: &CompilationStats{
@@ -142,3 +163,77 @@ func TestPrintCompilationSummaryWithFailedWorkflows(t *testing.T) {
 		})
 	}
 }
+
+// TestPrintCompilationSummarySortsFailures tests that failed workflows are printed
+// in path order regardless of the order in which they were recorded
+func TestPrintCompilationSummarySortsFailures(t *testing.T) {
+	tests := []struct {
+		name          string
+		stats         *CompilationStats
+		expectedOrder []string
+	}{
+		{
+			name: "FailureDetails are sorted by path",
+			stats: &CompilationStats{
+				Total:  3,
+				Errors: 3,
+				FailureDetails: []WorkflowFailure{
+					{Path: ".github/workflows/zeta.md", ErrorCount: 1, ErrorMessages: []string{"zeta.md:1:1: error: zeta"}},
+					{Path: ".github/workflows/alpha.md", ErrorCount: 1, ErrorMessages: []string{"alpha.md:1:1: error: alpha"}},
+					{Path: ".github/workflows/mid.md", ErrorCount: 1, ErrorMessages: []string{"mid.md:1:1: error: mid"}},
+				},
+			},
+			expectedOrder: []string{
+				"✗ alpha.md", "✗ mid.md", "✗ zeta.md",
+				"alpha.md:1:1: error: alpha", "mid.md:1:1: error: mid", "zeta.md:1:1: error: zeta",
+			},
+		},
+		{
+			name: "FailedWorkflows fallback is sorted",
+			stats: &CompilationStats{
+				Total:           2,
+				Errors:          2,
+				FailedWorkflows: []string{"b.md", "a.md"},
+			},
+			expectedOrder: []string{"✗ a.md", "✗ b.md"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := append([]WorkflowFailure(nil), tt.stats.FailureDetails...)
+
+			oldStderr := os.Stderr
+			r, w, _ := os.Pipe()
+			os.Stderr = w
+
+			printCompilationSummary(tt.stats)
+
+			w.Close()
+			os.Stderr = oldStderr
+
+			var buf bytes.Buffer
+			buf.ReadFrom(r)
+			output := buf.String()
+
+			lastIndex := -1
+			for _, expected := range tt.expectedOrder {
+				idx := strings.Index(output, expected)
+				if idx == -1 {
+					t.Fatalf("Expected output to contain %q.\nFull output:\n%s", expected, output)
+				}
+				if idx < lastIndex {
+					t.Errorf("Expected %q to appear after previous entries.\nFull output:\n%s", expected, output)
+				}
+				lastIndex = idx
+			}
+
+			// The caller's stats must not be reordered
+			for i := range original {
+				if original[i].Path != tt.stats.FailureDetails[i].Path {
+					t.Errorf("printCompilationSummary mutated FailureDetails order")
+				}
+			}
+		})
+	}
+}
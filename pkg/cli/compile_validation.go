@@ -212,6 +212,18 @@ func validateCompileConfig(config CompileConfig) error {
 		return errors.New("--purge flag can only be used when compiling all markdown files (no specific files specified)")
 	}
 
+	// Validate changed-since flag usage
+	if config.ChangedSince != "" {
+		if len(config.MarkdownFiles) > 0 {
+			compileValidationLog.Print("Config validation failed: changed-since flag with specific files")
+			return errors.New("--changed-since flag cannot be used with specific workflow files")
+		}
+		if config.Watch {
+			compileValidationLog.Print("Config validation failed: changed-since flag with watch mode")
+			return errors.New("--changed-since flag cannot be used with --watch")
+		}
+	}
+
 	// Validate workflow directory path
 	if config.WorkflowDir != "" && filepath.IsAbs(config.WorkflowDir) {
 		compileValidationLog.Printf("Config validation failed: absolute path in workflowDir: %s", config.WorkflowDir)
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/github/gh-aw/pkg/logger"
@@ -39,6 +41,21 @@ func NewDependencyGraph(workflowsDir string) *DependencyGraph {
 	}
 }
 
+// BuildWorkflowDependencyGraph scans every workflow under workflowsDir, parses its imports:
+// frontmatter and @include directives, and returns the graph of which files depend on which.
+// Import cycles do not fail the build; they are reported by Cycles.
+func BuildWorkflowDependencyGraph(workflowsDir string) (*DependencyGraph, error) {
+	graph := NewDependencyGraph(workflowsDir)
+	// Imports are parsed directly from the files, so no compiler is needed
+	if err := graph.BuildGraph(nil); err != nil {
+		return nil, err
+	}
+	for _, cycle := range graph.Cycles() {
+		depGraphLog.Printf("Import cycle: %s", strings.Join(cycle, " -> "))
+	}
+	return graph, nil
+}
+
 // isTopLevelWorkflow determines if a workflow is a top-level workflow (dominator)
 // Top-level workflows are those directly in the workflows directory, not in subdirectories
 func (g *DependencyGraph) isTopLevelWorkflow(absPath string) bool {
@@ -85,6 +102,21 @@ func (g *DependencyGraph) BuildGraph(compiler *workflow.Compiler) error {
 		}
 	}
 
+	// Shared markdown files outside the workflows directory (e.g. .github/agents/) can import
+	// further files, so follow them until every imported markdown file is a node
+	for added := true; added; {
+		added = false
+		for importPath := range g.reverseImports {
+			if _, exists := g.nodes[importPath]; exists || !strings.HasSuffix(importPath, ".md") {
+				continue
+			}
+			if err := g.addWorkflow(importPath, compiler); err != nil {
+				depGraphLog.Printf("Warning: failed to add imported file %s to graph: %v", importPath, err)
+			}
+			added = true
+		}
+	}
+
 	depGraphLog.Printf("Dependency graph built: %d nodes, %d reverse import entries", len(g.nodes), len(g.reverseImports))
 	return nil
 }
@@ -151,6 +183,7 @@ func (g *DependencyGraph) extractImportsFromFile(workflowPath string) ([]string,
 	}
 
 	imports := g.extractImportsFromFrontmatter(workflowPath, result.Frontmatter)
+	imports = append(imports, g.extractIncludesFromMarkdown(workflowPath, result.Markdown)...)
 	depGraphLog.Printf("Extracted %d imports from %s", len(imports), workflowPath)
 	return imports, nil
 }
@@ -201,11 +234,36 @@ func (g *DependencyGraph) extractImportsFromFrontmatter(workflowPath string, fro
 				imports = append(imports, resolvedPath)
 			}
 		}
+	case map[string]any:
+		// Import aliases: the workflow imports the local paths (keys)
+		for _, imp := range parseFrontmatterImports(v) {
+			if resolvedPath := g.resolveImportPath(imp.localPath, workflowDir); resolvedPath != "" {
+				imports = append(imports, resolvedPath)
+			}
+		}
 	}
 
 	return imports
 }
 
+// extractIncludesFromMarkdown extracts the local files included by @include and {{#import}}
+// directives in the markdown body. Remote workflowspec includes are skipped since they do not
+// change with the repository.
+func (g *DependencyGraph) extractIncludesFromMarkdown(workflowPath string, markdown string) []string {
+	var includes []string
+	workflowDir := filepath.Dir(workflowPath)
+	for line := range strings.SplitSeq(markdown, "\n") {
+		directive := parser.ParseImportDirective(line)
+		if directive == nil || IsWorkflowSpecFormat(directive.Path) {
+			continue
+		}
+		if resolvedPath := g.resolveImportPath(directive.Path, workflowDir); resolvedPath != "" {
+			includes = append(includes, resolvedPath)
+		}
+	}
+	return includes
+}
+
 // resolveImportPath resolves an import path to an absolute file path
 func (g *DependencyGraph) resolveImportPath(importPath string, baseDir string) string {
 	// Handle section references (file.md#Section) - strip the section part
@@ -327,6 +385,100 @@ func (g *DependencyGraph) findAffectedTopLevelWorkflows(filePath string) []strin
 	return topLevelWorkflows
 }
 
+// Dependents returns every file that depends on filePath, directly or through other shared
+// files, sorted by path. These are the workflows and shared files that break when filePath
+// is deleted.
+func (g *DependencyGraph) Dependents(filePath string) []string {
+	visited := map[string]bool{filePath: true}
+	var dependents []string
+
+	queue := []string{filePath}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, importer := range g.reverseImports[current] {
+			if visited[importer] {
+				continue
+			}
+			visited[importer] = true
+			dependents = append(dependents, importer)
+			queue = append(queue, importer)
+		}
+	}
+
+	sort.Strings(dependents)
+	return dependents
+}
+
+// Cycles returns the import cycles in the graph. Each cycle lists the files in import order,
+// starting from its smallest path and ending with that path again, e.g. [a.md b.md a.md].
+// Cycles are sorted and reported once each.
+func (g *DependencyGraph) Cycles() [][]string {
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	state := make(map[string]int)
+	seen := make(map[string]bool)
+	var cycles [][]string
+	var stack []string
+
+	var visit func(path string)
+	visit = func(path string) {
+		state[path] = inProgress
+		stack = append(stack, path)
+		if node := g.nodes[path]; node != nil {
+			for _, importPath := range node.Imports {
+				switch state[importPath] {
+				case unvisited:
+					visit(importPath)
+				case inProgress:
+					start := slices.Index(stack, importPath)
+					cycle := rotateCycle(stack[start:])
+					if key := strings.Join(cycle, "\x00"); !seen[key] {
+						seen[key] = true
+						cycles = append(cycles, cycle)
+					}
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[path] = done
+	}
+
+	paths := make([]string, 0, len(g.nodes))
+	for path := range g.nodes {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if state[path] == unvisited {
+			visit(path)
+		}
+	}
+
+	sort.Slice(cycles, func(i, j int) bool {
+		return strings.Join(cycles[i], "\x00") < strings.Join(cycles[j], "\x00")
+	})
+	return cycles
+}
+
+// rotateCycle rotates the files of a cycle to start at the smallest path and closes it by
+// repeating that path at the end
+func rotateCycle(files []string) []string {
+	start := 0
+	for i, file := range files {
+		if file < files[start] {
+			start = i
+		}
+	}
+	cycle := make([]string, 0, len(files)+1)
+	cycle = append(cycle, files[start:]...)
+	cycle = append(cycle, files[:start]...)
+	return append(cycle, files[start])
+}
+
 // getAllTopLevelWorkflows returns all top-level workflows in the graph
 func (g *DependencyGraph) getAllTopLevelWorkflows() []string {
 	var topLevel []string
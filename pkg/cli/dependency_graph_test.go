@@ -931,3 +931,61 @@ tools:
 		})
 	}
 }
+
+func TestBuildWorkflowDependencyGraph_DependentsAndCycles(t *testing.T) {
+	tmpDir := t.TempDir()
+	githubDir := filepath.Join(tmpDir, ".github")
+	workflowsDir := filepath.Join(githubDir, "workflows")
+	sharedDir := filepath.Join(workflowsDir, "shared")
+	agentsDir := filepath.Join(githubDir, "agents")
+	for _, dir := range []string{sharedDir, agentsDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files := map[string]string{
+		filepath.Join(workflowsDir, "main.md"):      "---\non: push\nimports:\n  - shared/a.md\n---\n# Main\n",
+		filepath.Join(workflowsDir, "review.md"):    "---\non: push\n---\n# Review\n\n{{#import ../agents/reviewer.md}}\n",
+		filepath.Join(sharedDir, "a.md"):            "---\nimports:\n  - b.md\n---\n# A\n",
+		filepath.Join(sharedDir, "b.md"):            "---\nimports:\n  - a.md\n---\n# B\n",
+		filepath.Join(agentsDir, "reviewer.md"):     "# Reviewer\n\n@include ../workflows/shared/tone.md\n",
+		filepath.Join(sharedDir, "tone.md"):         "# Tone\n",
+		filepath.Join(workflowsDir, "unrelated.md"): "---\non: push\n---\n# Unrelated\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	graph, err := BuildWorkflowDependencyGraph(workflowsDir)
+	if err != nil {
+		t.Fatalf("BuildWorkflowDependencyGraph() error = %v", err)
+	}
+
+	// Includes are followed through shared files outside the workflows directory
+	tone := filepath.Join(sharedDir, "tone.md")
+	wantDependents := []string{filepath.Join(agentsDir, "reviewer.md"), filepath.Join(workflowsDir, "review.md")}
+	if got := graph.Dependents(tone); fmt.Sprint(got) != fmt.Sprint(wantDependents) {
+		t.Errorf("Dependents(tone.md) = %v, want %v", got, wantDependents)
+	}
+	if got := graph.GetAffectedWorkflows(tone); fmt.Sprint(got) != fmt.Sprint([]string{filepath.Join(workflowsDir, "review.md")}) {
+		t.Errorf("GetAffectedWorkflows(tone.md) = %v, want [review.md]", got)
+	}
+	if got := graph.Dependents(filepath.Join(workflowsDir, "unrelated.md")); len(got) != 0 {
+		t.Errorf("Dependents(unrelated.md) = %v, want none", got)
+	}
+
+	// Each cycle is reported once, starting from its smallest path
+	a := filepath.Join(sharedDir, "a.md")
+	b := filepath.Join(sharedDir, "b.md")
+	wantCycles := [][]string{{a, b, a}}
+	if got := graph.Cycles(); fmt.Sprint(got) != fmt.Sprint(wantCycles) {
+		t.Errorf("Cycles() = %v, want %v", got, wantCycles)
+	}
+	wantDependents = []string{filepath.Join(workflowsDir, "main.md"), b}
+	if got := graph.Dependents(a); fmt.Sprint(got) != fmt.Sprint(wantDependents) {
+		t.Errorf("Dependents(a.md) = %v, want %v", got, wantDependents)
+	}
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/github/gh-aw/pkg/console"
+)
+
+// FetchEventLevel is the severity of a FetchEvent
+type FetchEventLevel string
+
+const (
+	FetchEventInfo    FetchEventLevel = "info"
+	FetchEventSuccess FetchEventLevel = "success"
+	FetchEventWarning FetchEventLevel = "warning"
+	FetchEventVerbose FetchEventLevel = "verbose"
+)
+
+// FetchEvent is a progress message produced while fetching workflows, includes, and imports
+type FetchEvent struct {
+	Level   FetchEventLevel // Severity of the message
+	Message string          // Human-readable message without console formatting
+	Path    string          // File the message is about (local target or remote path), if any
+}
+
+// FetchEventHandler receives fetch progress events. Set it as AddOptions.OnFetchEvent to
+// capture the messages that are printed to stderr by default.
+type FetchEventHandler func(FetchEvent)
+
+// emit delivers a fetch progress message to the handler, or prints it to stderr when the
+// handler is nil
+func (h FetchEventHandler) emit(level FetchEventLevel, message, path string) {
+	event := FetchEvent{Level: level, Message: message, Path: path}
+	if h != nil {
+		h(event)
+		return
+	}
+	fmt.Fprintln(os.Stderr, formatFetchEvent(event))
+}
+
+// formatFetchEvent formats an event with the console style matching its level
+func formatFetchEvent(event FetchEvent) string {
+	switch event.Level {
+	case FetchEventSuccess:
+		return console.FormatSuccessMessage(event.Message)
+	case FetchEventWarning:
+		return console.FormatWarningMessage(event.Message)
+	case FetchEventVerbose:
+		return console.FormatVerboseMessage(event.Message)
+	default:
+		return console.FormatInfoMessage(event.Message)
+	}
+}
+
+// FetchLogLevel selects which fetch progress messages are emitted
+type FetchLogLevel int
+
+const (
+	FetchLogDefault FetchLogLevel = iota // Only errors are returned; no progress messages
+	FetchLogQuiet                        // Warnings only, for scripts that only want to hear about problems
+	FetchLogVerbose                      // Info, success, and warning messages
+)
+
+// fetchLogLevel returns the log level for the verbose and quiet flags. Verbose wins when both
+// are set.
+func fetchLogLevel(verbose, quiet bool) FetchLogLevel {
+	switch {
+	case verbose:
+		return FetchLogVerbose
+	case quiet:
+		return FetchLogQuiet
+	default:
+		return FetchLogDefault
+	}
+}
+
+// showInfo reports whether info and success messages are emitted at this level
+func (l FetchLogLevel) showInfo() bool {
+	return l == FetchLogVerbose
+}
+
+// showWarnings reports whether warnings are emitted at this level
+func (l FetchLogLevel) showWarnings() bool {
+	return l == FetchLogVerbose || l == FetchLogQuiet
+}
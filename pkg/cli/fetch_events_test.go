@@ -0,0 +1,93 @@
+//go:build !integration
+
+package cli
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchEventHandlerCapturesEvents(t *testing.T) {
+	workflowPath := filepath.Join(t.TempDir(), "local.md")
+	writeTestFile(t, workflowPath, "---\non: push\n---\n# Local\n")
+
+	var events []FetchEvent
+	handler := func(event FetchEvent) {
+		events = append(events, event)
+	}
+
+	spec := &WorkflowSpec{WorkflowPath: workflowPath}
+	_, err := fetchWorkflowFromSource(spec, remoteFetchOptions{LogLevel: FetchLogVerbose, OnEvent: handler})
+	require.NoError(t, err, "local fetch should succeed")
+
+	require.Len(t, events, 1, "verbose local fetch should emit one event")
+	assert.Equal(t, FetchEventInfo, events[0].Level, "event level should be info")
+	assert.Equal(t, "Reading local workflow: "+workflowPath, events[0].Message, "event message should be unformatted")
+	assert.Equal(t, workflowPath, events[0].Path, "event should carry the workflow path")
+
+	events = nil
+	_, err = fetchWorkflowFromSource(spec, remoteFetchOptions{OnEvent: handler})
+	require.NoError(t, err, "local fetch should succeed")
+	assert.Empty(t, events, "non-verbose fetch should not emit info events")
+}
+
+func TestFetchEventHandlerIsPerFetch(t *testing.T) {
+	workflowPath := filepath.Join(t.TempDir(), "local.md")
+	writeTestFile(t, workflowPath, "---\non: push\n---\n# Local\n")
+	spec := &WorkflowSpec{WorkflowPath: workflowPath}
+
+	var wg sync.WaitGroup
+	counts := make([]int, 4)
+	for i := range counts {
+		wg.Go(func() {
+			for range 10 {
+				_, err := fetchWorkflowFromSource(spec, remoteFetchOptions{LogLevel: FetchLogVerbose, OnEvent: func(FetchEvent) { counts[i]++ }})
+				assert.NoError(t, err, "local fetch should succeed")
+			}
+		})
+	}
+	wg.Wait()
+
+	assert.Equal(t, []int{10, 10, 10, 10}, counts, "each concurrent fetch should only deliver events to its own handler")
+}
+
+func TestFetchLogLevelFiltersEvents(t *testing.T) {
+	stubIncludeFiles(t, map[string]string{
+		"owner/repo/workflows/prompts/a.md@v1": "# A\n",
+	})
+	spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "v1"}, WorkflowPath: "workflows/triage.md"}
+	content := "@include prompts/a.md\n@include? prompts/missing.md\n"
+
+	tests := []struct {
+		name     string
+		logLevel FetchLogLevel
+		expected []FetchEventLevel
+	}{
+		{name: "default", logLevel: FetchLogDefault, expected: nil},
+		{name: "quiet", logLevel: FetchLogQuiet, expected: []FetchEventLevel{FetchEventWarning}},
+		{name: "verbose", logLevel: FetchLogVerbose, expected: []FetchEventLevel{FetchEventSuccess, FetchEventWarning}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var levels []FetchEventLevel
+			handler := func(event FetchEvent) {
+				if event.Level != FetchEventVerbose && event.Level != FetchEventInfo {
+					levels = append(levels, event.Level)
+				}
+			}
+
+			workflowsDir := filepath.Join(t.TempDir(), ".github", "workflows")
+			_, err := fetchAndSaveRemoteIncludes(content, spec, workflowsDir, remoteFetchOptions{LogLevel: tt.logLevel, OnEvent: handler})
+			require.NoError(t, err, "includes should be fetched")
+			assert.Equal(t, tt.expected, levels, "only the events of the log level should be emitted")
+		})
+	}
+
+	assert.Equal(t, FetchLogVerbose, fetchLogLevel(true, true), "verbose should take precedence over quiet")
+	assert.Equal(t, FetchLogQuiet, fetchLogLevel(false, true), "quiet flag should select the quiet level")
+	assert.Equal(t, FetchLogDefault, fetchLogLevel(false, false), "no flags should select the default level")
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrFetchLockMismatch is returned (wrapped) by frozen fetches when a fetched file is not
+// recorded in the fetch lock or its content differs from the recorded blob SHA
+var ErrFetchLockMismatch = errors.New("fetched file does not match the fetch lock")
+
+// frozenFetch is the fetch lock that fetched files are verified against in frozen mode
+type frozenFetch struct {
+	gitRoot string
+	lock    *FetchLock
+}
+
+// loadFrozenFetch loads the fetch lock of the repository at gitRoot for a frozen fetch. While
+// frozen, floating refs are never resolved: files are downloaded at the commits recorded in the
+// lock (see parser.FetchConfig.FrozenRefs), every fetched include and import must have a lock
+// entry whose blob SHA matches the fetched content, and the lock is left unchanged.
+func loadFrozenFetch(gitRoot string) (*frozenFetch, error) {
+	lock, err := loadFetchLock(gitRoot)
+	if err != nil {
+		return nil, err
+	}
+	if len(lock.Files) == 0 {
+		return nil, fmt.Errorf("frozen mode requires a fetch lock with recorded files at %s", fetchLockPath(gitRoot))
+	}
+
+	fetchLockLog.Printf("Freezing fetches to %d locked files", len(lock.Files))
+	return &frozenFetch{gitRoot: gitRoot, lock: lock}, nil
+}
+
+// verify checks that the content fetched for targetPath, with the given blob SHA, is the
+// content recorded in the fetch lock. It always succeeds on a nil frozenFetch, when fetches
+// are not frozen.
+func (frozen *frozenFetch) verify(targetPath, blobSHA string) error {
+	if frozen == nil {
+		return nil
+	}
+
+	relPath, err := fetchLockKey(frozen.gitRoot, targetPath)
+	if err != nil {
+		return fmt.Errorf("%s: %w: %w", targetPath, ErrFetchLockMismatch, err)
+	}
+	entry, ok := frozen.lock.Files[relPath]
+	if !ok {
+		return fmt.Errorf("%s is not recorded: %w", relPath, ErrFetchLockMismatch)
+	}
+	if entry.BlobSHA != blobSHA {
+		return fmt.Errorf("%s has blob %s, but %s is recorded: %w", relPath, blobSHA, entry.BlobSHA, ErrFetchLockMismatch)
+	}
+	fetchLockLog.Printf("Verified %s against the fetch lock", relPath)
+	return nil
+}
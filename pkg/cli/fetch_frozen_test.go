@@ -0,0 +1,105 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrozenFetch(t *testing.T) {
+	commitSHA := strings.Repeat("c", 40)
+	gitRoot := t.TempDir()
+	workflowsDir := filepath.Join(gitRoot, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(workflowsDir, 0o755), "workflows directory should be created")
+	tracker := &FileTracker{OriginalContent: make(map[string][]byte), gitRoot: gitRoot}
+	spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "main"}, WorkflowPath: "workflows/triage.md"}
+
+	lock := &FetchLock{Files: map[string]FetchLockEntry{
+		".github/shared/tools.md":        {Source: "owner/repo/shared/tools.md@main", BlobSHA: gitBlobSHA([]byte("Use the tools.\n")), CommitSHA: commitSHA},
+		".github/workflows/shared/ci.md": {Source: "owner/repo/workflows/shared/ci.md@main", BlobSHA: gitBlobSHA([]byte("Check CI.\n")), CommitSHA: commitSHA},
+	}}
+	require.NoError(t, lock.save(gitRoot, nil), "fetch lock should be written")
+	lockData, err := os.ReadFile(fetchLockPath(gitRoot))
+	require.NoError(t, err, "fetch lock should be readable")
+
+	frozen, err := loadFrozenFetch(gitRoot)
+	require.NoError(t, err, "fetches should be frozen")
+	mirrorDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(mirrorDir, "workflows", "shared"), 0o755), "mirror should be created")
+	require.NoError(t, os.WriteFile(filepath.Join(mirrorDir, "workflows", "shared", "ci.md"), []byte("Check CI twice.\n"), 0o644), "import should be written")
+	fetcher, err := parser.NewFetcher(parser.FetchConfig{
+		RepoMirrors: map[string]string{"owner/repo": mirrorDir},
+		Offline:     true,
+		FrozenRefs:  frozen.lock.ResolvedRefs(),
+	})
+	require.NoError(t, err, "fetcher should be created")
+	opts := remoteFetchOptions{Tracker: tracker, Fetcher: fetcher, Frozen: frozen}
+
+	t.Run("matching include is saved without updating the lock", func(t *testing.T) {
+		stubIncludeFiles(t, map[string]string{"owner/repo/.github/shared/tools.md@main": "Use the tools.\n"})
+		installed, err := fetchAndSaveRemoteIncludes("@include shared/tools.md\n", spec, workflowsDir, opts)
+		require.NoError(t, err, "locked include should be fetched")
+		assert.Len(t, installed, 1, "include should be installed")
+
+		data, err := os.ReadFile(fetchLockPath(gitRoot))
+		require.NoError(t, err, "fetch lock should be readable")
+		assert.Equal(t, string(lockData), string(data), "frozen fetches should not change the lock")
+	})
+
+	t.Run("changed include fails", func(t *testing.T) {
+		stubIncludeFiles(t, map[string]string{"owner/repo/.github/shared/tools.md@main": "Use all the tools.\n"})
+		forced := opts
+		forced.Force = true
+		_, err := fetchAndSaveRemoteIncludes("@include shared/tools.md\n", spec, workflowsDir, forced)
+		require.ErrorIs(t, err, ErrFetchLockMismatch, "content differing from the lock should fail")
+	})
+
+	t.Run("include missing from the lock fails", func(t *testing.T) {
+		stubIncludeFiles(t, map[string]string{"owner/repo/.github/shared/new.md@main": "New.\n"})
+		_, err := fetchAndSaveRemoteIncludes("@include? shared/new.md\n", spec, workflowsDir, opts)
+		require.ErrorIs(t, err, ErrFetchLockMismatch, "unlocked includes should fail, even when optional")
+	})
+
+	t.Run("imports are verified", func(t *testing.T) {
+		content := "---\nimports:\n  - shared/ci.md\n---\n\n# Triage\n"
+		_, err := fetchAndSaveRemoteFrontmatterImports(content, spec, workflowsDir, opts)
+		require.ErrorIs(t, err, ErrFetchLockMismatch, "imports differing from the lock should fail")
+	})
+
+	t.Run("floating refs without a recorded commit fail", func(t *testing.T) {
+		other := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "develop"}, WorkflowPath: "workflows/triage.md"}
+		content := "---\nimports:\n  - shared/ci.md\n---\n\n# Triage\n"
+		_, err := fetchAndSaveRemoteFrontmatterImports(content, other, workflowsDir, opts)
+		require.ErrorIs(t, err, parser.ErrRefNotLocked, "refs missing from the lock should not be resolved")
+	})
+}
+
+func TestLoadFrozenFetch_RequiresLock(t *testing.T) {
+	_, err := loadFrozenFetch(t.TempDir())
+	require.Error(t, err, "frozen mode should require a fetch lock")
+}
+
+func TestAddResolvedWorkflows_FetchConfig(t *testing.T) {
+	tempDir := testutil.TempDir(t, "test-*")
+	require.NoError(t, exec.Command("git", "init", tempDir).Run(), "git init should succeed")
+	t.Chdir(tempDir)
+	resolved := &ResolvedWorkflows{}
+
+	_, err := AddResolvedWorkflows(nil, resolved, AddOptions{Frozen: true, Refresh: true})
+	require.ErrorContains(t, err, "--frozen cannot be combined with --refresh", "pre-resolved workflows should get the frozen configuration")
+
+	_, err = AddResolvedWorkflows(nil, resolved, AddOptions{Frozen: true})
+	require.ErrorContains(t, err, "requires a fetch lock", "frozen mode should require a fetch lock for pre-resolved workflows too")
+
+	_, err = AddResolvedWorkflows(nil, resolved, AddOptions{RequestHeaders: map[string]string{"X-Trace": "a\r\nb"}})
+	require.Error(t, err, "invalid request headers should be rejected for pre-resolved workflows")
+}
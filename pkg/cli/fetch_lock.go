@@ -0,0 +1,483 @@
+package cli
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var fetchLockLog = logger.New("cli:fetch_lock")
+
+// fetchLockFileName is the name of the file in .github/aw/ that records which
+// include and import files were fetched from remote repositories by gh-aw.
+// Only files listed here are ever considered for pruning.
+const fetchLockFileName = "fetch-lock.json"
+
+// FetchLockEntry describes a single fetched file
+type FetchLockEntry struct {
+	Source    string `json:"source"`               // Remote location the file was fetched from (owner/repo/path@ref)
+	BlobSHA   string `json:"blob_sha,omitempty"`   // Git blob SHA of the content written locally
+	CommitSHA string `json:"commit_sha,omitempty"` // Commit the source ref resolved to when the file was fetched
+}
+
+// FetchLock records files written by gh-aw when fetching remote includes and imports.
+// Keys are paths relative to the git root, using forward slashes.
+type FetchLock struct {
+	Files map[string]FetchLockEntry `json:"files"`
+}
+
+// fetchLockPath returns the location of the fetch lock file for a repository
+func fetchLockPath(gitRoot string) string {
+	return filepath.Join(gitRoot, ".github", "aw", fetchLockFileName)
+}
+
+// loadFetchLock reads the fetch lock file, returning an empty lock if it does not exist
+func loadFetchLock(gitRoot string) (*FetchLock, error) {
+	lock := &FetchLock{Files: make(map[string]FetchLockEntry)}
+
+	data, err := os.ReadFile(fetchLockPath(gitRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return lock, nil
+		}
+		return nil, fmt.Errorf("failed to read fetch lock: %w", err)
+	}
+
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("failed to parse fetch lock: %w", err)
+	}
+	if lock.Files == nil {
+		lock.Files = make(map[string]FetchLockEntry)
+	}
+	return lock, nil
+}
+
+// save writes the fetch lock file, tracking it for staging and rollback when a tracker is given
+func (l *FetchLock) save(gitRoot string, tracker *FileTracker) error {
+	lockPath := fetchLockPath(gitRoot)
+
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal fetch lock: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for fetch lock: %w", err)
+	}
+
+	if tracker != nil {
+		if _, err := os.Stat(lockPath); err == nil {
+			tracker.TrackModified(lockPath)
+		} else {
+			tracker.TrackCreated(lockPath)
+		}
+	}
+
+	if err := os.WriteFile(lockPath, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write fetch lock: %w", err)
+	}
+	return nil
+}
+
+// ResolvedRefs maps each fetched "owner/repo@ref" to the commit SHA it resolved to.
+// Entries fetched at a commit SHA, or without a recorded commit, are omitted.
+func (l *FetchLock) ResolvedRefs() map[string]string {
+	resolved := make(map[string]string)
+	for _, entry := range l.Files {
+		if entry.CommitSHA == "" {
+			continue
+		}
+		repo, ref, ok := fetchLockSourceRef(entry.Source)
+		if !ok || IsCommitSHA(ref) {
+			continue
+		}
+		resolved[repo+"@"+ref] = entry.CommitSHA
+	}
+	return resolved
+}
+
+// gitBlobSHA computes the git blob SHA-1 of content, matching `git hash-object`
+func gitBlobSHA(content []byte) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(content))
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// fileHasBlobSHA reports whether the file at path exists and has the given git blob SHA
+func fileHasBlobSHA(path, blobSHA string) bool {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return gitBlobSHA(content) == blobSHA
+}
+
+// recordFetchedFile adds a fetched file to the fetch lock of the tracker's repository.
+// Recording is best-effort: failures are logged and never abort the fetch.
+func recordFetchedFile(tracker *FileTracker, targetPath, source, blobSHA string) {
+	if tracker == nil || tracker.gitRoot == "" {
+		return
+	}
+
+	relPath, err := fetchLockKey(tracker.gitRoot, targetPath)
+	if err != nil {
+		fetchLockLog.Printf("Not recording %s: %v", targetPath, err)
+		return
+	}
+
+	lock, err := loadFetchLock(tracker.gitRoot)
+	if err != nil {
+		fetchLockLog.Printf("Not recording %s: %v", targetPath, err)
+		return
+	}
+
+	lock.Files[relPath] = FetchLockEntry{Source: source, BlobSHA: blobSHA}
+	if err := lock.save(tracker.gitRoot, tracker); err != nil {
+		fetchLockLog.Printf("Failed to record %s: %v", targetPath, err)
+		return
+	}
+	tracker.fetchedLockKeys = append(tracker.fetchedLockKeys, relPath)
+	fetchLockLog.Printf("Recorded fetched file: %s (source: %s)", relPath, source)
+}
+
+// fetchLockSource returns the source recorded in the fetch lock for targetPath, without its ref
+// or section, e.g. "owner/repo/shared/tools.md"
+func fetchLockSource(tracker *FileTracker, targetPath string) (string, bool) {
+	if tracker == nil || tracker.gitRoot == "" {
+		return "", false
+	}
+	relPath, err := fetchLockKey(tracker.gitRoot, targetPath)
+	if err != nil {
+		return "", false
+	}
+	lock, err := loadFetchLock(tracker.gitRoot)
+	if err != nil {
+		return "", false
+	}
+	entry, ok := lock.Files[relPath]
+	if !ok {
+		return "", false
+	}
+	source, _, _ := strings.Cut(entry.Source, "#")
+	return workflowSpecFilePath(source), true
+}
+
+// resolveFetchedRef resolves the ref that fetched files came from to a commit SHA.
+// It is a variable so tests can substitute the GitHub API.
+var resolveFetchedRef = resolveSourceRef
+
+// fetchLockSourceRef returns the repository ("owner/repo") and ref that a fetch lock source was
+// fetched from, without its section or checksum pin. Sources without a ref were fetched from
+// main, like workflows and includes without one. ok is false for sources outside a
+// repository, such as HTTP(S) includes.
+func fetchLockSourceRef(source string) (repo, ref string, ok bool) {
+	if isHTTPIncludePath(source) {
+		return "", "", false
+	}
+	source, _ = parser.SplitIncludeIntegrity(source)
+	spec, err := parseSourceSpec(source)
+	if err != nil {
+		return "", "", false
+	}
+	ref, _, _ = strings.Cut(spec.Ref, "#")
+	if ref == "" {
+		ref = "main"
+	}
+	return spec.Repo, ref, true
+}
+
+// recordFetchedCommitSHAs records the commit that each file fetched since the last call came
+// from, resolving every distinct owner/repo@ref once through fetcher. known maps "owner/repo@ref" to commits
+// that are already resolved, such as the workflow's own. Like recordFetchedFile, recording is
+// best-effort: refs that cannot be resolved are logged and their files keep no commit.
+func recordFetchedCommitSHAs(tracker *FileTracker, fetcher *parser.Fetcher, known map[string]string) {
+	if tracker == nil || tracker.gitRoot == "" || len(tracker.fetchedLockKeys) == 0 {
+		return
+	}
+	keys := tracker.fetchedLockKeys
+	tracker.fetchedLockKeys = nil
+
+	lock, err := loadFetchLock(tracker.gitRoot)
+	if err != nil {
+		fetchLockLog.Printf("Not recording fetched commits: %v", err)
+		return
+	}
+
+	resolved := make(map[string]string, len(known))
+	maps.Copy(resolved, known)
+	updated := 0
+	for _, key := range keys {
+		entry, ok := lock.Files[key]
+		if !ok || entry.CommitSHA != "" {
+			continue
+		}
+		repo, ref, ok := fetchLockSourceRef(entry.Source)
+		if !ok {
+			continue
+		}
+
+		repoRef := repo + "@" + ref
+		commitSHA, seen := resolved[repoRef]
+		if !seen {
+			commitSHA = ref
+			if !IsCommitSHA(ref) {
+				owner, name, _ := strings.Cut(repo, "/")
+				if commitSHA, err = resolveFetchedRef(fetcher, owner, name, ref); err != nil {
+					fetchLockLog.Printf("Not recording commit for %s: %v", repoRef, err)
+					commitSHA = ""
+				}
+			}
+			// Failures are remembered too, so each ref is looked up at most once
+			resolved[repoRef] = commitSHA
+		}
+		if commitSHA == "" {
+			continue
+		}
+
+		entry.CommitSHA = commitSHA
+		lock.Files[key] = entry
+		updated++
+	}
+	if updated == 0 {
+		return
+	}
+
+	if err := lock.save(tracker.gitRoot, tracker); err != nil {
+		fetchLockLog.Printf("Failed to record fetched commits: %v", err)
+		return
+	}
+	fetchLockLog.Printf("Recorded commits for %d fetched files", updated)
+}
+
+// fetchLockKey converts a file path to its key in the fetch lock
+func fetchLockKey(gitRoot, filePath string) (string, error) {
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return "", err
+	}
+	relPath, err := filepath.Rel(gitRoot, absPath)
+	if err != nil {
+		return "", err
+	}
+	if relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %s is outside the repository", filePath)
+	}
+	return filepath.ToSlash(relPath), nil
+}
+
+// pruneOrphanedFetchedFiles deletes fetched include and import files that are no longer
+// referenced by any workflow. Only files recorded in the fetch lock are candidates, so
+// files written by hand are never removed. Deletions go through the tracker (when given)
+// so they can be staged and rolled back. Returns the removed paths relative to the git root.
+func pruneOrphanedFetchedFiles(gitRoot, workflowsDir string, tracker *FileTracker, verbose bool) ([]string, error) {
+	fetchLockLog.Printf("Pruning orphaned fetched files: gitRoot=%s, workflowsDir=%s", gitRoot, workflowsDir)
+
+	absWorkflowsDir, err := filepath.Abs(workflowsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve workflows directory: %w", err)
+	}
+
+	lock, err := loadFetchLock(gitRoot)
+	if err != nil {
+		return nil, err
+	}
+	if len(lock.Files) == 0 {
+		return nil, nil
+	}
+
+	referenced, err := collectReferencedFiles(gitRoot, absWorkflowsDir, lock)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(lock.Files))
+	for key := range lock.Files {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var removed []string
+	for _, key := range keys {
+		filePath := filepath.Join(gitRoot, filepath.FromSlash(key))
+		if referenced[filePath] {
+			continue
+		}
+
+		if _, err := os.Stat(filePath); err == nil {
+			if tracker != nil {
+				tracker.TrackDeleted(filePath)
+			}
+			if err := os.Remove(filePath); err != nil {
+				return removed, fmt.Errorf("failed to remove orphaned file %s: %w", key, err)
+			}
+			fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Removed orphaned fetched file: "+key))
+		} else if verbose {
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Forgetting missing fetched file: "+key))
+		}
+
+		delete(lock.Files, key)
+		removed = append(removed, key)
+	}
+
+	if len(removed) > 0 {
+		if err := lock.save(gitRoot, tracker); err != nil {
+			return removed, err
+		}
+	}
+
+	fetchLockLog.Printf("Pruned %d orphaned fetched files", len(removed))
+	return removed, nil
+}
+
+// collectReferencedFiles returns the absolute paths of all files reachable through
+// @include directives and frontmatter imports, starting from every markdown file in
+// the workflows directory that gh-aw did not fetch itself.
+func collectReferencedFiles(gitRoot, workflowsDir string, lock *FetchLock) (map[string]bool, error) {
+	referenced := make(map[string]bool)
+	var queue []string
+
+	err := filepath.Walk(workflowsDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".md") {
+			return nil
+		}
+		if key, err := fetchLockKey(gitRoot, path); err == nil {
+			if _, fetched := lock.Files[key]; fetched {
+				return nil
+			}
+		}
+		queue = append(queue, path)
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to scan workflows directory: %w", err)
+	}
+
+	visited := make(map[string]bool)
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+
+		content, err := os.ReadFile(current)
+		if err != nil {
+			fetchLockLog.Printf("Could not read %s: %v", current, err)
+			continue
+		}
+
+		for _, ref := range extractFileReferences(string(content)) {
+			for _, candidate := range referenceCandidates(gitRoot, workflowsDir, filepath.Dir(current), ref) {
+				if _, err := os.Stat(candidate); err != nil {
+					continue
+				}
+				referenced[candidate] = true
+				queue = append(queue, candidate)
+			}
+		}
+	}
+
+	return referenced, nil
+}
+
+// extractFileReferences returns the paths referenced by @include/import directives in the
+// markdown body and by the frontmatter 'imports:' field, without section fragments
+func extractFileReferences(content string) []string {
+	var refs []string
+	addRef := func(ref string) {
+		ref, _, _ = strings.Cut(ref, "#")
+		if ref = strings.TrimSpace(ref); ref != "" {
+			refs = append(refs, ref)
+		}
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		if directive := parser.ParseImportDirective(scanner.Text()); directive != nil {
+			addRef(directive.Path)
+			if directive.Target != "" {
+				addRef(directive.Target)
+			}
+		}
+	}
+
+	result, err := parser.ExtractFrontmatterFromContent(content)
+	if err != nil {
+		return refs
+	}
+	switch imports := result.Frontmatter["imports"].(type) {
+	case []any:
+		for _, item := range imports {
+			switch importItem := item.(type) {
+			case string:
+				addRef(importItem)
+			case map[string]any:
+				if pathStr, ok := importItem["path"].(string); ok {
+					addRef(pathStr)
+				}
+			}
+		}
+	case map[string]any:
+		// Import aliases are saved at their local paths (keys)
+		for _, imp := range parseFrontmatterImports(imports) {
+			addRef(imp.localPath)
+		}
+	}
+
+	return refs
+}
+
+// referenceCandidates lists the local paths a reference may have been fetched to.
+// It mirrors the target paths used by fetchAndSaveRemoteIncludes and
+// fetchAndSaveRemoteFrontmatterImports; over-matching only keeps extra files.
+func referenceCandidates(gitRoot, workflowsDir, baseDir, ref string) []string {
+	if IsWorkflowSpecFormat(ref) {
+		// Flattened includes keep their file name unless it collided with another source
+		refPath := workflowSpecFilePath(ref)
+		githubDir := filepath.Dir(workflowsDir)
+		sharedDir := filepath.Join(githubDir, "shared")
+		candidates := []string{filepath.Join(sharedDir, path.Base(refPath)), filepath.Join(sharedDir, hashedIncludeName(refPath))}
+
+		// With --namespace-shared they are flattened into shared/<owner>-<repo>/ instead, and
+		// rooted includes (rewritten to workflowspecs in the workflow) keep their path under
+		// .github/, inside the namespace
+		parts := strings.SplitN(refPath, "/", 3)
+		if len(parts) == 3 {
+			namespace := sharedNamespace(parts[0] + "/" + parts[1])
+			candidates = append(candidates,
+				filepath.Join(sharedDir, namespace, path.Base(refPath)),
+				filepath.Join(sharedDir, namespace, hashedIncludeName(refPath)),
+				filepath.Join(githubDir, filepath.FromSlash(parts[2])),
+				filepath.Join(githubDir, filepath.FromSlash(namespaceSharedPath(parts[2], namespace, nil))))
+		}
+		return candidates
+	}
+
+	if rest, ok := strings.CutPrefix(ref, "/"); ok {
+		return []string{filepath.Join(gitRoot, filepath.FromSlash(rest))}
+	}
+
+	// Any relative reference may have been rooted under .github/ (shared/ by default, or a
+	// configured rooted include prefix), so always consider that location as well
+	return []string{
+		filepath.Join(baseDir, filepath.FromSlash(ref)),
+		filepath.Join(filepath.Dir(workflowsDir), filepath.FromSlash(ref)),
+	}
+}
@@ -0,0 +1,200 @@
+//go:build !integration
+
+package cli
+
+import (
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755), "should create parent directory")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644), "should write %s", path)
+}
+
+func TestRecordFetchedFile(t *testing.T) {
+	gitRoot := t.TempDir()
+	tracker := &FileTracker{OriginalContent: make(map[string][]byte), gitRoot: gitRoot}
+
+	target := filepath.Join(gitRoot, ".github", "workflows", "shared", "tools.md")
+	writeTestFile(t, target, "# Tools\n")
+
+	recordFetchedFile(tracker, target, "owner/repo/.github/workflows/shared/tools.md@main", gitBlobSHA([]byte("# Tools\n")))
+
+	lock, err := loadFetchLock(gitRoot)
+	require.NoError(t, err, "should load fetch lock")
+	assert.Equal(t, FetchLockEntry{Source: "owner/repo/.github/workflows/shared/tools.md@main", BlobSHA: gitBlobSHA([]byte("# Tools\n"))},
+		lock.Files[".github/workflows/shared/tools.md"], "should record the fetched file")
+	assert.Contains(t, tracker.CreatedFiles, fetchLockPath(gitRoot), "should track the new lock file")
+
+	// Files outside the repository are never recorded
+	recordFetchedFile(tracker, filepath.Join(t.TempDir(), "outside.md"), "owner/repo/outside.md", "")
+	lock, err = loadFetchLock(gitRoot)
+	require.NoError(t, err, "should load fetch lock")
+	assert.Len(t, lock.Files, 1, "should ignore files outside the repository")
+}
+
+func TestRecordFetchedCommitSHAs(t *testing.T) {
+	const (
+		workflowSHA = "0123456789abcdef0123456789abcdef01234567"
+		libSHA      = "89abcdef0123456789abcdef0123456789abcdef"
+		pinnedSHA   = "fedcba9876543210fedcba9876543210fedcba98"
+	)
+	var lookups []string
+	original := resolveFetchedRef
+	t.Cleanup(func() { resolveFetchedRef = original })
+	resolveFetchedRef = func(_ *parser.Fetcher, owner, repo, ref string) (string, error) {
+		lookups = append(lookups, owner+"/"+repo+"@"+ref)
+		switch owner + "/" + repo + "@" + ref {
+		case "other/lib@v2":
+			return libSHA, nil
+		case "owner/repo@main":
+			return workflowSHA, nil
+		}
+		return "", errors.New("not found")
+	}
+
+	gitRoot := t.TempDir()
+	tracker := &FileTracker{OriginalContent: make(map[string][]byte), gitRoot: gitRoot}
+	files := map[string]string{
+		"tools.md":   "owner/repo/.github/workflows/shared/tools.md@v1",
+		"style.md":   "owner/repo/.github/workflows/shared/style.md#Tone@v1",
+		"lib.md":     "other/lib/docs/lib.md@v2#Usage",
+		"lib2.md":    "other/lib/docs/lib2.md@v2@sha256:" + strings.Repeat("a", 64),
+		"default.md": "owner/repo/.github/shared/default.md",
+		"pinned.md":  "other/lib/docs/pinned.md@" + pinnedSHA,
+		"gone.md":    "other/gone/gone.md@main",
+		"web.md":     "https://docs.example.com/web.md",
+	}
+	for name, source := range files {
+		path := filepath.Join(gitRoot, ".github", "shared", name)
+		writeTestFile(t, path, "# "+name+"\n")
+		recordFetchedFile(tracker, path, source, "")
+	}
+
+	recordFetchedCommitSHAs(tracker, nil, map[string]string{"owner/repo@v1": workflowSHA})
+
+	lock, err := loadFetchLock(gitRoot)
+	require.NoError(t, err, "should load fetch lock")
+	commits := make(map[string]string)
+	for key, entry := range lock.Files {
+		commits[path.Base(key)] = entry.CommitSHA
+	}
+	assert.Equal(t, map[string]string{
+		"tools.md":   workflowSHA,
+		"style.md":   workflowSHA,
+		"lib.md":     libSHA,
+		"lib2.md":    libSHA,
+		"default.md": workflowSHA,
+		"pinned.md":  pinnedSHA,
+		"gone.md":    "",
+		"web.md":     "",
+	}, commits, "every fetched repository ref should record its commit")
+	assert.ElementsMatch(t, []string{"other/lib@v2", "owner/repo@main", "other/gone@main"}, lookups, "each ref should be resolved once, and known or pinned refs not at all")
+	assert.Equal(t, map[string]string{
+		"owner/repo@v1":   workflowSHA,
+		"owner/repo@main": workflowSHA,
+		"other/lib@v2":    libSHA,
+	}, lock.ResolvedRefs(), "resolved refs should map repo@ref to the commit")
+
+	lookups = nil
+	recordFetchedCommitSHAs(tracker, nil, nil)
+	assert.Empty(t, lookups, "files are only resolved once after they are fetched")
+}
+
+func TestGitBlobSHA(t *testing.T) {
+	// Matches `printf 'hello\n' | git hash-object --stdin`
+	assert.Equal(t, "ce013625030ba8dba906f756967f9e9ca394464a", gitBlobSHA([]byte("hello\n")), "should match git's blob SHA")
+	// Matches `git hash-object /dev/null`
+	assert.Equal(t, "e69de29bb2d1d6434b8b29ae775ad8c2e48c5391", gitBlobSHA(nil), "should match git's empty blob SHA")
+
+	path := filepath.Join(t.TempDir(), "file.md")
+	writeTestFile(t, path, "hello\n")
+	assert.True(t, fileHasBlobSHA(path, gitBlobSHA([]byte("hello\n"))), "should match unchanged content")
+	assert.False(t, fileHasBlobSHA(path, gitBlobSHA([]byte("changed\n"))), "should not match changed content")
+	assert.False(t, fileHasBlobSHA(filepath.Join(t.TempDir(), "missing.md"), gitBlobSHA(nil)), "should not match a missing file")
+}
+
+func TestPruneOrphanedFetchedFiles(t *testing.T) {
+	gitRoot := t.TempDir()
+	workflowsDir := filepath.Join(gitRoot, ".github", "workflows")
+
+	writeTestFile(t, filepath.Join(workflowsDir, "triage.md"), `---
+on: issues
+imports:
+  - shared/used.md
+---
+# Triage
+
+@include shared/body.md#Intro
+@include other/lib/docs/tools.md@v1
+@include owner/repo/shared/ns.md@abc123
+@include other/lib/docs/lib.md@v1
+`)
+	writeTestFile(t, filepath.Join(workflowsDir, "shared", "used.md"), "---\nimports:\n  - nested.md\n---\n# Used\n")
+	writeTestFile(t, filepath.Join(workflowsDir, "shared", "nested.md"), "# Nested\n")
+	writeTestFile(t, filepath.Join(gitRoot, ".github", "shared", "body.md"), "# Intro\n")
+	writeTestFile(t, filepath.Join(workflowsDir, "shared", "orphan.md"), "# Orphan\n")
+	writeTestFile(t, filepath.Join(workflowsDir, "shared", "handwritten.md"), "# Handwritten\n")
+	hashedName := hashedIncludeName("other/lib/docs/tools.md")
+	writeTestFile(t, filepath.Join(gitRoot, ".github", "shared", hashedName), "# Tools\n")
+	writeTestFile(t, filepath.Join(gitRoot, ".github", "shared", "owner-repo", "ns.md"), "# Namespaced\n")
+	writeTestFile(t, filepath.Join(gitRoot, ".github", "shared", "other-lib", "lib.md"), "# Lib\n")
+
+	lock := &FetchLock{Files: map[string]FetchLockEntry{
+		".github/workflows/shared/used.md":   {Source: "owner/repo/.github/workflows/shared/used.md@main"},
+		".github/workflows/shared/nested.md": {Source: "owner/repo/.github/workflows/shared/nested.md@main"},
+		".github/shared/body.md":             {Source: "owner/repo/shared/body.md@main"},
+		".github/workflows/shared/orphan.md": {Source: "owner/repo/.github/workflows/shared/orphan.md@main"},
+		".github/workflows/shared/gone.md":   {Source: "owner/repo/.github/workflows/shared/gone.md@main"},
+		".github/shared/" + hashedName:       {Source: "other/lib/docs/tools.md@v1"},
+		".github/shared/owner-repo/ns.md":    {Source: "owner/repo/shared/ns.md@main"},
+		".github/shared/other-lib/lib.md":    {Source: "other/lib/docs/lib.md@v1"},
+	}}
+	require.NoError(t, lock.save(gitRoot, nil), "should save fetch lock")
+
+	tracker := &FileTracker{OriginalContent: make(map[string][]byte), gitRoot: gitRoot}
+	removed, err := pruneOrphanedFetchedFiles(gitRoot, workflowsDir, tracker, false)
+	require.NoError(t, err, "should prune without error")
+
+	assert.Equal(t, []string{".github/workflows/shared/gone.md", ".github/workflows/shared/orphan.md"}, removed,
+		"should prune only unreferenced fetched files")
+	assert.NoFileExists(t, filepath.Join(workflowsDir, "shared", "orphan.md"), "orphan should be deleted")
+	assert.FileExists(t, filepath.Join(workflowsDir, "shared", "used.md"), "imported file should be kept")
+	assert.FileExists(t, filepath.Join(workflowsDir, "shared", "nested.md"), "nested import should be kept")
+	assert.FileExists(t, filepath.Join(gitRoot, ".github", "shared", "body.md"), "included file should be kept")
+	assert.FileExists(t, filepath.Join(gitRoot, ".github", "shared", hashedName), "include flattened under a hashed name should be kept")
+	assert.FileExists(t, filepath.Join(gitRoot, ".github", "shared", "owner-repo", "ns.md"), "namespaced rooted include should be kept")
+	assert.FileExists(t, filepath.Join(gitRoot, ".github", "shared", "other-lib", "lib.md"), "namespaced flattened include should be kept")
+	assert.FileExists(t, filepath.Join(workflowsDir, "shared", "handwritten.md"), "files not fetched by gh-aw should never be deleted")
+
+	updated, err := loadFetchLock(gitRoot)
+	require.NoError(t, err, "should reload fetch lock")
+	assert.Len(t, updated.Files, 6, "pruned entries should be removed from the lock")
+
+	// Deletions are reversible through the tracker
+	require.NoError(t, tracker.RollbackDeletedFiles(false), "should roll back deletions")
+	content, err := os.ReadFile(filepath.Join(workflowsDir, "shared", "orphan.md"))
+	require.NoError(t, err, "orphan should be restored")
+	assert.Equal(t, "# Orphan\n", string(content), "restored content should match")
+}
+
+func TestPruneOrphanedFetchedFiles_NoLock(t *testing.T) {
+	gitRoot := t.TempDir()
+	workflowsDir := filepath.Join(gitRoot, ".github", "workflows")
+	writeTestFile(t, filepath.Join(workflowsDir, "shared", "orphan.md"), "# Orphan\n")
+
+	removed, err := pruneOrphanedFetchedFiles(gitRoot, workflowsDir, nil, false)
+	require.NoError(t, err, "should not error without a fetch lock")
+	assert.Empty(t, removed, "should not remove anything without a fetch lock")
+	assert.FileExists(t, filepath.Join(workflowsDir, "shared", "orphan.md"), "untracked files should be kept")
+}
@@ -18,8 +18,10 @@ var fileTrackerLog = logger.New("cli:file_tracker")
 type FileTracker struct {
 	CreatedFiles    []string
 	ModifiedFiles   []string
+	DeletedFiles    []string
 	OriginalContent map[string][]byte // Store original content for rollback
 	gitRoot         string
+	fetchedLockKeys []string // Fetch lock entries recorded since their commits were last resolved
 }
 
 // NewFileTracker creates a new file tracker
@@ -67,6 +69,26 @@ func (ft *FileTracker) TrackModified(filePath string) {
 	ft.ModifiedFiles = append(ft.ModifiedFiles, absPath)
 }
 
+// TrackDeleted adds a file to the deleted files list and stores its content so the
+// deletion can be rolled back. Call it before removing the file.
+func (ft *FileTracker) TrackDeleted(filePath string) {
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		absPath = filePath
+	}
+
+	if _, exists := ft.OriginalContent[absPath]; !exists {
+		if content, err := os.ReadFile(absPath); err == nil {
+			ft.OriginalContent[absPath] = content
+			fileTrackerLog.Printf("Tracking deleted file: %s (stored %d bytes)", absPath, len(content))
+		} else {
+			fileTrackerLog.Printf("Tracking deleted file: %s (failed to store original: %v)", absPath, err)
+		}
+	}
+
+	ft.DeletedFiles = append(ft.DeletedFiles, absPath)
+}
+
 // GetAllFiles returns all tracked files (created and modified)
 func (ft *FileTracker) GetAllFiles() []string {
 	all := make([]string, 0, len(ft.CreatedFiles)+len(ft.ModifiedFiles))
@@ -102,6 +124,19 @@ func (ft *FileTracker) StageAllFiles(verbose bool) error {
 		return fmt.Errorf("failed to stage files: %w", err)
 	}
 
+	// Stage deletions separately: git add fails on paths that no longer exist
+	// and were never committed
+	if len(ft.DeletedFiles) > 0 {
+		fileTrackerLog.Printf("Staging %d deleted files", len(ft.DeletedFiles))
+		args := append([]string{"rm", "--cached", "--quiet", "--ignore-unmatch", "--"}, ft.DeletedFiles...)
+		cmd := exec.Command("git", args...)
+		cmd.Dir = ft.gitRoot
+		if err := cmd.Run(); err != nil {
+			fileTrackerLog.Printf("Failed to stage deleted files: %v", err)
+			return fmt.Errorf("failed to stage deleted files: %w", err)
+		}
+	}
+
 	fileTrackerLog.Printf("Successfully staged all files")
 	return nil
 }
@@ -164,7 +199,43 @@ func (ft *FileTracker) RollbackModifiedFiles(verbose bool) error {
 	return nil
 }
 
-// RollbackAllFiles rolls back both created and modified files
+// RollbackDeletedFiles restores all deleted files from their stored content
+func (ft *FileTracker) RollbackDeletedFiles(verbose bool) error {
+	if len(ft.DeletedFiles) == 0 {
+		return nil
+	}
+
+	console.LogVerbose(verbose, fmt.Sprintf("Rolling back %d deleted files...", len(ft.DeletedFiles)))
+
+	var errors []string
+	for _, file := range ft.DeletedFiles {
+		console.LogVerbose(verbose, "  - Restoring "+file)
+
+		originalContent, exists := ft.OriginalContent[file]
+		if !exists {
+			if verbose {
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage("No original content stored for "+file))
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			errors = append(errors, fmt.Sprintf("failed to restore %s: %v", file, err))
+			continue
+		}
+		// Use owner-only read/write permissions (0600) for security best practices
+		if err := os.WriteFile(file, originalContent, 0600); err != nil {
+			errors = append(errors, fmt.Sprintf("failed to restore %s: %v", file, err))
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("rollback errors: %s", strings.Join(errors, "; "))
+	}
+
+	return nil
+}
+
+// RollbackAllFiles rolls back created, modified, and deleted files
 func (ft *FileTracker) RollbackAllFiles(verbose bool) error {
 	var errors []string
 
@@ -176,6 +247,10 @@ func (ft *FileTracker) RollbackAllFiles(verbose bool) error {
 		errors = append(errors, fmt.Sprintf("modified files rollback: %v", err))
 	}
 
+	if err := ft.RollbackDeletedFiles(verbose); err != nil {
+		errors = append(errors, fmt.Sprintf("deleted files rollback: %v", err))
+	}
+
 	if len(errors) > 0 {
 		return fmt.Errorf("rollback errors: %s", strings.Join(errors, "; "))
 	}
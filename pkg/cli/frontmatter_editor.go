@@ -5,10 +5,8 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/github/gh-aw/pkg/constants"
 	"github.com/github/gh-aw/pkg/logger"
 	"github.com/github/gh-aw/pkg/parser"
-	"github.com/github/gh-aw/pkg/workflow"
 )
 
 var frontmatterEditorLog = logger.New("cli:frontmatter_editor")
@@ -27,7 +25,7 @@ func UpdateFieldInFrontmatter(content, fieldName, fieldValue string) (string, er
 	}
 
 	// Try to preserve original frontmatter formatting by manually updating the field
-	if len(result.FrontmatterLines) > 0 {
+	if len(result.FrontmatterLines) > 0 && !editsFrontmatterMap(result) {
 		frontmatterEditorLog.Printf("Using raw frontmatter lines for field update (%d lines)", len(result.FrontmatterLines))
 		// Look for existing field in the raw lines
 		fieldUpdated := false
@@ -95,28 +93,15 @@ func updateFieldInFrontmatterFallback(result *parser.FrontmatterResult, fieldNam
 	// Update the field
 	result.Frontmatter[fieldName] = fieldValue
 
-	// Convert back to YAML with proper field ordering
-	updatedFrontmatter, err := workflow.MarshalWithFieldOrder(result.Frontmatter, constants.PriorityWorkflowFields)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal updated frontmatter: %w", err)
-	}
-
-	// Clean up quoted keys - replace "on": with on: at the start of a line
-	frontmatterStr := strings.TrimSuffix(string(updatedFrontmatter), "\n")
-	frontmatterStr = workflow.UnquoteYAMLKey(frontmatterStr, "on")
-
-	// Reconstruct the file
-	var lines []string
-	lines = append(lines, "---")
-	if frontmatterStr != "" {
-		lines = append(lines, strings.Split(frontmatterStr, "\n")...)
-	}
-	lines = append(lines, "---")
-	if result.Markdown != "" {
-		lines = append(lines, result.Markdown)
-	}
+	// Convert back to the recorded format with proper field ordering
+	return reconstructWorkflowFileFromMap(result.Format, result.Frontmatter, result.Markdown)
+}
 
-	return strings.Join(lines, "\n"), nil
+// editsFrontmatterMap reports whether the frontmatter of result is edited through its map
+// instead of its raw lines. The line-based edits preserve formatting but only understand
+// YAML, so TOML and JSON frontmatter is rewritten from the map in its own format.
+func editsFrontmatterMap(result *parser.FrontmatterResult) bool {
+	return result.Format == parser.FrontmatterFormatTOML || result.Format == parser.FrontmatterFormatJSON
 }
 
 // addFieldToFrontmatter adds a new field to the frontmatter while preserving formatting.
@@ -129,7 +114,7 @@ func addFieldToFrontmatter(content, fieldName, fieldValue string) (string, error
 	}
 
 	// Try to preserve original frontmatter formatting by manually inserting the field
-	if len(result.FrontmatterLines) > 0 {
+	if len(result.FrontmatterLines) > 0 && !editsFrontmatterMap(result) {
 		// Check if field already exists
 		if result.Frontmatter != nil {
 			if _, exists := result.Frontmatter[fieldName]; exists {
@@ -202,6 +187,12 @@ func RemoveFieldFromOnTrigger(content, fieldName string) (string, error) {
 		return content, nil
 	}
 
+	if editsFrontmatterMap(result) {
+		frontmatterEditorLog.Printf("Removing field %s from %s frontmatter map", fieldName, result.Format)
+		delete(onMap, fieldName)
+		return reconstructWorkflowFileFromMap(result.Format, result.Frontmatter, result.Markdown)
+	}
+
 	// Work with raw frontmatter lines to preserve formatting
 	if len(result.FrontmatterLines) > 0 {
 		frontmatterEditorLog.Printf("Using raw frontmatter lines to remove field (%d lines)", len(result.FrontmatterLines))
@@ -306,6 +297,18 @@ func SetFieldInOnTrigger(content, fieldName, fieldValue string) (string, error)
 
 	// Check if 'on' field exists
 	onValue, exists := result.Frontmatter["on"]
+	if editsFrontmatterMap(result) {
+		onMap, isMap := onValue.(map[string]any)
+		if !exists {
+			onMap = make(map[string]any)
+			result.Frontmatter["on"] = onMap
+		} else if !isMap {
+			return "", errors.New("'on' field is not an object, cannot set nested field")
+		}
+		frontmatterEditorLog.Printf("Setting field %s in %s frontmatter map", fieldName, result.Format)
+		onMap[fieldName] = fieldValue
+		return reconstructWorkflowFileFromMap(result.Format, result.Frontmatter, result.Markdown)
+	}
 	if !exists {
 		// No 'on' field exists, need to create it
 		// Add the 'on:' block with the field at the beginning of frontmatter
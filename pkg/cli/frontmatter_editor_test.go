@@ -5,6 +5,8 @@ package cli
 import (
 	"strings"
 	"testing"
+
+	"github.com/github/gh-aw/pkg/parser"
 )
 
 func TestRemoveFieldFromOnTrigger(t *testing.T) {
@@ -212,3 +214,66 @@ permissions:
 		})
 	}
 }
+
+func TestFrontmatterEditorKeepsFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  parser.FrontmatterFormat
+		content string
+	}{
+		{
+			name:    "TOML",
+			format:  parser.FrontmatterFormatTOML,
+			content: "+++\nengine = \"copilot\"\n\n[on.issues]\ntypes = [\"opened\"]\n+++\n\n# Test Workflow",
+		},
+		{
+			name:    "JSON",
+			format:  parser.FrontmatterFormatJSON,
+			content: "```json\n{\n  \"engine\": \"copilot\",\n  \"on\": {\"issues\": {\"types\": [\"opened\"]}}\n}\n```\n\n# Test Workflow",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := UpdateFieldInFrontmatter(tt.content, "source", "acme/shared/workflows/triage.md@main")
+			if err != nil {
+				t.Fatalf("UpdateFieldInFrontmatter() error = %v", err)
+			}
+			content, err = SetFieldInOnTrigger(content, "stop-after", "+48h")
+			if err != nil {
+				t.Fatalf("SetFieldInOnTrigger() error = %v", err)
+			}
+
+			result, err := parser.ExtractFrontmatterFromContent(content)
+			if err != nil {
+				t.Fatalf("Edited content should parse, got %v:\n%s", err, content)
+			}
+			if result.Format != tt.format {
+				t.Errorf("Expected %s frontmatter, got %q:\n%s", tt.format, result.Format, content)
+			}
+			if result.Frontmatter["source"] != "acme/shared/workflows/triage.md@main" || result.Frontmatter["engine"] != "copilot" {
+				t.Errorf("Expected source to be set and engine kept, got %v", result.Frontmatter)
+			}
+			onMap, _ := result.Frontmatter["on"].(map[string]any)
+			if onMap["stop-after"] != "+48h" || onMap["issues"] == nil {
+				t.Errorf("Expected stop-after to be set and issues kept, got %v", onMap)
+			}
+			if result.Markdown != "# Test Workflow" {
+				t.Errorf("Expected markdown to be kept, got %q", result.Markdown)
+			}
+
+			content, err = RemoveFieldFromOnTrigger(content, "stop-after")
+			if err != nil {
+				t.Fatalf("RemoveFieldFromOnTrigger() error = %v", err)
+			}
+			result, err = parser.ExtractFrontmatterFromContent(content)
+			if err != nil || result.Format != tt.format {
+				t.Fatalf("Expected %s frontmatter after removing stop-after, got %v:\n%s", tt.format, err, content)
+			}
+			onMap, _ = result.Frontmatter["on"].(map[string]any)
+			if _, exists := onMap["stop-after"]; exists {
+				t.Errorf("Expected stop-after to be removed, got %v", onMap)
+			}
+		})
+	}
+}
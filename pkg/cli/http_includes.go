@@ -0,0 +1,172 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var httpIncludesLog = logger.New("cli:http_includes")
+
+// httpIncludeTimeout bounds a single HTTP(S) include download, including redirects
+const httpIncludeTimeout = 30 * time.Second
+
+// ErrHTTPIncludesDisabled is returned (wrapped) when a workflow includes an http(s):// URL
+// without --allow-http-includes
+var ErrHTTPIncludesDisabled = errors.New("HTTP(S) includes are disabled")
+
+// httpIncludeClient downloads HTTP(S) includes
+var httpIncludeClient = &http.Client{Timeout: httpIncludeTimeout}
+
+// isHTTPIncludePath reports whether an include path is an http:// or https:// URL rather than
+// a workflowspec or a relative path
+func isHTTPIncludePath(includePath string) bool {
+	lower := strings.ToLower(includePath)
+	return strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "http://")
+}
+
+// resolveHTTPIncludeURL resolves a relative include found in the file at baseURL against that
+// URL, e.g. ("https://docs.example.com/prompts/a.md", "b.md") → "https://docs.example.com/prompts/b.md"
+func resolveHTTPIncludeURL(baseURL, includePath string) (string, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid include URL %q: %w", baseURL, err)
+	}
+	ref, err := url.Parse(includePath)
+	if err != nil {
+		return "", fmt.Errorf("invalid include path %q: %w", includePath, err)
+	}
+	return base.ResolveReference(ref).String(), nil
+}
+
+// httpIncludeLocalPath returns the slash-separated path, relative to the workflows directory,
+// that an HTTP(S) include is saved to: shared/<host>/<path>, e.g.
+// "https://docs.example.com/prompts/tone.md" → "shared/docs.example.com/prompts/tone.md".
+// URLs with a query string get a short hash of the URL appended to their file name.
+func httpIncludeLocalPath(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid include URL %q: %w", rawURL, err)
+	}
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("invalid include URL %q: missing host", rawURL)
+	}
+	if u.Path == "" || strings.HasSuffix(u.Path, "/") {
+		return "", fmt.Errorf("invalid include URL %q: must name a file", rawURL)
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if port := u.Port(); port != "" {
+		host += "-" + port
+	}
+	filePath := strings.TrimPrefix(path.Clean("/"+u.Path), "/")
+	if u.RawQuery != "" {
+		u.Fragment = ""
+		filePath = path.Join(path.Dir(filePath), hashedIncludeName(u.String()))
+	}
+	return path.Join("shared", host, filePath), nil
+}
+
+// fetchHTTPInclude downloads an include with a plain HTTP GET. It fails with
+// ErrHTTPIncludesDisabled unless opts.AllowHTTPIncludes is set, and with parser.ErrFileTooLarge
+// when the response exceeds opts.MaxFileSize bytes (parser.DefaultMaxDownloadSize when it is 0).
+func fetchHTTPInclude(rawURL string, opts remoteFetchOptions) ([]byte, error) {
+	if !opts.AllowHTTPIncludes {
+		return nil, fmt.Errorf("include %s: %w (use --allow-http-includes to fetch it)", rawURL, ErrHTTPIncludesDisabled)
+	}
+
+	httpIncludesLog.Printf("GET %s", rawURL)
+	resp, err := httpIncludeClient.Get(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, &parser.NotFoundError{Source: rawURL, Err: fmt.Errorf("HTTP %d", resp.StatusCode)}
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("failed to fetch %s: HTTP %d", rawURL, resp.StatusCode)
+	}
+
+	limit := parser.MaxDownloadSize(opts.MaxFileSize)
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response for %s: %w", rawURL, err)
+	}
+	if int64(len(body)) > limit {
+		return nil, fmt.Errorf("%s exceeds %d bytes: %w", rawURL, limit, parser.ErrFileTooLarge)
+	}
+	httpIncludesLog.Printf("Fetched %d bytes from %s", len(body), rawURL)
+	return body, nil
+}
+
+// localHTTPIncludePath returns the local include path that replaces an HTTP(S) include path
+// (with optional checksum pin and #section) once the include has been saved, as seen from a file
+// in fileDir. HTTP(S) includes are saved relative to workflowsDir. A target override is the
+// local path itself. ok is false for other include paths.
+func localHTTPIncludePath(includePath, target, fileDir, workflowsDir string) (localPath string, ok bool) {
+	stripped, _ := parser.SplitIncludeIntegrity(includePath)
+	rawURL, section, hasSection := strings.Cut(stripped, "#")
+	if !isHTTPIncludePath(rawURL) {
+		return "", false
+	}
+
+	localPath = target
+	if localPath == "" {
+		savedPath, err := httpIncludeLocalPath(rawURL)
+		if err != nil {
+			httpIncludesLog.Printf("Keeping include %s: %v", rawURL, err)
+			return "", false
+		}
+		rel, err := filepath.Rel(fileDir, filepath.Join(workflowsDir, filepath.FromSlash(savedPath)))
+		if err != nil {
+			return "", false
+		}
+		localPath = filepath.ToSlash(rel)
+	}
+	if hasSection {
+		localPath += "#" + section
+	}
+	return localPath, true
+}
+
+// localizeHTTPIncludeDirectives rewrites the @include directives with http(s):// URLs in the
+// content of a file saved in fileDir to the local copies of the includes in workflowsDir, so
+// compilation never fetches over HTTP
+func localizeHTTPIncludeDirectives(content, fileDir, workflowsDir string) string {
+	var builder strings.Builder
+	changed := false
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if matches := includeDirectivePattern.FindStringSubmatch(line); matches != nil {
+			includePath, target := parser.SplitIncludeTarget(strings.TrimSpace(matches[2]))
+			if localPath, ok := localHTTPIncludePath(includePath, target, fileDir, workflowsDir); ok {
+				line = matches[1] + localPath
+				changed = true
+			}
+		}
+		builder.WriteString(line)
+		builder.WriteString("\n")
+	}
+
+	if !changed {
+		return content
+	}
+	result := builder.String()
+	if !strings.HasSuffix(content, "\n") {
+		result = strings.TrimSuffix(result, "\n")
+	}
+	return result
+}
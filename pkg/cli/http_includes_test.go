@@ -0,0 +1,105 @@
+//go:build !integration
+
+package cli
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPIncludeLocalPath(t *testing.T) {
+	tests := []struct {
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{url: "https://docs.example.com/prompts/tone.md", want: "shared/docs.example.com/prompts/tone.md"},
+		{url: "http://Docs.Example.com:8080/a/../tone.md", want: "shared/docs.example.com-8080/tone.md"},
+		{url: "https://docs.example.com/tone.md?raw=1", want: "shared/docs.example.com/" + hashedIncludeName("https://docs.example.com/tone.md?raw=1")},
+		{url: "https://docs.example.com/prompts/", wantErr: true},
+		{url: "https:///tone.md", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			got, err := httpIncludeLocalPath(tt.url)
+			if tt.wantErr {
+				assert.Error(t, err, "URL should be rejected")
+				return
+			}
+			require.NoError(t, err, "URL should map to a local path")
+			assert.Equal(t, tt.want, got, "local path")
+		})
+	}
+}
+
+func TestFetchHTTPIncludes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/prompts/style.md":
+			_, _ = w.Write([]byte("# Style\n\n@include tone.md#Tone\n"))
+		case "/prompts/tone.md":
+			_, _ = w.Write([]byte("## Tone\n\nBe kind.\n\n## Other\n\nSkipped.\n"))
+		case "/large.md":
+			_, _ = w.Write([]byte(strings.Repeat("x", 100)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	stubIncludeFiles(t, map[string]string{
+		"owner/repo/.github/shared/guide.md@v1": "# Guide\n\n@include " + server.URL + "/prompts/style.md\n",
+	})
+	spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "v1"}, WorkflowPath: ".github/workflows/triage.md"}
+	content := "@include shared/guide.md\n@include? " + server.URL + "/missing.md\n"
+
+	t.Run("disabled by default", func(t *testing.T) {
+		_, err := fetchAndSaveRemoteIncludes(content, spec, t.TempDir(), remoteFetchOptions{})
+		require.ErrorIs(t, err, ErrHTTPIncludesDisabled, "HTTP(S) includes should be refused")
+		assert.Contains(t, err.Error(), "--allow-http-includes", "error should name the flag")
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		workflowsDir := filepath.Join(t.TempDir(), ".github", "workflows")
+
+		_, err := fetchAndSaveRemoteIncludes(content, spec, workflowsDir, remoteFetchOptions{AllowHTTPIncludes: true})
+		require.NoError(t, err, "HTTP(S) includes should be fetched")
+
+		localDir, err := httpIncludeLocalPath(server.URL + "/prompts/style.md")
+		require.NoError(t, err, "URL should map to a local path")
+		localDir = filepath.Dir(filepath.FromSlash(localDir))
+
+		guide, err := os.ReadFile(filepath.Join(workflowsDir, "..", "shared", "guide.md"))
+		require.NoError(t, err, "GitHub include should be saved")
+		assert.Contains(t, string(guide), "@include ../workflows/"+filepath.ToSlash(localDir)+"/style.md\n", "URL directive should point at the local copy")
+
+		style, err := os.ReadFile(filepath.Join(workflowsDir, localDir, "style.md"))
+		require.NoError(t, err, "HTTP(S) include should be saved under shared/<host>/")
+		assert.Contains(t, string(style), "@include tone.md#Tone", "relative includes should be kept")
+
+		tone, err := os.ReadFile(filepath.Join(workflowsDir, localDir, "tone.md"))
+		require.NoError(t, err, "relative include of an HTTP(S) include should resolve against its URL")
+		assert.Equal(t, "## Tone\n\nBe kind.", strings.TrimSpace(string(tone)), "only the referenced section should be saved")
+
+		_, err = fetchAndSaveRemoteIncludes("@include "+server.URL+"/large.md\n", spec, t.TempDir(), remoteFetchOptions{AllowHTTPIncludes: true, MaxFileSize: 10})
+		require.ErrorIs(t, err, parser.ErrFileTooLarge, "oversized responses should be refused")
+	})
+}
+
+func TestProcessIncludesWithWorkflowSpecHTTPIncludes(t *testing.T) {
+	spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "v1"}, WorkflowPath: ".github/workflows/triage.md"}
+	content := "@include https://docs.example.com/prompts/tone.md#Tone\n@include? https://docs.example.com/extra.md -> shared/extra.md\n"
+
+	got, err := processIncludesWithWorkflowSpec(content, spec, "", "", false)
+	require.NoError(t, err, "includes should be processed")
+	assert.Equal(t, "{{#import shared/docs.example.com/prompts/tone.md#Tone}}\n{{#import? shared/extra.md}}\n", got, "URL includes should point at their local copies")
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// resolveImportLocalPath maps an import fetched from remoteFilePath, a clean forward-slash path
+// relative to the source repository root, to the local path it is saved at. originalBaseDir is
+// the directory of the top-level workflow in the source repository ("" for the repository root)
+// and targetDir is the local directory the workflow is installed in (.github/workflows).
+//
+// The local layout mirrors the source layout relative to the workflow, so imports resolve from
+// the installed workflow as they do upstream:
+//   - files inside originalBaseDir keep their path relative to it:
+//     ".github/workflows/shared/a.md" from ".github/workflows" → <targetDir>/shared/a.md
+//   - workflows at the repository root keep the full remote path:
+//     "shared/a.md" from "" → <targetDir>/shared/a.md
+//   - files outside originalBaseDir keep their relative position to it, as long as that stays
+//     inside the parent of targetDir (.github/):
+//     ".github/shared/a.md" from ".github/workflows" → <targetDir>/../shared/a.md
+//
+// Root-absolute imports ("/shared/a.md") are resolved by the compiler against the workflow's
+// directory, so callers pass "" as originalBaseDir for them.
+//
+// An error is returned for paths that cannot be placed unambiguously: remote paths that are not
+// clean or leave the repository, paths naming originalBaseDir itself, and files outside
+// originalBaseDir whose mirrored location would leave the parent of targetDir.
+func resolveImportLocalPath(remoteFilePath, originalBaseDir, targetDir string) (string, error) {
+	if remoteFilePath == "" || remoteFilePath == "." || path.IsAbs(remoteFilePath) || path.Clean(remoteFilePath) != remoteFilePath ||
+		remoteFilePath == ".." || strings.HasPrefix(remoteFilePath, "../") {
+		return "", fmt.Errorf("import path %q must be a clean path inside the repository", remoteFilePath)
+	}
+	baseDir := path.Clean(originalBaseDir)
+	if baseDir == "." {
+		baseDir = ""
+	}
+	if path.IsAbs(baseDir) || baseDir == ".." || strings.HasPrefix(baseDir, "../") {
+		return "", fmt.Errorf("workflow directory %q must be a path inside the repository", originalBaseDir)
+	}
+
+	relPath := remoteFilePath
+	if baseDir != "" {
+		rel, err := filepath.Rel(filepath.FromSlash(baseDir), filepath.FromSlash(remoteFilePath))
+		if err != nil {
+			return "", fmt.Errorf("import path %q: %w", remoteFilePath, err)
+		}
+		relPath = filepath.ToSlash(rel)
+	}
+	if relPath == "." {
+		return "", fmt.Errorf("import path %q names the workflow directory", remoteFilePath)
+	}
+
+	localPath := filepath.Join(targetDir, filepath.FromSlash(relPath))
+	if relPath == ".." || strings.HasPrefix(relPath, "../") {
+		absTargetDir, err := filepath.Abs(targetDir)
+		if err != nil {
+			return "", fmt.Errorf("import path %q: %w", remoteFilePath, err)
+		}
+		absLocalPath, err := filepath.Abs(localPath)
+		if err != nil {
+			return "", fmt.Errorf("import path %q: %w", remoteFilePath, err)
+		}
+		if parent := filepath.Dir(absTargetDir); absLocalPath == parent || !isWithinDir(parent, absLocalPath) {
+			return "", fmt.Errorf("import path %q is outside %s and cannot be placed relative to %s", remoteFilePath, originalBaseDir, targetDir)
+		}
+	}
+	return localPath, nil
+}
@@ -0,0 +1,133 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveImportLocalPath(t *testing.T) {
+	targetDir := filepath.Join("repo", ".github", "workflows")
+
+	tests := []struct {
+		name            string
+		remoteFilePath  string
+		originalBaseDir string
+		want            string
+		wantErr         string
+	}{
+		{
+			name:            "inside the workflow directory",
+			remoteFilePath:  ".github/workflows/shared/analysis.md",
+			originalBaseDir: ".github/workflows",
+			want:            filepath.Join(targetDir, "shared", "analysis.md"),
+		},
+		{
+			name:            "sibling workflow",
+			remoteFilePath:  "workflows/other.md",
+			originalBaseDir: "workflows",
+			want:            filepath.Join(targetDir, "other.md"),
+		},
+		{
+			name:            "workflow at the repository root",
+			remoteFilePath:  "shared/tools.md",
+			originalBaseDir: "",
+			want:            filepath.Join(targetDir, "shared", "tools.md"),
+		},
+		{
+			name:            "repository root written as dot",
+			remoteFilePath:  "shared/tools.md",
+			originalBaseDir: ".",
+			want:            filepath.Join(targetDir, "shared", "tools.md"),
+		},
+		{
+			name:            "outside a nested workflow directory",
+			remoteFilePath:  ".github/workflows/shared/ci.md",
+			originalBaseDir: ".github/workflows/team",
+			want:            filepath.Join(targetDir, "..", "shared", "ci.md"),
+		},
+		{
+			name:            "next to the workflow directory",
+			remoteFilePath:  ".github/shared/ci.md",
+			originalBaseDir: ".github/workflows",
+			want:            filepath.Join("repo", ".github", "shared", "ci.md"),
+		},
+		{
+			name:            "mirrored location leaves .github",
+			remoteFilePath:  "scripts/helper.md",
+			originalBaseDir: ".github/workflows",
+			wantErr:         "cannot be placed",
+		},
+		{
+			name:            "mirrored location is .github itself",
+			remoteFilePath:  ".github",
+			originalBaseDir: ".github/workflows",
+			wantErr:         "cannot be placed",
+		},
+		{
+			name:            "workflow directory itself",
+			remoteFilePath:  ".github/workflows",
+			originalBaseDir: ".github/workflows",
+			wantErr:         "names the workflow directory",
+		},
+		{
+			name:            "path leaving the repository",
+			remoteFilePath:  "../outside.md",
+			originalBaseDir: ".github/workflows",
+			wantErr:         "inside the repository",
+		},
+		{
+			name:            "absolute path",
+			remoteFilePath:  "/shared/tools.md",
+			originalBaseDir: "",
+			wantErr:         "inside the repository",
+		},
+		{
+			name:            "unclean path",
+			remoteFilePath:  ".github/workflows/../workflows/a.md",
+			originalBaseDir: ".github/workflows",
+			wantErr:         "inside the repository",
+		},
+		{
+			name:            "workflow directory leaving the repository",
+			remoteFilePath:  "shared/tools.md",
+			originalBaseDir: "../workflows",
+			wantErr:         "workflow directory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveImportLocalPath(tt.remoteFilePath, tt.originalBaseDir, targetDir)
+			if tt.wantErr != "" {
+				require.Error(t, err, "import should not be placed")
+				assert.Contains(t, err.Error(), tt.wantErr, "error should explain why")
+				return
+			}
+			require.NoError(t, err, "import should be placed")
+			assert.Equal(t, tt.want, got, "local path should mirror the source layout")
+		})
+	}
+}
+
+func TestFetchAndSaveRemoteFrontmatterImports_RootAbsolute(t *testing.T) {
+	mirrorDir := t.TempDir()
+	mirrorFile := filepath.Join(mirrorDir, "shared", "tools.md")
+	require.NoError(t, os.MkdirAll(filepath.Dir(mirrorFile), 0o755), "mirror directory should be created")
+	require.NoError(t, os.WriteFile(mirrorFile, []byte("# Tools\n"), 0o644), "mirror file should be written")
+	defer parser.SetRepoMirrors(map[string]string{"owner/repo": mirrorDir}, true)()
+
+	targetDir := filepath.Join(t.TempDir(), ".github", "workflows")
+	spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "main"}, WorkflowPath: "workflows/triage.md"}
+
+	installed, err := fetchAndSaveRemoteFrontmatterImports("---\nimports:\n  - /shared/tools.md\n---\n", spec, targetDir, remoteFetchOptions{})
+	require.NoError(t, err, "imports should be fetched")
+	// The compiler resolves root-absolute imports against the workflow's directory
+	assert.Equal(t, []string{filepath.Join(targetDir, "shared", "tools.md")}, installed, "import should be saved where the compiler looks for it")
+}
@@ -2,11 +2,14 @@ package cli
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/BurntSushi/toml"
 	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/constants"
 	"github.com/github/gh-aw/pkg/logger"
@@ -20,13 +23,18 @@ var importsLog = logger.New("cli:imports")
 // Format: owner/repo/path@version (e.g., "github/gh-aw/shared/mcp/arxiv.md@abc123")
 // If commitSHA is provided, it takes precedence over version.
 // If neither is provided, returns the path without a version suffix.
+// A checksum pin on path (path@sha256:<hex>) is kept after the version.
 func buildWorkflowSpecRef(repoSlug, path, commitSHA, version string) string {
+	path, integrity := parser.SplitIncludeIntegrity(path)
 	workflowSpec := repoSlug + "/" + path
 	if commitSHA != "" {
 		workflowSpec += "@" + commitSHA
 	} else if version != "" {
 		workflowSpec += "@" + version
 	}
+	if integrity != "" {
+		workflowSpec += "@" + integrity
+	}
 	return workflowSpec
 }
 
@@ -34,7 +42,7 @@ func buildWorkflowSpecRef(repoSlug, path, commitSHA, version string) string {
 // based on the workflow file's location
 func resolveImportPath(importPath string, workflowPath string) string {
 	// If the import path is already a workflowspec format (contains owner/repo), return as-is
-	if isWorkflowSpecFormat(importPath) {
+	if IsWorkflowSpecFormat(importPath) {
 		return importPath
 	}
 
@@ -99,7 +107,7 @@ func processImportsWithWorkflowSpec(content string, workflow *WorkflowSpec, comm
 	processedImports := make([]string, 0, len(imports))
 	for _, importPath := range imports {
 		// Skip if already a workflowspec
-		if isWorkflowSpecFormat(importPath) {
+		if IsWorkflowSpecFormat(importPath) {
 			importsLog.Printf("Import already in workflowspec format: %s", importPath)
 			processedImports = append(processedImports, importPath)
 			continue
@@ -120,30 +128,76 @@ func processImportsWithWorkflowSpec(content string, workflow *WorkflowSpec, comm
 	result.Frontmatter["imports"] = processedImports
 
 	// Use helper function to reconstruct workflow file with proper field ordering
-	return reconstructWorkflowFileFromMap(result.Frontmatter, result.Markdown)
+	return reconstructWorkflowFileFromMap(result.Format, result.Frontmatter, result.Markdown)
 }
 
-// reconstructWorkflowFileFromMap reconstructs a workflow file from frontmatter map and markdown
-// using proper field ordering and YAML helpers
-func reconstructWorkflowFileFromMap(frontmatter map[string]any, markdown string) (string, error) {
-	// Convert frontmatter to YAML with proper field ordering
-	// Use PriorityWorkflowFields to ensure consistent ordering of top-level fields
-	updatedFrontmatter, err := workflow.MarshalWithFieldOrder(frontmatter, constants.PriorityWorkflowFields)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal frontmatter: %w", err)
+// verifyFrontmatterImportsExist checks that every local import declared in the frontmatter of
+// content resolves to an existing file, relative to workflowFile as the compiler resolves it.
+// Fetching imports is best-effort, so this turns an import that failed to download into a
+// precise error instead of a confusing compile failure. Workflowspec imports are not checked.
+func verifyFrontmatterImportsExist(content, workflowFile string) error {
+	result, err := parser.ExtractFrontmatterFromContent(content)
+	if err != nil || result.Frontmatter == nil {
+		return nil
 	}
 
-	// Clean up the YAML - remove trailing newline and unquote the "on" key
-	frontmatterStr := strings.TrimSuffix(string(updatedFrontmatter), "\n")
-	frontmatterStr = workflow.UnquoteYAMLKey(frontmatterStr, "on")
+	var importPaths []string
+	switch v := result.Frontmatter["imports"].(type) {
+	case []any:
+		for _, item := range v {
+			switch importItem := item.(type) {
+			case string:
+				importPaths = append(importPaths, importItem)
+			case map[string]any:
+				if s, ok := importItem["path"].(string); ok {
+					importPaths = append(importPaths, s)
+				}
+			}
+		}
+	case []string:
+		importPaths = v
+	case map[string]any:
+		// Alias form: the workflow imports the local paths
+		for _, imp := range parseFrontmatterImports(v) {
+			importPaths = append(importPaths, imp.localPath)
+		}
+	}
+
+	baseDir := filepath.Dir(workflowFile)
+	for _, importPath := range importPaths {
+		if IsWorkflowSpecFormat(importPath) {
+			continue
+		}
+		filePath, _, _ := strings.Cut(importPath, "#")
+		filePath, _ = parser.SplitIncludeIntegrity(filePath)
+		if filePath == "" {
+			continue
+		}
+		fullPath := filepath.Join(baseDir, filepath.FromSlash(filePath))
+		if _, err := os.Stat(fullPath); os.IsNotExist(err) {
+			importsLog.Printf("Import %s of %s is missing at %s", importPath, workflowFile, fullPath)
+			return fmt.Errorf("import %s referenced but not found at path %s", importPath, fullPath)
+		}
+	}
+	return nil
+}
+
+// reconstructWorkflowFileFromMap reconstructs a workflow file from frontmatter map and markdown,
+// writing the frontmatter in format (YAML when empty) with proper field ordering and YAML helpers
+func reconstructWorkflowFileFromMap(format parser.FrontmatterFormat, frontmatter map[string]any, markdown string) (string, error) {
+	frontmatterStr, err := marshalFrontmatter(format, frontmatter)
+	if err != nil {
+		return "", err
+	}
 
 	// Reconstruct the file
+	opening, closing := format.Fences()
 	var lines []string
-	lines = append(lines, "---")
+	lines = append(lines, opening)
 	if frontmatterStr != "" {
 		lines = append(lines, strings.Split(frontmatterStr, "\n")...)
 	}
-	lines = append(lines, "---")
+	lines = append(lines, closing)
 	if markdown != "" {
 		lines = append(lines, markdown)
 	}
@@ -151,8 +205,44 @@ func reconstructWorkflowFileFromMap(frontmatter map[string]any, markdown string)
 	return strings.Join(lines, "\n"), nil
 }
 
+// marshalFrontmatter renders frontmatter in format, without a trailing newline. YAML puts the
+// priority workflow fields first, while the TOML and JSON encoders sort keys.
+func marshalFrontmatter(format parser.FrontmatterFormat, frontmatter map[string]any) (string, error) {
+	switch format {
+	case parser.FrontmatterFormatTOML:
+		var buf bytes.Buffer
+		encoder := toml.NewEncoder(&buf)
+		encoder.Indent = ""
+		if err := encoder.Encode(frontmatter); err != nil {
+			return "", fmt.Errorf("failed to marshal frontmatter: %w", err)
+		}
+		return strings.TrimSuffix(buf.String(), "\n"), nil
+	case parser.FrontmatterFormatJSON:
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(frontmatter); err != nil {
+			return "", fmt.Errorf("failed to marshal frontmatter: %w", err)
+		}
+		return strings.TrimSuffix(buf.String(), "\n"), nil
+	}
+
+	// Convert frontmatter to YAML with proper field ordering
+	// Use PriorityWorkflowFields to ensure consistent ordering of top-level fields
+	updatedFrontmatter, err := workflow.MarshalWithFieldOrder(frontmatter, constants.PriorityWorkflowFields)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal frontmatter: %w", err)
+	}
+
+	// Clean up the YAML - remove trailing newline and unquote the "on" key
+	frontmatterStr := strings.TrimSuffix(string(updatedFrontmatter), "\n")
+	return workflow.UnquoteYAMLKey(frontmatterStr, "on"), nil
+}
+
 // processIncludesWithWorkflowSpec processes @include directives in content and replaces local file references
-// with workflowspec format (owner/repo/path@sha) for all includes found in the package
+// with workflowspec format (owner/repo/path@sha) for all includes found in the package.
+// HTTP(S) includes are replaced with the paths of the local copies saved by fetchAndSaveRemoteIncludes.
 func processIncludesWithWorkflowSpec(content string, workflow *WorkflowSpec, commitSHA, packagePath string, verbose bool) (string, error) {
 	importsLog.Printf("Processing @include directives: repo=%s, sha=%s, package=%s", workflow.RepoSlug, commitSHA, packagePath)
 	if verbose {
@@ -200,6 +290,16 @@ func processIncludesWithWorkflowSpec(content string, workflow *WorkflowSpec, com
 				continue
 			}
 
+			// HTTP(S) includes were saved locally by 'add'; point at the local copy
+			if localPath, ok := localHTTPIncludePath(includePath, directive.Target, "", ""); ok {
+				if isOptional {
+					result.WriteString("{{#import? " + localPath + "}}\n")
+				} else {
+					result.WriteString("{{#import " + localPath + "}}\n")
+				}
+				continue
+			}
+
 			// Check for cycle detection
 			if visited[filePath] {
 				if verbose {
@@ -218,6 +318,10 @@ func processIncludesWithWorkflowSpec(content string, workflow *WorkflowSpec, com
 			if sectionName != "" {
 				workflowSpec += "#" + sectionName
 			}
+			// Keep the local target override so the saved file stays referenced
+			if directive.Target != "" {
+				workflowSpec += " -> " + directive.Target
+			}
 
 			// Write the updated @include directive
 			if isOptional {
@@ -329,7 +433,7 @@ func processIncludesInContent(content string, workflow *WorkflowSpec, commitSHA
 			includePath := directive.Path
 
 			// Skip if it's already a workflowspec (contains repo/path format)
-			if isWorkflowSpecFormat(includePath) {
+			if IsWorkflowSpecFormat(includePath) {
 				result.WriteString(line + "\n")
 				continue
 			}
@@ -363,6 +467,10 @@ func processIncludesInContent(content string, workflow *WorkflowSpec, commitSHA
 			if sectionName != "" {
 				workflowSpec += "#" + sectionName
 			}
+			// Keep the local target override so the saved file stays referenced
+			if directive.Target != "" {
+				workflowSpec += " -> " + directive.Target
+			}
 
 			// Write the updated import directive
 			if isOptional {
@@ -378,12 +486,3 @@ func processIncludesInContent(content string, workflow *WorkflowSpec, commitSHA
 
 	return result.String(), scanner.Err()
 }
-
-// isWorkflowSpecFormat checks if a path already looks like a workflowspec
-// A workflowspec is identified by having an @ version indicator (e.g., owner/repo/path@sha)
-// Simple paths like "shared/mcp/file.md" are NOT workflowspecs and should be processed
-func isWorkflowSpecFormat(path string) bool {
-	// The only reliable indicator of a workflowspec is the @ version separator
-	// Paths like "shared/mcp/arxiv.md" should be treated as local paths, not workflowspecs
-	return strings.Contains(path, "@")
-}
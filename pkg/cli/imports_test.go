@@ -3,8 +3,12 @@
 package cli
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/github/gh-aw/pkg/parser"
 )
 
 func TestProcessIncludesWithWorkflowSpec_NewSyntax(t *testing.T) {
@@ -318,9 +322,9 @@ func TestIsWorkflowSpecFormat(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := isWorkflowSpecFormat(tt.path)
+			result := IsWorkflowSpecFormat(tt.path)
 			if result != tt.expected {
-				t.Errorf("isWorkflowSpecFormat(%q) = %v, want %v", tt.path, result, tt.expected)
+				t.Errorf("IsWorkflowSpecFormat(%q) = %v, want %v", tt.path, result, tt.expected)
 			}
 		})
 	}
@@ -383,3 +387,119 @@ Test content.
 		}
 	}
 }
+
+func TestVerifyFrontmatterImportsExist(t *testing.T) {
+	workflowsDir := filepath.Join(t.TempDir(), ".github", "workflows")
+	if err := os.MkdirAll(filepath.Join(workflowsDir, "shared"), 0755); err != nil {
+		t.Fatalf("Failed to create shared directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(workflowsDir, "shared", "present.md"), []byte("# Present\n"), 0644); err != nil {
+		t.Fatalf("Failed to write import: %v", err)
+	}
+	workflowFile := filepath.Join(workflowsDir, "workflow.md")
+
+	tests := []struct {
+		name        string
+		imports     string
+		expectedErr string
+	}{
+		{
+			name:    "existing import and workflowspec",
+			imports: "imports:\n  - shared/present.md#Section\n  - github/gh-aw/shared/missing.md@main\n",
+		},
+		{
+			name:    "existing object import",
+			imports: "imports:\n  - path: shared/present.md\n",
+		},
+		{
+			name:    "existing alias",
+			imports: "imports:\n  shared/present.md: upstream/present.md\n",
+		},
+		{
+			name:        "missing import",
+			imports:     "imports:\n  - shared/present.md\n  - shared/missing.md\n",
+			expectedErr: "import shared/missing.md referenced but not found at path " + filepath.Join(workflowsDir, "shared", "missing.md"),
+		},
+		{
+			name:        "missing alias",
+			imports:     "imports:\n  shared/alias.md: upstream/alias.md\n",
+			expectedErr: "import shared/alias.md referenced but not found at path " + filepath.Join(workflowsDir, "shared", "alias.md"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := "---\non: push\n" + tt.imports + "---\n\n# Workflow\n"
+			err := verifyFrontmatterImportsExist(content, workflowFile)
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.expectedErr {
+				t.Errorf("Expected error %q, got: %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestProcessIncludesWithWorkflowSpec_TargetOverride(t *testing.T) {
+	workflow := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "githubnext/agentics", Version: "main"}}
+
+	result, err := processIncludesWithWorkflowSpec("@include shared/tools.md#Usage -> custom/tools.md\n", workflow, "", "", false)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// The target override is kept after the workflowspec so the saved file stays referenced
+	expected := "{{#import githubnext/agentics/shared/tools.md@main#Usage -> custom/tools.md}}\n"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestProcessImportsWithWorkflowSpec_KeepsFrontmatterFormat(t *testing.T) {
+	workflow := &WorkflowSpec{
+		RepoSpec:     RepoSpec{RepoSlug: "github/gh-aw", Version: "main"},
+		WorkflowPath: ".github/workflows/test-workflow.md",
+	}
+
+	tests := []struct {
+		name    string
+		format  parser.FrontmatterFormat
+		content string
+	}{
+		{
+			name:    "TOML",
+			format:  parser.FrontmatterFormatTOML,
+			content: "+++\nengine = \"copilot\"\nimports = [\"shared/reporting.md\"]\n+++\n\n# Test Workflow",
+		},
+		{
+			name:    "JSON",
+			format:  parser.FrontmatterFormatJSON,
+			content: "```json\n{\"engine\": \"copilot\", \"imports\": [\"shared/reporting.md\"]}\n```\n\n# Test Workflow",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := processImportsWithWorkflowSpec(tt.content, workflow, "abc123def456", false)
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			parsed, err := parser.ExtractFrontmatterFromContent(result)
+			if err != nil {
+				t.Fatalf("Rewritten workflow should parse, got %v:\n%s", err, result)
+			}
+			if parsed.Format != tt.format {
+				t.Errorf("Expected %s frontmatter, got %q:\n%s", tt.format, parsed.Format, result)
+			}
+			imports, _ := parsed.Frontmatter["imports"].([]any)
+			if len(imports) != 1 || imports[0] != "github/gh-aw/.github/workflows/shared/reporting.md@abc123def456" {
+				t.Errorf("Expected import to be converted to a workflowspec, got %v", parsed.Frontmatter["imports"])
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var inlineIncludesLog = logger.New("cli:inline_includes")
+
+// inlineRemoteIncludes replaces each @include directive in a remote workflow with the markdown of
+// the file it references, producing a single self-contained workflow. Section includes
+// (file.md#Section) inline just that section, directory includes inline every .md file in the
+// directory, and includes inside fetched files are inlined recursively. Optional includes that
+// cannot be fetched are dropped, as are missing includes reached only through optional ones.
+//
+// Conditional includes (@include[engine=...]) are kept as directives, to be fetched separately,
+// because the condition is only evaluated at compile time. Includes of files with frontmatter
+// are kept as directives too, so their tools and other settings still merge at compile time;
+// both are reported as warnings. At most opts.MaxIncludeFiles files are fetched in total
+// (defaultMaxIncludeFiles when it is 0 or less).
+func inlineRemoteIncludes(content string, spec *WorkflowSpec, opts remoteFetchOptions) (string, error) {
+	inlineIncludesLog.Printf("Inlining remote includes for workflow: %s", spec.String())
+	return inlineIncludesRecursive(content, spec, newIncludeFetchBudget(opts.MaxIncludeFiles), nil, false, opts)
+}
+
+// inlineIncludesRecursive inlines the includes of content, resolving relative includes against
+// spec, the location of content in its source repository. active holds the include files
+// currently being inlined, outermost first, to detect include cycles. When parentOptional is
+// set, content was reached only through optional includes and all of its includes are optional.
+func inlineIncludesRecursive(content string, spec *WorkflowSpec, budget *includeFetchBudget, active []string, parentOptional bool, opts remoteFetchOptions) (string, error) {
+	var builder strings.Builder
+	lines := strings.SplitAfter(content, "\n")
+	for _, line := range lines {
+		matches := remoteIncludePattern.FindStringSubmatch(strings.TrimRight(line, "\r\n"))
+		if matches == nil {
+			builder.WriteString(line)
+			continue
+		}
+
+		optional := parentOptional || matches[1] == "?"
+		condition := strings.TrimSpace(matches[2])
+		// A target override only matters for saved includes; inlined content has no file
+		includePath, _ := parser.SplitIncludeTarget(strings.TrimSpace(matches[3]))
+
+		if condition != "" {
+			opts.OnEvent.emit(FetchEventWarning, fmt.Sprintf("Conditional include %s was not inlined; it is evaluated at compile time", includePath), includePath)
+			builder.WriteString(line)
+			continue
+		}
+
+		filePath, _, _ := strings.Cut(includePath, "#")
+		includePaths := []string{includePath}
+		if isDirectoryInclude(filePath) {
+			if err := checkDirectoryIncludeUnpinned(filePath); err != nil {
+				return "", err
+			}
+			files, err := listRemoteIncludeDirectory(filePath, spec, opts)
+			if err != nil {
+				if optional && !isFatalFetchError(err) {
+					if opts.LogLevel.showInfo() {
+						opts.OnEvent.emit(FetchEventWarning, "Optional include directory not found: "+filePath, filePath)
+					}
+					continue
+				}
+				return "", fmt.Errorf("failed to list include directory %s: %w", filePath, err)
+			}
+			includePaths = files
+		}
+
+		for _, path := range includePaths {
+			markdown, err := inlineInclude(path, optional, spec, budget, active, opts)
+			if err != nil {
+				return "", err
+			}
+			if markdown == "" {
+				continue
+			}
+			builder.WriteString(markdown)
+			if !strings.HasSuffix(markdown, "\n") {
+				builder.WriteString("\n")
+			}
+		}
+	}
+
+	return builder.String(), nil
+}
+
+// inlineInclude fetches a single include file and returns its markdown, scoped to the
+// requested section and with its own includes inlined. An optional include that cannot be
+// fetched yields an empty string. A file with frontmatter yields an @include directive for it
+// instead, which resolves against the workflow: includes inside inlined files are rewritten to
+// the location the file was fetched from.
+func inlineInclude(includePath string, optional bool, spec *WorkflowSpec, budget *includeFetchBudget, active []string, opts remoteFetchOptions) (string, error) {
+	filePath, section, _ := strings.Cut(includePath, "#")
+	if slices.Contains(active, filePath) {
+		return "", fmt.Errorf("include cycle detected: %s -> %s", strings.Join(active, " -> "), filePath)
+	}
+
+	if err := budget.take(includePath); err != nil {
+		return "", err
+	}
+
+	result, err := fetchIncludeFromSource(includePath, spec, opts)
+	if err != nil {
+		if optional && !isFatalFetchError(err) {
+			if opts.LogLevel.showInfo() {
+				opts.OnEvent.emit(FetchEventWarning, "Optional include not found: "+includePath, includePath)
+			}
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to fetch include %s: %w", includePath, err)
+	}
+
+	extracted, err := parser.ExtractFrontmatterFromContent(string(result.Content))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse include %s: %w", filePath, err)
+	}
+	if len(extracted.Frontmatter) > 0 {
+		opts.OnEvent.emit(FetchEventWarning, fmt.Sprintf("Include %s has frontmatter and was kept as a separate file instead of being inlined", filePath), filePath)
+		directive := "@include "
+		if optional {
+			directive = "@include? "
+		}
+		if len(active) == 0 {
+			return directive + includePath, nil
+		}
+		keptPath := result.ResolvedPath
+		if _, integrity := parser.SplitIncludeIntegrity(filePath); integrity != "" {
+			keptPath += "@" + integrity
+		}
+		if section != "" {
+			keptPath += "#" + section
+		}
+		return directive + keptPath, nil
+	}
+
+	markdown := extracted.Markdown
+	if section != "" {
+		markdown, err = parser.ExtractMarkdownSection(markdown, section)
+		if err != nil {
+			return "", fmt.Errorf("failed to extract section from include %s: %w", filePath, err)
+		}
+	}
+
+	inlineIncludesLog.Printf("Inlining %s from %s", includePath, result.ResolvedPath)
+	return inlineIncludesRecursive(strings.TrimSpace(markdown)+"\n", includedFileSpec(result, spec), budget, append(active, filePath), optional, opts)
+}
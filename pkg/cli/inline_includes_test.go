@@ -0,0 +1,118 @@
+//go:build !integration
+
+package cli
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubIncludeFiles replaces downloadIncludeFile with a lookup in files, keyed by
+// "owner/repo/path@ref", and resolves refs to fakeCommitSHA
+func stubIncludeFiles(t *testing.T, files map[string]string) {
+	t.Helper()
+	original := downloadIncludeFile
+	originalResolve := resolveIncludeRef
+	t.Cleanup(func() {
+		downloadIncludeFile = original
+		resolveIncludeRef = originalResolve
+		includeCommitCache.Clear()
+	})
+	includeCommitCache.Clear()
+	resolveIncludeRef = func(_ *parser.Fetcher, owner, repo, ref string) (string, error) {
+		return fakeCommitSHA(owner + "/" + repo + "@" + ref), nil
+	}
+	downloadIncludeFile = func(_ *parser.Fetcher, owner, repo, path, ref string, maxSize int64) ([]byte, error) {
+		content, ok := files[owner+"/"+repo+"/"+path+"@"+ref]
+		if !ok {
+			return nil, errors.New("not found")
+		}
+		return []byte(content), nil
+	}
+}
+
+// fakeCommitSHA returns a stable 40-character commit SHA for repoRef ("owner/repo@ref")
+func fakeCommitSHA(repoRef string) string {
+	sum := sha1.Sum([]byte(repoRef))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestInlineRemoteIncludes(t *testing.T) {
+	spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "v1"}, WorkflowPath: "workflows/triage.md"}
+
+	t.Run("inlines sections and nested includes", func(t *testing.T) {
+		stubIncludeFiles(t, map[string]string{
+			"owner/repo/.github/shared/tools.md@v1": "# Usage\n\nUse the tools.\n\n@include shared/tips.md\n\n# Internals\n\nNot inlined.\n",
+			"owner/repo/.github/shared/tips.md@v1":  "Be concise.\n",
+			"other/lib/prompts/style.md@v2":         "# Style\n\nWrite clearly.\n",
+		})
+
+		content := "---\non: issues\n---\n\n# Triage\n\n@include shared/tools.md#Usage\n@include? shared/missing.md\n@include[engine=copilot] shared/copilot.md\n@include other/lib/prompts/style.md@v2\n"
+		inlined, err := inlineRemoteIncludes(content, spec, remoteFetchOptions{})
+		require.NoError(t, err, "includes should be inlined")
+
+		expected := "---\non: issues\n---\n\n# Triage\n\n# Usage\n\nUse the tools.\n\nBe concise.\n@include[engine=copilot] shared/copilot.md\n# Style\n\nWrite clearly.\n"
+		assert.Equal(t, expected, inlined, "directives should be replaced with the fetched markdown")
+	})
+
+	t.Run("includes with frontmatter are kept", func(t *testing.T) {
+		var warnings []string
+		handler := func(event FetchEvent) {
+			if event.Level == FetchEventWarning {
+				warnings = append(warnings, event.Message)
+			}
+		}
+		stubIncludeFiles(t, map[string]string{
+			"owner/repo/.github/shared/tools.md@v1": "---\ntools:\n  github:\n---\n\n# Usage\n\nUse the tools.\n",
+			"other/lib/prompts/style.md@v2":         "# Style\n\n@include? parts/mcp.md#Setup\n",
+			"other/lib/prompts/parts/mcp.md@v2":     "---\nmcp-servers:\n  docs:\n    url: https://example.com\n---\n\n# Setup\n",
+		})
+
+		content := "@include shared/tools.md#Usage\n@include other/lib/prompts/style.md@v2\n"
+		inlined, err := inlineRemoteIncludes(content, spec, remoteFetchOptions{OnEvent: handler})
+		require.NoError(t, err, "includes should be inlined")
+
+		expected := "@include shared/tools.md#Usage\n# Style\n\n@include? other/lib/prompts/parts/mcp.md@v2#Setup\n"
+		assert.Equal(t, expected, inlined, "includes with frontmatter should stay directives that resolve against the workflow")
+		assert.Contains(t, warnings, "Include shared/tools.md has frontmatter and was kept as a separate file instead of being inlined", "kept include should be reported")
+	})
+
+	t.Run("missing required include", func(t *testing.T) {
+		stubIncludeFiles(t, map[string]string{})
+
+		_, err := inlineRemoteIncludes("@include shared/missing.md\n", spec, remoteFetchOptions{})
+		require.Error(t, err, "missing required include should fail")
+		assert.Contains(t, err.Error(), "shared/missing.md", "error should name the include")
+	})
+
+	t.Run("missing include below an optional include", func(t *testing.T) {
+		stubIncludeFiles(t, map[string]string{
+			"owner/repo/.github/shared/extras.md@v1": "Extras.\n\n@include shared/missing.md\n",
+			"owner/repo/.github/shared/tools.md@v1":  "Tools.\n\n@include shared/missing.md\n",
+		})
+
+		inlined, err := inlineRemoteIncludes("@include? shared/extras.md\n", spec, remoteFetchOptions{})
+		require.NoError(t, err, "includes below an optional include should be optional")
+		assert.Equal(t, "Extras.\n\n", inlined, "missing nested include should be dropped")
+
+		_, err = inlineRemoteIncludes("@include shared/tools.md\n", spec, remoteFetchOptions{})
+		require.Error(t, err, "includes below a required include should stay required")
+	})
+
+	t.Run("include cycle", func(t *testing.T) {
+		stubIncludeFiles(t, map[string]string{
+			"owner/repo/.github/shared/a.md@v1": "@include shared/b.md\n",
+			"owner/repo/.github/shared/b.md@v1": "@include shared/a.md\n",
+		})
+
+		_, err := inlineRemoteIncludes("@include shared/a.md\n", spec, remoteFetchOptions{})
+		require.Error(t, err, "cyclic includes should fail")
+		assert.Contains(t, err.Error(), "include cycle detected: shared/a.md -> shared/b.md -> shared/a.md", "error should show the cycle")
+	})
+}
@@ -55,7 +55,7 @@ This is a test workflow.
 	}
 
 	// Verify the spec
-	if !isLocalWorkflowPath(spec.WorkflowPath) {
+	if !IsLocalWorkflowPath(spec.WorkflowPath) {
 		t.Errorf("Expected WorkflowPath to be a local path, got: %s", spec.WorkflowPath)
 	}
 
@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var pinRefsLog = logger.New("cli:pin_refs")
+
+// importSpecPattern matches a workflowspec with a ref and optional section fragment inside a
+// frontmatter imports entry, e.g. "owner/repo/shared/tools.md@main#Usage"
+var importSpecPattern = regexp.MustCompile(`[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+/[^\s"'\[\],#@]+@[A-Za-z0-9_./-]+(?:#[^\s"'\[\],]*)?`)
+
+// importsKeyPattern matches the top-level imports key, with or without an inline value
+var importsKeyPattern = regexp.MustCompile(`^imports:`)
+
+// RefPin describes a workflowspec rewritten from a floating ref to a commit SHA
+type RefPin struct {
+	From string // Spec as written, e.g. "owner/repo/shared/tools.md@main#Usage"
+	To   string // Pinned spec, e.g. "owner/repo/shared/tools.md@<sha>#Usage"
+}
+
+// PinWorkflowRefs rewrites the workflowspecs in the frontmatter imports field and in
+// include/import directives so that floating refs (branches and tags) point at commit SHAs.
+// resolved maps "owner/repo@ref" to a commit SHA, as returned by FetchLock.ResolvedRefs.
+// Only the ref is replaced: section fragments, quoting, and all other formatting are kept,
+// and specs whose ref is not in resolved are left as-is.
+func PinWorkflowRefs(content string, resolved map[string]string) (string, []RefPin) {
+	if len(resolved) == 0 {
+		return content, nil
+	}
+
+	var pins []RefPin
+	pin := func(line, spec string) string {
+		pinned, ok := pinWorkflowSpec(spec, resolved)
+		if !ok {
+			return line
+		}
+		pins = append(pins, RefPin{From: spec, To: pinned})
+		return strings.Replace(line, spec, pinned, 1)
+	}
+
+	lines := strings.Split(content, "\n")
+	format, frontmatterEnd := parser.FrontmatterBounds(lines)
+	if format == "" {
+		frontmatterEnd = 0
+	} else if frontmatterEnd == -1 {
+		// Unclosed frontmatter runs to the end of the content
+		frontmatterEnd = len(lines)
+	}
+
+	// TOML and JSON imports are not a YAML block, so their specs are found by value
+	var importSpecs map[string]bool
+	if format == parser.FrontmatterFormatTOML || format == parser.FrontmatterFormatJSON {
+		importSpecs = frontmatterImportSpecs(content)
+	}
+
+	inImports := false
+	for i, line := range lines {
+		trimmed := strings.TrimRight(line, "\r")
+		if format != "" && (i == 0 || i == frontmatterEnd) {
+			// Skip the frontmatter fences
+			continue
+		}
+		if i < frontmatterEnd {
+			if importSpecs != nil {
+				for _, spec := range importSpecPattern.FindAllString(trimmed, -1) {
+					if importSpecs[spec] {
+						line = pin(line, spec)
+					}
+				}
+				lines[i] = line
+				continue
+			}
+			// The imports block runs from the top-level key until the next top-level key
+			if importsKeyPattern.MatchString(trimmed) {
+				inImports = true
+			} else if trimmed != "" && !strings.HasPrefix(trimmed, " ") && !strings.HasPrefix(trimmed, "\t") && !strings.HasPrefix(trimmed, "-") {
+				inImports = false
+			}
+			if inImports {
+				for _, spec := range importSpecPattern.FindAllString(trimmed, -1) {
+					line = pin(line, spec)
+				}
+				lines[i] = line
+			}
+			continue
+		}
+
+		if directive := parser.ParseImportDirective(trimmed); directive != nil {
+			lines[i] = pin(line, directive.Path)
+		}
+	}
+
+	pinRefsLog.Printf("Pinned %d workflow refs", len(pins))
+	return strings.Join(lines, "\n"), pins
+}
+
+// frontmatterImportSpecs returns the import paths declared in the frontmatter imports field
+// of content
+func frontmatterImportSpecs(content string) map[string]bool {
+	specs := make(map[string]bool)
+	result, err := parser.ExtractFrontmatterFromContent(content)
+	if err != nil {
+		return specs
+	}
+	if items, ok := result.Frontmatter["imports"].([]any); ok {
+		for _, item := range items {
+			switch importItem := item.(type) {
+			case string:
+				specs[importItem] = true
+			case map[string]any:
+				if s, ok := importItem["path"].(string); ok {
+					specs[s] = true
+				}
+			}
+		}
+	}
+	return specs
+}
+
+// pinWorkflowSpec returns spec with its ref replaced by the resolved commit SHA, keeping any
+// section fragment. It reports false when spec is not a workflowspec, is already pinned to a
+// commit SHA, or its ref has no resolved commit.
+func pinWorkflowSpec(spec string, resolved map[string]string) (string, bool) {
+	specWithoutSection, section, hasSection := strings.Cut(spec, "#")
+	pathPart, ref, ok := strings.Cut(specWithoutSection, "@")
+	if !ok || ref == "" || IsCommitSHA(ref) {
+		return "", false
+	}
+	parts := strings.SplitN(pathPart, "/", 3)
+	if len(parts) < 3 {
+		return "", false
+	}
+	sha, ok := resolved[parts[0]+"/"+parts[1]+"@"+ref]
+	if !ok {
+		return "", false
+	}
+
+	pinned := pathPart + "@" + sha
+	if hasSection {
+		pinned += "#" + section
+	}
+	return pinned, true
+}
+
+// pinWorkflowRefsFromFetchLock pins the refs in content to the commits recorded in the
+// fetch lock of gitRoot, reporting each rewritten reference when verbose
+func pinWorkflowRefsFromFetchLock(content, gitRoot string, verbose bool) (string, error) {
+	lock, err := loadFetchLock(gitRoot)
+	if err != nil {
+		return content, err
+	}
+
+	pinned, pins := PinWorkflowRefs(content, lock.ResolvedRefs())
+	if verbose {
+		for _, p := range pins {
+			fmt.Fprintln(os.Stderr, console.FormatVerboseMessage(fmt.Sprintf("Pinned %s -> %s", p.From, p.To)))
+		}
+	}
+	return pinned, nil
+}
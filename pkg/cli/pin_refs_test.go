@@ -0,0 +1,113 @@
+//go:build !integration
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPinWorkflowRefs(t *testing.T) {
+	const mainSHA = "1111111111111111111111111111111111111111"
+	const tagSHA = "2222222222222222222222222222222222222222"
+	resolved := map[string]string{
+		"acme/shared@main": mainSHA,
+		"acme/shared@v1":   tagSHA,
+	}
+
+	content := `---
+on: issues
+source: acme/shared/workflows/triage.md@main
+imports:
+  - shared/local.md
+  - acme/shared/tools.md@main
+  - "acme/shared/mcp.md@v1#Setup"
+  - path: acme/shared/inputs.md@main
+    inputs:
+      count: 3
+  - other/repo/tools.md@main
+tools:
+  github:
+---
+
+# Triage
+
+{{#import acme/shared/tips.md@main#Usage}}
+@include? acme/shared/optional.md@v1
+{{#import acme/shared/pinned.md@` + tagSHA + `}}
+Mentions acme/shared/tips.md@main in prose stay unchanged.
+`
+
+	want := `---
+on: issues
+source: acme/shared/workflows/triage.md@main
+imports:
+  - shared/local.md
+  - acme/shared/tools.md@` + mainSHA + `
+  - "acme/shared/mcp.md@` + tagSHA + `#Setup"
+  - path: acme/shared/inputs.md@` + mainSHA + `
+    inputs:
+      count: 3
+  - other/repo/tools.md@main
+tools:
+  github:
+---
+
+# Triage
+
+{{#import acme/shared/tips.md@` + mainSHA + `#Usage}}
+@include? acme/shared/optional.md@` + tagSHA + `
+{{#import acme/shared/pinned.md@` + tagSHA + `}}
+Mentions acme/shared/tips.md@main in prose stay unchanged.
+`
+
+	got, pins := PinWorkflowRefs(content, resolved)
+	assert.Equal(t, want, got, "only import and include refs with a resolved commit should be pinned")
+	assert.Len(t, pins, 5, "each rewritten reference should be reported")
+	assert.Equal(t, RefPin{From: "acme/shared/tips.md@main#Usage", To: "acme/shared/tips.md@" + mainSHA + "#Usage"}, pins[3],
+		"section fragments should be preserved")
+
+	unchanged, pins := PinWorkflowRefs(content, nil)
+	assert.Equal(t, content, unchanged, "content should be unchanged without resolved refs")
+	assert.Empty(t, pins, "nothing should be pinned without resolved refs")
+}
+
+func TestPinWorkflowRefsPreservesCRLF(t *testing.T) {
+	const sha = "3333333333333333333333333333333333333333"
+	content := "---\r\non: push\r\nimports:\r\n  - acme/shared/tools.md@main\r\n---\r\n@include acme/shared/tips.md@main\r\n"
+	want := "---\r\non: push\r\nimports:\r\n  - acme/shared/tools.md@" + sha + "\r\n---\r\n@include acme/shared/tips.md@" + sha + "\r\n"
+
+	got, _ := PinWorkflowRefs(content, map[string]string{"acme/shared@main": sha})
+	assert.Equal(t, want, got, "line endings should be preserved")
+}
+
+func TestPinWorkflowRefsTOMLAndJSON(t *testing.T) {
+	const sha = "4444444444444444444444444444444444444444"
+	resolved := map[string]string{"acme/shared@main": sha}
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "TOML",
+			content: "+++\nsource = \"acme/shared/workflows/triage.md@main\"\nimports = [\n  \"acme/shared/tools.md@main\",\n  \"shared/local.md\",\n]\n+++\n@include acme/shared/tips.md@main\n",
+			want:    "+++\nsource = \"acme/shared/workflows/triage.md@main\"\nimports = [\n  \"acme/shared/tools.md@" + sha + "\",\n  \"shared/local.md\",\n]\n+++\n@include acme/shared/tips.md@" + sha + "\n",
+		},
+		{
+			name:    "JSON",
+			content: "```json\n{\"source\": \"acme/shared/workflows/triage.md@main\", \"imports\": [\"acme/shared/tools.md@main\"]}\n```\n@include acme/shared/tips.md@main\n",
+			want:    "```json\n{\"source\": \"acme/shared/workflows/triage.md@main\", \"imports\": [\"acme/shared/tools.md@" + sha + "\"]}\n```\n@include acme/shared/tips.md@" + sha + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, pins := PinWorkflowRefs(tt.content, resolved)
+			assert.Equal(t, tt.want, got, "imports and include refs should be pinned, and other fields left as-is")
+			assert.Len(t, pins, 2, "each rewritten reference should be reported")
+		})
+	}
+}
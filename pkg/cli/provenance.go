@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var provenanceLog = logger.New("cli:provenance")
+
+// provenanceFileSuffix is appended to the workflow name to form the provenance file
+// written next to the workflow, e.g. ci-doctor.provenance.json
+const provenanceFileSuffix = ".provenance.json"
+
+// WorkflowProvenance records where an added workflow and its dependencies came from
+type WorkflowProvenance struct {
+	Source        string           `json:"source"`               // Workflow spec the workflow was added from
+	CommitSHA     string           `json:"commit_sha,omitempty"` // Commit the spec resolved to (empty for local workflows)
+	FetchedAt     string           `json:"fetched_at"`           // RFC 3339 time the workflow was fetched
+	ContentSHA256 string           `json:"content_sha256"`       // SHA-256 of the workflow content as fetched
+	Files         []ProvenanceFile `json:"files,omitempty"`      // Include and import files installed with the workflow
+}
+
+// ProvenanceFile describes an include or import file installed with a workflow
+type ProvenanceFile struct {
+	Path      string `json:"path"`                 // Path relative to the git root, using forward slashes
+	Source    string `json:"source,omitempty"`     // Remote location the file was fetched from (owner/repo/path@ref)
+	BlobSHA   string `json:"blob_sha,omitempty"`   // Git blob SHA of the content written locally
+	CommitSHA string `json:"commit_sha,omitempty"` // Commit the source ref resolved to when the file was fetched
+}
+
+// provenancePath returns the location of the provenance file for a workflow file
+func provenancePath(workflowFile string) string {
+	return strings.TrimSuffix(workflowFile, ".md") + provenanceFileSuffix
+}
+
+// buildWorkflowProvenance collects the provenance of a fetched workflow. Sources and commits
+// of the installed files are taken from the fetch lock; files missing from it (e.g. copied
+// for local workflows) are described by their local content only.
+func buildWorkflowProvenance(gitRoot string, spec *WorkflowSpec, fetched *FetchedWorkflow) *WorkflowProvenance {
+	provenance := &WorkflowProvenance{
+		Source:        spec.String(),
+		CommitSHA:     fetched.CommitSHA,
+		FetchedAt:     time.Now().UTC().Format(time.RFC3339),
+		ContentSHA256: fetched.ContentSHA256,
+	}
+
+	lock, err := loadFetchLock(gitRoot)
+	if err != nil {
+		provenanceLog.Printf("Ignoring fetch lock: %v", err)
+		lock = &FetchLock{Files: make(map[string]FetchLockEntry)}
+	}
+
+	for _, installed := range fetched.Imports {
+		key, err := fetchLockKey(gitRoot, installed)
+		if err != nil {
+			provenanceLog.Printf("Skipping %s: %v", installed, err)
+			continue
+		}
+		file := ProvenanceFile{Path: key}
+		if entry, ok := lock.Files[key]; ok {
+			file.Source = entry.Source
+			file.BlobSHA = entry.BlobSHA
+			file.CommitSHA = entry.CommitSHA
+		} else if content, err := os.ReadFile(installed); err == nil {
+			file.BlobSHA = gitBlobSHA(content)
+		}
+		provenance.Files = append(provenance.Files, file)
+	}
+	sort.Slice(provenance.Files, func(i, j int) bool { return provenance.Files[i].Path < provenance.Files[j].Path })
+
+	return provenance
+}
+
+// writeWorkflowProvenance writes the provenance file next to workflowFile, replacing any
+// previous one. The file is tracked for staging unless git ignores it, so users can keep
+// provenance files out of the repository with a .gitignore entry.
+func writeWorkflowProvenance(workflowFile string, provenance *WorkflowProvenance, tracker *FileTracker) (string, error) {
+	path := provenancePath(workflowFile)
+
+	data, err := json.MarshalIndent(provenance, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal provenance: %w", err)
+	}
+
+	if tracker != nil && !isGitIgnored(tracker.gitRoot, path) {
+		if _, err := os.Stat(path); err == nil {
+			tracker.TrackModified(path)
+		} else {
+			tracker.TrackCreated(path)
+		}
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("failed to write provenance file: %w", err)
+	}
+	provenanceLog.Printf("Wrote provenance for %s with %d files", workflowFile, len(provenance.Files))
+	return path, nil
+}
+
+// isGitIgnored reports whether git ignores path in the repository at gitRoot
+func isGitIgnored(gitRoot, path string) bool {
+	if gitRoot == "" {
+		return false
+	}
+	cmd := exec.Command("git", "-C", gitRoot, "check-ignore", "-q", "--", path)
+	return cmd.Run() == nil
+}
@@ -0,0 +1,73 @@
+//go:build !integration
+
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildWorkflowProvenance(t *testing.T) {
+	const commitSHA = "0123456789abcdef0123456789abcdef01234567"
+	gitRoot := t.TempDir()
+	tracker := &FileTracker{OriginalContent: make(map[string][]byte), gitRoot: gitRoot}
+
+	tools := filepath.Join(gitRoot, ".github", "workflows", "shared", "tools.md")
+	copied := filepath.Join(gitRoot, ".github", "workflows", "shared", "copied.md")
+	writeTestFile(t, tools, "# Tools\n")
+	writeTestFile(t, copied, "# Copied\n")
+	recordFetchedFile(tracker, tools, "owner/repo/.github/workflows/shared/tools.md@main", gitBlobSHA([]byte("# Tools\n")))
+	recordFetchedCommitSHAs(tracker, nil, map[string]string{"owner/repo@main": commitSHA})
+
+	spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "main"}, WorkflowPath: ".github/workflows/triage.md"}
+	fetched := &FetchedWorkflow{CommitSHA: commitSHA, ContentSHA256: "abc123", Imports: []string{tools, copied}}
+
+	provenance := buildWorkflowProvenance(gitRoot, spec, fetched)
+	assert.Equal(t, spec.String(), provenance.Source, "source should be the workflow spec")
+	assert.Equal(t, commitSHA, provenance.CommitSHA, "commit should be the resolved commit")
+	assert.Equal(t, "abc123", provenance.ContentSHA256, "content checksum should be recorded")
+	_, err := time.Parse(time.RFC3339, provenance.FetchedAt)
+	require.NoError(t, err, "fetch time should be RFC 3339")
+	assert.Equal(t, []ProvenanceFile{
+		{Path: ".github/workflows/shared/copied.md", BlobSHA: gitBlobSHA([]byte("# Copied\n"))},
+		{Path: ".github/workflows/shared/tools.md", Source: "owner/repo/.github/workflows/shared/tools.md@main", BlobSHA: gitBlobSHA([]byte("# Tools\n")), CommitSHA: commitSHA},
+	}, provenance.Files, "files should come from the fetch lock, falling back to their local content")
+}
+
+func TestWriteWorkflowProvenance(t *testing.T) {
+	gitRoot := t.TempDir()
+	require.NoError(t, exec.Command("git", "-C", gitRoot, "init", "-q").Run(), "git init should succeed")
+	tracker := &FileTracker{OriginalContent: make(map[string][]byte), gitRoot: gitRoot}
+
+	workflowFile := filepath.Join(gitRoot, ".github", "workflows", "triage.md")
+	writeTestFile(t, workflowFile, "# Triage\n")
+
+	path, err := writeWorkflowProvenance(workflowFile, &WorkflowProvenance{Source: "owner/repo/triage@v1"}, tracker)
+	require.NoError(t, err, "provenance should be written")
+	assert.Equal(t, filepath.Join(gitRoot, ".github", "workflows", "triage.provenance.json"), path, "provenance should sit next to the workflow")
+	assert.Contains(t, tracker.CreatedFiles, path, "new provenance file should be tracked")
+
+	// Reinstalling replaces the previous provenance
+	_, err = writeWorkflowProvenance(workflowFile, &WorkflowProvenance{Source: "owner/repo/triage@v2"}, tracker)
+	require.NoError(t, err, "provenance should be rewritten")
+	assert.Contains(t, tracker.ModifiedFiles, path, "existing provenance file should be tracked as modified")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err, "provenance file should be readable")
+	var provenance WorkflowProvenance
+	require.NoError(t, json.Unmarshal(data, &provenance), "provenance should be valid JSON")
+	assert.Equal(t, "owner/repo/triage@v2", provenance.Source, "provenance should be updated")
+
+	// Ignored provenance files are written but never staged
+	writeTestFile(t, filepath.Join(gitRoot, ".gitignore"), "*.provenance.json\n")
+	ignoredTracker := &FileTracker{OriginalContent: make(map[string][]byte), gitRoot: gitRoot}
+	_, err = writeWorkflowProvenance(workflowFile, &WorkflowProvenance{Source: "owner/repo/triage@v3"}, ignoredTracker)
+	require.NoError(t, err, "ignored provenance should still be written")
+	assert.Empty(t, ignoredTracker.GetAllFiles(), "ignored provenance file should not be tracked")
+}
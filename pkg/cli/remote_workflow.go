@@ -2,17 +2,24 @@ package cli
 
 import (
 	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
 
-	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/fileutil"
 	"github.com/github/gh-aw/pkg/logger"
 	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/workflow"
 )
 
 var remoteWorkflowLog = logger.New("cli:remote_workflow")
@@ -24,30 +31,229 @@ type FetchedWorkflow struct {
 	CommitSHA  string // The resolved commit SHA at the time of fetch (empty for local)
 	IsLocal    bool   // true if this is a local workflow (from filesystem)
 	SourcePath string // The original source path (local path or remote path)
+	// PathStrategy records how SourcePath was found for remote workflows: at the path as
+	// given, or by probing under workflows/ or .github/workflows/. Empty for local workflows.
+	PathStrategy PathStrategy
+	// ContentSHA256 is the hex-encoded SHA-256 of Content. Unlike CommitSHA it is
+	// always set, including for local workflows, so content changes can be detected
+	// even when the commit is unknown.
+	ContentSHA256 string
+	// Imports lists the local paths written for the workflow's includes and frontmatter
+	// imports, including transitive ones. It is populated by the add flow after the
+	// dependencies have been saved.
+	Imports []string
 }
 
+// WorkflowFetchResult is the outcome of fetching one spec with FetchWorkflowsFromSource
+type WorkflowFetchResult struct {
+	Spec     *WorkflowSpec
+	Workflow *FetchedWorkflow // nil when Err is set
+	Err      error
+}
+
+// FetchWorkflowsFromSource fetches several workflows like FetchWorkflowFromSource, returning
+// one result per spec in the same order. Ref resolutions and downloads are shared across the
+// batch, so specs from the same repository and ref, repeated specs, and fallback path probes
+// only cost one GitHub API call each. A failing spec does not stop the others.
+func FetchWorkflowsFromSource(specs []*WorkflowSpec, verbose bool) []WorkflowFetchResult {
+	return fetchWorkflowsFromSource(specs, remoteFetchOptions{LogLevel: fetchLogLevel(verbose, false)})
+}
+
+// fetchWorkflowsFromSource is FetchWorkflowsFromSource with configurable fetch options
+func fetchWorkflowsFromSource(specs []*WorkflowSpec, opts remoteFetchOptions) []WorkflowFetchResult {
+	remoteWorkflowLog.Printf("Fetching %d workflows from source", len(specs))
+	if opts.Fetcher == nil {
+		// Without request headers the configuration cannot be invalid
+		opts.Fetcher, _ = parser.NewFetcher(parser.FetchConfig{})
+	}
+
+	results := make([]WorkflowFetchResult, len(specs))
+	for i, spec := range specs {
+		fetched, err := fetchWorkflowFromSource(spec, opts)
+		results[i] = WorkflowFetchResult{Spec: spec, Workflow: fetched, Err: err}
+	}
+	return results
+}
+
+// PathStrategy identifies which candidate path a remote workflow was found at
+type PathStrategy string
+
+const (
+	PathStrategyDirect                PathStrategy = "direct"                  // The path as given
+	PathStrategyWorkflowsPrefix       PathStrategy = "workflows-prefix"        // Under workflows/
+	PathStrategyGitHubWorkflowsPrefix PathStrategy = "github-workflows-prefix" // Under .github/workflows/
+)
+
 // FetchWorkflowFromSource fetches a workflow file directly from GitHub without cloning.
 // This is the preferred way to add remote workflows as it only fetches the specific
 // files needed rather than cloning the entire repository.
 //
 // For local workflows (local filesystem paths), it reads from the local filesystem.
-// For remote workflows, it uses the GitHub API to fetch the file content.
+// For remote workflows, it fetches the file content through the SourceProvider of the
+// configured host: the GitHub API by default, or an alternate forge such as Gitea.
 func FetchWorkflowFromSource(spec *WorkflowSpec, verbose bool) (*FetchedWorkflow, error) {
+	return fetchWorkflowFromSource(spec, remoteFetchOptions{LogLevel: fetchLogLevel(verbose, false)})
+}
+
+// fetchWorkflowFromSource is FetchWorkflowFromSource with configurable fetch options
+func fetchWorkflowFromSource(spec *WorkflowSpec, opts remoteFetchOptions) (*FetchedWorkflow, error) {
 	remoteWorkflowLog.Printf("Fetching workflow from source: spec=%s", spec.String())
 
-	// Handle local workflows
-	if isLocalWorkflowPath(spec.WorkflowPath) {
-		return fetchLocalWorkflow(spec, verbose)
+	// Local workflows are always allowed; remote ones must match the allowed-sources policy
+	if !IsLocalWorkflowPath(spec.WorkflowPath) {
+		if err := checkSourceAllowed(spec.RepoSlug, opts.AllowedSources); err != nil {
+			return nil, err
+		}
+		// Report a misconfigured provider before any download is attempted
+		if _, err := currentSourceProvider(opts.Fetcher); err != nil {
+			return nil, err
+		}
+	}
+
+	// Resolve repo-only specs (owner/repo[@ref]) to the repository's single agentic workflow
+	if spec.WorkflowPath == "" {
+		if err := resolveDefaultWorkflowPath(spec, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	var fetched *FetchedWorkflow
+	var err error
+	if IsLocalWorkflowPath(spec.WorkflowPath) {
+		// Handle local workflows
+		fetched, err = fetchLocalWorkflow(spec, opts)
+	} else {
+		// Handle remote workflows from the source provider
+		fetched, err = fetchRemoteWorkflow(spec, opts)
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	fetched.ContentSHA256 = contentSHA256(fetched.Content)
+	remoteWorkflowLog.Printf("Fetched %s: %d bytes, sha256=%s", fetched.SourcePath, len(fetched.Content), fetched.ContentSHA256)
+	return fetched, nil
+}
+
+// contentSHA256 returns the hex-encoded SHA-256 digest of content
+func contentSHA256(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// defaultWorkflowSearchDirs lists the conventional directories probed for a
+// remote spec that omits the workflow path
+var defaultWorkflowSearchDirs = []string{".github/workflows", "workflows"}
+
+// listDefaultWorkflows lists the agentic workflows of a repository for repo-only specs.
+// It is a variable so tests can substitute the source provider.
+var listDefaultWorkflows = func(owner, repo, ref string, opts remoteFetchOptions) ([]RemoteWorkflowInfo, error) {
+	return listRemoteWorkflows(owner, repo, ref, defaultWorkflowSearchDirs, opts)
+}
+
+// resolveDefaultWorkflowPath probes the conventional workflow directories of a
+// repo-only spec at the requested ref, or at the repository's default branch, and
+// fills in WorkflowPath and WorkflowName when exactly one agentic workflow is found.
+func resolveDefaultWorkflowPath(spec *WorkflowSpec, opts remoteFetchOptions) error {
+	parts := strings.SplitN(spec.RepoSlug, "/", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid repository slug: %s", spec.RepoSlug)
+	}
+	owner := parts[0]
+	repo := parts[1]
+
+	ref := spec.Version
+	if ref == "" {
+		defaultBranch, err := getRepoDefaultBranch(spec.RepoSlug)
+		if err != nil {
+			if isUnlistableRepoError(err) {
+				return err
+			}
+			remoteWorkflowLog.Printf("Failed to resolve default branch for %s, falling back to 'main': %v", spec.RepoSlug, err)
+			defaultBranch = "main"
+		}
+		ref = defaultBranch
+	}
+
+	if opts.LogLevel.showInfo() {
+		opts.OnEvent.emit(FetchEventInfo, fmt.Sprintf("No workflow path given, looking for workflows in %s@%s...", spec.RepoSlug, ref), "")
+	}
+
+	workflows, err := listDefaultWorkflows(owner, repo, ref, opts)
+	if err != nil {
+		if isUnlistableRepoError(err) {
+			return err
+		}
+		// Unlistable repositories are reported below as having no workflows
+		remoteWorkflowLog.Printf("Failed to list workflows in %s@%s: %v", spec.RepoSlug, ref, err)
+	}
+	candidates := make([]string, 0, len(workflows))
+	for _, info := range workflows {
+		candidates = append(candidates, info.Path)
+	}
+
+	workflowPath, err := selectDefaultWorkflow(spec.RepoSlug, ref, candidates)
+	if err != nil {
+		return err
+	}
+
+	remoteWorkflowLog.Printf("Resolved repo-only spec %s to %s", spec.RepoSlug, workflowPath)
+	spec.WorkflowPath = workflowPath
+	spec.WorkflowName = strings.TrimSuffix(path.Base(workflowPath), ".md")
+	return nil
+}
 
-	// Handle remote workflows from GitHub
-	return fetchRemoteWorkflow(spec, verbose)
+// isUnlistableRepoError reports whether err from probing a repo-only spec must be returned
+// as is rather than reported as a repository without workflows: policy and pinning errors,
+// rejected credentials, and exhausted rate limits.
+func isUnlistableRepoError(err error) bool {
+	var authErr *parser.AuthError
+	return isFatalFetchError(err) || errors.As(err, &authErr) || errors.Is(err, parser.ErrRateLimited)
+}
+
+// selectDefaultWorkflow picks the workflow for a repo-only spec, requiring
+// exactly one candidate so that the choice is never ambiguous.
+func selectDefaultWorkflow(repoSlug, ref string, candidates []string) (string, error) {
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("no agentic workflows found in %s@%s (searched %s); specify the workflow path explicitly (owner/repo/path/to/workflow.md)",
+			repoSlug, ref, strings.Join(defaultWorkflowSearchDirs, ", "))
+	case 1:
+		return candidates[0], nil
+	default:
+		return "", fmt.Errorf("multiple agentic workflows found in %s@%s, specify one explicitly:\n  %s",
+			repoSlug, ref, strings.Join(candidates, "\n  "))
+	}
+}
+
+// isAgenticWorkflowContent reports whether markdown content is a runnable agentic
+// workflow. Shared components and documentation have no 'on' trigger.
+func isAgenticWorkflowContent(content string) bool {
+	result, err := parser.ExtractFrontmatterFromContent(content)
+	if err != nil {
+		return false
+	}
+	_, hasOn := result.Frontmatter["on"]
+	return hasOn
 }
 
 // fetchLocalWorkflow reads a workflow file from the local filesystem
-func fetchLocalWorkflow(spec *WorkflowSpec, verbose bool) (*FetchedWorkflow, error) {
-	if verbose {
-		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Reading local workflow: "+spec.WorkflowPath))
+func fetchLocalWorkflow(spec *WorkflowSpec, opts remoteFetchOptions) (*FetchedWorkflow, error) {
+	if opts.LogLevel.showInfo() {
+		opts.OnEvent.emit(FetchEventInfo, "Reading local workflow: "+spec.WorkflowPath, spec.WorkflowPath)
+	}
+
+	// A directory resolves to its single agentic workflow, like a repo-only remote spec
+	if info, err := os.Stat(spec.WorkflowPath); err == nil && info.IsDir() {
+		if err := resolveLocalDirectoryWorkflow(spec); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.NoSymlinks {
+		if err := checkNoSymlinks(spec.WorkflowPath); err != nil {
+			return nil, err
+		}
 	}
 
 	content, err := os.ReadFile(spec.WorkflowPath)
@@ -63,8 +269,70 @@ func fetchLocalWorkflow(spec *WorkflowSpec, verbose bool) (*FetchedWorkflow, err
 	}, nil
 }
 
+// resolveLocalDirectoryWorkflow fills in WorkflowPath and WorkflowName of a spec naming a local
+// directory when the directory contains exactly one agentic workflow
+func resolveLocalDirectoryWorkflow(spec *WorkflowSpec) error {
+	candidates, err := discoverLocalWorkflows(spec.WorkflowPath)
+	if err != nil {
+		return err
+	}
+	switch len(candidates) {
+	case 0:
+		return fmt.Errorf("no agentic workflows found in local directory '%s'", spec.WorkflowPath)
+	case 1:
+		remoteWorkflowLog.Printf("Resolved local directory %s to %s", spec.WorkflowPath, candidates[0])
+		spec.WorkflowPath = candidates[0]
+		spec.WorkflowName = strings.TrimSuffix(filepath.Base(candidates[0]), ".md")
+		return nil
+	default:
+		return fmt.Errorf("multiple agentic workflows found in local directory '%s', specify one explicitly:\n  %s",
+			spec.WorkflowPath, strings.Join(candidates, "\n  "))
+	}
+}
+
+// checkNoSymlinks fails if path is a symbolic link, or if it lies in the current git repository
+// but a symlinked directory makes it resolve outside of it. Missing files are left for the
+// caller to report.
+func checkNoSymlinks(filePath string) error {
+	info, err := os.Lstat(filePath)
+	if err != nil {
+		return nil
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		return fmt.Errorf("local workflow '%s' is a symbolic link, which is not allowed with --no-symlinks", filePath)
+	}
+
+	gitRoot, err := findGitRoot()
+	if err != nil {
+		remoteWorkflowLog.Printf("Not in a git repository, skipping symlinked directory check for %s: %v", filePath, err)
+		return nil
+	}
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve local workflow '%s': %w", filePath, err)
+	}
+	resolvedRoot, rootErr := filepath.EvalSymlinks(gitRoot)
+	resolvedPath, pathErr := filepath.EvalSymlinks(absPath)
+	if rootErr != nil || pathErr != nil {
+		return fmt.Errorf("failed to resolve local workflow '%s': %w", filePath, errors.Join(rootErr, pathErr))
+	}
+	inRepo := isWithinDir(gitRoot, absPath) || isWithinDir(resolvedRoot, absPath)
+	if inRepo && !isWithinDir(resolvedRoot, resolvedPath) {
+		return fmt.Errorf("local workflow '%s' resolves to %s outside the repository through a symlinked directory, which is not allowed with --no-symlinks", filePath, resolvedPath)
+	}
+	return nil
+}
+
+// downloadWorkflowFile and resolveRemoteWorkflowSHA fetch a workflow file and resolve its ref
+// through the source provider. They are variables so tests can substitute the forge API.
+var (
+	downloadWorkflowFile     = downloadSourceFile
+	resolveRemoteWorkflowSHA = resolveSourceRef
+)
+
 // fetchRemoteWorkflow fetches a workflow file directly from GitHub using the API
-func fetchRemoteWorkflow(spec *WorkflowSpec, verbose bool) (*FetchedWorkflow, error) {
+func fetchRemoteWorkflow(spec *WorkflowSpec, opts remoteFetchOptions) (*FetchedWorkflow, error) {
+	verbose := opts.LogLevel.showInfo()
 	remoteWorkflowLog.Printf("Fetching remote workflow: repo=%s, path=%s, version=%s",
 		spec.RepoSlug, spec.WorkflowPath, spec.Version)
 
@@ -84,11 +352,11 @@ func fetchRemoteWorkflow(spec *WorkflowSpec, verbose bool) (*FetchedWorkflow, er
 	}
 
 	if verbose {
-		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Fetching %s/%s/%s@%s...", owner, repo, spec.WorkflowPath, ref)))
+		opts.OnEvent.emit(FetchEventInfo, fmt.Sprintf("Fetching %s/%s/%s@%s...", owner, repo, spec.WorkflowPath, ref), spec.WorkflowPath)
 	}
 
 	// Resolve the ref to a commit SHA for source tracking
-	commitSHA, err := parser.ResolveRefToSHA(owner, repo, ref)
+	commitSHA, err := resolveRemoteWorkflowSHA(opts.Fetcher, owner, repo, ref)
 	if err != nil {
 		remoteWorkflowLog.Printf("Failed to resolve ref to SHA: %v", err)
 		// Continue without SHA - we can still fetch the content
@@ -96,82 +364,218 @@ func fetchRemoteWorkflow(spec *WorkflowSpec, verbose bool) (*FetchedWorkflow, er
 	} else {
 		remoteWorkflowLog.Printf("Resolved ref %s to SHA: %s", ref, commitSHA)
 		if verbose {
-			fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Resolved to commit: "+commitSHA[:7]))
+			opts.OnEvent.emit(FetchEventInfo, "Resolved to commit: "+commitSHA[:7], spec.WorkflowPath)
 		}
 	}
 
 	// Download the workflow file from GitHub
-	content, err := parser.DownloadFileFromGitHub(owner, repo, spec.WorkflowPath, ref)
+	content, err := downloadWorkflowFile(opts.Fetcher, owner, repo, spec.WorkflowPath, ref, opts.MaxFileSize)
 	if err != nil {
-		// Try with a workflows/ prefix if the direct path fails
-		if !strings.HasPrefix(spec.WorkflowPath, "workflows/") && !strings.Contains(spec.WorkflowPath, "/") {
-			// Try workflows/filename.md
-			altPath := "workflows/" + spec.WorkflowPath
-			if !strings.HasSuffix(altPath, ".md") {
-				altPath += ".md"
-			}
-			remoteWorkflowLog.Printf("Direct path failed, trying: %s", altPath)
-			if altContent, altErr := parser.DownloadFileFromGitHub(owner, repo, altPath, ref); altErr == nil {
-				return &FetchedWorkflow{
-					Content:    altContent,
-					CommitSHA:  commitSHA,
-					IsLocal:    false,
-					SourcePath: altPath,
-				}, nil
-			}
-
-			// Try .github/workflows/filename.md
-			altPath = ".github/workflows/" + spec.WorkflowPath
-			if !strings.HasSuffix(altPath, ".md") {
-				altPath += ".md"
-			}
-			remoteWorkflowLog.Printf("Trying: %s", altPath)
-			if altContent, altErr := parser.DownloadFileFromGitHub(owner, repo, altPath, ref); altErr == nil {
-				return &FetchedWorkflow{
-					Content:    altContent,
-					CommitSHA:  commitSHA,
-					IsLocal:    false,
-					SourcePath: altPath,
-				}, nil
+		// Try the workflows/ and .github/workflows/ directories if the direct path does not exist.
+		// Other failures (authentication, rate limiting) would fail the same way for every
+		// alternate path, so they are returned immediately.
+		var notFound *parser.NotFoundError
+		if errors.As(err, &notFound) && !strings.HasPrefix(spec.WorkflowPath, "workflows/") && !strings.Contains(spec.WorkflowPath, "/") {
+			var skipped []string
+			for _, altPath := range remoteWorkflowFallbackPaths(spec.WorkflowPath) {
+				remoteWorkflowLog.Printf("Direct path not found, trying: %s", altPath)
+				altContent, altErr := downloadWorkflowFile(opts.Fetcher, owner, repo, altPath, ref, opts.MaxFileSize)
+				// A probed file must be an agentic workflow: .github/workflows/ also holds
+				// plain GitHub Actions workflows with the same name
+				if altErr == nil && !isAgenticWorkflowContent(string(altContent)) {
+					remoteWorkflowLog.Printf("Skipping %s: not an agentic workflow", altPath)
+					skipped = append(skipped, altPath)
+					continue
+				}
+				if altErr == nil {
+					strategy := fallbackPathStrategy(altPath)
+					remoteWorkflowLog.Printf("Found %s at fallback path %s (strategy: %s)", spec.WorkflowPath, altPath, strategy)
+					if verbose {
+						opts.OnEvent.emit(FetchEventInfo, fmt.Sprintf("Found %s at %s (%s); use the full path to avoid probing", spec.WorkflowPath, altPath, strategy), spec.WorkflowPath)
+					}
+					return &FetchedWorkflow{
+						Content:      altContent,
+						CommitSHA:    commitSHA,
+						IsLocal:      false,
+						SourcePath:   altPath,
+						PathStrategy: strategy,
+					}, nil
+				}
+				if !errors.As(altErr, &notFound) {
+					return nil, fmt.Errorf("failed to download workflow from %s/%s/%s@%s: %w", owner, repo, altPath, ref, altErr)
+				}
+			}
+			if len(skipped) > 0 {
+				err = fmt.Errorf("%w (skipped %s: not agentic workflows with frontmatter)", err, strings.Join(skipped, ", "))
 			}
 		}
 		return nil, fmt.Errorf("failed to download workflow from %s/%s/%s@%s: %w", owner, repo, spec.WorkflowPath, ref, err)
 	}
 
 	if verbose {
-		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Downloaded workflow (%d bytes)", len(content))))
+		opts.OnEvent.emit(FetchEventSuccess, fmt.Sprintf("Downloaded workflow (%d bytes)", len(content)), spec.WorkflowPath)
 	}
 
 	return &FetchedWorkflow{
-		Content:    content,
-		CommitSHA:  commitSHA,
-		IsLocal:    false,
-		SourcePath: spec.WorkflowPath,
+		Content:      content,
+		CommitSHA:    commitSHA,
+		IsLocal:      false,
+		SourcePath:   spec.WorkflowPath,
+		PathStrategy: PathStrategyDirect,
 	}, nil
 }
 
+// fallbackPathStrategy returns the strategy of a path from remoteWorkflowFallbackPaths
+func fallbackPathStrategy(altPath string) PathStrategy {
+	if strings.HasPrefix(altPath, ".github/workflows/") {
+		return PathStrategyGitHubWorkflowsPrefix
+	}
+	return PathStrategyWorkflowsPrefix
+}
+
+// remoteWorkflowExtensions lists the workflow file extensions probed, in order, when a
+// workflow name is given without one
+var remoteWorkflowExtensions = []string{".md", ".yaml", ".yml"}
+
+// remoteWorkflowFallbackPaths returns the paths probed, in order, when a bare workflow name is
+// not found at the repository root: each extension under workflows/, then under
+// .github/workflows/. Names that already carry a known extension are probed as-is. Only files
+// with agentic workflow frontmatter are accepted at these paths.
+func remoteWorkflowFallbackPaths(name string) []string {
+	candidates := []string{name}
+	if !slices.Contains(remoteWorkflowExtensions, path.Ext(name)) {
+		candidates = candidates[:0]
+		for _, ext := range remoteWorkflowExtensions {
+			candidates = append(candidates, name+ext)
+		}
+	}
+
+	var paths []string
+	for _, dir := range []string{"workflows/", ".github/workflows/"} {
+		for _, candidate := range candidates {
+			paths = append(paths, dir+candidate)
+		}
+	}
+	return paths
+}
+
+// defaultRootedIncludePrefixes lists the relative include prefixes that resolve under .github/
+// instead of relative to the including workflow's directory
+var defaultRootedIncludePrefixes = []string{"shared/"}
+
+// normalizeRootedIncludePrefixes ensures every prefix ends with "/" and has no leading "./" or "/",
+// falling back to defaultRootedIncludePrefixes when none are given
+func normalizeRootedIncludePrefixes(prefixes []string) []string {
+	var normalized []string
+	for _, prefix := range prefixes {
+		prefix = strings.TrimPrefix(strings.TrimSpace(prefix), "./")
+		prefix = strings.Trim(prefix, "/")
+		if prefix == "" {
+			continue
+		}
+		normalized = append(normalized, prefix+"/")
+	}
+	if len(normalized) == 0 {
+		return defaultRootedIncludePrefixes
+	}
+	return normalized
+}
+
+// isRootedIncludePath checks whether a relative include path starts with one of the rooted prefixes
+func isRootedIncludePath(filePath string, rootedPrefixes []string) bool {
+	for _, prefix := range normalizeRootedIncludePrefixes(rootedPrefixes) {
+		if strings.HasPrefix(filePath, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ContentTransform rewrites the content of a fetched include or import file before it is
+// saved, e.g. to substitute organization-specific values or redact text. source is the
+// location the file was fetched from ("owner/repo/path@ref"). The transformed content is what
+// gets written, recorded in the fetch lock, and parsed for nested includes or imports.
+type ContentTransform func(source string, content []byte) ([]byte, error)
+
+// downloadIncludeFile downloads a single include file.
+// It is a variable so tests can substitute the GitHub contents API.
+var downloadIncludeFile = downloadSourceFile
+
+// resolveIncludeRef resolves the ref an include was fetched at to a commit SHA.
+// It is a variable so tests can substitute the forge API.
+var resolveIncludeRef = resolveSourceRef
+
+// includeCommitCache memoizes include ref resolution for the lifetime of the process, keyed
+// by GitHub host, repository, and ref, so files fetched from the same ref cost one lookup.
+var includeCommitCache sync.Map
+
+// IncludeResult describes an include file fetched from GitHub
+type IncludeResult struct {
+	Content      []byte // Raw file content
+	Section      string // #fragment from the include path (e.g., "#section-name"), empty when absent
+	ResolvedPath string // Repository location the file was fetched from: owner/repo/path/to/file.md@ref
+	CommitSHA    string // Commit the file was fetched at, empty for URLs and refs that could not be resolved
+}
+
 // FetchIncludeFromSource fetches an include file from GitHub directly using a workflowspec format path.
 // The includePath should be in the format: owner/repo/path/to/file.md[@ref]
 // If the includePath is a relative path, it's resolved relative to the baseSpec.
-// Returns: (content, section, error) where section is the #fragment from the path (e.g., "#section-name").
-func FetchIncludeFromSource(includePath string, baseSpec *WorkflowSpec, verbose bool) ([]byte, string, error) {
+// An http:// or https:// URL is fetched with a plain HTTP GET when HTTP(S) includes are allowed
+// (--allow-http-includes); relative includes inside such a file resolve against its URL.
+// The returned result carries the #fragment from the path (e.g., "#section-name") as its Section.
+func FetchIncludeFromSource(includePath string, baseSpec *WorkflowSpec, verbose bool) (*IncludeResult, error) {
+	return fetchIncludeFromSource(includePath, baseSpec, remoteFetchOptions{LogLevel: fetchLogLevel(verbose, false)})
+}
+
+// fetchIncludeFromSource is FetchIncludeFromSource with configurable fetch options.
+// Relative includes starting with one of opts.RootedIncludePrefixes (defaultRootedIncludePrefixes
+// when empty) resolve under .github/; all other relative includes resolve against the workflow's
+// directory. Files larger than opts.MaxFileSize fail with parser.ErrFileTooLarge.
+// A checksum pin (path@sha256:<hex>) is verified against the downloaded bytes; a mismatch
+// wraps parser.ErrIntegrityMismatch.
+func fetchIncludeFromSource(includePath string, baseSpec *WorkflowSpec, opts remoteFetchOptions) (*IncludeResult, error) {
 	baseSpecStr := "<nil>"
 	if baseSpec != nil {
 		baseSpecStr = baseSpec.String()
 	}
 	remoteWorkflowLog.Printf("Fetching include from source: path=%s, base=%s", includePath, baseSpecStr)
 
-	// Extract section reference (e.g., "#section-name") from the path upfront
-	// This ensures consistent behavior regardless of which code path is taken
+	// Extract the checksum pin and section reference (e.g., "#section-name") from the path
+	// upfront. This ensures consistent behavior regardless of which code path is taken
+	includePath, integrity := parser.SplitIncludeIntegrity(includePath)
 	cleanPath := includePath
-	var section string
+	result := &IncludeResult{}
 	if idx := strings.Index(includePath, "#"); idx != -1 {
 		cleanPath = includePath[:idx]
-		section = includePath[idx:]
+		result.Section = includePath[idx:]
+	}
+
+	// URLs are checked first since they may contain "@" (user info) like a workflowspec
+	includeURL := ""
+	if isHTTPIncludePath(cleanPath) {
+		includeURL = cleanPath
+	} else if baseSpec != nil && baseSpec.RepoSlug == "" && isHTTPIncludePath(baseSpec.WorkflowPath) && !IsWorkflowSpecFormat(cleanPath) {
+		resolved, err := resolveHTTPIncludeURL(baseSpec.WorkflowPath, cleanPath)
+		if err != nil {
+			return nil, err
+		}
+		includeURL = resolved
+	}
+	if includeURL != "" {
+		result.ResolvedPath = includeURL
+		content, err := fetchHTTPInclude(includeURL, opts)
+		if err != nil {
+			return nil, err
+		}
+		if err := parser.VerifyIncludeIntegrity(includePath, content, integrity); err != nil {
+			return nil, err
+		}
+		result.Content = content
+		return result, nil
 	}
 
 	// Check if this is a workflowspec format (owner/repo/path[@ref])
-	if isWorkflowSpecFormat(cleanPath) {
+	if IsWorkflowSpecFormat(cleanPath) {
 		// Split on @ to get path and ref
 		parts := strings.SplitN(cleanPath, "@", 2)
 		pathPart := parts[0]
@@ -185,20 +589,29 @@ func FetchIncludeFromSource(includePath string, baseSpec *WorkflowSpec, verbose
 		// Parse path: owner/repo/path/to/file.md
 		slashParts := strings.Split(pathPart, "/")
 		if len(slashParts) < 3 {
-			return nil, section, errors.New("invalid workflowspec: must be owner/repo/path[@ref]")
+			return nil, errors.New("invalid workflowspec: must be owner/repo/path[@ref]")
 		}
 
 		owner := slashParts[0]
 		repo := slashParts[1]
 		filePath := strings.Join(slashParts[2:], "/")
+		setIncludeLocation(result, owner, repo, filePath, ref)
+		if err := checkSourceAllowed(owner+"/"+repo, opts.AllowedSources); err != nil {
+			return nil, err
+		}
 
 		// Download the file
-		content, err := parser.DownloadFileFromGitHub(owner, repo, filePath, ref)
+		content, err := downloadIncludeFile(opts.Fetcher, owner, repo, filePath, ref, opts.MaxFileSize)
 		if err != nil {
-			return nil, section, fmt.Errorf("failed to fetch include from %s: %w", includePath, err)
+			return nil, fmt.Errorf("failed to fetch include from %s: %w", includePath, err)
+		}
+		if err := parser.VerifyIncludeIntegrity(includePath, content, integrity); err != nil {
+			return nil, err
 		}
 
-		return content, section, nil
+		result.Content = content
+		result.CommitSHA = includeCommitSHA(opts.Fetcher, owner, repo, ref)
+		return result, nil
 	}
 
 	// For relative paths, resolve against the base spec
@@ -218,30 +631,109 @@ func FetchIncludeFromSource(includePath string, baseSpec *WorkflowSpec, verbose
 				filePath = filePath[:idx]
 			}
 
-			// If it's a relative path starting with shared/, it's relative to .github/
+			// If it's a relative path starting with a rooted prefix (e.g. shared/), it's relative to .github/
 			var fullPath string
-			if strings.HasPrefix(filePath, "shared/") {
+			if isRootedIncludePath(filePath, opts.RootedIncludePrefixes) {
 				fullPath = ".github/" + filePath
 			} else {
 				// Otherwise, resolve relative to the workflow path directory
-				baseDir := getParentDir(baseSpec.WorkflowPath)
+				baseDir := GetParentDir(baseSpec.WorkflowPath)
 				if baseDir != "" {
 					fullPath = baseDir + "/" + filePath
 				} else {
 					fullPath = filePath
 				}
 			}
+			setIncludeLocation(result, owner, repo, fullPath, ref)
 
-			content, err := parser.DownloadFileFromGitHub(owner, repo, fullPath, ref)
+			content, err := downloadIncludeFile(opts.Fetcher, owner, repo, fullPath, ref, opts.MaxFileSize)
 			if err != nil {
-				return nil, section, fmt.Errorf("failed to fetch include %s from %s/%s: %w", filePath, owner, repo, err)
+				return nil, fmt.Errorf("failed to fetch include %s from %s/%s: %w", filePath, owner, repo, err)
+			}
+			if err := parser.VerifyIncludeIntegrity(filePath, content, integrity); err != nil {
+				return nil, err
 			}
 
-			return content, section, nil
+			result.Content = content
+			result.CommitSHA = includeCommitSHA(opts.Fetcher, owner, repo, ref)
+			return result, nil
 		}
 	}
 
-	return nil, section, fmt.Errorf("cannot resolve include path: %s (no base spec provided)", includePath)
+	return nil, fmt.Errorf("cannot resolve include path: %s (no base spec provided)", includePath)
+}
+
+// setIncludeLocation records the repository file an include resolves to
+func setIncludeLocation(result *IncludeResult, owner, repo, filePath, ref string) {
+	result.ResolvedPath = fmt.Sprintf("%s/%s/%s@%s", owner, repo, filePath, ref)
+}
+
+// includeCommitSHA returns the commit that ref of owner/repo points at, resolving each ref at
+// most once per host through fetcher. Resolution is best-effort: failures are logged and yield
+// an empty SHA.
+func includeCommitSHA(fetcher *parser.Fetcher, owner, repo, ref string) string {
+	if IsCommitSHA(ref) {
+		return ref
+	}
+	key := parser.GetGitHubHost() + "/" + owner + "/" + repo + "@" + ref
+	if cached, ok := includeCommitCache.Load(key); ok {
+		return cached.(string)
+	}
+
+	commitSHA, err := resolveIncludeRef(fetcher, owner, repo, ref)
+	if err != nil {
+		// Failures are not memoized so that transient errors can be retried
+		remoteWorkflowLog.Printf("Failed to resolve include ref %s: %v", key, err)
+		return ""
+	}
+	includeCommitCache.Store(key, commitSHA)
+	return commitSHA
+}
+
+// includedFileSpec returns the location of a fetched include as a WorkflowSpec, so that the
+// relative includes inside it resolve against its own directory and repository.
+// The including spec is returned when the include location is unknown. Includes fetched from a
+// URL are returned as a spec whose WorkflowPath is that URL.
+func includedFileSpec(result *IncludeResult, spec *WorkflowSpec) *WorkflowSpec {
+	if isHTTPIncludePath(result.ResolvedPath) {
+		return &WorkflowSpec{WorkflowPath: result.ResolvedPath}
+	}
+	location, ref, ok := strings.Cut(result.ResolvedPath, "@")
+	parts := strings.SplitN(location, "/", 3)
+	if !ok || len(parts) != 3 {
+		return spec
+	}
+	return &WorkflowSpec{
+		RepoSpec:     RepoSpec{RepoSlug: parts[0] + "/" + parts[1], Version: ref},
+		WorkflowPath: parts[2],
+	}
+}
+
+// isWithinDir reports whether path is dir or inside it
+func isWithinDir(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// remoteFetchOptions configures how fetchAndSaveRemoteIncludes and
+// fetchAndSaveRemoteFrontmatterImports fetch and save the dependencies of a remote workflow.
+// Fetching the workflow itself only uses LogLevel, OnEvent, and MaxFileSize. The zero value reports no
+// progress and keeps existing files.
+type remoteFetchOptions struct {
+	LogLevel              FetchLogLevel     // Progress messages to emit
+	Force                 bool              // Rewrite existing files whose content changed upstream
+	Tracker               *FileTracker      // Tracks written files and records them in the fetch lock (may be nil)
+	MaxIncludeFiles       int               // Maximum number of include files fetched in total (0 uses the default)
+	RootedIncludePrefixes []string          // Relative include prefixes resolved under .github/ (defaults to shared/)
+	NamespaceShared       bool              // Save shared files under shared/<owner>-<repo>/
+	Transform             ContentTransform  // Optional rewrite of each file before it is saved
+	OnEvent               FetchEventHandler // Receives progress messages instead of stderr (may be nil)
+	MaxFileSize           int64             // Maximum size in bytes of each downloaded file (0 uses parser.DefaultMaxDownloadSize)
+	AllowedSources        []string          // Normalized allowed-sources patterns narrowing GH_AW_ALLOWED_SOURCES (see checkSourceAllowed)
+	Fetcher               *parser.Fetcher   // Downloads, resolves refs, and lists directories (nil uses the parser defaults)
+	Frozen                *frozenFetch      // Fetch lock that fetched files must match (nil unless frozen)
+	NoSymlinks            bool              // Refuse local workflow files that are, or resolve through, symbolic links
+	AllowHTTPIncludes     bool              // Fetch includes with http:// and https:// URLs
 }
 
 // fetchAndSaveRemoteFrontmatterImports fetches and saves files referenced in the frontmatter
@@ -250,16 +742,24 @@ func FetchIncludeFromSource(includePath string, baseSpec *WorkflowSpec, verbose
 // This is analogous to fetchAndSaveRemoteIncludes, which handles @include directives in the
 // markdown body; this function handles the YAML frontmatter 'imports:' field.
 // Import failures are non-fatal (best-effort); the compiler will report any still-missing files.
-func fetchAndSaveRemoteFrontmatterImports(content string, spec *WorkflowSpec, targetDir string, verbose bool, force bool, tracker *FileTracker) error {
+// With opts.NamespaceShared, imports under shared/ are saved to shared/<owner>-<repo>/ and the
+// imports of saved files are rewritten to match (see namespaceFrontmatterImports).
+// opts.LogLevel selects the progress messages: warnings about skipped imports are shown in quiet
+// and verbose mode, fetched and unchanged files only in verbose mode.
+// Returns the local paths of all files written, including transitively imported ones.
+func fetchAndSaveRemoteFrontmatterImports(content string, spec *WorkflowSpec, targetDir string, opts remoteFetchOptions) ([]string, error) {
 	if spec.RepoSlug == "" {
-		return nil
+		return nil, nil
 	}
 
 	parts := strings.SplitN(spec.RepoSlug, "/", 2)
 	if len(parts) != 2 {
-		return nil
+		return nil, nil
 	}
 	owner, repo := parts[0], parts[1]
+	if err := checkSourceAllowed(spec.RepoSlug, opts.AllowedSources); err != nil {
+		return nil, err
+	}
 	ref := spec.Version
 	if ref == "" {
 		// Resolve the actual default branch of the source repo rather than assuming "main"
@@ -277,63 +777,108 @@ func fetchAndSaveRemoteFrontmatterImports(content string, spec *WorkflowSpec, ta
 	// workflowBaseDir is the directory of the top-level workflow in the source repo
 	// (e.g. ".github/workflows"). It serves as both the starting point for resolving
 	// relative imports and as the prefix to strip when computing local target paths.
-	workflowBaseDir := getParentDir(spec.WorkflowPath)
+	workflowBaseDir := GetParentDir(spec.WorkflowPath)
+
+	fetcher := &importFetcher{
+		remoteFetchOptions: opts,
+		owner:              owner,
+		repo:               repo,
+		ref:                ref,
+		originalBaseDir:    workflowBaseDir,
+		targetDir:          targetDir,
+		seen:               make(map[string]bool),
+	}
+	if opts.NamespaceShared {
+		fetcher.namespace = sharedNamespace(spec.RepoSlug)
+	}
+	err := fetcher.fetch(content, workflowBaseDir, targetDir)
+	return fetcher.installed, err
+}
 
-	// seen is keyed by fully-resolved remote file path. It is shared across all recursion
-	// levels so that every import (at any depth) is downloaded at most once and import
-	// cycles (A imports B, B imports A) are broken without infinite recursion.
-	seen := make(map[string]bool)
-	fetchFrontmatterImportsRecursive(content, owner, repo, ref, workflowBaseDir, workflowBaseDir, targetDir, verbose, force, tracker, seen)
-	return nil
+// importFetcher holds the state of fetchAndSaveRemoteFrontmatterImports that is shared by all
+// levels of its recursion
+type importFetcher struct {
+	remoteFetchOptions
+	owner, repo, ref string // Source repository coordinates
+	originalBaseDir  string // Directory of the top-level workflow (used to map remote paths → local paths)
+	targetDir        string // The .github/workflows directory in the user's repo
+	namespace        string // Subdirectory inserted after shared/ in local paths (empty to disable)
+	// seen is keyed by fully-resolved remote file path, plus the local target for aliases, so
+	// that every import (at any depth) is downloaded at most once and import cycles (A imports
+	// B, B imports A) are broken without infinite recursion
+	seen      map[string]bool
+	installed []string // Local paths of every file written
 }
 
-// fetchFrontmatterImportsRecursive is the internal worker for fetchAndSaveRemoteFrontmatterImports.
+// frontmatterImport is an entry of the imports frontmatter field
+type frontmatterImport struct {
+	path      string // Import path as written (remote path for aliases)
+	localPath string // Local path of an alias (imports map form), empty for list entries
+}
+
+// parseFrontmatterImports returns the string entries of an imports field. Besides the list
+// form, imports may be a map from local paths to the remote paths they are fetched from, which
+// is returned sorted by local path.
+func parseFrontmatterImports(importsField any) []frontmatterImport {
+	var imports []frontmatterImport
+	switch v := importsField.(type) {
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				imports = append(imports, frontmatterImport{path: s})
+			}
+		}
+	case []string:
+		for _, s := range v {
+			imports = append(imports, frontmatterImport{path: s})
+		}
+	case map[string]any:
+		for _, localPath := range slices.Sorted(maps.Keys(v)) {
+			if remotePath, ok := v[localPath].(string); ok {
+				imports = append(imports, frontmatterImport{path: remotePath, localPath: localPath})
+			}
+		}
+	}
+	return imports
+}
+
+// fetch is the internal worker for fetchAndSaveRemoteFrontmatterImports.
 //
 // Parameters that change per recursion level:
 //   - content: the text of the file whose imports are being processed
 //   - currentBaseDir: directory of that file inside the source repo (used to resolve relative paths)
+//   - currentLocalDir: local directory of that file (used to resolve import alias targets)
 //
-// Parameters that remain constant across all recursion levels:
-//   - owner, repo, ref: source repository coordinates
-//   - originalBaseDir: directory of the top-level workflow (used to map remote paths → local paths)
-//   - targetDir: the `.github/workflows` directory in the user's repo
-//   - seen: shared visited set (keyed by fully-resolved remote path) — prevents cycles & duplicates
-func fetchFrontmatterImportsRecursive(content, owner, repo, ref, currentBaseDir, originalBaseDir, targetDir string, verbose, force bool, tracker *FileTracker, seen map[string]bool) {
+// Imports that cannot be fetched or saved are skipped with a warning; only frozen-mode
+// failures (see loadFrozenFetch) are returned.
+func (f *importFetcher) fetch(content, currentBaseDir, currentLocalDir string) error {
 	result, err := parser.ExtractFrontmatterFromContent(content)
 	if err != nil || result.Frontmatter == nil {
-		return
+		return nil
 	}
 
 	importsField, exists := result.Frontmatter["imports"]
 	if !exists {
-		return
-	}
-
-	var importPaths []string
-	switch v := importsField.(type) {
-	case []any:
-		for _, item := range v {
-			if s, ok := item.(string); ok {
-				importPaths = append(importPaths, s)
-			}
-		}
-	case []string:
-		importPaths = v
+		return nil
 	}
 
-	if len(importPaths) == 0 {
-		return
+	imports := parseFrontmatterImports(importsField)
+	if len(imports) == 0 {
+		return nil
 	}
 
 	// Pre-compute the absolute target directory once for path-traversal boundary checks.
-	absTargetDir, err := filepath.Abs(targetDir)
+	absTargetDir, err := filepath.Abs(f.targetDir)
 	if err != nil {
-		return
+		return nil
 	}
 
-	for _, importPath := range importPaths {
+	for _, imp := range imports {
+		// The checksum pin is verified against the downloaded bytes below
+		importPath, integrity := parser.SplitIncludeIntegrity(imp.path)
+
 		// Skip workflowspec-format imports (already pinned to a remote ref)
-		if isWorkflowSpecFormat(importPath) {
+		if IsWorkflowSpecFormat(importPath) {
 			continue
 		}
 
@@ -359,173 +904,806 @@ func fetchFrontmatterImportsRecursive(content, owner, repo, ref, currentBaseDir,
 		}
 		remoteFilePath = path.Clean(remoteFilePath)
 
-		// Reject paths that try to escape the repository root (e.g. "../../etc/passwd")
+		// Reject paths that escape the repository root once joined with currentBaseDir (e.g.
+		// "../../etc/passwd"). Paths that only pass through ".." but stay inside the repository,
+		// such as "../shared/x.md" from a nested workflow, are allowed.
 		if remoteFilePath == ".." || strings.HasPrefix(remoteFilePath, "../") {
-			if verbose {
-				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Skipping import with unsafe path: %q", importPath)))
+			if f.LogLevel.showWarnings() {
+				f.OnEvent.emit(FetchEventWarning, fmt.Sprintf("Skipping import with unsafe path: %q", importPath), importPath)
 			}
 			continue
 		}
 
-		// Cycle/duplicate prevention: use the fully-resolved remote path as the key.
-		if seen[remoteFilePath] {
+		// Cycle/duplicate prevention: use the fully-resolved remote path as the key, plus the
+		// local target for aliases since the same file may be aliased to several locations.
+		seenKey := remoteFilePath
+		if imp.localPath != "" {
+			seenKey += " -> " + filepath.Join(currentLocalDir, filepath.FromSlash(imp.localPath))
+		}
+		if f.seen[seenKey] {
 			continue
 		}
-		seen[remoteFilePath] = true
-
-		// Derive the local path relative to targetDir by stripping the original base-dir
-		// prefix from the remote path. This ensures that imports in nested files resolve
-		// to the correct location regardless of how many levels deep the recursion goes.
-		//
-		// Example: originalBaseDir=".github/workflows"
-		//   remoteFilePath=".github/workflows/shared/analysis.md" → localRelPath="shared/analysis.md"
-		//   (nested) remoteFilePath=".github/workflows/other.md"  → localRelPath="other.md"
-		var localRelPath string
-		if originalBaseDir != "" && strings.HasPrefix(remoteFilePath, originalBaseDir+"/") {
-			localRelPath = remoteFilePath[len(originalBaseDir)+1:]
+		f.seen[seenKey] = true
+
+		// Derive the local path from the remote path (see resolveImportLocalPath). Because it
+		// depends only on the remote path and the top-level workflow's directory, imports in
+		// nested files resolve to the same location regardless of the recursion depth.
+		var localRelPath, sourceRelPath string
+		if imp.localPath != "" {
+			// Aliases name their local target explicitly, relative to the importing file.
+			// They are not namespaced; the target directory check below still applies.
+			aliasPath, _, _ := strings.Cut(imp.localPath, "#")
+			if aliasPath == "" || strings.HasPrefix(aliasPath, "/") {
+				if f.LogLevel.showWarnings() {
+					f.OnEvent.emit(FetchEventWarning, fmt.Sprintf("Skipping import alias with unsafe local path: %q", imp.localPath), importPath)
+				}
+				continue
+			}
+			localRelPath = filepath.Join(currentLocalDir, filepath.FromSlash(aliasPath))
+			if rel, relErr := filepath.Rel(f.targetDir, localRelPath); relErr == nil {
+				localRelPath = rel
+			}
+			sourceRelPath = filepath.ToSlash(localRelPath)
 		} else {
-			// Workflow at repo root, or import outside the original base dir:
-			// use the full remote path relative to targetDir.
-			localRelPath = remoteFilePath
-		}
-		localRelPath = filepath.Clean(filepath.FromSlash(localRelPath))
-		// Strip any leading separator produced by Clean on root-relative paths.
-		localRelPath = strings.TrimLeft(localRelPath, string(filepath.Separator))
-		// Reject empty or "." paths (would point to targetDir itself) as a safety guard.
-		// ".." cannot appear here because remoteFilePath was already rejected above if it
-		// started with "..", and path.Clean cannot introduce new ".." components.
+			baseDir := f.originalBaseDir
+			if strings.HasPrefix(filePath, "/") {
+				baseDir = ""
+			}
+			localPath, resolveErr := resolveImportLocalPath(remoteFilePath, baseDir, f.targetDir)
+			if resolveErr != nil {
+				if f.LogLevel.showWarnings() {
+					f.OnEvent.emit(FetchEventWarning, fmt.Sprintf("Skipping import %q: %v", importPath, resolveErr), importPath)
+				}
+				continue
+			}
+			rel, relErr := filepath.Rel(f.targetDir, localPath)
+			if relErr != nil {
+				continue
+			}
+			sourceRelPath = filepath.ToSlash(rel)
+			localRelPath = filepath.FromSlash(namespaceSharedPath(sourceRelPath, f.namespace, nil))
+		}
+		// Reject empty or "." paths (would point to the target directory itself) as a safety guard.
+		// Imports were already placed by resolveImportLocalPath; aliases are bounded below.
 		if localRelPath == "" || localRelPath == "." {
 			continue
 		}
-		targetPath := filepath.Join(targetDir, localRelPath)
+		targetPath := filepath.Join(f.targetDir, localRelPath)
 
-		// Belt-and-suspenders: verify the resolved path is inside targetDir
+		// Belt-and-suspenders: aliases must stay inside the target directory, and other imports inside
+		// its parent (.github/), like relative includes
 		absTargetPath, absErr := filepath.Abs(targetPath)
 		if absErr != nil {
 			continue
 		}
-		if rel, relErr := filepath.Rel(absTargetDir, absTargetPath); relErr != nil || strings.HasPrefix(rel, "..") {
-			if verbose {
-				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Refusing to write import outside target directory: %q", importPath)))
+		boundary, boundaryName := filepath.Dir(absTargetDir), ".github/"
+		if imp.localPath != "" {
+			boundary, boundaryName = absTargetDir, "target directory"
+		}
+		if !isWithinDir(boundary, absTargetPath) {
+			if f.LogLevel.showWarnings() {
+				f.OnEvent.emit(FetchEventWarning, fmt.Sprintf("Refusing to write import outside %s: %q", boundaryName, importPath), importPath)
 			}
 			continue
 		}
 
-		// Check existence before downloading: if the file already exists and force=false,
+		// Check existence before downloading: if the file already exists and force is not set,
 		// skip the download entirely (no unnecessary network round-trip).
 		fileExists := false
 		if _, statErr := os.Stat(targetPath); statErr == nil {
 			fileExists = true
-			if !force {
-				if verbose {
-					fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Import file already exists, skipping: "+targetPath))
+			if !f.Force {
+				if f.LogLevel.showInfo() {
+					f.OnEvent.emit(FetchEventInfo, "Import file already exists, skipping: "+targetPath, targetPath)
 				}
 				continue
 			}
 		}
 
 		// Download from the source repository
-		importContent, err := parser.DownloadFileFromGitHub(owner, repo, remoteFilePath, ref)
+		source := fmt.Sprintf("%s/%s/%s@%s", f.owner, f.repo, remoteFilePath, f.ref)
+		importContent, err := downloadSourceFile(f.Fetcher, f.owner, f.repo, remoteFilePath, f.ref, f.MaxFileSize)
 		if err != nil {
-			if verbose {
-				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to fetch import %s: %v", remoteFilePath, err)))
+			if isFatalFetchError(err) {
+				return fmt.Errorf("failed to fetch import %s: %w", remoteFilePath, err)
+			}
+			if f.LogLevel.showWarnings() {
+				f.OnEvent.emit(FetchEventWarning, fmt.Sprintf("Failed to fetch import %s: %v", remoteFilePath, err), remoteFilePath)
 			}
 			continue
 		}
+		if err := parser.VerifyIncludeIntegrity(source, importContent, integrity); err != nil {
+			return fmt.Errorf("import %s: %w", remoteFilePath, err)
+		}
+		if _, section, hasSection := strings.Cut(importPath, "#"); hasSection && section != "" {
+			warnMissingSections("Import", filePath, importContent, []string{section}, f.OnEvent)
+		}
+		if f.Transform != nil {
+			if importContent, err = f.Transform(source, importContent); err != nil {
+				f.OnEvent.emit(FetchEventWarning, fmt.Sprintf("Failed to transform import %s, skipping: %v", remoteFilePath, err), remoteFilePath)
+				continue
+			}
+		}
 
 		// Create the parent directory if needed
 		if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
-			if verbose {
-				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to create directory for import %s: %v", remoteFilePath, err)))
+			if f.LogLevel.showWarnings() {
+				f.OnEvent.emit(FetchEventWarning, fmt.Sprintf("Failed to create directory for import %s: %v", remoteFilePath, err), remoteFilePath)
 			}
 			continue
 		}
 
-		// Write the file
-		if err := os.WriteFile(targetPath, importContent, 0600); err != nil {
-			if verbose {
-				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to write import %s: %v", remoteFilePath, err)))
+		// Point the saved file's own imports at the namespaced locations
+		localContent := importContent
+		if f.namespace != "" {
+			if rewritten, err := namespaceFrontmatterImports(string(importContent), sourceRelPath, f.namespace); err == nil {
+				localContent = []byte(rewritten)
+			} else {
+				remoteWorkflowLog.Printf("Failed to namespace imports of %s: %v", remoteFilePath, err)
 			}
-			continue
 		}
 
-		if verbose {
-			fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Fetched import: "+targetPath))
+		// Existing files whose content is unchanged upstream are left as-is
+		blobSHA := gitBlobSHA(localContent)
+		if err := f.Frozen.verify(targetPath, blobSHA); err != nil {
+			return fmt.Errorf("import %s: %w", remoteFilePath, err)
 		}
+		if fileExists && fileHasBlobSHA(targetPath, blobSHA) {
+			if f.LogLevel.showInfo() {
+				f.OnEvent.emit(FetchEventInfo, "Import file unchanged, skipping: "+targetPath, targetPath)
+			}
+		} else {
+			// Write the file
+			if err := fileutil.WriteFileAtomic(targetPath, localContent, 0600); err != nil {
+				if f.LogLevel.showWarnings() {
+					f.OnEvent.emit(FetchEventWarning, fmt.Sprintf("Failed to write import %s: %v", remoteFilePath, err), remoteFilePath)
+				}
+				continue
+			}
 
-		// Track the file for git staging and potential rollback
-		if tracker != nil {
-			if fileExists {
-				tracker.TrackModified(targetPath)
-			} else {
-				tracker.TrackCreated(targetPath)
+			if f.LogLevel.showInfo() {
+				f.OnEvent.emit(FetchEventSuccess, "Fetched import: "+targetPath, targetPath)
+			}
+
+			f.installed = append(f.installed, targetPath)
+
+			// Track the file for git staging and potential rollback
+			if f.Tracker != nil {
+				if fileExists {
+					f.Tracker.TrackModified(targetPath)
+				} else {
+					f.Tracker.TrackCreated(targetPath)
+				}
 			}
 		}
 
+		// Frozen fetches leave the fetch lock unchanged
+		if f.Frozen == nil {
+			recordFetchedFile(f.Tracker, targetPath, source, blobSHA)
+		}
+
 		// Recurse into the imported file's imports. Use the imported file's directory as
 		// currentBaseDir so that relative paths inside it resolve correctly.
 		importedBaseDir := path.Dir(remoteFilePath)
-		fetchFrontmatterImportsRecursive(string(importContent), owner, repo, ref, importedBaseDir, originalBaseDir, targetDir, verbose, force, tracker, seen)
+		if err := f.fetch(string(importContent), importedBaseDir, filepath.Dir(targetPath)); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-// fetchAndSaveRemoteIncludes parses the workflow content for @include directives and fetches them from the remote source
-func fetchAndSaveRemoteIncludes(content string, spec *WorkflowSpec, targetDir string, verbose bool, force bool, tracker *FileTracker) error {
-	remoteWorkflowLog.Printf("Fetching remote includes for workflow: %s", spec.String())
+// remoteInclude groups all @include directives that reference the same file
+type remoteInclude struct {
+	includePath string   // First directive path seen for the file (used for fetching)
+	filePath    string   // Include path without the #section fragment and checksum pin
+	optional    bool     // true only if every directive for the file is optional
+	wholeFile   bool     // true if any directive includes the file without a section
+	sections    []string // Referenced section names, in order of first appearance
+	conditions  []string // Include conditions such as "engine=copilot", evaluated at compile time
+	target      string   // Local path from a "path -> target" override, resolved like a relative include
+}
 
-	// Parse the workflow content to find @include directives
-	includePattern := regexp.MustCompile(`^@include(\?)?\s+(.+)$`)
+// warnUnknownIncludeConditions warns about include conditions that compilation cannot honor,
+// such as unsupported condition keys or engine IDs that are not registered
+func warnUnknownIncludeConditions(include *remoteInclude, events FetchEventHandler) {
+	registry := workflow.GetGlobalEngineRegistry()
+	for _, condition := range include.conditions {
+		engines, err := parser.ParseIncludeEngineCondition(condition)
+		if err != nil {
+			events.emit(FetchEventWarning, fmt.Sprintf("Include %s: %v", include.includePath, err), include.includePath)
+			continue
+		}
+		for _, engine := range engines {
+			if !registry.IsValidEngine(engine) {
+				events.emit(FetchEventWarning, fmt.Sprintf("Include %s has a condition for unknown engine '%s'; it will never be included. Known engines: %s",
+					include.includePath, engine, strings.Join(registry.GetSupportedEngines(), ", ")), include.includePath)
+			}
+		}
+	}
+}
 
-	scanner := bufio.NewScanner(strings.NewReader(content))
-	seen := make(map[string]bool)
+// warnMissingSections warns about each section that is requested from a fetched include or
+// import but not found in its content, listing the sections that are available
+func warnMissingSections(kind, filePath string, content []byte, sections []string, events FetchEventHandler) {
+	if len(sections) == 0 {
+		return
+	}
+	result, err := parser.ExtractFrontmatterFromContent(string(content))
+	if err != nil {
+		return
+	}
+	for _, section := range sections {
+		if _, err := parser.ExtractMarkdownSection(result.Markdown, section); err != nil {
+			events.emit(FetchEventWarning, fmt.Sprintf("%s %s#%s references a missing section: %v", kind, filePath, section, err), filePath)
+		}
+	}
+}
+
+// remoteIncludePattern matches an @include directive line.
+// Group 1: optional marker, Group 2: include condition, Group 3: path (with optional #section
+// and " -> target" override)
+var remoteIncludePattern = regexp.MustCompile(`^@include(\?)?(?:\[([^\]]*)\])?\s+(.+)$`)
 
+// collectRemoteIncludes scans workflow content for @include directives and groups them by file,
+// preserving the order in which files are first referenced
+func collectRemoteIncludes(content string) []*remoteInclude {
+	var includes []*remoteInclude
+	byPath := make(map[string]*remoteInclude)
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
 	for scanner.Scan() {
-		line := scanner.Text()
-		matches := includePattern.FindStringSubmatch(line)
+		matches := remoteIncludePattern.FindStringSubmatch(scanner.Text())
 		if matches == nil {
 			continue
 		}
 
 		isOptional := matches[1] == "?"
-		includePath := strings.TrimSpace(matches[2])
+		condition := strings.TrimSpace(matches[2])
+		includePath, target := parser.SplitIncludeTarget(strings.TrimSpace(matches[3]))
+
+		// Split off the section reference and checksum pin for file fetching; the pin stays
+		// in includePath and is verified by fetchIncludeFromSource
+		filePath, section, hasSection := strings.Cut(includePath, "#")
+		filePath, _ = parser.SplitIncludeIntegrity(filePath)
+
+		// The same file saved to different targets is fetched once per target
+		key := filePath
+		if target != "" {
+			key += " -> " + target
+		}
+		include, exists := byPath[key]
+		if !exists {
+			include = &remoteInclude{includePath: includePath, filePath: filePath, optional: true, target: target}
+			byPath[key] = include
+			includes = append(includes, include)
+		}
+		include.optional = include.optional && isOptional
+		if !hasSection || section == "" {
+			include.wholeFile = true
+		} else if !slices.Contains(include.sections, section) {
+			include.sections = append(include.sections, section)
+		}
+		if condition != "" && !slices.Contains(include.conditions, condition) {
+			include.conditions = append(include.conditions, condition)
+		}
+	}
 
-		// Remove section reference for file fetching
-		filePath := includePath
-		if before, _, ok := strings.Cut(includePath, "#"); ok {
-			filePath = before
+	return includes
+}
+
+// listIncludeDirectory lists the .md files directly inside a remote directory.
+// It is a variable so tests can substitute the GitHub contents API.
+var listIncludeDirectory = listSourceDir
+
+// isDirectoryInclude reports whether an include path (without #section) names a directory,
+// which is written with a trailing slash, e.g. "shared/prompts/" or "owner/repo/prompts/@v1".
+// URLs never name directories, since HTTP servers cannot be listed.
+func isDirectoryInclude(filePath string) bool {
+	if isHTTPIncludePath(filePath) {
+		return false
+	}
+	pathPart, _, _ := strings.Cut(filePath, "@")
+	return strings.HasSuffix(pathPart, "/")
+}
+
+// checkDirectoryIncludeUnpinned rejects a checksum pin on a directory include, whose files
+// cannot share one checksum
+func checkDirectoryIncludeUnpinned(includePath string) error {
+	if _, integrity := parser.SplitIncludeIntegrity(includePath); integrity != "" {
+		return fmt.Errorf("include %s: checksum pins are only supported on files, not directories", includePath)
+	}
+	return nil
+}
+
+// listRemoteIncludeDirectory enumerates the .md files of a directory include at the
+// resolved ref and returns them as include paths in the same form as the directive
+func listRemoteIncludeDirectory(dirPath string, spec *WorkflowSpec, opts remoteFetchOptions) ([]string, error) {
+	pathPart, ref, hasRef := strings.Cut(dirPath, "@")
+	pathPart = strings.TrimSuffix(pathPart, "/")
+
+	if IsWorkflowSpecFormat(dirPath) {
+		slashParts := strings.Split(pathPart, "/")
+		if len(slashParts) < 3 {
+			return nil, errors.New("invalid workflowspec: must be owner/repo/path[@ref]")
+		}
+		owner, repo := slashParts[0], slashParts[1]
+		if !hasRef || ref == "" {
+			ref = "main"
+		}
+		if err := checkSourceAllowed(owner+"/"+repo, opts.AllowedSources); err != nil {
+			return nil, err
 		}
 
-		// Skip if already processed
-		if seen[filePath] {
+		files, err := listIncludeDirectory(opts.Fetcher, owner, repo, ref, strings.Join(slashParts[2:], "/"))
+		if err != nil {
+			return nil, err
+		}
+		includePaths := make([]string, 0, len(files))
+		for _, file := range files {
+			includePaths = append(includePaths, fmt.Sprintf("%s/%s/%s@%s", owner, repo, file, ref))
+		}
+		return includePaths, nil
+	}
+
+	if spec == nil || spec.RepoSlug == "" {
+		return nil, fmt.Errorf("cannot resolve include directory: %s (no base spec provided)", dirPath)
+	}
+	owner, repo, ok := strings.Cut(spec.RepoSlug, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid repository slug: %s", spec.RepoSlug)
+	}
+	ref = spec.Version
+	if ref == "" {
+		ref = "main"
+	}
+
+	// Resolve the directory the same way fetchIncludeFromSource resolves files
+	fullPath := pathPart
+	if isRootedIncludePath(pathPart+"/", opts.RootedIncludePrefixes) {
+		fullPath = ".github/" + pathPart
+	} else if baseDir := GetParentDir(spec.WorkflowPath); baseDir != "" {
+		fullPath = baseDir + "/" + pathPart
+	}
+
+	files, err := listIncludeDirectory(opts.Fetcher, owner, repo, ref, fullPath)
+	if err != nil {
+		return nil, err
+	}
+	includePaths := make([]string, 0, len(files))
+	for _, file := range files {
+		includePaths = append(includePaths, pathPart+"/"+path.Base(file))
+	}
+	return includePaths, nil
+}
+
+// expandDirectoryIncludeDirectives rewrites each directory include directive in content into one
+// whole-file include per .md file in the directory, keeping its optional marker and condition.
+// Compilation only reads files, so the directives of the added workflow and of saved includes
+// must name files. Files that are already included individually, or by an earlier directory,
+// are not repeated. An empty directory produces a warning. A directory that cannot be listed is
+// an error unless its directive is optional (or optional is set, for content reached only
+// through optional includes), in which case the directive is dropped.
+func expandDirectoryIncludeDirectives(content string, spec *WorkflowSpec, optional bool, opts remoteFetchOptions) (string, error) {
+	seen := make(map[string]bool)
+	hasDirectory := false
+	for _, include := range collectRemoteIncludes(content) {
+		if isDirectoryInclude(include.filePath) {
+			hasDirectory = true
+		} else {
+			seen[include.filePath] = true
+		}
+	}
+	if !hasDirectory {
+		return content, nil
+	}
+
+	var builder strings.Builder
+	for _, line := range strings.SplitAfter(content, "\n") {
+		matches := remoteIncludePattern.FindStringSubmatch(strings.TrimRight(line, "\r\n"))
+		if matches == nil {
+			builder.WriteString(line)
+			continue
+		}
+		includePath, target := parser.SplitIncludeTarget(strings.TrimSpace(matches[3]))
+		filePath, _, _ := strings.Cut(includePath, "#")
+		if !isDirectoryInclude(filePath) {
+			builder.WriteString(line)
+			continue
+		}
+		if err := checkDirectoryIncludeUnpinned(includePath); err != nil {
+			return "", err
+		}
+		if target != "" {
+			return "", fmt.Errorf("include %s: target paths are only supported on files, not directories", includePath)
+		}
+
+		files, err := listRemoteIncludeDirectory(filePath, spec, opts)
+		if err != nil {
+			if (optional || matches[1] == "?") && !isFatalFetchError(err) {
+				if opts.LogLevel.showWarnings() {
+					opts.OnEvent.emit(FetchEventWarning, "Optional include directory not found: "+filePath, filePath)
+				}
+				continue
+			}
+			return "", fmt.Errorf("failed to list include directory %s: %w", filePath, err)
+		}
+		if len(files) == 0 {
+			opts.OnEvent.emit(FetchEventWarning, "Include directory contains no .md files: "+filePath, filePath)
+			continue
+		}
+
+		remoteWorkflowLog.Printf("Expanded include directory %s to %d files", filePath, len(files))
+		directive := "@include" + matches[1]
+		if matches[2] != "" {
+			directive += "[" + matches[2] + "]"
+		}
+		for _, file := range files {
+			if seen[file] {
+				continue
+			}
+			seen[file] = true
+			builder.WriteString(directive + " " + file + "\n")
+		}
+	}
+
+	return builder.String(), nil
+}
+
+// extractIncludeSections builds a section-scoped copy of an include file that keeps the
+// original frontmatter (so tools and other settings still merge) followed by only the
+// requested markdown sections. Each section runs from its heading to the next heading
+// of the same or higher level.
+func extractIncludeSections(content string, sections []string) (string, error) {
+	result, err := parser.ExtractFrontmatterFromContent(content)
+	if err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	if result.Format != "" {
+		// Copy the frontmatter verbatim, including its opening and closing fences
+		lines := strings.Split(content, "\n")
+		builder.WriteString(strings.Join(lines[:len(result.FrontmatterLines)+2], "\n"))
+		builder.WriteString("\n\n")
+	}
+
+	extracted := make([]string, 0, len(sections))
+	for _, section := range sections {
+		sectionContent, err := parser.ExtractMarkdownSection(result.Markdown, section)
+		if err != nil {
+			return "", err
+		}
+		extracted = append(extracted, sectionContent)
+	}
+	builder.WriteString(strings.Join(extracted, "\n\n"))
+	builder.WriteString("\n")
+
+	return builder.String(), nil
+}
+
+// includeSectionHeadingPattern matches the markdown headings that start a section, like the
+// parser's section extraction
+var includeSectionHeadingPattern = regexp.MustCompile(`^(#{1,3})[ \t]+(.*?)[ \t]*$`)
+
+// savedIncludeSections returns the sections of the upstream include file that a section-scoped
+// copy saved by extractIncludeSections contains, in the order they appear: each heading at or
+// above the level of the section before it starts another section. whole is true when the copy
+// does not start with a heading, like a copy of the whole file with leading text.
+func savedIncludeSections(saved, upstream string) (sections []string, whole bool) {
+	savedResult, err := parser.ExtractFrontmatterFromContent(saved)
+	if err != nil {
+		return nil, true
+	}
+	upstreamResult, err := parser.ExtractFrontmatterFromContent(upstream)
+	if err != nil {
+		return nil, true
+	}
+
+	level := 0
+	for line := range strings.SplitSeq(savedResult.Markdown, "\n") {
+		matches := includeSectionHeadingPattern.FindStringSubmatch(line)
+		if level == 0 && matches == nil {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			return nil, true
+		}
+		if matches == nil || (level != 0 && len(matches[1]) > level) {
 			continue
 		}
-		seen[filePath] = true
+		level = len(matches[1])
+		if _, err := parser.ExtractMarkdownSection(upstreamResult.Markdown, matches[2]); err == nil {
+			sections = append(sections, matches[2])
+		}
+	}
+	return sections, level == 0
+}
+
+// mergeIncludeSections returns sections followed by each of more that is not already among
+// them, dropping sections of content nested inside another selected section
+func mergeIncludeSections(content string, sections, more []string) []string {
+	result, err := parser.ExtractFrontmatterFromContent(content)
+	if err != nil {
+		return sections
+	}
+
+	merged := slices.Clone(sections)
+	for _, section := range more {
+		if !slices.ContainsFunc(merged, func(s string) bool { return parser.HeadingSlug(s) == parser.HeadingSlug(section) }) {
+			merged = append(merged, section)
+		}
+	}
+
+	texts := make([]string, len(merged))
+	for i, section := range merged {
+		texts[i], _ = parser.ExtractMarkdownSection(result.Markdown, section)
+	}
+	kept := make([]string, 0, len(merged))
+	for i, section := range merged {
+		nested := false
+		for j, text := range texts {
+			if j != i && texts[i] != "" && len(text) > len(texts[i]) && strings.Contains(text, texts[i]) {
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			kept = append(kept, section)
+		}
+	}
+	return kept
+}
+
+// includeSourceString describes where an include was fetched from for the fetch lock
+func includeSourceString(includePath string, spec *WorkflowSpec) string {
+	if isHTTPIncludePath(includePath) || IsWorkflowSpecFormat(includePath) {
+		return includePath
+	}
+	if spec.RepoSlug == "" && isHTTPIncludePath(spec.WorkflowPath) {
+		if resolved, err := resolveHTTPIncludeURL(spec.WorkflowPath, includePath); err == nil {
+			return resolved
+		}
+	}
+	source := spec.RepoSlug + "/" + includePath
+	if spec.Version != "" {
+		source += "@" + spec.Version
+	}
+	return source
+}
+
+// defaultMaxIncludeFiles is the default limit on the total number of include files
+// fetched for a single workflow, across all levels of nesting
+const defaultMaxIncludeFiles = 100
+
+// ErrIncludeBudgetExceeded is returned when fetching includes would exceed the file budget
+var ErrIncludeBudgetExceeded = errors.New("include fetch budget exceeded")
+
+// includeFetchBudget bounds the total number of include files fetched across the
+// whole recursion, independently of nesting depth
+type includeFetchBudget struct {
+	limit int
+	used  int
+}
+
+// newIncludeFetchBudget creates a budget, using defaultMaxIncludeFiles when limit is not positive
+func newIncludeFetchBudget(limit int) *includeFetchBudget {
+	if limit <= 0 {
+		limit = defaultMaxIncludeFiles
+	}
+	return &includeFetchBudget{limit: limit}
+}
+
+// take consumes one unit of the budget for the given include, failing once the limit is reached
+func (b *includeFetchBudget) take(includePath string) error {
+	if b.used >= b.limit {
+		return fmt.Errorf("%w: refusing to fetch %s after %d include files (limit %d)",
+			ErrIncludeBudgetExceeded, includePath, b.used, b.limit)
+	}
+	b.used++
+	return nil
+}
+
+// fetchAndSaveRemoteIncludes parses the workflow content for @include directives and fetches them from the remote source.
+// Includes that only reference sections (file.md#Section) are written with just those sections,
+// merged into a single local file per source file.
+// At most opts.MaxIncludeFiles include files are fetched in total, including nested ones
+// (defaultMaxIncludeFiles when it is not positive).
+// Relative includes starting with one of opts.RootedIncludePrefixes (defaultRootedIncludePrefixes when empty) are
+// fetched from and saved under .github/.
+// An include path ending in "/" names a directory and expands to every .md file in it; saved
+// files include each of them individually (see expandDirectoryIncludeDirectives).
+// With opts.NamespaceShared, rooted and workflowspec includes are saved under a per-source
+// subdirectory (e.g. shared/<owner>-<repo>/) and rooted @include directives in saved files are
+// rewritten to match, so shared files from different repositories do not collide.
+// Workflowspec includes are flattened into shared/ under their file name, with a short hash of
+// the source appended when that name is already taken by a different source.
+// Includes reached only through optional includes are optional themselves, so a missing file
+// below an @include? never fails the fetch; failures below required includes do.
+// With opts.Force, existing includes are only rewritten when their content changed; in verbose mode
+// each one is reported as unchanged or updated, with a count of the changed lines. Warnings
+// about skipped includes are also shown in quiet mode.
+// Returns the local paths of all files written, including nested includes.
+func fetchAndSaveRemoteIncludes(content string, spec *WorkflowSpec, targetDir string, opts remoteFetchOptions) ([]string, error) {
+	fetcher := &includeFetcher{
+		remoteFetchOptions: opts,
+		targetDir:          targetDir,
+		budget:             newIncludeFetchBudget(opts.MaxIncludeFiles),
+		flattened:          make(map[string]string),
+	}
+	err := fetcher.fetch(content, spec, targetDir, false)
+	return fetcher.installed, err
+}
+
+// includeFetcher holds the state of fetchAndSaveRemoteIncludes that is shared by all levels of
+// its recursion
+type includeFetcher struct {
+	remoteFetchOptions
+	targetDir string              // The .github/workflows directory in the user's repo
+	budget    *includeFetchBudget // Include files left to fetch
+	flattened map[string]string   // Sources of the flattened workflowspec includes, keyed by local path
+	installed []string            // Local paths of every file written
+}
+
+// fetch is the internal worker for fetchAndSaveRemoteIncludes.
+//
+// Parameters that change per recursion level:
+//   - content: the text of the file whose includes are being processed
+//   - spec: the location of that file in its source repository, against which relative
+//     includes are resolved
+//   - localDir: the local directory of that file, under which relative includes are saved
+//   - optional: whether that file was reached only through optional includes, which makes
+//     all of its includes optional
+func (f *includeFetcher) fetch(content string, spec *WorkflowSpec, localDir string, optional bool) error {
+	remoteWorkflowLog.Printf("Fetching remote includes for workflow: %s", spec.String())
+
+	content, err := expandDirectoryIncludeDirectives(content, spec, optional, f.remoteFetchOptions)
+	if err != nil {
+		return err
+	}
+	includes := collectRemoteIncludes(content)
+	if optional {
+		for _, include := range includes {
+			include.optional = true
+		}
+	}
+
+	for _, include := range includes {
+		filePath := include.filePath
+
+		if err := f.budget.take(include.includePath); err != nil {
+			return err
+		}
 
 		// Fetch the include file
-		includeContent, _, err := FetchIncludeFromSource(includePath, spec, verbose)
+		result, err := fetchIncludeFromSource(include.includePath, spec, f.remoteFetchOptions)
 		if err != nil {
-			if isOptional {
-				if verbose {
-					fmt.Fprintln(os.Stderr, console.FormatWarningMessage("Optional include not found: "+includePath))
+			if include.optional && !isFatalFetchError(err) {
+				if f.LogLevel.showWarnings() {
+					f.OnEvent.emit(FetchEventWarning, "Optional include not found: "+include.includePath, include.includePath)
 				}
 				continue
 			}
-			return fmt.Errorf("failed to fetch include %s: %w", includePath, err)
+			return fmt.Errorf("failed to fetch include %s: %w", include.includePath, err)
 		}
+		remoteWorkflowLog.Printf("Fetched include %s from %s", include.includePath, result.ResolvedPath)
+		warnUnknownIncludeConditions(include, f.OnEvent)
+		// Save directory includes inside the file as one include per file, like the workflow's
+		nestedSpec := includedFileSpec(result, spec)
 
 		// Determine target path for the include file
 		var targetPath string
-		if strings.HasPrefix(filePath, "shared/") {
-			// shared/ files go to .github/shared/
-			targetPath = filepath.Join(filepath.Dir(targetDir), filePath)
-		} else if isWorkflowSpecFormat(filePath) {
+		namespace := ""
+		if include.target != "" {
+			// An explicit target replaces the default layout and is resolved like a relative
+			// include, without namespacing
+			targetPath = filepath.Join(localDir, filepath.FromSlash(include.target))
+			if isRootedIncludePath(include.target, f.RootedIncludePrefixes) {
+				targetPath = filepath.Join(filepath.Dir(f.targetDir), filepath.FromSlash(include.target))
+			}
+			if filepath.IsAbs(include.target) || !isWithinDir(filepath.Dir(f.targetDir), targetPath) {
+				if f.LogLevel.showWarnings() {
+					f.OnEvent.emit(FetchEventWarning, fmt.Sprintf("Refusing to write include %s outside .github/: %q", filePath, include.target), filePath)
+				}
+				continue
+			}
+		} else if isHTTPIncludePath(result.ResolvedPath) {
+			// HTTP(S) includes mirror their URL under shared/<host>/ in the workflows directory,
+			// so relative includes inside them resolve locally as they do against the URL
+			localPath, err := httpIncludeLocalPath(result.ResolvedPath)
+			if err != nil {
+				return fmt.Errorf("include %s: %w", filePath, err)
+			}
+			targetPath = filepath.Join(f.targetDir, filepath.FromSlash(localPath))
+		} else if isRootedIncludePath(filePath, f.RootedIncludePrefixes) {
+			// Rooted files (e.g. shared/) go under .github/
+			localPath := filePath
+			if f.NamespaceShared {
+				namespace = sharedNamespace(spec.RepoSlug)
+				localPath = namespaceSharedPath(filePath, namespace, f.RootedIncludePrefixes)
+			}
+			targetPath = filepath.Join(filepath.Dir(f.targetDir), localPath)
+		} else if IsWorkflowSpecFormat(filePath) {
 			// Workflowspec includes: extract just the filename and put in shared/
 			parts := strings.Split(filePath, "/")
-			filename := parts[len(parts)-1]
-			targetPath = filepath.Join(filepath.Dir(targetDir), "shared", filename)
+			sharedDir := filepath.Join(filepath.Dir(f.targetDir), "shared")
+			if f.NamespaceShared && len(parts) >= 3 {
+				sharedDir = filepath.Join(sharedDir, sharedNamespace(parts[0]+"/"+parts[1]))
+			}
+			targetPath = flattenedIncludePath(sharedDir, filePath, f.flattened, f.Tracker)
+			if f.LogLevel.showInfo() && filepath.Base(targetPath) != path.Base(workflowSpecFilePath(filePath)) {
+				f.OnEvent.emit(FetchEventInfo, fmt.Sprintf("Include %s saved as %s to avoid overwriting a same-named include from another source", filePath, targetPath), targetPath)
+			}
 		} else {
-			// Relative includes go alongside the workflow
-			targetPath = filepath.Join(targetDir, filePath)
+			// Relative includes go alongside the file that includes them
+			targetPath = filepath.Join(localDir, filePath)
+			if !isWithinDir(filepath.Dir(f.targetDir), targetPath) {
+				if f.LogLevel.showWarnings() {
+					f.OnEvent.emit(FetchEventWarning, fmt.Sprintf("Refusing to write include outside .github/: %q", filePath), filePath)
+				}
+				continue
+			}
+		}
+
+		// prepare builds the content of the include as included by the workflow and as saved
+		// locally, keeping only the given sections unless the whole file is included
+		prepare := func(wholeFile bool, sections []string) (includeContent, localContent []byte, err error) {
+			includeContent = result.Content
+			if wholeFile {
+				warnMissingSections("Include", filePath, includeContent, sections, f.OnEvent)
+			} else {
+				scoped, err := extractIncludeSections(string(includeContent), sections)
+				if err != nil {
+					return nil, nil, fmt.Errorf("failed to extract sections from include %s: %w", filePath, err)
+				}
+				remoteWorkflowLog.Printf("Scoped include %s to sections %v", filePath, sections)
+				includeContent = []byte(scoped)
+			}
+			if f.Transform != nil {
+				if includeContent, err = f.Transform(includeSourceString(include.includePath, spec), includeContent); err != nil {
+					return nil, nil, fmt.Errorf("failed to transform include %s: %w", filePath, err)
+				}
+			}
+			expanded, err := expandDirectoryIncludeDirectives(string(includeContent), nestedSpec, include.optional, f.remoteFetchOptions)
+			if err != nil {
+				return nil, nil, fmt.Errorf("include %s: %w", filePath, err)
+			}
+			includeContent = []byte(expanded)
+
+			localContent = includeContent
+			if namespace != "" {
+				localContent = []byte(namespaceIncludeDirectives(string(includeContent), namespace, f.RootedIncludePrefixes))
+			}
+			// Point HTTP(S) includes inside the file at their local copies
+			localContent = []byte(localizeHTTPIncludeDirectives(string(localContent), filepath.Dir(targetPath), f.targetDir))
+			return includeContent, localContent, nil
+		}
+
+		// A section-scoped copy saved for another workflow keeps its sections, so workflows
+		// that include different sections of the same file can share it
+		wholeFile, sections := include.wholeFile, include.sections
+		mergeSections := false
+		existing, readErr := os.ReadFile(targetPath)
+		fileExists := readErr == nil
+		if fileExists && !wholeFile {
+			saved, savedWhole := savedIncludeSections(string(existing), string(result.Content))
+			if savedWhole {
+				wholeFile = f.Force
+			} else {
+				merged := mergeIncludeSections(string(result.Content), saved, sections)
+				if len(merged) > len(saved) {
+					if _, savedContent, err := prepare(false, saved); err == nil && bytes.Equal(savedContent, existing) {
+						mergeSections = true
+					}
+				}
+				if mergeSections || f.Force {
+					sections = merged
+				}
+			}
+		}
+
+		includeContent, localContent, err := prepare(wholeFile, sections)
+		if err != nil {
+			return err
 		}
 
 		// Create target directory if needed
@@ -533,40 +1711,75 @@ func fetchAndSaveRemoteIncludes(content string, spec *WorkflowSpec, targetDir st
 			return fmt.Errorf("failed to create directory for %s: %w", targetPath, err)
 		}
 
-		// Check if file already exists
-		fileExists := false
-		if _, err := os.Stat(targetPath); err == nil {
-			fileExists = true
-			if !force {
-				if verbose {
-					fmt.Fprintln(os.Stderr, console.FormatWarningMessage("Include file already exists, skipping: "+targetPath))
-				}
-				continue
+		// Existing files are kept unless forced or missing sections another workflow includes
+		if fileExists && !f.Force && !mergeSections {
+			if f.LogLevel.showWarnings() {
+				f.OnEvent.emit(FetchEventWarning, "Include file already exists, skipping: "+targetPath, targetPath)
 			}
+			continue
 		}
 
-		// Write the include file
-		if err := os.WriteFile(targetPath, includeContent, 0600); err != nil {
-			return fmt.Errorf("failed to write include file %s: %w", targetPath, err)
+		// Existing files whose content is unchanged upstream are left as-is
+		blobSHA := gitBlobSHA(localContent)
+		if err := f.Frozen.verify(targetPath, blobSHA); err != nil {
+			return fmt.Errorf("include %s: %w", filePath, err)
 		}
+		if fileExists && fileHasBlobSHA(targetPath, blobSHA) {
+			if f.LogLevel.showInfo() {
+				f.OnEvent.emit(FetchEventInfo, "Include file unchanged, skipping: "+targetPath, targetPath)
+			}
+		} else {
+			// Keep the previous content of overwritten files to report what changed
+			var previousContent []byte
+			if fileExists && f.LogLevel.showInfo() {
+				previousContent, _ = os.ReadFile(targetPath)
+			}
 
-		if verbose {
-			fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Fetched include: "+targetPath))
-		}
+			// Write the include file
+			if err := fileutil.WriteFileAtomic(targetPath, localContent, 0600); err != nil {
+				return fmt.Errorf("failed to write include file %s: %w", targetPath, err)
+			}
 
-		// Track the file
-		if tracker != nil {
-			if fileExists {
-				tracker.TrackModified(targetPath)
-			} else {
-				tracker.TrackCreated(targetPath)
+			if f.LogLevel.showInfo() {
+				message := "Fetched include: " + targetPath
+				if fileExists {
+					message = "Updated include: " + targetPath
+					if stat := diffStat(string(previousContent), string(localContent)); stat != "" {
+						message += " (" + stat + ")"
+					}
+				}
+				if len(include.conditions) > 0 {
+					message += fmt.Sprintf(" (included at compile time when %s)", strings.Join(include.conditions, " or "))
+				}
+				f.OnEvent.emit(FetchEventSuccess, message, targetPath)
+			}
+
+			f.installed = append(f.installed, targetPath)
+
+			// Track the file
+			if f.Tracker != nil {
+				if fileExists {
+					f.Tracker.TrackModified(targetPath)
+				} else {
+					f.Tracker.TrackCreated(targetPath)
+				}
 			}
 		}
 
-		// Recursively fetch includes from the fetched file
-		if err := fetchAndSaveRemoteIncludes(string(includeContent), spec, targetDir, verbose, force, tracker); err != nil {
-			if verbose {
-				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to fetch nested includes from %s: %v", filePath, err)))
+		// Frozen fetches leave the fetch lock unchanged
+		if f.Frozen == nil {
+			recordFetchedFile(f.Tracker, targetPath, includeSourceString(include.includePath, spec), blobSHA)
+		}
+
+		// Recursively fetch includes from the fetched file, resolving its relative includes
+		// against its own location both in the source repository and locally. Its includes
+		// inherit the optionality of this include.
+		if err := f.fetch(string(includeContent), nestedSpec, filepath.Dir(targetPath), include.optional); err != nil {
+			if !include.optional || errors.Is(err, ErrIncludeBudgetExceeded) || isFatalFetchError(err) {
+				return fmt.Errorf("include %s: %w", filePath, err)
+			}
+			if f.LogLevel.showWarnings() {
+				f.OnEvent.emit(FetchEventWarning, fmt.Sprintf("Failed to fetch nested includes from %s: %v", filePath, err), filePath)
 			}
 		}
 	}
@@ -574,11 +1787,66 @@ func fetchAndSaveRemoteIncludes(content string, spec *WorkflowSpec, targetDir st
 	return nil
 }
 
-// getParentDir returns the directory part of a path
-func getParentDir(path string) string {
-	idx := strings.LastIndex(path, "/")
-	if idx == -1 {
-		return ""
+// workflowSpecFilePath returns a workflowspec include path without its ref,
+// e.g. "owner/repo/shared/tools.md@v1" → "owner/repo/shared/tools.md"
+func workflowSpecFilePath(filePath string) string {
+	specPath, _, _ := strings.Cut(filePath, "@")
+	return specPath
+}
+
+// flattenedIncludePath returns where a workflowspec include is saved in dir. The file name of the
+// source is used unless another source already claims it, either earlier in this fetch (tracked
+// in flattened, keyed by local path) or in the fetch lock. The include is then saved as
+// <name>-<hash>.<ext>, where hash is derived from the source path, so the same source always gets
+// the same name.
+func flattenedIncludePath(dir, filePath string, flattened map[string]string, tracker *FileTracker) string {
+	source := workflowSpecFilePath(filePath)
+	filename := path.Base(source)
+	targetPath := filepath.Join(dir, filename)
+
+	owner, claimed := flattened[targetPath]
+	if !claimed {
+		owner, claimed = fetchLockSource(tracker, targetPath)
+	}
+	if claimed && owner != source {
+		targetPath = filepath.Join(dir, hashedIncludeName(source))
+		remoteWorkflowLog.Printf("Include name %s is taken by %s, saving %s as %s", filename, owner, source, targetPath)
+	}
+
+	flattened[targetPath] = source
+	return targetPath
+}
+
+// hashedIncludeName returns the collision-resistant file name of a flattened workflowspec
+// include, e.g. "owner/repo/shared/tools.md" → "tools-1a2b3c4d.md"
+func hashedIncludeName(source string) string {
+	filename := path.Base(source)
+	ext := path.Ext(filename)
+	hash := sha256.Sum256([]byte(source))
+	return fmt.Sprintf("%s-%x%s", strings.TrimSuffix(filename, ext), hash[:4], ext)
+}
+
+// reportGitHubAPICalls prints the GitHub API calls made since the start snapshot when show is set
+func reportGitHubAPICalls(start parser.APICallStats, show bool, events FetchEventHandler) {
+	calls := parser.CurrentAPICallStats().Sub(start)
+	remoteWorkflowLog.Printf("GitHub API calls while fetching: %s", calls)
+	if show {
+		events.emit(FetchEventInfo, calls.String(), "")
+	}
+}
+
+// reportGitHubRateLimit prints the last observed GitHub API rate-limit state.
+// A warning is always printed when the remaining quota is low; otherwise the
+// state is only shown in verbose mode.
+func reportGitHubRateLimit(verbose bool, events FetchEventHandler) {
+	state, ok := parser.LastRateLimitState()
+	if !ok {
+		return
+	}
+	remoteWorkflowLog.Printf("GitHub API rate limit after fetching: %s", state)
+	if state.IsLow() {
+		events.emit(FetchEventWarning, "GitHub API rate limit is running low: "+state.String(), "")
+	} else if verbose {
+		events.emit(FetchEventVerbose, "GitHub API rate limit: "+state.String(), "")
 	}
-	return path[:idx]
 }
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+// RemoteWorkflowInfo describes an agentic workflow offered by a remote repository
+type RemoteWorkflowInfo struct {
+	Path        string // Repository-relative path, e.g. ".github/workflows/triage.md"
+	Name        string // Name from the frontmatter, or the file name without .md
+	Description string // Description from the frontmatter, if any
+}
+
+// ListRemoteWorkflows lists the agentic workflows in the conventional workflow directories
+// (.github/workflows and workflows) of owner/repo at ref, or at the default branch when ref
+// is empty. Markdown files without an 'on' trigger, such as shared components and
+// documentation, are left out. Missing or empty directories yield no workflows rather than
+// an error. Directory listings come from the contents API, which returns a whole directory
+// in one response (up to 1,000 entries), so there are no further pages to request.
+func ListRemoteWorkflows(owner, repo, ref string) ([]RemoteWorkflowInfo, error) {
+	return listRemoteWorkflows(owner, repo, ref, defaultWorkflowSearchDirs, remoteFetchOptions{})
+}
+
+// ListRemoteWorkflowsInScope lists the agentic workflows directly in the scope directory of
+// owner/repo at ref, like ListRemoteWorkflows does for the conventional workflow directories.
+// Workflows in subdirectories of scope are not listed.
+func ListRemoteWorkflowsInScope(owner, repo, ref, scope string) ([]RemoteWorkflowInfo, error) {
+	dir, err := normalizeWorkflowScope(scope)
+	if err != nil {
+		return nil, err
+	}
+	return listRemoteWorkflows(owner, repo, ref, []string{dir}, remoteFetchOptions{})
+}
+
+// normalizeWorkflowScope validates a repository directory given as a workflow scope and returns
+// it without leading or trailing slashes
+func normalizeWorkflowScope(scope string) (string, error) {
+	dir := strings.Trim(scope, "/")
+	if dir == "" || path.Clean(dir) != dir || dir == "." || dir == ".." || strings.HasPrefix(dir, "../") {
+		return "", fmt.Errorf("invalid workflow scope %q: must be a directory inside the repository, e.g. teams/backend", scope)
+	}
+	return dir, nil
+}
+
+// listRemoteWorkflows lists the agentic workflows directly in dirs of owner/repo at ref
+func listRemoteWorkflows(owner, repo, ref string, dirs []string, opts remoteFetchOptions) ([]RemoteWorkflowInfo, error) {
+	repoSlug := owner + "/" + repo
+	if err := checkSourceAllowed(repoSlug, opts.AllowedSources); err != nil {
+		return nil, err
+	}
+	if ref == "" {
+		defaultBranch, err := getRepoDefaultBranch(repoSlug)
+		if err != nil {
+			remoteWorkflowLog.Printf("Failed to resolve default branch for %s, falling back to 'main': %v", repoSlug, err)
+			defaultBranch = "main"
+		}
+		ref = defaultBranch
+	}
+	remoteWorkflowLog.Printf("Listing agentic workflows in %s@%s", repoSlug, ref)
+
+	var workflows []RemoteWorkflowInfo
+	for _, dir := range dirs {
+		files, err := listSourceDir(opts.Fetcher, owner, repo, ref, dir)
+		if err != nil {
+			var notFound *parser.NotFoundError
+			if errors.As(err, &notFound) {
+				// Missing directories are expected; only one of them usually exists
+				remoteWorkflowLog.Printf("Skipping %s: %v", dir, err)
+				continue
+			}
+			return nil, fmt.Errorf("failed to list workflows in %s@%s: %w", repoSlug, ref, err)
+		}
+
+		for _, file := range files {
+			content, err := downloadSourceFile(opts.Fetcher, owner, repo, file, ref, opts.MaxFileSize)
+			if err != nil {
+				remoteWorkflowLog.Printf("Skipping %s: %v", file, err)
+				continue
+			}
+			if !isAgenticWorkflowContent(string(content)) {
+				continue
+			}
+			workflows = append(workflows, remoteWorkflowInfo(file, string(content)))
+		}
+	}
+
+	remoteWorkflowLog.Printf("Found %d agentic workflows in %s@%s", len(workflows), repoSlug, ref)
+	return workflows, nil
+}
+
+// remoteWorkflowInfo describes the workflow at filePath from its content
+func remoteWorkflowInfo(filePath, content string) RemoteWorkflowInfo {
+	info := RemoteWorkflowInfo{
+		Path:        filePath,
+		Name:        strings.TrimSuffix(path.Base(filePath), ".md"),
+		Description: ExtractWorkflowDescription(content),
+	}
+	if result, err := parser.ExtractFrontmatterFromContent(content); err == nil {
+		if name, ok := result.Frontmatter["name"].(string); ok && name != "" {
+			info.Name = name
+		}
+	}
+	return info
+}
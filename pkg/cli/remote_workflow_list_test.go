@@ -0,0 +1,82 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListRemoteWorkflows(t *testing.T) {
+	t.Setenv(allowedSourcesEnvVar, "")
+	mirrorDir := t.TempDir()
+	for name, content := range map[string]string{
+		".github/workflows/triage.md":       "---\non: issues\nname: Issue Triage\ndescription: Label new issues\n---\n# Triage\n",
+		".github/workflows/shared/tools.md": "---\ntools:\n  github:\n---\n# Tools\n",
+		".github/workflows/README.md":       "# Workflows\n",
+		".github/workflows/ci.yml":          "on: push\n",
+		"workflows/weekly.md":               "---\non: weekly\n---\n# Weekly summary\n",
+	} {
+		path := filepath.Join(mirrorDir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755), "mirror directory should be created")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644), "mirror file should be written")
+	}
+	defer parser.SetRepoMirrors(map[string]string{"owner/repo": mirrorDir, "owner/empty": t.TempDir()}, true)()
+
+	workflows, err := ListRemoteWorkflows("owner", "repo", "main")
+	require.NoError(t, err, "workflows should be listed")
+	assert.Equal(t, []RemoteWorkflowInfo{
+		{Path: ".github/workflows/triage.md", Name: "Issue Triage", Description: "Label new issues"},
+		{Path: "workflows/weekly.md", Name: "weekly"},
+	}, workflows, "only agentic workflows should be listed, named from their frontmatter or file name")
+
+	workflows, err = ListRemoteWorkflows("owner", "empty", "main")
+	require.NoError(t, err, "repositories without workflow directories should not fail")
+	assert.Empty(t, workflows, "no workflows should be listed")
+}
+
+func TestExpandScopedWorkflows(t *testing.T) {
+	t.Setenv(allowedSourcesEnvVar, "")
+	mirrorDir := t.TempDir()
+	for name, content := range map[string]string{
+		"teams/backend/deploy.md":         "---\non: push\n---\n# Deploy\n",
+		"teams/backend/triage.md":         "---\non: issues\n---\n# Triage\n",
+		"teams/backend/shared/tools.md":   "---\ntools:\n  github:\n---\n# Tools\n",
+		"teams/backend/nested/weekly.md":  "---\non: weekly\n---\n# Weekly\n",
+		"teams/frontend/review.md":        "---\non: pull_request\n---\n# Review\n",
+		".github/workflows/repo-level.md": "---\non: push\n---\n# Repo level\n",
+	} {
+		path := filepath.Join(mirrorDir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755), "mirror directory should be created")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644), "mirror file should be written")
+	}
+	defer parser.SetRepoMirrors(map[string]string{"owner/repo": mirrorDir}, true)()
+
+	expanded, err := expandScopedWorkflows([]string{"owner/repo@v1", "./local.md", "owner/repo/teams/backend/nested/weekly.md"}, "/teams/backend/", remoteFetchOptions{})
+	require.NoError(t, err, "scoped workflows should be expanded")
+	assert.Equal(t, []string{
+		"owner/repo/teams/backend/deploy.md@v1",
+		"owner/repo/teams/backend/triage.md@v1",
+		"./local.md",
+		"owner/repo/teams/backend/nested/weekly.md",
+	}, expanded, "repo-only specs should expand to the workflows directly in the scope")
+
+	_, err = expandScopedWorkflows([]string{"owner/repo/teams/frontend/review.md"}, "teams/backend", remoteFetchOptions{})
+	require.Error(t, err, "workflows outside the scope should be rejected")
+	assert.Contains(t, err.Error(), "outside the scope teams/backend", "error should name the scope")
+
+	_, err = expandScopedWorkflows([]string{"owner/repo"}, "teams/ops", remoteFetchOptions{})
+	require.Error(t, err, "a scope without workflows should fail")
+	assert.Contains(t, err.Error(), "no agentic workflows found in owner/repo under teams/ops", "error should name the scope")
+
+	for _, scope := range []string{"", "/", "..", "../other", "teams/../backend"} {
+		_, err = expandScopedWorkflows([]string{"owner/repo"}, scope, remoteFetchOptions{})
+		require.Error(t, err, "scope %q should be rejected", scope)
+		assert.Contains(t, err.Error(), "invalid workflow scope", "error should explain the scope is invalid")
+	}
+}
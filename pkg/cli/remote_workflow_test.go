@@ -3,11 +3,17 @@
 package cli
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/github/gh-aw/pkg/parser"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -56,7 +62,7 @@ This is a test.
 				WorkflowName: "test-workflow",
 			}
 
-			result, err := fetchLocalWorkflow(spec, false)
+			result, err := fetchLocalWorkflow(spec, remoteFetchOptions{})
 
 			if tt.expectError {
 				assert.Error(t, err, "expected error")
@@ -77,7 +83,7 @@ func TestFetchLocalWorkflow_NonExistentFile(t *testing.T) {
 		WorkflowName: "nonexistent-workflow",
 	}
 
-	result, err := fetchLocalWorkflow(spec, false)
+	result, err := fetchLocalWorkflow(spec, remoteFetchOptions{})
 
 	require.Error(t, err, "should error for non-existent file")
 	assert.Nil(t, result, "result should be nil on error")
@@ -92,12 +98,42 @@ func TestFetchLocalWorkflow_DirectoryInsteadOfFile(t *testing.T) {
 		WorkflowName: "directory-workflow",
 	}
 
-	result, err := fetchLocalWorkflow(spec, false)
+	result, err := fetchLocalWorkflow(spec, remoteFetchOptions{})
 
 	require.Error(t, err, "should error when path is a directory")
 	assert.Nil(t, result, "result should be nil on error")
 }
 
+func TestFetchLocalWorkflow_NoSymlinks(t *testing.T) {
+	repoDir := t.TempDir()
+	require.NoError(t, exec.Command("git", "-C", repoDir, "init", "-q").Run(), "git repository should be created")
+	t.Chdir(repoDir)
+	outsideDir := t.TempDir()
+	outsideFile := filepath.Join(outsideDir, "secret.md")
+	require.NoError(t, os.WriteFile(outsideFile, []byte("# Secret"), 0o644), "outside file should be written")
+
+	regular := filepath.Join(repoDir, "regular.md")
+	require.NoError(t, os.WriteFile(regular, []byte("# Regular"), 0o644), "workflow should be written")
+	linkedFile := filepath.Join(repoDir, "linked.md")
+	require.NoError(t, os.Symlink(outsideFile, linkedFile), "file symlink should be created")
+	linkedDir := filepath.Join(repoDir, "linked-dir")
+	require.NoError(t, os.Symlink(outsideDir, linkedDir), "directory symlink should be created")
+
+	_, err := fetchLocalWorkflow(&WorkflowSpec{WorkflowPath: linkedFile}, remoteFetchOptions{})
+	require.NoError(t, err, "symlinks should be followed by default")
+
+	noSymlinks := remoteFetchOptions{NoSymlinks: true}
+	fetched, err := fetchLocalWorkflow(&WorkflowSpec{WorkflowPath: regular}, noSymlinks)
+	require.NoError(t, err, "regular files should still be read")
+	assert.Equal(t, "# Regular", string(fetched.Content), "content should be read")
+
+	_, err = fetchLocalWorkflow(&WorkflowSpec{WorkflowPath: linkedFile}, noSymlinks)
+	require.ErrorContains(t, err, "symbolic link", "symlinked files should be refused")
+
+	_, err = fetchLocalWorkflow(&WorkflowSpec{WorkflowPath: filepath.Join(linkedDir, "secret.md")}, noSymlinks)
+	require.ErrorContains(t, err, "outside the repository", "files reached through a symlinked directory should be refused")
+}
+
 func TestFetchWorkflowFromSource_LocalRouting(t *testing.T) {
 	// Create a temporary local workflow file
 	tempDir := t.TempDir()
@@ -116,6 +152,7 @@ func TestFetchWorkflowFromSource_LocalRouting(t *testing.T) {
 	require.NoError(t, err, "should not error for local workflow")
 	assert.True(t, result.IsLocal, "should route to local fetch")
 	assert.Equal(t, []byte(content), result.Content, "content should match")
+	assert.Equal(t, "9089f2f8d71aa9fb18649a10580bce6347ec853ee3b913190dfca09d828e8d5e", result.ContentSHA256, "content checksum should be set for local workflows")
 }
 
 func TestFetchWorkflowFromSource_RemoteRoutingWithInvalidSlug(t *testing.T) {
@@ -136,7 +173,128 @@ func TestFetchWorkflowFromSource_RemoteRoutingWithInvalidSlug(t *testing.T) {
 	assert.Contains(t, err.Error(), "invalid repository slug", "error should mention invalid slug")
 }
 
+func TestSelectDefaultWorkflow(t *testing.T) {
+	t.Run("single candidate is selected", func(t *testing.T) {
+		path, err := selectDefaultWorkflow("owner/repo", "main", []string{".github/workflows/triage.md"})
+		require.NoError(t, err, "should select the only candidate")
+		assert.Equal(t, ".github/workflows/triage.md", path, "should return the candidate path")
+	})
+
+	t.Run("no candidates", func(t *testing.T) {
+		_, err := selectDefaultWorkflow("owner/repo", "main", nil)
+		require.Error(t, err, "should error without candidates")
+		assert.Contains(t, err.Error(), "no agentic workflows found in owner/repo@main", "error should name the repository")
+	})
+
+	t.Run("multiple candidates are ambiguous", func(t *testing.T) {
+		_, err := selectDefaultWorkflow("owner/repo", "v1", []string{".github/workflows/a.md", "workflows/b.md"})
+		require.Error(t, err, "should error with multiple candidates")
+		assert.Contains(t, err.Error(), "multiple agentic workflows found in owner/repo@v1", "error should explain the ambiguity")
+		assert.Contains(t, err.Error(), ".github/workflows/a.md", "error should list the first candidate")
+		assert.Contains(t, err.Error(), "workflows/b.md", "error should list the second candidate")
+	})
+}
+
+// stubDefaultWorkflows replaces the default branch lookup and the workflow listing used for
+// repo-only specs. The listing fails with listErr, or returns a workflow for each path when
+// called with the given default branch.
+func stubDefaultWorkflows(t *testing.T, defaultBranch string, paths []string, listErr error) {
+	t.Helper()
+	originalLookup := lookupRepoDefaultBranch
+	originalList := listDefaultWorkflows
+	t.Cleanup(func() {
+		lookupRepoDefaultBranch = originalLookup
+		listDefaultWorkflows = originalList
+		defaultBranchCache.Clear()
+	})
+	defaultBranchCache.Clear()
+
+	lookupRepoDefaultBranch = func(repo string) (string, error) {
+		if defaultBranch == "" {
+			return "", errors.New("lookup failed")
+		}
+		return defaultBranch, nil
+	}
+	listDefaultWorkflows = func(owner, repo, ref string, _ remoteFetchOptions) ([]RemoteWorkflowInfo, error) {
+		if listErr != nil {
+			return nil, listErr
+		}
+		var workflows []RemoteWorkflowInfo
+		for _, p := range paths {
+			workflows = append(workflows, RemoteWorkflowInfo{Path: p + "@" + ref})
+		}
+		return workflows, nil
+	}
+}
+
+func TestResolveDefaultWorkflowPath(t *testing.T) {
+	t.Run("uses the default branch", func(t *testing.T) {
+		stubDefaultWorkflows(t, "trunk", []string{".github/workflows/triage.md"}, nil)
+		spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo"}}
+		require.NoError(t, resolveDefaultWorkflowPath(spec, remoteFetchOptions{}), "should resolve the only workflow")
+		assert.Equal(t, ".github/workflows/triage.md@trunk", spec.WorkflowPath, "should list the default branch")
+	})
+
+	t.Run("uses the requested ref", func(t *testing.T) {
+		stubDefaultWorkflows(t, "trunk", []string{".github/workflows/triage.md"}, nil)
+		spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "v1"}}
+		require.NoError(t, resolveDefaultWorkflowPath(spec, remoteFetchOptions{}), "should resolve the only workflow")
+		assert.Equal(t, ".github/workflows/triage.md@v1", spec.WorkflowPath, "should list the requested ref")
+	})
+
+	t.Run("falls back to main when the lookup fails", func(t *testing.T) {
+		stubDefaultWorkflows(t, "", nil, nil)
+		err := resolveDefaultWorkflowPath(&WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo"}}, remoteFetchOptions{})
+		require.Error(t, err, "should error without workflows")
+		assert.Contains(t, err.Error(), "no agentic workflows found in owner/repo@main", "should list main")
+	})
+
+	t.Run("reports unlistable repositories as empty", func(t *testing.T) {
+		stubDefaultWorkflows(t, "trunk", nil, &parser.NotFoundError{Source: "owner/repo", Err: errors.New("404")})
+		err := resolveDefaultWorkflowPath(&WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo"}}, remoteFetchOptions{})
+		require.Error(t, err, "should error without workflows")
+		assert.Contains(t, err.Error(), "no agentic workflows found in owner/repo@trunk", "should report no workflows")
+	})
+
+	t.Run("returns auth errors", func(t *testing.T) {
+		stubDefaultWorkflows(t, "trunk", nil, &parser.AuthError{Source: "owner/repo", Err: errors.New("401")})
+		err := resolveDefaultWorkflowPath(&WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo"}}, remoteFetchOptions{})
+		var authErr *parser.AuthError
+		require.ErrorAs(t, err, &authErr, "auth errors should not be reported as no workflows")
+	})
+
+	t.Run("returns rate limit errors", func(t *testing.T) {
+		stubDefaultWorkflows(t, "trunk", nil, &parser.RateLimitError{Reset: "soon", Err: errors.New("403")})
+		err := resolveDefaultWorkflowPath(&WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo"}}, remoteFetchOptions{})
+		require.ErrorIs(t, err, parser.ErrRateLimited, "rate limit errors should not be reported as no workflows")
+	})
+}
+
+func TestIsAgenticWorkflowContent(t *testing.T) {
+	assert.True(t, isAgenticWorkflowContent("---\non: issues\n---\n# Triage\n"), "workflow with trigger should be agentic")
+	assert.False(t, isAgenticWorkflowContent("---\ntools:\n  github:\n---\n# Shared\n"), "shared component should not be agentic")
+	assert.False(t, isAgenticWorkflowContent("# README\n"), "plain markdown should not be agentic")
+}
+
+func TestFetchWorkflowFromSource_RepoOnlyWithInvalidSlug(t *testing.T) {
+	spec := &WorkflowSpec{
+		RepoSpec: RepoSpec{
+			RepoSlug: "invalid-slug-no-slash",
+		},
+	}
+
+	result, err := FetchWorkflowFromSource(spec, false)
+
+	require.Error(t, err, "should error for invalid repo slug")
+	assert.Nil(t, result, "result should be nil on error")
+	assert.Contains(t, err.Error(), "invalid repository slug", "error should mention invalid slug")
+}
+
 func TestFetchIncludeFromSource_WorkflowSpecParsing(t *testing.T) {
+	stubIncludeFiles(t, map[string]string{
+		"owner/repo/path/file.md@main": "# Section Name\n",
+	})
+
 	tests := []struct {
 		name          string
 		includePath   string
@@ -149,31 +307,32 @@ func TestFetchIncludeFromSource_WorkflowSpecParsing(t *testing.T) {
 			name:          "two parts falls through to cannot resolve",
 			includePath:   "owner/repo",
 			baseSpec:      nil,
-			expectSection: "",
 			expectError:   true,
 			errorContains: "cannot resolve include path", // Not a workflowspec format (only 2 parts)
 		},
 		{
 			name:          "section extraction from workflowspec",
-			includePath:   "owner/repo/path/file.md#section-name",
+			includePath:   "owner/repo/path/file.md@main#section-name",
 			baseSpec:      nil,
 			expectSection: "#section-name",
-			expectError:   true, // Will fail to download, but section should be extracted
-			errorContains: "",   // Don't check error message, just that section is extracted
 		},
 		{
 			name:          "no section in workflowspec",
-			includePath:   "owner/repo/path/file.md",
+			includePath:   "owner/repo/path/file.md@main",
 			baseSpec:      nil,
 			expectSection: "",
-			expectError:   true, // Will fail to download
-			errorContains: "",
+		},
+		{
+			name:          "missing workflowspec file",
+			includePath:   "owner/repo/path/missing.md@main#section-name",
+			baseSpec:      nil,
+			expectError:   true,
+			errorContains: "failed to fetch include",
 		},
 		{
 			name:          "relative path without base spec",
 			includePath:   "shared/file.md",
 			baseSpec:      nil,
-			expectSection: "",
 			expectError:   true,
 			errorContains: "cannot resolve include path",
 		},
@@ -181,7 +340,6 @@ func TestFetchIncludeFromSource_WorkflowSpecParsing(t *testing.T) {
 			name:          "relative path with section but no base spec",
 			includePath:   "shared/file.md#my-section",
 			baseSpec:      nil,
-			expectSection: "#my-section",
 			expectError:   true,
 			errorContains: "cannot resolve include path",
 		},
@@ -189,24 +347,28 @@ func TestFetchIncludeFromSource_WorkflowSpecParsing(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, section, err := FetchIncludeFromSource(tt.includePath, tt.baseSpec, false)
+			result, err := FetchIncludeFromSource(tt.includePath, tt.baseSpec, false)
 
 			if tt.expectError {
 				require.Error(t, err, "expected error")
+				assert.Nil(t, result, "result should be nil on error")
 				if tt.errorContains != "" {
 					assert.Contains(t, err.Error(), tt.errorContains, "error should contain expected text")
 				}
-			} else {
-				require.NoError(t, err, "should not error")
+				return
 			}
-
-			// Section should always be extracted consistently
-			assert.Equal(t, tt.expectSection, section, "section should match expected")
+			require.NoError(t, err, "should not error")
+			assert.Equal(t, tt.expectSection, result.Section, "section should match expected")
 		})
 	}
 }
 
 func TestFetchIncludeFromSource_SectionExtraction(t *testing.T) {
+	stubIncludeFiles(t, map[string]string{
+		"owner/repo/file.md@main":   "# Section\n",
+		"owner/repo/file.md@v1.0.0": "# Section\n",
+	})
+
 	// Test that section is consistently extracted regardless of path type
 	tests := []struct {
 		name          string
@@ -215,17 +377,17 @@ func TestFetchIncludeFromSource_SectionExtraction(t *testing.T) {
 	}{
 		{
 			name:          "hash section",
-			includePath:   "owner/repo/file.md#section",
+			includePath:   "owner/repo/file.md@main#section",
 			expectSection: "#section",
 		},
 		{
 			name:          "complex section with hyphens",
-			includePath:   "owner/repo/file.md#my-complex-section-name",
+			includePath:   "owner/repo/file.md@main#my-complex-section-name",
 			expectSection: "#my-complex-section-name",
 		},
 		{
 			name:          "no section",
-			includePath:   "owner/repo/file.md",
+			includePath:   "owner/repo/file.md@main",
 			expectSection: "",
 		},
 		{
@@ -235,16 +397,136 @@ func TestFetchIncludeFromSource_SectionExtraction(t *testing.T) {
 		},
 		{
 			name:          "section after everything",
-			includePath:   "owner/repo/file.md#section-name",
+			includePath:   "owner/repo/file.md@main#section-name",
 			expectSection: "#section-name",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// We expect errors since these are remote paths, but section should still be extracted
-			_, section, _ := FetchIncludeFromSource(tt.includePath, nil, false)
-			assert.Equal(t, tt.expectSection, section, "section should be correctly extracted")
+			result, err := FetchIncludeFromSource(tt.includePath, nil, false)
+			require.NoError(t, err, "include should be fetched")
+			assert.Equal(t, tt.expectSection, result.Section, "section should be correctly extracted")
+		})
+	}
+}
+
+func TestFetchIncludeFromSource_ResolvedLocation(t *testing.T) {
+	const sha = "0123456789abcdef0123456789abcdef01234567"
+	stubIncludeFiles(t, map[string]string{
+		"owner/repo/shared/tools.md@v2":              "# Usage\n",
+		"owner/repo/shared/tools.md@" + sha:          "# Usage\n",
+		"owner/repo/.github/shared/tools.md@v1":      "# Usage\n",
+		"owner/repo/workflows/helpers/notes.md@main": "# Notes\n",
+	})
+
+	tests := []struct {
+		name           string
+		includePath    string
+		baseSpec       *WorkflowSpec
+		expectedPath   string
+		expectedCommit string
+	}{
+		{
+			name:           "workflowspec with a tag",
+			includePath:    "owner/repo/shared/tools.md@v2#Usage",
+			expectedPath:   "owner/repo/shared/tools.md@v2",
+			expectedCommit: fakeCommitSHA("owner/repo@v2"),
+		},
+		{
+			name:           "workflowspec pinned to a commit",
+			includePath:    "owner/repo/shared/tools.md@" + sha,
+			expectedPath:   "owner/repo/shared/tools.md@" + sha,
+			expectedCommit: sha,
+		},
+		{
+			name:           "rooted relative include resolves under .github",
+			includePath:    "shared/tools.md",
+			baseSpec:       &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "v1"}, WorkflowPath: ".github/workflows/triage.md"},
+			expectedPath:   "owner/repo/.github/shared/tools.md@v1",
+			expectedCommit: fakeCommitSHA("owner/repo@v1"),
+		},
+		{
+			name:           "relative include resolves against the workflow directory",
+			includePath:    "helpers/notes.md",
+			baseSpec:       &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo"}, WorkflowPath: "workflows/triage.md"},
+			expectedPath:   "owner/repo/workflows/helpers/notes.md@main",
+			expectedCommit: fakeCommitSHA("owner/repo@main"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := fetchIncludeFromSource(tt.includePath, tt.baseSpec, remoteFetchOptions{})
+			require.NoError(t, err, "include should be fetched")
+			assert.Equal(t, tt.expectedPath, result.ResolvedPath, "resolved path should match")
+			assert.Equal(t, tt.expectedCommit, result.CommitSHA, "commit SHA should match")
+		})
+	}
+
+	t.Run("each ref is resolved once", func(t *testing.T) {
+		includeCommitCache.Clear()
+		lookups := 0
+		resolveIncludeRef = func(_ *parser.Fetcher, owner, repo, ref string) (string, error) {
+			lookups++
+			return sha, nil
+		}
+		for range 2 {
+			result, err := fetchIncludeFromSource("owner/repo/shared/tools.md@v2", nil, remoteFetchOptions{})
+			require.NoError(t, err, "include should be fetched")
+			assert.Equal(t, sha, result.CommitSHA, "floating ref should be resolved")
+		}
+		assert.Equal(t, 1, lookups, "resolution should be memoized")
+	})
+
+	t.Run("unresolvable refs leave the commit empty", func(t *testing.T) {
+		includeCommitCache.Clear()
+		resolveIncludeRef = func(_ *parser.Fetcher, owner, repo, ref string) (string, error) {
+			return "", errors.New("lookup failed")
+		}
+		result, err := fetchIncludeFromSource("owner/repo/shared/tools.md@v2", nil, remoteFetchOptions{})
+		require.NoError(t, err, "resolution failures should not fail the fetch")
+		assert.Empty(t, result.CommitSHA, "commit should be empty")
+	})
+}
+
+func TestRemoteWorkflowFallbackPaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		workflow string
+		expected []string
+	}{
+		{
+			name:     "bare name probes every extension",
+			workflow: "triage",
+			expected: []string{
+				"workflows/triage.md", "workflows/triage.yaml", "workflows/triage.yml",
+				".github/workflows/triage.md", ".github/workflows/triage.yaml", ".github/workflows/triage.yml",
+			},
+		},
+		{
+			name:     "markdown name is probed as-is",
+			workflow: "triage.md",
+			expected: []string{"workflows/triage.md", ".github/workflows/triage.md"},
+		},
+		{
+			name:     "yaml name is probed as-is",
+			workflow: "triage.yml",
+			expected: []string{"workflows/triage.yml", ".github/workflows/triage.yml"},
+		},
+		{
+			name:     "unknown extension is treated as part of the name",
+			workflow: "triage.v2",
+			expected: []string{
+				"workflows/triage.v2.md", "workflows/triage.v2.yaml", "workflows/triage.v2.yml",
+				".github/workflows/triage.v2.md", ".github/workflows/triage.v2.yaml", ".github/workflows/triage.v2.yml",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, remoteWorkflowFallbackPaths(tt.workflow), "fallback paths should match")
 		})
 	}
 }
@@ -284,8 +566,8 @@ func TestGetParentDir(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := getParentDir(tt.path)
-			assert.Equal(t, tt.expected, result, "getParentDir(%q) should return %q", tt.path, tt.expected)
+			result := GetParentDir(tt.path)
+			assert.Equal(t, tt.expected, result, "GetParentDir(%q) should return %q", tt.path, tt.expected)
 		})
 	}
 }
@@ -308,8 +590,9 @@ engine: copilot
 	}
 
 	tmpDir := t.TempDir()
-	err := fetchAndSaveRemoteFrontmatterImports(content, spec, tmpDir, false, false, nil)
+	installed, err := fetchAndSaveRemoteFrontmatterImports(content, spec, tmpDir, remoteFetchOptions{})
 	require.NoError(t, err, "should not error when no imports are present")
+	assert.Empty(t, installed, "no paths should be reported when no imports are present")
 
 	// No files should have been created
 	entries, readErr := os.ReadDir(tmpDir)
@@ -336,7 +619,7 @@ imports:
 	}
 
 	tmpDir := t.TempDir()
-	err := fetchAndSaveRemoteFrontmatterImports(content, spec, tmpDir, false, false, nil)
+	_, err := fetchAndSaveRemoteFrontmatterImports(content, spec, tmpDir, remoteFetchOptions{})
 	require.NoError(t, err, "should not error for local workflow with empty RepoSlug")
 
 	entries, readErr := os.ReadDir(tmpDir)
@@ -365,7 +648,7 @@ imports:
 
 	tmpDir := t.TempDir()
 	// This should not attempt any network calls; already-pinned imports are skipped.
-	err := fetchAndSaveRemoteFrontmatterImports(content, spec, tmpDir, false, false, nil)
+	_, err := fetchAndSaveRemoteFrontmatterImports(content, spec, tmpDir, remoteFetchOptions{})
 	require.NoError(t, err, "should not error for workflowspec imports")
 
 	entries, readErr := os.ReadDir(tmpDir)
@@ -397,7 +680,7 @@ engine: copilot
 		WorkflowPath: ".github/workflows/test.md",
 	}
 
-	err := fetchAndSaveRemoteFrontmatterImports(content, spec, tracker.gitRoot, false, false, tracker)
+	_, err := fetchAndSaveRemoteFrontmatterImports(content, spec, tracker.gitRoot, remoteFetchOptions{Tracker: tracker})
 	require.NoError(t, err)
 	assert.Empty(t, tracker.CreatedFiles, "no files should be created when there are no imports")
 	assert.Empty(t, tracker.ModifiedFiles, "no files should be modified when there are no imports")
@@ -431,7 +714,7 @@ imports:
 	tmpDir := t.TempDir()
 	// No network in unit tests: the download attempt for the first import will fail silently
 	// (verbose=false).  The second import must be deduplicated without a second download.
-	err := fetchAndSaveRemoteFrontmatterImports(content, spec, tmpDir, false, false, nil)
+	_, err := fetchAndSaveRemoteFrontmatterImports(content, spec, tmpDir, remoteFetchOptions{})
 	require.NoError(t, err, "section-fragment deduplication should not error")
 
 	entries, readErr := os.ReadDir(tmpDir)
@@ -473,7 +756,7 @@ imports:
 		WorkflowPath: ".github/workflows/ci-coach.md",
 	}
 
-	err := fetchAndSaveRemoteFrontmatterImports(content, spec, tmpDir, false, false, tracker)
+	_, err := fetchAndSaveRemoteFrontmatterImports(content, spec, tmpDir, remoteFetchOptions{Tracker: tracker})
 	require.NoError(t, err)
 
 	// The existing file must be untouched and not added to the tracker.
@@ -519,7 +802,7 @@ imports:
 			}
 
 			tmpDir := t.TempDir()
-			err := fetchAndSaveRemoteFrontmatterImports(content, spec, tmpDir, false, false, nil)
+			_, err := fetchAndSaveRemoteFrontmatterImports(content, spec, tmpDir, remoteFetchOptions{})
 			require.NoError(t, err, "path traversal should be silently rejected, not return an error")
 
 			// No file must have been written anywhere
@@ -530,6 +813,104 @@ imports:
 	}
 }
 
+func TestFetchAndSaveRemoteFrontmatterImports_ParentDirectory(t *testing.T) {
+	mirrorDir := t.TempDir()
+	for name, content := range map[string]string{
+		".github/workflows/shared/x.md": "---\nimports:\n  - y.md\n---\n# X\n",
+		".github/workflows/shared/y.md": "# Y\n",
+		"docs/readme.md":                "# Docs\n",
+	} {
+		path := filepath.Join(mirrorDir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755), "mirror directory should be created")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644), "mirror file should be written")
+	}
+	defer parser.SetRepoMirrors(map[string]string{"owner/repo": mirrorDir}, true)()
+
+	content := `---
+engine: copilot
+imports:
+  - ../shared/x.md
+  - ../../../docs/readme.md
+  - ../../../../outside.md
+---
+# Workflow
+`
+	spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "main"}, WorkflowPath: ".github/workflows/team/triage.md"}
+	githubDir := filepath.Join(t.TempDir(), ".github")
+	targetDir := filepath.Join(githubDir, "workflows")
+
+	installed, err := fetchAndSaveRemoteFrontmatterImports(content, spec, targetDir, remoteFetchOptions{})
+	require.NoError(t, err, "imports should be fetched")
+
+	assert.ElementsMatch(t, []string{
+		filepath.Join(githubDir, "shared", "x.md"),
+		filepath.Join(githubDir, "shared", "y.md"),
+	}, installed, "sibling imports should keep their relative layout next to the installed workflow")
+	assert.NoFileExists(t, filepath.Join(filepath.Dir(githubDir), "docs", "readme.md"), "imports outside .github/ should be refused")
+}
+
+func TestFetchAndSaveRemoteFrontmatterImports_Aliases(t *testing.T) {
+	mirrorDir := t.TempDir()
+	for name, content := range map[string]string{
+		".github/workflows/shared/mcp/tools.md": "# Tools\n",
+		".github/workflows/shared/style.md":     "# Style\n",
+	} {
+		path := filepath.Join(mirrorDir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755), "mirror directory should be created")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644), "mirror file should be written")
+	}
+	defer parser.SetRepoMirrors(map[string]string{"owner/repo": mirrorDir}, true)()
+
+	content := `---
+engine: copilot
+imports:
+  tools.md: shared/mcp/tools.md
+  local/style.md#Tone: shared/style.md
+  ../outside.md: shared/style.md
+---
+# Workflow
+`
+	spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "main"}, WorkflowPath: ".github/workflows/triage.md"}
+	targetDir := filepath.Join(t.TempDir(), "workflows")
+
+	installed, err := fetchAndSaveRemoteFrontmatterImports(content, spec, targetDir, remoteFetchOptions{})
+	require.NoError(t, err, "aliased imports should be fetched")
+
+	assert.ElementsMatch(t, []string{
+		filepath.Join(targetDir, "tools.md"),
+		filepath.Join(targetDir, "local", "style.md"),
+	}, installed, "aliased imports should be written to their local paths")
+	data, err := os.ReadFile(filepath.Join(targetDir, "tools.md"))
+	require.NoError(t, err, "aliased import should exist")
+	assert.Equal(t, "# Tools\n", string(data), "alias should fetch the remote path")
+	assert.NoFileExists(t, filepath.Join(filepath.Dir(targetDir), "outside.md"), "aliases outside the target directory should be refused")
+}
+
+func TestFetchAndSaveRemoteFrontmatterImports_Transform(t *testing.T) {
+	mirrorDir := t.TempDir()
+	sharedDir := filepath.Join(mirrorDir, ".github", "workflows", "shared")
+	require.NoError(t, os.MkdirAll(sharedDir, 0o755), "mirror directory should be created")
+	require.NoError(t, os.WriteFile(filepath.Join(sharedDir, "a.md"), []byte("---\nimports:\n  - b.md\n---\nToken: secret\n"), 0o644), "mirror file should be written")
+	require.NoError(t, os.WriteFile(filepath.Join(sharedDir, "c.md"), []byte("C\n"), 0o644), "mirror file should be written")
+	defer parser.SetRepoMirrors(map[string]string{"owner/repo": mirrorDir}, true)()
+
+	// The transform redirects the nested import, so b.md is never fetched
+	transform := func(source string, content []byte) ([]byte, error) {
+		text := strings.ReplaceAll(string(content), "secret", "[redacted]")
+		return []byte(strings.ReplaceAll(text, "b.md", "c.md")), nil
+	}
+	spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "main"}, WorkflowPath: ".github/workflows/triage.md"}
+	targetDir := t.TempDir()
+	installed, err := fetchAndSaveRemoteFrontmatterImports("---\nimports:\n  - shared/a.md\n---\n", spec, targetDir, remoteFetchOptions{Transform: transform})
+	require.NoError(t, err, "imports should be fetched")
+
+	assert.Equal(t, []string{filepath.Join(targetDir, "shared", "a.md"), filepath.Join(targetDir, "shared", "c.md")}, installed,
+		"nested imports should be parsed from the transformed content")
+	data, err := os.ReadFile(filepath.Join(targetDir, "shared", "a.md"))
+	require.NoError(t, err, "import should be saved")
+	assert.Contains(t, string(data), "Token: [redacted]", "saved content should be transformed")
+}
+
 // TestFetchAndSaveRemoteFrontmatterImports_InvalidRepoSlug verifies that an invalid
 // RepoSlug (not in owner/repo format) causes the function to return early without error.
 func TestFetchAndSaveRemoteFrontmatterImports_InvalidRepoSlug(t *testing.T) {
@@ -548,10 +929,681 @@ imports:
 	}
 
 	tmpDir := t.TempDir()
-	err := fetchAndSaveRemoteFrontmatterImports(content, spec, tmpDir, false, false, nil)
+	_, err := fetchAndSaveRemoteFrontmatterImports(content, spec, tmpDir, remoteFetchOptions{})
 	require.NoError(t, err, "invalid RepoSlug should return nil without error")
 
 	entries, readErr := os.ReadDir(tmpDir)
 	require.NoError(t, readErr)
 	assert.Empty(t, entries, "no files should be created for an invalid RepoSlug")
 }
+
+func TestCollectRemoteIncludes(t *testing.T) {
+	content := `# Workflow
+
+@include shared/big.md#Setup
+@include? shared/big.md#Usage
+@include shared/big.md#Setup
+@include? shared/optional.md
+@include shared/full.md#Intro
+@include shared/full.md
+`
+
+	includes := collectRemoteIncludes(content)
+	require.Len(t, includes, 3, "should group directives by file")
+
+	assert.Equal(t, "shared/big.md", includes[0].filePath, "first file should keep reference order")
+	assert.Equal(t, []string{"Setup", "Usage"}, includes[0].sections, "sections should be deduplicated in order")
+	assert.False(t, includes[0].wholeFile, "section-only file should not be whole")
+	assert.False(t, includes[0].optional, "file with a required directive should be required")
+
+	assert.Equal(t, "shared/optional.md", includes[1].filePath, "second file should be the optional include")
+	assert.True(t, includes[1].optional, "file with only optional directives should be optional")
+	assert.True(t, includes[1].wholeFile, "include without section should be whole")
+
+	assert.True(t, includes[2].wholeFile, "whole include should win over section include")
+}
+
+func TestCollectRemoteIncludesConditions(t *testing.T) {
+	content := `# Workflow
+
+@include[engine=copilot] shared/copilot-tips.md
+@include?[engine=claude] shared/claude-tips.md#Usage
+@include[engine=claude] shared/copilot-tips.md
+@include shared/common.md
+`
+
+	includes := collectRemoteIncludes(content)
+	require.Len(t, includes, 3, "conditional directives should still be fetched")
+
+	assert.Equal(t, "shared/copilot-tips.md", includes[0].filePath, "condition should not be part of the path")
+	assert.Equal(t, []string{"engine=copilot", "engine=claude"}, includes[0].conditions, "conditions should be recorded per file")
+
+	assert.Equal(t, "shared/claude-tips.md", includes[1].filePath, "optional conditional include should be parsed")
+	assert.True(t, includes[1].optional, "optional marker should be kept")
+	assert.Equal(t, []string{"Usage"}, includes[1].sections, "section should be kept")
+
+	assert.Empty(t, includes[2].conditions, "unconditional include should have no conditions")
+}
+
+func TestExtractIncludeSections(t *testing.T) {
+	content := `---
+tools:
+  github:
+---
+
+# Setup
+
+Install things.
+
+## Details
+
+More setup.
+
+# Usage
+
+Use things.
+
+# Other
+
+Unrelated.
+`
+
+	t.Run("keeps frontmatter and requested sections", func(t *testing.T) {
+		scoped, err := extractIncludeSections(content, []string{"Usage", "Setup"})
+		require.NoError(t, err, "should extract existing sections")
+
+		expected := `---
+tools:
+  github:
+---
+
+# Usage
+
+Use things.
+
+# Setup
+
+Install things.
+
+## Details
+
+More setup.
+`
+		assert.Equal(t, expected, scoped, "should contain only the requested sections")
+	})
+
+	t.Run("without frontmatter", func(t *testing.T) {
+		scoped, err := extractIncludeSections("# A\n\none\n\n# B\n\ntwo\n", []string{"B"})
+		require.NoError(t, err, "should extract section")
+		assert.Equal(t, "# B\n\ntwo\n", scoped, "should not add a frontmatter block")
+	})
+
+	t.Run("missing section", func(t *testing.T) {
+		_, err := extractIncludeSections(content, []string{"Missing"})
+		require.Error(t, err, "should error for missing section")
+		assert.Contains(t, err.Error(), "section 'Missing' not found", "error should name the section")
+	})
+}
+
+func TestIncludeFetchBudget(t *testing.T) {
+	budget := newIncludeFetchBudget(2)
+	require.NoError(t, budget.take("a.md"), "first fetch should fit the budget")
+	require.NoError(t, budget.take("b.md"), "second fetch should fit the budget")
+
+	err := budget.take("c.md")
+	require.Error(t, err, "third fetch should exceed the budget")
+	require.ErrorIs(t, err, ErrIncludeBudgetExceeded, "error should wrap the budget sentinel")
+	assert.Contains(t, err.Error(), "limit 2", "error should name the limit")
+	assert.Contains(t, err.Error(), "c.md", "error should name the include")
+
+	assert.Equal(t, defaultMaxIncludeFiles, newIncludeFetchBudget(0).limit, "non-positive limit should use the default")
+}
+
+func TestFetchAndSaveRemoteIncludes_BudgetExceeded(t *testing.T) {
+	// Optional includes that cannot be resolved still consume the budget,
+	// so the third directive trips the limit without any network access
+	content := "@include? a.md\n@include? b.md\n@include? c.md\n"
+	spec := &WorkflowSpec{WorkflowPath: "workflow.md"}
+
+	_, err := fetchAndSaveRemoteIncludes(content, spec, t.TempDir(), remoteFetchOptions{MaxIncludeFiles: 2})
+	require.ErrorIs(t, err, ErrIncludeBudgetExceeded, "should abort once the budget is exhausted")
+
+	installed, err := fetchAndSaveRemoteIncludes(content, spec, t.TempDir(), remoteFetchOptions{MaxIncludeFiles: 3})
+	require.NoError(t, err, "should succeed when the budget covers every include")
+	assert.Empty(t, installed, "unresolvable optional includes should not be reported as installed")
+}
+
+func TestFetchAndSaveRemoteIncludes_FlattenedNameCollision(t *testing.T) {
+	stubIncludeFiles(t, map[string]string{
+		"octo/prompts/shared/tools.md@v1": "# Octo tools\n",
+		"acme/lib/docs/tools.md@main":     "# Acme tools\n",
+		"octo/prompts/shared/style.md@v1": "# Style\n",
+	})
+
+	gitRoot := t.TempDir()
+	workflowsDir := filepath.Join(gitRoot, ".github", "workflows")
+	tracker := &FileTracker{OriginalContent: make(map[string][]byte), gitRoot: gitRoot}
+	spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "v1"}, WorkflowPath: "workflows/triage.md"}
+	sharedDir := filepath.Join(gitRoot, ".github", "shared")
+
+	content := "@include octo/prompts/shared/tools.md@v1\n@include acme/lib/docs/tools.md@main\n@include octo/prompts/shared/style.md@v1\n"
+	installed, err := fetchAndSaveRemoteIncludes(content, spec, workflowsDir, remoteFetchOptions{Tracker: tracker})
+	require.NoError(t, err, "includes should be fetched")
+
+	renamed := flattenedIncludePath(sharedDir, "acme/lib/docs/tools.md", map[string]string{filepath.Join(sharedDir, "tools.md"): "octo/prompts/shared/tools.md"}, nil)
+	assert.Regexp(t, `tools-[0-9a-f]{8}\.md$`, renamed, "colliding include should get a hashed name")
+	assert.Equal(t, []string{filepath.Join(sharedDir, "tools.md"), renamed, filepath.Join(sharedDir, "style.md")}, installed,
+		"only the colliding include should be renamed")
+
+	data, err := os.ReadFile(renamed)
+	require.NoError(t, err, "renamed include should be written")
+	assert.Equal(t, "# Acme tools\n", string(data), "renamed include should hold the second source")
+	data, err = os.ReadFile(filepath.Join(sharedDir, "tools.md"))
+	require.NoError(t, err, "first include should be written")
+	assert.Equal(t, "# Octo tools\n", string(data), "first include should not be overwritten")
+
+	// A later fetch sees the name claimed in the fetch lock, even when fetched on its own
+	installed, err = fetchAndSaveRemoteIncludes("@include acme/lib/docs/tools.md@main\n", spec, workflowsDir, remoteFetchOptions{Force: true, Tracker: tracker})
+	require.NoError(t, err, "include should be fetched again")
+	assert.Empty(t, installed, "unchanged include should be left in place under its hashed name")
+	data, err = os.ReadFile(filepath.Join(sharedDir, "tools.md"))
+	require.NoError(t, err, "first include should still exist")
+	assert.Equal(t, "# Octo tools\n", string(data), "later fetches should not overwrite the other source")
+}
+
+func TestFetchAndSaveRemoteIncludes_ForceReportsChanges(t *testing.T) {
+	var messages []string
+	handler := func(event FetchEvent) {
+		messages = append(messages, event.Message)
+	}
+
+	stubIncludeFiles(t, map[string]string{
+		"owner/repo/workflows/prompts/a.md@v1": "# A\n\nNew line.\n",
+		"owner/repo/workflows/prompts/b.md@v1": "# B\n",
+	})
+	gitRoot := t.TempDir()
+	workflowsDir := filepath.Join(gitRoot, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(filepath.Join(workflowsDir, "prompts"), 0o755), "prompts directory should be created")
+	aPath := filepath.Join(workflowsDir, "prompts", "a.md")
+	bPath := filepath.Join(workflowsDir, "prompts", "b.md")
+	require.NoError(t, os.WriteFile(aPath, []byte("# A\n\nOld line.\n"), 0o644), "existing include should be written")
+	require.NoError(t, os.WriteFile(bPath, []byte("# B\n"), 0o644), "existing include should be written")
+
+	tracker := &FileTracker{OriginalContent: make(map[string][]byte), gitRoot: gitRoot}
+	spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "v1"}, WorkflowPath: "workflows/triage.md"}
+	installed, err := fetchAndSaveRemoteIncludes("@include prompts/a.md\n@include prompts/b.md\n", spec, workflowsDir, remoteFetchOptions{LogLevel: FetchLogVerbose, Force: true, Tracker: tracker, OnEvent: handler})
+	require.NoError(t, err, "includes should be fetched")
+
+	assert.Equal(t, []string{aPath}, installed, "only the changed include should be rewritten")
+	assert.Contains(t, tracker.ModifiedFiles, aPath, "changed include should be tracked as modified")
+	assert.NotContains(t, tracker.ModifiedFiles, bPath, "unchanged include should not be tracked as modified")
+	assert.Contains(t, messages, "Updated include: "+aPath+" (+1 -1 lines)", "changed include should be reported as updated")
+	assert.Contains(t, messages, "Include file unchanged, skipping: "+bPath, "unchanged include should be reported as unchanged")
+}
+
+func TestFetchAndSaveRemoteIncludes_MergesSections(t *testing.T) {
+	stubIncludeFiles(t, map[string]string{
+		"owner/repo/.github/shared/guide.md@v1": "---\ntools:\n  bash: true\n---\n\n## Tools\n\nUse tools.\n\n### Bash\n\nUse bash.\n\n## Style\n\nBe brief.\n\n## Other\n\nSkipped.\n",
+	})
+	gitRoot := t.TempDir()
+	workflowsDir := filepath.Join(gitRoot, ".github", "workflows")
+	guidePath := filepath.Join(gitRoot, ".github", "shared", "guide.md")
+	spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "v1"}, WorkflowPath: ".github/workflows/triage.md"}
+
+	_, err := fetchAndSaveRemoteIncludes("@include shared/guide.md#Tools\n", spec, workflowsDir, remoteFetchOptions{})
+	require.NoError(t, err, "first workflow's include should be fetched")
+	installed, err := fetchAndSaveRemoteIncludes("@include shared/guide.md#Style\n@include shared/guide.md#Bash\n", spec, workflowsDir, remoteFetchOptions{})
+	require.NoError(t, err, "second workflow's include should be fetched")
+	assert.Equal(t, []string{guidePath}, installed, "the shared file should be updated with the new section")
+
+	guide, err := os.ReadFile(guidePath)
+	require.NoError(t, err, "shared include should be saved")
+	assert.Equal(t, "---\ntools:\n  bash: true\n---\n\n## Tools\n\nUse tools.\n\n### Bash\n\nUse bash.\n\n## Style\n\nBe brief.\n", string(guide), "sections of both workflows should be kept once each")
+
+	installed, err = fetchAndSaveRemoteIncludes("@include shared/guide.md#Style\n", spec, workflowsDir, remoteFetchOptions{})
+	require.NoError(t, err, "include should be fetched")
+	assert.Empty(t, installed, "a shared file that has the sections should be kept")
+
+	require.NoError(t, os.WriteFile(guidePath, []byte("## Tools\n\nEdited.\n"), 0o644), "shared include should be edited")
+	_, err = fetchAndSaveRemoteIncludes("@include shared/guide.md#Other\n", spec, workflowsDir, remoteFetchOptions{})
+	require.NoError(t, err, "include should be fetched")
+	guide, err = os.ReadFile(guidePath)
+	require.NoError(t, err, "shared include should be kept")
+	assert.Equal(t, "## Tools\n\nEdited.\n", string(guide), "an edited shared file should not be rewritten without --force")
+
+	_, err = fetchAndSaveRemoteIncludes("@include shared/guide.md#Other\n", spec, workflowsDir, remoteFetchOptions{Force: true})
+	require.NoError(t, err, "include should be fetched")
+	guide, err = os.ReadFile(guidePath)
+	require.NoError(t, err, "shared include should be saved")
+	assert.Contains(t, string(guide), "## Tools\n\nUse tools.", "--force should keep the sections of the existing file")
+	assert.Contains(t, string(guide), "## Other\n\nSkipped.", "--force should add the new section")
+}
+
+func TestFetchAndSaveRemoteIncludes_NestedRelativeIncludes(t *testing.T) {
+	stubIncludeFiles(t, map[string]string{
+		"owner/repo/workflows/prompts/a.md@v1":                         "# A\n\n@include parts/b.md\n",
+		"owner/repo/workflows/prompts/parts/b.md@v1":                   "# B\n\n@include ../../../../outside.md\n",
+		"owner/repo/workflows/prompts/parts/../../../../outside.md@v1": "# Outside\n",
+		"other/lib/docs/c.md@v2":                                       "# C\n\n@include d.md\n",
+		"other/lib/docs/d.md@v2":                                       "# D\n",
+	})
+
+	gitRoot := t.TempDir()
+	workflowsDir := filepath.Join(gitRoot, ".github", "workflows")
+	spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "v1"}, WorkflowPath: "workflows/triage.md"}
+
+	content := "@include prompts/a.md\n@include other/lib/docs/c.md@v2\n"
+	installed, err := fetchAndSaveRemoteIncludes(content, spec, workflowsDir, remoteFetchOptions{})
+	require.NoError(t, err, "nested includes should be fetched")
+
+	assert.Equal(t, []string{
+		filepath.Join(workflowsDir, "prompts", "a.md"),
+		filepath.Join(workflowsDir, "prompts", "parts", "b.md"),
+		filepath.Join(gitRoot, ".github", "shared", "c.md"),
+		filepath.Join(gitRoot, ".github", "shared", "d.md"),
+	}, installed, "nested relative includes should resolve against the including file, in its own repository")
+	assert.NoFileExists(t, filepath.Join(gitRoot, "outside.md"), "includes escaping .github/ should not be written")
+}
+
+func TestFetchAndSaveRemoteIncludes_OptionalSubtree(t *testing.T) {
+	stubIncludeFiles(t, map[string]string{
+		"owner/repo/workflows/prompts/extras.md@v1":  "# Extras\n\n@include missing.md\n@include present.md\n",
+		"owner/repo/workflows/prompts/present.md@v1": "# Present\n",
+		"owner/repo/workflows/prompts/tools.md@v1":   "# Tools\n\n@include missing.md\n",
+	})
+	spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "v1"}, WorkflowPath: "workflows/triage.md"}
+
+	workflowsDir := filepath.Join(t.TempDir(), ".github", "workflows")
+	installed, err := fetchAndSaveRemoteIncludes("@include? prompts/extras.md\n", spec, workflowsDir, remoteFetchOptions{})
+	require.NoError(t, err, "includes below an optional include should be optional")
+	assert.Equal(t, []string{
+		filepath.Join(workflowsDir, "prompts", "extras.md"),
+		filepath.Join(workflowsDir, "prompts", "present.md"),
+	}, installed, "missing nested includes should be skipped without dropping their siblings")
+
+	_, err = fetchAndSaveRemoteIncludes("@include prompts/tools.md\n", spec, filepath.Join(t.TempDir(), ".github", "workflows"), remoteFetchOptions{})
+	require.Error(t, err, "includes below a required include should stay required")
+	assert.Contains(t, err.Error(), "missing.md", "error should name the missing include")
+}
+
+func TestFetchAndSaveRemoteIncludes_TargetOverride(t *testing.T) {
+	stubIncludeFiles(t, map[string]string{
+		"owner/repo/workflows/prompts/tools.md@v1":  "# Tools\n\n@include nested.md\n",
+		"owner/repo/workflows/prompts/nested.md@v1": "# Nested\n",
+		"other/lib/docs/guide.md@v2":                "# Guide\n",
+	})
+	spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "v1"}, WorkflowPath: "workflows/triage.md"}
+
+	workflowsDir := filepath.Join(t.TempDir(), ".github", "workflows")
+	content := "@include prompts/tools.md -> custom/tools.md\n@include other/lib/docs/guide.md@v2 -> shared/guides/guide.md\n"
+	installed, err := fetchAndSaveRemoteIncludes(content, spec, workflowsDir, remoteFetchOptions{})
+	require.NoError(t, err, "includes with target overrides should be fetched")
+	assert.Equal(t, []string{
+		filepath.Join(workflowsDir, "custom", "tools.md"),
+		filepath.Join(workflowsDir, "custom", "nested.md"),
+		filepath.Join(filepath.Dir(workflowsDir), "shared", "guides", "guide.md"),
+	}, installed, "targets should replace the default layout, with nested includes saved next to them")
+
+	workflowsDir = filepath.Join(t.TempDir(), ".github", "workflows")
+	installed, err = fetchAndSaveRemoteIncludes("@include prompts/nested.md -> ../../escape.md\n", spec, workflowsDir, remoteFetchOptions{})
+	require.NoError(t, err, "unsafe targets should be skipped with a warning")
+	assert.Empty(t, installed, "nothing should be written outside .github/")
+
+	_, err = fetchAndSaveRemoteIncludes("@include prompts/ -> custom/\n", spec, workflowsDir, remoteFetchOptions{})
+	require.Error(t, err, "target overrides on directory includes should be rejected")
+	assert.Contains(t, err.Error(), "only supported on files", "error should explain the restriction")
+}
+
+func TestFetchAndSaveRemoteIncludes_NestedDirectoryInclude(t *testing.T) {
+	stubIncludeFiles(t, map[string]string{
+		"owner/repo/workflows/guide.md@v1":     "# Guide\n\n@include? prompts/\n",
+		"owner/repo/workflows/prompts/a.md@v1": "# A\n",
+		"owner/repo/workflows/prompts/b.md@v1": "# B\n",
+	})
+	original := listIncludeDirectory
+	t.Cleanup(func() { listIncludeDirectory = original })
+	listIncludeDirectory = func(_ *parser.Fetcher, owner, repo, ref, dir string) ([]string, error) {
+		if owner+"/"+repo+"@"+ref+":"+dir != "owner/repo@v1:workflows/prompts" {
+			return nil, errors.New("not found")
+		}
+		return []string{"workflows/prompts/a.md", "workflows/prompts/b.md"}, nil
+	}
+	spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "v1"}, WorkflowPath: "workflows/triage.md"}
+
+	workflowsDir := filepath.Join(t.TempDir(), ".github", "workflows")
+	installed, err := fetchAndSaveRemoteIncludes("@include guide.md\n", spec, workflowsDir, remoteFetchOptions{})
+	require.NoError(t, err, "includes should be fetched")
+	assert.Len(t, installed, 3, "the directory's files should be fetched")
+
+	guide, err := os.ReadFile(filepath.Join(workflowsDir, "guide.md"))
+	require.NoError(t, err, "include should be saved")
+	assert.Equal(t, "# Guide\n\n@include? prompts/a.md\n@include? prompts/b.md\n", string(guide), "the directory include should be saved as one include per file")
+}
+
+func TestFetchAndSaveRemoteIncludes_ChecksumPins(t *testing.T) {
+	tools := "# Tools\n"
+	sum := sha256.Sum256([]byte(tools))
+	pin := "sha256:" + hex.EncodeToString(sum[:])
+	wrongPin := "sha256:" + strings.Repeat("0", 64)
+	stubIncludeFiles(t, map[string]string{
+		"owner/repo/workflows/prompts/tools.md@v1": tools,
+	})
+	spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "v1"}, WorkflowPath: "workflows/triage.md"}
+
+	workflowsDir := filepath.Join(t.TempDir(), ".github", "workflows")
+	installed, err := fetchAndSaveRemoteIncludes("@include prompts/tools.md@sha256:"+strings.ToUpper(pin[7:])+"#Tools\n", spec, workflowsDir, remoteFetchOptions{})
+	require.NoError(t, err, "include matching its pin should be fetched")
+	assert.Equal(t, []string{filepath.Join(workflowsDir, "prompts", "tools.md")}, installed, "pin should not be part of the saved file name")
+
+	for _, directive := range []string{"@include prompts/tools.md@" + wrongPin, "@include? prompts/tools.md@" + wrongPin} {
+		workflowsDir := filepath.Join(t.TempDir(), ".github", "workflows")
+		_, err := fetchAndSaveRemoteIncludes(directive+"\n", spec, workflowsDir, remoteFetchOptions{})
+		require.ErrorIs(t, err, parser.ErrIntegrityMismatch, "%s should fail even when optional", directive)
+		assert.Contains(t, err.Error(), pin, "error should report the actual checksum")
+		assert.NoFileExists(t, filepath.Join(workflowsDir, "prompts", "tools.md"), "nothing should be written on a mismatch")
+	}
+
+	_, err = fetchAndSaveRemoteIncludes("@include prompts/@"+pin+"\n", spec, filepath.Join(t.TempDir(), ".github", "workflows"), remoteFetchOptions{})
+	require.Error(t, err, "pinned directory includes should be rejected")
+	assert.Contains(t, err.Error(), "not directories", "error should explain the restriction")
+}
+
+func TestFetchAndSaveRemoteFrontmatterImports_ChecksumPins(t *testing.T) {
+	mirrorDir := t.TempDir()
+	sharedDir := filepath.Join(mirrorDir, ".github", "workflows", "shared")
+	require.NoError(t, os.MkdirAll(sharedDir, 0o755), "mirror directory should be created")
+	require.NoError(t, os.WriteFile(filepath.Join(sharedDir, "a.md"), []byte("# A\n"), 0o644), "mirror file should be written")
+	defer parser.SetRepoMirrors(map[string]string{"owner/repo": mirrorDir}, true)()
+
+	sum := sha256.Sum256([]byte("# A\n"))
+	pin := "sha256:" + hex.EncodeToString(sum[:])
+	spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "main"}, WorkflowPath: ".github/workflows/triage.md"}
+
+	targetDir := t.TempDir()
+	installed, err := fetchAndSaveRemoteFrontmatterImports("---\nimports:\n  - shared/a.md@"+pin+"\n---\n", spec, targetDir, remoteFetchOptions{})
+	require.NoError(t, err, "import matching its pin should be fetched")
+	assert.Equal(t, []string{filepath.Join(targetDir, "shared", "a.md")}, installed, "pin should not be part of the saved file name")
+
+	targetDir = t.TempDir()
+	_, err = fetchAndSaveRemoteFrontmatterImports("---\nimports:\n  - shared/a.md@sha256:"+strings.Repeat("f", 64)+"\n---\n", spec, targetDir, remoteFetchOptions{})
+	require.ErrorIs(t, err, parser.ErrIntegrityMismatch, "mismatching import should be a hard error")
+	assert.NoFileExists(t, filepath.Join(targetDir, "shared", "a.md"), "nothing should be written on a mismatch")
+}
+
+func TestFetchMissingSectionWarnings(t *testing.T) {
+	var warnings []string
+	handler := func(event FetchEvent) {
+		if event.Level == FetchEventWarning {
+			warnings = append(warnings, event.Message)
+		}
+	}
+
+	stubIncludeFiles(t, map[string]string{
+		"owner/repo/workflows/prompts/a.md@v1": "# Usage\n\nUse it.\n\n# Setup\n\nSet it up.\n",
+	})
+	spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "v1"}, WorkflowPath: "workflows/triage.md"}
+	_, err := fetchAndSaveRemoteIncludes("@include prompts/a.md\n@include prompts/a.md#Nonexistent\n", spec, filepath.Join(t.TempDir(), ".github", "workflows"), remoteFetchOptions{OnEvent: handler})
+	require.NoError(t, err, "includes should be fetched")
+
+	mirrorDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(mirrorDir, ".github", "workflows", "shared"), 0o755), "mirror directory should be created")
+	require.NoError(t, os.WriteFile(filepath.Join(mirrorDir, ".github", "workflows", "shared", "ci.md"), []byte("# Checks\n\nRun them.\n"), 0o644), "mirror file should be written")
+	defer parser.SetRepoMirrors(map[string]string{"owner/repo": mirrorDir}, true)()
+
+	importSpec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "main"}, WorkflowPath: ".github/workflows/triage.md"}
+	_, err = fetchAndSaveRemoteFrontmatterImports("---\nimports:\n  - shared/ci.md#Deploy\n---\n", importSpec, filepath.Join(t.TempDir(), ".github", "workflows"), remoteFetchOptions{OnEvent: handler})
+	require.NoError(t, err, "imports should be fetched")
+
+	assert.Equal(t, []string{
+		"Include prompts/a.md#Nonexistent references a missing section: section 'Nonexistent' not found (available sections: usage, setup)",
+		"Import shared/ci.md#Deploy references a missing section: section 'Deploy' not found (available sections: checks)",
+	}, warnings, "missing sections should be reported with the available sections")
+}
+
+func TestFetchAndSaveRemoteIncludes_Transform(t *testing.T) {
+	stubIncludeFiles(t, map[string]string{
+		"owner/repo/workflows/prompts/a.md@v1": "Label issues in {{repo}}.\n\n@include b.md\n",
+		"owner/repo/workflows/prompts/b.md@v1": "Use {{repo}} labels.\n",
+	})
+	spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "v1"}, WorkflowPath: "workflows/triage.md"}
+	workflowsDir := filepath.Join(t.TempDir(), ".github", "workflows")
+
+	var sources []string
+	transform := func(source string, content []byte) ([]byte, error) {
+		sources = append(sources, source)
+		return []byte(strings.ReplaceAll(string(content), "{{repo}}", "acme/app")), nil
+	}
+	_, err := fetchAndSaveRemoteIncludes("@include prompts/a.md\n", spec, workflowsDir, remoteFetchOptions{Transform: transform})
+	require.NoError(t, err, "includes should be fetched")
+
+	assert.Equal(t, []string{"owner/repo/prompts/a.md@v1", "owner/repo/b.md@v1"}, sources, "each include should be transformed once with its source")
+	data, err := os.ReadFile(filepath.Join(workflowsDir, "prompts", "b.md"))
+	require.NoError(t, err, "nested include should be saved")
+	assert.Equal(t, "Use acme/app labels.\n", string(data), "saved content should be transformed")
+
+	failing := func(source string, content []byte) ([]byte, error) {
+		return nil, errors.New("template error")
+	}
+	_, err = fetchAndSaveRemoteIncludes("@include prompts/a.md\n", spec, t.TempDir(), remoteFetchOptions{Force: true, Transform: failing})
+	require.ErrorContains(t, err, "template error", "transform errors should fail the fetch")
+}
+
+func TestIncludedFileSpec(t *testing.T) {
+	spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "v1"}, WorkflowPath: "workflows/triage.md"}
+
+	nested := includedFileSpec(&IncludeResult{ResolvedPath: "other/lib/docs/prompts/c.md@v2"}, spec)
+	assert.Equal(t, &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "other/lib", Version: "v2"}, WorkflowPath: "docs/prompts/c.md"}, nested,
+		"include location should become the base spec")
+
+	assert.Same(t, spec, includedFileSpec(&IncludeResult{}, spec), "unknown locations should keep the including spec")
+}
+
+func TestIsRootedIncludePath(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		prefixes []string
+		expected bool
+	}{
+		{name: "shared by default", filePath: "shared/tools.md", expected: true},
+		{name: "other prefix not rooted by default", filePath: "prompts/foo.md", expected: false},
+		{name: "configured prefix", filePath: "prompts/foo.md", prefixes: []string{"prompts"}, expected: true},
+		{name: "configured prefix with slashes", filePath: "prompts/foo.md", prefixes: []string{"./prompts/"}, expected: true},
+		{name: "configured prefixes replace default", filePath: "shared/tools.md", prefixes: []string{"prompts/"}, expected: false},
+		{name: "prefix must match a directory", filePath: "prompts-old/foo.md", prefixes: []string{"prompts"}, expected: false},
+		{name: "blank prefixes fall back to default", filePath: "shared/tools.md", prefixes: []string{" ", "/"}, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isRootedIncludePath(tt.filePath, tt.prefixes), "isRootedIncludePath(%q, %v)", tt.filePath, tt.prefixes)
+		})
+	}
+}
+
+func TestExpandDirectoryIncludeDirectives(t *testing.T) {
+	listed := map[string][]string{
+		"owner/repo@v1:.github/shared/prompts": {".github/shared/prompts/a.md", ".github/shared/prompts/b.md"},
+		"owner/repo@v1:workflows/local":        {"workflows/local/c.md"},
+		"other/lib@v2:prompts":                 {"prompts/d.md"},
+		"owner/repo@v1:.github/shared/empty":   {},
+	}
+	original := listIncludeDirectory
+	listIncludeDirectory = func(_ *parser.Fetcher, owner, repo, ref, dir string) ([]string, error) {
+		files, ok := listed[owner+"/"+repo+"@"+ref+":"+dir]
+		if !ok {
+			return nil, errors.New("not found")
+		}
+		return files, nil
+	}
+	defer func() { listIncludeDirectory = original }()
+
+	var warnings []string
+	handler := func(event FetchEvent) {
+		if event.Level == FetchEventWarning {
+			warnings = append(warnings, event.Message)
+		}
+	}
+
+	spec := &WorkflowSpec{
+		RepoSpec:     RepoSpec{RepoSlug: "owner/repo", Version: "v1"},
+		WorkflowPath: "workflows/main.md",
+	}
+	content := `@include shared/prompts/b.md
+@include shared/prompts/
+@include[engine=copilot] local/
+@include other/lib/prompts/@v2
+@include shared/empty/
+@include? shared/missing/
+`
+
+	expanded, err := expandDirectoryIncludeDirectives(content, spec, false, remoteFetchOptions{OnEvent: handler})
+	require.NoError(t, err, "directories should expand")
+	assert.Equal(t, `@include shared/prompts/b.md
+@include shared/prompts/a.md
+@include[engine=copilot] local/c.md
+@include other/lib/prompts/d.md@v2
+`, expanded, "directories should expand in order without repeating individual includes")
+	assert.Equal(t, []string{"Include directory contains no .md files: shared/empty/"}, warnings, "empty directory should warn")
+
+	_, err = expandDirectoryIncludeDirectives("@include shared/missing/\n", spec, false, remoteFetchOptions{})
+	require.Error(t, err, "required directory that cannot be listed should fail")
+	assert.Contains(t, err.Error(), "shared/missing/", "error should name the directory")
+
+	expanded, err = expandDirectoryIncludeDirectives("@include shared/missing/\n", spec, true, remoteFetchOptions{})
+	require.NoError(t, err, "directories below optional includes should be optional")
+	assert.Empty(t, expanded, "unlisted optional directories should be dropped")
+}
+
+func TestFetchWorkflowsFromSource(t *testing.T) {
+	originalDownload, originalResolve := downloadWorkflowFile, resolveRemoteWorkflowSHA
+	t.Cleanup(func() { downloadWorkflowFile, resolveRemoteWorkflowSHA = originalDownload, originalResolve })
+	resolveRemoteWorkflowSHA = func(_ *parser.Fetcher, owner, repo, ref string) (string, error) {
+		return "", errors.New("not resolved")
+	}
+	downloadWorkflowFile = func(_ *parser.Fetcher, owner, repo, path, ref string, maxSize int64) ([]byte, error) {
+		if path == "workflows/triage.md" {
+			return []byte("# Triage"), nil
+		}
+		return nil, &parser.NotFoundError{Source: path, Err: errors.New("HTTP 404")}
+	}
+
+	specs := []*WorkflowSpec{
+		{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "main"}, WorkflowPath: "workflows/triage.md"},
+		{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "main"}, WorkflowPath: "workflows/missing.md"},
+		{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "main"}, WorkflowPath: "workflows/triage.md"},
+	}
+	results := FetchWorkflowsFromSource(specs, false)
+
+	require.Len(t, results, 3, "each spec should have a result")
+	for i, result := range results {
+		assert.Same(t, specs[i], result.Spec, "results should be in spec order")
+	}
+	require.NoError(t, results[0].Err, "existing workflow should be fetched")
+	assert.Equal(t, "# Triage", string(results[0].Workflow.Content), "content should be returned")
+	require.Error(t, results[1].Err, "missing workflow should fail")
+	assert.Nil(t, results[1].Workflow, "failed fetch should have no workflow")
+	require.NoError(t, results[2].Err, "a failure should not stop later specs")
+}
+
+func TestFetchWorkflowsFromSourceMaxFileSize(t *testing.T) {
+	originalDownload, originalResolve := downloadWorkflowFile, resolveRemoteWorkflowSHA
+	t.Cleanup(func() { downloadWorkflowFile, resolveRemoteWorkflowSHA = originalDownload, originalResolve })
+	resolveRemoteWorkflowSHA = func(_ *parser.Fetcher, owner, repo, ref string) (string, error) {
+		return "", errors.New("not resolved")
+	}
+	var limits []int64
+	downloadWorkflowFile = func(_ *parser.Fetcher, owner, repo, path, ref string, maxSize int64) ([]byte, error) {
+		limits = append(limits, maxSize)
+		return []byte("# Triage"), nil
+	}
+
+	spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "main"}, WorkflowPath: "workflows/triage.md"}
+	results := fetchWorkflowsFromSource([]*WorkflowSpec{spec}, remoteFetchOptions{MaxFileSize: 1024})
+
+	require.NoError(t, results[0].Err, "workflow should be fetched")
+	assert.Equal(t, []int64{1024}, limits, "the configured limit should reach the download")
+}
+
+func TestFetchRemoteWorkflowFallbackOnlyOnNotFound(t *testing.T) {
+	originalDownload, originalResolve := downloadWorkflowFile, resolveRemoteWorkflowSHA
+	t.Cleanup(func() { downloadWorkflowFile, resolveRemoteWorkflowSHA = originalDownload, originalResolve })
+	resolveRemoteWorkflowSHA = func(_ *parser.Fetcher, owner, repo, ref string) (string, error) {
+		return "", errors.New("not resolved")
+	}
+
+	spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "main"}, WorkflowPath: "triage"}
+
+	t.Run("not found probes alternate paths", func(t *testing.T) {
+		var requested []string
+		downloadWorkflowFile = func(_ *parser.Fetcher, owner, repo, path, ref string, maxSize int64) ([]byte, error) {
+			requested = append(requested, path)
+			switch path {
+			case "workflows/triage.yml":
+				return []byte("on: push\njobs: {}\n"), nil
+			case ".github/workflows/triage.md":
+				return []byte("---\non: issues\n---\n# Triage"), nil
+			}
+			return nil, &parser.NotFoundError{Source: path, Err: errors.New("HTTP 404")}
+		}
+
+		fetched, err := fetchRemoteWorkflow(spec, remoteFetchOptions{})
+		require.NoError(t, err, "workflow should be found in .github/workflows")
+		assert.Equal(t, ".github/workflows/triage.md", fetched.SourcePath, "source path should be the alternate path")
+		assert.Equal(t, PathStrategyGitHubWorkflowsPrefix, fetched.PathStrategy, "strategy should record the matching prefix")
+		assert.Len(t, requested, 5, "paths should be probed until the workflow is found")
+	})
+
+	t.Run("fallback skips files that are not agentic workflows", func(t *testing.T) {
+		downloadWorkflowFile = func(_ *parser.Fetcher, owner, repo, path, ref string, maxSize int64) ([]byte, error) {
+			if path == ".github/workflows/triage.yml" {
+				return []byte("name: Triage\non: push\njobs: {}\n"), nil
+			}
+			return nil, &parser.NotFoundError{Source: path, Err: errors.New("HTTP 404")}
+		}
+
+		_, err := fetchRemoteWorkflow(spec, remoteFetchOptions{})
+		var notFound *parser.NotFoundError
+		require.ErrorAs(t, err, &notFound, "a GitHub Actions workflow should not be added")
+		assert.Contains(t, err.Error(), "skipped .github/workflows/triage.yml", "error should name the skipped file")
+	})
+
+	t.Run("direct path match", func(t *testing.T) {
+		downloadWorkflowFile = func(_ *parser.Fetcher, owner, repo, path, ref string, maxSize int64) ([]byte, error) {
+			return []byte("# Triage"), nil
+		}
+
+		fetched, err := fetchRemoteWorkflow(spec, remoteFetchOptions{})
+		require.NoError(t, err, "workflow should be found at the given path")
+		assert.Equal(t, PathStrategyDirect, fetched.PathStrategy, "strategy should be direct")
+		assert.Equal(t, PathStrategyWorkflowsPrefix, fallbackPathStrategy("workflows/triage.md"), "workflows/ paths should use the workflows prefix strategy")
+	})
+
+	t.Run("auth error is returned immediately", func(t *testing.T) {
+		var requested []string
+		downloadWorkflowFile = func(_ *parser.Fetcher, owner, repo, path, ref string, maxSize int64) ([]byte, error) {
+			requested = append(requested, path)
+			return nil, &parser.AuthError{Source: path, Err: errors.New("HTTP 401")}
+		}
+
+		_, err := fetchRemoteWorkflow(spec, remoteFetchOptions{})
+		var authErr *parser.AuthError
+		require.ErrorAs(t, err, &authErr, "auth error should be surfaced")
+		assert.Equal(t, []string{"triage"}, requested, "alternate paths should not be probed")
+	})
+
+	t.Run("rate limit on an alternate path stops probing", func(t *testing.T) {
+		var requested []string
+		downloadWorkflowFile = func(_ *parser.Fetcher, owner, repo, path, ref string, maxSize int64) ([]byte, error) {
+			requested = append(requested, path)
+			if path == "triage" {
+				return nil, &parser.NotFoundError{Source: path, Err: errors.New("HTTP 404")}
+			}
+			return nil, &parser.RateLimitError{Reset: "reset time unknown", Err: errors.New("HTTP 403")}
+		}
+
+		_, err := fetchRemoteWorkflow(spec, remoteFetchOptions{})
+		require.ErrorIs(t, err, parser.ErrRateLimited, "rate limit error should be surfaced")
+		assert.Equal(t, []string{"triage", "workflows/triage.md"}, requested, "probing should stop at the rate limit")
+	})
+}
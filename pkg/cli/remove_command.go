@@ -151,6 +151,16 @@ func RemoveWorkflows(pattern string, keepOrphans bool) error {
 				fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Removed: "+filepath.Base(lockFile)))
 			}
 		}
+
+		// Also remove the provenance file written by add --provenance
+		provenanceFile := provenancePath(file)
+		if _, err := os.Stat(provenanceFile); err == nil {
+			if err := os.Remove(provenanceFile); err != nil {
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to remove %s: %v", provenanceFile, err)))
+			} else {
+				fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("Removed: "+filepath.Base(provenanceFile)))
+			}
+		}
 	}
 
 	// Clean up orphaned include files (if orphan removal is enabled)
@@ -408,6 +418,9 @@ func findIncludesInContent(content, baseDir string, verbose bool) ([]string, err
 			}
 
 			includes = append(includes, filePath)
+			if directive.Target != "" {
+				includes = append(includes, directive.Target)
+			}
 		}
 	}
 
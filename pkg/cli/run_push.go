@@ -294,6 +294,12 @@ func collectImports(workflowPath string, files map[string]bool, visited map[stri
 	case []string:
 		runPushLog.Printf("Parsing imports as []string with %d items", len(v))
 		imports = v
+	case map[string]any:
+		// Import aliases: the workflow imports the local paths (keys)
+		runPushLog.Printf("Parsing imports as aliases with %d items", len(v))
+		for _, imp := range parseFrontmatterImports(v) {
+			imports = append(imports, imp.localPath)
+		}
 	default:
 		runPushLog.Printf("Imports field has unexpected type: %T", v)
 	}
@@ -364,7 +370,7 @@ func resolveImportPathLocal(importPath, baseDir string) string {
 	}
 
 	// Skip workflowspec format imports (owner/repo/path@sha)
-	if isWorkflowSpecFormatLocal(importPath) {
+	if IsWorkflowSpecFormat(importPath) {
 		runPushLog.Printf("Skipping workflowspec format import: %s", importPath)
 		return ""
 	}
@@ -389,14 +395,6 @@ func resolveImportPathLocal(importPath, baseDir string) string {
 	return resolved
 }
 
-// isWorkflowSpecFormatLocal is a local version of isWorkflowSpecFormat for push functionality
-// This is duplicated from imports.go to avoid circular dependencies
-func isWorkflowSpecFormatLocal(path string) bool {
-	// The only reliable indicator of a workflowspec is the @ version separator
-	// Paths like "shared/mcp/arxiv.md" should be treated as local paths, not workflowspecs
-	return strings.Contains(path, "@")
-}
-
 // pushWorkflowFiles commits and pushes the workflow files to the repository
 func pushWorkflowFiles(workflowName string, files []string, refOverride string, verbose bool) error {
 	runPushLog.Printf("Pushing %d files for workflow: %s", len(files), workflowName)
@@ -165,7 +165,7 @@ on: workflow_dispatch
 	assert.True(t, fileSet[baseSharedPath], "Should include base-shared.md file")
 }
 
-func TestIsWorkflowSpecFormatLocal(t *testing.T) {
+func TestIsWorkflowSpecFormatImportPaths(t *testing.T) {
 	tests := []struct {
 		name     string
 		path     string
@@ -215,8 +215,8 @@ func TestIsWorkflowSpecFormatLocal(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := isWorkflowSpecFormatLocal(tt.path)
-			assert.Equal(t, tt.expected, result, "isWorkflowSpecFormatLocal(%q) = %v, want %v", tt.path, result, tt.expected)
+			result := IsWorkflowSpecFormat(tt.path)
+			assert.Equal(t, tt.expected, result, "IsWorkflowSpecFormat(%q) = %v, want %v", tt.path, result, tt.expected)
 		})
 	}
 }
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"bufio"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var sharedNamespaceLog = logger.New("cli:shared_namespace")
+
+// This file contains helpers for namespacing shared include and import files by source
+// repository, so that workflows added from different repositories that ship files with
+// the same name (e.g. shared/common.md) do not overwrite each other.
+
+// includeDirectivePattern matches @include directives as fetched by collectRemoteIncludes
+var includeDirectivePattern = regexp.MustCompile(`^(@include\??(?:\[[^\]]*\])?\s+)(.+)$`)
+
+// sharedNamespace returns the subdirectory used to namespace shared files from a repository,
+// e.g. "octo-org/prompts" → "octo-org-prompts"
+func sharedNamespace(repoSlug string) string {
+	return strings.ReplaceAll(repoSlug, "/", "-")
+}
+
+// namespaceSharedPath inserts namespace after the rooted prefix of a slash-separated path,
+// e.g. "shared/common.md" → "shared/octo-org-prompts/common.md". Paths outside the rooted
+// prefixes (defaultRootedIncludePrefixes when empty) and paths already inside the namespace
+// are returned unchanged.
+func namespaceSharedPath(filePath, namespace string, rootedPrefixes []string) string {
+	if namespace == "" {
+		return filePath
+	}
+	for _, prefix := range normalizeRootedIncludePrefixes(rootedPrefixes) {
+		rest, ok := strings.CutPrefix(filePath, prefix)
+		if !ok {
+			continue
+		}
+		if strings.HasPrefix(rest, namespace+"/") {
+			return filePath
+		}
+		return prefix + namespace + "/" + rest
+	}
+	return filePath
+}
+
+// namespaceIncludeDirectives rewrites relative @include directives with a rooted prefix so they
+// point at the namespaced copies. Workflowspec includes and section fragments are preserved.
+func namespaceIncludeDirectives(content, namespace string, rootedPrefixes []string) string {
+	if namespace == "" {
+		return content
+	}
+
+	var builder strings.Builder
+	changed := false
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if matches := includeDirectivePattern.FindStringSubmatch(line); matches != nil {
+			includePath := strings.TrimSpace(matches[2])
+			filePath, section, hasSection := strings.Cut(includePath, "#")
+			if !IsWorkflowSpecFormat(filePath) {
+				if namespaced := namespaceSharedPath(filePath, namespace, rootedPrefixes); namespaced != filePath {
+					if hasSection {
+						namespaced += "#" + section
+					}
+					line = matches[1] + namespaced
+					changed = true
+				}
+			}
+		}
+		builder.WriteString(line)
+		builder.WriteString("\n")
+	}
+
+	if !changed {
+		return content
+	}
+	result := builder.String()
+	if !strings.HasSuffix(content, "\n") {
+		result = strings.TrimSuffix(result, "\n")
+	}
+	return result
+}
+
+// namespaceFrontmatterImports rewrites the relative frontmatter imports of a file so they keep
+// pointing at the right files after shared imports are moved into the namespace. localPath is
+// the file's slash-separated location relative to the workflows directory before namespacing.
+// Workflowspec and repository-root ("/...") imports are left unchanged.
+func namespaceFrontmatterImports(content, localPath, namespace string) (string, error) {
+	if namespace == "" {
+		return content, nil
+	}
+
+	result, err := parser.ExtractFrontmatterFromContent(content)
+	if err != nil || result.Frontmatter == nil {
+		return content, nil
+	}
+	imports, ok := result.Frontmatter["imports"].([]any)
+	if !ok {
+		return content, nil
+	}
+
+	oldDir := path.Dir(localPath)
+	newDir := path.Dir(namespaceSharedPath(localPath, namespace, nil))
+
+	changed := false
+	rewritten := make([]any, 0, len(imports))
+	for _, item := range imports {
+		importPath, ok := item.(string)
+		if !ok || importPath == "" || IsWorkflowSpecFormat(importPath) || strings.HasPrefix(importPath, "/") {
+			rewritten = append(rewritten, item)
+			continue
+		}
+
+		filePath, section, hasSection := strings.Cut(importPath, "#")
+		oldTarget := path.Clean(path.Join(oldDir, filePath))
+		newTarget := namespaceSharedPath(oldTarget, namespace, nil)
+		if newDir == oldDir && newTarget == oldTarget {
+			rewritten = append(rewritten, item)
+			continue
+		}
+		rel, err := filepath.Rel(filepath.FromSlash(newDir), filepath.FromSlash(newTarget))
+		if err != nil {
+			rewritten = append(rewritten, item)
+			continue
+		}
+		newPath := filepath.ToSlash(rel)
+		if hasSection {
+			newPath += "#" + section
+		}
+		sharedNamespaceLog.Printf("Namespaced import in %s: %s -> %s", localPath, importPath, newPath)
+		rewritten = append(rewritten, newPath)
+		changed = true
+	}
+
+	if !changed {
+		return content, nil
+	}
+	result.Frontmatter["imports"] = rewritten
+	return reconstructWorkflowFileFromMap(result.Format, result.Frontmatter, result.Markdown)
+}
@@ -0,0 +1,91 @@
+//go:build !integration
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaceSharedPath(t *testing.T) {
+	const ns = "octo-org-prompts"
+
+	tests := []struct {
+		name     string
+		filePath string
+		prefixes []string
+		expected string
+	}{
+		{name: "shared file", filePath: "shared/common.md", expected: "shared/octo-org-prompts/common.md"},
+		{name: "nested shared file", filePath: "shared/mcp/tool.md", expected: "shared/octo-org-prompts/mcp/tool.md"},
+		{name: "already namespaced", filePath: "shared/octo-org-prompts/common.md", expected: "shared/octo-org-prompts/common.md"},
+		{name: "non-shared file", filePath: "helpers/common.md", expected: "helpers/common.md"},
+		{name: "custom prefix", filePath: "prompts/a.md", prefixes: []string{"prompts"}, expected: "prompts/octo-org-prompts/a.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, namespaceSharedPath(tt.filePath, ns, tt.prefixes), "namespaced path mismatch")
+		})
+	}
+
+	assert.Equal(t, "shared/common.md", namespaceSharedPath("shared/common.md", "", nil), "empty namespace should leave paths unchanged")
+	assert.Equal(t, "octo-org-prompts", sharedNamespace("octo-org/prompts"), "namespace should join owner and repo")
+}
+
+func TestNamespaceIncludeDirectives(t *testing.T) {
+	content := `# Shared
+
+@include shared/common.md
+@include? shared/optional.md#Setup
+@include[engine=copilot] shared/copilot-tips.md
+@include local.md
+@include other/repo/shared/x.md@v1
+`
+	expected := `# Shared
+
+@include shared/octo-org-prompts/common.md
+@include? shared/octo-org-prompts/optional.md#Setup
+@include[engine=copilot] shared/octo-org-prompts/copilot-tips.md
+@include local.md
+@include other/repo/shared/x.md@v1
+`
+	assert.Equal(t, expected, namespaceIncludeDirectives(content, "octo-org-prompts", nil), "rooted includes should be namespaced")
+	assert.Equal(t, content, namespaceIncludeDirectives(content, "", nil), "empty namespace should leave content unchanged")
+}
+
+func TestNamespaceFrontmatterImports(t *testing.T) {
+	const ns = "octo-org-prompts"
+
+	t.Run("workflow importing shared files", func(t *testing.T) {
+		content := "---\non: push\nimports:\n  - shared/common.md\n  - helpers/local.md\n  - owner/repo/shared/x.md@v1\n---\n# Workflow\n"
+		rewritten, err := namespaceFrontmatterImports(content, "workflow.md", ns)
+		require.NoError(t, err, "rewriting should succeed")
+
+		result, err := parser.ExtractFrontmatterFromContent(rewritten)
+		require.NoError(t, err, "rewritten content should parse")
+		assert.Equal(t, []any{"shared/octo-org-prompts/common.md", "helpers/local.md", "owner/repo/shared/x.md@v1"},
+			result.Frontmatter["imports"], "only shared imports should be namespaced")
+	})
+
+	t.Run("shared file importing a sibling and a parent file", func(t *testing.T) {
+		content := "---\nimports:\n  - sibling.md#Intro\n  - ../helpers/local.md\n---\n# Shared\n"
+		rewritten, err := namespaceFrontmatterImports(content, "shared/common.md", ns)
+		require.NoError(t, err, "rewriting should succeed")
+
+		result, err := parser.ExtractFrontmatterFromContent(rewritten)
+		require.NoError(t, err, "rewritten content should parse")
+		assert.Equal(t, []any{"sibling.md#Intro", "../../helpers/local.md"},
+			result.Frontmatter["imports"], "sibling stays relative; parent reference gains a level")
+	})
+
+	t.Run("no shared imports", func(t *testing.T) {
+		content := "---\nimports:\n  - helpers/local.md\n---\n# Workflow\n"
+		rewritten, err := namespaceFrontmatterImports(content, "workflow.md", ns)
+		require.NoError(t, err, "rewriting should succeed")
+		assert.Equal(t, content, rewritten, "content without shared imports should be unchanged")
+	})
+}
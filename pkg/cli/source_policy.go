@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var sourcePolicyLog = logger.New("cli:source_policy")
+
+// allowedSourcesEnvVar names the environment variable holding a comma-separated allowed-sources
+// policy that applies to every fetch (e.g. set by administrators on CI runners)
+const allowedSourcesEnvVar = "GH_AW_ALLOWED_SOURCES"
+
+// ErrSourceNotAllowed is returned (wrapped) when a workflow, include, or import would be fetched
+// from a repository outside the allowed sources
+var ErrSourceNotAllowed = errors.New("source is not allowed by policy")
+
+// parseAllowedSources validates allowed-sources patterns and normalizes them to lowercase.
+// Each pattern is an owner ("octo-org" or "octo-org/*") or a single repository ("octo-org/tools").
+// Blank patterns are ignored.
+func parseAllowedSources(patterns []string) ([]string, error) {
+	var normalized []string
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if pattern == "" {
+			continue
+		}
+		owner, repo, hasRepo := strings.Cut(pattern, "/")
+		if owner == "" || owner == "*" || strings.Contains(repo, "/") || (hasRepo && repo == "") || strings.Contains(pattern, "@") {
+			return nil, fmt.Errorf("invalid allowed source %q: must be owner, owner/*, or owner/repo", pattern)
+		}
+		normalized = append(normalized, strings.TrimSuffix(pattern, "/*"))
+	}
+	return normalized, nil
+}
+
+// envAllowedSources returns the normalized GH_AW_ALLOWED_SOURCES patterns, or nil when the
+// variable is not set
+func envAllowedSources() ([]string, error) {
+	env := os.Getenv(allowedSourcesEnvVar)
+	if env == "" {
+		return nil, nil
+	}
+	patterns, err := parseAllowedSources(strings.Split(env, ","))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", allowedSourcesEnvVar, err)
+	}
+	return patterns, nil
+}
+
+// checkSourceAllowed returns an error wrapping ErrSourceNotAllowed unless owner/repo matches
+// the GH_AW_ALLOWED_SOURCES policy and the normalized patterns configured for the fetch (see
+// parseAllowedSources). Configured patterns can only narrow the environment policy, never widen
+// it. Either policy allows any source when empty. Local workflows are not subject to the policy.
+func checkSourceAllowed(repoSlug string, allowed []string) error {
+	envPatterns, err := envAllowedSources()
+	if err != nil {
+		return err
+	}
+	for _, patterns := range [][]string{envPatterns, allowed} {
+		if len(patterns) > 0 && !matchesAllowedSource(repoSlug, patterns) {
+			sourcePolicyLog.Printf("Refusing to fetch from %s", repoSlug)
+			return fmt.Errorf("policy violation: fetching from %s is not allowed (allowed sources: %s): %w", repoSlug, strings.Join(patterns, ", "), ErrSourceNotAllowed)
+		}
+	}
+	return nil
+}
+
+// matchesAllowedSource reports whether owner/repo matches one of the normalized patterns
+func matchesAllowedSource(repoSlug string, patterns []string) bool {
+	slug := strings.ToLower(repoSlug)
+	owner, _, _ := strings.Cut(slug, "/")
+	return slices.ContainsFunc(patterns, func(pattern string) bool {
+		return pattern == slug || pattern == owner
+	})
+}
+
+// isFatalFetchError reports whether err is a policy violation (including a disabled HTTP(S)
+// include), a frozen-mode failure, a checksum pin mismatch, or an ambiguous ref, which always
+// abort the fetch instead of being skipped with a warning
+func isFatalFetchError(err error) bool {
+	var ambiguous *parser.AmbiguousRefError
+	return errors.Is(err, ErrSourceNotAllowed) || errors.Is(err, ErrFetchLockMismatch) || errors.Is(err, parser.ErrRefNotLocked) ||
+		errors.Is(err, parser.ErrIntegrityMismatch) || errors.Is(err, ErrHTTPIncludesDisabled) || errors.As(err, &ambiguous)
+}
@@ -0,0 +1,90 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAllowedSources(t *testing.T) {
+	patterns, err := parseAllowedSources([]string{"Octo-Org", " acme/* ", "", "other/Tools"})
+	require.NoError(t, err, "valid patterns should parse")
+	assert.Equal(t, []string{"octo-org", "acme", "other/tools"}, patterns, "patterns should be normalized")
+
+	for _, invalid := range []string{"*", "owner/", "/repo", "owner/repo/path", "owner/repo@main"} {
+		_, err := parseAllowedSources([]string{invalid})
+		assert.Error(t, err, "pattern %q should be rejected", invalid)
+	}
+}
+
+func TestCheckSourceAllowed(t *testing.T) {
+	t.Setenv(allowedSourcesEnvVar, "")
+	require.NoError(t, checkSourceAllowed("anyone/anything", nil), "all sources should be allowed without a policy")
+
+	t.Run("configured policy", func(t *testing.T) {
+		allowed := []string{"octo-org", "acme/tools"}
+		require.NoError(t, checkSourceAllowed("Octo-Org/workflows", allowed), "repositories of allowed owners should be allowed")
+		require.NoError(t, checkSourceAllowed("acme/tools", allowed), "allowed repositories should be allowed")
+		err := checkSourceAllowed("acme/other", allowed)
+		require.ErrorIs(t, err, ErrSourceNotAllowed, "other repositories of the owner should be refused")
+		assert.Contains(t, err.Error(), "policy violation", "error should name the policy violation")
+	})
+
+	t.Run("environment policy", func(t *testing.T) {
+		t.Setenv(allowedSourcesEnvVar, "octo-org,acme")
+		require.ErrorIs(t, checkSourceAllowed("untrusted/repo", nil), ErrSourceNotAllowed, "environment policy should apply")
+		require.ErrorIs(t, checkSourceAllowed("untrusted/repo", []string{"untrusted"}), ErrSourceNotAllowed, "configured patterns should not widen the environment policy")
+		require.NoError(t, checkSourceAllowed("acme/tools", []string{"acme/tools"}), "sources allowed by both policies should be allowed")
+		require.ErrorIs(t, checkSourceAllowed("octo-org/repo", []string{"acme"}), ErrSourceNotAllowed, "configured patterns should narrow the environment policy")
+	})
+}
+
+func TestFetchWithAllowedSources(t *testing.T) {
+	t.Setenv(allowedSourcesEnvVar, "owner,untrusted")
+	opts := remoteFetchOptions{AllowedSources: []string{"owner"}}
+
+	stubIncludeFiles(t, map[string]string{
+		"owner/repo/.github/shared/tools.md@v1": "Use the tools.\n",
+		"untrusted/lib/prompts/style.md@v1":     "Write clearly.\n",
+	})
+	spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "v1"}, WorkflowPath: "workflows/triage.md"}
+
+	_, err := fetchAndSaveRemoteIncludes("@include shared/tools.md\n", spec, t.TempDir(), opts)
+	require.NoError(t, err, "includes from allowed sources should be fetched")
+
+	_, err = fetchAndSaveRemoteIncludes("@include? untrusted/lib/prompts/style.md@v1\n", spec, t.TempDir(), opts)
+	require.ErrorIs(t, err, ErrSourceNotAllowed, "includes from other sources should be refused, even when optional")
+
+	_, err = fetchWorkflowFromSource(&WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "untrusted/lib", Version: "v1"}, WorkflowPath: "workflows/triage.md"}, opts)
+	require.ErrorIs(t, err, ErrSourceNotAllowed, "workflows from other sources should be refused")
+
+	other := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "untrusted/lib", Version: "v1"}, WorkflowPath: "workflows/triage.md"}
+	_, err = fetchAndSaveRemoteFrontmatterImports("---\nimports:\n  - shared/ci.md\n---\n", other, t.TempDir(), opts)
+	require.ErrorIs(t, err, ErrSourceNotAllowed, "imports from other sources should be refused")
+}
+
+func TestAddResolvedWorkflowsWithAllowedSources(t *testing.T) {
+	t.Setenv(allowedSourcesEnvVar, "")
+	tempDir := testutil.TempDir(t, "test-*")
+	require.NoError(t, exec.Command("git", "init", tempDir).Run(), "git init should succeed")
+	t.Chdir(tempDir)
+
+	stubIncludeFiles(t, map[string]string{
+		"untrusted/lib/prompts/style.md@v1": "Write clearly.\n",
+	})
+	content := []byte("---\non: push\n---\n\n@include untrusted/lib/prompts/style.md@v1\n")
+	spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "v1"}, WorkflowPath: "workflows/triage.md", WorkflowName: "triage"}
+	resolved := &ResolvedWorkflows{Workflows: []*ResolvedWorkflow{{
+		Spec:       spec,
+		Content:    content,
+		SourceInfo: &FetchedWorkflow{Content: content, SourcePath: spec.WorkflowPath},
+	}}}
+
+	_, err := AddResolvedWorkflows([]string{"owner/repo/workflows/triage.md@v1"}, resolved, AddOptions{AllowedSources: []string{"owner"}, NoGitattributes: true})
+	require.ErrorIs(t, err, ErrSourceNotAllowed, "includes of pre-resolved workflows should be subject to the configured policy")
+}
@@ -0,0 +1,277 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var sourceProviderLog = logger.New("cli:source_provider")
+
+// sourceProvidersEnvVar names the environment variable mapping source hosts to provider kinds,
+// as a comma-separated list of host=kind entries (e.g. "git.example.com=gitea")
+const sourceProvidersEnvVar = "GH_AW_SOURCE_PROVIDERS"
+
+// giteaTokenEnvVar names the environment variable holding the token sent to Gitea hosts
+const giteaTokenEnvVar = "GITEA_TOKEN"
+
+// SourceProvider downloads workflows, includes, and imports from the forge hosting a source
+// repository. GitHub is the default; other forges are selected per host (see
+// RegisterSourceProvider and GH_AW_SOURCE_PROVIDERS).
+type SourceProvider interface {
+	// ResolveRef resolves a branch, tag, or commit SHA of owner/repo to a full commit SHA
+	ResolveRef(owner, repo, ref string) (string, error)
+	// DownloadFile returns the content of path in owner/repo at ref. Missing files are
+	// reported with a *parser.NotFoundError, and files larger than maxSize bytes
+	// (parser.DefaultMaxDownloadSize when maxSize is 0) with parser.ErrFileTooLarge.
+	DownloadFile(owner, repo, path, ref string, maxSize int64) ([]byte, error)
+	// ListDir returns the repository-relative paths of the .md files directly in dir of
+	// owner/repo at ref. Missing directories are reported with a *parser.NotFoundError.
+	ListDir(owner, repo, ref, dir string) ([]string, error)
+}
+
+// sourceProviders holds the providers registered with RegisterSourceProvider, keyed by host
+var sourceProviders sync.Map
+
+// RegisterSourceProvider makes provider serve every fetch while host is the configured source
+// host (see parser.GetGitHubHost). host may include a scheme, e.g. "https://git.example.com".
+// A registered provider takes precedence over GH_AW_SOURCE_PROVIDERS.
+func RegisterSourceProvider(host string, provider SourceProvider) {
+	sourceProviders.Store(sourceHostKey(host), provider)
+}
+
+// sourceHostKey normalizes a host or host URL to a lowercase host name
+func sourceHostKey(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+	return strings.TrimRight(host, "/")
+}
+
+// currentSourceProvider returns the provider for the configured source host
+func currentSourceProvider(fetcher *parser.Fetcher) (SourceProvider, error) {
+	return sourceProviderForHost(parser.GetGitHubHost(), fetcher)
+}
+
+// sourceProviderForHost returns fetcher, which fetches through the parser download layer
+// (including frozen refs, repository mirrors, request headers, the request limit, and the
+// download caches), sending its requests to the forge of hostURL. GitHub is fetched from directly.
+func sourceProviderForHost(hostURL string, fetcher *parser.Fetcher) (SourceProvider, error) {
+	forge, err := sourceForgeForHost(hostURL, fetcher.RequestHeaders())
+	if err != nil {
+		return nil, err
+	}
+	if forge == nil {
+		return fetcher, nil
+	}
+	return fetcher.WithForge(sourceHostKey(hostURL), forge), nil
+}
+
+// sourceForgeForHost returns the provider registered for hostURL, or the provider configured for
+// it in GH_AW_SOURCE_PROVIDERS sending headers with each request. It returns nil for GitHub,
+// when neither is set.
+func sourceForgeForHost(hostURL string, headers map[string]string) (SourceProvider, error) {
+	key := sourceHostKey(hostURL)
+	if provider, ok := sourceProviders.Load(key); ok {
+		return provider.(SourceProvider), nil
+	}
+
+	kinds, err := parseSourceProviders(os.Getenv(sourceProvidersEnvVar))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", sourceProvidersEnvVar, err)
+	}
+	switch kinds[key] {
+	case "gitea":
+		sourceProviderLog.Printf("Using Gitea source provider for %s", key)
+		return newGiteaSourceProvider(hostURL, headers), nil
+	default:
+		return nil, nil
+	}
+}
+
+// parseSourceProviders parses a comma-separated list of host=kind entries into a map from
+// normalized host to kind. Supported kinds are "github" and "gitea"; blank entries are ignored.
+func parseSourceProviders(value string) (map[string]string, error) {
+	kinds := make(map[string]string)
+	for entry := range strings.SplitSeq(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		host, kind, ok := strings.Cut(entry, "=")
+		host, kind = sourceHostKey(host), strings.ToLower(strings.TrimSpace(kind))
+		if !ok || host == "" {
+			return nil, fmt.Errorf("invalid source provider %q: expected host=kind, e.g. git.example.com=gitea", entry)
+		}
+		if kind != "github" && kind != "gitea" {
+			return nil, fmt.Errorf("unsupported source provider kind %q for %s: must be github or gitea", kind, host)
+		}
+		kinds[host] = kind
+	}
+	return kinds, nil
+}
+
+// downloadSourceFile, listSourceDir, and resolveSourceRef fetch through the provider of the
+// configured source host, using fetcher for GitHub
+func downloadSourceFile(fetcher *parser.Fetcher, owner, repo, path, ref string, maxSize int64) ([]byte, error) {
+	provider, err := currentSourceProvider(fetcher)
+	if err != nil {
+		return nil, err
+	}
+	return provider.DownloadFile(owner, repo, path, ref, maxSize)
+}
+
+func listSourceDir(fetcher *parser.Fetcher, owner, repo, ref, dir string) ([]string, error) {
+	provider, err := currentSourceProvider(fetcher)
+	if err != nil {
+		return nil, err
+	}
+	return provider.ListDir(owner, repo, ref, dir)
+}
+
+func resolveSourceRef(fetcher *parser.Fetcher, owner, repo, ref string) (string, error) {
+	provider, err := currentSourceProvider(fetcher)
+	if err != nil {
+		return "", err
+	}
+	return provider.ResolveRef(owner, repo, ref)
+}
+
+// fullCommitSHAPattern matches a full 40-character commit SHA
+var fullCommitSHAPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// giteaSourceProvider fetches from a Gitea (or Forgejo) instance through its REST API
+type giteaSourceProvider struct {
+	apiURL  string            // e.g. "https://git.example.com/api/v1"
+	token   string            // Sent as an authorization token when set
+	headers map[string]string // Sent with each request, including the User-Agent
+	client  *http.Client
+}
+
+// newGiteaSourceProvider returns a provider for the Gitea instance at hostURL sending headers
+// with each request, authenticated with GITEA_TOKEN when it is set
+func newGiteaSourceProvider(hostURL string, headers map[string]string) *giteaSourceProvider {
+	if !strings.HasPrefix(hostURL, "https://") && !strings.HasPrefix(hostURL, "http://") {
+		hostURL = "https://" + hostURL
+	}
+	return &giteaSourceProvider{
+		apiURL:  strings.TrimRight(hostURL, "/") + "/api/v1",
+		token:   os.Getenv(giteaTokenEnvVar),
+		headers: headers,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (p *giteaSourceProvider) ResolveRef(owner, repo, ref string) (string, error) {
+	if fullCommitSHAPattern.MatchString(ref) {
+		return ref, nil
+	}
+	var commits []struct {
+		SHA string `json:"sha"`
+	}
+	endpoint := fmt.Sprintf("repos/%s/%s/commits?sha=%s&limit=1&stat=false&files=false", url.PathEscape(owner), url.PathEscape(repo), url.QueryEscape(ref))
+	body, err := p.get(endpoint, fmt.Sprintf("%s/%s@%s", owner, repo, ref), 0)
+	if err != nil {
+		return "", err
+	}
+	if err := json.Unmarshal(body, &commits); err != nil {
+		return "", fmt.Errorf("failed to parse commits of %s/%s@%s: %w", owner, repo, ref, err)
+	}
+	if len(commits) == 0 || commits[0].SHA == "" {
+		return "", &parser.NotFoundError{Source: fmt.Sprintf("%s/%s@%s", owner, repo, ref), Err: errors.New("no commit found")}
+	}
+	return commits[0].SHA, nil
+}
+
+func (p *giteaSourceProvider) DownloadFile(owner, repo, path, ref string, maxSize int64) ([]byte, error) {
+	endpoint := fmt.Sprintf("repos/%s/%s/raw/%s?ref=%s", url.PathEscape(owner), url.PathEscape(repo), escapeSourcePath(path), url.QueryEscape(ref))
+	return p.get(endpoint, fmt.Sprintf("%s/%s/%s@%s", owner, repo, path, ref), parser.MaxDownloadSize(maxSize))
+}
+
+func (p *giteaSourceProvider) ListDir(owner, repo, ref, dir string) ([]string, error) {
+	endpoint := fmt.Sprintf("repos/%s/%s/contents/%s?ref=%s", url.PathEscape(owner), url.PathEscape(repo), escapeSourcePath(dir), url.QueryEscape(ref))
+	body, err := p.get(endpoint, fmt.Sprintf("%s/%s/%s@%s", owner, repo, dir, ref), 0)
+	if err != nil {
+		return nil, err
+	}
+	var contents []struct {
+		Name string `json:"name"`
+		Path string `json:"path"`
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(body, &contents); err != nil {
+		return nil, fmt.Errorf("failed to parse contents of %s/%s/%s@%s: %w", owner, repo, dir, ref, err)
+	}
+
+	var files []string
+	for _, item := range contents {
+		if item.Type == "file" && strings.HasSuffix(strings.ToLower(item.Name), ".md") {
+			files = append(files, item.Path)
+		}
+	}
+	return files, nil
+}
+
+// get requests endpoint relative to the API URL and returns the response body. source names
+// what was requested in errors; 404 responses are returned as *parser.NotFoundError and 401
+// and 403 responses as *parser.AuthError. When limit is positive, bodies larger than limit
+// bytes fail with parser.ErrFileTooLarge without being read in full.
+func (p *giteaSourceProvider) get(endpoint, source string, limit int64) ([]byte, error) {
+	sourceProviderLog.Printf("GET %s/%s", p.apiURL, endpoint)
+	req, err := http.NewRequest(http.MethodGet, p.apiURL+"/"+endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for %s: %w", source, err)
+	}
+	for name, value := range p.headers {
+		req.Header.Set(name, value)
+	}
+	if p.token != "" {
+		req.Header.Set("Authorization", "token "+p.token)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", source, err)
+	}
+	defer resp.Body.Close()
+
+	var reader io.Reader = resp.Body
+	if limit > 0 {
+		reader = io.LimitReader(resp.Body, limit+1)
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response for %s: %w", source, err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		if limit > 0 && int64(len(body)) > limit {
+			return nil, fmt.Errorf("%s exceeds %d bytes: %w", source, limit, parser.ErrFileTooLarge)
+		}
+		return body, nil
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, &parser.NotFoundError{Source: source, Err: fmt.Errorf("HTTP %d", resp.StatusCode)}
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, &parser.AuthError{Source: source, Err: fmt.Errorf("HTTP %d (set %s to authenticate)", resp.StatusCode, giteaTokenEnvVar)}
+	default:
+		return nil, fmt.Errorf("failed to fetch %s: HTTP %d", source, resp.StatusCode)
+	}
+}
+
+// escapeSourcePath escapes each segment of a repository path for use in a URL path
+func escapeSourcePath(repoPath string) string {
+	segments := strings.Split(strings.Trim(repoPath, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
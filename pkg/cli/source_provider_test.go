@@ -0,0 +1,253 @@
+//go:build !integration
+
+package cli
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubSourceProvider serves files from memory
+type stubSourceProvider struct {
+	files map[string]string
+}
+
+func (p stubSourceProvider) ResolveRef(owner, repo, ref string) (string, error) {
+	return "0123456789abcdef0123456789abcdef01234567", nil
+}
+
+func (p stubSourceProvider) DownloadFile(owner, repo, path, ref string, maxSize int64) ([]byte, error) {
+	if content, ok := p.files[path]; ok {
+		return []byte(content), nil
+	}
+	return nil, &parser.NotFoundError{Source: path, Err: errors.New("missing")}
+}
+
+func (p stubSourceProvider) ListDir(owner, repo, ref, dir string) ([]string, error) {
+	return nil, &parser.NotFoundError{Source: dir, Err: errors.New("missing")}
+}
+
+// setSourceHost makes host the configured source host for the duration of the test
+func setSourceHost(t *testing.T, host string) {
+	t.Helper()
+	for _, envVar := range []string{"GITHUB_SERVER_URL", "GITHUB_ENTERPRISE_HOST", "GITHUB_HOST"} {
+		t.Setenv(envVar, "")
+	}
+	t.Setenv("GH_HOST", host)
+}
+
+func TestSourceProviderForHost(t *testing.T) {
+	t.Run("GitHub is the default", func(t *testing.T) {
+		t.Setenv(sourceProvidersEnvVar, "")
+		fetcher, err := parser.NewFetcher(parser.FetchConfig{})
+		require.NoError(t, err, "fetcher should be created")
+		provider, err := sourceProviderForHost("https://github.com", fetcher)
+		require.NoError(t, err, "default provider should be returned")
+		assert.Same(t, fetcher, provider, "GitHub should be fetched through the parser fetcher")
+	})
+
+	t.Run("configured Gitea host", func(t *testing.T) {
+		t.Setenv(sourceProvidersEnvVar, "other.example.com=github, Git.Example.com=gitea")
+		t.Setenv(giteaTokenEnvVar, "secret")
+		provider, err := sourceForgeForHost("https://git.example.com/", map[string]string{"User-Agent": "corp-fetcher/1.0"})
+		require.NoError(t, err, "Gitea provider should be returned")
+		gitea, ok := provider.(*giteaSourceProvider)
+		require.True(t, ok, "configured host should use the Gitea provider")
+		assert.Equal(t, "https://git.example.com/api/v1", gitea.apiURL, "API URL should be derived from the host")
+		assert.Equal(t, "secret", gitea.token, "token should come from GITEA_TOKEN")
+		assert.Equal(t, "corp-fetcher/1.0", gitea.headers["User-Agent"], "request headers should be sent")
+
+		provider, err = sourceProviderForHost("https://git.example.com/", nil)
+		require.NoError(t, err, "Gitea provider should be returned")
+		assert.IsType(t, &parser.Fetcher{}, provider, "Gitea should be fetched through the parser fetcher")
+	})
+
+	t.Run("registered provider takes precedence", func(t *testing.T) {
+		t.Setenv(sourceProvidersEnvVar, "forge.example.com=gitea")
+		RegisterSourceProvider("https://forge.example.com", stubSourceProvider{})
+		t.Cleanup(func() { sourceProviders.Delete("forge.example.com") })
+		provider, err := sourceForgeForHost("forge.example.com", nil)
+		require.NoError(t, err, "registered provider should be returned")
+		assert.IsType(t, stubSourceProvider{}, provider, "registered provider should win over the environment")
+	})
+
+	t.Run("invalid configuration", func(t *testing.T) {
+		for _, value := range []string{"git.example.com", "=gitea", "git.example.com=gitlab"} {
+			t.Setenv(sourceProvidersEnvVar, value)
+			_, err := sourceProviderForHost("https://git.example.com", nil)
+			require.Error(t, err, "%q should be rejected", value)
+			assert.Contains(t, err.Error(), sourceProvidersEnvVar, "error should name the environment variable")
+		}
+	})
+}
+
+func TestFetchWorkflowFromSourceUsesHostProvider(t *testing.T) {
+	setSourceHost(t, "forge.test")
+	RegisterSourceProvider("forge.test", stubSourceProvider{files: map[string]string{
+		".github/workflows/triage.md": "# Triage",
+	}})
+	t.Cleanup(func() { sourceProviders.Delete("forge.test") })
+
+	spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "main"}, WorkflowPath: ".github/workflows/triage.md"}
+	fetched, err := FetchWorkflowFromSource(spec, false)
+	require.NoError(t, err, "workflow should be fetched from the registered provider")
+	assert.Equal(t, "# Triage", string(fetched.Content), "content should come from the provider")
+	assert.Equal(t, "0123456789abcdef0123456789abcdef01234567", fetched.CommitSHA, "ref should be resolved by the provider")
+}
+
+func TestGiteaSourceProvider(t *testing.T) {
+	var authHeaders, userAgents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+		userAgents = append(userAgents, r.Header.Get("User-Agent"))
+		switch r.URL.Path {
+		case "/api/v1/repos/owner/repo/raw/.github/workflows/triage.md":
+			if r.URL.Query().Get("ref") != "v1" {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write([]byte("# Triage"))
+		case "/api/v1/repos/owner/repo/contents/.github/workflows":
+			_, _ = w.Write([]byte(`[{"name":"triage.md","path":".github/workflows/triage.md","type":"file"},{"name":"shared","path":".github/workflows/shared","type":"dir"},{"name":"ci.yml","path":".github/workflows/ci.yml","type":"file"}]`))
+		case "/api/v1/repos/owner/repo/commits":
+			if r.URL.Query().Get("sha") != "v1" {
+				_, _ = w.Write([]byte(`[]`))
+				return
+			}
+			_, _ = w.Write([]byte(`[{"sha":"fedcba9876543210fedcba9876543210fedcba98"}]`))
+		case "/api/v1/repos/owner/private/raw/a.md":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv(giteaTokenEnvVar, "secret")
+	provider := newGiteaSourceProvider(server.URL, map[string]string{"User-Agent": "corp-fetcher/1.0"})
+
+	content, err := provider.DownloadFile("owner", "repo", ".github/workflows/triage.md", "v1", 0)
+	require.NoError(t, err, "file should be downloaded")
+	assert.Equal(t, "# Triage", string(content), "raw content should be returned")
+	assert.Equal(t, "token secret", authHeaders[0], "token should be sent")
+	assert.Equal(t, "corp-fetcher/1.0", userAgents[0], "configured User-Agent should be sent")
+
+	_, err = provider.DownloadFile("owner", "repo", ".github/workflows/triage.md", "v1", 4)
+	require.ErrorIs(t, err, parser.ErrFileTooLarge, "files over the requested limit should be refused")
+
+	_, err = provider.DownloadFile("owner", "repo", "missing.md", "v1", 0)
+	var notFound *parser.NotFoundError
+	require.ErrorAs(t, err, &notFound, "missing files should be reported as not found")
+
+	_, err = provider.DownloadFile("owner", "private", "a.md", "main", 0)
+	var authErr *parser.AuthError
+	require.ErrorAs(t, err, &authErr, "rejected credentials should be reported as auth errors")
+
+	files, err := provider.ListDir("owner", "repo", "v1", ".github/workflows")
+	require.NoError(t, err, "directory should be listed")
+	assert.Equal(t, []string{".github/workflows/triage.md"}, files, "only .md files should be listed")
+
+	sha, err := provider.ResolveRef("owner", "repo", "v1")
+	require.NoError(t, err, "ref should be resolved")
+	assert.Equal(t, "fedcba9876543210fedcba9876543210fedcba98", sha, "commit SHA should be returned")
+
+	_, err = provider.ResolveRef("owner", "repo", "missing")
+	require.ErrorAs(t, err, &notFound, "unknown refs should be reported as not found")
+
+	fullSHA := "0123456789abcdef0123456789abcdef01234567"
+	sha, err = provider.ResolveRef("owner", "repo", fullSHA)
+	require.NoError(t, err, "full SHAs should not need a request")
+	assert.Equal(t, fullSHA, sha, "full SHA should be returned as-is")
+}
+
+// registerGiteaHost configures host as a Gitea source host served by a test server with the
+// files of owner/repo, keyed by "path@ref", and returns the paths of the requests it received
+func registerGiteaHost(t *testing.T, host string, commits map[string]string, files map[string]string) *[]string {
+	t.Helper()
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path+"?"+r.URL.RawQuery)
+		ref := r.URL.Query().Get("ref")
+		switch {
+		case r.URL.Path == "/api/v1/repos/owner/repo/commits":
+			sha, ok := commits[r.URL.Query().Get("sha")]
+			if !ok {
+				_, _ = w.Write([]byte(`[]`))
+				return
+			}
+			_, _ = w.Write([]byte(`[{"sha":"` + sha + `"}]`))
+		case strings.HasPrefix(r.URL.Path, "/api/v1/repos/owner/repo/raw/"):
+			content, ok := files[strings.TrimPrefix(r.URL.Path, "/api/v1/repos/owner/repo/raw/")+"@"+ref]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write([]byte(content))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	setSourceHost(t, host)
+	RegisterSourceProvider(host, newGiteaSourceProvider(server.URL, nil))
+	t.Cleanup(func() { sourceProviders.Delete(host) })
+	return &requests
+}
+
+func TestGiteaSourceProviderFrozen(t *testing.T) {
+	lockedSHA := strings.Repeat("a", 40)
+	requests := registerGiteaHost(t, "gitea-frozen.test",
+		map[string]string{"main": strings.Repeat("b", 40)},
+		map[string]string{
+			".github/workflows/triage.md@" + lockedSHA: "# Locked",
+			".github/workflows/triage.md@main":         "# Latest",
+		})
+	fetcher, err := parser.NewFetcher(parser.FetchConfig{FrozenRefs: map[string]string{"owner/repo@main": lockedSHA}})
+	require.NoError(t, err, "fetcher should be created")
+	opts := remoteFetchOptions{Fetcher: fetcher}
+
+	spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "main"}, WorkflowPath: ".github/workflows/triage.md"}
+	fetched, err := fetchWorkflowFromSource(spec, opts)
+	require.NoError(t, err, "locked workflow should be fetched")
+	assert.Equal(t, "# Locked", string(fetched.Content), "workflow should be downloaded at the locked commit")
+	assert.Equal(t, lockedSHA, fetched.CommitSHA, "ref should resolve to the locked commit")
+	assert.Equal(t, []string{"/api/v1/repos/owner/repo/raw/.github/workflows/triage.md?ref=" + lockedSHA}, *requests, "the floating ref should not be resolved by the forge")
+
+	unlocked := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "develop"}, WorkflowPath: ".github/workflows/triage.md"}
+	_, err = fetchWorkflowFromSource(unlocked, opts)
+	require.ErrorIs(t, err, parser.ErrRefNotLocked, "refs missing from the lock should not be fetched from the forge")
+	assert.Len(t, *requests, 1, "unlocked refs should not reach the forge")
+}
+
+func TestGiteaSourceProviderOffline(t *testing.T) {
+	requests := registerGiteaHost(t, "gitea-offline.test", nil, map[string]string{
+		".github/workflows/triage.md@main": "# Remote",
+	})
+	spec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "owner/repo", Version: "main"}, WorkflowPath: ".github/workflows/triage.md"}
+
+	offline, err := parser.NewFetcher(parser.FetchConfig{Offline: true})
+	require.NoError(t, err, "fetcher should be created")
+	_, err = fetchWorkflowFromSource(spec, remoteFetchOptions{Fetcher: offline})
+	require.ErrorIs(t, err, parser.ErrRepoNotMirrored, "repositories without a mirror should not be fetched offline")
+
+	mirrorDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(mirrorDir, ".github", "workflows"), 0o755), "mirror should be created")
+	require.NoError(t, os.WriteFile(filepath.Join(mirrorDir, ".github", "workflows", "triage.md"), []byte("# Mirrored"), 0o644), "workflow should be written")
+	mirrored, err := parser.NewFetcher(parser.FetchConfig{RepoMirrors: map[string]string{"owner/repo": mirrorDir}, Offline: true})
+	require.NoError(t, err, "fetcher should be created")
+	fetched, err := fetchWorkflowFromSource(spec, remoteFetchOptions{Fetcher: mirrored})
+	require.NoError(t, err, "mirrored workflow should be read")
+	assert.Equal(t, "# Mirrored", string(fetched.Content), "workflow should be read from the mirror")
+
+	assert.Empty(t, *requests, "offline fetches should never reach the forge")
+}
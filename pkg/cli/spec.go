@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/github/gh-aw/pkg/logger"
@@ -31,15 +33,17 @@ type WorkflowSpec struct {
 	RepoSpec            // embedded RepoSpec for Repo and Version fields
 	WorkflowPath string // e.g., "workflows/workflow-name.md"
 	WorkflowName string // e.g., "workflow-name"
-	IsWildcard   bool   // true if this is a wildcard spec (e.g., "owner/repo/*")
+	IsWildcard   bool   // true if this is a wildcard spec (e.g., "owner/repo/*") or a local directory of workflows
+	Section      string // optional section reference including the leading "#" (e.g., "#section-name")
 }
 
-// isLocalWorkflowPath checks if a path refers to a local filesystem workflow.
+// IsLocalWorkflowPath reports whether path refers to a local filesystem workflow rather than
+// a remote workflow spec. FetchWorkflowFromSource reads specs with a local path from disk.
 // Local paths include:
 //   - Relative paths starting with "./", "../", ".\", or "..\", or equal to "." / ".."
 //   - Absolute paths as determined by filepath.IsAbs (OS-specific)
 //   - UNC-style paths starting with "\\" or "//" (Windows network paths)
-func isLocalWorkflowPath(path string) bool {
+func IsLocalWorkflowPath(path string) bool {
 	// Explicit relative path checks (POSIX and Windows-style)
 	if path == "." || path == ".." {
 		return true
@@ -61,16 +65,37 @@ func isLocalWorkflowPath(path string) bool {
 	return false
 }
 
+// IsWorkflowSpecFormat reports whether an include or import path is a workflowspec
+// (e.g. "owner/repo/path/file.md@v1") rather than a path relative to the including file.
+// The "@" version separator is the only indicator: "owner/repo/file.md" without a version
+// is treated as a relative path, and so is "shared/mcp/arxiv.md".
+func IsWorkflowSpecFormat(path string) bool {
+	return strings.Contains(path, "@")
+}
+
+// GetParentDir returns the part of a slash-separated path before its last "/",
+// or "" when the path has no directory (e.g. "a/b/c.md" → "a/b", "c.md" → "")
+func GetParentDir(path string) string {
+	idx := strings.LastIndex(path, "/")
+	if idx == -1 {
+		return ""
+	}
+	return path[:idx]
+}
+
 // String returns the canonical string representation of the workflow spec
 // in the format "owner/repo/path[@version]" or just the WorkflowPath for local specs
 func (w *WorkflowSpec) String() string {
 	// For local workflows, return just the WorkflowPath
-	if isLocalWorkflowPath(w.WorkflowPath) {
+	if IsLocalWorkflowPath(w.WorkflowPath) {
 		return w.WorkflowPath
 	}
 
 	// For remote workflows, use the standard format
-	spec := w.RepoSlug + "/" + w.WorkflowPath
+	spec := w.RepoSlug
+	if w.WorkflowPath != "" {
+		spec += "/" + w.WorkflowPath
+	}
 	if w.Version != "" {
 		spec += "@" + w.Version
 	}
@@ -85,7 +110,7 @@ func isRepoOnlySpec(spec string) bool {
 	}
 
 	// Local paths are not repo-only specs
-	if isLocalWorkflowPath(spec) {
+	if IsLocalWorkflowPath(spec) {
 		return false
 	}
 
@@ -171,6 +196,10 @@ func parseRepoSpec(repoSpec string) (*RepoSpec, error) {
 //   - https://raw.githubusercontent.com/owner/repo/refs/heads/branch/path/to/workflow.md
 //   - https://raw.githubusercontent.com/owner/repo/COMMIT_SHA/path/to/workflow.md
 //   - https://raw.githubusercontent.com/owner/repo/refs/tags/tag/path/to/workflow.md
+//
+// Repository URLs (https://github.com/owner/repo) and tree URLs of a whole ref
+// (https://github.com/owner/repo/tree/branch) are parsed like the owner/repo[@version]
+// shorthand, leaving WorkflowPath empty. Permalinks pin the spec to their commit SHA.
 func parseGitHubURL(spec string) (*WorkflowSpec, error) {
 	specLog.Printf("Parsing GitHub URL: %s", spec)
 	// First validate that this is a GitHub URL (github.com or raw.githubusercontent.com)
@@ -183,19 +212,41 @@ func parseGitHubURL(spec string) (*WorkflowSpec, error) {
 	// Must be a GitHub URL
 	if parsedURL.Host != "github.com" && parsedURL.Host != "raw.githubusercontent.com" {
 		specLog.Printf("Invalid host: %s", parsedURL.Host)
-		return nil, errors.New("URL must be from github.com or raw.githubusercontent.com")
+		return nil, fmt.Errorf("unsupported URL host %q: URL must be from github.com or raw.githubusercontent.com", parsedURL.Host)
+	}
+
+	pathParts := strings.Split(strings.Trim(parsedURL.Path, "/"), "/")
+	if parsedURL.Host == "github.com" && (len(pathParts) == 2 || (len(pathParts) == 4 && pathParts[2] == "tree")) {
+		owner, repo := pathParts[0], strings.TrimSuffix(pathParts[1], ".git")
+		if !parser.IsValidGitHubIdentifier(owner) || !parser.IsValidGitHubIdentifier(repo) {
+			return nil, fmt.Errorf("invalid GitHub URL: '%s/%s' does not look like a valid GitHub repository", owner, repo)
+		}
+		var version string
+		if len(pathParts) == 4 {
+			version = pathParts[3]
+		}
+		specLog.Printf("Parsed GitHub repository URL: repo=%s/%s, ref=%s", owner, repo, version)
+		return &WorkflowSpec{
+			RepoSpec: RepoSpec{
+				RepoSlug: fmt.Sprintf("%s/%s", owner, repo),
+				Version:  version,
+			},
+		}, nil
 	}
 
 	owner, repo, ref, filePath, err := parser.ParseRepoFileURL(spec)
 	if err != nil {
 		specLog.Printf("Failed to parse repo file URL: %v", err)
-		return nil, err
+		return nil, fmt.Errorf("unsupported GitHub URL %s: expected /blob/, /tree/, or /raw/ followed by a ref and a file path: %w", spec, err)
 	}
 
 	specLog.Printf("Parsed GitHub URL: owner=%s, repo=%s, ref=%s, path=%s", owner, repo, ref, filePath)
 
 	// Ensure the file path ends with .md
 	if !strings.HasSuffix(filePath, ".md") {
+		if parsedURL.Host == "github.com" && pathParts[2] == "tree" {
+			return nil, fmt.Errorf("GitHub URL points to the directory %s: link to a workflow file (https://github.com/%s/%s/blob/%s/<path>.md) or to the repository instead", filePath, owner, repo, ref)
+		}
 		return nil, errors.New("GitHub URL must point to a .md file")
 	}
 
@@ -214,21 +265,73 @@ func parseGitHubURL(spec string) (*WorkflowSpec, error) {
 	}, nil
 }
 
+// lineAnchorPattern matches the line anchors GitHub appends to file links, e.g. #L10 or #L10-L20
+var lineAnchorPattern = regexp.MustCompile(`^#L\d+(C\d+)?(-L\d+(C\d+)?)?$`)
+
+// isURLSpec reports whether spec is a URL rather than a workflowspec or local path
+func isURLSpec(spec string) bool {
+	return strings.HasPrefix(spec, "http://") || strings.HasPrefix(spec, "https://")
+}
+
+// ParseWorkflowSpec parses a user-supplied workflow specification into a WorkflowSpec.
+// It accepts everything parseWorkflowSpec does (remote specs, GitHub URLs, and local paths)
+// plus an optional trailing section reference, e.g. "owner/repo/path/file.md@ref#section".
+// The section is stored in Section with its leading "#" and is stripped before the
+// remaining spec is parsed and validated.
+func ParseWorkflowSpec(spec string) (*WorkflowSpec, error) {
+	specLog.Printf("Parsing public workflow spec: %q", spec)
+
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, errors.New("workflow specification cannot be empty")
+	}
+
+	var section string
+	if idx := strings.Index(spec, "#"); idx != -1 {
+		section = spec[idx:]
+		spec = spec[:idx]
+		if isURLSpec(spec) && lineAnchorPattern.MatchString(section) {
+			// Line anchors of copied GitHub links (#L10, #L10-L20) select lines, not a section
+			section = ""
+		} else if section == "#" {
+			return nil, errors.New("invalid workflow specification: section name after '#' cannot be empty")
+		}
+		if spec == "" {
+			return nil, fmt.Errorf("invalid workflow specification: missing workflow path before section %q", section)
+		}
+	}
+
+	if strings.HasSuffix(spec, "@") {
+		return nil, fmt.Errorf("invalid workflow specification: version after '@' cannot be empty: %s", spec)
+	}
+
+	ws, err := parseWorkflowSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	ws.Section = section
+
+	specLog.Printf("Parsed public workflow spec: repo=%s, path=%s, version=%s, section=%s",
+		ws.RepoSlug, ws.WorkflowPath, ws.Version, ws.Section)
+	return ws, nil
+}
+
 // parseWorkflowSpec parses a workflow specification in the new format
 // Format: owner/repo/workflows/workflow-name[@version] or owner/repo/workflow-name[@version]
+// Also supports the repo-only shorthand owner/repo[@version], which leaves WorkflowPath empty
 // Also supports full GitHub URLs like https://github.com/owner/repo/blob/branch/path/to/workflow.md
 // Also supports local paths like ./workflows/workflow-name.md
 func parseWorkflowSpec(spec string) (*WorkflowSpec, error) {
 	specLog.Printf("Parsing workflow spec: %q", spec)
 
 	// Check if this is a GitHub URL
-	if strings.HasPrefix(spec, "http://") || strings.HasPrefix(spec, "https://") {
+	if isURLSpec(spec) {
 		specLog.Print("Detected GitHub URL format")
 		return parseGitHubURL(spec)
 	}
 
 	// Check if this is a local path
-	if isLocalWorkflowPath(spec) {
+	if IsLocalWorkflowPath(spec) {
 		specLog.Print("Detected local path format")
 
 		ws, err := parseLocalWorkflowSpec(spec)
@@ -260,8 +363,8 @@ func parseWorkflowSpec(spec string) (*WorkflowSpec, error) {
 	// Split by slashes
 	slashParts := strings.Split(specWithoutVersion, "/")
 
-	// Must have at least 3 parts: owner/repo/workflow-path
-	if len(slashParts) < 3 {
+	// Must have at least 2 parts: owner/repo[/workflow-path]
+	if len(slashParts) < 2 {
 		return nil, errors.New("workflow specification must be in format 'owner/repo/workflow-name[@version]'")
 	}
 
@@ -296,6 +399,18 @@ func parseWorkflowSpec(spec string) (*WorkflowSpec, error) {
 		return nil, fmt.Errorf("invalid workflow specification: '%s/%s' does not look like a valid GitHub repository", owner, repo)
 	}
 
+	// Repo-only shorthand (owner/repo[@version]): the workflow path is left empty and
+	// resolved when fetching by probing the repository's conventional workflow locations
+	if len(slashParts) == 2 {
+		specLog.Printf("Detected repo-only spec: %s/%s", owner, repo)
+		return &WorkflowSpec{
+			RepoSpec: RepoSpec{
+				RepoSlug: fmt.Sprintf("%s/%s", owner, repo),
+				Version:  version,
+			},
+		}, nil
+	}
+
 	// Check if this is a wildcard specification (owner/repo/*)
 	if workflowPath == "*" {
 		return &WorkflowSpec{
@@ -335,10 +450,20 @@ func parseWorkflowSpec(spec string) (*WorkflowSpec, error) {
 // parseLocalWorkflowSpec parses a local workflow specification starting with "./"
 func parseLocalWorkflowSpec(spec string) (*WorkflowSpec, error) {
 	specLog.Printf("Parsing local workflow spec: %s", spec)
+	// A directory stands for the agentic workflows inside it and is expanded like a wildcard
+	if info, err := os.Stat(spec); err == nil && info.IsDir() {
+		specLog.Printf("Parsed local workflow directory: path=%s", spec)
+		return &WorkflowSpec{
+			WorkflowPath: spec,
+			WorkflowName: filepath.Base(spec),
+			IsWildcard:   true,
+		}, nil
+	}
+
 	// Validate that it's a .md file
 	if !strings.HasSuffix(spec, ".md") {
 		specLog.Printf("Invalid extension for local workflow: %s", spec)
-		return nil, fmt.Errorf("local workflow specification must end with '.md' extension: %s", spec)
+		return nil, fmt.Errorf("local workflow specification must end with '.md' extension or name a directory: %s", spec)
 	}
 
 	specLog.Printf("Parsed local workflow: path=%s", spec)
@@ -3,6 +3,7 @@
 package cli
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -45,6 +46,42 @@ func TestParseGitHubURL(t *testing.T) {
 			wantVersion:      "v2.0.0",
 			wantErr:          false,
 		},
+		{
+			name:        "repository URL",
+			url:         "https://github.com/owner/repo",
+			wantRepo:    "owner/repo",
+			wantVersion: "",
+			wantErr:     false,
+		},
+		{
+			name:        "repository clone URL",
+			url:         "https://github.com/owner/repo.git",
+			wantRepo:    "owner/repo",
+			wantVersion: "",
+			wantErr:     false,
+		},
+		{
+			name:        "tree URL of a whole ref",
+			url:         "https://github.com/owner/repo/tree/v1.2.0",
+			wantRepo:    "owner/repo",
+			wantVersion: "v1.2.0",
+			wantErr:     false,
+		},
+		{
+			name:             "permalink with query and commit SHA",
+			url:              "https://github.com/owner/repo/blob/fc7992627494253a869e177e5d1985d25f3bb316/.github/workflows/triage.md?plain=1",
+			wantRepo:         "owner/repo",
+			wantWorkflowPath: ".github/workflows/triage.md",
+			wantWorkflowName: "triage",
+			wantVersion:      "fc7992627494253a869e177e5d1985d25f3bb316",
+			wantErr:          false,
+		},
+		{
+			name:        "invalid - tree URL of a directory",
+			url:         "https://github.com/owner/repo/tree/main/workflows",
+			wantErr:     true,
+			errContains: "points to the directory workflows",
+		},
 		{
 			name:        "invalid - non-github domain",
 			url:         "https://gitlab.com/owner/repo/blob/main/workflows/test.md",
@@ -55,7 +92,7 @@ func TestParseGitHubURL(t *testing.T) {
 			name:        "invalid - path too short",
 			url:         "https://github.com/owner/repo/blob/main",
 			wantErr:     true,
-			errContains: "path too short",
+			errContains: "followed by a ref and a file path",
 		},
 		{
 			name:        "invalid - wrong URL type",
@@ -107,6 +144,9 @@ func TestParseGitHubURL(t *testing.T) {
 					t.Errorf("parseGitHubURL() expected error containing %q, got nil", tt.errContains)
 					return
 				}
+				if !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("parseGitHubURL() error = %v, want error containing %q", err, tt.errContains)
+				}
 				return
 			}
 
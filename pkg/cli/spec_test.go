@@ -196,7 +196,7 @@ func TestParseWorkflowSpec(t *testing.T) {
 			name:        "GitHub URL - invalid path (too short)",
 			spec:        "https://github.com/owner/repo/blob/main",
 			wantErr:     true,
-			errContains: "path too short",
+			errContains: "followed by a ref and a file path",
 		},
 		{
 			name:        "GitHub URL - invalid type",
@@ -240,9 +240,18 @@ func TestParseWorkflowSpec(t *testing.T) {
 			wantVersion:      "main",
 			wantErr:          false,
 		},
+		{
+			name:             "repo-only shorthand with version",
+			spec:             "owner/repo@v1.0.0",
+			wantRepo:         "owner/repo",
+			wantWorkflowPath: "",
+			wantWorkflowName: "",
+			wantVersion:      "v1.0.0",
+			wantErr:          false,
+		},
 		{
 			name:        "invalid - too few parts",
-			spec:        "owner/repo@v1.0.0",
+			spec:        "owner@v1.0.0",
 			wantErr:     true,
 			errContains: "must be in format",
 		},
@@ -332,6 +341,121 @@ func TestParseWorkflowSpec(t *testing.T) {
 	}
 }
 
+func TestParseWorkflowSpecPublic(t *testing.T) {
+	tests := []struct {
+		name             string
+		spec             string
+		wantRepo         string
+		wantWorkflowPath string
+		wantVersion      string
+		wantSection      string
+		wantErr          bool
+		errContains      string
+	}{
+		{
+			name:             "remote spec with ref and section",
+			spec:             "owner/repo/shared/tools.md@v1.2.0#setup",
+			wantRepo:         "owner/repo",
+			wantWorkflowPath: "shared/tools.md",
+			wantVersion:      "v1.2.0",
+			wantSection:      "#setup",
+		},
+		{
+			name:             "remote spec without section",
+			spec:             "owner/repo/workflows/ci-doctor.md@main",
+			wantRepo:         "owner/repo",
+			wantWorkflowPath: "workflows/ci-doctor.md",
+			wantVersion:      "main",
+		},
+		{
+			name:             "local path with section",
+			spec:             "./workflows/local.md#Overview",
+			wantWorkflowPath: "./workflows/local.md",
+			wantSection:      "#Overview",
+		},
+		{
+			name:             "GitHub URL with line anchor",
+			spec:             "https://github.com/owner/repo/blob/main/workflows/triage.md#L10-L20",
+			wantRepo:         "owner/repo",
+			wantWorkflowPath: "workflows/triage.md",
+			wantVersion:      "main",
+		},
+		{
+			name:             "GitHub URL with heading anchor",
+			spec:             "https://github.com/owner/repo/blob/main/shared/tools.md#setup",
+			wantRepo:         "owner/repo",
+			wantWorkflowPath: "shared/tools.md",
+			wantVersion:      "main",
+			wantSection:      "#setup",
+		},
+		{
+			name:        "empty spec",
+			spec:        "   ",
+			wantErr:     true,
+			errContains: "cannot be empty",
+		},
+		{
+			name:        "empty section",
+			spec:        "owner/repo/file.md#",
+			wantErr:     true,
+			errContains: "section name",
+		},
+		{
+			name:        "section only",
+			spec:        "#setup",
+			wantErr:     true,
+			errContains: "missing workflow path",
+		},
+		{
+			name:        "empty version",
+			spec:        "owner/repo/file.md@",
+			wantErr:     true,
+			errContains: "version after '@'",
+		},
+		{
+			name:        "invalid slug",
+			spec:        "own er/repo/file.md",
+			wantErr:     true,
+			errContains: "does not look like a valid GitHub repository",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := ParseWorkflowSpec(tt.spec)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseWorkflowSpec() expected error, got nil")
+					return
+				}
+				if !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("ParseWorkflowSpec() error = %v, want error containing %q", err, tt.errContains)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("ParseWorkflowSpec() unexpected error: %v", err)
+				return
+			}
+
+			if spec.RepoSlug != tt.wantRepo {
+				t.Errorf("ParseWorkflowSpec() repo = %q, want %q", spec.RepoSlug, tt.wantRepo)
+			}
+			if spec.WorkflowPath != tt.wantWorkflowPath {
+				t.Errorf("ParseWorkflowSpec() workflowPath = %q, want %q", spec.WorkflowPath, tt.wantWorkflowPath)
+			}
+			if spec.Version != tt.wantVersion {
+				t.Errorf("ParseWorkflowSpec() version = %q, want %q", spec.Version, tt.wantVersion)
+			}
+			if spec.Section != tt.wantSection {
+				t.Errorf("ParseWorkflowSpec() section = %q, want %q", spec.Section, tt.wantSection)
+			}
+		})
+	}
+}
+
 func TestParseLocalWorkflowSpec(t *testing.T) {
 	// Clear the repository slug cache to ensure clean test state
 	ClearCurrentRepoSlugCache()
@@ -473,6 +597,16 @@ func TestWorkflowSpecString(t *testing.T) {
 			},
 			expected: "./test.md",
 		},
+		{
+			name: "repo-only spec",
+			spec: &WorkflowSpec{
+				RepoSpec: RepoSpec{
+					RepoSlug: "owner/repo",
+					Version:  "v1.0.0",
+				},
+			},
+			expected: "owner/repo@v1.0.0",
+		},
 	}
 
 	for _, tt := range tests {
@@ -807,3 +941,32 @@ func TestIsCommitSHA(t *testing.T) {
 		})
 	}
 }
+
+func TestIsLocalWorkflowPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{path: ".", expected: true},
+		{path: "..", expected: true},
+		{path: "./workflow.md", expected: true},
+		{path: "../shared/workflow.md", expected: true},
+		{path: `.\workflow.md`, expected: true},
+		{path: `..\workflow.md`, expected: true},
+		{path: "/abs/workflow.md", expected: true},
+		{path: "//server/share/workflow.md", expected: true},
+		{path: `\\server\share\workflow.md`, expected: true},
+		{path: "workflow.md", expected: false},
+		{path: "owner/repo/workflow.md", expected: false},
+		{path: "owner/repo/workflows/ci.md@v1", expected: false},
+		{path: ".github/workflows/ci.md", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := IsLocalWorkflowPath(tt.path); got != tt.expected {
+				t.Errorf("IsLocalWorkflowPath(%q) = %v, want %v", tt.path, got, tt.expected)
+			}
+		})
+	}
+}
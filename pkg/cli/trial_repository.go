@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -212,7 +213,7 @@ func installWorkflowInTrialMode(ctx context.Context, tempDir string, parsedSpec
 	specToFetch := parsedSpec
 	var fetched *FetchedWorkflow
 
-	if isLocalWorkflowPath(parsedSpec.WorkflowPath) {
+	if IsLocalWorkflowPath(parsedSpec.WorkflowPath) {
 		// For local workflows, temporarily change to original dir for fetch
 		// Use a closure to ensure directory is restored even on error
 		fetched, err = func() (*FetchedWorkflow, error) {
@@ -256,6 +257,15 @@ func installWorkflowInTrialMode(ctx context.Context, tempDir string, parsedSpec
 		}
 	}
 
+	// Include each file of an included directory individually, since compilation reads files
+	if !fetched.IsLocal {
+		if expanded, err := expandDirectoryIncludeDirectives(string(content), parsedSpec, false, remoteFetchOptions{LogLevel: fetchLogLevel(opts.Verbose, false)}); err == nil {
+			content = []byte(expanded)
+		} else if opts.Verbose {
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to expand include directories: %v", err)))
+		}
+	}
+
 	// Use common helper for security scan, directory creation, and writing
 	result, err := writeWorkflowToTrialDir(tempDir, parsedSpec.WorkflowName, content, opts)
 	if err != nil {
@@ -272,7 +282,10 @@ func installWorkflowInTrialMode(ctx context.Context, tempDir string, parsedSpec
 
 	// Fetch and save include dependencies for remote workflows
 	if !fetched.IsLocal {
-		if err := fetchAndSaveRemoteIncludes(string(content), parsedSpec, result.WorkflowsDir, opts.Verbose, true, nil); err != nil {
+		if _, err := fetchAndSaveRemoteIncludes(string(content), parsedSpec, result.WorkflowsDir, remoteFetchOptions{LogLevel: fetchLogLevel(opts.Verbose, false), Force: true}); err != nil {
+			if errors.Is(err, ErrIncludeBudgetExceeded) {
+				return err
+			}
 			if opts.Verbose {
 				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to fetch include dependencies: %v", err)))
 			}
@@ -1001,3 +1001,35 @@ func TestRunUpdateWorkflows_SpecificWorkflowNotFound(t *testing.T) {
 	require.Error(t, err, "Should error when specified workflow not found")
 	assert.Contains(t, err.Error(), "no workflows found matching the specified names")
 }
+
+// TestGetRepoDefaultBranch_Memoized tests that default branches are resolved once per host and repository
+func TestGetRepoDefaultBranch_Memoized(t *testing.T) {
+	original := lookupRepoDefaultBranch
+	t.Cleanup(func() {
+		lookupRepoDefaultBranch = original
+		defaultBranchCache.Clear()
+	})
+	defaultBranchCache.Clear()
+
+	lookups := 0
+	lookupRepoDefaultBranch = func(repo string) (string, error) {
+		lookups++
+		return "main", nil
+	}
+
+	for _, envVar := range []string{"GITHUB_SERVER_URL", "GITHUB_ENTERPRISE_HOST", "GITHUB_HOST"} {
+		t.Setenv(envVar, "")
+	}
+	t.Setenv("GH_HOST", "github.com")
+	for range 3 {
+		branch, err := getRepoDefaultBranch("owner/repo")
+		require.NoError(t, err, "default branch should resolve")
+		assert.Equal(t, "main", branch, "should return the looked-up branch")
+	}
+	assert.Equal(t, 1, lookups, "repeated lookups for the same repository should be memoized")
+
+	t.Setenv("GH_HOST", "myorg.ghe.com")
+	_, err := getRepoDefaultBranch("owner/repo")
+	require.NoError(t, err, "default branch should resolve on another host")
+	assert.Equal(t, 2, lookups, "the same repository on another host should be looked up separately")
+}
@@ -0,0 +1,290 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aymanbagabas/go-udiff"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/stringutil"
+)
+
+var updateDiffLog = logger.New("cli:update_diff")
+
+// FileDiff compares an installed file with its upstream source. Both diffs are unified diffs
+// and are empty when there is no difference.
+type FileDiff struct {
+	Path            string // Local path of the installed file (empty for an include only the target ref references)
+	Source          string // Upstream location, e.g. "owner/repo/workflows/triage.md@v1"
+	LocalDrift      string // Local edits: the upstream file at the installed ref → the local file
+	UpstreamChanges string // Upstream changes: the upstream file at the installed ref → at the target ref
+}
+
+// HasLocalDrift reports whether the installed file was edited locally
+func (d FileDiff) HasLocalDrift() bool {
+	return d.LocalDrift != ""
+}
+
+// HasUpstreamChanges reports whether the upstream file changed between the installed and target refs
+func (d FileDiff) HasUpstreamChanges() bool {
+	return d.UpstreamChanges != ""
+}
+
+// WorkflowDiff compares an installed workflow, and the include files it references, with its
+// upstream source
+type WorkflowDiff struct {
+	BaseRef   string     // Ref the workflow was installed from (its source field)
+	TargetRef string     // Ref the workflow is compared with
+	Workflow  FileDiff   // The workflow file itself
+	Includes  []FileDiff // @include files of the workflow, in reference order
+}
+
+// DiffWorkflowAgainstUpstream compares the installed workflow at workflowPath with its upstream
+// source at targetRef (the installed ref when empty), without changing any file.
+//
+// Local drift and upstream changes are reported separately, both relative to the upstream file
+// at the installed ref: local drift is what was edited locally since installing, and upstream
+// changes are what changed upstream since then. The installed form of the workflow is the
+// upstream content with its source field set; relative includes may also have been rewritten to
+// workflowspecs by update, so the closer of the two forms is used. The stop-after field and
+// whitespace differences are ignored.
+//
+// The @include directives of the upstream workflow are compared the same way, for include files
+// recorded in the fetch lock. Includes only referenced at one of the refs appear as added or
+// removed in the upstream changes.
+func DiffWorkflowAgainstUpstream(workflowPath, targetRef string, verbose bool) (*WorkflowDiff, error) {
+	updateDiffLog.Printf("Diffing workflow against upstream: path=%s, target_ref=%s", workflowPath, targetRef)
+
+	localContent, err := os.ReadFile(workflowPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow: %w", err)
+	}
+	result, err := parser.ExtractFrontmatterFromContent(string(localContent))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter of %s: %w", workflowPath, err)
+	}
+	sourceField, _ := result.Frontmatter["source"].(string)
+	if sourceField == "" {
+		return nil, fmt.Errorf("workflow %s has no source field", workflowPath)
+	}
+	sourceSpec, err := parseSourceSpec(sourceField)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source field in %s: %w", workflowPath, err)
+	}
+
+	baseRef := sourceSpec.Ref
+	if baseRef == "" {
+		baseRef = "main"
+	}
+	if targetRef == "" {
+		targetRef = baseRef
+	}
+	baseSpec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: sourceSpec.Repo, Version: baseRef}, WorkflowPath: sourceSpec.Path}
+	targetSpec := &WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: sourceSpec.Repo, Version: targetRef}, WorkflowPath: sourceSpec.Path}
+
+	base, err := FetchWorkflowFromSource(baseSpec, verbose)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch installed version: %w", err)
+	}
+	target := base
+	if targetRef != baseRef {
+		if target, err = FetchWorkflowFromSource(targetSpec, verbose); err != nil {
+			return nil, fmt.Errorf("failed to fetch target version: %w", err)
+		}
+	}
+
+	baseSource := fmt.Sprintf("%s/%s@%s", sourceSpec.Repo, base.SourcePath, baseRef)
+	diff := &WorkflowDiff{
+		BaseRef:   baseRef,
+		TargetRef: targetRef,
+		Workflow: FileDiff{
+			Path:            workflowPath,
+			Source:          baseSource,
+			LocalDrift:      workflowLocalDrift(string(base.Content), string(localContent), sourceField, baseSpec, workflowPath, verbose),
+			UpstreamChanges: unifiedDiff(baseSource, fmt.Sprintf("%s/%s@%s", sourceSpec.Repo, target.SourcePath, targetRef), string(base.Content), string(target.Content)),
+		},
+	}
+
+	diff.Includes, err = diffWorkflowIncludes(string(base.Content), string(target.Content), baseSpec, targetSpec, workflowPath, verbose)
+	if err != nil {
+		return nil, err
+	}
+
+	updateDiffLog.Printf("Diffed %s: local_drift=%v, upstream_changes=%v, includes=%d",
+		workflowPath, diff.Workflow.HasLocalDrift(), diff.Workflow.HasUpstreamChanges(), len(diff.Includes))
+	return diff, nil
+}
+
+// workflowLocalDrift diffs the installed form of the upstream workflow with the local file,
+// using whichever installed form (as written by add, or by update) is closer to the local file
+func workflowLocalDrift(upstream, local, sourceField string, spec *WorkflowSpec, workflowPath string, verbose bool) string {
+	local = normalizeForDiff(local)
+
+	installed, err := UpdateFieldInFrontmatter(upstream, "source", sourceField)
+	if err != nil {
+		updateDiffLog.Printf("Failed to set source field on upstream content: %v", err)
+		installed = upstream
+	}
+	drift := unifiedDiff(sourceField, workflowPath, normalizeForDiff(installed), local)
+	if drift == "" {
+		return ""
+	}
+
+	if updated, err := processIncludesInContent(installed, spec, spec.Version, verbose); err == nil {
+		if updatedDrift := unifiedDiff(sourceField, workflowPath, normalizeForDiff(updated), local); len(updatedDrift) < len(drift) {
+			return updatedDrift
+		}
+	}
+	return drift
+}
+
+// diffWorkflowIncludes compares the @include files referenced by the upstream workflow at the
+// installed or the target ref. Local copies are looked up in the fetch lock by source.
+func diffWorkflowIncludes(baseContent, targetContent string, baseSpec, targetSpec *WorkflowSpec, workflowPath string, verbose bool) ([]FileDiff, error) {
+	localPaths := make(map[string]string)
+	if gitRoot, err := findGitRoot(); err == nil {
+		lock, err := loadFetchLock(gitRoot)
+		if err != nil {
+			return nil, err
+		}
+		for relPath, entry := range lock.Files {
+			localPaths[entry.Source] = filepath.Join(gitRoot, filepath.FromSlash(relPath))
+		}
+	}
+
+	baseIncludes := collectRemoteIncludes(baseContent)
+	targetIncludes := make(map[string]*remoteInclude)
+	for _, include := range collectRemoteIncludes(targetContent) {
+		targetIncludes[include.filePath] = include
+	}
+
+	var diffs []FileDiff
+	seen := make(map[string]bool)
+	for _, include := range baseIncludes {
+		seen[include.filePath] = true
+		source := includeSourceString(include.includePath, baseSpec)
+		localPath, installed := localPaths[source]
+		if !installed {
+			updateDiffLog.Printf("Include %s is not recorded in the fetch lock, skipping", source)
+			continue
+		}
+
+		baseText, err := fetchIncludeForDiff(include, baseSpec, verbose)
+		if err != nil {
+			return nil, err
+		}
+		targetText := ""
+		if targetInclude, ok := targetIncludes[include.filePath]; ok {
+			if targetText, err = fetchIncludeForDiff(targetInclude, targetSpec, verbose); err != nil {
+				return nil, err
+			}
+		}
+
+		fileDiff := FileDiff{Path: localPath, Source: source}
+		if localText, err := os.ReadFile(localPath); err == nil {
+			fileDiff.LocalDrift = unifiedDiff(source, localPath, normalizeForDiff(baseText), normalizeForDiff(string(localText)))
+		} else {
+			fileDiff.LocalDrift = unifiedDiff(source, localPath, normalizeForDiff(baseText), "")
+		}
+		fileDiff.UpstreamChanges = unifiedDiff(source, includeSourceString(include.includePath, targetSpec), baseText, targetText)
+		diffs = append(diffs, fileDiff)
+	}
+
+	// Includes that only the target ref references are new upstream
+	for _, include := range collectRemoteIncludes(targetContent) {
+		if seen[include.filePath] {
+			continue
+		}
+		targetText, err := fetchIncludeForDiff(include, targetSpec, verbose)
+		if err != nil {
+			return nil, err
+		}
+		source := includeSourceString(include.includePath, targetSpec)
+		diffs = append(diffs, FileDiff{Source: source, UpstreamChanges: unifiedDiff(source, source, "", targetText)})
+	}
+
+	updateDiffLog.Printf("Compared %d includes of %s", len(diffs), workflowPath)
+	return diffs, nil
+}
+
+// fetchIncludeForDiff fetches an include the way add saves it, scoped to its referenced
+// sections. Optional includes that do not exist yield empty content.
+func fetchIncludeForDiff(include *remoteInclude, spec *WorkflowSpec, verbose bool) (string, error) {
+	result, err := fetchIncludeFromSource(include.includePath, spec, remoteFetchOptions{LogLevel: fetchLogLevel(verbose, false)})
+	if err != nil {
+		var notFound *parser.NotFoundError
+		if include.optional || errors.As(err, &notFound) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to fetch include %s: %w", include.includePath, err)
+	}
+	if include.wholeFile {
+		return string(result.Content), nil
+	}
+	return extractIncludeSections(string(result.Content), include.sections)
+}
+
+// normalizeForDiff removes differences that do not matter when comparing an installed file
+// with its source: whitespace and the deployment-specific stop-after field
+func normalizeForDiff(content string) string {
+	normalized := stringutil.NormalizeWhitespace(content)
+	if withoutStopAfter, err := RemoveFieldFromOnTrigger(normalized, "stop-after"); err == nil {
+		normalized = withoutStopAfter
+	}
+	return normalized
+}
+
+// unifiedDiff returns a unified diff from oldText to newText, or "" when they are equal
+func unifiedDiff(oldLabel, newLabel, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+	return udiff.Unified(oldLabel, newLabel, oldText, newText)
+}
+
+// diffStat summarizes the lines changed from oldText to newText, e.g. "+3 -1 lines",
+// or returns "" when they are equal
+func diffStat(oldText, newText string) string {
+	diff := unifiedDiff("old", "new", oldText, newText)
+	if diff == "" {
+		return ""
+	}
+	added, removed := 0, 0
+	for line := range strings.SplitSeq(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+"):
+			added++
+		case strings.HasPrefix(line, "-"):
+			removed++
+		}
+	}
+	return fmt.Sprintf("+%d -%d lines", added, removed)
+}
+
+// FormatWorkflowDiff renders a WorkflowDiff for display, listing local drift and upstream
+// changes separately for the workflow and each include
+func FormatWorkflowDiff(diff *WorkflowDiff) string {
+	var b strings.Builder
+	for _, file := range append([]FileDiff{diff.Workflow}, diff.Includes...) {
+		name := file.Path
+		if name == "" {
+			name = file.Source + " (new upstream)"
+		}
+		if !file.HasLocalDrift() && !file.HasUpstreamChanges() {
+			fmt.Fprintf(&b, "%s: unchanged\n", name)
+			continue
+		}
+		if file.HasLocalDrift() {
+			fmt.Fprintf(&b, "%s: local changes since %s\n%s", name, diff.BaseRef, file.LocalDrift)
+		}
+		if file.HasUpstreamChanges() {
+			fmt.Fprintf(&b, "%s: upstream changes %s → %s\n%s", name, diff.BaseRef, diff.TargetRef, file.UpstreamChanges)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,89 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffWorkflowAgainstUpstream(t *testing.T) {
+	upstream := map[string]string{
+		"v1": "---\non: push\n---\n\n# Triage\n\nLabel new issues.\n\n@include shared/tools.md\n",
+		"v2": "---\non: push\n---\n\n# Triage\n\nLabel and assign new issues.\n\n@include shared/tools.md\n@include shared/style.md\n",
+	}
+	originalDownload, originalResolve := downloadWorkflowFile, resolveRemoteWorkflowSHA
+	t.Cleanup(func() { downloadWorkflowFile, resolveRemoteWorkflowSHA = originalDownload, originalResolve })
+	downloadWorkflowFile = func(_ *parser.Fetcher, owner, repo, path, ref string, maxSize int64) ([]byte, error) {
+		return []byte(upstream[ref]), nil
+	}
+	resolveRemoteWorkflowSHA = func(_ *parser.Fetcher, owner, repo, ref string) (string, error) {
+		return strings.Repeat("a", 40), nil
+	}
+	stubIncludeFiles(t, map[string]string{
+		"owner/repo/.github/shared/tools.md@v1": "Use the GitHub tools.\n",
+		"owner/repo/.github/shared/tools.md@v2": "Use the GitHub tools sparingly.\n",
+		"owner/repo/.github/shared/style.md@v2": "Be concise.\n",
+	})
+
+	gitRoot := t.TempDir()
+	require.NoError(t, exec.Command("git", "-C", gitRoot, "init", "-q").Run(), "git repository should be created")
+	t.Chdir(gitRoot)
+
+	workflowsDir := filepath.Join(gitRoot, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(workflowsDir, 0o755), "workflows directory should be created")
+	require.NoError(t, os.MkdirAll(filepath.Join(gitRoot, ".github", "shared"), 0o755), "shared directory should be created")
+	workflowPath := filepath.Join(workflowsDir, "triage.md")
+	localWorkflow := "---\non: push\nsource: owner/repo/workflows/triage.md@v1\n---\n\n# Triage\n\nLabel new issues in our repo.\n\n@include shared/tools.md\n"
+	require.NoError(t, os.WriteFile(workflowPath, []byte(localWorkflow), 0o644), "workflow should be written")
+	toolsPath := filepath.Join(gitRoot, ".github", "shared", "tools.md")
+	require.NoError(t, os.WriteFile(toolsPath, []byte("Use the GitHub tools.\n"), 0o644), "include should be written")
+
+	lock := &FetchLock{Files: map[string]FetchLockEntry{
+		".github/shared/tools.md": {Source: "owner/repo/shared/tools.md@v1"},
+	}}
+	require.NoError(t, lock.save(gitRoot, nil), "fetch lock should be written")
+
+	t.Run("same ref reports only local drift", func(t *testing.T) {
+		diff, err := DiffWorkflowAgainstUpstream(workflowPath, "", false)
+		require.NoError(t, err, "diff should succeed")
+		assert.Equal(t, "v1", diff.TargetRef, "target ref should default to the installed ref")
+		assert.False(t, diff.Workflow.HasUpstreamChanges(), "upstream should not change against itself")
+		require.True(t, diff.Workflow.HasLocalDrift(), "local edit should be reported")
+		assert.Contains(t, diff.Workflow.LocalDrift, "+Label new issues in our repo.", "local edit should be an added line")
+		assert.NotContains(t, diff.Workflow.LocalDrift, "source:", "the source field should not count as drift")
+
+		require.Len(t, diff.Includes, 1, "installed include should be compared")
+		assert.Equal(t, toolsPath, diff.Includes[0].Path, "include should be found through the fetch lock")
+		assert.False(t, diff.Includes[0].HasLocalDrift(), "unedited include should have no drift")
+	})
+
+	t.Run("newer ref reports upstream changes separately", func(t *testing.T) {
+		diff, err := DiffWorkflowAgainstUpstream(workflowPath, "v2", false)
+		require.NoError(t, err, "diff should succeed")
+		assert.Contains(t, diff.Workflow.UpstreamChanges, "+Label and assign new issues.", "upstream edit should be reported")
+		assert.NotContains(t, diff.Workflow.UpstreamChanges, "in our repo", "local edits should not appear as upstream changes")
+		assert.Contains(t, diff.Workflow.LocalDrift, "+Label new issues in our repo.", "local drift should stay relative to the installed ref")
+
+		require.Len(t, diff.Includes, 2, "changed and new includes should be compared")
+		assert.Contains(t, diff.Includes[0].UpstreamChanges, "+Use the GitHub tools sparingly.", "include change should be reported")
+		assert.Empty(t, diff.Includes[1].Path, "new include should not be installed")
+		assert.Contains(t, diff.Includes[1].UpstreamChanges, "+Be concise.", "new include should be an addition")
+
+		assert.Contains(t, FormatWorkflowDiff(diff), "local changes since v1", "formatted diff should label local changes")
+	})
+
+	t.Run("workflow without source", func(t *testing.T) {
+		path := filepath.Join(workflowsDir, "local.md")
+		require.NoError(t, os.WriteFile(path, []byte("---\non: push\n---\n\n# Local\n"), 0o644), "workflow should be written")
+		_, err := DiffWorkflowAgainstUpstream(path, "", false)
+		require.Error(t, err, "workflows without a source cannot be diffed")
+	})
+}
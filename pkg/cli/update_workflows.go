@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/parser"
@@ -223,8 +224,36 @@ func resolveLatestCommitFromDefaultBranch(repo, currentSHA string, verbose bool)
 	return latestSHA, nil
 }
 
-// getRepoDefaultBranch fetches the default branch name for a repository.
+// defaultBranchCache memoizes default branch lookups for the lifetime of the process, keyed
+// by GitHub host and repository so that same-named repositories on different hosts never
+// share an entry.
+var defaultBranchCache sync.Map
+
+// lookupRepoDefaultBranch queries the default branch of a repository.
+// It is a variable so tests can substitute the GitHub API.
+var lookupRepoDefaultBranch = fetchRepoDefaultBranch
+
+// getRepoDefaultBranch returns the default branch name for a repository, resolving it at most
+// once per host and repository.
 func getRepoDefaultBranch(repo string) (string, error) {
+	key := parser.GetGitHubHost() + "/" + repo
+	if cached, ok := defaultBranchCache.Load(key); ok {
+		updateLog.Printf("Using memoized default branch for %s", key)
+		return cached.(string), nil
+	}
+
+	branch, err := lookupRepoDefaultBranch(repo)
+	if err != nil {
+		// Failures are not memoized so that transient errors can be retried
+		return "", err
+	}
+
+	defaultBranchCache.Store(key, branch)
+	return branch, nil
+}
+
+// fetchRepoDefaultBranch fetches the default branch name for a repository.
+func fetchRepoDefaultBranch(repo string) (string, error) {
 	output, err := workflow.RunGH("Fetching repo info...", "api", "/repos/"+repo, "--jq", ".default_branch")
 	if err != nil {
 		return "", err
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var validateLog = logger.New("cli:validate_command")
+
+// NewValidateCommand creates the validate command
+func NewValidateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate [workflow]... --safe-outputs",
+		Short: "Quickly check parts of agentic workflows without compiling them",
+		Long: `Check parts of agentic workflows without running a full compilation.
+
+With --safe-outputs, only the safe-outputs section of each workflow is checked: unknown keys,
+max limits, labels, mentions, allowed domains, targets, and dispatch-workflow names. Imports
+are not resolved, and whether dispatch-workflow targets exist is only checked by 'compile'.
+
+If no workflows are specified, all Markdown files in .github/workflows will be checked.
+
+` + WorkflowIDExplanation + `
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` validate --safe-outputs             # Check safe-outputs of all workflows
+  ` + string(constants.CLIExtensionPrefix) + ` validate --safe-outputs ci-doctor   # Check safe-outputs of a specific workflow`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			dir, _ := cmd.Flags().GetString("dir")
+			return RunValidateSafeOutputs(args, dir, verbose)
+		},
+	}
+
+	cmd.Flags().Bool("safe-outputs", false, "Check the safe-outputs section of each workflow")
+	cmd.Flags().StringP("dir", "d", "", "Workflow directory (default: .github/workflows)")
+	_ = cmd.MarkFlagRequired("safe-outputs")
+
+	// Register completions
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+	RegisterDirFlagCompletion(cmd, "dir")
+
+	return cmd
+}
+
+// RunValidateSafeOutputs checks the safe-outputs section of the given workflows, or of every
+// workflow in workflowDir when none are given, and reports each problem found
+func RunValidateSafeOutputs(workflowIDs []string, workflowDir string, verbose bool) error {
+	validateLog.Printf("Validating safe-outputs: workflowIDs=%v, workflowDir=%s", workflowIDs, workflowDir)
+
+	if workflowDir == "" {
+		workflowDir = getWorkflowsDir()
+	} else {
+		workflowDir = filepath.Clean(workflowDir)
+	}
+
+	var files []string
+	if len(workflowIDs) > 0 {
+		for _, workflowID := range workflowIDs {
+			file, err := resolveWorkflowFileInDir(workflowID, verbose, workflowDir)
+			if err != nil {
+				return err
+			}
+			files = append(files, file)
+		}
+	} else {
+		var err error
+		files, err = getMarkdownWorkflowFiles(workflowDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(files) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("No workflow files found."))
+		return nil
+	}
+
+	compiler := workflow.NewCompiler()
+	var failed int
+	for _, file := range files {
+		errs, err := validateWorkflowSafeOutputs(compiler, file)
+		if err != nil {
+			errs = []error{err}
+		}
+		if len(errs) == 0 {
+			if verbose {
+				fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(file+": safe-outputs are valid"))
+			}
+			continue
+		}
+		failed++
+		for _, validationErr := range errs {
+			fmt.Fprintln(os.Stderr, console.FormatErrorMessage(fmt.Sprintf("%s: %v", file, validationErr)))
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("safe-outputs validation failed for %d of %d workflow(s)", failed, len(files))
+	}
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Safe-outputs of %d workflow(s) are valid", len(files))))
+	return nil
+}
+
+// validateWorkflowSafeOutputs parses the frontmatter of a workflow file and checks its
+// safe-outputs section, returning the problems found
+func validateWorkflowSafeOutputs(compiler *workflow.Compiler, file string) ([]error, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow file: %w", err)
+	}
+	result, err := parser.ExtractFrontmatterFromContent(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+
+	safeOutputs, exists := result.Frontmatter["safe-outputs"]
+	if !exists {
+		validateLog.Printf("%s has no safe-outputs section", file)
+		return nil, nil
+	}
+	if _, ok := safeOutputs.(map[string]any); !ok {
+		return nil, errors.New("safe-outputs must be a mapping")
+	}
+
+	return workflow.ValidateSafeOutputs(compiler.ExtractSafeOutputsConfig(result.Frontmatter)), nil
+}
@@ -0,0 +1,38 @@
+//go:build !integration
+
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunValidateSafeOutputs(t *testing.T) {
+	workflowsDir := filepath.Join(t.TempDir(), ".github", "workflows")
+	writeTestFile(t, filepath.Join(workflowsDir, "valid.md"), "---\non: push\nsafe-outputs:\n  create-issue:\n    max: 2\n---\n# Valid\n")
+	writeTestFile(t, filepath.Join(workflowsDir, "none.md"), "---\non: push\n---\n# No safe outputs\n")
+	writeTestFile(t, filepath.Join(workflowsDir, "invalid.md"), "---\non: push\nsafe-outputs:\n  create-isue:\n  add-comment:\n    max: -5\n---\n# Invalid\n")
+
+	compiler := workflow.NewCompiler()
+	errs, err := validateWorkflowSafeOutputs(compiler, filepath.Join(workflowsDir, "valid.md"))
+	require.NoError(t, err, "valid workflow should parse")
+	assert.Empty(t, errs, "valid safe-outputs should have no problems")
+
+	errs, err = validateWorkflowSafeOutputs(compiler, filepath.Join(workflowsDir, "none.md"))
+	require.NoError(t, err, "workflow without safe-outputs should parse")
+	assert.Empty(t, errs, "workflow without safe-outputs should have no problems")
+
+	errs, err = validateWorkflowSafeOutputs(compiler, filepath.Join(workflowsDir, "invalid.md"))
+	require.NoError(t, err, "invalid workflow should parse")
+	assert.Len(t, errs, 2, "unknown key and negative max should be reported")
+
+	err = RunValidateSafeOutputs([]string{"valid"}, workflowsDir, false)
+	require.NoError(t, err, "valid workflow should pass")
+	err = RunValidateSafeOutputs(nil, workflowsDir, false)
+	require.Error(t, err, "invalid workflow should fail validation")
+	assert.Contains(t, err.Error(), "failed for 1 of 3 workflow(s)", "error should count failing workflows")
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var workflowOutdatedLog = logger.New("cli:workflow_outdated")
+
+// WorkflowUpdateCheck is the result of checking an installed workflow against its upstream branch
+type WorkflowUpdateCheck struct {
+	Branch     string // Branch the workflow tracks
+	CurrentSHA string // Commit recorded when the workflow was installed
+	LatestSHA  string // Current head of the tracked branch
+	Outdated   bool   // true when a commit after CurrentSHA changed the workflow file
+}
+
+// Status returns "up-to-date" or "outdated"
+func (c *WorkflowUpdateCheck) Status() string {
+	if c.Outdated {
+		return "outdated"
+	}
+	return "up-to-date"
+}
+
+// resolveBranchHeadSHA resolves a branch to its head commit; replaceable in tests
+var resolveBranchHeadSHA = parser.ResolveRefToSHA
+
+// workflowPathChanged reports whether any commit in base..head modified path; replaceable in tests
+var workflowPathChanged = workflowPathChangedViaCompare
+
+// CheckWorkflowUpdate reports whether the upstream branch tracked by spec has new commits
+// that touch the workflow file since recordedSHA, the commit the workflow was installed from.
+// Workflows pinned to a tag or commit SHA (or without a ref) track the repository's default branch.
+func CheckWorkflowUpdate(spec *WorkflowSpec, recordedSHA string) (*WorkflowUpdateCheck, error) {
+	workflowOutdatedLog.Printf("Checking for updates: spec=%s, recorded=%s", spec.String(), recordedSHA)
+
+	if recordedSHA == "" {
+		return nil, errors.New("no recorded commit SHA for workflow")
+	}
+	owner, repo, ok := strings.Cut(spec.RepoSlug, "/")
+	if !ok || owner == "" || repo == "" {
+		return nil, fmt.Errorf("invalid repository slug: %s", spec.RepoSlug)
+	}
+	if spec.WorkflowPath == "" {
+		return nil, errors.New("workflow path is required to check for updates")
+	}
+
+	branch := spec.Version
+	if branch == "" || !isBranchRef(branch) {
+		defaultBranch, err := getRepoDefaultBranch(spec.RepoSlug)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get default branch for %s: %w", spec.RepoSlug, err)
+		}
+		branch = defaultBranch
+	}
+
+	headSHA, err := resolveBranchHeadSHA(owner, repo, branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s@%s: %w", spec.RepoSlug, branch, err)
+	}
+
+	check := &WorkflowUpdateCheck{
+		Branch:     branch,
+		CurrentSHA: recordedSHA,
+		LatestSHA:  headSHA,
+	}
+	if headSHA == recordedSHA {
+		workflowOutdatedLog.Printf("%s is at recorded commit %s", branch, shortRef(headSHA))
+		return check, nil
+	}
+
+	// The branch moved; only commits that touch the workflow file make it outdated
+	changed, err := workflowPathChanged(spec.RepoSlug, recordedSHA, headSHA, spec.WorkflowPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare %s...%s in %s: %w", shortRef(recordedSHA), shortRef(headSHA), spec.RepoSlug, err)
+	}
+	check.Outdated = changed
+	workflowOutdatedLog.Printf("%s moved %s → %s, workflow changed=%v", branch, shortRef(recordedSHA), shortRef(headSHA), changed)
+	return check, nil
+}
+
+// workflowPathChangedViaCompare uses the compare API to check whether path was modified between base and head
+func workflowPathChangedViaCompare(repo, base, head, path string) (bool, error) {
+	jq := fmt.Sprintf(`[.files[]?.filename] | any(. == %q)`, path)
+	output, err := workflow.RunGH("Comparing commits...", "api", fmt.Sprintf("/repos/%s/compare/%s...%s", repo, base, head), "--jq", jq)
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(string(output)) == "true", nil
+}
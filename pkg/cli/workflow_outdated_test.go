@@ -0,0 +1,100 @@
+//go:build !integration
+
+package cli
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckWorkflowUpdate(t *testing.T) {
+	const recordedSHA = "1111111111111111111111111111111111111111"
+	const headSHA = "2222222222222222222222222222222222222222"
+
+	tests := []struct {
+		name        string
+		head        string
+		pathChanged bool
+		compareErr  error
+		outdated    bool
+		status      string
+		wantErr     string
+	}{
+		{
+			name:   "branch still at recorded commit",
+			head:   recordedSHA,
+			status: "up-to-date",
+		},
+		{
+			name:        "branch moved without touching the workflow",
+			head:        headSHA,
+			pathChanged: false,
+			status:      "up-to-date",
+		},
+		{
+			name:        "branch moved and changed the workflow",
+			head:        headSHA,
+			pathChanged: true,
+			outdated:    true,
+			status:      "outdated",
+		},
+		{
+			name:       "compare failure",
+			head:       headSHA,
+			compareErr: errors.New("HTTP 404"),
+			wantErr:    "failed to compare 1111111...2222222",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			originalResolve, originalChanged := resolveBranchHeadSHA, workflowPathChanged
+			defer func() { resolveBranchHeadSHA, workflowPathChanged = originalResolve, originalChanged }()
+
+			resolveBranchHeadSHA = func(owner, repo, ref string) (string, error) {
+				assert.Equal(t, "owner", owner, "owner should come from the spec")
+				assert.Equal(t, "repo", repo, "repo should come from the spec")
+				assert.Equal(t, "main", ref, "tracked branch should be resolved")
+				return tt.head, nil
+			}
+			workflowPathChanged = func(repo, base, head, path string) (bool, error) {
+				assert.Equal(t, "workflows/ci-doctor.md", path, "compare should check the workflow path")
+				return tt.pathChanged, tt.compareErr
+			}
+
+			spec := &WorkflowSpec{
+				RepoSpec:     RepoSpec{RepoSlug: "owner/repo", Version: "main"},
+				WorkflowPath: "workflows/ci-doctor.md",
+			}
+			check, err := CheckWorkflowUpdate(spec, recordedSHA)
+			if tt.wantErr != "" {
+				require.Error(t, err, "should fail")
+				assert.Contains(t, err.Error(), tt.wantErr, "error message mismatch")
+				return
+			}
+
+			require.NoError(t, err, "check should succeed")
+			assert.Equal(t, "main", check.Branch, "branch should be reported")
+			assert.Equal(t, recordedSHA, check.CurrentSHA, "current SHA should be the recorded commit")
+			assert.Equal(t, tt.head, check.LatestSHA, "latest SHA should be the branch head")
+			assert.Equal(t, tt.outdated, check.Outdated, "outdated mismatch")
+			assert.Equal(t, tt.status, check.Status(), "status mismatch")
+		})
+	}
+}
+
+func TestCheckWorkflowUpdate_InvalidInput(t *testing.T) {
+	spec := &WorkflowSpec{
+		RepoSpec:     RepoSpec{RepoSlug: "owner/repo", Version: "main"},
+		WorkflowPath: "workflows/ci-doctor.md",
+	}
+	_, err := CheckWorkflowUpdate(spec, "")
+	require.Error(t, err, "missing recorded SHA should fail")
+
+	_, err = CheckWorkflowUpdate(&WorkflowSpec{RepoSpec: RepoSpec{RepoSlug: "invalid"}, WorkflowPath: "a.md"}, "abc")
+	require.Error(t, err, "invalid slug should fail")
+	assert.Contains(t, err.Error(), "invalid repository slug", "error should name the problem")
+}
@@ -0,0 +1,254 @@
+package cli
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var workflowRefsReportLog = logger.New("cli:workflow_refs_report")
+
+// resolveWorkflowRefSHA resolves a repository ref to its commit SHA; replaceable in tests
+var resolveWorkflowRefSHA = parser.ResolveRefToSHA
+
+// WorkflowRefUsage records one place a workflow pins a remote file
+type WorkflowRefUsage struct {
+	Workflow string `json:"workflow"` // Workflow file, relative to the scanned directory
+	Kind     string `json:"kind"`     // "source", "import", or "include"
+	Spec     string `json:"spec"`     // Spec as written, e.g. "owner/repo/shared/tools.md@v1"
+}
+
+// PinnedRef groups the usages of a remote file at a single ref
+type PinnedRef struct {
+	Ref    string             `json:"ref"`
+	SHA    string             `json:"sha,omitempty"` // Resolved commit SHA; empty when not resolved
+	Usages []WorkflowRefUsage `json:"usages"`
+}
+
+// SharedFileRefs lists every ref a remote file is pinned at across the scanned workflows
+type SharedFileRefs struct {
+	Repo     string      `json:"repo"`
+	Path     string      `json:"path"`
+	Refs     []PinnedRef `json:"refs"`
+	Conflict bool        `json:"conflict"` // true when the file is pinned at refs that resolve to different commits
+}
+
+// WorkflowRefsReport summarizes the remote refs pinned by the workflows in a directory
+type WorkflowRefsReport struct {
+	Files     []SharedFileRefs `json:"files"`
+	Conflicts int              `json:"conflicts"`
+}
+
+// GenerateWorkflowRefsReport scans the workflows in workflowsDir (including subdirectories) for
+// remote specs in the source field, the imports field, and include/import directives, and groups
+// them by file and ref. When resolveSHAs is set, each ref is resolved to a commit SHA with
+// parser.ResolveRefToSHA so pins that name the same commit are not reported as conflicts.
+func GenerateWorkflowRefsReport(workflowsDir string, resolveSHAs, verbose bool) (*WorkflowRefsReport, error) {
+	workflowRefsReportLog.Printf("Generating workflow refs report: dir=%s, resolveSHAs=%v", workflowsDir, resolveSHAs)
+
+	var usages []WorkflowRefUsage
+	err := filepath.WalkDir(workflowsDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".md") {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			if verbose {
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to read %s: %v", path, err)))
+			}
+			return nil
+		}
+
+		relPath, err := filepath.Rel(workflowsDir, path)
+		if err != nil {
+			relPath = path
+		}
+		usages = append(usages, extractWorkflowRefUsages(filepath.ToSlash(relPath), string(content))...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan workflows directory: %w", err)
+	}
+	workflowRefsReportLog.Printf("Found %d pinned remote specs", len(usages))
+
+	report := buildWorkflowRefsReport(usages)
+
+	if resolveSHAs {
+		resolveWorkflowRefsReportSHAs(report, verbose)
+	}
+
+	for i := range report.Files {
+		report.Files[i].Conflict = hasConflictingRefs(report.Files[i].Refs)
+		if report.Files[i].Conflict {
+			report.Conflicts++
+		}
+	}
+
+	workflowRefsReportLog.Printf("Report generated: %d files, %d conflicts", len(report.Files), report.Conflicts)
+	return report, nil
+}
+
+// extractWorkflowRefUsages returns the pinned remote specs in a workflow's source field,
+// imports field, and include/import directives
+func extractWorkflowRefUsages(workflow, content string) []WorkflowRefUsage {
+	var usages []WorkflowRefUsage
+	add := func(kind, spec string) {
+		spec = strings.TrimSpace(spec)
+		if IsWorkflowSpecFormat(spec) {
+			usages = append(usages, WorkflowRefUsage{Workflow: workflow, Kind: kind, Spec: spec})
+		}
+	}
+
+	result, err := parser.ExtractFrontmatterFromContent(content)
+	if err != nil {
+		workflowRefsReportLog.Printf("Failed to parse frontmatter in %s: %v", workflow, err)
+		return nil
+	}
+
+	if source, ok := result.Frontmatter["source"].(string); ok {
+		add("source", source)
+	}
+
+	forEachWorkflowImportSpec(result, add)
+
+	return usages
+}
+
+// buildWorkflowRefsReport groups usages by repository file and ref, sorted for stable output
+func buildWorkflowRefsReport(usages []WorkflowRefUsage) *WorkflowRefsReport {
+	type fileKey struct{ repo, path string }
+	files := make(map[fileKey]map[string][]WorkflowRefUsage)
+
+	for _, usage := range usages {
+		specWithoutSection, _, _ := strings.Cut(usage.Spec, "#")
+		source, err := parseSourceSpec(specWithoutSection)
+		if err != nil || source.Ref == "" {
+			workflowRefsReportLog.Printf("Skipping unparseable spec %q in %s: %v", usage.Spec, usage.Workflow, err)
+			continue
+		}
+		key := fileKey{source.Repo, source.Path}
+		if files[key] == nil {
+			files[key] = make(map[string][]WorkflowRefUsage)
+		}
+		files[key][source.Ref] = append(files[key][source.Ref], usage)
+	}
+
+	report := &WorkflowRefsReport{}
+	for key, refs := range files {
+		file := SharedFileRefs{Repo: key.repo, Path: key.path}
+		for ref, refUsages := range refs {
+			file.Refs = append(file.Refs, PinnedRef{Ref: ref, Usages: refUsages})
+		}
+		slices.SortFunc(file.Refs, func(a, b PinnedRef) int { return cmp.Compare(a.Ref, b.Ref) })
+		report.Files = append(report.Files, file)
+	}
+	slices.SortFunc(report.Files, func(a, b SharedFileRefs) int {
+		return cmp.Or(cmp.Compare(a.Repo, b.Repo), cmp.Compare(a.Path, b.Path))
+	})
+	return report
+}
+
+// resolveWorkflowRefsReportSHAs fills in the commit SHA of every pinned ref, resolving each
+// repository ref once. Refs that fail to resolve keep an empty SHA.
+func resolveWorkflowRefsReportSHAs(report *WorkflowRefsReport, verbose bool) {
+	resolved := make(map[string]string)
+	for i := range report.Files {
+		file := &report.Files[i]
+		owner, repo, _ := strings.Cut(file.Repo, "/")
+		for j := range file.Refs {
+			pinned := &file.Refs[j]
+			cacheKey := file.Repo + "@" + pinned.Ref
+			sha, ok := resolved[cacheKey]
+			if !ok {
+				var err error
+				sha, err = resolveWorkflowRefSHA(owner, repo, pinned.Ref)
+				if err != nil {
+					workflowRefsReportLog.Printf("Failed to resolve %s: %v", cacheKey, err)
+					if verbose {
+						fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Could not resolve %s: %v", cacheKey, err)))
+					}
+					sha = ""
+				}
+				resolved[cacheKey] = sha
+			}
+			pinned.SHA = sha
+		}
+	}
+}
+
+// hasConflictingRefs reports whether a file is pinned at more than one commit. Refs that
+// resolved to the same SHA agree; unresolved refs are compared by name.
+func hasConflictingRefs(refs []PinnedRef) bool {
+	commits := make(map[string]bool)
+	for _, pinned := range refs {
+		commits[cmp.Or(pinned.SHA, pinned.Ref)] = true
+	}
+	return len(commits) > 1
+}
+
+// DisplayWorkflowRefsReport shows the remote refs pinned by workflows, conflicts first
+func DisplayWorkflowRefsReport(report *WorkflowRefsReport) {
+	if len(report.Files) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("No workflows pin remote files"))
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Remote refs: %d %s, %d with conflicting pins",
+		len(report.Files), pluralize("file", len(report.Files)), report.Conflicts)))
+	fmt.Fprintln(os.Stderr, "")
+
+	files := slices.Clone(report.Files)
+	slices.SortStableFunc(files, func(a, b SharedFileRefs) int {
+		if a.Conflict == b.Conflict {
+			return 0
+		}
+		if a.Conflict {
+			return -1
+		}
+		return 1
+	})
+
+	for _, file := range files {
+		header := fmt.Sprintf("%s/%s", file.Repo, file.Path)
+		if file.Conflict {
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(header+" is pinned at different commits"))
+		} else {
+			fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(header))
+		}
+		for _, pinned := range file.Refs {
+			ref := pinned.Ref
+			if pinned.SHA != "" && pinned.SHA != pinned.Ref {
+				ref = fmt.Sprintf("%s (%s)", pinned.Ref, shortRef(pinned.SHA))
+			}
+			workflows := make([]string, 0, len(pinned.Usages))
+			for _, usage := range pinned.Usages {
+				workflows = append(workflows, fmt.Sprintf("%s [%s]", usage.Workflow, usage.Kind))
+			}
+			fmt.Fprintf(os.Stderr, "  @%s: %s\n", ref, strings.Join(workflows, ", "))
+		}
+	}
+}
+
+// DisplayWorkflowRefsReportJSON outputs the workflow refs report in JSON format
+func DisplayWorkflowRefsReportJSON(report *WorkflowRefsReport) error {
+	jsonData, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	fmt.Println(string(jsonData))
+	return nil
+}
@@ -0,0 +1,108 @@
+//go:build !integration
+
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractWorkflowRefUsages(t *testing.T) {
+	content := `---
+on: issues
+source: acme/agents/workflows/triage.md@v1.2.0
+imports:
+  - shared/local.md
+  - acme/shared/tools.md@v1
+  - path: acme/shared/inputs.md@main
+    inputs:
+      count: 3
+---
+
+# Triage
+
+{{#import acme/shared/tips.md@v2#Usage}}
+@include? acme/shared/optional.md@main
+{{#import shared/local-tips.md}}
+`
+
+	usages := extractWorkflowRefUsages("triage.md", content)
+	assert.Equal(t, []WorkflowRefUsage{
+		{Workflow: "triage.md", Kind: "source", Spec: "acme/agents/workflows/triage.md@v1.2.0"},
+		{Workflow: "triage.md", Kind: "import", Spec: "acme/shared/tools.md@v1"},
+		{Workflow: "triage.md", Kind: "import", Spec: "acme/shared/inputs.md@main"},
+		{Workflow: "triage.md", Kind: "include", Spec: "acme/shared/tips.md@v2#Usage"},
+		{Workflow: "triage.md", Kind: "include", Spec: "acme/shared/optional.md@main"},
+	}, usages, "only remote specs with a ref should be extracted")
+}
+
+func TestGenerateWorkflowRefsReport(t *testing.T) {
+	const shaV1 = "1111111111111111111111111111111111111111"
+	const shaMain = "2222222222222222222222222222222222222222"
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.md":            "---\non: issues\nimports:\n  - acme/shared/tools.md@v1\n  - acme/shared/mcp.md@v1\n---\n# A\n",
+		"b.md":            "---\non: push\nimports:\n  - acme/shared/tools.md@main\n---\n# B\n{{#import acme/shared/mcp.md@" + shaV1 + "}}\n",
+		"shared/local.md": "---\nimports:\n  - acme/shared/tools.md@v1\n---\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755), "should create directory")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644), "should write %s", name)
+	}
+
+	t.Run("without SHA resolution", func(t *testing.T) {
+		report, err := GenerateWorkflowRefsReport(dir, false, false)
+		require.NoError(t, err, "report should be generated")
+		require.Len(t, report.Files, 2, "two shared files should be reported")
+
+		mcp := report.Files[0]
+		assert.Equal(t, "mcp.md", mcp.Path, "files should be sorted by path")
+		assert.True(t, mcp.Conflict, "different ref names should conflict when not resolved")
+
+		tools := report.Files[1]
+		assert.Equal(t, "acme/shared", tools.Repo, "repo should be parsed from the spec")
+		require.Len(t, tools.Refs, 2, "tools.md should be pinned at two refs")
+		assert.Equal(t, "main", tools.Refs[0].Ref, "refs should be sorted")
+		assert.Equal(t, "v1", tools.Refs[1].Ref, "refs should be sorted")
+		assert.Len(t, tools.Refs[1].Usages, 2, "v1 should be used by a.md and the shared file")
+		assert.True(t, tools.Conflict, "main and v1 should conflict")
+		assert.Equal(t, 2, report.Conflicts, "both files should be counted as conflicts")
+	})
+
+	t.Run("with SHA resolution", func(t *testing.T) {
+		original := resolveWorkflowRefSHA
+		defer func() { resolveWorkflowRefSHA = original }()
+
+		calls := 0
+		resolveWorkflowRefSHA = func(owner, repo, ref string) (string, error) {
+			calls++
+			assert.Equal(t, "acme", owner, "owner should come from the spec")
+			assert.Equal(t, "shared", repo, "repo should come from the spec")
+			switch ref {
+			case "v1", shaV1:
+				return shaV1, nil
+			case "main":
+				return shaMain, nil
+			}
+			return "", errors.New("unknown ref")
+		}
+
+		report, err := GenerateWorkflowRefsReport(dir, true, false)
+		require.NoError(t, err, "report should be generated")
+		assert.Equal(t, 3, calls, "each repository ref should be resolved once")
+
+		mcp := report.Files[0]
+		assert.False(t, mcp.Conflict, "v1 and its commit SHA should not conflict")
+		assert.Equal(t, shaV1, mcp.Refs[1].SHA, "resolved SHA should be recorded")
+
+		assert.True(t, report.Files[1].Conflict, "main and v1 resolve to different commits")
+		assert.Equal(t, 1, report.Conflicts, "only tools.md should conflict")
+	})
+}
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var workflowSourcesLog = logger.New("cli:workflow_sources")
+
+// WorkflowSourceRef describes one file a workflow depends on through the imports field or an
+// include/import directive
+type WorkflowSourceRef struct {
+	Kind     string `json:"kind"`              // "import" or "include"
+	Spec     string `json:"spec"`              // Reference as written, e.g. "owner/repo/shared/tools.md@v1"
+	Remote   bool   `json:"remote"`            // true for workflowspecs, false for paths relative to the workflow
+	Repo     string `json:"repo,omitempty"`    // e.g. "owner/repo"; empty for local references
+	Path     string `json:"path"`              // File path within Repo, or the local path
+	Ref      string `json:"ref,omitempty"`     // Version/tag/SHA/branch of a remote reference
+	Section  string `json:"section,omitempty"` // Optional section including the leading "#"
+	Floating bool   `json:"floating"`          // true for remote references whose Ref is not a full commit SHA
+}
+
+// ExtractWorkflowSources reads the workflow at workflowPath and returns the files it references.
+// See ExtractWorkflowSourcesFromContent.
+func ExtractWorkflowSources(workflowPath string) ([]WorkflowSourceRef, error) {
+	content, err := os.ReadFile(workflowPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow %s: %w", workflowPath, err)
+	}
+	return ExtractWorkflowSourcesFromContent(string(content))
+}
+
+// ExtractWorkflowSourcesFromContent returns the files a workflow references in its imports field
+// and include/import directives, in order of first appearance. References to the same file at
+// the same ref are reported once, regardless of section. Only the workflow itself is inspected;
+// nested imports of the referenced files are not followed.
+func ExtractWorkflowSourcesFromContent(content string) ([]WorkflowSourceRef, error) {
+	result, err := parser.ExtractFrontmatterFromContent(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+
+	var sources []WorkflowSourceRef
+	seen := make(map[string]bool)
+	var parseErr error
+	forEachWorkflowImportSpec(result, func(kind, spec string) {
+		if parseErr != nil {
+			return
+		}
+		source, err := parseWorkflowSourceRef(kind, spec)
+		if err != nil {
+			parseErr = err
+			return
+		}
+		key := source.Repo + "/" + source.Path + "@" + source.Ref
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		sources = append(sources, source)
+	})
+	if parseErr != nil {
+		return nil, parseErr
+	}
+
+	workflowSourcesLog.Printf("Extracted %d workflow sources", len(sources))
+	return sources, nil
+}
+
+// forEachWorkflowImportSpec calls fn with every non-empty spec in the imports field ("import")
+// and in include/import directives of the markdown body ("include")
+func forEachWorkflowImportSpec(result *parser.FrontmatterResult, fn func(kind, spec string)) {
+	add := func(kind, spec string) {
+		if spec = strings.TrimSpace(spec); spec != "" {
+			fn(kind, spec)
+		}
+	}
+
+	if imports, ok := result.Frontmatter["imports"].([]any); ok {
+		for _, item := range imports {
+			switch v := item.(type) {
+			case string:
+				add("import", v)
+			case map[string]any:
+				if importPath, ok := v["path"].(string); ok {
+					add("import", importPath)
+				}
+			}
+		}
+	}
+
+	for line := range strings.SplitSeq(result.Markdown, "\n") {
+		if directive := parser.ParseImportDirective(line); directive != nil {
+			add("include", directive.Path)
+		}
+	}
+}
+
+// parseWorkflowSourceRef classifies a single import or include spec
+func parseWorkflowSourceRef(kind, spec string) (WorkflowSourceRef, error) {
+	specWithoutSection, section, hasSection := strings.Cut(spec, "#")
+	ref := WorkflowSourceRef{Kind: kind, Spec: spec}
+	if hasSection {
+		ref.Section = "#" + section
+	}
+
+	if !IsWorkflowSpecFormat(specWithoutSection) {
+		ref.Path = path.Clean(specWithoutSection)
+		return ref, nil
+	}
+
+	source, err := parseSourceSpec(specWithoutSection)
+	if err != nil {
+		return WorkflowSourceRef{}, fmt.Errorf("invalid %s %q: %w", kind, spec, err)
+	}
+	ref.Remote = true
+	ref.Repo = source.Repo
+	ref.Path = source.Path
+	ref.Ref = source.Ref
+	ref.Floating = !IsCommitSHA(source.Ref)
+	return ref, nil
+}
@@ -0,0 +1,56 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractWorkflowSourcesFromContent(t *testing.T) {
+	content := `---
+on: issues
+source: acme/agents/workflows/triage.md@v1.2.0
+imports:
+  - shared/local.md
+  - acme/shared/tools.md@v1
+  - path: acme/shared/inputs.md@0123456789abcdef0123456789abcdef01234567
+    inputs:
+      count: 3
+---
+
+# Triage
+
+{{#import acme/shared/tools.md@v1#Usage}}
+@include? ./shared/notes.md
+@include acme/shared/tools.md@v2
+`
+
+	sources, err := ExtractWorkflowSourcesFromContent(content)
+	require.NoError(t, err, "workflow should parse")
+
+	assert.Equal(t, []WorkflowSourceRef{
+		{Kind: "import", Spec: "shared/local.md", Path: "shared/local.md"},
+		{Kind: "import", Spec: "acme/shared/tools.md@v1", Remote: true, Repo: "acme/shared", Path: "tools.md", Ref: "v1", Floating: true},
+		{Kind: "import", Spec: "acme/shared/inputs.md@0123456789abcdef0123456789abcdef01234567", Remote: true, Repo: "acme/shared", Path: "inputs.md", Ref: "0123456789abcdef0123456789abcdef01234567"},
+		{Kind: "include", Spec: "./shared/notes.md", Path: "shared/notes.md"},
+		{Kind: "include", Spec: "acme/shared/tools.md@v2", Remote: true, Repo: "acme/shared", Path: "tools.md", Ref: "v2", Floating: true},
+	}, sources, "sources should be deduplicated in order of first appearance, without the source field")
+}
+
+func TestExtractWorkflowSources(t *testing.T) {
+	dir := t.TempDir()
+	workflowPath := filepath.Join(dir, "workflow.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte("---\non: push\n---\n\n@include owner/repo@v1\n"), 0644), "should write workflow")
+
+	_, err := ExtractWorkflowSources(workflowPath)
+	require.Error(t, err, "remote include without a file path should be rejected")
+	assert.Contains(t, err.Error(), `invalid include "owner/repo@v1"`, "error should name the reference")
+
+	_, err = ExtractWorkflowSources(filepath.Join(dir, "missing.md"))
+	require.Error(t, err, "missing workflow should be reported")
+}
@@ -323,11 +323,17 @@ const GitHubCopilotMCPDomain = "api.githubcopilot.com"
 // DefaultClaudeCodeVersion is the default version of the Claude Code CLI.
 const DefaultClaudeCodeVersion Version = "2.1.50"
 
+// MinimumClaudeCodeVersion is the oldest Claude Code CLI version a workflow can pin with engine.version.
+const MinimumClaudeCodeVersion Version = "2.0.0"
+
 // DefaultCopilotVersion is the default version of the GitHub Copilot CLI.
 //
 // WARNING: UPGRADING COPILOT CLI REQUIRES A FULL INTEGRATION TEST RUN TO ENSURE COMPATIBILITY.
 const DefaultCopilotVersion Version = "0.0.414"
 
+// MinimumCopilotVersion is the oldest GitHub Copilot CLI version a workflow can pin with engine.version.
+const MinimumCopilotVersion Version = "0.0.354"
+
 // DefaultCopilotDetectionModel is the default model for the Copilot engine when used in the detection job
 // Updated to gpt-5.1-codex-mini after gpt-5-mini deprecation on 2026-01-17
 const DefaultCopilotDetectionModel ModelName = "gpt-5.1-codex-mini"
@@ -392,9 +398,15 @@ const (
 // DefaultCodexVersion is the default version of the OpenAI Codex CLI
 const DefaultCodexVersion Version = "0.104.0"
 
+// MinimumCodexVersion is the oldest OpenAI Codex CLI version a workflow can pin with engine.version
+const MinimumCodexVersion Version = "0.77.0"
+
 // DefaultGeminiVersion is the default version of the Google Gemini CLI
 const DefaultGeminiVersion Version = "0.29.0"
 
+// MinimumGeminiVersion is the oldest Google Gemini CLI version a workflow can pin with engine.version
+const MinimumGeminiVersion Version = "0.20.0"
+
 // DefaultGitHubMCPServerVersion is the default version of the GitHub MCP server Docker image
 const DefaultGitHubMCPServerVersion Version = "v0.31.0"
 
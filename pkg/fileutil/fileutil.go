@@ -102,6 +102,37 @@ func CopyFile(src, dst string) error {
 	return out.Sync()
 }
 
+// WriteFileAtomic writes data to path like os.WriteFile, but through a temporary file in the
+// same directory that is renamed over path once fully written. Readers see either the previous
+// content or the new content, never a partial file, and an interrupted write leaves the
+// previous file intact.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	log.Printf("Writing file atomically: path=%s, size=%d bytes", path, len(data))
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	// Remove the temporary file unless it was renamed into place
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
 // CalculateDirectorySize recursively calculates the total size of files in a directory.
 func CalculateDirectorySize(dirPath string) int64 {
 	log.Printf("Calculating directory size: %s", dirPath)
@@ -3,6 +3,7 @@
 package fileutil
 
 import (
+	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -176,3 +177,28 @@ func TestValidateAbsolutePath_SecurityScenarios(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "workflow.md")
+
+	require.NoError(t, WriteFileAtomic(path, []byte("first"), 0600), "new file should be written")
+	require.NoError(t, WriteFileAtomic(path, []byte("second"), 0600), "existing file should be replaced")
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err, "written file should be readable")
+	assert.Equal(t, "second", string(content), "file should hold the latest content")
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		require.NoError(t, err, "written file should exist")
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "file should have the requested permissions")
+	}
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err, "directory should be readable")
+	assert.Len(t, entries, 1, "no temporary files should be left behind")
+
+	err = WriteFileAtomic(filepath.Join(dir, "missing", "workflow.md"), []byte("x"), 0600)
+	assert.Error(t, err, "writing into a missing directory should fail")
+}
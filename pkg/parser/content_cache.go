@@ -0,0 +1,135 @@
+//go:build !js && !wasm
+
+package parser
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/fileutil"
+	"github.com/github/gh-aw/pkg/gitutil"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var contentCacheLog = logger.New("parser:content_cache")
+
+// DefaultContentCacheDir returns the persistent content cache directory under the user's cache
+// directory, e.g. ~/.cache/gh-aw/content on Linux
+func DefaultContentCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user cache directory: %w", err)
+	}
+	return filepath.Join(dir, "gh-aw", "content"), nil
+}
+
+// diskCachedDownload returns owner/repo/path@ref from the persistent content cache configured
+// with FetchConfig.CacheDir, calling download with the resolved commit SHA on a miss. Files
+// fetched at a commit SHA are then read from the cache without any request, and files fetched
+// at a floating ref only cost the ref resolution while the ref still points at a cached commit.
+// Content is stored by git blob SHA, so unchanged files are stored once, and is verified against
+// that SHA on every read. Without a cache, or when the ref cannot be resolved, download is called
+// with ref unchanged and nothing is cached. Ref resolutions are memoized per repository and ref,
+// so floating refs cost one lookup however many files share them.
+func (f *Fetcher) diskCachedDownload(owner, repo, path, ref string, download func(ref string) ([]byte, error)) ([]byte, error) {
+	dir := f.cacheDir
+	if dir == "" {
+		return download(ref)
+	}
+
+	commit, err := f.resolveRefToSHA(owner, repo, ref)
+	if err != nil {
+		contentCacheLog.Printf("Failed to resolve %s/%s@%s, bypassing content cache: %v", owner, repo, ref, err)
+		return download(ref)
+	}
+
+	host := f.forgeHost
+	if f.forge == nil {
+		host = GetGitHubHostForRepo(owner, repo)
+	}
+	indexPath := contentCacheIndexPath(dir, host, owner, repo, path, commit)
+	if content, ok := readContentCache(dir, indexPath); ok {
+		contentCacheLog.Printf("Using cached content of %s/%s/%s@%s", owner, repo, path, commit)
+		return content, nil
+	}
+
+	content, err := download(commit)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeContentCache(dir, indexPath, content); err != nil {
+		// The cache is an optimization, so a read-only or full disk must not fail the download
+		contentCacheLog.Printf("Failed to cache %s/%s/%s@%s: %v", owner, repo, path, commit, err)
+	}
+	return content, nil
+}
+
+// contentCacheIndexPath returns the index file recording the blob SHA of owner/repo/path at
+// commit on host. The host is part of the key so that a repository name reused on another
+// GitHub instance never reads this one's entries. Keys are hashed so that paths from remote
+// repositories never name cache files.
+func contentCacheIndexPath(dir, host, owner, repo, path, commit string) string {
+	key := fmt.Sprintf("%s/%s/%s/%s@%s", strings.ToLower(host), strings.ToLower(owner), strings.ToLower(repo), path, strings.ToLower(commit))
+	sum := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(sum[:])
+	return filepath.Join(dir, "index", name[:2], name)
+}
+
+// contentCacheBlobPath returns the file holding the content with the given git blob SHA
+func contentCacheBlobPath(dir, blobSHA string) string {
+	return filepath.Join(dir, "blobs", blobSHA[:2], blobSHA)
+}
+
+// readContentCache returns the content recorded by indexPath. Missing, malformed, and corrupted
+// entries are misses.
+func readContentCache(dir, indexPath string) ([]byte, bool) {
+	indexContent, err := os.ReadFile(indexPath)
+	if err != nil {
+		return nil, false
+	}
+	blobSHA := strings.TrimSpace(string(indexContent))
+	if len(blobSHA) != 40 || !gitutil.IsHexString(blobSHA) {
+		contentCacheLog.Printf("Ignoring malformed content cache index %s", indexPath)
+		return nil, false
+	}
+	content, err := os.ReadFile(contentCacheBlobPath(dir, blobSHA))
+	if err != nil {
+		return nil, false
+	}
+	if contentBlobSHA(content) != blobSHA {
+		contentCacheLog.Printf("Ignoring corrupted content cache blob %s", blobSHA)
+		return nil, false
+	}
+	return content, true
+}
+
+// writeContentCache stores content and records its blob SHA in indexPath
+func writeContentCache(dir, indexPath string, content []byte) error {
+	blobSHA := contentBlobSHA(content)
+	blobPath := contentCacheBlobPath(dir, blobSHA)
+	if !fileutil.FileExists(blobPath) {
+		if err := os.MkdirAll(filepath.Dir(blobPath), 0700); err != nil {
+			return err
+		}
+		if err := fileutil.WriteFileAtomic(blobPath, content, 0600); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(indexPath), 0700); err != nil {
+		return err
+	}
+	return fileutil.WriteFileAtomic(indexPath, []byte(blobSHA+"\n"), 0600)
+}
+
+// contentBlobSHA returns the git blob SHA of content, as reported by the contents API
+func contentBlobSHA(content []byte) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(content))
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,71 @@
+//go:build !integration
+
+package parser
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskCachedDownload(t *testing.T) {
+	const commit = "0123456789abcdef0123456789abcdef01234567"
+	var requested []string
+	download := func(content string) func(ref string) ([]byte, error) {
+		return func(ref string) ([]byte, error) {
+			requested = append(requested, ref)
+			return []byte(content), nil
+		}
+	}
+
+	// Without a cache directory every call downloads
+	_, err := defaultFetcher().diskCachedDownload("owner", "repo", "shared/tools.md", commit, download("v1"))
+	require.NoError(t, err, "download should succeed")
+	require.Len(t, requested, 1, "download should be called without a cache")
+
+	dir := t.TempDir()
+	f, err := NewFetcher(FetchConfig{CacheDir: dir})
+	require.NoError(t, err, "fetcher should be created")
+
+	content, err := f.diskCachedDownload("owner", "repo", "shared/tools.md", commit, download("v1"))
+	require.NoError(t, err, "first download should succeed")
+	assert.Equal(t, "v1", string(content), "first download should return the content")
+
+	content, err = f.diskCachedDownload("Owner", "Repo", "shared/tools.md", commit, download("v2"))
+	require.NoError(t, err, "cached read should succeed")
+	assert.Equal(t, "v1", string(content), "pinned file should be served from the cache")
+	assert.Equal(t, []string{commit, commit}, requested, "cached read should not download")
+
+	// The same content at another path is stored once
+	_, err = f.diskCachedDownload("owner", "repo", "shared/copy.md", commit, download("v1"))
+	require.NoError(t, err, "download should succeed")
+	blobs, err := filepath.Glob(filepath.Join(dir, "blobs", "*", "*"))
+	require.NoError(t, err, "blobs should be listable")
+	assert.Len(t, blobs, 1, "identical content should share one blob")
+
+	// Corrupted blobs are downloaded again
+	require.NoError(t, os.WriteFile(blobs[0], []byte("tampered"), 0600), "should corrupt blob")
+	content, err = f.diskCachedDownload("owner", "repo", "shared/tools.md", commit, download("v1"))
+	require.NoError(t, err, "download should succeed")
+	assert.Equal(t, "v1", string(content), "corrupted entry should be replaced by a fresh download")
+	assert.Len(t, requested, 4, "corrupted entry should be downloaded")
+
+	// Failed downloads are not cached
+	_, err = f.diskCachedDownload("owner", "repo", "missing.md", commit, func(string) ([]byte, error) {
+		return nil, errors.New("not found")
+	})
+	require.Error(t, err, "download error should be returned")
+	content, err = f.diskCachedDownload("owner", "repo", "missing.md", commit, download("later"))
+	require.NoError(t, err, "retry should succeed")
+	assert.Equal(t, "later", string(content), "failure should not be cached")
+
+	// The same repository on another GitHub host has its own entries
+	t.Setenv("GITHUB_SERVER_URL", "https://ghe.example.com")
+	content, err = f.diskCachedDownload("owner", "repo", "shared/tools.md", commit, download("ghe"))
+	require.NoError(t, err, "download from another host should succeed")
+	assert.Equal(t, "ghe", string(content), "entries of another host should not be served")
+}
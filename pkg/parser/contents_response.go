@@ -0,0 +1,76 @@
+//go:build !js && !wasm
+
+package parser
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// ErrUnexpectedResponse is returned (wrapped) when the GitHub contents API answers with
+// something other than file metadata, such as an HTML error page or a directory listing
+var ErrUnexpectedResponse = errors.New("unexpected response from the GitHub contents API")
+
+// checkContentsResponse verifies that a successful contents API response is JSON served by the
+// host that was asked. Redirects, e.g. for renamed repositories, must stay on the original host
+// and scheme so a misconfigured proxy or captive portal cannot substitute an HTML page.
+func checkContentsResponse(resp *http.Response, source string) error {
+	if final := resp.Request; final != nil && final.Response != nil {
+		original := final
+		for original.Response != nil && original.Response.Request != nil {
+			original = original.Response.Request
+		}
+		if final.URL.Host != original.URL.Host || final.URL.Scheme != original.URL.Scheme {
+			return fmt.Errorf("%s was redirected from %s://%s to %s://%s: %w",
+				source, original.URL.Scheme, original.URL.Host, final.URL.Scheme, final.URL.Host, ErrUnexpectedResponse)
+		}
+		remoteLog.Printf("Followed redirect for %s to %s", source, final.URL.Path)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		return nil // Left to JSON decoding
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("%s returned invalid content type %q: %w", source, contentType, ErrUnexpectedResponse)
+	}
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return nil
+	case mediaType == "text/html":
+		return fmt.Errorf("%s returned an HTML page instead of file contents (check the repository and path): %w", source, ErrUnexpectedResponse)
+	default:
+		return fmt.Errorf("%s returned content type %q instead of JSON: %w", source, mediaType, ErrUnexpectedResponse)
+	}
+}
+
+// contentsResponseBody returns the response body, decompressing it when the server sent gzip
+// content that the transport did not already decode
+func contentsResponseBody(resp *http.Response) (io.ReadCloser, error) {
+	if resp.Uncompressed || !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return resp.Body, nil
+	}
+	reader, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress response: %w", err)
+	}
+	return reader, nil
+}
+
+// checkFileContent verifies that contents API metadata describes a file whose content is
+// returned inline as base64
+func checkFileContent(fileType, encoding, source string) error {
+	if fileType != "" && fileType != "file" {
+		return fmt.Errorf("%s is a %s, not a file: %w", source, fileType, ErrUnexpectedResponse)
+	}
+	if encoding != "" && encoding != "base64" {
+		return fmt.Errorf("%s content is not returned inline (encoding %q): %w", source, encoding, ErrUnexpectedResponse)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+//go:build !integration
+
+package parser
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckContentsResponse(t *testing.T) {
+	mustParse := func(raw string) *url.URL {
+		u, err := url.Parse(raw)
+		require.NoError(t, err, "URL should parse")
+		return u
+	}
+	redirected := func(from, to string) *http.Request {
+		original := &http.Request{URL: mustParse(from)}
+		return &http.Request{URL: mustParse(to), Response: &http.Response{Request: original}}
+	}
+
+	tests := []struct {
+		name        string
+		request     *http.Request
+		contentType string
+		wantErr     string
+	}{
+		{
+			name:        "json",
+			request:     &http.Request{URL: mustParse("https://api.github.com/repos/o/r/contents/a.md")},
+			contentType: "application/json; charset=utf-8",
+		},
+		{
+			name:        "vendor json",
+			request:     &http.Request{URL: mustParse("https://api.github.com/repos/o/r/contents/a.md")},
+			contentType: "application/vnd.github+json",
+		},
+		{
+			name:    "missing content type",
+			request: &http.Request{URL: mustParse("https://api.github.com/repos/o/r/contents/a.md")},
+		},
+		{
+			name:        "html page",
+			request:     &http.Request{URL: mustParse("https://api.github.com/repos/o/r/contents/a.md")},
+			contentType: "text/html; charset=utf-8",
+			wantErr:     "returned an HTML page",
+		},
+		{
+			name:        "raw file",
+			request:     &http.Request{URL: mustParse("https://api.github.com/repos/o/r/contents/a.md")},
+			contentType: "text/plain",
+			wantErr:     `content type "text/plain" instead of JSON`,
+		},
+		{
+			name:        "redirect on the same host",
+			request:     redirected("https://api.github.com/repos/o/r/contents/a.md", "https://api.github.com/repositories/1/contents/a.md"),
+			contentType: "application/json",
+		},
+		{
+			name:        "redirect to another host",
+			request:     redirected("https://api.github.com/repos/o/r/contents/a.md", "https://login.example.com/portal"),
+			contentType: "application/json",
+			wantErr:     "redirected from https://api.github.com to https://login.example.com",
+		},
+		{
+			name:        "redirect to plain http",
+			request:     redirected("https://api.github.com/repos/o/r/contents/a.md", "http://api.github.com/repos/o/r/contents/a.md"),
+			contentType: "application/json",
+			wantErr:     "redirected from https://api.github.com to http://api.github.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Request: tt.request, Header: http.Header{}}
+			if tt.contentType != "" {
+				resp.Header.Set("Content-Type", tt.contentType)
+			}
+
+			err := checkContentsResponse(resp, "o/r/a.md@main")
+			if tt.wantErr == "" {
+				assert.NoError(t, err, "response should be accepted")
+				return
+			}
+			require.ErrorIs(t, err, ErrUnexpectedResponse, "error should wrap the sentinel")
+			assert.Contains(t, err.Error(), tt.wantErr, "error message mismatch")
+		})
+	}
+}
+
+func TestContentsResponseBody(t *testing.T) {
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	_, err := writer.Write([]byte(`{"content":""}`))
+	require.NoError(t, err, "gzip write should succeed")
+	require.NoError(t, writer.Close(), "gzip close should succeed")
+
+	resp := &http.Response{
+		Header: http.Header{"Content-Encoding": []string{"gzip"}},
+		Body:   io.NopCloser(&compressed),
+	}
+	body, err := contentsResponseBody(resp)
+	require.NoError(t, err, "gzip body should be opened")
+	data, err := io.ReadAll(body)
+	require.NoError(t, err, "gzip body should be read")
+	assert.JSONEq(t, `{"content":""}`, string(data), "gzip body should be decompressed")
+
+	plain := &http.Response{Header: http.Header{}, Body: io.NopCloser(bytes.NewBufferString("plain"))}
+	body, err = contentsResponseBody(plain)
+	require.NoError(t, err, "plain body should be returned")
+	assert.Equal(t, plain.Body, body, "uncompressed body should be returned as-is")
+}
+
+func TestCheckFileContent(t *testing.T) {
+	require.NoError(t, checkFileContent("file", "base64", "a.md"), "files with base64 content should be accepted")
+	require.NoError(t, checkFileContent("", "", "a.md"), "missing metadata should be left to content checks")
+
+	err := checkFileContent("submodule", "", "a.md")
+	require.ErrorIs(t, err, ErrUnexpectedResponse, "non-files should be rejected")
+	assert.Contains(t, err.Error(), "is a submodule, not a file", "error should name the type")
+
+	err = checkFileContent("file", "none", "a.md")
+	require.ErrorIs(t, err, ErrUnexpectedResponse, "non-inline content should be rejected")
+	assert.Contains(t, err.Error(), `encoding "none"`, "error should name the encoding")
+}
+
+func TestGetFileContentLimitedUnexpectedResponses(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantErr     string
+	}{
+		{name: "html error page", contentType: "text/html", body: "<html>Not Found</html>", wantErr: "returned an HTML page"},
+		{name: "directory listing", contentType: "application/json", body: `[{"name":"a.md","type":"file"}]`, wantErr: "is a directory, not a file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			target, err := url.Parse(server.URL)
+			require.NoError(t, err, "server URL should parse")
+			client, err := api.NewRESTClient(api.ClientOptions{
+				Host:      "github.com",
+				AuthToken: "test-token",
+				Transport: &redirectTransport{target: target},
+			})
+			require.NoError(t, err, "client should be created")
+
+			var response struct {
+				Content string `json:"content"`
+			}
+			err = getFileContentLimited(client, "repos/o/r/contents/shared", DefaultMaxDownloadSize, "o/r/shared@main", &response)
+			require.ErrorIs(t, err, ErrUnexpectedResponse, "unexpected responses should be rejected")
+			assert.Contains(t, err.Error(), tt.wantErr, "error message mismatch")
+		})
+	}
+}
@@ -0,0 +1,58 @@
+//go:build !js && !wasm
+
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var downloadCacheLog = logger.New("parser:download_cache")
+
+// downloadCacheEntry is a memoized download: the file content, or a not-found error
+type downloadCacheEntry struct {
+	content []byte
+	err     error
+}
+
+// cachedDownload returns a memoized download of owner/repo/path@ref, calling download on a miss.
+// Concurrent identical requests share a single call to download, whether or not downloads are
+// memoized: memoization de-duplicates downloads across time, the in-flight sharing across
+// goroutines.
+func (f *Fetcher) cachedDownload(owner, repo, path, ref string, download func() ([]byte, error)) ([]byte, error) {
+	key := f.cacheKey(fmt.Sprintf("%s/%s/%s@%s", owner, repo, path, ref))
+	cache := f.downloads
+	if cache == nil {
+		return f.sharedDownload(key, download)
+	}
+
+	if cached, ok := cache.Load(key); ok {
+		downloadCacheLog.Printf("Using cached download of %s", key)
+		entry := cached.(downloadCacheEntry)
+		return bytes.Clone(entry.content), entry.err
+	}
+
+	content, err := f.sharedDownload(key, download)
+	var notFound *NotFoundError
+	if err == nil || errors.As(err, &notFound) {
+		cache.Store(key, downloadCacheEntry{content: bytes.Clone(content), err: err})
+	}
+	return content, err
+}
+
+// sharedDownload calls download for key, or waits for the call already in flight for key and
+// returns its result. Shared results are cloned so that callers never alias each other's content.
+func (f *Fetcher) sharedDownload(key string, download func() ([]byte, error)) ([]byte, error) {
+	result, err, shared := f.group.Do(key, func() (any, error) {
+		return download()
+	})
+	content, _ := result.([]byte)
+	if !shared {
+		return content, err
+	}
+	downloadCacheLog.Printf("Shared in-flight download of %s", key)
+	return bytes.Clone(content), err
+}
@@ -0,0 +1,104 @@
+//go:build !integration
+
+package parser
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachedDownload(t *testing.T) {
+	calls := 0
+	download := func(content string, err error) func() ([]byte, error) {
+		return func() ([]byte, error) {
+			calls++
+			if err != nil {
+				return nil, err
+			}
+			return []byte(content), nil
+		}
+	}
+
+	_, _ = defaultFetcher().cachedDownload("owner", "repo", "a.md", "main", download("a", nil))
+	_, _ = defaultFetcher().cachedDownload("owner", "repo", "a.md", "main", download("a", nil))
+	assert.Equal(t, 2, calls, "downloads should not be memoized by the default fetcher")
+
+	f, err := NewFetcher(FetchConfig{})
+	require.NoError(t, err, "fetcher should be created")
+	calls = 0
+
+	content, err := f.cachedDownload("owner", "repo", "a.md", "main", download("a", nil))
+	require.NoError(t, err, "first download should succeed")
+	content[0] = 'x'
+	content, err = f.cachedDownload("owner", "repo", "a.md", "main", download("changed", nil))
+	require.NoError(t, err, "cached download should succeed")
+	assert.Equal(t, "a", string(content), "cached content should be returned unmodified")
+
+	_, _ = f.cachedDownload("owner", "repo", "a.md", "v1", download("a", nil))
+	assert.Equal(t, 2, calls, "each ref should be downloaded once")
+
+	notFound := &NotFoundError{Source: "owner/repo/missing.md@main", Err: errors.New("HTTP 404")}
+	for range 2 {
+		_, err = f.cachedDownload("owner", "repo", "missing.md", "main", download("", notFound))
+		require.ErrorAs(t, err, &notFound, "not found should be returned")
+	}
+	assert.Equal(t, 3, calls, "not found results should be memoized")
+
+	for range 2 {
+		_, err = f.cachedDownload("owner", "repo", "flaky.md", "main", download("", errors.New("connection reset")))
+		require.Error(t, err, "transient errors should be returned")
+	}
+	assert.Equal(t, 5, calls, "transient errors should not be memoized")
+
+	other, err := NewFetcher(FetchConfig{})
+	require.NoError(t, err, "fetcher should be created")
+	_, _ = other.cachedDownload("owner", "repo", "a.md", "main", download("a", nil))
+	assert.Equal(t, 6, calls, "fetchers should not share memoized downloads")
+}
+
+func TestCachedDownload_SharesInFlightRequests(t *testing.T) {
+	var calls atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	download := func() ([]byte, error) {
+		if calls.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		return []byte("content"), nil
+	}
+
+	f := defaultFetcher()
+	const requests = 5
+	results := make([][]byte, requests)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], _ = f.cachedDownload("owner", "repo", "shared.md", "main", download)
+	}()
+	<-started
+	for i := 1; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = f.cachedDownload("owner", "repo", "shared.md", "main", download)
+		}()
+	}
+	// Give the followers time to join the in-flight download before it completes
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load(), "concurrent identical requests should share one download")
+	results[0][0] = 'x'
+	for i := 1; i < requests; i++ {
+		assert.Equal(t, "content", string(results[i]), "request %d should get its own copy of the content", i)
+	}
+}
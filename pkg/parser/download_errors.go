@@ -0,0 +1,84 @@
+//go:build !js && !wasm
+
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// NotFoundError is returned when a file or ref does not exist in a GitHub repository
+type NotFoundError struct {
+	Source string // What was requested, e.g. "owner/repo/path/to/file.md@main"
+	Err    error  // Underlying API or git error
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s not found: %v", e.Source, e.Err)
+}
+
+func (e *NotFoundError) Unwrap() error {
+	return e.Err
+}
+
+// AuthError is returned when GitHub rejects the credentials used for a request and the
+// unauthenticated git fallback also fails
+type AuthError struct {
+	Source string // What was requested, e.g. "owner/repo/path/to/file.md@main"
+	Err    error  // Underlying API and git errors
+}
+
+func (e *AuthError) Error() string {
+	return fmt.Sprintf("authentication failed for %s (check that you are logged in with 'gh auth login' and have access to the repository): %v", e.Source, e.Err)
+}
+
+func (e *AuthError) Unwrap() error {
+	return e.Err
+}
+
+// RateLimitError is returned when a GitHub API request fails because the rate limit is
+// exhausted. It matches ErrRateLimited with errors.Is.
+type RateLimitError struct {
+	Reset string // When the limit resets, e.g. "resets at 15:04:05 MST (in 12m0s)"
+	Err   error  // Underlying API error
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%v (%s); wait for the reset or authenticate with a token that has a higher limit: %v", ErrRateLimited, e.Reset, e.Err)
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrRateLimited
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// AmbiguousRefError is returned when an unqualified ref names both a branch and a tag that
+// point at different commits. Qualifying the ref with refs/heads/ or refs/tags/ selects one.
+type AmbiguousRefError struct {
+	Repo      string // Repository slug, e.g. "owner/repo"
+	Ref       string // The unqualified ref, e.g. "v1"
+	BranchSHA string // Commit of refs/heads/<Ref>
+	TagSHA    string // Commit of refs/tags/<Ref>
+}
+
+func (e *AmbiguousRefError) Error() string {
+	return fmt.Sprintf("ref %s is ambiguous in %s: branch %s is at %s and tag %s is at %s; use refs/heads/%s or refs/tags/%s to choose one",
+		e.Ref, e.Repo, e.Ref, e.BranchSHA, e.Ref, e.TagSHA, e.Ref, e.Ref)
+}
+
+// isNotFound reports whether err is a 404 from the GitHub API, falling back on the error
+// message for errors from gh CLI or git commands
+func isNotFound(err error) bool {
+	var httpErr *api.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusNotFound
+	}
+	return isNotFoundError(err.Error())
+}
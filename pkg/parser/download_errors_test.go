@@ -0,0 +1,39 @@
+//go:build !integration
+
+package parser
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadErrors(t *testing.T) {
+	apiErr := &api.HTTPError{StatusCode: http.StatusNotFound, Message: "Not Found"}
+
+	var err error = &NotFoundError{Source: "o/r/a.md@main", Err: apiErr}
+	var notFound *NotFoundError
+	require.ErrorAs(t, err, &notFound, "not found error should match its type")
+	assert.ErrorAs(t, err, new(*api.HTTPError), "not found error should keep the API error")
+	assert.Contains(t, err.Error(), "o/r/a.md@main not found", "error should name the source")
+
+	err = &AuthError{Source: "o/r/a.md@main", Err: errors.New("HTTP 401")}
+	var authErr *AuthError
+	require.ErrorAs(t, err, &authErr, "auth error should match its type")
+	assert.Contains(t, err.Error(), "authentication failed for o/r/a.md@main", "error should name the source")
+
+	err = &RateLimitError{Reset: "reset time unknown", Err: errors.New("HTTP 403")}
+	require.ErrorIs(t, err, ErrRateLimited, "rate limit error should match the sentinel")
+	assert.NotErrorAs(t, err, &notFound, "rate limit error should not match other types")
+}
+
+func TestIsNotFound(t *testing.T) {
+	assert.True(t, isNotFound(&api.HTTPError{StatusCode: http.StatusNotFound}), "404 API errors should be not found")
+	assert.False(t, isNotFound(&api.HTTPError{StatusCode: http.StatusForbidden, Message: "Resource not found"}), "status code should take precedence over the message")
+	assert.True(t, isNotFound(errors.New("gh: Not Found (HTTP 404)")), "404 messages should be not found")
+	assert.False(t, isNotFound(errors.New("connection reset")), "other errors should not be not found")
+}
@@ -0,0 +1,47 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// DefaultMaxDownloadSize is the default limit on the size of a single downloaded file (5 MiB)
+const DefaultMaxDownloadSize int64 = 5 * 1024 * 1024
+
+// DefaultDownloadTimeout bounds a single file download from the GitHub API
+const DefaultDownloadTimeout = 60 * time.Second
+
+// ErrFileTooLarge is returned (wrapped) when downloaded content exceeds the maximum download size
+var ErrFileTooLarge = errors.New("file exceeds the maximum download size")
+
+// MaxDownloadSize returns the limit in bytes on the size of a single downloaded file for a
+// requested size: size itself, or DefaultMaxDownloadSize when size is 0 or less
+func MaxDownloadSize(size int64) int64 {
+	if size > 0 {
+		return size
+	}
+	return DefaultMaxDownloadSize
+}
+
+// checkDownloadSize fails with ErrFileTooLarge when size exceeds limit
+func checkDownloadSize(size, limit int64, source string) error {
+	if size > limit {
+		return fmt.Errorf("%s is %d bytes, limit is %d bytes: %w", source, size, limit, ErrFileTooLarge)
+	}
+	return nil
+}
+
+// readLimited reads r until EOF, failing with ErrFileTooLarge as soon as more than limit bytes
+// have been read so oversized responses are never buffered in full
+func readLimited(r io.Reader, limit int64, source string) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%s exceeds %d bytes: %w", source, limit, ErrFileTooLarge)
+	}
+	return data, nil
+}
@@ -0,0 +1,76 @@
+//go:build !integration
+
+package parser
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxDownloadSize(t *testing.T) {
+	assert.Equal(t, int64(1024), MaxDownloadSize(1024), "requested limit should apply")
+	assert.Equal(t, DefaultMaxDownloadSize, MaxDownloadSize(0), "zero should fall back to the default")
+	assert.Equal(t, DefaultMaxDownloadSize, MaxDownloadSize(-1), "negative sizes should fall back to the default")
+}
+
+func TestReadLimited(t *testing.T) {
+	data, err := readLimited(strings.NewReader("12345"), 5, "test.md")
+	require.NoError(t, err, "content at the limit should be accepted")
+	assert.Equal(t, "12345", string(data), "content should be returned unchanged")
+
+	_, err = readLimited(strings.NewReader("123456"), 5, "test.md")
+	require.ErrorIs(t, err, ErrFileTooLarge, "content over the limit should be rejected")
+	assert.Contains(t, err.Error(), "test.md", "error should name the source")
+}
+
+// redirectTransport sends every request to a test server
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestGetFileContentLimited(t *testing.T) {
+	fileContent := strings.Repeat("a", 128*1024)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"content":  base64.StdEncoding.EncodeToString([]byte(fileContent)),
+			"encoding": "base64",
+			"name":     "big.md",
+		})
+	}))
+	defer server.Close()
+
+	target, err := url.Parse(server.URL)
+	require.NoError(t, err, "server URL should parse")
+	client, err := api.NewRESTClient(api.ClientOptions{
+		Host:      "github.com",
+		AuthToken: "test-token",
+		Transport: &redirectTransport{target: target},
+	})
+	require.NoError(t, err, "client should be created")
+
+	var response struct {
+		Content string `json:"content"`
+	}
+	err = getFileContentLimited(client, "repos/o/r/contents/big.md", DefaultMaxDownloadSize, "o/r/big.md@main", &response)
+	require.NoError(t, err, "small responses should be read")
+	assert.NotEmpty(t, response.Content, "content should be decoded from the response")
+
+	err = getFileContentLimited(client, "repos/o/r/contents/big.md", 16, "o/r/big.md@main", &response)
+	require.ErrorIs(t, err, ErrFileTooLarge, "responses larger than the limit should be rejected while reading")
+}
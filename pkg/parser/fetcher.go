@@ -0,0 +1,150 @@
+//go:build !js && !wasm
+
+package parser
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"golang.org/x/sync/singleflight"
+)
+
+var fetcherLog = logger.New("parser:fetcher")
+
+// FetchConfig configures the downloads, ref resolutions, and directory listings made through a
+// Fetcher. The zero value fetches from GitHub with the default User-Agent.
+type FetchConfig struct {
+	UserAgent             string            // User-Agent sent with GitHub API requests (empty uses UserAgent())
+	RequestHeaders        map[string]string // Extra headers sent with GitHub API requests, e.g. for an egress proxy
+	RepoMirrors           map[string]string // Local directories read instead of repositories, keyed by owner/repo (see ParseRepoMirrors)
+	Offline               bool              // Fail with ErrRepoNotMirrored for repositories without a mirror instead of using the network
+	FrozenRefs            map[string]string // Commits that floating refs are frozen to, keyed by owner/repo@ref (nil unless frozen)
+	MaxConcurrentRequests int               // Maximum number of downloads and ref resolutions in flight at once (0 means unlimited)
+	CacheDir              string            // Directory caching downloaded files across runs by commit SHA (empty disables it)
+}
+
+// Forge serves the requests of a Fetcher for a repository host other than GitHub, such as a
+// Gitea instance (see Fetcher.WithForge). Missing files and directories are reported with a
+// *NotFoundError, and files larger than maxSize bytes with ErrFileTooLarge.
+type Forge interface {
+	ResolveRef(owner, repo, ref string) (string, error)
+	DownloadFile(owner, repo, path, ref string, maxSize int64) ([]byte, error)
+	ListDir(owner, repo, ref, dir string) ([]string, error)
+}
+
+// Fetcher downloads files, resolves refs, and lists directories of GitHub repositories under a
+// FetchConfig. Downloads are memoized for the lifetime of the Fetcher, so that workflows,
+// includes, and imports shared by several workflows are downloaded once; files that do not
+// exist are memoized too, so fallback paths are only probed once. Other failures are not
+// memoized. A Fetcher is safe for concurrent use, and a nil *Fetcher fetches like the
+// package-level functions such as DownloadFileFromGitHub.
+type Fetcher struct {
+	headers   map[string]string   // Extra request headers, including the configured User-Agent
+	mirrors   *repoMirrorConfig   // Repository mirrors (nil without any)
+	frozen    map[string]string   // Lowercase owner/repo@ref to frozen commit (nil unless frozen)
+	slots     chan struct{}       // Bounds the requests in flight (nil without a limit)
+	downloads *sync.Map           // Memoized downloads (nil when downloads are not memoized)
+	group     *singleflight.Group // Shares in-flight downloads
+	cacheDir  string              // Persistent content cache directory (empty when disabled)
+	forge     Forge               // Serves requests instead of GitHub (nil for GitHub)
+	forgeHost string              // Host of the forge, qualifying cache keys
+}
+
+// downloadGroup shares the in-flight downloads of the package-level functions
+var downloadGroup singleflight.Group
+
+// NewFetcher returns a Fetcher for config. It fails when a request header is malformed or
+// managed elsewhere, like Authorization and User-Agent (see ParseRequestHeaders).
+func NewFetcher(config FetchConfig) (*Fetcher, error) {
+	f := &Fetcher{
+		headers:   make(map[string]string, len(config.RequestHeaders)+1),
+		downloads: &sync.Map{},
+		group:     &singleflight.Group{},
+		cacheDir:  config.CacheDir,
+	}
+	for name, value := range config.RequestHeaders {
+		if err := validateRequestHeader(name, value); err != nil {
+			return nil, err
+		}
+		f.headers[http.CanonicalHeaderKey(name)] = value
+	}
+	if config.UserAgent != "" {
+		f.headers["User-Agent"] = config.UserAgent
+	}
+	if len(config.RepoMirrors) > 0 || config.Offline {
+		f.mirrors = newRepoMirrorConfig(config.RepoMirrors, config.Offline)
+	}
+	if config.FrozenRefs != nil {
+		f.frozen = make(map[string]string, len(config.FrozenRefs))
+		for key, sha := range config.FrozenRefs {
+			f.frozen[strings.ToLower(key)] = sha
+		}
+	}
+	if config.MaxConcurrentRequests > 0 {
+		f.slots = make(chan struct{}, config.MaxConcurrentRequests)
+	}
+	fetcherLog.Printf("Created fetcher: headers=%d, mirrors=%d, offline=%v, frozen refs=%d, max concurrent requests=%d, cache dir=%q",
+		len(config.RequestHeaders), len(config.RepoMirrors), config.Offline, len(config.FrozenRefs), config.MaxConcurrentRequests, config.CacheDir)
+	return f, nil
+}
+
+// defaultFetcher returns the Fetcher used by the package-level functions: GitHub with the
+// default User-Agent and the mirrors configured with SetRepoMirrors, without memoization
+func defaultFetcher() *Fetcher {
+	return &Fetcher{mirrors: repoMirrors.Load(), group: &downloadGroup}
+}
+
+// orDefault returns f, or the default Fetcher when f is nil
+func (f *Fetcher) orDefault() *Fetcher {
+	if f == nil {
+		return defaultFetcher()
+	}
+	return f
+}
+
+// DownloadFile returns the content of path in owner/repo at ref, like DownloadFileFromGitHub.
+// Files larger than maxSize bytes (DefaultMaxDownloadSize when maxSize is 0 or less) fail with
+// ErrFileTooLarge.
+func (f *Fetcher) DownloadFile(owner, repo, path, ref string, maxSize int64) ([]byte, error) {
+	return f.orDefault().downloadFile(owner, repo, path, ref, maxSize)
+}
+
+// ResolveRef resolves a branch, tag, or short SHA of owner/repo to its full commit SHA, like
+// ResolveRefToSHA
+func (f *Fetcher) ResolveRef(owner, repo, ref string) (string, error) {
+	return f.orDefault().resolveRefToSHA(owner, repo, ref)
+}
+
+// ListDir lists the .md files directly in dir of owner/repo at ref, like ListWorkflowFiles
+func (f *Fetcher) ListDir(owner, repo, ref, dir string) ([]string, error) {
+	return f.orDefault().listWorkflowFiles(owner, repo, ref, dir)
+}
+
+// WithForge returns a Fetcher that sends the requests f would send to GitHub to forge, the
+// repository host at host, instead. Frozen refs, repository mirrors, memoized downloads, the
+// request limit, the persistent content cache, and the API call counts apply to it like to f,
+// and are shared with f; memoized and cached entries are keyed by host.
+func (f *Fetcher) WithForge(host string, forge Forge) *Fetcher {
+	withForge := *f.orDefault()
+	withForge.forge = forge
+	withForge.forgeHost = strings.ToLower(host)
+	fetcherLog.Printf("Fetching from forge at %s", withForge.forgeHost)
+	return &withForge
+}
+
+// RequestHeaders returns the headers f sends with each request, including the User-Agent, for
+// forges that make their own requests
+func (f *Fetcher) RequestHeaders() map[string]string {
+	return apiRequestHeaders(f.orDefault().headers)
+}
+
+// cacheKey qualifies a memoization key with the forge host, so that a repository of a forge
+// never shares entries with the GitHub repository of the same name
+func (f *Fetcher) cacheKey(key string) string {
+	if f.forge == nil {
+		return key
+	}
+	return f.forgeHost + "|" + key
+}
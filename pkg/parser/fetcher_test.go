@@ -0,0 +1,81 @@
+//go:build !integration
+
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubForge serves files from memory and counts its requests
+type stubForge struct {
+	sha       string
+	files     map[string]string
+	resolves  int
+	downloads int
+}
+
+func (f *stubForge) ResolveRef(owner, repo, ref string) (string, error) {
+	f.resolves++
+	return f.sha, nil
+}
+
+func (f *stubForge) DownloadFile(owner, repo, path, ref string, maxSize int64) ([]byte, error) {
+	f.downloads++
+	if content, ok := f.files[path+"@"+ref]; ok {
+		return []byte(content), nil
+	}
+	return nil, &NotFoundError{Source: path, Err: errors.New("missing")}
+}
+
+func (f *stubForge) ListDir(owner, repo, ref, dir string) ([]string, error) {
+	return []string{dir + "/triage.md"}, nil
+}
+
+func TestFetcherWithForge(t *testing.T) {
+	commit := strings.Repeat("c", 40)
+	forge := &stubForge{sha: commit, files: map[string]string{"README.md@" + commit: "# Forge"}}
+	base, err := NewFetcher(FetchConfig{MaxConcurrentRequests: 1, CacheDir: t.TempDir()})
+	require.NoError(t, err, "fetcher should be created")
+	f := base.WithForge("Git.Example.com", forge)
+	t.Cleanup(refSHACache.Clear)
+
+	before := CurrentAPICallStats()
+	for range 2 {
+		content, err := f.DownloadFile("owner", "repo", "README.md", "main", 0)
+		require.NoError(t, err, "file should be downloaded from the forge")
+		assert.Equal(t, "# Forge", string(content), "content should come from the forge")
+	}
+	assert.Equal(t, 1, forge.resolves, "the ref should be resolved once")
+	assert.Equal(t, 1, forge.downloads, "the download should be memoized")
+	assert.Equal(t, APICallStats{RefResolutions: 1, ContentRequests: 1}, CurrentAPICallStats().Sub(before), "forge requests should be counted")
+
+	_, memoized := refSHACache.Load("git.example.com|" + refSHACacheKey("owner", "repo", "main"))
+	assert.True(t, memoized, "forge refs should be memoized under the forge host")
+	_, memoized = refSHACache.Load(refSHACacheKey("owner", "repo", "main"))
+	assert.False(t, memoized, "forge refs should not be memoized for GitHub")
+
+	content, err := f.DownloadFile("owner", "repo", "README.md", commit, 0)
+	require.NoError(t, err, "pinned file should be read")
+	assert.Equal(t, "# Forge", string(content), "content should come from the cache")
+	assert.Equal(t, 1, forge.downloads, "files cached on disk should not be downloaded again")
+
+	files, err := f.ListDir("owner", "repo", "main", "workflows")
+	require.NoError(t, err, "directory should be listed by the forge")
+	assert.Equal(t, []string{"workflows/triage.md"}, files, "listing should come from the forge")
+
+	frozen, err := NewFetcher(FetchConfig{FrozenRefs: map[string]string{"owner/repo@main": commit}})
+	require.NoError(t, err, "fetcher should be created")
+	_, err = frozen.WithForge("git.example.com", forge).ResolveRef("owner", "repo", "develop")
+	require.ErrorIs(t, err, ErrRefNotLocked, "frozen refs should apply to forges")
+
+	offline, err := NewFetcher(FetchConfig{Offline: true})
+	require.NoError(t, err, "fetcher should be created")
+	_, err = offline.WithForge("git.example.com", forge).DownloadFile("owner", "repo", "README.md", commit, 0)
+	require.ErrorIs(t, err, ErrRepoNotMirrored, "offline mode should apply to forges")
+	assert.Equal(t, 1, forge.downloads, "offline fetches should not reach the forge")
+}
@@ -3,16 +3,32 @@ package parser
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
+	"unicode"
 
+	"github.com/BurntSushi/toml"
 	"github.com/goccy/go-yaml"
 )
 
+// FrontmatterFormat identifies the syntax used for a frontmatter block
+type FrontmatterFormat string
+
+const (
+	// FrontmatterFormatYAML is YAML frontmatter between --- fences
+	FrontmatterFormatYAML FrontmatterFormat = "yaml"
+	// FrontmatterFormatTOML is TOML frontmatter between +++ fences
+	FrontmatterFormatTOML FrontmatterFormat = "toml"
+	// FrontmatterFormatJSON is JSON frontmatter in a leading ```json code block
+	FrontmatterFormatJSON FrontmatterFormat = "json"
+)
+
 // FrontmatterResult holds parsed frontmatter and markdown content
 type FrontmatterResult struct {
 	Frontmatter map[string]any
@@ -20,15 +36,66 @@ type FrontmatterResult struct {
 	// Additional fields for error context
 	FrontmatterLines []string // Original frontmatter lines for error context
 	FrontmatterStart int      // Line number where frontmatter starts (1-based)
+	// Format records the syntax the frontmatter was written in (empty when there is no frontmatter)
+	Format FrontmatterFormat
+}
+
+// detectFrontmatterFormat returns the frontmatter format and closing fence for the
+// given opening line, or false if the line does not open a frontmatter block
+func detectFrontmatterFormat(firstLine string) (FrontmatterFormat, string, bool) {
+	switch strings.TrimSpace(firstLine) {
+	case "---":
+		return FrontmatterFormatYAML, "---", true
+	case "+++":
+		return FrontmatterFormatTOML, "+++", true
+	case "```json":
+		return FrontmatterFormatJSON, "```", true
+	default:
+		return "", "", false
+	}
+}
+
+// Fences returns the lines that open and close frontmatter written in the format
+func (f FrontmatterFormat) Fences() (opening, closing string) {
+	switch f {
+	case FrontmatterFormatTOML:
+		return "+++", "+++"
+	case FrontmatterFormatJSON:
+		return "```json", "```"
+	default:
+		return "---", "---"
+	}
 }
 
-// ExtractFrontmatterFromContent parses YAML frontmatter from markdown content string
+// FrontmatterBounds returns the format of the frontmatter that lines open with and the index
+// of the line closing it. The format is empty when lines do not open with frontmatter, and
+// the index is -1 when the frontmatter is not closed.
+func FrontmatterBounds(lines []string) (FrontmatterFormat, int) {
+	if len(lines) == 0 {
+		return "", -1
+	}
+	format, closingFence, ok := detectFrontmatterFormat(lines[0])
+	if !ok {
+		return "", -1
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == closingFence {
+			return format, i
+		}
+	}
+	return format, -1
+}
+
+// ExtractFrontmatterFromContent parses frontmatter from markdown content string.
+// YAML (--- fences), TOML (+++ fences) and JSON (leading ```json block) are supported;
+// all formats produce the same Frontmatter map shape.
 func ExtractFrontmatterFromContent(content string) (*FrontmatterResult, error) {
 	log.Printf("Extracting frontmatter from content: size=%d bytes", len(content))
 	lines := strings.Split(content, "\n")
 
 	// Check if file starts with frontmatter delimiter
-	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+	format, endIndex := FrontmatterBounds(lines)
+	if format == "" {
 		log.Print("No frontmatter delimiter found, returning content as markdown")
 		// No frontmatter, return entire content as markdown
 		return &FrontmatterResult{
@@ -39,33 +106,21 @@ func ExtractFrontmatterFromContent(content string) (*FrontmatterResult, error) {
 		}, nil
 	}
 
-	// Find end of frontmatter
-	endIndex := -1
-	for i := 1; i < len(lines); i++ {
-		if strings.TrimSpace(lines[i]) == "---" {
-			endIndex = i
-			break
-		}
-	}
-
 	if endIndex == -1 {
 		return nil, errors.New("frontmatter not properly closed")
 	}
 
-	// Extract frontmatter YAML
+	// Extract frontmatter source
 	frontmatterLines := lines[1:endIndex]
-	frontmatterYAML := strings.Join(frontmatterLines, "\n")
+	frontmatterSource := strings.Join(frontmatterLines, "\n")
 
-	// Sanitize no-break whitespace characters (U+00A0) which break the YAML parser
-	frontmatterYAML = strings.ReplaceAll(frontmatterYAML, "\u00A0", " ")
+	// Sanitize no-break whitespace characters (U+00A0) which break the parsers
+	frontmatterSource = strings.ReplaceAll(frontmatterSource, "\u00A0", " ")
 
-	// Parse YAML
-	var frontmatter map[string]any
-	if err := yaml.Unmarshal([]byte(frontmatterYAML), &frontmatter); err != nil {
-		// Use FormatYAMLError to provide source-positioned error output with adjusted line numbers
-		// FrontmatterStart is 2 (line 2 is where frontmatter content starts after opening ---)
-		formattedErr := FormatYAMLError(err, 2, frontmatterYAML)
-		return nil, fmt.Errorf("failed to parse frontmatter:\n%s", formattedErr)
+	log.Printf("Detected %s frontmatter", format)
+	frontmatter, err := parseFrontmatterSource(format, frontmatterSource)
+	if err != nil {
+		return nil, err
 	}
 
 	// Ensure frontmatter is never nil (yaml.Unmarshal sets it to nil for empty YAML)
@@ -73,7 +128,7 @@ func ExtractFrontmatterFromContent(content string) (*FrontmatterResult, error) {
 		frontmatter = make(map[string]any)
 	}
 
-	// Extract markdown content (everything after the closing ---)
+	// Extract markdown content (everything after the closing fence)
 	var markdownLines []string
 	if endIndex+1 < len(lines) {
 		markdownLines = lines[endIndex+1:]
@@ -85,50 +140,116 @@ func ExtractFrontmatterFromContent(content string) (*FrontmatterResult, error) {
 		Frontmatter:      frontmatter,
 		Markdown:         strings.TrimSpace(markdown),
 		FrontmatterLines: frontmatterLines,
-		FrontmatterStart: 2, // Line 2 is where frontmatter content starts (after opening ---)
+		FrontmatterStart: 2, // Line 2 is where frontmatter content starts (after opening fence)
+		Format:           format,
 	}, nil
 }
 
+// parseFrontmatterSource parses the frontmatter body in the given format.
+// TOML and JSON are normalized through YAML so that value types (integers, nested
+// maps, lists) match exactly what the YAML parser produces for the rest of the pipeline.
+func parseFrontmatterSource(format FrontmatterFormat, source string) (map[string]any, error) {
+	switch format {
+	case FrontmatterFormatTOML:
+		var decoded map[string]any
+		if _, err := toml.Decode(source, &decoded); err != nil {
+			return nil, fmt.Errorf("failed to parse TOML frontmatter: %w", err)
+		}
+		return normalizeFrontmatterViaYAML(decoded)
+	case FrontmatterFormatJSON:
+		// JSON is a subset of YAML, so once it is known to be valid JSON the YAML
+		// parser below yields the same value types as native YAML frontmatter
+		if strings.TrimSpace(source) == "" {
+			return make(map[string]any), nil
+		}
+		var decoded map[string]any
+		if err := json.Unmarshal([]byte(source), &decoded); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON frontmatter: %w", err)
+		}
+	}
+
+	var frontmatter map[string]any
+	if err := yaml.Unmarshal([]byte(source), &frontmatter); err != nil {
+		// Use FormatYAMLError to provide source-positioned error output with adjusted line numbers
+		// FrontmatterStart is 2 (line 2 is where frontmatter content starts after opening ---)
+		formattedErr := FormatYAMLError(err, 2, source)
+		return nil, fmt.Errorf("failed to parse frontmatter:\n%s", formattedErr)
+	}
+	return frontmatter, nil
+}
+
+// normalizeFrontmatterViaYAML round-trips decoded frontmatter through YAML
+func normalizeFrontmatterViaYAML(decoded map[string]any) (map[string]any, error) {
+	if len(decoded) == 0 {
+		return make(map[string]any), nil
+	}
+
+	yamlBytes, err := yaml.Marshal(decoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to normalize frontmatter: %w", err)
+	}
+
+	var frontmatter map[string]any
+	if err := yaml.Unmarshal(yamlBytes, &frontmatter); err != nil {
+		return nil, fmt.Errorf("failed to normalize frontmatter: %w", err)
+	}
+	return frontmatter, nil
+}
+
+// markdownHeadingPattern matches H1-H3 headings, capturing the level and the heading text
+var markdownHeadingPattern = regexp.MustCompile(`^(#{1,3})[ \t]+(.*?)[ \t]*$`)
+
 // ExtractMarkdownSection extracts a specific section from markdown content
 // Supports H1-H3 headers and proper nesting (matches bash implementation)
+// Sections are matched by their GitHub heading slug, so "My Section", "my section", and
+// "my-section" all select "## My Section". The first heading with a matching slug is used.
 func ExtractMarkdownSection(content, sectionName string) (string, error) {
 	log.Printf("Extracting markdown section: section=%s, content_size=%d bytes", sectionName, len(content))
+	wantSlug := HeadingSlug(sectionName)
+	if wantSlug == "" {
+		return "", fmt.Errorf("section '%s' is not a valid section name", sectionName)
+	}
+
 	scanner := bufio.NewScanner(strings.NewReader(content))
 	var sectionContent bytes.Buffer
 	inSection := false
 	var sectionLevel int
-
-	// Create regex pattern to match headers at any level (H1-H3) with flexible spacing
-	headerPattern := regexp.MustCompile(`^(#{1,3})[\s\t]+` + regexp.QuoteMeta(sectionName) + `[\s\t]*$`)
-	levelPattern := regexp.MustCompile(`^(#{1,3})[\s\t]+`)
+	var available []string
 
 	for scanner.Scan() {
 		line := scanner.Text()
+		matches := markdownHeadingPattern.FindStringSubmatch(line)
 
-		// Check if this line matches our target section
-		if matches := headerPattern.FindStringSubmatch(line); matches != nil {
-			inSection = true
-			sectionLevel = len(matches[1]) // Number of # characters
+		// If we're in the section, stop at the next header at same or higher level
+		if inSection {
+			if matches != nil && len(matches[1]) <= sectionLevel {
+				break
+			}
 			sectionContent.WriteString(line + "\n")
 			continue
 		}
 
-		// If we're in the section, check if we've hit another header at same or higher level
-		if inSection {
-			if levelMatches := levelPattern.FindStringSubmatch(line); levelMatches != nil {
-				currentLevel := len(levelMatches[1])
-				// Stop if we encounter same or higher level header
-				if currentLevel <= sectionLevel {
-					break
-				}
+		// Check if this line is our target section
+		if matches != nil {
+			slug := HeadingSlug(matches[2])
+			if slug == wantSlug {
+				inSection = true
+				sectionLevel = len(matches[1]) // Number of # characters
+				sectionContent.WriteString(line + "\n")
+				continue
+			}
+			if slug != "" && !slices.Contains(available, slug) {
+				available = append(available, slug)
 			}
-			sectionContent.WriteString(line + "\n")
 		}
 	}
 
 	if !inSection {
-		log.Printf("Section not found: %s", sectionName)
-		return "", fmt.Errorf("section '%s' not found", sectionName)
+		log.Printf("Section not found: %s (slug %s)", sectionName, wantSlug)
+		if len(available) == 0 {
+			return "", fmt.Errorf("section '%s' not found: the content has no sections", sectionName)
+		}
+		return "", fmt.Errorf("section '%s' not found (available sections: %s)", sectionName, strings.Join(available, ", "))
 	}
 
 	extractedContent := strings.TrimSpace(sectionContent.String())
@@ -136,6 +257,27 @@ func ExtractMarkdownSection(content, sectionName string) (string, error) {
 	return extractedContent, nil
 }
 
+// HeadingSlug converts a markdown heading or section fragment to its GitHub anchor slug:
+// lowercased, with spaces turned into hyphens and punctuation removed. A closing sequence of
+// '#' characters (as in "## Setup ##") is not part of the heading.
+func HeadingSlug(heading string) string {
+	heading = strings.TrimSpace(heading)
+	if trimmed := strings.TrimRight(heading, "#"); trimmed != heading && (trimmed == "" || strings.HasSuffix(trimmed, " ") || strings.HasSuffix(trimmed, "\t")) {
+		heading = strings.TrimSpace(trimmed)
+	}
+
+	var slug strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case r == ' ' || r == '\t':
+			slug.WriteRune('-')
+		case r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+			slug.WriteRune(r)
+		}
+	}
+	return slug.String()
+}
+
 // ExtractFrontmatterString extracts only the YAML frontmatter as a string
 // This matches the bash extract_frontmatter function
 func ExtractFrontmatterString(content string) (string, error) {
@@ -3,6 +3,7 @@
 package parser
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -103,6 +104,104 @@ This is a test workflow with empty frontmatter.`,
 	}
 }
 
+func TestExtractFrontmatterFromContentFormats(t *testing.T) {
+	yamlContent := "---\non: push\ntimeout-minutes: 10\nimports:\n  - shared/a.md\n  - shared/b.md\ntools:\n  github:\n    toolsets: [repos]\n---\n\n# Body"
+
+	yamlResult, err := ExtractFrontmatterFromContent(yamlContent)
+	if err != nil {
+		t.Fatalf("YAML frontmatter failed to parse: %v", err)
+	}
+	if yamlResult.Format != FrontmatterFormatYAML {
+		t.Errorf("Format = %q, want %q", yamlResult.Format, FrontmatterFormatYAML)
+	}
+
+	tests := []struct {
+		name       string
+		content    string
+		wantFormat FrontmatterFormat
+		wantErr    bool
+	}{
+		{
+			name:       "TOML frontmatter",
+			content:    "+++\non = \"push\"\ntimeout-minutes = 10\nimports = [\"shared/a.md\", \"shared/b.md\"]\n\n[tools.github]\ntoolsets = [\"repos\"]\n+++\n\n# Body",
+			wantFormat: FrontmatterFormatTOML,
+		},
+		{
+			name:       "JSON frontmatter",
+			content:    "```json\n{\"on\": \"push\", \"timeout-minutes\": 10, \"imports\": [\"shared/a.md\", \"shared/b.md\"], \"tools\": {\"github\": {\"toolsets\": [\"repos\"]}}}\n```\n\n# Body",
+			wantFormat: FrontmatterFormatJSON,
+		},
+		{
+			name:    "invalid TOML frontmatter",
+			content: "+++\non = \n+++\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid JSON frontmatter",
+			content: "```json\n{\"on\":\n```\n",
+			wantErr: true,
+		},
+		{
+			name:    "unclosed JSON frontmatter",
+			content: "```json\n{}\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ExtractFrontmatterFromContent(tt.content)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ExtractFrontmatterFromContent() expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExtractFrontmatterFromContent() error = %v", err)
+			}
+
+			if result.Format != tt.wantFormat {
+				t.Errorf("Format = %q, want %q", result.Format, tt.wantFormat)
+			}
+			if !reflect.DeepEqual(result.Frontmatter, yamlResult.Frontmatter) {
+				t.Errorf("Frontmatter = %#v, want same as YAML %#v", result.Frontmatter, yamlResult.Frontmatter)
+			}
+			if result.Markdown != "# Body" {
+				t.Errorf("Markdown = %q, want %q", result.Markdown, "# Body")
+			}
+		})
+	}
+}
+
+func TestFrontmatterBounds(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		wantFormat FrontmatterFormat
+		wantEnd    int
+	}{
+		{name: "YAML", content: "---\non: push\n---\n# Body", wantFormat: FrontmatterFormatYAML, wantEnd: 2},
+		{name: "TOML", content: "+++\non = \"push\"\n+++\n# Body", wantFormat: FrontmatterFormatTOML, wantEnd: 2},
+		{name: "JSON", content: "```json\n{\n  \"on\": \"push\"\n}\n```\n# Body", wantFormat: FrontmatterFormatJSON, wantEnd: 4},
+		{name: "unclosed", content: "+++\non = \"push\"\n---\n# Body", wantFormat: FrontmatterFormatTOML, wantEnd: -1},
+		{name: "no frontmatter", content: "# Body\n---", wantFormat: "", wantEnd: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, end := FrontmatterBounds(strings.Split(tt.content, "\n"))
+			if format != tt.wantFormat || end != tt.wantEnd {
+				t.Errorf("FrontmatterBounds() = %q, %d, want %q, %d", format, end, tt.wantFormat, tt.wantEnd)
+			}
+			opening, closing := tt.wantFormat.Fences()
+			if tt.wantFormat != "" && (opening != strings.Split(tt.content, "\n")[0] || (end >= 0 && closing != strings.Split(tt.content, "\n")[end])) {
+				t.Errorf("Fences() = %q, %q, do not match %q", opening, closing, tt.content)
+			}
+		})
+	}
+}
+
 func TestExtractYamlChunk(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -264,6 +363,24 @@ Nested content`,
 			sectionName: "NonExistent",
 			wantErr:     true,
 		},
+		{
+			name:        "case-insensitive match",
+			content:     "# Intro\n\nHello\n\n## My Section\n\nBody\n",
+			sectionName: "my section",
+			expected:    "## My Section\n\nBody",
+		},
+		{
+			name:        "slug match ignores punctuation",
+			content:     "# Intro\n\nHello\n\n## What's New? (v2)\n\nChanges\n\n## Other\n",
+			sectionName: "whats-new-v2",
+			expected:    "## What's New? (v2)\n\nChanges",
+		},
+		{
+			name:        "closing hashes are not part of the heading",
+			content:     "## Setup ##\n\nSteps\n",
+			sectionName: "Setup",
+			expected:    "## Setup ##\n\nSteps",
+		},
 	}
 
 	for _, tt := range tests {
@@ -289,6 +406,35 @@ Nested content`,
 	}
 }
 
+func TestHeadingSlug(t *testing.T) {
+	tests := map[string]string{
+		"My Section":          "my-section",
+		"my-section":          "my-section",
+		"What's New? (v2)":    "whats-new-v2",
+		"snake_case heading":  "snake_case-heading",
+		"Setup ##":            "setup",
+		"C# Tips":             "c-tips",
+		"  Trailing spaces  ": "trailing-spaces",
+		"Überblick & Details": "überblick--details",
+		"!!!":                 "",
+	}
+	for heading, expected := range tests {
+		if got := HeadingSlug(heading); got != expected {
+			t.Errorf("HeadingSlug(%q) = %q, want %q", heading, got, expected)
+		}
+	}
+}
+
+func TestExtractMarkdownSection_ListsAvailableSections(t *testing.T) {
+	_, err := ExtractMarkdownSection("# Intro\n\n## Getting Started\n\n## FAQ\n", "Install")
+	if err == nil {
+		t.Fatal("ExtractMarkdownSection() expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "available sections: intro, getting-started, faq") {
+		t.Errorf("ExtractMarkdownSection() error = %q, want the available section slugs", err)
+	}
+}
+
 func TestGenerateDefaultWorkflowName(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -423,29 +423,21 @@ func extractFrontmatterAndBodyText(content string) (string, string, error) {
 
 	lines := strings.Split(content, "\n")
 
-	// Check if content starts with frontmatter delimiter
-	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+	// Check if content starts with a frontmatter fence (---, +++, or ```json)
+	format, endIndex := FrontmatterBounds(lines)
+	if format == "" {
 		// No frontmatter
 		return "", content, nil
 	}
 
-	// Find end of frontmatter
-	endIndex := -1
-	for i := 1; i < len(lines); i++ {
-		if strings.TrimSpace(lines[i]) == "---" {
-			endIndex = i
-			break
-		}
-	}
-
 	if endIndex == -1 {
 		return "", "", errors.New("frontmatter not properly closed")
 	}
 
-	// Extract frontmatter text (lines between --- delimiters)
+	// Extract frontmatter text (lines between the fences)
 	frontmatterText := strings.Join(lines[1:endIndex], "\n")
 
-	// Extract markdown body (everything after closing ---)
+	// Extract markdown body (everything after the closing fence)
 	var markdown string
 	if endIndex+1 < len(lines) {
 		markdown = strings.Join(lines[endIndex+1:], "\n")
@@ -463,8 +455,13 @@ func normalizeFrontmatterText(text string) string {
 	return strings.TrimSpace(normalized)
 }
 
+// importsArrayKeyPattern matches the imports key of TOML (imports = [) and JSON ("imports": [)
+// frontmatter, where the list is an array of quoted strings
+var importsArrayKeyPattern = regexp.MustCompile(`(?:^|[{,]\s*)"imports"\s*:\s*\[|^"?imports"?\s*=\s*\[`)
+
 // extractImportsFromText extracts import paths from frontmatter text using simple text parsing
-// Only extracts array items under "imports:" key
+// Only extracts array items under "imports:" key, or the quoted strings of an imports array
+// in TOML and JSON frontmatter
 func extractImportsFromText(frontmatterText string) []string {
 	var imports []string
 	lines := strings.Split(frontmatterText, "\n")
@@ -481,6 +478,12 @@ func extractImportsFromText(frontmatterText string) []string {
 			continue
 		}
 
+		// Check if this is the imports key of TOML or JSON frontmatter
+		if loc := importsArrayKeyPattern.FindStringIndex(trimmed); loc != nil {
+			rest := strings.Join(append([]string{trimmed[loc[1]:]}, lines[i+1:]...), "\n")
+			return extractQuotedArrayItems(rest)
+		}
+
 		// Check if this is the imports: key
 		if strings.HasPrefix(trimmed, "imports:") {
 			inImports = true
@@ -513,6 +516,35 @@ func extractImportsFromText(frontmatterText string) []string {
 	return imports
 }
 
+// extractQuotedArrayItems returns the quoted string items of the array whose text follows its
+// opening bracket in text, up to the closing bracket. Items of nested arrays and objects are
+// skipped.
+func extractQuotedArrayItems(text string) []string {
+	var items []string
+	depth := 1
+	for i := 0; i < len(text) && depth > 0; i++ {
+		switch c := text[i]; c {
+		case '"', '\'':
+			end := i + 1
+			for end < len(text) && text[end] != c {
+				if c == '"' && text[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if depth == 1 && end > i+1 && end < len(text) {
+				items = append(items, text[i+1:end])
+			}
+			i = end
+		case '[', '{':
+			depth++
+		case ']', '}':
+			depth--
+		}
+	}
+	return items
+}
+
 // processImportsTextBased processes imports from frontmatter using text-based parsing
 // Returns: importedFiles (list of import paths), importedFrontmatterTexts (list of frontmatter texts)
 func processImportsTextBased(frontmatterText, baseDir string, visited map[string]bool, fileReader FileReader) ([]string, []string, error) {
@@ -450,3 +450,77 @@ tools:
 	assert.Len(t, hash, 64, "Hash should be 64 characters")
 	assert.Regexp(t, "^[a-f0-9]{64}$", hash, "Hash should be lowercase hex")
 }
+
+func TestExtractImportsFromText_TOMLAndJSON(t *testing.T) {
+	tests := []struct {
+		name            string
+		frontmatterText string
+		expected        []string
+	}{
+		{
+			name:            "TOML inline array",
+			frontmatterText: "engine = \"copilot\"\nimports = [\"shared/a.md\", 'shared/b.md']\ndescription = \"Test\"",
+			expected:        []string{"shared/a.md", "shared/b.md"},
+		},
+		{
+			name:            "TOML multiline array",
+			frontmatterText: "imports = [\n  \"shared/a.md\",\n  \"shared/b.md\",\n]\nengine = \"copilot\"",
+			expected:        []string{"shared/a.md", "shared/b.md"},
+		},
+		{
+			name:            "JSON array",
+			frontmatterText: "{\n  \"engine\": \"copilot\",\n  \"imports\": [\n    \"shared/a.md\",\n    \"shared/b.md\"\n  ],\n  \"description\": \"Test\"\n}",
+			expected:        []string{"shared/a.md", "shared/b.md"},
+		},
+		{
+			name:            "JSON on one line",
+			frontmatterText: "{\"engine\": \"copilot\", \"imports\": [\"shared/a.md\"]}",
+			expected:        []string{"shared/a.md"},
+		},
+		{
+			name:            "JSON array skips object items",
+			frontmatterText: "{\n  \"imports\": [\"shared/a.md\", {\"path\": \"shared/b.md\"}]\n}",
+			expected:        []string{"shared/a.md"},
+		},
+		{
+			name:            "YAML flow sequence is not read",
+			frontmatterText: "imports: [\"shared/a.md\"]",
+			expected:        nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, extractImportsFromText(tt.frontmatterText), "Imports should match")
+		})
+	}
+}
+
+func TestComputeFrontmatterHashFromFileWithReader_TOMLAndJSON(t *testing.T) {
+	mockFS := map[string]string{
+		"/test/shared/imported.md": "+++\n[tools]\nbash = true\n+++\n\n# Imported Content",
+	}
+	customReader := func(filePath string) ([]byte, error) {
+		content, exists := mockFS[filePath]
+		if !exists {
+			return nil, os.ErrNotExist
+		}
+		return []byte(content), nil
+	}
+	hashOf := func(content string) string {
+		mockFS["/test/workflow.md"] = content
+		hash, err := ComputeFrontmatterHashFromFileWithReader("/test/workflow.md", nil, customReader)
+		require.NoError(t, err, "Should compute hash")
+		return hash
+	}
+
+	noFrontmatter := hashOf("# Main Workflow")
+	toml := hashOf("+++\nengine = \"copilot\"\nimports = [\"shared/imported.md\"]\n+++\n\n# Main Workflow")
+	json := hashOf("```json\n{\"engine\": \"copilot\", \"imports\": [\"shared/imported.md\"]}\n```\n\n# Main Workflow")
+	assert.NotEqual(t, noFrontmatter, toml, "TOML frontmatter should be hashed")
+	assert.NotEqual(t, noFrontmatter, json, "JSON frontmatter should be hashed")
+
+	mockFS["/test/shared/imported.md"] = "+++\n[tools]\nbash = false\n+++\n\n# Imported Content"
+	assert.NotEqual(t, toml, hashOf("+++\nengine = \"copilot\"\nimports = [\"shared/imported.md\"]\n+++\n\n# Main Workflow"), "TOML imports should be hashed")
+	assert.NotEqual(t, json, hashOf("```json\n{\"engine\": \"copilot\", \"imports\": [\"shared/imported.md\"]}\n```\n\n# Main Workflow"), "JSON imports should be hashed")
+}
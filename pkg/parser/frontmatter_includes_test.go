@@ -608,7 +608,7 @@ This is a shared configuration file.`
 	}
 
 	// Process the included file - should not generate warnings for name and description
-	result, err := processIncludedFileWithVisited(testFile, "", false, make(map[string]bool))
+	result, err := processIncludedFileWithVisited(testFile, "", false, "", nil, make(map[string]bool))
 	if err != nil {
 		t.Fatalf("processIncludedFileWithVisited() error = %v", err)
 	}
@@ -647,7 +647,7 @@ This file only has name and description in frontmatter.`
 	}
 
 	// Process the included file - should not generate warnings
-	result, err := processIncludedFileWithVisited(testFile, "", false, make(map[string]bool))
+	result, err := processIncludedFileWithVisited(testFile, "", false, "", nil, make(map[string]bool))
 	if err != nil {
 		t.Fatalf("processIncludedFileWithVisited() error = %v", err)
 	}
@@ -683,7 +683,7 @@ This is a custom agent file with the disable-model-invocation field.`
 	}
 
 	// Process the included file - should not generate warnings
-	result, err := processIncludedFileWithVisited(testFile, "", false, make(map[string]bool))
+	result, err := processIncludedFileWithVisited(testFile, "", false, "", nil, make(map[string]bool))
 	if err != nil {
 		t.Fatalf("processIncludedFileWithVisited() error = %v", err)
 	}
@@ -737,7 +737,7 @@ This agent removes feature flags from the codebase.`
 
 	// Process the included file - should not generate validation errors
 	// because custom agent files use a different tools format (array vs object)
-	result, err := processIncludedFileWithVisited(testFile, "", false, make(map[string]bool))
+	result, err := processIncludedFileWithVisited(testFile, "", false, "", nil, make(map[string]bool))
 	if err != nil {
 		t.Fatalf("processIncludedFileWithVisited() error = %v, want nil", err)
 	}
@@ -747,7 +747,7 @@ This agent removes feature flags from the codebase.`
 	}
 
 	// Also test that tools extraction skips agent files and returns empty object
-	toolsResult, err := processIncludedFileWithVisited(testFile, "", true, make(map[string]bool))
+	toolsResult, err := processIncludedFileWithVisited(testFile, "", true, "", nil, make(map[string]bool))
 	if err != nil {
 		t.Fatalf("processIncludedFileWithVisited(extractTools=true) error = %v, want nil", err)
 	}
@@ -787,7 +787,7 @@ This is a shared engine configuration with custom command.`
 	}
 
 	// Process the included file - should not generate validation errors
-	result, err := processIncludedFileWithVisited(testFile, "", false, make(map[string]bool))
+	result, err := processIncludedFileWithVisited(testFile, "", false, "", nil, make(map[string]bool))
 	if err != nil {
 		t.Fatalf("processIncludedFileWithVisited() error = %v, want nil", err)
 	}
@@ -797,7 +797,7 @@ This is a shared engine configuration with custom command.`
 	}
 
 	// Also test that tools extraction works correctly
-	toolsResult, err := processIncludedFileWithVisited(testFile, "", true, make(map[string]bool))
+	toolsResult, err := processIncludedFileWithVisited(testFile, "", true, "", nil, make(map[string]bool))
 	if err != nil {
 		t.Fatalf("processIncludedFileWithVisited(extractTools=true) error = %v, want nil", err)
 	}
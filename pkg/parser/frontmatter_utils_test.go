@@ -733,6 +733,26 @@ This is an included file.`
 			wantEngines:   false,
 			wantErr:       false,
 		},
+		{
+			name: "import aliases use local paths",
+			frontmatter: map[string]any{
+				"on":      "push",
+				"imports": map[string]any{"include.md": "shared/remote-include.md"},
+			},
+			wantToolsJSON: true,
+			wantEngines:   false,
+			wantErr:       false,
+		},
+		{
+			name: "import alias without remote path",
+			frontmatter: map[string]any{
+				"on":      "push",
+				"imports": map[string]any{"include.md": 1},
+			},
+			wantToolsJSON: false,
+			wantEngines:   false,
+			wantErr:       true,
+		},
 		{
 			name: "invalid imports type",
 			frontmatter: map[string]any{
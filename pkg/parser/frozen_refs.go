@@ -0,0 +1,30 @@
+//go:build !js && !wasm
+
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/gitutil"
+)
+
+// ErrRefNotLocked is returned (wrapped) in frozen mode when a floating ref has no recorded commit
+var ErrRefNotLocked = errors.New("ref has no commit recorded in the fetch lock")
+
+// frozenRef returns the commit that ref of owner/repo is frozen to (see FetchConfig.FrozenRefs).
+// While refs are frozen, downloads and ref resolutions use the recorded commit instead of
+// resolving the ref, and fail with ErrRefNotLocked for refs without one. It returns ref
+// unchanged when refs are not frozen or ref is already a full commit SHA.
+func (f *Fetcher) frozenRef(owner, repo, ref string) (string, error) {
+	if f.frozen == nil || (len(ref) == 40 && gitutil.IsHexString(ref)) {
+		return ref, nil
+	}
+	sha, ok := f.frozen[strings.ToLower(owner+"/"+repo+"@"+ref)]
+	if !ok {
+		return "", fmt.Errorf("%s/%s@%s: %w", owner, repo, ref, ErrRefNotLocked)
+	}
+	remoteLog.Printf("Using frozen commit %s for %s/%s@%s", sha, owner, repo, ref)
+	return sha, nil
+}
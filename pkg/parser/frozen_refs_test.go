@@ -0,0 +1,36 @@
+//go:build !integration
+
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetcherFrozenRefs(t *testing.T) {
+	lockedSHA := strings.Repeat("a", 40)
+	f, err := NewFetcher(FetchConfig{FrozenRefs: map[string]string{"Owner/Repo@main": lockedSHA}})
+	require.NoError(t, err, "fetcher should be created")
+
+	sha, err := f.resolveRefToSHA("owner", "repo", "main")
+	require.NoError(t, err, "locked refs should resolve without a lookup")
+	assert.Equal(t, lockedSHA, sha, "ref should resolve to the recorded commit")
+
+	pinned := strings.Repeat("b", 40)
+	sha, err = f.resolveRefToSHA("owner", "repo", pinned)
+	require.NoError(t, err, "commit SHAs should not need a recorded commit")
+	assert.Equal(t, pinned, sha, "commit SHAs should be used as-is")
+
+	_, err = f.resolveRefToSHA("owner", "repo", "develop")
+	require.ErrorIs(t, err, ErrRefNotLocked, "unrecorded refs should not be resolved")
+
+	_, err = f.downloadFile("owner", "repo", "README.md", "develop", 0)
+	require.ErrorIs(t, err, ErrRefNotLocked, "unrecorded refs should not be downloaded")
+
+	ref, err := defaultFetcher().frozenRef("owner", "repo", "develop")
+	require.NoError(t, err, "refs should not be frozen without frozen refs")
+	assert.Equal(t, "develop", ref, "ref should be unchanged without frozen refs")
+}
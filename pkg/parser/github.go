@@ -81,6 +81,11 @@ func GetGitHubToken() (string, error) {
 		return token, nil
 	}
 
+	// Then a token mounted as a file
+	if token, err := readGitHubTokenFile(); err != nil || token != "" {
+		return token, err
+	}
+
 	// Fall back to gh auth token command
 	githubLog.Print("Attempting to get token from gh auth token command")
 	cmd := exec.Command("gh", "auth", "token")
@@ -100,3 +105,40 @@ func GetGitHubToken() (string, error) {
 	githubLog.Print("Successfully retrieved token from gh auth token")
 	return token, nil
 }
+
+// githubTokenFileEnvVars lists, in priority order, the environment variables naming a file that
+// holds the GitHub token, as mounted for Docker and Kubernetes secrets
+var githubTokenFileEnvVars = []string{"GITHUB_TOKEN_FILE", "GH_TOKEN_FILE"}
+
+// readGitHubTokenFile returns the token in the file named by GITHUB_TOKEN_FILE or GH_TOKEN_FILE,
+// with surrounding whitespace trimmed, or "" when neither is set. A file that cannot be read or
+// is empty is an error. The token itself is never logged.
+func readGitHubTokenFile() (string, error) {
+	for _, envVar := range githubTokenFileEnvVars {
+		path := os.Getenv(envVar)
+		if path == "" {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			githubLog.Printf("Failed to read token file from %s", envVar)
+			return "", fmt.Errorf("failed to read GitHub token from %s: %w", envVar, err)
+		}
+		token := strings.TrimSpace(string(content))
+		if token == "" {
+			return "", fmt.Errorf("GitHub token file %s from %s is empty", path, envVar)
+		}
+		githubLog.Printf("Found GitHub token file from %s", envVar)
+		return token, nil
+	}
+	return "", nil
+}
+
+// fileAuthToken returns the token of a GITHUB_TOKEN_FILE or GH_TOKEN_FILE file for API clients,
+// or "" to keep the default resolution. Token environment variables take precedence over files.
+func fileAuthToken() (string, error) {
+	if os.Getenv("GITHUB_TOKEN") != "" || os.Getenv("GH_TOKEN") != "" {
+		return "", nil
+	}
+	return readGitHubTokenFile()
+}
@@ -0,0 +1,99 @@
+//go:build !js && !wasm
+
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// APICallStats counts the GitHub API requests made while fetching remote workflows,
+// includes, and imports
+type APICallStats struct {
+	RefResolutions  int64 // Requests resolving a branch, tag, or short SHA to a commit SHA
+	ContentRequests int64 // Contents API requests: file downloads, directory listings, and path probes
+	Other           int64 // Any other request, excluding rate-limit queries which do not count against the quota
+}
+
+// Total returns the number of API requests of every kind
+func (s APICallStats) Total() int64 {
+	return s.RefResolutions + s.ContentRequests + s.Other
+}
+
+// Sub returns the requests made since an earlier snapshot
+func (s APICallStats) Sub(earlier APICallStats) APICallStats {
+	return APICallStats{
+		RefResolutions:  s.RefResolutions - earlier.RefResolutions,
+		ContentRequests: s.ContentRequests - earlier.ContentRequests,
+		Other:           s.Other - earlier.Other,
+	}
+}
+
+// String formats the counts for display, e.g. "12 GitHub API calls (3 ref resolutions, 9 content requests)"
+func (s APICallStats) String() string {
+	var parts []string
+	for _, count := range []struct {
+		n    int64
+		name string
+	}{
+		{s.RefResolutions, "ref resolution"},
+		{s.ContentRequests, "content request"},
+		{s.Other, "other request"},
+	} {
+		if count.n > 0 {
+			parts = append(parts, pluralizeCount(count.n, count.name))
+		}
+	}
+	summary := pluralizeCount(s.Total(), "GitHub API call")
+	if len(parts) > 0 {
+		summary += " (" + strings.Join(parts, ", ") + ")"
+	}
+	return summary
+}
+
+// pluralizeCount formats n followed by name, adding an "s" unless n is 1
+func pluralizeCount(n int64, name string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, name)
+	}
+	return fmt.Sprintf("%d %ss", n, name)
+}
+
+var (
+	refResolutionCalls  atomic.Int64
+	contentRequestCalls atomic.Int64
+	otherAPICalls       atomic.Int64
+)
+
+// CurrentAPICallStats returns the GitHub API requests made so far by this process.
+// Callers take a snapshot before an operation and use Sub to count the requests it made.
+func CurrentAPICallStats() APICallStats {
+	return APICallStats{
+		RefResolutions:  refResolutionCalls.Load(),
+		ContentRequests: contentRequestCalls.Load(),
+		Other:           otherAPICalls.Load(),
+	}
+}
+
+// recordAPICall counts a GitHub API request by the kind of its endpoint path,
+// e.g. "/repos/owner/repo/contents/README.md" or "/api/v3/repos/owner/repo/commits/main"
+func recordAPICall(path string) {
+	path = strings.TrimPrefix(path, "/api/v3")
+	if path == "/rate_limit" {
+		return
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) >= 4 && segments[0] == "repos" {
+		switch segments[3] {
+		case "commits", "git":
+			refResolutionCalls.Add(1)
+			return
+		case "contents":
+			contentRequestCalls.Add(1)
+			return
+		}
+	}
+	otherAPICalls.Add(1)
+}
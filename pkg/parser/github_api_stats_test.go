@@ -0,0 +1,47 @@
+//go:build !integration
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordAPICall(t *testing.T) {
+	start := CurrentAPICallStats()
+
+	recordAPICall("/repos/owner/repo/commits/main")
+	recordAPICall("/api/v3/repos/owner/repo/git/ref/heads/main")
+	recordAPICall("/repos/owner/repo/contents/workflows/triage.md")
+	recordAPICall("/repos/owner/repo/contents/workflows")
+	recordAPICall("/repos/owner/repo/contents")
+	recordAPICall("/repos/owner/repo")
+	recordAPICall("/rate_limit")
+
+	calls := CurrentAPICallStats().Sub(start)
+	assert.Equal(t, APICallStats{RefResolutions: 2, ContentRequests: 3, Other: 1}, calls, "requests should be counted by endpoint kind")
+	assert.Equal(t, int64(6), calls.Total(), "rate-limit queries should not be counted")
+}
+
+func TestAPICallStatsString(t *testing.T) {
+	tests := []struct {
+		name     string
+		stats    APICallStats
+		expected string
+	}{
+		{name: "no calls", expected: "0 GitHub API calls"},
+		{name: "single call", stats: APICallStats{ContentRequests: 1}, expected: "1 GitHub API call (1 content request)"},
+		{
+			name:     "every kind",
+			stats:    APICallStats{RefResolutions: 3, ContentRequests: 9, Other: 2},
+			expected: "14 GitHub API calls (3 ref resolutions, 9 content requests, 2 other requests)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.stats.String(), "summary mismatch")
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package parser
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// DefaultUserAgent is the User-Agent sent with GitHub API requests until SetDefaultUserAgent
+// records the CLI version
+const DefaultUserAgent = "gh-aw/dev"
+
+// headerNamePattern matches valid HTTP header field names (RFC 9110 tokens)
+var headerNamePattern = regexp.MustCompile("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$")
+
+var defaultUserAgent atomic.Pointer[string]
+
+// SetDefaultUserAgent sets the User-Agent used when a fetch configures none, normally
+// "gh-aw/<version>"
+func SetDefaultUserAgent(ua string) {
+	defaultUserAgent.Store(&ua)
+}
+
+// UserAgent returns the default User-Agent sent with GitHub API requests
+func UserAgent() string {
+	if ua := defaultUserAgent.Load(); ua != nil && *ua != "" {
+		return *ua
+	}
+	return DefaultUserAgent
+}
+
+// ParseRequestHeaders parses "Name: value" header specifications into a map suitable for
+// FetchConfig.RequestHeaders. Authentication is always taken from the GitHub token, so
+// Authorization cannot be set, and the User-Agent is configured separately.
+func ParseRequestHeaders(specs []string) (map[string]string, error) {
+	headers := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		name, value, ok := strings.Cut(spec, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid request header %q: expected Name: value", spec)
+		}
+		name = strings.TrimSpace(name)
+		value = strings.TrimSpace(value)
+		if err := validateRequestHeader(name, value); err != nil {
+			return nil, err
+		}
+		headers[http.CanonicalHeaderKey(name)] = value
+	}
+	return headers, nil
+}
+
+// validateRequestHeader rejects malformed headers and headers that are managed elsewhere
+func validateRequestHeader(name, value string) error {
+	if !headerNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid request header name %q", name)
+	}
+	if strings.ContainsAny(value, "\r\n") {
+		return fmt.Errorf("invalid value for request header %s: must not contain line breaks", name)
+	}
+	switch http.CanonicalHeaderKey(name) {
+	case "Authorization":
+		return fmt.Errorf("request header %s cannot be set: authentication uses the GitHub token", name)
+	case "User-Agent":
+		return fmt.Errorf("request header %s cannot be set: use the user agent option instead", name)
+	}
+	return nil
+}
+
+// apiRequestHeaders returns the headers added to a GitHub API request: the given extra headers,
+// and the default User-Agent unless they set one
+func apiRequestHeaders(extra map[string]string) map[string]string {
+	headers := make(map[string]string, len(extra)+1)
+	maps.Copy(headers, extra)
+	if _, ok := headers["User-Agent"]; !ok {
+		headers["User-Agent"] = UserAgent()
+	}
+	return headers
+}
+
+// ghAPIHeaderArgs returns the "gh api" arguments that send apiRequestHeaders(extra), in a stable order
+func ghAPIHeaderArgs(extra map[string]string) []string {
+	headers := apiRequestHeaders(extra)
+	args := make([]string, 0, 2*len(headers))
+	for _, name := range slices.Sorted(maps.Keys(headers)) {
+		args = append(args, "-H", name+": "+headers[name])
+	}
+	return args
+}
@@ -0,0 +1,92 @@
+//go:build !integration
+
+package parser
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingTransport captures each request and answers it with an empty JSON object
+type recordingTransport struct {
+	requests []*http.Request
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests = append(t.requests, req)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader("{}")),
+		Request:    req,
+	}, nil
+}
+
+func TestUserAgent(t *testing.T) {
+	assert.True(t, strings.HasPrefix(UserAgent(), "gh-aw/"), "default User-Agent should identify gh-aw")
+	assert.Equal(t, UserAgent(), apiRequestHeaders(nil)["User-Agent"], "default User-Agent should be sent without a configured one")
+
+	headers := apiRequestHeaders(map[string]string{"User-Agent": "corp-fetcher/1.0"})
+	assert.Equal(t, "corp-fetcher/1.0", headers["User-Agent"], "configured User-Agent should be used")
+}
+
+func TestParseRequestHeaders(t *testing.T) {
+	headers, err := ParseRequestHeaders([]string{"x-proxy-tenant: team-a", "X-Trace:  abc "})
+	require.NoError(t, err, "valid headers should parse")
+	assert.Equal(t, map[string]string{"X-Proxy-Tenant": "team-a", "X-Trace": "abc"}, headers, "names should be canonical and values trimmed")
+
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr string
+	}{
+		{name: "missing colon", spec: "X-Proxy-Tenant", wantErr: "expected Name: value"},
+		{name: "invalid name", spec: "X Proxy: a", wantErr: "invalid request header name"},
+		{name: "authorization", spec: "authorization: token abc", wantErr: "GitHub token"},
+		{name: "user agent", spec: "User-Agent: other", wantErr: "user agent option"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRequestHeaders([]string{tt.spec})
+			require.Error(t, err, "header should be rejected")
+			assert.Contains(t, err.Error(), tt.wantErr, "error should explain why")
+		})
+	}
+
+	_, err = NewFetcher(FetchConfig{RequestHeaders: map[string]string{"X-Trace": "a\r\nAuthorization: token abc"}})
+	require.Error(t, err, "line breaks should be rejected")
+}
+
+func TestRESTClientSendsConfiguredHeaders(t *testing.T) {
+	f, err := NewFetcher(FetchConfig{
+		UserAgent:      "corp-fetcher/1.0",
+		RequestHeaders: map[string]string{"x-proxy-tenant": "team-a"},
+	})
+	require.NoError(t, err, "headers should be accepted")
+
+	transport := &recordingTransport{}
+	client, err := api.NewRESTClient(api.ClientOptions{
+		Host:      "github.com",
+		AuthToken: "secret-token",
+		Headers:   apiRequestHeaders(f.headers),
+		Transport: transport,
+	})
+	require.NoError(t, err, "client should be created")
+
+	var response map[string]any
+	require.NoError(t, client.Get("repos/owner/repo", &response), "request should succeed")
+	require.Len(t, transport.requests, 1, "one request should be sent")
+
+	header := transport.requests[0].Header
+	assert.Equal(t, "corp-fetcher/1.0", header.Get("User-Agent"), "configured User-Agent should be sent")
+	assert.Equal(t, "team-a", header.Get("X-Proxy-Tenant"), "extra header should be sent")
+	assert.Equal(t, "token secret-token", header.Get("Authorization"), "token should still authenticate the request")
+
+	assert.Equal(t, []string{"-H", "User-Agent: corp-fetcher/1.0", "-H", "X-Proxy-Tenant: team-a"}, ghAPIHeaderArgs(f.headers), "gh api should receive the same headers")
+}
@@ -0,0 +1,209 @@
+//go:build !js && !wasm
+
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var rateLimitLog = logger.New("parser:github_rate_limit")
+
+// lowRateLimitThreshold is the number of remaining requests at or below which
+// the rate limit is considered close to exhaustion
+const lowRateLimitThreshold = 10
+
+// ErrRateLimited is returned (wrapped) when a GitHub API request fails because
+// the API rate limit has been exhausted
+var ErrRateLimited = errors.New("GitHub API rate limit exceeded")
+
+// RateLimitState holds the GitHub API rate-limit headers observed on a response
+type RateLimitState struct {
+	Limit     int       // X-RateLimit-Limit
+	Remaining int       // X-RateLimit-Remaining
+	Reset     time.Time // X-RateLimit-Reset
+}
+
+// IsLow reports whether the remaining quota is at or below lowRateLimitThreshold
+func (s RateLimitState) IsLow() bool {
+	return s.Remaining <= lowRateLimitThreshold
+}
+
+// String formats the state for display, e.g. "42/5000 requests remaining, resets at 15:04:05 MST (in 12m0s)"
+func (s RateLimitState) String() string {
+	return fmt.Sprintf("%d/%d requests remaining, %s", s.Remaining, s.Limit, formatRateLimitReset(s.Reset))
+}
+
+// formatRateLimitReset describes when the rate limit resets
+func formatRateLimitReset(reset time.Time) string {
+	if reset.IsZero() {
+		return "reset time unknown"
+	}
+	wait := max(time.Until(reset).Round(time.Second), 0)
+	return fmt.Sprintf("resets at %s (in %s)", reset.Local().Format("15:04:05 MST"), wait)
+}
+
+var (
+	rateLimitMu   sync.Mutex
+	lastRateLimit *RateLimitState
+)
+
+// LastRateLimitState returns the most recently observed GitHub API rate-limit state.
+// The boolean is false when no GitHub API response carrying rate-limit headers has been seen.
+func LastRateLimitState() (RateLimitState, bool) {
+	rateLimitMu.Lock()
+	defer rateLimitMu.Unlock()
+	if lastRateLimit == nil {
+		return RateLimitState{}, false
+	}
+	return *lastRateLimit, true
+}
+
+// parseRateLimitHeaders extracts the rate-limit state from GitHub API response headers
+func parseRateLimitHeaders(header http.Header) (RateLimitState, bool) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return RateLimitState{}, false
+	}
+
+	state := RateLimitState{Remaining: remaining}
+	if limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit")); err == nil {
+		state.Limit = limit
+	}
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		state.Reset = time.Unix(reset, 0)
+	}
+	return state, true
+}
+
+// recordRateLimitHeaders stores the rate-limit state carried by a response, if any
+func recordRateLimitHeaders(header http.Header) {
+	state, ok := parseRateLimitHeaders(header)
+	if !ok {
+		return
+	}
+
+	rateLimitMu.Lock()
+	lastRateLimit = &state
+	rateLimitMu.Unlock()
+
+	rateLimitLog.Printf("GitHub API rate limit: %s", state)
+	if state.IsLow() {
+		rateLimitLog.Printf("GitHub API rate limit is low: %d requests remaining", state.Remaining)
+	}
+}
+
+// rateLimitTransport records rate-limit headers from every GitHub API response
+// and counts the requests for APICallStats
+type rateLimitTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recordAPICall(req.URL.Path)
+	resp, err := t.base.RoundTrip(req)
+	if resp != nil {
+		recordRateLimitHeaders(resp.Header)
+	}
+	return resp, err
+}
+
+// newRESTClient creates a GitHub REST client that records rate-limit headers and sends the
+// given extra headers and the User-Agent (see apiRequestHeaders). Host and token resolution match
+// api.DefaultRESTClient, except that a token file (GITHUB_TOKEN_FILE or GH_TOKEN_FILE) is used
+// before the gh CLI config.
+func newRESTClient(headers map[string]string) (*api.RESTClient, error) {
+	token, err := fileAuthToken()
+	if err != nil {
+		return nil, err
+	}
+	return api.NewRESTClient(api.ClientOptions{
+		AuthToken: token,
+		Headers:   apiRequestHeaders(headers),
+		Transport: &rateLimitTransport{base: http.DefaultTransport},
+	})
+}
+
+// isRateLimitMessage checks if an error message indicates an exhausted rate limit
+func isRateLimitMessage(errMsg string) bool {
+	return strings.Contains(strings.ToLower(errMsg), "rate limit")
+}
+
+// isRateLimitError checks if a GitHub API error was caused by an exhausted rate limit.
+// GitHub answers with 429, or with 403 and X-RateLimit-Remaining: 0 (primary limit)
+// or a "rate limit" message (secondary limit).
+func isRateLimitError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var httpErr *api.HTTPError
+	if errors.As(err, &httpErr) {
+		if httpErr.StatusCode == http.StatusTooManyRequests {
+			return true
+		}
+		if httpErr.StatusCode == http.StatusForbidden {
+			return httpErr.Headers.Get("X-RateLimit-Remaining") == "0" || isRateLimitMessage(httpErr.Message)
+		}
+		return false
+	}
+	return isRateLimitMessage(err.Error())
+}
+
+// newRateLimitError wraps err in a RateLimitError with the time at which the limit resets.
+// The reset time comes from the failed response when available, otherwise from the last observed state.
+func newRateLimitError(err error) error {
+	var state RateLimitState
+	var known bool
+
+	var httpErr *api.HTTPError
+	if errors.As(err, &httpErr) {
+		state, known = parseRateLimitHeaders(httpErr.Headers)
+	}
+	if !known {
+		state, known = LastRateLimitState()
+	}
+
+	reset := "reset time unknown"
+	if known {
+		reset = formatRateLimitReset(state.Reset)
+	}
+	rateLimitLog.Printf("GitHub API rate limit exceeded: %s", reset)
+	return &RateLimitError{Reset: reset, Err: err}
+}
+
+// refreshRateLimitState queries the rate_limit endpoint, which does not count against
+// the quota, so that the reset time is known after a failure outside the REST client
+func refreshRateLimitState() {
+	client, err := newRESTClient(nil)
+	if err != nil {
+		rateLimitLog.Printf("Failed to create REST client for rate limit query: %v", err)
+		return
+	}
+	var response struct {
+		Resources struct {
+			Core struct {
+				Limit     int   `json:"limit"`
+				Remaining int   `json:"remaining"`
+				Reset     int64 `json:"reset"`
+			} `json:"core"`
+		} `json:"resources"`
+	}
+	if err := client.Get("rate_limit", &response); err != nil {
+		rateLimitLog.Printf("Failed to query rate limit: %v", err)
+		return
+	}
+
+	core := response.Resources.Core
+	rateLimitMu.Lock()
+	lastRateLimit = &RateLimitState{Limit: core.Limit, Remaining: core.Remaining, Reset: time.Unix(core.Reset, 0)}
+	rateLimitMu.Unlock()
+}
@@ -0,0 +1,151 @@
+//go:build !integration
+
+package parser
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resetRateLimitState clears the recorded rate-limit state for the duration of a test
+func resetRateLimitState(t *testing.T) {
+	t.Helper()
+	rateLimitMu.Lock()
+	lastRateLimit = nil
+	rateLimitMu.Unlock()
+	t.Cleanup(func() {
+		rateLimitMu.Lock()
+		lastRateLimit = nil
+		rateLimitMu.Unlock()
+	})
+}
+
+func rateLimitHeader(limit, remaining int, reset time.Time) http.Header {
+	header := http.Header{}
+	header.Set("X-RateLimit-Limit", strconv.Itoa(limit))
+	header.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	header.Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+	return header
+}
+
+func TestParseRateLimitHeaders(t *testing.T) {
+	reset := time.Unix(1893456000, 0)
+
+	state, ok := parseRateLimitHeaders(rateLimitHeader(5000, 42, reset))
+	require.True(t, ok, "headers with X-RateLimit-Remaining should parse")
+	assert.Equal(t, 5000, state.Limit, "limit should be parsed")
+	assert.Equal(t, 42, state.Remaining, "remaining should be parsed")
+	assert.True(t, reset.Equal(state.Reset), "reset should be parsed from unix seconds")
+	assert.False(t, state.IsLow(), "42 remaining should not be low")
+
+	_, ok = parseRateLimitHeaders(http.Header{})
+	assert.False(t, ok, "headers without rate-limit information should not parse")
+}
+
+func TestRateLimitTransportRecordsHeaders(t *testing.T) {
+	resetRateLimitState(t)
+
+	reset := time.Now().Add(10 * time.Minute)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for key, values := range rateLimitHeader(60, 3, reset) {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	_, ok := LastRateLimitState()
+	require.False(t, ok, "no state should be recorded before any request")
+
+	client := &http.Client{Transport: &rateLimitTransport{base: http.DefaultTransport}}
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err, "request should succeed")
+	resp.Body.Close()
+
+	state, ok := LastRateLimitState()
+	require.True(t, ok, "state should be recorded from the response")
+	assert.Equal(t, 3, state.Remaining, "remaining should match the response header")
+	assert.True(t, state.IsLow(), "3 remaining should be low")
+	assert.Contains(t, state.String(), "3/60 requests remaining", "string should show the remaining quota")
+	assert.Contains(t, state.String(), "resets at", "string should show the reset time")
+}
+
+func TestIsRateLimitError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "nil error",
+			err:      nil,
+			expected: false,
+		},
+		{
+			name:     "429 response",
+			err:      &api.HTTPError{StatusCode: http.StatusTooManyRequests},
+			expected: true,
+		},
+		{
+			name: "403 with exhausted primary limit",
+			err: &api.HTTPError{
+				StatusCode: http.StatusForbidden,
+				Headers:    http.Header{"X-Ratelimit-Remaining": []string{"0"}},
+			},
+			expected: true,
+		},
+		{
+			name:     "403 secondary rate limit",
+			err:      &api.HTTPError{StatusCode: http.StatusForbidden, Message: "You have exceeded a secondary rate limit"},
+			expected: true,
+		},
+		{
+			name:     "403 permission error",
+			err:      &api.HTTPError{StatusCode: http.StatusForbidden, Message: "Resource not accessible by integration"},
+			expected: false,
+		},
+		{
+			name:     "404 response",
+			err:      &api.HTTPError{StatusCode: http.StatusNotFound, Message: "Not Found"},
+			expected: false,
+		},
+		{
+			name:     "gh CLI rate limit message",
+			err:      errors.New("gh: API rate limit exceeded for user ID 1. (HTTP 403)"),
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isRateLimitError(tt.err), "isRateLimitError mismatch")
+		})
+	}
+}
+
+func TestNewRateLimitError(t *testing.T) {
+	resetRateLimitState(t)
+
+	reset := time.Now().Add(30 * time.Minute)
+	apiErr := &api.HTTPError{
+		StatusCode: http.StatusForbidden,
+		Headers:    rateLimitHeader(5000, 0, reset),
+		Message:    "API rate limit exceeded",
+	}
+
+	err := newRateLimitError(apiErr)
+	require.ErrorIs(t, err, ErrRateLimited, "error should wrap the rate-limit sentinel")
+	assert.ErrorAs(t, err, new(*api.HTTPError), "error should keep the original API error")
+	assert.Contains(t, err.Error(), "resets at "+reset.Local().Format("15:04:05 MST"), "error should include the reset time")
+
+	err = newRateLimitError(errors.New("API rate limit exceeded"))
+	assert.Contains(t, err.Error(), "reset time unknown", "error without headers or state should say the reset is unknown")
+}
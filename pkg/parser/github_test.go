@@ -0,0 +1,75 @@
+//go:build !integration && !js && !wasm
+
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetGitHubTokenFromFile(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("  file-token\n"), 0600), "token file should be written")
+	otherFile := filepath.Join(dir, "other")
+	require.NoError(t, os.WriteFile(otherFile, []byte("other-token"), 0600), "token file should be written")
+
+	t.Run("file is read when no token variable is set", func(t *testing.T) {
+		t.Setenv("GITHUB_TOKEN", "")
+		t.Setenv("GH_TOKEN", "")
+		t.Setenv("GITHUB_TOKEN_FILE", "")
+		t.Setenv("GH_TOKEN_FILE", tokenFile)
+
+		token, err := GetGitHubToken()
+		require.NoError(t, err, "token should be read from the file")
+		assert.Equal(t, "file-token", token, "whitespace should be trimmed")
+
+		authToken, err := fileAuthToken()
+		require.NoError(t, err, "API client token should be read from the file")
+		assert.Equal(t, "file-token", authToken, "API clients should use the file token")
+	})
+
+	t.Run("GITHUB_TOKEN_FILE takes precedence", func(t *testing.T) {
+		t.Setenv("GITHUB_TOKEN", "")
+		t.Setenv("GH_TOKEN", "")
+		t.Setenv("GITHUB_TOKEN_FILE", otherFile)
+		t.Setenv("GH_TOKEN_FILE", tokenFile)
+
+		token, err := GetGitHubToken()
+		require.NoError(t, err, "token should be read from the file")
+		assert.Equal(t, "other-token", token, "GITHUB_TOKEN_FILE should be checked first")
+	})
+
+	t.Run("token variables take precedence over files", func(t *testing.T) {
+		t.Setenv("GITHUB_TOKEN", "env-token")
+		t.Setenv("GH_TOKEN", "")
+		t.Setenv("GITHUB_TOKEN_FILE", tokenFile)
+
+		token, err := GetGitHubToken()
+		require.NoError(t, err, "token should come from the environment")
+		assert.Equal(t, "env-token", token, "GITHUB_TOKEN should win over a token file")
+
+		authToken, err := fileAuthToken()
+		require.NoError(t, err, "API clients should keep the default resolution")
+		assert.Empty(t, authToken, "API clients should not override the environment token")
+	})
+
+	t.Run("unreadable or empty files are errors", func(t *testing.T) {
+		emptyFile := filepath.Join(dir, "empty")
+		require.NoError(t, os.WriteFile(emptyFile, []byte(" \n"), 0600), "token file should be written")
+		t.Setenv("GITHUB_TOKEN", "")
+		t.Setenv("GH_TOKEN", "")
+		t.Setenv("GH_TOKEN_FILE", "")
+
+		for _, path := range []string{filepath.Join(dir, "missing"), emptyFile} {
+			t.Setenv("GITHUB_TOKEN_FILE", path)
+			_, err := GetGitHubToken()
+			require.Error(t, err, "%s should be rejected", path)
+			assert.Contains(t, err.Error(), "GITHUB_TOKEN_FILE", "error should name the variable")
+		}
+	})
+}
@@ -10,11 +10,16 @@ import (
 var importDirectiveLog = logger.New("parser:import_directive")
 
 // IncludeDirectivePattern matches @include, @import (deprecated), or {{#import (new) directives
-// The colon after #import is optional and ignored if present
-var IncludeDirectivePattern = regexp.MustCompile(`^(?:@(?:include|import)(\?)?\s+(.+)|{{#import(\?)?\s*:?\s*(.+?)\s*}})$`)
+// The colon after #import is optional and ignored if present. An optional bracketed condition
+// may follow the keyword, e.g. @include[engine=copilot] or {{#import[engine=claude] ...}}
+var IncludeDirectivePattern = regexp.MustCompile(`^(?:@(?:include|import)(\?)?(?:\[([^\]]*)\])?\s+(.+)|{{#import(\?)?(?:\[([^\]]*)\])?\s*:?\s*(.+?)\s*}})$`)
 
 // LegacyIncludeDirectivePattern matches only the deprecated @include and @import directives
-var LegacyIncludeDirectivePattern = regexp.MustCompile(`^@(?:include|import)(\?)?\s+(.+)$`)
+var LegacyIncludeDirectivePattern = regexp.MustCompile(`^@(?:include|import)(\?)?(?:\[([^\]]*)\])?\s+(.+)$`)
+
+// includeTargetPattern matches a local target path override at the end of an include path,
+// e.g. "shared/tools.md -> prompts/tools.md"
+var includeTargetPattern = regexp.MustCompile(`^(.*?)\s+->\s+(\S.*)$`)
 
 // ImportDirectiveMatch holds the parsed components of an import directive
 type ImportDirectiveMatch struct {
@@ -22,6 +27,23 @@ type ImportDirectiveMatch struct {
 	Path       string
 	IsLegacy   bool
 	Original   string
+	Condition  string // Bracketed include condition without brackets, e.g. "engine=copilot"
+	Target     string // Local path the include is saved to by 'gh aw add', empty for the default
+}
+
+// SplitIncludeTarget removes the local target override from an include path and returns the
+// path and the target (empty when there is no override):
+//
+//	owner/repo/shared/tools.md@v1#Tools -> prompts/tools.md
+//
+// The target only controls where 'gh aw add' saves the fetched file; compilation always
+// resolves the include path itself.
+func SplitIncludeTarget(includePath string) (string, string) {
+	matches := includeTargetPattern.FindStringSubmatch(includePath)
+	if matches == nil {
+		return includePath, ""
+	}
+	return strings.TrimSpace(matches[1]), strings.TrimSpace(matches[2])
 }
 
 // ParseImportDirective parses an import directive and returns its components
@@ -39,26 +61,31 @@ func ParseImportDirective(line string) *ImportDirectiveMatch {
 	importDirectiveLog.Printf("Parsing import directive: legacy=%t, line=%s", isLegacy, trimmedLine)
 
 	var isOptional bool
-	var path string
+	var path, condition string
 
 	if isLegacy {
 		// Legacy syntax: @include? path or @import? path
-		// Group 1: optional marker, Group 2: path
+		// Group 1: optional marker, Group 2: condition, Group 3: path
 		isOptional = matches[1] == "?"
-		path = strings.TrimSpace(matches[2])
+		condition = strings.TrimSpace(matches[2])
+		path = strings.TrimSpace(matches[3])
 	} else {
 		// New syntax: {{#import?: path}} or {{#import: path}} (colon is optional)
-		// Group 3: optional marker, Group 4: path
-		isOptional = matches[3] == "?"
-		path = strings.TrimSpace(matches[4])
+		// Group 4: optional marker, Group 5: condition, Group 6: path
+		isOptional = matches[4] == "?"
+		condition = strings.TrimSpace(matches[5])
+		path = strings.TrimSpace(matches[6])
 	}
+	path, target := SplitIncludeTarget(path)
 
 	match := &ImportDirectiveMatch{
 		IsOptional: isOptional,
 		Path:       path,
 		IsLegacy:   isLegacy,
 		Original:   trimmedLine,
+		Condition:  condition,
+		Target:     target,
 	}
-	importDirectiveLog.Printf("Parsed import directive: path=%s, optional=%t, legacy=%t", path, isOptional, isLegacy)
+	importDirectiveLog.Printf("Parsed import directive: path=%s, target=%s, optional=%t, legacy=%t", path, target, isOptional, isLegacy)
 	return match
 }
@@ -115,7 +115,8 @@ type importQueueItem struct {
 // For example, "elastic/ai-github-actions/gh-agent-workflows/gh-aw-workflows/file.md@main"
 // produces BasePath="gh-agent-workflows" so nested imports resolve relative to that directory.
 func parseRemoteOrigin(spec string) *remoteImportOrigin {
-	// Remove section reference if present
+	// Remove the checksum pin and section reference if present
+	spec, _ = SplitIncludeIntegrity(spec)
 	cleanSpec := spec
 	if before, _, ok := strings.Cut(spec, "#"); ok {
 		cleanSpec = before
@@ -224,8 +225,16 @@ func processImportsFromFrontmatterWithManifestAndSource(frontmatter map[string]a
 		for _, s := range v {
 			importSpecs = append(importSpecs, ImportSpec{Path: s})
 		}
+	case map[string]any:
+		// Alias form: the workflow imports the local paths
+		for _, localPath := range slices.Sorted(maps.Keys(v)) {
+			if _, ok := v[localPath].(string); !ok {
+				return nil, fmt.Errorf("import alias '%s' must map to a remote path string", localPath)
+			}
+			importSpecs = append(importSpecs, ImportSpec{Path: localPath})
+		}
 	default:
-		return nil, errors.New("imports field must be an array of strings or objects")
+		return nil, errors.New("imports field must be an array of strings or objects, or a map of local paths to remote paths")
 	}
 
 	if len(importSpecs) == 0 {
@@ -407,7 +416,7 @@ func processImportsFromFrontmatterWithManifestAndSource(frontmatter map[string]a
 				log.Printf("Agent file has inputs - will be inlined instead of runtime-imported")
 
 				// For agent files, extract markdown content (only when inputs are present)
-				markdownContent, err := processIncludedFileWithVisited(item.fullPath, item.sectionName, false, visited)
+				markdownContent, err := processIncludedFileWithVisited(item.fullPath, item.sectionName, false, "", nil, visited)
 				if err != nil {
 					return nil, fmt.Errorf("failed to process markdown from agent file '%s': %w", item.fullPath, err)
 				}
@@ -498,6 +507,10 @@ func processImportsFromFrontmatterWithManifestAndSource(frontmatter map[string]a
 					}
 				case []string:
 					nestedImports = v
+				case map[string]any:
+					// Alias form: files fetched from a remote repository import the remote
+					// paths, since the local copies only exist where they were added
+					nestedImports = importAliasPaths(v, item.remoteOrigin != nil)
 				}
 
 				// Add nested imports to queue (BFS: append to end)
@@ -588,7 +601,7 @@ func processImportsFromFrontmatterWithManifestAndSource(frontmatter map[string]a
 		}
 
 		// Extract tools from imported file
-		toolsContent, err := processIncludedFileWithVisited(item.fullPath, item.sectionName, true, visited)
+		toolsContent, err := processIncludedFileWithVisited(item.fullPath, item.sectionName, true, "", nil, visited)
 		if err != nil {
 			return nil, fmt.Errorf("failed to process imported file '%s': %w", item.fullPath, err)
 		}
@@ -601,8 +614,8 @@ func processImportsFromFrontmatterWithManifestAndSource(frontmatter map[string]a
 		if idx := strings.Index(item.fullPath, "/.github/"); idx >= 0 {
 			importRelPath = item.fullPath[idx+1:] // +1 to skip the leading slash
 		} else {
-			// For files not under .github/, use the original import path
-			importRelPath = item.importPath
+			// For files not under .github/, use the original import path without its checksum pin
+			importRelPath, _ = SplitIncludeIntegrity(item.importPath)
 		}
 
 		if len(item.inputs) == 0 {
@@ -614,7 +627,7 @@ func processImportsFromFrontmatterWithManifestAndSource(frontmatter map[string]a
 			log.Printf("Import %s has inputs - will be inlined for compile-time substitution", importRelPath)
 
 			// Extract markdown content from imported file (only for imports with inputs)
-			markdownContent, err := processIncludedFileWithVisited(item.fullPath, item.sectionName, false, visited)
+			markdownContent, err := processIncludedFileWithVisited(item.fullPath, item.sectionName, false, "", nil, visited)
 			if err != nil {
 				return nil, fmt.Errorf("failed to process markdown from imported file '%s': %w", item.fullPath, err)
 			}
@@ -1098,7 +1111,28 @@ func extractImportPaths(frontmatter map[string]any) []string {
 		}
 	case []string:
 		imports = v
+	case map[string]any:
+		imports = importAliasPaths(v, false)
 	}
 
 	return imports
 }
+
+// importAliasPaths returns the paths of an imports field in alias form, which maps local paths
+// to the remote paths they were fetched from, in local path order. It returns the remote paths
+// when remote is true and the local paths otherwise.
+func importAliasPaths(aliases map[string]any, remote bool) []string {
+	var paths []string
+	for _, localPath := range slices.Sorted(maps.Keys(aliases)) {
+		remotePath, ok := aliases[localPath].(string)
+		if !ok {
+			continue
+		}
+		if remote {
+			paths = append(paths, remotePath)
+		} else {
+			paths = append(paths, localPath)
+		}
+	}
+	return paths
+}
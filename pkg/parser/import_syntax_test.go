@@ -12,12 +12,14 @@ import (
 
 func TestParseImportDirective(t *testing.T) {
 	tests := []struct {
-		name         string
-		input        string
-		wantMatch    bool
-		wantPath     string
-		wantOptional bool
-		wantLegacy   bool
+		name          string
+		input         string
+		wantMatch     bool
+		wantPath      string
+		wantOptional  bool
+		wantLegacy    bool
+		wantCondition string
+		wantTarget    string
 	}{
 		// New syntax tests
 		{
@@ -126,6 +128,53 @@ func TestParseImportDirective(t *testing.T) {
 			wantOptional: false,
 			wantLegacy:   true,
 		},
+		// Conditional include tests
+		{
+			name:          "legacy - @include with engine condition",
+			input:         "@include[engine=copilot] shared/copilot-tips.md",
+			wantMatch:     true,
+			wantPath:      "shared/copilot-tips.md",
+			wantOptional:  false,
+			wantLegacy:    true,
+			wantCondition: "engine=copilot",
+		},
+		{
+			name:          "legacy - optional @include with engine condition",
+			input:         "@include?[engine=claude] shared/claude-tips.md#Usage",
+			wantMatch:     true,
+			wantPath:      "shared/claude-tips.md#Usage",
+			wantOptional:  true,
+			wantLegacy:    true,
+			wantCondition: "engine=claude",
+		},
+		{
+			name:          "new syntax - with engine condition",
+			input:         "{{#import?[engine=codex]: shared/codex-tips.md}}",
+			wantMatch:     true,
+			wantPath:      "shared/codex-tips.md",
+			wantOptional:  true,
+			wantLegacy:    false,
+			wantCondition: "engine=codex",
+		},
+		// Target override tests
+		{
+			name:         "legacy - @include with target override",
+			input:        "@include owner/repo/shared/tools.md@v1#Tools -> prompts/tools.md",
+			wantMatch:    true,
+			wantPath:     "owner/repo/shared/tools.md@v1#Tools",
+			wantOptional: false,
+			wantLegacy:   true,
+			wantTarget:   "prompts/tools.md",
+		},
+		{
+			name:         "new syntax - optional import with target override",
+			input:        "{{#import? shared/tools.md   ->   local/tools.md}}",
+			wantMatch:    true,
+			wantPath:     "shared/tools.md",
+			wantOptional: true,
+			wantLegacy:   false,
+			wantTarget:   "local/tools.md",
+		},
 		// Non-matching tests
 		{
 			name:      "no match - regular text",
@@ -161,6 +210,14 @@ func TestParseImportDirective(t *testing.T) {
 					t.Errorf("ParseImportDirective() IsLegacy = %v, want %v", result.IsLegacy, tt.wantLegacy)
 				}
 
+				if result.Condition != tt.wantCondition {
+					t.Errorf("ParseImportDirective() Condition = %q, want %q", result.Condition, tt.wantCondition)
+				}
+
+				if result.Target != tt.wantTarget {
+					t.Errorf("ParseImportDirective() Target = %q, want %q", result.Target, tt.wantTarget)
+				}
+
 				if result.Original != strings.TrimSpace(tt.input) {
 					t.Errorf("ParseImportDirective() Original = %q, want %q", result.Original, strings.TrimSpace(tt.input))
 				}
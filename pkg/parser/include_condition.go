@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var includeConditionLog = logger.New("parser:include_condition")
+
+// ParseIncludeEngineCondition parses an include condition of the form "engine=<id>[,<id>...]"
+// and returns the listed engine IDs
+func ParseIncludeEngineCondition(condition string) ([]string, error) {
+	key, value, ok := strings.Cut(condition, "=")
+	if !ok || strings.TrimSpace(key) != "engine" {
+		return nil, fmt.Errorf("unsupported include condition '%s': expected 'engine=<id>'", condition)
+	}
+
+	var engines []string
+	for engine := range strings.SplitSeq(value, ",") {
+		engine = strings.TrimSpace(engine)
+		if engine == "" {
+			continue
+		}
+		engines = append(engines, engine)
+	}
+	if len(engines) == 0 {
+		return nil, errors.New("include condition 'engine=' requires at least one engine ID")
+	}
+	return engines, nil
+}
+
+// includeConditionApplies reports whether a conditional include directive applies to the
+// active engine. Engine IDs in the condition that are not in knownEngines produce a warning
+// (unless quiet or knownEngines is empty) and never match. Without an active engine,
+// conditional includes are skipped.
+func includeConditionApplies(directive *ImportDirectiveMatch, engine string, knownEngines []string, quiet bool) (bool, error) {
+	engines, err := ParseIncludeEngineCondition(directive.Condition)
+	if err != nil {
+		return false, fmt.Errorf("invalid include directive %q: %w", directive.Original, err)
+	}
+
+	if !quiet && len(knownEngines) > 0 {
+		for _, id := range engines {
+			if !slices.Contains(knownEngines, id) {
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Unknown engine '%s' in include condition %q. Known engines: %s",
+					id, directive.Original, strings.Join(slices.Sorted(slices.Values(knownEngines)), ", "))))
+			}
+		}
+	}
+
+	if engine == "" {
+		includeConditionLog.Printf("No active engine, skipping conditional include: %s", directive.Original)
+		return false, nil
+	}
+	applies := slices.Contains(engines, engine)
+	includeConditionLog.Printf("Include condition %s for engine %s: applies=%t", directive.Condition, engine, applies)
+	return applies, nil
+}
@@ -0,0 +1,122 @@
+//go:build !integration
+
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+)
+
+func TestParseIncludeEngineCondition(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition string
+		want      []string
+		wantErr   bool
+	}{
+		{name: "single engine", condition: "engine=copilot", want: []string{"copilot"}},
+		{name: "multiple engines", condition: "engine=claude, codex", want: []string{"claude", "codex"}},
+		{name: "unsupported key", condition: "os=linux", wantErr: true},
+		{name: "missing value", condition: "engine=", wantErr: true},
+		{name: "no key", condition: "copilot", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseIncludeEngineCondition(tt.condition)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseIncludeEngineCondition(%q) = %v, want error", tt.condition, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseIncludeEngineCondition(%q) error = %v", tt.condition, err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ParseIncludeEngineCondition(%q) = %v, want %v", tt.condition, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandIncludesWithManifestForEngine(t *testing.T) {
+	tempDir := testutil.TempDir(t, "test-*")
+	files := map[string]string{
+		"copilot.md": "Copilot tips.\n",
+		"claude.md":  "Claude tips.\n",
+		"common.md":  "Common tips.\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	content := `# Workflow
+@include common.md
+@include[engine=copilot] copilot.md
+{{#import[engine=claude,codex] claude.md}}
+@include[engine=unknown-engine] copilot.md
+`
+
+	tests := []struct {
+		name        string
+		engine      string
+		wantContain []string
+		wantOmit    []string
+	}{
+		{
+			name:        "copilot engine",
+			engine:      "copilot",
+			wantContain: []string{"Common tips.", "Copilot tips."},
+			wantOmit:    []string{"Claude tips."},
+		},
+		{
+			name:        "codex engine matches engine list",
+			engine:      "codex",
+			wantContain: []string{"Common tips.", "Claude tips."},
+			wantOmit:    []string{"Copilot tips."},
+		},
+		{
+			name:        "no engine skips conditional includes",
+			engine:      "",
+			wantContain: []string{"Common tips."},
+			wantOmit:    []string{"Copilot tips.", "Claude tips."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := ExpandIncludesWithManifestForEngine(content, tempDir, false, tt.engine, []string{"claude", "codex", "copilot"})
+			if err != nil {
+				t.Fatalf("ExpandIncludesWithManifestForEngine() error = %v", err)
+			}
+			for _, want := range tt.wantContain {
+				if !strings.Contains(got, want) {
+					t.Errorf("Expected expanded content to contain %q, got:\n%s", want, got)
+				}
+			}
+			for _, omit := range tt.wantOmit {
+				if strings.Contains(got, omit) {
+					t.Errorf("Expected expanded content not to contain %q, got:\n%s", omit, got)
+				}
+			}
+		})
+	}
+
+	t.Run("unsupported condition fails", func(t *testing.T) {
+		_, _, err := ExpandIncludesWithManifestForEngine("@include[os=linux] common.md\n", tempDir, false, "copilot", nil)
+		if err == nil {
+			t.Fatal("Expected error for unsupported include condition")
+		}
+		if !strings.Contains(err.Error(), "unsupported include condition") {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+}
@@ -3,6 +3,7 @@ package parser
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -15,9 +16,17 @@ func ExpandIncludes(content, baseDir string, extractTools bool) (string, error)
 	return expandedContent, err
 }
 
-// ExpandIncludesWithManifest recursively expands @include and @import directives and returns list of included files
+// ExpandIncludesWithManifest recursively expands @include and @import directives and returns list of included files.
+// Conditional includes (e.g. @include[engine=copilot]) are skipped; use ExpandIncludesWithManifestForEngine to honor them.
 func ExpandIncludesWithManifest(content, baseDir string, extractTools bool) (string, []string, error) {
-	log.Printf("Expanding includes: baseDir=%s, extractTools=%t, content_size=%d", baseDir, extractTools, len(content))
+	return ExpandIncludesWithManifestForEngine(content, baseDir, extractTools, "", nil)
+}
+
+// ExpandIncludesWithManifestForEngine is like ExpandIncludesWithManifest but also expands
+// conditional includes whose engine condition matches engine. Conditions naming engines that
+// are not in knownEngines (typically the IDs of the engine registry) are reported as warnings.
+func ExpandIncludesWithManifestForEngine(content, baseDir string, extractTools bool, engine string, knownEngines []string) (string, []string, error) {
+	log.Printf("Expanding includes: baseDir=%s, extractTools=%t, engine=%s, content_size=%d", baseDir, extractTools, engine, len(content))
 	const maxDepth = 10
 	currentContent := content
 	visited := make(map[string]bool)
@@ -25,7 +34,7 @@ func ExpandIncludesWithManifest(content, baseDir string, extractTools bool) (str
 	for depth := range maxDepth {
 		log.Printf("Include expansion depth: %d", depth)
 		// Process includes in current content
-		processedContent, err := processIncludesWithVisited(currentContent, baseDir, extractTools, visited)
+		processedContent, err := processIncludesWithVisited(currentContent, baseDir, extractTools, engine, knownEngines, visited)
 		if err != nil {
 			return "", nil, err
 		}
@@ -74,31 +83,33 @@ func ExpandIncludesWithManifest(content, baseDir string, extractTools bool) (str
 	return currentContent, includedFiles, nil
 }
 
-// ExpandIncludesForEngines recursively expands @include and @import directives to extract engine configurations
+// ExpandIncludesForEngines recursively expands @include and @import directives to extract engine configurations.
+// Conditional includes are skipped since they depend on the engine being determined.
 func ExpandIncludesForEngines(content, baseDir string) ([]string, error) {
 	log.Printf("Expanding includes for engines: baseDir=%s", baseDir)
-	return expandIncludesForField(content, baseDir, func(c string) (string, error) {
+	return expandIncludesForField(content, baseDir, "", func(c string) (string, error) {
 		return extractFrontmatterField(c, "engine", "")
 	}, "")
 }
 
-// ExpandIncludesForSafeOutputs recursively expands @include and @import directives to extract safe-outputs configurations
-func ExpandIncludesForSafeOutputs(content, baseDir string) ([]string, error) {
-	log.Printf("Expanding includes for safe-outputs: baseDir=%s", baseDir)
-	return expandIncludesForField(content, baseDir, func(c string) (string, error) {
+// ExpandIncludesForSafeOutputs recursively expands @include and @import directives to extract safe-outputs configurations.
+// Conditional includes are only considered when their engine condition matches engine.
+func ExpandIncludesForSafeOutputs(content, baseDir, engine string) ([]string, error) {
+	log.Printf("Expanding includes for safe-outputs: baseDir=%s, engine=%s", baseDir, engine)
+	return expandIncludesForField(content, baseDir, engine, func(c string) (string, error) {
 		return extractFrontmatterField(c, "safe-outputs", "{}")
 	}, "{}")
 }
 
 // expandIncludesForField recursively expands includes to extract a specific frontmatter field
-func expandIncludesForField(content, baseDir string, extractFunc func(string) (string, error), emptyValue string) ([]string, error) {
+func expandIncludesForField(content, baseDir, engine string, extractFunc func(string) (string, error), emptyValue string) ([]string, error) {
 	const maxDepth = 10
 	var results []string
 	currentContent := content
 
 	for range maxDepth {
 		// Process includes in current content to extract the field
-		processedResults, processedContent, err := processIncludesForField(currentContent, baseDir, extractFunc, emptyValue)
+		processedResults, processedContent, err := processIncludesForField(currentContent, baseDir, engine, extractFunc, emptyValue)
 		if err != nil {
 			return nil, err
 		}
@@ -121,20 +132,20 @@ func expandIncludesForField(content, baseDir string, extractFunc func(string) (s
 
 // ProcessIncludesForEngines processes import directives to extract engine configurations
 func ProcessIncludesForEngines(content, baseDir string) ([]string, string, error) {
-	return processIncludesForField(content, baseDir, func(c string) (string, error) {
+	return processIncludesForField(content, baseDir, "", func(c string) (string, error) {
 		return extractFrontmatterField(c, "engine", "")
 	}, "")
 }
 
 // ProcessIncludesForSafeOutputs processes import directives to extract safe-outputs configurations
 func ProcessIncludesForSafeOutputs(content, baseDir string) ([]string, string, error) {
-	return processIncludesForField(content, baseDir, func(c string) (string, error) {
+	return processIncludesForField(content, baseDir, "", func(c string) (string, error) {
 		return extractFrontmatterField(c, "safe-outputs", "{}")
 	}, "{}")
 }
 
 // processIncludesForField processes import directives to extract a specific frontmatter field
-func processIncludesForField(content, baseDir string, extractFunc func(string) (string, error), emptyValue string) ([]string, string, error) {
+func processIncludesForField(content, baseDir, engine string, extractFunc func(string) (string, error), emptyValue string) ([]string, string, error) {
 	scanner := bufio.NewScanner(strings.NewReader(content))
 	var result bytes.Buffer
 	var results []string
@@ -145,6 +156,17 @@ func processIncludesForField(content, baseDir string, extractFunc func(string) (
 		// Parse import directive
 		directive := ParseImportDirective(line)
 		if directive != nil {
+			// Skip conditional includes that do not apply to the active engine
+			if directive.Condition != "" {
+				applies, err := includeConditionApplies(directive, engine, nil, true)
+				if err != nil {
+					return nil, "", err
+				}
+				if !applies {
+					continue
+				}
+			}
+
 			isOptional := directive.IsOptional
 			includePath := directive.Path
 
@@ -161,7 +183,7 @@ func processIncludesForField(content, baseDir string, extractFunc func(string) (
 			// Resolve file path
 			fullPath, err := ResolveIncludePath(filePath, baseDir, nil)
 			if err != nil {
-				if isOptional {
+				if isOptional && !errors.Is(err, ErrIntegrityMismatch) {
 					// For optional includes, skip extraction
 					continue
 				}
@@ -0,0 +1,58 @@
+package parser
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var includeIntegrityLog = logger.New("parser:include_integrity")
+
+// ErrIntegrityMismatch is returned (wrapped) when a fetched include or import does not match
+// the checksum pinned in its reference
+var ErrIntegrityMismatch = errors.New("content does not match the pinned checksum")
+
+// integritySuffixPattern matches the checksum pin at the end of an include or import path,
+// e.g. "shared/tools.md@sha256:<64 hex digits>"
+var integritySuffixPattern = regexp.MustCompile(`@(sha256:[0-9a-fA-F]{64})$`)
+
+// SplitIncludeIntegrity removes the checksum pin from an include or import path and returns
+// the path and the pin ("sha256:<hex>", empty when the path is not pinned). The pin follows
+// the path and optional ref, before any #section:
+//
+//	shared/tools.md@sha256:<hex>
+//	owner/repo/shared/tools.md@v1@sha256:<hex>#Tools
+func SplitIncludeIntegrity(includePath string) (string, string) {
+	filePath, section, hasSection := strings.Cut(includePath, "#")
+	loc := integritySuffixPattern.FindStringSubmatchIndex(filePath)
+	if loc == nil {
+		return includePath, ""
+	}
+	integrity := strings.ToLower(filePath[loc[2]:loc[3]])
+	stripped := filePath[:loc[0]]
+	if hasSection {
+		stripped += "#" + section
+	}
+	return stripped, integrity
+}
+
+// VerifyIncludeIntegrity checks content against a checksum pin from SplitIncludeIntegrity.
+// An empty pin always passes.
+func VerifyIncludeIntegrity(name string, content []byte, integrity string) error {
+	if integrity == "" {
+		return nil
+	}
+	sum := sha256.Sum256(content)
+	actual := "sha256:" + hex.EncodeToString(sum[:])
+	if actual != strings.ToLower(integrity) {
+		includeIntegrityLog.Printf("Checksum mismatch for %s: expected %s, got %s", name, integrity, actual)
+		return fmt.Errorf("%s %w: expected %s, got %s", name, ErrIntegrityMismatch, integrity, actual)
+	}
+	includeIntegrityLog.Printf("Verified checksum of %s", name)
+	return nil
+}
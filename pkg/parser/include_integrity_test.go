@@ -0,0 +1,108 @@
+//go:build !integration
+
+package parser
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitIncludeIntegrity(t *testing.T) {
+	hexSum := strings.Repeat("ab", 32)
+
+	tests := []struct {
+		name          string
+		includePath   string
+		wantPath      string
+		wantIntegrity string
+	}{
+		{
+			name:        "unpinned path",
+			includePath: "shared/tools.md",
+			wantPath:    "shared/tools.md",
+		},
+		{
+			name:          "pinned path",
+			includePath:   "shared/tools.md@sha256:" + hexSum,
+			wantPath:      "shared/tools.md",
+			wantIntegrity: "sha256:" + hexSum,
+		},
+		{
+			name:          "pinned workflowspec with ref and section",
+			includePath:   "owner/repo/shared/tools.md@v1@sha256:" + hexSum + "#Tools",
+			wantPath:      "owner/repo/shared/tools.md@v1#Tools",
+			wantIntegrity: "sha256:" + hexSum,
+		},
+		{
+			name:          "uppercase hex is normalized",
+			includePath:   "shared/tools.md@sha256:" + strings.ToUpper(hexSum),
+			wantPath:      "shared/tools.md",
+			wantIntegrity: "sha256:" + hexSum,
+		},
+		{
+			name:        "short digest is not a pin",
+			includePath: "owner/repo/shared/tools.md@sha256:abc",
+			wantPath:    "owner/repo/shared/tools.md@sha256:abc",
+		},
+		{
+			name:        "pin in the section is ignored",
+			includePath: "shared/tools.md#x@sha256:" + hexSum,
+			wantPath:    "shared/tools.md#x@sha256:" + hexSum,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, integrity := SplitIncludeIntegrity(tt.includePath)
+			assert.Equal(t, tt.wantPath, path, "path should have the pin removed")
+			assert.Equal(t, tt.wantIntegrity, integrity, "pin should be extracted")
+		})
+	}
+}
+
+func TestVerifyIncludeIntegrity(t *testing.T) {
+	content := []byte("# Tools\n")
+	sum := sha256.Sum256(content)
+	pin := "sha256:" + hex.EncodeToString(sum[:])
+
+	require.NoError(t, VerifyIncludeIntegrity("tools.md", content, ""), "unpinned content should pass")
+	require.NoError(t, VerifyIncludeIntegrity("tools.md", content, pin), "matching content should pass")
+	require.NoError(t, VerifyIncludeIntegrity("tools.md", content, strings.ToUpper(pin)), "pin comparison should ignore case")
+
+	err := VerifyIncludeIntegrity("tools.md", []byte("# Changed\n"), pin)
+	require.ErrorIs(t, err, ErrIntegrityMismatch, "changed content should fail")
+	assert.Contains(t, err.Error(), "tools.md", "error should name the file")
+	assert.Contains(t, err.Error(), pin, "error should report the expected checksum")
+}
+
+func TestResolveIncludePathIntegrity(t *testing.T) {
+	mirrorDir := t.TempDir()
+	writeMirrorFile(t, mirrorDir, "shared/tools.md", "# Tools\n")
+	defer SetRepoMirrors(map[string]string{"owner/repo": mirrorDir}, true)()
+
+	sum := sha256.Sum256([]byte("# Tools\n"))
+	pin := "sha256:" + hex.EncodeToString(sum[:])
+	cache := NewImportCache(t.TempDir())
+
+	path, err := ResolveIncludePath("owner/repo/shared/tools.md@main@"+pin, "", cache)
+	require.NoError(t, err, "download matching its pin should resolve")
+	content, err := os.ReadFile(path)
+	require.NoError(t, err, "resolved include should be readable")
+	assert.Equal(t, "# Tools\n", string(content), "resolved include should hold the downloaded content")
+
+	_, err = ResolveIncludePath("owner/repo/shared/tools.md@main@sha256:"+strings.Repeat("0", 64), "", NewImportCache(t.TempDir()))
+	require.ErrorIs(t, err, ErrIntegrityMismatch, "download not matching its pin should fail")
+
+	githubDir := filepath.Join(t.TempDir(), ".github")
+	writeMirrorFile(t, githubDir, "shared/local.md", "# Local\n")
+	path, err = ResolveIncludePath("shared/local.md@"+pin, githubDir, cache)
+	require.NoError(t, err, "local include should resolve with the pin removed")
+	assert.Equal(t, filepath.Join(githubDir, "shared", "local.md"), path, "pin should not be part of the local path")
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -20,11 +21,13 @@ var includeLog = logger.New("parser:include_processor")
 func ProcessIncludes(content, baseDir string, extractTools bool) (string, error) {
 	includeLog.Printf("Processing includes: baseDir=%s, extractTools=%t, content_size=%d", baseDir, extractTools, len(content))
 	visited := make(map[string]bool)
-	return processIncludesWithVisited(content, baseDir, extractTools, visited)
+	return processIncludesWithVisited(content, baseDir, extractTools, "", nil, visited)
 }
 
-// processIncludesWithVisited processes import directives with cycle detection
-func processIncludesWithVisited(content, baseDir string, extractTools bool, visited map[string]bool) (string, error) {
+// processIncludesWithVisited processes import directives with cycle detection.
+// Conditional includes (e.g. @include[engine=copilot]) are only processed when they apply to engine;
+// engine IDs in conditions are checked against knownEngines.
+func processIncludesWithVisited(content, baseDir string, extractTools bool, engine string, knownEngines []string, visited map[string]bool) (string, error) {
 	scanner := bufio.NewScanner(strings.NewReader(content))
 	var result bytes.Buffer
 
@@ -42,12 +45,27 @@ func processIncludesWithVisited(content, baseDir string, extractTools bool, visi
 				if directive.IsOptional {
 					optionalMarker = "?"
 				}
+				if directive.Condition != "" {
+					optionalMarker += "[" + directive.Condition + "]"
+				}
 				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Deprecated syntax: %q. Use {{#import%s %s}} instead.",
 					directive.Original,
 					optionalMarker,
 					directive.Path)))
 			}
 
+			// Skip conditional includes that do not apply to the active engine
+			if directive.Condition != "" {
+				applies, err := includeConditionApplies(directive, engine, knownEngines, extractTools)
+				if err != nil {
+					return "", err
+				}
+				if !applies {
+					includeLog.Printf("Skipping conditional include for engine %q: %s", engine, directive.Original)
+					continue
+				}
+			}
+
 			isOptional := directive.IsOptional
 			includePath := directive.Path
 
@@ -65,7 +83,7 @@ func processIncludesWithVisited(content, baseDir string, extractTools bool, visi
 			fullPath, err := ResolveIncludePath(filePath, baseDir, nil)
 			if err != nil {
 				includeLog.Printf("Failed to resolve include path '%s': %v", filePath, err)
-				if isOptional {
+				if isOptional && !errors.Is(err, ErrIntegrityMismatch) {
 					// For optional includes, show a friendly informational message to stdout
 					if !extractTools {
 						fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Optional include file not found: %s. You can create this file to configure the workflow.", filePath)))
@@ -90,7 +108,7 @@ func processIncludesWithVisited(content, baseDir string, extractTools bool, visi
 			visited[fullPath] = true
 
 			// Process the included file
-			includedContent, err := processIncludedFileWithVisited(fullPath, sectionName, extractTools, visited)
+			includedContent, err := processIncludedFileWithVisited(fullPath, sectionName, extractTools, engine, knownEngines, visited)
 			if err != nil {
 				// For any processing errors, fail compilation
 				return "", fmt.Errorf("failed to process included file '%s': %w", fullPath, err)
@@ -115,7 +133,7 @@ func processIncludesWithVisited(content, baseDir string, extractTools bool, visi
 
 // processIncludedFile processes a single included file, optionally extracting a section
 // processIncludedFileWithVisited processes a single included file with cycle detection for nested includes
-func processIncludedFileWithVisited(filePath, sectionName string, extractTools bool, visited map[string]bool) (string, error) {
+func processIncludedFileWithVisited(filePath, sectionName string, extractTools bool, engine string, knownEngines []string, visited map[string]bool) (string, error) {
 	includeLog.Printf("Reading included file: %s (extractTools=%t, section=%s)", filePath, extractTools, sectionName)
 	content, err := readFileFunc(filePath)
 	if err != nil {
@@ -250,7 +268,7 @@ func processIncludedFileWithVisited(filePath, sectionName string, extractTools b
 
 	// Process nested includes recursively
 	includedDir := filepath.Dir(filePath)
-	markdownContent, err = processIncludesWithVisited(markdownContent, includedDir, extractTools, visited)
+	markdownContent, err = processIncludesWithVisited(markdownContent, includedDir, extractTools, engine, knownEngines, visited)
 	if err != nil {
 		return "", fmt.Errorf("failed to process nested includes in %s: %w", filePath, err)
 	}
@@ -460,6 +460,32 @@ func TestFindFrontmatterBounds(t *testing.T) {
 			expectedEndIdx:           -1,
 			expectedFrontmatterLines: 0,
 		},
+		{
+			name: "TOML frontmatter",
+			lines: []string{
+				"+++",
+				"name = \"test\"",
+				"+++",
+				"Content",
+			},
+			expectedStartIdx:         0,
+			expectedEndIdx:           2,
+			expectedFrontmatterLines: 1,
+		},
+		{
+			name: "JSON frontmatter",
+			lines: []string{
+				"```json",
+				"{",
+				"  \"name\": \"test\"",
+				"}",
+				"```",
+				"Content",
+			},
+			expectedStartIdx:         0,
+			expectedEndIdx:           4,
+			expectedFrontmatterLines: 3,
+		},
 		{
 			name: "empty frontmatter",
 			lines: []string{
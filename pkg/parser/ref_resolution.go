@@ -0,0 +1,125 @@
+//go:build !js && !wasm
+
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var refResolutionLog = logger.New("parser:ref_resolution")
+
+const (
+	branchRefPrefix = "refs/heads/"
+	tagRefPrefix    = "refs/tags/"
+)
+
+// isQualifiedRef reports whether ref names a branch or tag explicitly, e.g. "refs/tags/v1"
+func isQualifiedRef(ref string) bool {
+	return strings.HasPrefix(ref, branchRefPrefix) || strings.HasPrefix(ref, tagRefPrefix)
+}
+
+// pickBranchOrTag chooses between the commits a ref resolves to as a branch and as a tag
+// (empty when it is not one). It reports false when ref is neither, and an
+// AmbiguousRefError when it is both and they point at different commits.
+func pickBranchOrTag(repoSlug, ref, branchSHA, tagSHA string) (string, bool, error) {
+	switch {
+	case branchSHA != "" && tagSHA != "" && branchSHA != tagSHA:
+		refResolutionLog.Printf("Ref %s is ambiguous in %s: branch %s, tag %s", ref, repoSlug, branchSHA, tagSHA)
+		return "", false, &AmbiguousRefError{Repo: repoSlug, Ref: ref, BranchSHA: branchSHA, TagSHA: tagSHA}
+	case branchSHA != "":
+		return branchSHA, true, nil
+	case tagSHA != "":
+		return tagSHA, true, nil
+	}
+	return "", false, nil
+}
+
+// lookupQualifiedRef resolves a qualified ref (refs/heads/<name> or refs/tags/<name>) to the
+// commit it points at, sending the given extra request headers, and reports false when the ref
+// does not exist. It is a variable so tests can substitute the GitHub API.
+var lookupQualifiedRef = lookupQualifiedRefViaAPI
+
+// lookupQualifiedRefViaAPI looks a qualified ref up with the git refs API, peeling annotated
+// tags to their commit
+func lookupQualifiedRefViaAPI(headers map[string]string, owner, repo, ref string) (string, bool, error) {
+	client, err := newRESTClient(headers)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to create REST client: %w", err)
+	}
+
+	var gitRef struct {
+		Object struct {
+			SHA  string `json:"sha"`
+			Type string `json:"type"`
+		} `json:"object"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s/%s/git/ref/%s", owner, repo, strings.TrimPrefix(ref, "refs/")), &gitRef); err != nil {
+		if isNotFound(err) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	if gitRef.Object.Type != "tag" {
+		return gitRef.Object.SHA, true, nil
+	}
+
+	var tag struct {
+		Object struct {
+			SHA string `json:"sha"`
+		} `json:"object"`
+	}
+	if err := client.Get(fmt.Sprintf("repos/%s/%s/git/tags/%s", owner, repo, gitRef.Object.SHA), &tag); err != nil {
+		return "", false, err
+	}
+	return tag.Object.SHA, true, nil
+}
+
+// resolveBranchOrTag resolves ref as a branch and as a tag, sending the given extra request headers. Qualified refs are looked up
+// directly; an unqualified ref that names both a branch and a tag at different commits is
+// rejected with an AmbiguousRefError. It reports false when ref is neither.
+func resolveBranchOrTag(headers map[string]string, owner, repo, ref string) (string, bool, error) {
+	if isQualifiedRef(ref) {
+		return lookupQualifiedRef(headers, owner, repo, ref)
+	}
+
+	branchSHA, _, err := lookupQualifiedRef(headers, owner, repo, branchRefPrefix+ref)
+	if err != nil {
+		return "", false, err
+	}
+	tagSHA, _, err := lookupQualifiedRef(headers, owner, repo, tagRefPrefix+ref)
+	if err != nil {
+		return "", false, err
+	}
+	return pickBranchOrTag(owner+"/"+repo, ref, branchSHA, tagSHA)
+}
+
+// parseLsRemoteRefs parses git ls-remote output into a map from ref name to commit.
+// Annotated tags are peeled: the "<tag>^{}" line, which follows the tag, replaces it.
+func parseLsRemoteRefs(output []byte) map[string]string {
+	refs := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		refs[strings.TrimSuffix(fields[1], "^{}")] = fields[0]
+	}
+	return refs
+}
+
+// revParseMirrorRef returns the commit a qualified ref points at in a git mirror, or "" when
+// the mirror has no such ref
+func revParseMirrorRef(dir, ref string) string {
+	output, err := exec.Command("git", "-C", dir, "rev-parse", "--verify", "--quiet", ref+"^{commit}").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
@@ -0,0 +1,106 @@
+//go:build !integration
+
+package parser
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveRefToSHAAmbiguousRefs(t *testing.T) {
+	const (
+		branchSHA = "1111111111111111111111111111111111111111"
+		tagSHA    = "2222222222222222222222222222222222222222"
+	)
+	refs := map[string]string{
+		"refs/heads/v1":   branchSHA,
+		"refs/tags/v1":    tagSHA,
+		"refs/heads/main": branchSHA,
+		"refs/tags/v2":    tagSHA,
+		"refs/heads/same": branchSHA,
+		"refs/tags/same":  branchSHA,
+	}
+	original := lookupQualifiedRef
+	t.Cleanup(func() {
+		lookupQualifiedRef = original
+		refSHACache.Clear()
+	})
+	lookupQualifiedRef = func(_ map[string]string, owner, repo, ref string) (string, bool, error) {
+		sha, ok := refs[ref]
+		return sha, ok, nil
+	}
+
+	_, err := ResolveRefToSHA("octo", "repo", "v1")
+	var ambiguous *AmbiguousRefError
+	require.ErrorAs(t, err, &ambiguous, "a branch and tag with the same name should be ambiguous")
+	assert.Equal(t, &AmbiguousRefError{Repo: "octo/repo", Ref: "v1", BranchSHA: branchSHA, TagSHA: tagSHA}, ambiguous, "error should list both candidates")
+	assert.Contains(t, err.Error(), "refs/heads/v1 or refs/tags/v1", "error should explain how to disambiguate")
+	_, memoized := refSHACache.Load(refSHACacheKey("octo", "repo", "v1"))
+	assert.False(t, memoized, "ambiguous refs should not be memoized")
+
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{ref: "refs/heads/v1", want: branchSHA},
+		{ref: "refs/tags/v1", want: tagSHA},
+		{ref: "main", want: branchSHA},
+		{ref: "v2", want: tagSHA},
+		{ref: "same", want: branchSHA},
+	}
+	for _, tt := range tests {
+		sha, err := ResolveRefToSHA("octo", "repo", tt.ref)
+		require.NoError(t, err, "%s should resolve", tt.ref)
+		assert.Equal(t, tt.want, sha, "%s should resolve to its commit", tt.ref)
+	}
+
+	_, err = ResolveRefToSHA("octo", "repo", "refs/tags/missing")
+	var notFound *NotFoundError
+	require.ErrorAs(t, err, &notFound, "unknown qualified refs should be not found")
+}
+
+func TestRepoMirrorAmbiguousRefs(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q", "-b", "main")
+	writeMirrorFile(t, dir, "workflows/triage.md", "tagged")
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-q", "-m", "tagged")
+	runGit(t, dir, "tag", "-a", "release", "-m", "release")
+	runGit(t, dir, "branch", "release")
+	runGit(t, dir, "checkout", "-q", "release")
+	writeMirrorFile(t, dir, "workflows/triage.md", "branch")
+	runGit(t, dir, "commit", "-q", "-am", "branch")
+
+	defer SetRepoMirrors(map[string]string{"owner/repo": dir}, true)()
+
+	_, err := ResolveRefToSHA("owner", "repo", "release")
+	var ambiguous *AmbiguousRefError
+	require.ErrorAs(t, err, &ambiguous, "a branch and tag with the same name should be ambiguous")
+
+	sha, err := ResolveRefToSHA("owner", "repo", "refs/tags/release")
+	require.NoError(t, err, "qualified tag should resolve")
+	assert.Equal(t, strings.TrimSpace(runGit(t, dir, "rev-parse", "refs/tags/release^{commit}")), sha, "annotated tag should resolve to its commit")
+
+	sha, err = ResolveRefToSHA("owner", "repo", "refs/heads/release")
+	require.NoError(t, err, "qualified branch should resolve")
+	assert.Equal(t, strings.TrimSpace(runGit(t, dir, "rev-parse", "refs/heads/release")), sha, "branch should resolve to its head")
+}
+
+func TestParseLsRemoteRefs(t *testing.T) {
+	output := "1111111111111111111111111111111111111111\trefs/heads/v1\n" +
+		"3333333333333333333333333333333333333333\trefs/tags/v1\n" +
+		"2222222222222222222222222222222222222222\trefs/tags/v1^{}\n"
+
+	assert.Equal(t, map[string]string{
+		"refs/heads/v1": "1111111111111111111111111111111111111111",
+		"refs/tags/v1":  "2222222222222222222222222222222222222222",
+	}, parseLsRemoteRefs([]byte(output)), "annotated tags should be peeled to their commit")
+}
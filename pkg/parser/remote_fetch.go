@@ -3,15 +3,18 @@
 package parser
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"os/exec"
 	pathpkg "path"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/cli/go-gh/v2"
 	"github.com/cli/go-gh/v2/pkg/api"
@@ -109,16 +112,31 @@ func isRepositoryImport(importPath string) bool {
 	return true
 }
 
-// ResolveIncludePath resolves include path based on workflowspec format or relative path
+// ResolveIncludePath resolves include path based on workflowspec format or relative path.
+// A checksum pin (path@sha256:<hex>, see SplitIncludeIntegrity) is verified against downloaded
+// workflowspec files. Local files are not verified, since files saved by 'gh aw add' may be
+// scoped to the referenced sections.
 func ResolveIncludePath(filePath, baseDir string, cache *ImportCache) (string, error) {
 	remoteLog.Printf("Resolving include path: file_path=%s, base_dir=%s", filePath, baseDir)
+	filePath, integrity := SplitIncludeIntegrity(filePath)
 
 	// Check if this is a workflowspec (contains owner/repo/path format)
 	// Format: owner/repo/path@ref or owner/repo/path@ref#section
 	if isWorkflowSpec(filePath) {
 		remoteLog.Printf("Detected workflowspec format: %s", filePath)
 		// Download from GitHub using workflowspec (with cache support)
-		return downloadIncludeFromWorkflowSpec(filePath, cache)
+		downloadedPath, err := downloadIncludeFromWorkflowSpec(filePath, cache)
+		if err != nil || integrity == "" {
+			return downloadedPath, err
+		}
+		content, err := os.ReadFile(downloadedPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to read downloaded include %s: %w", filePath, err)
+		}
+		if err := VerifyIncludeIntegrity(filePath, content, integrity); err != nil {
+			return "", err
+		}
+		return downloadedPath, nil
 	}
 
 	remoteLog.Printf("Using local file resolution for: %s", filePath)
@@ -257,7 +275,7 @@ func downloadIncludeFromWorkflowSpec(spec string, cache *ImportCache) (string, e
 
 	// Download the file content from GitHub
 	remoteLog.Printf("Fetching file from GitHub: %s/%s/%s@%s", owner, repo, filePath, ref)
-	content, err := downloadFileFromGitHub(owner, repo, filePath, ref)
+	content, err := downloadFileFromGitHub(owner, repo, filePath, ref, 0)
 	if err != nil {
 		return "", fmt.Errorf("failed to download include from %s: %w", spec, err)
 	}
@@ -311,39 +329,29 @@ func resolveRefToSHAViaGit(owner, repo, ref string) (string, error) {
 	githubHost := GetGitHubHostForRepo(owner, repo)
 	repoURL := fmt.Sprintf("%s/%s/%s.git", githubHost, owner, repo)
 
-	// Try to resolve the ref using git ls-remote
-	// Format: git ls-remote <repo> <ref>
-	cmd := exec.Command("git", "ls-remote", repoURL, ref)
-	output, err := cmd.Output()
+	// List the branch and tag the ref may name; qualified refs are listed as-is
+	// Format: git ls-remote <repo> <ref>...
+	patterns := []string{ref}
+	if !isQualifiedRef(ref) {
+		patterns = []string{branchRefPrefix + ref, tagRefPrefix + ref}
+	}
+	output, err := exec.Command("git", append([]string{"ls-remote", repoURL}, patterns...)...).Output()
 	if err != nil {
-		// If exact ref doesn't work, try with refs/heads/ and refs/tags/ prefixes
-		for _, prefix := range []string{"refs/heads/", "refs/tags/"} {
-			cmd = exec.Command("git", "ls-remote", repoURL, prefix+ref)
-			output, err = cmd.Output()
-			if err == nil && len(output) > 0 {
-				break
-			}
-		}
+		return "", fmt.Errorf("failed to resolve ref via git ls-remote: %w", err)
+	}
 
-		if err != nil {
-			return "", fmt.Errorf("failed to resolve ref via git ls-remote: %w", err)
+	// Parse the output: "<sha>\t<ref>" per line
+	refs := parseLsRemoteRefs(output)
+	sha, found := refs[ref], refs[ref] != ""
+	if !isQualifiedRef(ref) {
+		if sha, found, err = pickBranchOrTag(owner+"/"+repo, ref, refs[branchRefPrefix+ref], refs[tagRefPrefix+ref]); err != nil {
+			return "", err
 		}
 	}
-
-	// Parse the output: "<sha> <ref>"
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	if len(lines) == 0 || len(lines[0]) == 0 {
+	if !found {
 		return "", fmt.Errorf("no matching ref found for %s", ref)
 	}
 
-	// Extract SHA from the first line
-	parts := strings.Fields(lines[0])
-	if len(parts) < 1 {
-		return "", errors.New("invalid git ls-remote output format")
-	}
-
-	sha := parts[0]
-
 	// Validate it's a valid SHA
 	if len(sha) != 40 || !gitutil.IsHexString(sha) {
 		return "", fmt.Errorf("invalid SHA format from git ls-remote: %s", sha)
@@ -353,20 +361,120 @@ func resolveRefToSHAViaGit(owner, repo, ref string) (string, error) {
 	return sha, nil
 }
 
-// resolveRefToSHA resolves a git ref (branch, tag, or SHA) to its commit SHA
+// refSHACache memoizes floating ref resolutions for the lifetime of the process so that
+// a single command invocation resolves each owner/repo/ref at most once.
+// It is intentionally in-memory only: floating refs must be re-resolved by the next run.
+var refSHACache sync.Map
+
+// refSHACacheKey builds the refSHACache key for a repository ref
+func refSHACacheKey(owner, repo, ref string) string {
+	return fmt.Sprintf("%s/%s/%s", owner, repo, ref)
+}
+
+// resolveRefToSHA resolves a git ref (branch, tag, or SHA) to its commit SHA with the default Fetcher
 func resolveRefToSHA(owner, repo, ref string) (string, error) {
+	return defaultFetcher().resolveRefToSHA(owner, repo, ref)
+}
+
+// resolveRefToSHA resolves a git ref (branch, tag, or SHA) to its commit SHA
+func (f *Fetcher) resolveRefToSHA(owner, repo, ref string) (string, error) {
 	// If ref is already a full SHA (40 hex characters), return it as-is
 	if len(ref) == 40 && gitutil.IsHexString(ref) {
 		return ref, nil
 	}
 
+	// Frozen refs resolve to their recorded commit without any lookup
+	if f.frozen != nil {
+		return f.frozenRef(owner, repo, ref)
+	}
+
+	if dir, ok, err := f.lookupRepoMirror(owner, repo); err != nil {
+		return "", err
+	} else if ok {
+		return resolveRefFromMirror(dir, owner, repo, ref)
+	}
+
+	key := f.cacheKey(refSHACacheKey(owner, repo, ref))
+	if cached, ok := refSHACache.Load(key); ok {
+		remoteLog.Printf("Using memoized SHA for %s/%s@%s", owner, repo, ref)
+		return cached.(string), nil
+	}
+
+	release := f.acquireRequestSlot()
+	var sha string
+	var err error
+	if f.forge != nil {
+		refResolutionCalls.Add(1)
+		sha, err = f.forge.ResolveRef(owner, repo, ref)
+	} else {
+		sha, err = f.resolveFloatingRefToSHA(owner, repo, ref)
+	}
+	release()
+	if err != nil {
+		// Failures are not memoized so that transient errors can be retried
+		return "", err
+	}
+
+	refSHACache.Store(key, sha)
+	return sha, nil
+}
+
+// resolveFloatingRefToSHA resolves a branch, tag, or short SHA to its commit SHA via the GitHub API,
+// falling back to git ls-remote when API authentication fails. The ref is looked up as a branch
+// and as a tag first, so a name used by both is reported as an AmbiguousRefError instead of
+// resolving to either silently; refs/heads/ and refs/tags/ prefixes select one.
+func (f *Fetcher) resolveFloatingRefToSHA(owner, repo, ref string) (string, error) {
+	sha, found, err := resolveBranchOrTag(f.headers, owner, repo, ref)
+	if err != nil {
+		var ambiguous *AmbiguousRefError
+		if errors.As(err, &ambiguous) {
+			return "", err
+		}
+		// Rate-limit responses are 403s, so check them before falling back on auth errors
+		if isRateLimitError(err) {
+			return "", newRateLimitError(fmt.Errorf("failed to resolve ref %s to SHA for %s/%s: %w", ref, owner, repo, err))
+		}
+		if gitutil.IsAuthError(err.Error()) {
+			remoteLog.Printf("GitHub API authentication failed, attempting git ls-remote fallback for %s/%s@%s", owner, repo, ref)
+			sha, gitErr := resolveRefToSHAViaGit(owner, repo, ref)
+			if gitErr != nil {
+				if errors.As(gitErr, &ambiguous) {
+					return "", gitErr
+				}
+				return "", fmt.Errorf("failed to resolve ref via GitHub API (auth error) and git ls-remote: API error: %w, Git error: %w", err, gitErr)
+			}
+			return sha, nil
+		}
+		return "", fmt.Errorf("failed to resolve ref %s to SHA for %s/%s: %w", ref, owner, repo, err)
+	}
+	if found {
+		return sha, nil
+	}
+	if isQualifiedRef(ref) {
+		return "", &NotFoundError{Source: fmt.Sprintf("%s/%s@%s", owner, repo, ref), Err: errors.New("no such branch or tag")}
+	}
+
+	// Neither a branch nor a tag, e.g. a short SHA: let the commits API resolve it
+	return f.resolveCommitRefToSHA(owner, repo, ref)
+}
+
+// resolveCommitRefToSHA resolves a commit-ish ref such as a short SHA with the commits API
+func (f *Fetcher) resolveCommitRefToSHA(owner, repo, ref string) (string, error) {
 	// Use gh CLI to get the commit SHA for the ref
 	// This works for branches, tags, and short SHAs
 	// Using go-gh to properly handle enterprise GitHub instances via GH_HOST
-	stdout, stderr, err := gh.Exec("api", fmt.Sprintf("/repos/%s/%s/commits/%s", owner, repo, ref), "--jq", ".sha")
+	endpoint := fmt.Sprintf("/repos/%s/%s/commits/%s", owner, repo, ref)
+	recordAPICall(endpoint)
+	args := append([]string{"api", endpoint, "--jq", ".sha"}, ghAPIHeaderArgs(f.headers)...)
+	stdout, stderr, err := gh.Exec(args...)
 
 	if err != nil {
 		outputStr := stderr.String()
+		// Rate-limit responses are 403s, so check them before falling back on auth errors
+		if isRateLimitMessage(outputStr) {
+			refreshRateLimitState()
+			return "", newRateLimitError(fmt.Errorf("failed to resolve ref %s to SHA for %s/%s: %s: %w", ref, owner, repo, strings.TrimSpace(outputStr), err))
+		}
 		if gitutil.IsAuthError(outputStr) {
 			remoteLog.Printf("GitHub API authentication failed, attempting git ls-remote fallback for %s/%s@%s", owner, repo, ref)
 			// Try fallback using git ls-remote for public repositories
@@ -393,9 +501,12 @@ func resolveRefToSHA(owner, repo, ref string) (string, error) {
 	return sha, nil
 }
 
+// tarArchiveOverhead is the room left for tar headers and padding around a single archived file
+const tarArchiveOverhead = 64 * 1024
+
 // downloadFileViaGit downloads a file from a Git repository using git commands
 // This is a fallback for when GitHub API authentication fails
-func downloadFileViaGit(owner, repo, path, ref string) ([]byte, error) {
+func downloadFileViaGit(owner, repo, path, ref string, limit int64) ([]byte, error) {
 	remoteLog.Printf("Attempting git fallback for %s/%s/%s@%s", owner, repo, path, ref)
 
 	// Use git archive to get the file content without cloning
@@ -407,10 +518,28 @@ func downloadFileViaGit(owner, repo, path, ref string) ([]byte, error) {
 	// #nosec G204 -- repoURL, ref, and path are from workflow import configuration authored by the
 	// developer; exec.Command with separate args (not shell execution) prevents shell injection.
 	cmd := exec.Command("git", "archive", "--remote="+repoURL, ref, path)
-	archiveOutput, err := cmd.Output()
+	stdout, err := cmd.StdoutPipe()
 	if err != nil {
+		return nil, fmt.Errorf("failed to run git archive: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return downloadFileViaGitClone(owner, repo, path, ref, limit)
+	}
+
+	// Stop reading once the archive is larger than the file limit allows (plus tar headers)
+	source := fmt.Sprintf("%s/%s/%s@%s", owner, repo, path, ref)
+	archiveOutput, readErr := readLimited(stdout, limit+tarArchiveOverhead, source)
+	if readErr != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		if errors.Is(readErr, ErrFileTooLarge) {
+			return nil, readErr
+		}
+		return downloadFileViaGitClone(owner, repo, path, ref, limit)
+	}
+	if err := cmd.Wait(); err != nil {
 		// If git archive fails, try with git clone + git show as a fallback
-		return downloadFileViaGitClone(owner, repo, path, ref)
+		return downloadFileViaGitClone(owner, repo, path, ref, limit)
 	}
 
 	// Extract the file from the tar archive using Go's archive/tar (cross-platform)
@@ -418,6 +547,9 @@ func downloadFileViaGit(owner, repo, path, ref string) ([]byte, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract file from git archive: %w", err)
 	}
+	if err := checkDownloadSize(int64(len(content)), limit, source); err != nil {
+		return nil, err
+	}
 
 	remoteLog.Printf("Successfully downloaded file via git archive: %s/%s/%s@%s", owner, repo, path, ref)
 	return content, nil
@@ -425,7 +557,7 @@ func downloadFileViaGit(owner, repo, path, ref string) ([]byte, error) {
 
 // downloadFileViaGitClone downloads a file by shallow cloning the repository
 // This is used as a fallback when git archive doesn't work
-func downloadFileViaGitClone(owner, repo, path, ref string) ([]byte, error) {
+func downloadFileViaGitClone(owner, repo, path, ref string, limit int64) ([]byte, error) {
 	remoteLog.Printf("Attempting git clone fallback for %s/%s/%s@%s", owner, repo, path, ref)
 
 	// Create a temporary directory for the shallow clone
@@ -470,6 +602,11 @@ func downloadFileViaGitClone(owner, repo, path, ref string) ([]byte, error) {
 
 	// Read the file from the cloned repository
 	filePath := filepath.Join(tmpDir, path)
+	if info, err := os.Stat(filePath); err == nil {
+		if err := checkDownloadSize(info.Size(), limit, fmt.Sprintf("%s/%s/%s@%s", owner, repo, path, ref)); err != nil {
+			return nil, err
+		}
+	}
 	content, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file from cloned repository: %w", err)
@@ -531,7 +668,7 @@ func checkRemoteSymlink(client *api.RESTClient, owner, repo, dirPath, ref string
 // if .github/workflows/shared is a symlink to ../../gh-agent-workflows/shared,
 // fetching .github/workflows/shared/elastic-tools.md returns 404.
 // This function walks the path components and resolves any symlinks found.
-func resolveRemoteSymlinks(owner, repo, filePath, ref string) (string, error) {
+func (f *Fetcher) resolveRemoteSymlinks(owner, repo, filePath, ref string) (string, error) {
 	parts := strings.Split(filePath, "/")
 	if len(parts) <= 1 {
 		return "", fmt.Errorf("no directory components to resolve in path: %s", filePath)
@@ -539,7 +676,7 @@ func resolveRemoteSymlinks(owner, repo, filePath, ref string) (string, error) {
 
 	remoteLog.Printf("Attempting symlink resolution for %s/%s/%s@%s (%d path components)", owner, repo, filePath, ref, len(parts))
 
-	client, err := api.DefaultRESTClient()
+	client, err := newRESTClient(f.headers)
 	if err != nil {
 		return "", fmt.Errorf("failed to create REST client: %w", err)
 	}
@@ -601,15 +738,23 @@ func resolveRemoteSymlinks(owner, repo, filePath, ref string) (string, error) {
 }
 
 // DownloadFileFromGitHub downloads a file from a GitHub repository using the GitHub API.
-// This is the exported wrapper for downloadFileFromGitHub.
+// This is the exported wrapper for downloadFileFromGitHub; use a Fetcher to configure
+// request headers, mirrors, frozen refs, request limits, or caching.
 // Parameters:
 // - owner: Repository owner (e.g., "github")
 // - repo: Repository name (e.g., "gh-aw")
 // - path: Path to the file within the repository (e.g., ".github/workflows/workflow.md")
 // - ref: Git reference (branch, tag, or commit SHA)
 // Returns the file content as bytes or an error if the file cannot be retrieved.
+// Files larger than DefaultMaxDownloadSize fail with ErrFileTooLarge.
 func DownloadFileFromGitHub(owner, repo, path, ref string) ([]byte, error) {
-	return downloadFileFromGitHub(owner, repo, path, ref)
+	return downloadFileFromGitHub(owner, repo, path, ref, 0)
+}
+
+// DownloadFileFromGitHubWithLimit is DownloadFileFromGitHub with a limit of maxSize bytes on
+// the file size (DefaultMaxDownloadSize when maxSize is 0 or less).
+func DownloadFileFromGitHubWithLimit(owner, repo, path, ref string, maxSize int64) ([]byte, error) {
+	return downloadFileFromGitHub(owner, repo, path, ref, maxSize)
 }
 
 // ResolveRefToSHA resolves a git ref (branch, tag, or short SHA) to its full commit SHA.
@@ -619,13 +764,45 @@ func ResolveRefToSHA(owner, repo, ref string) (string, error) {
 	return resolveRefToSHA(owner, repo, ref)
 }
 
-func downloadFileFromGitHub(owner, repo, path, ref string) ([]byte, error) {
-	return downloadFileFromGitHubWithDepth(owner, repo, path, ref, 0)
+// downloadFileFromGitHub downloads a file with the default Fetcher
+func downloadFileFromGitHub(owner, repo, path, ref string, maxSize int64) ([]byte, error) {
+	return defaultFetcher().downloadFile(owner, repo, path, ref, maxSize)
 }
 
-func downloadFileFromGitHubWithDepth(owner, repo, path, ref string, symlinkDepth int) ([]byte, error) {
+func (f *Fetcher) downloadFile(owner, repo, path, ref string, maxSize int64) ([]byte, error) {
+	limit := MaxDownloadSize(maxSize)
+	ref, err := f.frozenRef(owner, repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	if dir, ok, err := f.lookupRepoMirror(owner, repo); err != nil {
+		return nil, err
+	} else if ok {
+		return downloadFileFromMirror(dir, owner, repo, path, ref, limit)
+	}
+	content, err := f.cachedDownload(owner, repo, path, ref, func() ([]byte, error) {
+		return f.diskCachedDownload(owner, repo, path, ref, func(ref string) ([]byte, error) {
+			defer f.acquireRequestSlot()()
+			if f.forge != nil {
+				contentRequestCalls.Add(1)
+				return f.forge.DownloadFile(owner, repo, path, ref, limit)
+			}
+			return f.downloadFileWithDepth(owner, repo, path, ref, limit, 0)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Cached content may have been downloaded under a larger limit
+	if err := checkDownloadSize(int64(len(content)), limit, fmt.Sprintf("%s/%s/%s@%s", owner, repo, path, ref)); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+func (f *Fetcher) downloadFileWithDepth(owner, repo, path, ref string, limit int64, symlinkDepth int) ([]byte, error) {
 	// Create REST client
-	client, err := api.DefaultRESTClient()
+	client, err := newRESTClient(f.headers)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create REST client: %w", err)
 	}
@@ -635,39 +812,56 @@ func downloadFileFromGitHubWithDepth(owner, repo, path, ref string, symlinkDepth
 		Content  string `json:"content"`
 		Encoding string `json:"encoding"`
 		Name     string `json:"name"`
+		Type     string `json:"type"`
 	}
 
-	// Fetch file content from GitHub API
-	err = client.Get(fmt.Sprintf("repos/%s/%s/contents/%s?ref=%s", owner, repo, path, ref), &fileContent)
+	// Fetch file content from GitHub API, bounding both the request duration and the body size
+	source := fmt.Sprintf("%s/%s/%s@%s", owner, repo, path, ref)
+	err = getFileContentLimited(client, fmt.Sprintf("repos/%s/%s/contents/%s?ref=%s", owner, repo, path, ref), limit, source, &fileContent)
 	if err != nil {
+		if errors.Is(err, ErrFileTooLarge) || errors.Is(err, ErrUnexpectedResponse) || errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("failed to fetch file content from %s: %w", source, err)
+		}
+
 		errStr := err.Error()
 
+		// Rate-limit responses are 403s, so check them before falling back on auth errors
+		if isRateLimitError(err) {
+			return nil, newRateLimitError(fmt.Errorf("failed to fetch file content from %s/%s/%s@%s: %w", owner, repo, path, ref, err))
+		}
+
 		// Check if this is an authentication error
 		if gitutil.IsAuthError(errStr) {
 			remoteLog.Printf("GitHub API authentication failed, attempting git fallback for %s/%s/%s@%s", owner, repo, path, ref)
 			// Try fallback using git commands for public repositories
-			content, gitErr := downloadFileViaGit(owner, repo, path, ref)
+			content, gitErr := downloadFileViaGit(owner, repo, path, ref, limit)
 			if gitErr != nil {
 				// If git fallback also fails, return both errors
-				return nil, fmt.Errorf("failed to fetch file content via GitHub API (auth error) and git fallback: API error: %w, Git error: %w", err, gitErr)
+				return nil, &AuthError{Source: source, Err: fmt.Errorf("GitHub API error: %w, git fallback error: %w", err, gitErr)}
 			}
 			return content, nil
 		}
 
 		// Check if this is a 404 — the path may traverse a symlink that the API doesn't follow
-		if isNotFoundError(errStr) && symlinkDepth < constants.MaxSymlinkDepth {
-			remoteLog.Printf("File not found at %s/%s/%s@%s, checking for symlinks in path (depth: %d)", owner, repo, path, ref, symlinkDepth)
-			resolvedPath, resolveErr := resolveRemoteSymlinks(owner, repo, path, ref)
-			if resolveErr == nil && resolvedPath != path {
-				remoteLog.Printf("Retrying download with symlink-resolved path: %s -> %s", path, resolvedPath)
-				return downloadFileFromGitHubWithDepth(owner, repo, resolvedPath, ref, symlinkDepth+1)
+		if isNotFound(err) {
+			if symlinkDepth < constants.MaxSymlinkDepth {
+				remoteLog.Printf("File not found at %s/%s/%s@%s, checking for symlinks in path (depth: %d)", owner, repo, path, ref, symlinkDepth)
+				resolvedPath, resolveErr := f.resolveRemoteSymlinks(owner, repo, path, ref)
+				if resolveErr == nil && resolvedPath != path {
+					remoteLog.Printf("Retrying download with symlink-resolved path: %s -> %s", path, resolvedPath)
+					return f.downloadFileWithDepth(owner, repo, resolvedPath, ref, limit, symlinkDepth+1)
+				}
 			}
+			return nil, &NotFoundError{Source: source, Err: err}
 		}
 
 		return nil, fmt.Errorf("failed to fetch file content from %s/%s/%s@%s: %w", owner, repo, path, ref, err)
 	}
 
-	// Verify we have content
+	// Verify the response describes a file with inline content
+	if err := checkFileContent(fileContent.Type, fileContent.Encoding, source); err != nil {
+		return nil, err
+	}
 	if fileContent.Content == "" {
 		return nil, fmt.Errorf("empty content returned from GitHub API for %s/%s/%s@%s", owner, repo, path, ref)
 	}
@@ -677,17 +871,90 @@ func downloadFileFromGitHubWithDepth(owner, repo, path, ref string, symlinkDepth
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode base64 content: %w", err)
 	}
+	if err := checkDownloadSize(int64(len(content)), limit, source); err != nil {
+		return nil, err
+	}
 
 	return content, nil
 }
 
+// contentsMetadataAllowance is the room left for the JSON metadata around the base64 file
+// content in a contents API response
+const contentsMetadataAllowance = 64 * 1024
+
+// getFileContentLimited fetches a contents API response into response. The request is bounded
+// by DefaultDownloadTimeout, and the body is read only as far as a file of limit bytes needs.
+// Responses that are not JSON file metadata (HTML pages, directory listings, or redirects to
+// another host) fail with ErrUnexpectedResponse.
+func getFileContentLimited(client *api.RESTClient, endpoint string, limit int64, source string, response any) error {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultDownloadTimeout)
+	defer cancel()
+
+	resp, err := client.RequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("timed out after %s: %w", DefaultDownloadTimeout, err)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := checkContentsResponse(resp, source); err != nil {
+		return err
+	}
+	bodyReader, err := contentsResponseBody(resp)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", source, err)
+	}
+	defer bodyReader.Close()
+
+	// GitHub wraps base64 content at 60 characters per line
+	encodedLimit := int64(base64.StdEncoding.EncodedLen(int(limit)))
+	encodedLimit += encodedLimit/60 + contentsMetadataAllowance
+	body, err := readLimited(bodyReader, encodedLimit, source)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("timed out after %s: %w", DefaultDownloadTimeout, err)
+		}
+		return err
+	}
+	if err := json.Unmarshal(body, response); err != nil {
+		// Directories are listed as a JSON array rather than a file object
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Value == "array" {
+			return fmt.Errorf("%s is a directory, not a file: %w", source, ErrUnexpectedResponse)
+		}
+		return fmt.Errorf("failed to parse contents response for %s: %w", source, err)
+	}
+	return nil
+}
+
+// maxContentsListing is the maximum number of entries the contents API returns for a directory
+const maxContentsListing = 1000
+
 // ListWorkflowFiles lists workflow files from a remote GitHub repository
 // Returns a list of .md files in the specified directory (excluding subdirectories)
 func ListWorkflowFiles(owner, repo, ref, workflowPath string) ([]string, error) {
+	return defaultFetcher().listWorkflowFiles(owner, repo, ref, workflowPath)
+}
+
+func (f *Fetcher) listWorkflowFiles(owner, repo, ref, workflowPath string) ([]string, error) {
 	remoteLog.Printf("Listing workflow files for %s/%s@%s (path: %s)", owner, repo, ref, workflowPath)
 
+	if dir, ok, err := f.lookupRepoMirror(owner, repo); err != nil {
+		return nil, err
+	} else if ok {
+		return listWorkflowFilesFromMirror(dir, owner, repo, ref, workflowPath)
+	}
+
+	if f.forge != nil {
+		defer f.acquireRequestSlot()()
+		contentRequestCalls.Add(1)
+		return f.forge.ListDir(owner, repo, ref, workflowPath)
+	}
+
 	// Create REST client
-	client, err := api.DefaultRESTClient()
+	client, err := newRESTClient(f.headers)
 	if err != nil {
 		remoteLog.Printf("Failed to create REST client, attempting git fallback: %v", err)
 		return listWorkflowFilesViaGit(owner, repo, ref, workflowPath)
@@ -706,6 +973,11 @@ func ListWorkflowFiles(owner, repo, ref, workflowPath string) ([]string, error)
 	if err != nil {
 		errStr := err.Error()
 
+		// Rate-limit responses are 403s, so check them before falling back on auth errors
+		if isRateLimitError(err) {
+			return nil, newRateLimitError(fmt.Errorf("failed to list workflow files from %s/%s@%s (path: %s): %w", owner, repo, ref, workflowPath, err))
+		}
+
 		// Check if this is an authentication error
 		if gitutil.IsAuthError(errStr) {
 			remoteLog.Printf("GitHub API authentication failed, attempting git fallback for %s/%s@%s", owner, repo, ref)
@@ -718,9 +990,18 @@ func ListWorkflowFiles(owner, repo, ref, workflowPath string) ([]string, error)
 			return files, nil
 		}
 
+		if isNotFound(err) {
+			return nil, &NotFoundError{Source: fmt.Sprintf("%s/%s/%s@%s", owner, repo, workflowPath, ref), Err: err}
+		}
 		return nil, fmt.Errorf("failed to list workflow files from %s/%s@%s (path: %s): %w", owner, repo, ref, workflowPath, err)
 	}
 
+	// The contents API lists directories in a single response without pagination, capped at
+	// 1,000 entries
+	if len(contents) >= maxContentsListing {
+		remoteLog.Printf("Listing of %s/%s@%s (path: %s) may be truncated at %d entries", owner, repo, ref, workflowPath, maxContentsListing)
+	}
+
 	// Filter to only .md files (not in subdirectories)
 	var workflowFiles []string
 	for _, item := range contents {
@@ -20,7 +20,7 @@ func TestDownloadFileFromGitHubRESTClient(t *testing.T) {
 	path := "Go.gitignore"
 	ref := "main"
 
-	content, err := downloadFileFromGitHub(owner, repo, path, ref)
+	content, err := downloadFileFromGitHub(owner, repo, path, ref, 0)
 	if err != nil {
 		// If we get an auth error, we can skip this test in CI environments
 		// where GitHub tokens might not be available
@@ -53,7 +53,7 @@ func TestDownloadFileFromGitHubInvalidRepo(t *testing.T) {
 	path := "README.md"
 	ref := "main"
 
-	_, err := downloadFileFromGitHub(owner, repo, path, ref)
+	_, err := downloadFileFromGitHub(owner, repo, path, ref, 0)
 	if err == nil {
 		t.Fatal("Expected error for nonexistent repository, got nil")
 	}
@@ -78,7 +78,7 @@ func TestDownloadFileFromGitHubInvalidPath(t *testing.T) {
 	path := "nonexistent-file-xyz123.txt"
 	ref := "main"
 
-	_, err := downloadFileFromGitHub(owner, repo, path, ref)
+	_, err := downloadFileFromGitHub(owner, repo, path, ref, 0)
 	if err == nil {
 		t.Fatal("Expected error for nonexistent file, got nil")
 	}
@@ -107,7 +107,7 @@ func TestDownloadFileFromGitHubWithSHA(t *testing.T) {
 	// Note: This might fail if the SHA doesn't exist, but demonstrates SHA support
 	ref := "main" // Using main instead of specific SHA to avoid brittleness
 
-	content, err := downloadFileFromGitHub(owner, repo, path, ref)
+	content, err := downloadFileFromGitHub(owner, repo, path, ref, 0)
 	if err != nil {
 		if strings.Contains(err.Error(), "auth") || strings.Contains(err.Error(), "forbidden") {
 			t.Skip("Skipping test due to authentication requirements")
@@ -217,7 +217,7 @@ func TestCheckRemoteSymlink(t *testing.T) {
 // directory components of a real path and returns "no symlinks found" when none exist.
 func TestResolveRemoteSymlinksNoSymlinks(t *testing.T) {
 	// "Global/Perl.gitignore" is a real path in github/gitignore with no symlinks
-	_, err := resolveRemoteSymlinks("github", "gitignore", "Global/Perl.gitignore", "main")
+	_, err := defaultFetcher().resolveRemoteSymlinks("github", "gitignore", "Global/Perl.gitignore", "main")
 	require.Error(t, err, "Expected error when no symlinks found")
 	skipOnAuthError(t, err)
 
@@ -230,7 +230,7 @@ func TestResolveRemoteSymlinksNoSymlinks(t *testing.T) {
 func TestDownloadFileFromGitHubSymlinkRoute(t *testing.T) {
 	// Use a path through a real directory but with a nonexistent file.
 	// This triggers: 404 -> symlink resolution -> "no symlinks found" -> original error.
-	_, err := downloadFileFromGitHub("github", "gitignore", "Global/nonexistent-file-xyz123.gitignore", "main")
+	_, err := downloadFileFromGitHub("github", "gitignore", "Global/nonexistent-file-xyz123.gitignore", "main", 0)
 	require.Error(t, err, "Expected error for nonexistent file")
 	skipOnAuthError(t, err)
 
@@ -0,0 +1,37 @@
+//go:build !integration
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveRefToSHAMemoization(t *testing.T) {
+	t.Cleanup(func() { refSHACache.Clear() })
+
+	const memoizedSHA = "0123456789abcdef0123456789abcdef01234567"
+	refSHACache.Store(refSHACacheKey("octo", "repo", "main"), memoizedSHA)
+
+	sha, err := ResolveRefToSHA("octo", "repo", "main")
+	require.NoError(t, err, "memoized ref should resolve without a network call")
+	assert.Equal(t, memoizedSHA, sha, "should return the memoized SHA")
+
+	// A different ref in the same repository is a distinct cache entry
+	_, found := refSHACache.Load(refSHACacheKey("octo", "repo", "v1"))
+	assert.False(t, found, "unrelated refs should not be memoized")
+}
+
+func TestResolveRefToSHAFullSHABypassesCache(t *testing.T) {
+	t.Cleanup(func() { refSHACache.Clear() })
+
+	const fullSHA = "fedcba9876543210fedcba9876543210fedcba98"
+	sha, err := ResolveRefToSHA("octo", "repo", fullSHA)
+	require.NoError(t, err, "full SHA should resolve without a network call")
+	assert.Equal(t, fullSHA, sha, "full SHA should be returned as-is")
+
+	_, found := refSHACache.Load(refSHACacheKey("octo", "repo", fullSHA))
+	assert.False(t, found, "full SHAs should not be stored in the floating ref cache")
+}
@@ -0,0 +1,208 @@
+//go:build !js && !wasm
+
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	pathpkg "path"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var mirrorLog = logger.New("parser:repo_mirrors")
+
+// ErrRepoNotMirrored is returned (wrapped) in offline mode when a repository has no local mirror
+var ErrRepoNotMirrored = errors.New("repository has no local mirror and network access is disabled")
+
+// repoMirrorConfig maps lowercase "owner/repo" slugs to local directories
+type repoMirrorConfig struct {
+	dirs    map[string]string
+	offline bool
+}
+
+// repoMirrors holds the mirrors of the package-level functions (see SetRepoMirrors)
+var repoMirrors atomic.Pointer[repoMirrorConfig]
+
+// SetRepoMirrors redirects the downloads, ref resolutions, and directory listings of the
+// package-level functions, such as the imports resolved during compilation, for the given
+// repositories to local directories, keyed by "owner/repo". Fetchers are configured with
+// FetchConfig.RepoMirrors instead. A mirror that is a git repository
+// (including a bare clone) is read at the requested ref; any other directory is read as-is and
+// refs cannot be resolved. When offline is true, repositories without a mirror fail with
+// ErrRepoNotMirrored instead of falling back to the network. The returned function restores the
+// previous configuration.
+func SetRepoMirrors(mirrors map[string]string, offline bool) (restore func()) {
+	previous := repoMirrors.Swap(newRepoMirrorConfig(mirrors, offline))
+	return func() {
+		repoMirrors.Store(previous)
+	}
+}
+
+// newRepoMirrorConfig normalizes mirrors keyed by "owner/repo" into a repoMirrorConfig
+func newRepoMirrorConfig(mirrors map[string]string, offline bool) *repoMirrorConfig {
+	config := &repoMirrorConfig{dirs: make(map[string]string, len(mirrors)), offline: offline}
+	for slug, dir := range mirrors {
+		config.dirs[strings.ToLower(slug)] = dir
+	}
+	mirrorLog.Printf("Configured %d repository mirrors (offline: %v)", len(config.dirs), offline)
+	return config
+}
+
+// ParseRepoMirrors parses "owner/repo=path" mirror specifications into a map suitable for
+// SetRepoMirrors and FetchConfig.RepoMirrors. Each path must be an existing directory.
+func ParseRepoMirrors(specs []string) (map[string]string, error) {
+	mirrors := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		slug, dir, ok := strings.Cut(spec, "=")
+		owner, repo, slugOK := strings.Cut(slug, "/")
+		if !ok || !slugOK || owner == "" || repo == "" || strings.Contains(repo, "/") || dir == "" {
+			return nil, fmt.Errorf("invalid repository mirror %q: expected owner/repo=path", spec)
+		}
+		info, err := os.Stat(dir)
+		if err != nil {
+			return nil, fmt.Errorf("invalid repository mirror %q: %w", spec, err)
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("invalid repository mirror %q: %s is not a directory", spec, dir)
+		}
+		mirrors[slug] = dir
+	}
+	return mirrors, nil
+}
+
+// lookupRepoMirror returns the mirror directory for owner/repo. It fails with ErrRepoNotMirrored
+// when the repository has no mirror and offline mode is enabled.
+func (f *Fetcher) lookupRepoMirror(owner, repo string) (string, bool, error) {
+	config := f.mirrors
+	if config == nil {
+		return "", false, nil
+	}
+	if dir, ok := config.dirs[strings.ToLower(owner+"/"+repo)]; ok {
+		return dir, true, nil
+	}
+	if config.offline {
+		return "", false, fmt.Errorf("%s/%s: %w", owner, repo, ErrRepoNotMirrored)
+	}
+	return "", false, nil
+}
+
+// isGitMirror reports whether dir is the root of a git repository, including bare clones. A
+// plain directory nested inside some other repository's working tree is not a git mirror.
+func isGitMirror(dir string) bool {
+	prefix, err := exec.Command("git", "-C", dir, "rev-parse", "--show-prefix").Output()
+	return err == nil && strings.TrimSpace(string(prefix)) == ""
+}
+
+// downloadFileFromMirror reads path at ref from a local mirror, applying the same size limit
+// as downloads from the GitHub API
+func downloadFileFromMirror(dir, owner, repo, path, ref string, limit int64) ([]byte, error) {
+	source := fmt.Sprintf("%s/%s/%s@%s", owner, repo, path, ref)
+	if !filepath.IsLocal(filepath.FromSlash(path)) {
+		return nil, fmt.Errorf("invalid path %s: must stay inside the repository", source)
+	}
+	mirrorLog.Printf("Reading %s from mirror %s", source, dir)
+
+	if isGitMirror(dir) {
+		content, err := exec.Command("git", "-C", dir, "cat-file", "blob", ref+":"+path).Output()
+		if err != nil {
+			return nil, &NotFoundError{Source: source, Err: gitCommandError(err)}
+		}
+		if err := checkDownloadSize(int64(len(content)), limit, source); err != nil {
+			return nil, err
+		}
+		return content, nil
+	}
+
+	filePath := filepath.Join(dir, filepath.FromSlash(path))
+	info, err := os.Stat(filePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, &NotFoundError{Source: source, Err: err}
+		}
+		return nil, fmt.Errorf("failed to read %s from mirror: %w", source, err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%s is a directory, not a file: %w", source, ErrUnexpectedResponse)
+	}
+	if err := checkDownloadSize(info.Size(), limit, source); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(filePath)
+}
+
+// resolveRefFromMirror resolves ref to a commit SHA in a git mirror
+func resolveRefFromMirror(dir, owner, repo, ref string) (string, error) {
+	if !isGitMirror(dir) {
+		return "", fmt.Errorf("cannot resolve %s/%s@%s: mirror %s is not a git repository", owner, repo, ref, dir)
+	}
+	// Like the GitHub API, report a name used by both a branch and a tag instead of letting
+	// git pick one
+	if !isQualifiedRef(ref) {
+		sha, found, err := pickBranchOrTag(owner+"/"+repo, ref, revParseMirrorRef(dir, branchRefPrefix+ref), revParseMirrorRef(dir, tagRefPrefix+ref))
+		if err != nil || found {
+			return sha, err
+		}
+	}
+	output, err := exec.Command("git", "-C", dir, "rev-parse", "--verify", "--quiet", ref+"^{commit}").Output()
+	if err != nil {
+		return "", &NotFoundError{Source: fmt.Sprintf("%s/%s@%s", owner, repo, ref), Err: gitCommandError(err)}
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// listWorkflowFilesFromMirror lists the .md files directly inside workflowPath of a mirror,
+// returning repository-relative paths like ListWorkflowFiles
+func listWorkflowFilesFromMirror(dir, owner, repo, ref, workflowPath string) ([]string, error) {
+	workflowPath = strings.Trim(workflowPath, "/")
+	if !filepath.IsLocal(filepath.FromSlash(workflowPath)) {
+		return nil, fmt.Errorf("invalid path %s: must stay inside the repository", workflowPath)
+	}
+
+	var files []string
+	if isGitMirror(dir) {
+		output, err := exec.Command("git", "-C", dir, "ls-tree", ref, "--", workflowPath+"/").Output()
+		if err != nil {
+			return nil, &NotFoundError{Source: fmt.Sprintf("%s/%s/%s@%s", owner, repo, workflowPath, ref), Err: gitCommandError(err)}
+		}
+		// Each line is "<mode> <type> <object>\t<path>"
+		scanner := bufio.NewScanner(bytes.NewReader(output))
+		for scanner.Scan() {
+			meta, filePath, ok := strings.Cut(scanner.Text(), "\t")
+			if ok && strings.Contains(meta, " blob ") && strings.HasSuffix(strings.ToLower(filePath), ".md") {
+				files = append(files, filePath)
+			}
+		}
+		return files, nil
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, filepath.FromSlash(workflowPath)))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, &NotFoundError{Source: fmt.Sprintf("%s/%s/%s@%s", owner, repo, workflowPath, ref), Err: err}
+		}
+		return nil, fmt.Errorf("failed to list %s in mirror: %w", workflowPath, err)
+	}
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && strings.HasSuffix(strings.ToLower(entry.Name()), ".md") {
+			files = append(files, pathpkg.Join(workflowPath, entry.Name()))
+		}
+	}
+	return files, nil
+}
+
+// gitCommandError includes the stderr of a failed git command in its error
+func gitCommandError(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return err
+}
@@ -0,0 +1,123 @@
+//go:build !integration
+
+package parser
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeMirrorFile creates a file under dir, including parent directories
+func writeMirrorFile(t *testing.T, dir, path, content string) {
+	t.Helper()
+	fullPath := filepath.Join(dir, filepath.FromSlash(path))
+	require.NoError(t, os.MkdirAll(filepath.Dir(fullPath), 0o755), "mirror directory should be created")
+	require.NoError(t, os.WriteFile(fullPath, []byte(content), 0o644), "mirror file should be written")
+}
+
+// runGit runs a git command in dir, failing the test on error
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, "git %v failed: %s", args, output)
+	return string(output)
+}
+
+func TestParseRepoMirrors(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file.md")
+	require.NoError(t, os.WriteFile(file, []byte("x"), 0o644), "file should be written")
+
+	mirrors, err := ParseRepoMirrors([]string{"owner/repo=" + dir})
+	require.NoError(t, err, "valid mirror should parse")
+	assert.Equal(t, map[string]string{"owner/repo": dir}, mirrors, "mirror should be keyed by slug")
+
+	for _, spec := range []string{"owner/repo", "owner=" + dir, "owner/repo/extra=" + dir, "owner/repo=", "owner/repo=" + file, "owner/repo=" + filepath.Join(dir, "missing")} {
+		_, err := ParseRepoMirrors([]string{spec})
+		assert.Error(t, err, "mirror %q should be rejected", spec)
+	}
+}
+
+func TestRepoMirrorDirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeMirrorFile(t, dir, "workflows/triage.md", "# Triage")
+	writeMirrorFile(t, dir, "workflows/notes.txt", "notes")
+	writeMirrorFile(t, dir, "workflows/shared/tools.md", "# Tools")
+
+	defer SetRepoMirrors(map[string]string{"Owner/Repo": dir}, true)()
+
+	content, err := DownloadFileFromGitHub("owner", "repo", "workflows/triage.md", "main")
+	require.NoError(t, err, "mirrored file should be read")
+	assert.Equal(t, "# Triage", string(content), "content should come from the mirror")
+
+	_, err = DownloadFileFromGitHub("owner", "repo", "workflows/missing.md", "main")
+	var notFound *NotFoundError
+	require.ErrorAs(t, err, &notFound, "missing mirrored file should be not found")
+
+	_, err = DownloadFileFromGitHub("owner", "repo", "../outside.md", "main")
+	require.Error(t, err, "paths outside the mirror should be rejected")
+
+	_, err = DownloadFileFromGitHubWithLimit("owner", "repo", "workflows/triage.md", "main", 4)
+	require.ErrorIs(t, err, ErrFileTooLarge, "mirrored files over the requested limit should be rejected")
+
+	files, err := ListWorkflowFiles("owner", "repo", "main", "workflows")
+	require.NoError(t, err, "mirrored directory should be listed")
+	assert.Equal(t, []string{"workflows/triage.md"}, files, "only .md files directly in the directory should be listed")
+
+	_, err = ResolveRefToSHA("owner", "repo", "main")
+	require.Error(t, err, "refs cannot be resolved in a plain directory")
+
+	_, err = DownloadFileFromGitHub("other", "repo", "README.md", "main")
+	require.ErrorIs(t, err, ErrRepoNotMirrored, "unmirrored repositories should fail offline")
+	_, err = ResolveRefToSHA("other", "repo", "main")
+	require.ErrorIs(t, err, ErrRepoNotMirrored, "unmirrored refs should fail offline")
+}
+
+func TestRepoMirrorGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q", "-b", "main")
+	writeMirrorFile(t, dir, "workflows/triage.md", "v1")
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-q", "-m", "v1")
+	runGit(t, dir, "tag", "v1")
+	writeMirrorFile(t, dir, "workflows/triage.md", "v2")
+	writeMirrorFile(t, dir, "workflows/review.md", "v2")
+	runGit(t, dir, "commit", "-q", "-am", "v2")
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-q", "-m", "review")
+
+	defer SetRepoMirrors(map[string]string{"owner/repo": dir}, false)()
+
+	content, err := DownloadFileFromGitHub("owner", "repo", "workflows/triage.md", "v1")
+	require.NoError(t, err, "file should be read at the tag")
+	assert.Equal(t, "v1", string(content), "content should come from the requested ref")
+
+	content, err = DownloadFileFromGitHub("owner", "repo", "workflows/triage.md", "main")
+	require.NoError(t, err, "file should be read at the branch")
+	assert.Equal(t, "v2", string(content), "content should come from the branch head")
+
+	_, err = DownloadFileFromGitHub("owner", "repo", "workflows", "main")
+	var notFound *NotFoundError
+	require.ErrorAs(t, err, &notFound, "directories should not be read as files")
+
+	files, err := ListWorkflowFiles("owner", "repo", "v1", "workflows")
+	require.NoError(t, err, "directory should be listed at the tag")
+	assert.Equal(t, []string{"workflows/triage.md"}, files, "listing should reflect the requested ref")
+
+	sha, err := ResolveRefToSHA("owner", "repo", "v1")
+	require.NoError(t, err, "tag should resolve")
+	assert.Equal(t, runGit(t, dir, "rev-parse", "v1^{commit}")[:40], sha, "tag should resolve to its commit")
+
+	_, err = ResolveRefToSHA("owner", "repo", "missing")
+	require.ErrorAs(t, err, &notFound, "unknown refs should be not found")
+}
@@ -0,0 +1,17 @@
+//go:build !js && !wasm
+
+package parser
+
+// acquireRequestSlot blocks until a GitHub request may start under the limit configured with
+// FetchConfig.MaxConcurrentRequests, which keeps adding many workflows in parallel under
+// GitHub's secondary rate limits. Reads from repository mirrors and cached downloads do not
+// count towards the limit. The returned function must be called once the request has finished.
+func (f *Fetcher) acquireRequestSlot() (release func()) {
+	if f.slots == nil {
+		return func() {}
+	}
+	f.slots <- struct{}{}
+	return func() {
+		<-f.slots
+	}
+}
@@ -0,0 +1,61 @@
+//go:build !integration
+
+package parser
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetcherMaxConcurrentRequests(t *testing.T) {
+	f, err := NewFetcher(FetchConfig{MaxConcurrentRequests: 2})
+	require.NoError(t, err, "fetcher should be created")
+
+	var inFlight, peak atomic.Int32
+	var wg sync.WaitGroup
+	for range 6 {
+		wg.Go(func() {
+			defer f.acquireRequestSlot()()
+			current := inFlight.Add(1)
+			for {
+				observed := peak.Load()
+				if current <= observed || peak.CompareAndSwap(observed, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			inFlight.Add(-1)
+		})
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(2), peak.Load(), "no more than the limit should run at once")
+}
+
+func TestFetcherMaxConcurrentRequests_Independent(t *testing.T) {
+	limited, err := NewFetcher(FetchConfig{MaxConcurrentRequests: 1})
+	require.NoError(t, err, "fetcher should be created")
+	release := limited.acquireRequestSlot()
+	defer release()
+
+	// Fetchers without a limit, including the default one, must not wait for another fetcher's slots
+	unlimited, err := NewFetcher(FetchConfig{})
+	require.NoError(t, err, "fetcher should be created")
+	for _, f := range []*Fetcher{unlimited, defaultFetcher()} {
+		done := make(chan struct{})
+		go func() {
+			f.acquireRequestSlot()()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("request should not wait without a limit")
+		}
+	}
+}
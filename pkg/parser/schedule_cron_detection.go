@@ -120,7 +120,8 @@ func IsFuzzyCron(cron string) bool {
 }
 
 // IsCronExpression checks if the input looks like a valid cron expression
-// A valid cron expression has exactly 5 fields (minute, hour, day of month, month, day of week)
+// A valid cron expression has exactly 5 fields (minute, hour, day of month, month, day of week).
+// Only the shape is checked here; ValidateCronExpression checks field values.
 func IsCronExpression(input string) bool {
 	// A cron expression has exactly 5 fields
 	fields := strings.Fields(input)
@@ -128,10 +129,16 @@ func IsCronExpression(input string) bool {
 		return false
 	}
 
-	// Each field should match cron syntax (numbers, *, /, -, ,)
+	// Each field should match cron syntax (numbers, *, /, -, ,); month and
+	// day of week may also use named abbreviations such as JAN or MON
 	cronFieldPattern := regexp.MustCompile(`^[\d\*\-/,]+$`)
-	for _, field := range fields {
-		if !cronFieldPattern.MatchString(field) {
+	namedCronFieldPattern := regexp.MustCompile(`^[\d\*\-/,A-Za-z]+$`)
+	for i, field := range fields {
+		pattern := cronFieldPattern
+		if i >= 3 {
+			pattern = namedCronFieldPattern
+		}
+		if !pattern.MatchString(field) {
 			return false
 		}
 	}
@@ -14,6 +14,8 @@ func TestIsCronExpression(t *testing.T) {
 		{"0 14 * * 1-5", true},
 		{"30 6 * * 1", true},
 		{"0 12 25 12 *", true},
+		{"0 9 * JAN-JUN MON-FRI", true},
+		{"0 MON * * *", false}, // Names only in month and day-of-week
 		{"daily", false},
 		{"weekly on monday", false},
 		{"every 10 minutes", false},
@@ -0,0 +1,102 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// This file contains field-level validation of 5-field cron expressions, so that
+// typos in schedules are reported at compile time rather than by GitHub.
+
+// cronField describes the allowed values of one cron field
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int // Named abbreviations (upper case), if the field supports them
+}
+
+var cronFields = [5]cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day-of-month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}},
+	{name: "day-of-week", min: 0, max: 6, names: map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}},
+}
+
+// ValidateCronExpression checks that a cron expression has exactly 5 fields and that every
+// field uses valid syntax (*, values, ranges, steps, and comma lists) with in-range values.
+// Month and day-of-week accept the abbreviations JAN-DEC and SUN-SAT (case-insensitive).
+// The returned error names the offending field by position.
+func ValidateCronExpression(expr string) error {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return fmt.Errorf("must have exactly 5 fields (minute hour day-of-month month day-of-week), got %d", len(fields))
+	}
+
+	for i, value := range fields {
+		if err := cronFields[i].validate(value); err != nil {
+			return fmt.Errorf("field %d (%s) %q: %w", i+1, cronFields[i].name, value, err)
+		}
+	}
+	return nil
+}
+
+// validate checks a single field value, e.g. "*/15", "1-5", "MON,WED,FRI" or "0-30/10"
+func (f cronField) validate(value string) error {
+	for element := range strings.SplitSeq(value, ",") {
+		if element == "" {
+			return errors.New("empty list element")
+		}
+
+		base, step, hasStep := strings.Cut(element, "/")
+		if hasStep {
+			n, err := strconv.Atoi(step)
+			if err != nil || n < 1 || n > f.max {
+				return fmt.Errorf("invalid step %q (must be between 1 and %d)", step, f.max)
+			}
+		}
+
+		if base == "*" {
+			continue
+		}
+
+		start, end, isRange := strings.Cut(base, "-")
+		startValue, err := f.parseValue(start)
+		if err != nil {
+			return err
+		}
+		if isRange {
+			endValue, err := f.parseValue(end)
+			if err != nil {
+				return err
+			}
+			if startValue > endValue {
+				return fmt.Errorf("range %s has start greater than end", base)
+			}
+		}
+	}
+	return nil
+}
+
+// parseValue parses a numeric value or named abbreviation and checks it is in range
+func (f cronField) parseValue(s string) (int, error) {
+	if n, ok := f.names[strings.ToUpper(s)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if n < f.min || n > f.max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", n, f.min, f.max)
+	}
+	return n, nil
+}
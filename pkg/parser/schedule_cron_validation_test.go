@@ -0,0 +1,54 @@
+//go:build !integration
+
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateCronExpression(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr string
+	}{
+		{input: "0 0 * * *"},
+		{input: "*/15 * * * *"},
+		{input: "0 9-17/2 * * 1-5"},
+		{input: "0,15,30,45 * * * *"},
+		{input: "30 6 1,15 * *"},
+		{input: "0 9 * jan,jul mon"},
+		{input: "0 9 * DEC SUN-SAT"},
+		{input: "5/10 * * * *"},
+		{input: "0 0 * *", wantErr: "must have exactly 5 fields"},
+		{input: "60 * * * *", wantErr: `field 1 (minute) "60": value 60 out of range 0-59`},
+		{input: "0 24 * * *", wantErr: `field 2 (hour) "24": value 24 out of range 0-23`},
+		{input: "0 0 0 * *", wantErr: `field 3 (day-of-month) "0": value 0 out of range 1-31`},
+		{input: "0 0 * 13 *", wantErr: `field 4 (month) "13": value 13 out of range 1-12`},
+		{input: "0 0 * * 7", wantErr: `field 5 (day-of-week) "7": value 7 out of range 0-6`},
+		{input: "0 0 * * MOM", wantErr: `field 5 (day-of-week) "MOM": invalid value "MOM"`},
+		{input: "0 0 * MON *", wantErr: `field 4 (month) "MON": invalid value "MON"`},
+		{input: "0 17-9 * * *", wantErr: "range 17-9 has start greater than end"},
+		{input: "*/0 * * * *", wantErr: `invalid step "0"`},
+		{input: "0 0,,12 * * *", wantErr: "empty list element"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := ValidateCronExpression(tt.input)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateCronExpression(%q) unexpected error: %v", tt.input, err)
+				}
+				return
+			}
+			if err == nil {
+				t.Errorf("ValidateCronExpression(%q) expected error containing %q, got nil", tt.input, tt.wantErr)
+				return
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateCronExpression(%q) error = %q, want substring %q", tt.input, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
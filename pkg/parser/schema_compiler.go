@@ -100,6 +100,25 @@ var safeOutputMetaFields = map[string]bool{
 func GetSafeOutputTypeKeys() ([]string, error) {
 	schemaCompilerLog.Print("Extracting safe output type keys from main workflow schema")
 
+	allKeys, err := GetSafeOutputKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	// Extract keys that are actual safe output types (not meta-configuration)
+	var keys []string
+	for _, key := range allKeys {
+		if !safeOutputMetaFields[key] {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, nil
+}
+
+// GetSafeOutputKeys returns every key allowed under safe-outputs in the embedded main workflow schema,
+// including both safe output types and meta-configuration fields. Keys are sorted.
+func GetSafeOutputKeys() ([]string, error) {
 	// Parse the embedded schema JSON
 	var schemaDoc map[string]any
 	if err := json.Unmarshal([]byte(mainWorkflowSchema), &schemaDoc); err != nil {
@@ -122,12 +141,9 @@ func GetSafeOutputTypeKeys() ([]string, error) {
 		return nil, errors.New("schema missing 'properties.safe-outputs.properties' field")
 	}
 
-	// Extract keys that are actual safe output types (not meta-configuration)
-	var keys []string
+	keys := make([]string, 0, len(safeOutputsProperties))
 	for key := range safeOutputsProperties {
-		if !safeOutputMetaFields[key] {
-			keys = append(keys, key)
-		}
+		keys = append(keys, key)
 	}
 
 	// Sort keys for consistent ordering
@@ -219,7 +235,7 @@ func validateWithSchemaAndLocation(frontmatter map[string]any, schemaJSON, conte
 
 			if frontmatterStartIdx >= 0 && frontmatterEndIdx > frontmatterStartIdx {
 				frontmatterContent = actualFrontmatterContent
-				frontmatterStart = frontmatterStartIdx + 2 // +2 because we skip the opening "---" and use 1-based indexing
+				frontmatterStart = frontmatterStartIdx + 2 // +2 because we skip the opening fence and use 1-based indexing
 
 				// Use the frontmatter section plus a bit of context as context lines
 				contextStart := max(0, frontmatterStartIdx)
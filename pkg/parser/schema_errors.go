@@ -161,21 +161,24 @@ func stripAtPathPrefix(line string) string {
 }
 
 // findFrontmatterBounds finds the start and end indices of frontmatter in file lines
+// The frontmatter may be fenced as YAML (---), TOML (+++), or JSON (```json)
 // Returns: startIdx (-1 if not found), endIdx (-1 if not found), frontmatterContent
 func findFrontmatterBounds(lines []string) (startIdx int, endIdx int, frontmatterContent string) {
 	startIdx = -1
 	endIdx = -1
 
-	// Look for the opening "---"
+	// Look for the opening fence
+	var closingFence string
 	for i, line := range lines {
 		trimmed := strings.TrimSpace(line)
-		if trimmed == "---" {
+		if _, closing, ok := detectFrontmatterFormat(trimmed); ok {
 			startIdx = i
+			closingFence = closing
 			break
 		}
 		// Skip empty lines and comments at the beginning
 		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
-			// Found non-empty, non-comment line before "---" - no frontmatter
+			// Found non-empty, non-comment line before the opening fence - no frontmatter
 			return -1, -1, ""
 		}
 	}
@@ -184,17 +187,17 @@ func findFrontmatterBounds(lines []string) (startIdx int, endIdx int, frontmatte
 		return -1, -1, ""
 	}
 
-	// Look for the closing "---"
+	// Look for the closing fence
 	for i := startIdx + 1; i < len(lines); i++ {
 		trimmed := strings.TrimSpace(lines[i])
-		if trimmed == "---" {
+		if trimmed == closingFence {
 			endIdx = i
 			break
 		}
 	}
 
 	if endIdx == -1 {
-		// No closing "---" found
+		// No closing fence found
 		return -1, -1, ""
 	}
 
@@ -1998,3 +1998,30 @@ func TestGetSafeOutputTypeKeys(t *testing.T) {
 		}
 	}
 }
+
+// TestGetSafeOutputKeys tests that all safe-outputs keys, including meta fields, are extracted
+func TestGetSafeOutputKeys(t *testing.T) {
+	keys, err := GetSafeOutputKeys()
+	if err != nil {
+		t.Fatalf("GetSafeOutputKeys() returned error: %v", err)
+	}
+
+	keySet := make(map[string]bool)
+	for _, key := range keys {
+		keySet[key] = true
+	}
+
+	for _, expected := range []string{"create-issue", "add-comment", "staged", "env", "github-token"} {
+		if !keySet[expected] {
+			t.Errorf("GetSafeOutputKeys() missing expected key: %s", expected)
+		}
+	}
+
+	typeKeys, err := GetSafeOutputTypeKeys()
+	if err != nil {
+		t.Fatalf("GetSafeOutputTypeKeys() returned error: %v", err)
+	}
+	if len(typeKeys) >= len(keys) {
+		t.Errorf("GetSafeOutputKeys() should return more keys than GetSafeOutputTypeKeys(): %d <= %d", len(keys), len(typeKeys))
+	}
+}
@@ -0,0 +1,240 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var schemaUnknownFieldsLog = logger.New("parser:schema_unknown_fields")
+
+// UnknownFrontmatterField describes a frontmatter key that is not defined by the workflow schema
+type UnknownFrontmatterField struct {
+	Path       string // Location of the field, e.g. "on.workflow_call.inputs.name.descripton"
+	Field      string // The unknown key itself
+	Suggestion string // Closest known key at the same location, if any
+}
+
+// String formats the unknown field as a human-readable message
+func (f UnknownFrontmatterField) String() string {
+	if f.Suggestion != "" {
+		return fmt.Sprintf("unknown field '%s' at '%s'. Did you mean '%s'?", f.Field, f.Path, f.Suggestion)
+	}
+	return fmt.Sprintf("unknown field '%s' at '%s'", f.Field, f.Path)
+}
+
+var (
+	mainWorkflowSchemaDocOnce  sync.Once
+	mainWorkflowSchemaDoc      map[string]any
+	mainWorkflowSchemaDocError error
+)
+
+// getMainWorkflowSchemaDoc returns the parsed main workflow schema document, parsing it once and caching
+func getMainWorkflowSchemaDoc() (map[string]any, error) {
+	mainWorkflowSchemaDocOnce.Do(func() {
+		mainWorkflowSchemaDocError = json.Unmarshal([]byte(mainWorkflowSchema), &mainWorkflowSchemaDoc)
+	})
+	return mainWorkflowSchemaDoc, mainWorkflowSchemaDocError
+}
+
+// FindUnknownFrontmatterFields walks the frontmatter and returns every top-level and nested key
+// that the main workflow schema does not define, sorted by path. Objects the schema leaves
+// free-form (e.g. env maps or custom tool settings) are not reported.
+func FindUnknownFrontmatterFields(frontmatter map[string]any) ([]UnknownFrontmatterField, error) {
+	schemaDoc, err := getMainWorkflowSchemaDoc()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse main workflow schema: %w", err)
+	}
+
+	var unknown []UnknownFrontmatterField
+	collectUnknownFields(schemaDoc, []map[string]any{schemaDoc}, frontmatter, "", &unknown)
+
+	sort.Slice(unknown, func(i, j int) bool {
+		return unknown[i].Path < unknown[j].Path
+	})
+	schemaUnknownFieldsLog.Printf("Found %d unknown frontmatter fields", len(unknown))
+	return unknown, nil
+}
+
+// collectUnknownFields checks value against the union of the given schema nodes and appends unknown keys
+func collectUnknownFields(root map[string]any, nodes []map[string]any, value any, path string, unknown *[]UnknownFrontmatterField) {
+	variants := expandSchemaVariants(root, nodes)
+
+	switch v := value.(type) {
+	case map[string]any:
+		collectUnknownObjectFields(root, variants, v, path, unknown)
+	case []any:
+		var items []map[string]any
+		for _, variant := range variants {
+			if itemSchema, ok := variant["items"].(map[string]any); ok {
+				items = append(items, itemSchema)
+			}
+		}
+		if len(items) == 0 {
+			return
+		}
+		for i, item := range v {
+			collectUnknownFields(root, items, item, fmt.Sprintf("%s[%d]", path, i), unknown)
+		}
+	}
+}
+
+// collectUnknownObjectFields checks the keys of an object against the object variants of the schema
+func collectUnknownObjectFields(root map[string]any, variants []map[string]any, obj map[string]any, path string, unknown *[]UnknownFrontmatterField) {
+	var objectVariants []map[string]any
+	open := false
+	for _, variant := range variants {
+		_, hasProperties := variant["properties"].(map[string]any)
+		_, hasPatterns := variant["patternProperties"].(map[string]any)
+		additional, hasAdditional := variant["additionalProperties"]
+		if !hasProperties && !hasPatterns && !hasAdditional {
+			// A plain object schema without declared keys accepts anything
+			if variant["type"] == "object" {
+				open = true
+			}
+			continue
+		}
+		if allowed, ok := additional.(bool); ok && allowed {
+			open = true
+		}
+		objectVariants = append(objectVariants, variant)
+	}
+	if len(objectVariants) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fieldPath := key
+		if path != "" {
+			fieldPath = path + "." + key
+		}
+
+		var children []map[string]any
+		known := false
+		for _, variant := range objectVariants {
+			if properties, ok := variant["properties"].(map[string]any); ok {
+				if child, ok := properties[key].(map[string]any); ok {
+					children = append(children, child)
+					known = true
+					continue
+				}
+			}
+			matchedPattern := false
+			if patterns, ok := variant["patternProperties"].(map[string]any); ok {
+				for pattern, child := range patterns {
+					if re, err := regexp.Compile(pattern); err == nil && re.MatchString(key) {
+						if childMap, ok := child.(map[string]any); ok {
+							children = append(children, childMap)
+						}
+						matchedPattern = true
+					}
+				}
+			}
+			if matchedPattern {
+				known = true
+				continue
+			}
+			if child, ok := variant["additionalProperties"].(map[string]any); ok {
+				children = append(children, child)
+				known = true
+			}
+		}
+
+		if !known {
+			if !open {
+				*unknown = append(*unknown, UnknownFrontmatterField{
+					Path:       fieldPath,
+					Field:      key,
+					Suggestion: closestKnownField(key, objectVariants),
+				})
+			}
+			continue
+		}
+		collectUnknownFields(root, children, obj[key], fieldPath, unknown)
+	}
+}
+
+// expandSchemaVariants resolves $ref and flattens oneOf/anyOf so every alternative is checked
+func expandSchemaVariants(root map[string]any, nodes []map[string]any) []map[string]any {
+	var variants []map[string]any
+	seen := make(map[string]bool)
+
+	var expand func(node map[string]any, depth int)
+	expand = func(node map[string]any, depth int) {
+		if depth > 10 {
+			return
+		}
+		if ref, ok := node["$ref"].(string); ok {
+			if seen[ref] {
+				return
+			}
+			seen[ref] = true
+			if resolved := resolveSchemaRef(root, ref); resolved != nil {
+				expand(resolved, depth+1)
+			}
+			return
+		}
+		variants = append(variants, node)
+		for _, keyword := range []string{"oneOf", "anyOf"} {
+			alternatives, ok := node[keyword].([]any)
+			if !ok {
+				continue
+			}
+			for _, alternative := range alternatives {
+				if alternativeMap, ok := alternative.(map[string]any); ok {
+					expand(alternativeMap, depth+1)
+				}
+			}
+		}
+	}
+
+	for _, node := range nodes {
+		expand(node, 0)
+	}
+	return variants
+}
+
+// resolveSchemaRef resolves a local JSON pointer reference such as "#/$defs/engine_config"
+func resolveSchemaRef(root map[string]any, ref string) map[string]any {
+	pointer, ok := strings.CutPrefix(ref, "#/")
+	if !ok {
+		return nil
+	}
+	var current any = root
+	for _, segment := range strings.Split(pointer, "/") {
+		currentMap, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = currentMap[segment]
+	}
+	resolved, _ := current.(map[string]any)
+	return resolved
+}
+
+// closestKnownField returns the declared property closest to key, or "" when nothing is close enough
+func closestKnownField(key string, variants []map[string]any) string {
+	var candidates []string
+	for _, variant := range variants {
+		if properties, ok := variant["properties"].(map[string]any); ok {
+			for name := range properties {
+				candidates = append(candidates, name)
+			}
+		}
+	}
+	if matches := FindClosestMatches(key, candidates, 1); len(matches) > 0 {
+		return matches[0]
+	}
+	return ""
+}
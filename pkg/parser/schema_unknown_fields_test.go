@@ -0,0 +1,103 @@
+//go:build !integration
+
+package parser
+
+import (
+	"testing"
+)
+
+func TestFindUnknownFrontmatterFields(t *testing.T) {
+	tests := []struct {
+		name        string
+		frontmatter map[string]any
+		want        []UnknownFrontmatterField
+	}{
+		{
+			name: "known fields only",
+			frontmatter: map[string]any{
+				"on":          map[string]any{"issues": map[string]any{"types": []any{"opened"}}},
+				"engine":      "copilot",
+				"permissions": map[string]any{"contents": "read"},
+				"env":         map[string]any{"ANY_NAME": "value"},
+			},
+		},
+		{
+			name: "misspelled top-level field",
+			frontmatter: map[string]any{
+				"on":     "push",
+				"enigne": "copilot",
+			},
+			want: []UnknownFrontmatterField{
+				{Path: "enigne", Field: "enigne", Suggestion: "engine"},
+			},
+		},
+		{
+			name: "misspelled nested field in an open schema object",
+			frontmatter: map[string]any{
+				"on": map[string]any{
+					"workflow_call": map[string]any{
+						"inputs": map[string]any{
+							"target": map[string]any{"descripton": "Target", "type": "string"},
+						},
+					},
+				},
+			},
+			want: []UnknownFrontmatterField{
+				{Path: "on.workflow_call.inputs.target.descripton", Field: "descripton", Suggestion: "description"},
+			},
+		},
+		{
+			name: "unknown field inside array items",
+			frontmatter: map[string]any{
+				"on":    "push",
+				"cache": []any{map[string]any{"key": "deps", "path": "node_modules", "restore-key": "deps-"}},
+			},
+			want: []UnknownFrontmatterField{
+				{Path: "cache[0].restore-key", Field: "restore-key", Suggestion: "restore-keys"},
+			},
+		},
+		{
+			name: "free-form custom steps are not reported",
+			frontmatter: map[string]any{
+				"on":    "push",
+				"steps": []any{map[string]any{"run": "echo hi", "nmae": "greet"}},
+			},
+		},
+		{
+			name: "free-form custom tool settings are not reported",
+			frontmatter: map[string]any{
+				"on":    "push",
+				"tools": map[string]any{"my-tool": map[string]any{"anything": true}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FindUnknownFrontmatterFields(tt.frontmatter)
+			if err != nil {
+				t.Fatalf("FindUnknownFrontmatterFields() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("FindUnknownFrontmatterFields() = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("field %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestUnknownFrontmatterFieldString(t *testing.T) {
+	withSuggestion := UnknownFrontmatterField{Path: "on.workflow_call.inputs.x.requird", Field: "requird", Suggestion: "required"}
+	if got, want := withSuggestion.String(), "unknown field 'requird' at 'on.workflow_call.inputs.x.requird'. Did you mean 'required'?"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	withoutSuggestion := UnknownFrontmatterField{Path: "xyz", Field: "xyz"}
+	if got, want := withoutSuggestion.String(), "unknown field 'xyz' at 'xyz'"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
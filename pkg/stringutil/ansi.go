@@ -6,7 +6,8 @@ import (
 )
 
 // StripANSI removes ANSI escape codes from a string using a comprehensive byte scanner.
-// It handles CSI sequences (\x1b[), OSC sequences (\x1b]), G0/G1 character set selections,
+// It handles CSI sequences (\x1b[), OSC sequences (\x1b]), DCS/PM/APC string sequences
+// (\x1bP, \x1b^, \x1b_), G0/G1 character set selections,
 // keypad mode sequences, reset sequences, and other common 2-character escape sequences.
 //
 // This is more thorough than regex-based approaches and correctly handles edge cases
@@ -46,19 +47,13 @@ func StripANSI(s string) string {
 					}
 				}
 			case ']':
-				// OSC sequence: \x1b]...terminator
+				// OSC sequence: \x1b]...terminator (window titles, hyperlinks)
 				// Terminators: \x07 (BEL) or \x1b\\ (ST)
-				i += 2 // Skip ESC and ]
-				for i < len(s) {
-					if s[i] == '\x07' {
-						i++ // Skip BEL
-						break
-					} else if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
-						i += 2 // Skip ESC and \
-						break
-					}
-					i++
-				}
+				i = skipStringSequence(s, i+2, true)
+			case 'P', '^', '_':
+				// DCS (\x1bP), PM (\x1b^) and APC (\x1b_) sequences
+				// carry a string payload terminated by \x1b\\ (ST)
+				i = skipStringSequence(s, i+2, false)
 			case '(':
 				// G0 character set selection: \x1b(char
 				i += 2 // Skip ESC and (
@@ -112,3 +107,20 @@ func isFinalCSIChar(b byte) bool {
 func isCSIParameterChar(b byte) bool {
 	return (b >= 0x20 && b <= 0x2F) || (b >= 0x30 && b <= 0x3F)
 }
+
+// skipStringSequence skips the payload of a control string (OSC, DCS, PM, APC)
+// starting at index i and returns the index just past its terminator.
+// All control strings end with ST (\x1b\\); OSC sequences may also end with BEL (\x07).
+// An unterminated sequence consumes the rest of the string.
+func skipStringSequence(s string, i int, allowBEL bool) int {
+	for i < len(s) {
+		if allowBEL && s[i] == '\x07' {
+			return i + 1 // Skip BEL
+		}
+		if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+			return i + 2 // Skip ESC and \
+		}
+		i++
+	}
+	return i
+}
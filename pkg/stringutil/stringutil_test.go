@@ -557,6 +557,72 @@ func TestStripANSIEscapeCodes(t *testing.T) {
 	}
 }
 
+func TestStripANSIControlStrings(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "OSC window title terminated by BEL",
+			input:    "\x1b]0;my title\x07Hello",
+			expected: "Hello",
+		},
+		{
+			name:     "OSC window title terminated by ST",
+			input:    "\x1b]2;my title\x1b\\Hello",
+			expected: "Hello",
+		},
+		{
+			name:     "OSC 8 hyperlink",
+			input:    "See \x1b]8;;https://example.com\x1b\\the docs\x1b]8;;\x1b\\ for details",
+			expected: "See the docs for details",
+		},
+		{
+			name:     "OSC 8 hyperlink terminated by BEL",
+			input:    "\x1b]8;;https://example.com\x07link\x1b]8;;\x07",
+			expected: "link",
+		},
+		{
+			name:     "DCS sequence",
+			input:    "before\x1bP1$r0m\x1b\\after",
+			expected: "beforeafter",
+		},
+		{
+			name:     "DCS sequence does not end at BEL",
+			input:    "a\x1bPdata\x07more\x1b\\b",
+			expected: "ab",
+		},
+		{
+			name:     "APC and PM sequences",
+			input:    "\x1b_apc payload\x1b\\x\x1b^pm payload\x1b\\y",
+			expected: "xy",
+		},
+		{
+			name:     "unterminated DCS consumes remainder",
+			input:    "text\x1bPunterminated",
+			expected: "text",
+		},
+		{
+			name:     "mixed CSI and OSC",
+			input:    "\x1b]0;title\x07\x1b[31mred\x1b[0m",
+			expected: "red",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := StripANSI(tt.input)
+			if result != tt.expected {
+				t.Errorf("StripANSI(%q) = %q, expected %q", tt.input, result, tt.expected)
+			}
+			if strings.Contains(result, "\x1b") {
+				t.Errorf("Result still contains escape characters: %q", result)
+			}
+		})
+	}
+}
+
 func BenchmarkStripANSIEscapeCodes_Clean(b *testing.B) {
 	s := "This is a clean string without any ANSI codes"
 	for b.Loop() {
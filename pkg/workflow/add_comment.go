@@ -25,6 +25,7 @@ type AddCommentsConfig struct {
 	HideOlderComments    *string  `yaml:"hide-older-comments,omitempty"` // When true, minimizes/hides all previous comments from the same workflow before creating the new comment
 	AllowedReasons       []string `yaml:"allowed-reasons,omitempty"`     // List of allowed reasons for hiding older comments (default: all reasons allowed)
 	Discussions          *bool    `yaml:"discussions,omitempty"`         // When false, excludes discussions:write permission. Default (nil or true) includes discussions:write for GitHub Apps with Discussions permission.
+	AllowedDomains       []string `yaml:"allowed-domains,omitempty"`     // Domains that links in comments may point to; links to other domains are redacted (default: no restriction)
 }
 
 // buildCreateOutputAddCommentJob creates the add_comment job
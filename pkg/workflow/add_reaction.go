@@ -0,0 +1,70 @@
+package workflow
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var addReactionLog = logger.New("workflow:add_reaction")
+
+// addReactionTypes lists the reactions the add-reaction safe output can add, in GitHub's order
+var addReactionTypes = []string{"+1", "-1", "laugh", "confused", "heart", "hooray", "rocket", "eyes"}
+
+// AddReactionConfig holds configuration for adding reactions to the triggering item from agent output
+type AddReactionConfig struct {
+	BaseSafeOutputConfig `yaml:",inline"`
+	AllowedReactions     []string `yaml:"allowed-reactions,omitempty"` // Reactions the agent may add (default: all reactions allowed)
+}
+
+// parseAddReactionConfig handles add-reaction configuration
+func (c *Compiler) parseAddReactionConfig(outputMap map[string]any) *AddReactionConfig {
+	addReactionLog.Print("Parsing add-reaction configuration")
+	configData, exists := outputMap["add-reaction"]
+	if !exists {
+		return nil
+	}
+
+	addReactionConfig := &AddReactionConfig{}
+	if configMap, ok := configData.(map[string]any); ok {
+		// Parse allowed-reactions; YAML parses unquoted +1 and -1 as integers
+		if allowedReactions, exists := configMap["allowed-reactions"]; exists {
+			if reactionsArray, ok := allowedReactions.([]any); ok {
+				for _, reaction := range reactionsArray {
+					reactionStr, err := parseReactionValue(reaction)
+					if err != nil {
+						// Keep the raw value so validation can report it
+						reactionStr = fmt.Sprintf("%v", reaction)
+					}
+					addReactionConfig.AllowedReactions = append(addReactionConfig.AllowedReactions, reactionStr)
+				}
+			}
+		}
+
+		// Parse common base fields with default max of 1
+		c.parseBaseSafeOutputConfig(configMap, &addReactionConfig.BaseSafeOutputConfig, 1)
+
+		addReactionLog.Printf("Parsed add-reaction config: allowed_reactions=%v", addReactionConfig.AllowedReactions)
+	} else {
+		// If configData is nil or not a map, still set the default max
+		addReactionConfig.Max = defaultIntStr(1)
+	}
+
+	return addReactionConfig
+}
+
+// validateAddReactionTypes ensures every allowed reaction is one GitHub supports
+func validateAddReactionTypes(config *SafeOutputsConfig) error {
+	if config == nil || config.AddReaction == nil {
+		return nil
+	}
+
+	for i, reaction := range config.AddReaction.AllowedReactions {
+		if !slices.Contains(addReactionTypes, reaction) {
+			return fmt.Errorf("safe-outputs.add-reaction.allowed-reactions[%d] has invalid value %q. Expected one of: %s", i, reaction, strings.Join(addReactionTypes, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAddReactionConfig(t *testing.T) {
+	compiler := NewCompiler()
+
+	config := compiler.parseAddReactionConfig(map[string]any{
+		"add-reaction": map[string]any{
+			"allowed-reactions": []any{1, -1, "rocket", "thumbsup"},
+			"max":               2,
+		},
+	})
+	require.NotNil(t, config, "Config should be parsed")
+	assert.Equal(t, []string{"+1", "-1", "rocket", "thumbsup"}, config.AllowedReactions, "Integer reactions should be converted and unknown names kept for validation")
+	require.NotNil(t, config.Max, "max should be parsed")
+	assert.Equal(t, "2", *config.Max, "max should be parsed")
+
+	config = compiler.parseAddReactionConfig(map[string]any{"add-reaction": nil})
+	require.NotNil(t, config, "Null config should enable the output")
+	require.NotNil(t, config.Max, "Default max should be set")
+	assert.Equal(t, "1", *config.Max, "Default max should be 1")
+
+	assert.Nil(t, compiler.parseAddReactionConfig(map[string]any{}), "Missing key should not enable the output")
+}
+
+func TestValidateAddReactionTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *SafeOutputsConfig
+		wantErr string
+	}{
+		{
+			name:   "nil safe outputs",
+			config: nil,
+		},
+		{
+			name:   "defaults",
+			config: &SafeOutputsConfig{AddReaction: &AddReactionConfig{}},
+		},
+		{
+			name:   "known reactions",
+			config: &SafeOutputsConfig{AddReaction: &AddReactionConfig{AllowedReactions: []string{"+1", "eyes", "rocket"}}},
+		},
+		{
+			name:    "unknown reaction",
+			config:  &SafeOutputsConfig{AddReaction: &AddReactionConfig{AllowedReactions: []string{"eyes", "thumbsup"}}},
+			wantErr: `allowed-reactions[1] has invalid value "thumbsup"`,
+		},
+		{
+			name:    "none is not a reaction",
+			config:  &SafeOutputsConfig{AddReaction: &AddReactionConfig{AllowedReactions: []string{"none"}}},
+			wantErr: `allowed-reactions[0] has invalid value "none"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAddReactionTypes(tt.config)
+			if tt.wantErr == "" {
+				assert.NoError(t, err, "Validation should pass")
+				return
+			}
+			require.Error(t, err, "Validation should fail")
+			assert.Contains(t, err.Error(), tt.wantErr, "Error message mismatch")
+		})
+	}
+}
+
+func TestAddReactionSafeOutputsConfig(t *testing.T) {
+	data := &WorkflowData{
+		SafeOutputs: &SafeOutputsConfig{
+			AddReaction: &AddReactionConfig{
+				BaseSafeOutputConfig: BaseSafeOutputConfig{Max: strPtr("2")},
+				AllowedReactions:     []string{"+1", "rocket"},
+			},
+		},
+	}
+
+	config := generateSafeOutputsConfig(data)
+	assert.Contains(t, config, `"add_reaction":{"allowed_reactions":["+1","rocket"],"max":2}`, "Safe outputs config should include add_reaction")
+
+	permissions := ComputePermissionsForSafeOutputs(data.SafeOutputs)
+	for _, scope := range []PermissionScope{PermissionIssues, PermissionPullRequests, PermissionDiscussions} {
+		level, ok := permissions.Get(scope)
+		assert.True(t, ok, "Permission %s should be set", scope)
+		assert.Equal(t, PermissionWrite, level, "Permission %s should be write", scope)
+	}
+}
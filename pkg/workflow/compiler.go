@@ -143,18 +143,20 @@ func (c *Compiler) validateWorkflowData(workflowData *WorkflowData, markdownPath
 		return formatCompilerError(markdownPath, "error", err.Error(), err)
 	}
 
-	// Validate safe-outputs target configuration
-	log.Printf("Validating safe-outputs target fields")
-	if err := validateSafeOutputsTarget(workflowData.SafeOutputs); err != nil {
-		return formatCompilerError(markdownPath, "error", err.Error(), err)
-	}
-
 	// Validate safe-outputs allowed-domains configuration
 	log.Printf("Validating safe-outputs allowed-domains")
 	if err := c.validateSafeOutputsAllowedDomains(workflowData.SafeOutputs); err != nil {
 		return formatCompilerError(markdownPath, "error", err.Error(), err)
 	}
 
+	// Validate the rest of the safe-outputs configuration (targets, limits, labels, ...)
+	for _, check := range safeOutputsConfigChecks {
+		log.Printf("Validating safe-outputs %s", check.name)
+		if err := check.check(workflowData.SafeOutputs); err != nil {
+			return formatCompilerError(markdownPath, "error", err.Error(), err)
+		}
+	}
+
 	// Validate network allowed domains configuration
 	log.Printf("Validating network allowed domains")
 	if err := c.validateNetworkAllowedDomains(workflowData.NetworkPermissions); err != nil {
@@ -333,6 +335,12 @@ Ensure proper audience validation and trust policies are configured.`
 		return formatCompilerError(markdownPath, "error", fmt.Sprintf("dispatch-workflow validation failed: %v", err), err)
 	}
 
+	// Resolve the mentions allowlist against GitHub (users, team members) before generating YAML
+	if !c.skipValidation {
+		log.Print("Resolving safe-outputs mentions allowlist")
+		c.resolveMentionsAllowlist(workflowData, markdownPath)
+	}
+
 	return nil
 }
 
@@ -201,6 +201,10 @@ func (c *Compiler) setupEngineAndImports(result *parser.FrontmatterResult, clean
 	// Apply the default AI engine setting if not specified
 	if engineSetting == "" {
 		defaultEngine := c.engineRegistry.GetDefaultEngine()
+		if err := c.validateToolsHaveEngine(result.Frontmatter, defaultEngine.GetID()); err != nil {
+			orchestratorEngineLog.Printf("Tools without engine validation failed: %v", err)
+			return nil, err
+		}
 		engineSetting = defaultEngine.GetID()
 		log.Printf("No 'engine:' setting found, defaulting to: %s", engineSetting)
 		// Create a default EngineConfig with the default engine ID if not already set
@@ -225,6 +229,11 @@ func (c *Compiler) setupEngineAndImports(result *parser.FrontmatterResult, clean
 		return nil, err
 	}
 
+	if err := c.validateEngineVersion(engineConfig, agenticEngine.GetID()); err != nil {
+		orchestratorEngineLog.Printf("Engine version validation failed: %v", err)
+		return nil, err
+	}
+
 	log.Printf("AI engine: %s (%s)", agenticEngine.GetDisplayName(), engineSetting)
 	if agenticEngine.IsExperimental() && c.verbose {
 		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("Using experimental engine: "+agenticEngine.GetDisplayName()))
@@ -288,6 +297,15 @@ func (c *Compiler) setupEngineAndImports(result *parser.FrontmatterResult, clean
 		return nil, err
 	}
 
+	// Validate that safe-outputs (including imported ones) only use known keys in strict mode
+	orchestratorEngineLog.Printf("Validating safe-outputs keys (strict=%v)", c.strictMode)
+	if err := c.validateStrictSafeOutputKeys(result.Frontmatter, importsResult.MergedSafeOutputs); err != nil {
+		orchestratorEngineLog.Printf("Safe-outputs key validation failed: %v", err)
+		// Restore strict mode before returning error
+		c.strictMode = initialStrictModeForFirewall
+		return nil, err
+	}
+
 	// Restore the strict mode state after network check
 	c.strictMode = initialStrictModeForFirewall
 
@@ -98,6 +98,9 @@ func (c *Compiler) parseFrontmatterSection(markdownPath string) (*frontmatterPar
 		return nil, errors.New("no markdown content found")
 	}
 
+	// Let safe-outputs fields from newer schema versions through unless in strict mode
+	frontmatterForValidation = c.passThroughUnknownSafeOutputFields(frontmatterForValidation, cleanPath)
+
 	// Validate main workflow frontmatter contains only expected entries
 	orchestratorFrontmatterLog.Printf("Validating main workflow frontmatter schema")
 	if err := parser.ValidateMainWorkflowFrontmatterWithSchemaAndLocation(frontmatterForValidation, cleanPath); err != nil {
@@ -105,6 +108,9 @@ func (c *Compiler) parseFrontmatterSection(markdownPath string) (*frontmatterPar
 		return nil, err
 	}
 
+	// Warn about keys the schema accepts but does not define (e.g. typos in workflow_call inputs)
+	c.warnUnknownFrontmatterFields(frontmatterForValidation, cleanPath)
+
 	// Validate event filter mutual exclusivity (branches/branches-ignore, paths/paths-ignore)
 	if err := ValidateEventFilters(frontmatterForValidation); err != nil {
 		orchestratorFrontmatterLog.Printf("Event filter validation failed: %v", err)
@@ -173,3 +179,17 @@ func (c *Compiler) copyFrontmatterWithoutInternalMarkers(frontmatter map[string]
 	}
 	return copy
 }
+
+// warnUnknownFrontmatterFields emits a compiler warning for each frontmatter key that is not
+// defined by the workflow schema. Such keys are silently ignored by the compiler.
+func (c *Compiler) warnUnknownFrontmatterFields(frontmatter map[string]any, markdownPath string) {
+	unknown, err := parser.FindUnknownFrontmatterFields(frontmatter)
+	if err != nil {
+		orchestratorFrontmatterLog.Printf("Unknown field check skipped: %v", err)
+		return
+	}
+	for _, field := range unknown {
+		fmt.Fprintln(os.Stderr, formatCompilerMessage(markdownPath, "warning", field.String()+" (field is ignored)"))
+		c.IncrementWarningCount()
+	}
+}
@@ -50,8 +50,9 @@ func (c *Compiler) processToolsAndMarkdown(result *parser.FrontmatterResult, cle
 	orchestratorToolsLog.Printf("Processing tools and markdown")
 	log.Print("Processing tools and includes...")
 
-	// Extract SafeOutputs configuration early so we can use it when applying default tools
-	safeOutputs := c.extractSafeOutputsConfig(result.Frontmatter)
+	// Extract SafeOutputs configuration early so we can use it when applying default tools.
+	// Imported definitions of types the main workflow also defines are merged in first.
+	safeOutputs := c.extractSafeOutputsConfig(c.mergeImportedSafeOutputTypes(result.Frontmatter, importsResult.MergedSafeOutputs))
 
 	// Extract SecretMasking configuration
 	secretMasking := c.extractSecretMaskingConfig(result.Frontmatter)
@@ -77,7 +78,7 @@ func (c *Compiler) processToolsAndMarkdown(result *parser.FrontmatterResult, cle
 
 	// Process @include directives to extract additional tools
 	orchestratorToolsLog.Printf("Expanding includes for tools")
-	includedTools, includedToolFiles, err := parser.ExpandIncludesWithManifest(result.Markdown, markdownDir, true)
+	includedTools, includedToolFiles, err := parser.ExpandIncludesWithManifestForEngine(result.Markdown, markdownDir, true, agenticEngine.GetID(), c.engineRegistry.GetSupportedEngines())
 	if err != nil {
 		orchestratorToolsLog.Printf("Failed to expand includes for tools: %v", err)
 		return nil, fmt.Errorf("failed to expand includes for tools: %w", err)
@@ -214,6 +215,13 @@ func (c *Compiler) processToolsAndMarkdown(result *parser.FrontmatterResult, cle
 		orchestratorToolsLog.Printf("MCP configuration validation failed: %v", err)
 		return nil, err
 	}
+	if err := validateMCPContainerImages(tools, c.allowedMCPImages); err != nil {
+		orchestratorToolsLog.Printf("MCP container image validation failed: %v", err)
+		return nil, err
+	}
+	if err := c.checkMCPMountSources(tools); err != nil {
+		return nil, err
+	}
 
 	if !agenticEngine.SupportsToolsAllowlist() {
 		// For engines that don't support tool allowlists (like custom engine), ignore tools section and provide warnings
@@ -238,7 +246,7 @@ func (c *Compiler) processToolsAndMarkdown(result *parser.FrontmatterResult, cle
 	c.validateWebSearchSupport(tools, agenticEngine)
 
 	// Process @include directives in markdown content
-	markdownContent, includedMarkdownFiles, err := parser.ExpandIncludesWithManifest(result.Markdown, markdownDir, false)
+	markdownContent, includedMarkdownFiles, err := parser.ExpandIncludesWithManifestForEngine(result.Markdown, markdownDir, false, agenticEngine.GetID(), c.engineRegistry.GetSupportedEngines())
 	if err != nil {
 		return nil, fmt.Errorf("failed to expand includes in markdown: %w", err)
 	}
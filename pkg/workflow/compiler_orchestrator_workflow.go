@@ -484,7 +484,7 @@ func (c *Compiler) extractAdditionalConfigurations(
 	topSafeJobs := extractSafeJobsFromFrontmatter(frontmatter)
 
 	// Process @include directives to extract additional safe-outputs configurations
-	includedSafeOutputsConfigs, err := parser.ExpandIncludesForSafeOutputs(markdown, markdownDir)
+	includedSafeOutputsConfigs, err := parser.ExpandIncludesForSafeOutputs(markdown, markdownDir, workflowData.AI)
 	if err != nil {
 		return fmt.Errorf("failed to expand includes for safe-outputs: %w", err)
 	}
@@ -163,6 +163,7 @@ var handlerRegistry = map[string]handlerBuilder{
 			AddTemplatableBool("hide_older_comments", c.HideOlderComments).
 			AddIfNotEmpty("target-repo", c.TargetRepoSlug).
 			AddStringSlice("allowed_repos", c.AllowedRepos).
+			AddStringSlice("allowed_domains", c.AllowedDomains).
 			Build()
 	},
 	"create_discussion": func(cfg *SafeOutputsConfig) map[string]any {
@@ -407,6 +408,7 @@ var handlerRegistry = map[string]handlerBuilder{
 			AddTemplatableInt("max", c.Max).
 			AddIfNotEmpty("target", c.Target).
 			AddStringPtr("footer", getEffectiveFooterString(c.Footer, cfg.Footer)).
+			AddStringSlice("allowed_events", c.effectiveAllowedEvents()).
 			Build()
 	},
 	"reply_to_pull_request_review_comment": func(cfg *SafeOutputsConfig) map[string]any {
@@ -526,6 +528,27 @@ var handlerRegistry = map[string]handlerBuilder{
 			AddStringSlice("allowed_repos", c.AllowedRepos).
 			Build()
 	},
+	"add_reaction": func(cfg *SafeOutputsConfig) map[string]any {
+		if cfg.AddReaction == nil {
+			return nil
+		}
+		c := cfg.AddReaction
+		return newHandlerConfigBuilder().
+			AddTemplatableInt("max", c.Max).
+			AddStringSlice("allowed_reactions", c.AllowedReactions).
+			Build()
+	},
+	"push_files": func(cfg *SafeOutputsConfig) map[string]any {
+		if cfg.PushFiles == nil {
+			return nil
+		}
+		c := cfg.PushFiles
+		return newHandlerConfigBuilder().
+			AddTemplatableInt("max", c.Max).
+			AddStringSlice("allowed_branches", c.AllowedBranches).
+			AddStringSlice("allowed_paths", c.AllowedPaths).
+			Build()
+	},
 	"dispatch_workflow": func(cfg *SafeOutputsConfig) map[string]any {
 		if cfg.DispatchWorkflow == nil {
 			return nil
@@ -540,6 +563,11 @@ var handlerRegistry = map[string]handlerBuilder{
 			builder.AddDefault("workflow_files", c.WorkflowFiles)
 		}
 
+		// Add input schemas so agent-supplied inputs are validated before dispatching
+		if len(c.WorkflowInputs) > 0 {
+			builder.AddDefault("workflow_inputs", c.WorkflowInputs)
+		}
+
 		return builder.Build()
 	},
 	"missing_tool": func(cfg *SafeOutputsConfig) map[string]any {
@@ -89,10 +89,14 @@ func (c *Compiler) ParseWorkflowString(content string, virtualPath string) (*Wor
 		return nil, &SharedWorkflowError{Path: cleanPath}
 	}
 
+	// Let safe-outputs fields from newer schema versions through unless in strict mode
+	frontmatterForValidation = c.passThroughUnknownSafeOutputFields(frontmatterForValidation, cleanPath)
+
 	// Validate frontmatter against schema
 	if err := parser.ValidateMainWorkflowFrontmatterWithSchemaAndLocation(frontmatterForValidation, cleanPath); err != nil {
 		return nil, err
 	}
+	c.warnUnknownFrontmatterFields(frontmatterForValidation, cleanPath)
 
 	// Build parse result to reuse the rest of the orchestrator pipeline
 	parseResult := &frontmatterParseResult{
@@ -84,6 +84,16 @@ func WithInlinePrompt(inline bool) CompilerOption {
 	return func(c *Compiler) { c.inlinePrompt = inline }
 }
 
+// WithCheckMountSources configures whether to check that MCP bind mount sources exist
+func WithCheckMountSources(check bool) CompilerOption {
+	return func(c *Compiler) { c.checkMountSources = check }
+}
+
+// WithAllowedMCPImages restricts the container images custom MCP servers may use
+func WithAllowedMCPImages(images []string) CompilerOption {
+	return func(c *Compiler) { c.allowedMCPImages = images }
+}
+
 // FileTracker interface for tracking files created during compilation
 type FileTracker interface {
 	TrackCreated(filePath string)
@@ -141,6 +151,8 @@ type Compiler struct {
 	contentOverride         string              // If set, use this content instead of reading from disk (for Wasm/in-memory compilation)
 	skipHeader              bool                // If true, skip ASCII art header in generated YAML (for Wasm/editor mode)
 	inlinePrompt            bool                // If true, inline markdown content in YAML instead of using runtime-import macros (for Wasm builds)
+	checkMountSources       bool                // If true, check that MCP bind mount sources exist on this machine
+	allowedMCPImages        []string            // Allowed MCP container images or "/"-terminated prefixes; empty allows all
 }
 
 // NewCompiler creates a new workflow compiler with functional options.
@@ -506,6 +518,8 @@ type SafeOutputsConfig struct {
 	CreateProjectStatusUpdates      *CreateProjectStatusUpdateConfig       `yaml:"create-project-status-update,omitempty"` // Create GitHub project status updates
 	LinkSubIssue                    *LinkSubIssueConfig                    `yaml:"link-sub-issue,omitempty"`               // Link issues as sub-issues
 	HideComment                     *HideCommentConfig                     `yaml:"hide-comment,omitempty"`                 // Hide comments
+	AddReaction                     *AddReactionConfig                     `yaml:"add-reaction,omitempty"`                 // Add reactions to the triggering item
+	PushFiles                       *PushFilesConfig                       `yaml:"push-files,omitempty"`                   // Commit files to a branch without opening a pull request
 	DispatchWorkflow                *DispatchWorkflowConfig                `yaml:"dispatch-workflow,omitempty"`            // Dispatch workflow_dispatch events to other workflows
 	MissingTool                     *MissingToolConfig                     `yaml:"missing-tool,omitempty"`                 // Optional for reporting missing functionality
 	MissingData                     *MissingDataConfig                     `yaml:"missing-data,omitempty"`                 // Optional for reporting missing data required to achieve goals
@@ -526,6 +540,9 @@ type SafeOutputsConfig struct {
 	GroupReports                    bool                                   `yaml:"group-reports,omitempty"`             // If true, create parent "Failed runs" issue for agent failures (default: false)
 	MaxBotMentions                  *string                                `yaml:"max-bot-mentions,omitempty"`          // Maximum bot trigger references (e.g. 'fixes #123') allowed before filtering. Default: 10. Supports integer or GitHub Actions expression.
 	AutoInjectedCreateIssue         bool                                   `yaml:"-"`                                   // Internal: true when create-issues was automatically injected by the compiler (not user-configured)
+	Passthrough                     map[string]any                         `yaml:"-"`                                   // Fields the schema does not define, keyed by path under safe-outputs (e.g. "create-issue.new-field"), re-emitted in the generated config
+
+	source map[string]any // safe-outputs frontmatter the config was parsed from, used to validate its keys
 }
 
 // SafeOutputMessagesConfig holds custom message templates for safe-output footer and notification messages
@@ -566,6 +583,14 @@ type MentionsConfig struct {
 	// Allowed is a list of user/bot names always allowed (bots not allowed by default)
 	Allowed []string `yaml:"allowed,omitempty" json:"allowed,omitempty"`
 
+	// Denied is a list of user/bot names never allowed, even when they match the context,
+	// team membership, or teams. It must not overlap with Allowed.
+	Denied []string `yaml:"denied,omitempty" json:"denied,omitempty"`
+
+	// Teams is a list of GitHub team slugs (org/team) whose members are allowed when
+	// AllowTeamMembers is enabled. Teams are expanded to member logins at runtime.
+	Teams []string `yaml:"teams,omitempty" json:"teams,omitempty"`
+
 	// Max is the maximum number of mentions per message (default: 50)
 	Max *int `yaml:"max,omitempty" json:"max,omitempty"`
 }
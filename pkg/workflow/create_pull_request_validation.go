@@ -0,0 +1,47 @@
+package workflow
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var createPullRequestValidationLog = logger.New("workflow:create_pull_request_validation")
+
+// reviewerUserPattern matches a GitHub username (1-39 alphanumeric characters or single hyphens)
+var reviewerUserPattern = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9]|-[A-Za-z0-9]){0,38}$`)
+
+// reviewerTeamPattern matches an organization team slug in the form "org/team"
+var reviewerTeamPattern = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9]|-[A-Za-z0-9]){0,38}/[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// validateCreatePullRequestReviewers validates the reviewers of the create-pull-request safe output.
+// Each reviewer must be one of:
+//   - A GitHub username (e.g., "octocat") or "copilot"
+//   - An organization team slug (e.g., "octo-org/maintainers")
+//   - A GitHub Actions expression (e.g., "${{ inputs.reviewer }}")
+func validateCreatePullRequestReviewers(config *SafeOutputsConfig) error {
+	if config == nil || config.CreatePullRequests == nil || len(config.CreatePullRequests.Reviewers) == 0 {
+		return nil
+	}
+
+	reviewers := config.CreatePullRequests.Reviewers
+	createPullRequestValidationLog.Printf("Validating %d create-pull-request reviewers", len(reviewers))
+
+	for i, reviewer := range reviewers {
+		if reviewer == "" {
+			return fmt.Errorf("safe-outputs.create-pull-request.reviewers[%d] is empty. Each reviewer must be a username or an 'org/team' slug", i)
+		}
+		if strings.HasPrefix(reviewer, "${{") && strings.HasSuffix(reviewer, "}}") {
+			continue
+		}
+		if reviewerUserPattern.MatchString(reviewer) || reviewerTeamPattern.MatchString(reviewer) {
+			continue
+		}
+		return fmt.Errorf("safe-outputs.create-pull-request.reviewers[%d] has invalid value %q. Expected a GitHub username (e.g., 'octocat') or an 'org/team' slug (e.g., 'octo-org/maintainers')", i, reviewer)
+	}
+
+	createPullRequestValidationLog.Printf("All %d reviewers validated successfully", len(reviewers))
+	return nil
+}
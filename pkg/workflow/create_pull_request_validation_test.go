@@ -0,0 +1,88 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCreatePullRequestReviewers(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *SafeOutputsConfig
+		expectError bool
+		errContains string
+	}{
+		{
+			name:   "nil config",
+			config: nil,
+		},
+		{
+			name:   "no create-pull-request",
+			config: &SafeOutputsConfig{},
+		},
+		{
+			name: "valid users, teams, copilot, and expressions",
+			config: &SafeOutputsConfig{
+				CreatePullRequests: &CreatePullRequestsConfig{
+					Reviewers: []string{"octocat", "copilot", "octo-org/maintainers", "my-org/team_a.b", "${{ inputs.reviewer }}"},
+				},
+			},
+		},
+		{
+			name: "empty reviewer",
+			config: &SafeOutputsConfig{
+				CreatePullRequests: &CreatePullRequestsConfig{
+					Reviewers: []string{"octocat", ""},
+				},
+			},
+			expectError: true,
+			errContains: "reviewers[1] is empty",
+		},
+		{
+			name: "username with leading hyphen",
+			config: &SafeOutputsConfig{
+				CreatePullRequests: &CreatePullRequestsConfig{
+					Reviewers: []string{"-octocat"},
+				},
+			},
+			expectError: true,
+			errContains: "reviewers[0] has invalid value",
+		},
+		{
+			name: "team slug with missing team",
+			config: &SafeOutputsConfig{
+				CreatePullRequests: &CreatePullRequestsConfig{
+					Reviewers: []string{"octo-org/"},
+				},
+			},
+			expectError: true,
+			errContains: "'org/team' slug",
+		},
+		{
+			name: "username with at sign",
+			config: &SafeOutputsConfig{
+				CreatePullRequests: &CreatePullRequestsConfig{
+					Reviewers: []string{"@octocat"},
+				},
+			},
+			expectError: true,
+			errContains: "invalid value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCreatePullRequestReviewers(tt.config)
+			if tt.expectError {
+				require.Error(t, err, "Expected validation error")
+				assert.Contains(t, err.Error(), tt.errContains, "Error message should describe the problem")
+			} else {
+				assert.NoError(t, err, "Expected no validation error")
+			}
+		})
+	}
+}
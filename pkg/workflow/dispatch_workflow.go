@@ -9,8 +9,10 @@ var dispatchWorkflowLog = logger.New("workflow:dispatch_workflow")
 // DispatchWorkflowConfig holds configuration for dispatching workflows from agent output
 type DispatchWorkflowConfig struct {
 	BaseSafeOutputConfig `yaml:",inline"`
-	Workflows            []string          `yaml:"workflows,omitempty"`      // List of workflow names (without .md extension) to allow dispatching
-	WorkflowFiles        map[string]string `yaml:"workflow_files,omitempty"` // Map of workflow name to file extension (.lock.yml or .yml) - populated at compile time
+	Workflows            []string                  `yaml:"workflows,omitempty"`       // List of workflow names (without .md extension) to allow dispatching
+	WorkflowFiles        map[string]string         `yaml:"workflow_files,omitempty"`  // Map of workflow name to file extension (.lock.yml or .yml) - populated at compile time
+	Inputs               map[string]map[string]any `yaml:"inputs,omitempty"`          // Declared workflow_dispatch input schemas, keyed by workflow name
+	WorkflowInputs       map[string]map[string]any `yaml:"workflow_inputs,omitempty"` // Input schemas validated at runtime: declared, or derived from the target - populated at compile time
 }
 
 // parseDispatchWorkflowConfig handles dispatch-workflow configuration
@@ -47,6 +49,16 @@ func (c *Compiler) parseDispatchWorkflowConfig(outputMap map[string]any) *Dispat
 				}
 			}
 
+			// Parse declared input schemas: inputs: { <workflow>: { <input>: { type, required, ... } } }
+			if inputs, ok := configMap["inputs"].(map[string]any); ok {
+				dispatchWorkflowConfig.Inputs = make(map[string]map[string]any, len(inputs))
+				for workflowName, workflowInputs := range inputs {
+					if inputsMap, ok := workflowInputs.(map[string]any); ok {
+						dispatchWorkflowConfig.Inputs[workflowName] = inputsMap
+					}
+				}
+			}
+
 			// Parse common base fields with default max of 1
 			c.parseBaseSafeOutputConfig(configMap, &dispatchWorkflowConfig.BaseSafeOutputConfig, 1)
 
@@ -64,3 +76,21 @@ func (c *Compiler) parseDispatchWorkflowConfig(outputMap map[string]any) *Dispat
 
 	return nil
 }
+
+// dispatchInputsRuntimeSchema reduces workflow_dispatch input definitions to the fields the
+// dispatch handler validates against: type, required, options, and default
+func dispatchInputsRuntimeSchema(inputs map[string]any) map[string]any {
+	schema := make(map[string]any, len(inputs))
+	for name, inputDef := range inputs {
+		inputSchema := map[string]any{}
+		if inputDefMap, ok := inputDef.(map[string]any); ok {
+			for _, field := range []string{"type", "required", "options", "default"} {
+				if value, ok := inputDefMap[field]; ok {
+					inputSchema[field] = value
+				}
+			}
+		}
+		schema[name] = inputSchema
+	}
+	return schema
+}
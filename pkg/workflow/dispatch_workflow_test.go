@@ -590,3 +590,22 @@ safe-outputs:
 	assert.Contains(t, errStr, "self-reference", "Should contain first error")
 	assert.NotContains(t, errStr, "Found 2", "Should not have multiple error header in fail-fast mode")
 }
+
+// TestParseDispatchWorkflowConfigInputs tests parsing of declared input schemas
+func TestParseDispatchWorkflowConfigInputs(t *testing.T) {
+	compiler := NewCompilerWithVersion("1.0.0")
+
+	config := compiler.parseDispatchWorkflowConfig(map[string]any{
+		"dispatch-workflow": map[string]any{
+			"workflows": []any{"deploy"},
+			"inputs": map[string]any{
+				"deploy":  map[string]any{"environment": map[string]any{"type": "choice", "options": []any{"staging"}}},
+				"invalid": "not a map",
+			},
+		},
+	})
+	require.NotNil(t, config, "Config should be parsed")
+	assert.Equal(t, map[string]map[string]any{
+		"deploy": {"environment": map[string]any{"type": "choice", "options": []any{"staging"}}},
+	}, config.Inputs, "Only mapping input schemas should be kept")
+}
@@ -3,8 +3,10 @@ package workflow
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/github/gh-aw/pkg/fileutil"
@@ -36,8 +38,18 @@ func (c *Compiler) validateDispatchWorkflow(data *WorkflowData, workflowPath str
 	// Collect all validation errors using ErrorCollector
 	collector := NewErrorCollector(c.failFast)
 
+	// Workflow names and declared input schemas must be well-formed before looking for the files
+	for _, nameErr := range dispatchWorkflowNameErrors(config) {
+		if returnErr := collector.Add(nameErr); returnErr != nil {
+			return returnErr // Fail-fast mode
+		}
+	}
+
 	for _, workflowName := range config.Workflows {
 		dispatchWorkflowValidationLog.Printf("Validating workflow: %s", workflowName)
+		if !isDispatchWorkflowName(workflowName) {
+			continue // Already reported by dispatchWorkflowNameErrors
+		}
 
 		// Check for self-reference
 		if workflowName == currentWorkflowName {
@@ -157,6 +169,33 @@ func (c *Compiler) validateDispatchWorkflow(data *WorkflowData, workflowPath str
 			continue // Skip further validation for this workflow
 		}
 
+		// The agent's dispatch tool is generated from the target's inputs, so inputs it cannot
+		// represent would make the dispatch fail at runtime
+		inputs := workflowDispatchInputs(workflow)
+		var problems []string
+		if declared, ok := config.Inputs[workflowName]; ok {
+			// Declared schemas replace the target's inputs but cannot introduce new ones,
+			// since GitHub rejects dispatches with undefined inputs
+			for _, name := range slices.Sorted(maps.Keys(declared)) {
+				if _, defined := inputs[name]; !defined {
+					problems = append(problems, fmt.Sprintf("declared input '%s' is not defined in the workflow's workflow_dispatch inputs", name))
+				}
+			}
+			inputs = declared
+		}
+		problems = append(problems, findDispatchInputProblems(inputs)...)
+		if len(problems) > 0 {
+			message := fmt.Sprintf("dispatch-workflow: workflow '%s' has workflow_dispatch inputs the agent cannot pass:\n  - %s", workflowName, strings.Join(problems, "\n  - "))
+			if c.strictMode {
+				if returnErr := collector.Add(errors.New(message)); returnErr != nil {
+					return returnErr // Fail-fast mode
+				}
+				continue // Skip further validation for this workflow
+			}
+			fmt.Fprintln(os.Stderr, formatCompilerMessage(workflowPath, "warning", message))
+			c.IncrementWarningCount()
+		}
+
 		dispatchWorkflowValidationLog.Printf("Workflow '%s' is valid for dispatch (found in %s)", workflowName, workflowFile)
 	}
 
@@ -166,6 +205,31 @@ func (c *Compiler) validateDispatchWorkflow(data *WorkflowData, workflowPath str
 	return collector.FormattedError("dispatch-workflow")
 }
 
+// isDispatchWorkflowName reports whether name can refer to a workflow in .github/workflows:
+// a bare file name without extension or directory
+func isDispatchWorkflowName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, `/\`) && name != "." && name != ".." &&
+		!strings.HasSuffix(name, ".md") && !strings.HasSuffix(name, ".yml") && !strings.HasSuffix(name, ".yaml")
+}
+
+// dispatchWorkflowNameErrors checks a dispatch-workflow configuration without looking at the
+// target workflow files: every workflow must be named by its file name without extension,
+// and declared input schemas must belong to a listed workflow
+func dispatchWorkflowNameErrors(config *DispatchWorkflowConfig) []error {
+	var errs []error
+	for _, workflowName := range config.Workflows {
+		if !isDispatchWorkflowName(workflowName) {
+			errs = append(errs, fmt.Errorf("dispatch-workflow: invalid workflow name '%s' (use the workflow file name without directory or extension, e.g. 'ci-doctor')", workflowName))
+		}
+	}
+	for _, workflowName := range slices.Sorted(maps.Keys(config.Inputs)) {
+		if !slices.Contains(config.Workflows, workflowName) {
+			errs = append(errs, fmt.Errorf("dispatch-workflow: inputs declared for workflow '%s', which is not in the workflows list", workflowName))
+		}
+	}
+	return errs
+}
+
 // extractWorkflowDispatchInputs parses a workflow file and extracts the workflow_dispatch inputs schema
 // Returns a map of input definitions that can be used to generate MCP tool schemas
 func extractWorkflowDispatchInputs(workflowPath string) (map[string]any, error) {
@@ -185,38 +249,71 @@ func extractWorkflowDispatchInputs(workflowPath string) (map[string]any, error)
 		return nil, fmt.Errorf("failed to parse workflow file %s: %w", workflowPath, err)
 	}
 
-	// Navigate to workflow_dispatch.inputs
-	onSection, hasOn := workflow["on"]
-	if !hasOn {
-		return make(map[string]any), nil // No inputs
-	}
+	return workflowDispatchInputs(workflow), nil
+}
 
-	onMap, ok := onSection.(map[string]any)
+// workflowDispatchInputs returns the on.workflow_dispatch.inputs map of a parsed workflow,
+// or an empty map when the workflow declares no inputs
+func workflowDispatchInputs(workflow map[string]any) map[string]any {
+	// Navigate to workflow_dispatch.inputs
+	onMap, ok := workflow["on"].(map[string]any)
 	if !ok {
-		return make(map[string]any), nil // No inputs
+		return make(map[string]any) // No inputs
 	}
 
-	workflowDispatch, hasWorkflowDispatch := onMap["workflow_dispatch"]
-	if !hasWorkflowDispatch {
-		return make(map[string]any), nil // No inputs
+	workflowDispatchMap, ok := onMap["workflow_dispatch"].(map[string]any)
+	if !ok {
+		return make(map[string]any) // No inputs
 	}
 
-	workflowDispatchMap, ok := workflowDispatch.(map[string]any)
+	inputsMap, ok := workflowDispatchMap["inputs"].(map[string]any)
 	if !ok {
-		return make(map[string]any), nil // No inputs
+		return make(map[string]any) // No inputs
 	}
 
-	inputs, hasInputs := workflowDispatchMap["inputs"]
-	if !hasInputs {
-		return make(map[string]any), nil // No inputs
-	}
+	return inputsMap
+}
 
-	inputsMap, ok := inputs.(map[string]any)
-	if !ok {
-		return make(map[string]any), nil // No inputs
-	}
+// dispatchInputTypes lists the workflow_dispatch input types supported by GitHub Actions
+var dispatchInputTypes = []string{"string", "number", "boolean", "choice", "environment"}
+
+// findDispatchInputProblems checks that the workflow_dispatch inputs of a dispatch target can be
+// turned into the agent's dispatch tool schema: every input must be a mapping with a supported
+// type, and choice inputs must list their options (including the default, if any).
+// Problems are returned sorted by input name.
+func findDispatchInputProblems(inputs map[string]any) []string {
+	var problems []string
+	for _, name := range slices.Sorted(maps.Keys(inputs)) {
+		inputDef, ok := inputs[name].(map[string]any)
+		if !ok {
+			if inputs[name] != nil {
+				problems = append(problems, fmt.Sprintf("input '%s' must be a mapping", name))
+			}
+			continue
+		}
+
+		inputType, hasType := inputDef["type"].(string)
+		if !hasType {
+			continue // Defaults to string
+		}
+		if !slices.Contains(dispatchInputTypes, inputType) {
+			problems = append(problems, fmt.Sprintf("input '%s' has unsupported type '%s' (expected one of: %s)", name, inputType, strings.Join(dispatchInputTypes, ", ")))
+			continue
+		}
+		if inputType != "choice" {
+			continue
+		}
 
-	return inputsMap, nil
+		options, _ := inputDef["options"].([]any)
+		if len(options) == 0 {
+			problems = append(problems, fmt.Sprintf("choice input '%s' must list its options", name))
+			continue
+		}
+		if defaultVal, hasDefault := inputDef["default"]; hasDefault && !slices.Contains(options, defaultVal) {
+			problems = append(problems, fmt.Sprintf("choice input '%s' has default '%v' that is not one of its options", name, defaultVal))
+		}
+	}
+	return problems
 }
 
 // getCurrentWorkflowName extracts the workflow name from the file path
@@ -317,3 +317,106 @@ safe-outputs:
 	assert.Contains(t, errMsg, "To fix:", "Should include fix instructions")
 	assert.Contains(t, errMsg, "Checked for:", "Should include checked extensions")
 }
+
+func TestFindDispatchInputProblems(t *testing.T) {
+	inputs := map[string]any{
+		"message": map[string]any{"type": "string"},
+		"count":   map[string]any{"type": "number"},
+		"plain":   map[string]any{"description": "defaults to string"},
+		"empty":   nil,
+		"size":    map[string]any{"type": "choice", "options": []any{"small", "large"}, "default": "small"},
+		"target":  map[string]any{"type": "choice"},
+		"mode":    map[string]any{"type": "choice", "options": []any{"fast", "slow"}, "default": "medium"},
+		"files":   map[string]any{"type": "array"},
+		"flat":    "string",
+	}
+
+	assert.Equal(t, []string{
+		"input 'files' has unsupported type 'array' (expected one of: string, number, boolean, choice, environment)",
+		"input 'flat' must be a mapping",
+		"choice input 'mode' has default 'medium' that is not one of its options",
+		"choice input 'target' must list its options",
+	}, findDispatchInputProblems(inputs), "problems should be reported sorted by input name")
+
+	assert.Empty(t, findDispatchInputProblems(map[string]any{}), "no inputs should have no problems")
+}
+
+// TestDispatchWorkflowInvalidInputs tests that dispatch targets with inputs the agent cannot
+// pass produce a warning normally and an error in strict mode
+func TestDispatchWorkflowInvalidInputs(t *testing.T) {
+	tmpDir := t.TempDir()
+	awDir := filepath.Join(tmpDir, ".github", "aw")
+	workflowsDir := filepath.Join(tmpDir, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(awDir, 0755), "Failed to create aw directory")
+	require.NoError(t, os.MkdirAll(workflowsDir, 0755), "Failed to create workflows directory")
+
+	deployWorkflow := `name: Deploy
+on:
+  workflow_dispatch:
+    inputs:
+      environment:
+        type: choice
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo "Deploying"
+`
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "deploy.lock.yml"), []byte(deployWorkflow), 0644), "Failed to write deploy workflow")
+
+	dispatcherFile := filepath.Join(awDir, "dispatcher.md")
+	workflowData := &WorkflowData{
+		SafeOutputs: &SafeOutputsConfig{
+			DispatchWorkflow: &DispatchWorkflowConfig{Workflows: []string{"deploy"}},
+		},
+	}
+
+	compiler := NewCompilerWithVersion("1.0.0")
+	require.NoError(t, compiler.validateDispatchWorkflow(workflowData, dispatcherFile), "Invalid inputs should only warn outside strict mode")
+	assert.Equal(t, 1, compiler.GetWarningCount(), "Invalid inputs should be counted as a warning")
+
+	compiler = NewCompilerWithVersion("1.0.0")
+	compiler.SetStrictMode(true)
+	err := compiler.validateDispatchWorkflow(workflowData, dispatcherFile)
+	require.Error(t, err, "Invalid inputs should fail in strict mode")
+	assert.Contains(t, err.Error(), "choice input 'environment' must list its options", "Error should describe the input problem")
+}
+
+// TestDispatchWorkflowDeclaredInputs tests validation of declared input schemas
+func TestDispatchWorkflowDeclaredInputs(t *testing.T) {
+	tmpDir := t.TempDir()
+	awDir := filepath.Join(tmpDir, ".github", "aw")
+	workflowsDir := filepath.Join(tmpDir, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(awDir, 0755), "Failed to create aw directory")
+	require.NoError(t, os.MkdirAll(workflowsDir, 0755), "Failed to create workflows directory")
+
+	deployWorkflow := `on:
+  workflow_dispatch:
+    inputs:
+      environment:
+        type: string
+`
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "deploy.lock.yml"), []byte(deployWorkflow), 0644), "Failed to write deploy workflow")
+	dispatcherFile := filepath.Join(awDir, "dispatcher.md")
+
+	compiler := NewCompilerWithVersion("1.0.0")
+	compiler.SetStrictMode(true)
+
+	valid := &WorkflowData{SafeOutputs: &SafeOutputsConfig{DispatchWorkflow: &DispatchWorkflowConfig{
+		Workflows: []string{"deploy"},
+		Inputs:    map[string]map[string]any{"deploy": {"environment": map[string]any{"type": "choice", "options": []any{"staging"}}}},
+	}}}
+	require.NoError(t, compiler.validateDispatchWorkflow(valid, dispatcherFile), "Declared schema narrowing a target input should be valid")
+
+	invalid := &WorkflowData{SafeOutputs: &SafeOutputsConfig{DispatchWorkflow: &DispatchWorkflowConfig{
+		Workflows: []string{"deploy"},
+		Inputs: map[string]map[string]any{
+			"deploy":  {"region": map[string]any{"type": "string"}},
+			"cleanup": {"force": map[string]any{"type": "boolean"}},
+		},
+	}}}
+	err := compiler.validateDispatchWorkflow(invalid, dispatcherFile)
+	require.Error(t, err, "Invalid declared inputs should fail in strict mode")
+	assert.Contains(t, err.Error(), "inputs declared for workflow 'cleanup', which is not in the workflows list", "Error should reject inputs for unknown workflows")
+	assert.Contains(t, err.Error(), "declared input 'region' is not defined", "Error should reject inputs the target does not define")
+}
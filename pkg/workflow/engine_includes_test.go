@@ -630,3 +630,52 @@ func TestExtractEngineConfigFromJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestConditionalIncludesByEngine(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "test-*")
+	workflowsDir := filepath.Join(tmpDir, constants.GetWorkflowDir())
+	if err := os.MkdirAll(workflowsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	includes := map[string]string{
+		"copilot-tips.md": "Use Copilot-specific tips.\n",
+		"claude-tips.md":  "Use Claude-specific tips.\n",
+	}
+	for name, content := range includes {
+		if err := os.WriteFile(filepath.Join(workflowsDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, engine := range []string{"copilot", "claude"} {
+		t.Run(engine, func(t *testing.T) {
+			mainContent := `---
+on: push
+engine: ` + engine + `
+---
+
+# Conditional Includes
+
+@include[engine=copilot] copilot-tips.md
+@include[engine=claude] claude-tips.md
+`
+			mainFile := filepath.Join(workflowsDir, "conditional-"+engine+".md")
+			if err := os.WriteFile(mainFile, []byte(mainContent), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			compiler := NewCompiler()
+			workflowData, err := compiler.ParseWorkflowFile(mainFile)
+			if err != nil {
+				t.Fatalf("Expected successful parsing, got error: %v", err)
+			}
+
+			hasCopilot := strings.Contains(workflowData.MarkdownContent, "Copilot-specific")
+			hasClaude := strings.Contains(workflowData.MarkdownContent, "Claude-specific")
+			if hasCopilot != (engine == "copilot") || hasClaude != (engine == "claude") {
+				t.Errorf("Engine %s: unexpected conditional include expansion, got markdown:\n%s", engine, workflowData.MarkdownContent)
+			}
+		})
+	}
+}
@@ -10,6 +10,8 @@
 //
 //   - validateEngine() - Validates that a given engine ID is supported
 //   - validateSingleEngineSpecification() - Validates that only one engine field exists across all files
+//   - validateToolsHaveEngine() - Warns when tools are configured without any engine
+//   - validateEngineVersion() - Validates that a pinned engine.version is a supported release
 //
 // # Validation Pattern: Engine Registry
 //
@@ -36,11 +38,15 @@ package workflow
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"regexp"
 	"strings"
 
+	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/constants"
 	"github.com/github/gh-aw/pkg/logger"
 	"github.com/github/gh-aw/pkg/parser"
+	"golang.org/x/mod/semver"
 )
 
 var engineValidationLog = logger.New("workflow:engine_validation")
@@ -147,6 +153,84 @@ func (c *Compiler) validateSingleEngineSpecification(mainEngineSetting string, i
 	return "", fmt.Errorf("invalid engine configuration in included file, missing or invalid 'id' field. Expected string or object with 'id' field.\n\nExample (string):\nengine: copilot\n\nExample (object):\nengine:\n  id: copilot\n  model: gpt-4\n\nSee: %s", constants.DocsEnginesURL)
 }
 
+// validateToolsHaveEngine warns when the workflow configures tools but no engine is set in the
+// workflow, its imports, or on the command line, so the tools silently go to the default engine.
+// Workflows that explicitly request strict mode (--strict or strict: true) fail instead; the
+// schema default for strict does not count, so existing workflows keep compiling.
+func (c *Compiler) validateToolsHaveEngine(frontmatter map[string]any, defaultEngineID string) error {
+	tools, _ := frontmatter["tools"].(map[string]any)
+	enabledTools := 0
+	for _, toolConfig := range tools {
+		// "github: false" turns a default tool off rather than configuring one
+		if enabled, isBool := toolConfig.(bool); !isBool || enabled {
+			enabledTools++
+		}
+	}
+	if enabledTools == 0 {
+		return nil
+	}
+
+	engineValidationLog.Printf("Workflow configures %d tools without an engine, default is %s", enabledTools, defaultEngineID)
+	message := fmt.Sprintf("workflow configures tools but no engine, so it will run with the default engine (%s). Set the engine explicitly, e.g. 'engine: %s'. See: %s", defaultEngineID, defaultEngineID, constants.DocsEnginesURL)
+
+	explicitStrict, _ := frontmatter["strict"].(bool)
+	if c.strictMode || explicitStrict {
+		return fmt.Errorf("strict mode: %s", message)
+	}
+
+	fmt.Fprintln(os.Stderr, console.FormatWarningMessage("Warning: "+message))
+	c.IncrementWarningCount()
+	return nil
+}
+
+// engineVersionRange is the range of CLI releases a workflow can pin an engine to: from minimum
+// up to any later release with the same major version as the default
+type engineVersionRange struct {
+	minimum constants.Version
+	latest  constants.Version
+}
+
+// supportedEngineVersions lists the pinnable CLI versions of each engine that installs one
+var supportedEngineVersions = map[string]engineVersionRange{
+	"claude":  {minimum: constants.MinimumClaudeCodeVersion, latest: constants.DefaultClaudeCodeVersion},
+	"copilot": {minimum: constants.MinimumCopilotVersion, latest: constants.DefaultCopilotVersion},
+	"codex":   {minimum: constants.MinimumCodexVersion, latest: constants.DefaultCodexVersion},
+	"gemini":  {minimum: constants.MinimumGeminiVersion, latest: constants.DefaultGeminiVersion},
+}
+
+// distTagPattern matches npm dist-tags such as "latest" or "beta", which select a release
+// channel rather than pinning a version
+var distTagPattern = regexp.MustCompile(`^[a-z][a-z-]*$`)
+
+// validateEngineVersion validates that engine.version pins a known release of the engine's CLI.
+// Dist-tags are passed through unchecked, as is the version of engines with a custom command,
+// since no CLI is installed for them.
+func (c *Compiler) validateEngineVersion(engineConfig *EngineConfig, engineID string) error {
+	if engineConfig == nil || engineConfig.Version == "" || engineConfig.Command != "" {
+		return nil
+	}
+	supported, ok := supportedEngineVersions[engineID]
+	if !ok {
+		return nil
+	}
+
+	version := engineConfig.Version
+	if distTagPattern.MatchString(version) {
+		engineValidationLog.Printf("Engine %s uses dist-tag %q, skipping version range check", engineID, version)
+		return nil
+	}
+
+	engineValidationLog.Printf("Validating %s engine version %s against %s..%s", engineID, version, supported.minimum, supported.latest)
+	canonical := "v" + strings.TrimPrefix(version, "v")
+	if !semver.IsValid(canonical) {
+		return fmt.Errorf("engine.version %q is not a valid version for the %s engine. Use a release version such as '%s' or a dist-tag such as 'latest'. See: %s", version, engineID, supported.latest, constants.DocsEnginesURL)
+	}
+	if compareVersions(canonical, string(supported.minimum)) < 0 || !isSemverCompatible(canonical, string(supported.latest)) {
+		return fmt.Errorf("engine.version %q is not supported by the %s engine: supported versions are %s up to the latest %s.x release (default %s). See: %s", version, engineID, supported.minimum, semver.Major("v"+string(supported.latest)), supported.latest, constants.DocsEnginesURL)
+	}
+	return nil
+}
+
 // validatePluginSupport validates that plugins are only used with engines that support them
 func (c *Compiler) validatePluginSupport(pluginInfo *PluginInfo, agenticEngine CodingAgentEngine) error {
 	// No plugins specified, validation passes
@@ -5,6 +5,8 @@ package workflow
 import (
 	"strings"
 	"testing"
+
+	"github.com/github/gh-aw/pkg/constants"
 )
 
 // TestValidateEngine tests the validateEngine function
@@ -495,3 +497,118 @@ func TestValidatePluginSupportErrorMessage(t *testing.T) {
 		t.Errorf("Error message should provide actionable fixes, got: %s", errorMsg)
 	}
 }
+
+// TestValidateToolsHaveEngine tests that tools without an engine warn, and fail in explicit strict mode
+func TestValidateToolsHaveEngine(t *testing.T) {
+	tests := []struct {
+		name          string
+		frontmatter   map[string]any
+		cliStrict     bool
+		expectError   bool
+		expectWarning bool
+	}{
+		{
+			name:        "no tools",
+			frontmatter: map[string]any{"on": "push"},
+		},
+		{
+			name:        "empty tools",
+			frontmatter: map[string]any{"tools": map[string]any{}},
+		},
+		{
+			name:        "only disabled tools",
+			frontmatter: map[string]any{"tools": map[string]any{"github": false}, "strict": true},
+		},
+		{
+			name:          "tools without strict",
+			frontmatter:   map[string]any{"tools": map[string]any{"github": nil}},
+			expectWarning: true,
+		},
+		{
+			name:          "tools with strict disabled",
+			frontmatter:   map[string]any{"tools": map[string]any{"github": nil}, "strict": false},
+			expectWarning: true,
+		},
+		{
+			name:        "tools with strict in frontmatter",
+			frontmatter: map[string]any{"tools": map[string]any{"github": nil}, "strict": true},
+			expectError: true,
+		},
+		{
+			name:        "tools with strict on the command line",
+			frontmatter: map[string]any{"tools": map[string]any{"github": nil}},
+			cliStrict:   true,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compiler := NewCompiler()
+			compiler.SetStrictMode(tt.cliStrict)
+
+			err := compiler.validateToolsHaveEngine(tt.frontmatter, "copilot")
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("Expected an error in strict mode, got nil")
+				}
+				if !strings.Contains(err.Error(), "engine: copilot") {
+					t.Errorf("Expected error to suggest setting the engine, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			wantWarnings := 0
+			if tt.expectWarning {
+				wantWarnings = 1
+			}
+			if got := compiler.GetWarningCount(); got != wantWarnings {
+				t.Errorf("Expected %d warnings, got %d", wantWarnings, got)
+			}
+		})
+	}
+}
+
+// TestValidateEngineVersion tests that pinned engine versions are checked against the supported range
+func TestValidateEngineVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *EngineConfig
+		engineID string
+		errorMsg string
+	}{
+		{name: "no engine config", config: nil, engineID: "claude"},
+		{name: "no version", config: &EngineConfig{ID: "claude"}, engineID: "claude"},
+		{name: "default version", config: &EngineConfig{Version: string(constants.DefaultClaudeCodeVersion)}, engineID: "claude"},
+		{name: "minimum version", config: &EngineConfig{Version: string(constants.MinimumCopilotVersion)}, engineID: "copilot"},
+		{name: "newer release in the same major", config: &EngineConfig{Version: "2.9.0"}, engineID: "claude"},
+		{name: "v prefix", config: &EngineConfig{Version: "v2.1.0"}, engineID: "claude"},
+		{name: "dist-tag", config: &EngineConfig{Version: "latest"}, engineID: "codex"},
+		{name: "custom command", config: &EngineConfig{Version: "0.0.1", Command: "/usr/local/bin/claude"}, engineID: "claude"},
+		{name: "engine without known versions", config: &EngineConfig{Version: "0.0.1"}, engineID: "custom"},
+		{name: "older than minimum", config: &EngineConfig{Version: "1.0.0"}, engineID: "claude", errorMsg: `engine.version "1.0.0" is not supported by the claude engine`},
+		{name: "next major", config: &EngineConfig{Version: "3.0.0"}, engineID: "claude", errorMsg: "up to the latest v2.x release"},
+		{name: "malformed version", config: &EngineConfig{Version: "2.1.x"}, engineID: "claude", errorMsg: "is not a valid version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewCompiler().validateEngineVersion(tt.config, tt.engineID)
+			if tt.errorMsg == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Expected error containing %q, got nil", tt.errorMsg)
+			}
+			if !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("Expected error containing %q, got: %v", tt.errorMsg, err)
+			}
+		})
+	}
+}
@@ -4,10 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"maps"
+	"os"
+	"reflect"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
 
+	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/logger"
 	"github.com/github/gh-aw/pkg/parser"
 )
@@ -327,8 +331,14 @@ func getSafeOutputTypeKeys() ([]string, error) {
 	return safeOutputTypeKeys, safeOutputTypeKeysErr
 }
 
-// MergeSafeOutputs merges safe-outputs configurations from imports into the top-level safe-outputs
-// Returns an error if a conflict is detected (same safe-output type defined in both main and imported)
+// MergeSafeOutputs merges safe-outputs configurations from imports into the top-level safe-outputs.
+//
+// A safe-output type defined in more than one import is merged field by field, with earlier
+// imports taking precedence over later ones. Lists (such as labels or allowed) are combined
+// without duplicates and nested objects are merged recursively. Scalar values that differ keep
+// the value with precedence and are reported as warnings. Types the main workflow also defines
+// are skipped here, since mergeImportedSafeOutputTypes merged them into the main definition
+// before it was parsed.
 func (c *Compiler) MergeSafeOutputs(topSafeOutputs *SafeOutputsConfig, importedSafeOutputsJSON []string) (*SafeOutputsConfig, error) {
 	importsLog.Print("Merging safe-outputs from imports")
 
@@ -354,8 +364,8 @@ func (c *Compiler) MergeSafeOutputs(topSafeOutputs *SafeOutputsConfig, importedS
 	}
 	importsLog.Printf("Top-level safe-outputs defines %d types", len(topDefinedTypes))
 
-	// Track types defined in imported configs for conflict detection
-	importedDefinedTypes := make(map[string]bool)
+	// Track the imported config that first defines each type; later definitions are merged into it
+	importedDefinedTypes := make(map[string]map[string]any)
 
 	// Collect all imported configs. This includes configs with only meta fields (like allowed-domains,
 	// staged, env, github-token, max-patch-size, runs-on) as well as those defining safe output types.
@@ -372,21 +382,25 @@ func (c *Compiler) MergeSafeOutputs(topSafeOutputs *SafeOutputsConfig, importedS
 			continue
 		}
 
-		// Check for conflicts and remove types already defined in top-level config
-		// Main workflow definitions take precedence over imports (override behavior)
+		// Types the main workflow defines were merged into its definition before parsing (see
+		// mergeImportedSafeOutputTypes); merge other types already defined by an earlier import
+		// into that import's definition
 		for _, key := range typeKeys {
-			if _, exists := config[key]; exists {
-				if topDefinedTypes[key] {
-					// Main workflow overrides imported definition - remove from imported config
-					importsLog.Printf("Main workflow overrides imported safe-output: %s", key)
-					delete(config, key)
-					continue
-				}
-				if importedDefinedTypes[key] {
-					return nil, fmt.Errorf("safe-outputs conflict: '%s' is defined in multiple imported workflows. Each safe-output type can only be defined once", key)
-				}
-				importedDefinedTypes[key] = true
+			if _, exists := config[key]; !exists {
+				continue
+			}
+			if topDefinedTypes[key] {
+				importsLog.Printf("Main workflow overrides imported safe-output: %s", key)
+				delete(config, key)
+				continue
+			}
+			if first, defined := importedDefinedTypes[key]; defined {
+				importsLog.Printf("Merging safe-output %s defined in multiple imports", key)
+				first[key] = c.mergeSafeOutputTypeConfigs(key, first[key], config[key])
+				delete(config, key)
+				continue
 			}
+			importedDefinedTypes[key] = config
 		}
 
 		importedConfigs = append(importedConfigs, config)
@@ -418,6 +432,131 @@ func (c *Compiler) MergeSafeOutputs(topSafeOutputs *SafeOutputsConfig, importedS
 	return result, nil
 }
 
+// mergeImportedSafeOutputTypes returns the frontmatter with imported definitions of safe-output
+// types that the main workflow also defines merged into the main definitions, so the merged
+// safe-outputs can be parsed once. The main workflow takes precedence over imports, and earlier
+// imports over later ones. The frontmatter is returned unchanged when nothing is merged.
+func (c *Compiler) mergeImportedSafeOutputTypes(frontmatter map[string]any, importedSafeOutputsJSON []string) map[string]any {
+	mainOutputs, ok := frontmatter["safe-outputs"].(map[string]any)
+	if !ok || len(importedSafeOutputsJSON) == 0 {
+		return frontmatter
+	}
+
+	typeKeys, err := getSafeOutputTypeKeys()
+	if err != nil {
+		importsLog.Printf("Failed to get safe output type keys: %v", err)
+		return frontmatter
+	}
+
+	var merged map[string]any
+	for _, configJSON := range importedSafeOutputsJSON {
+		if configJSON == "" || configJSON == "{}" {
+			continue
+		}
+
+		var config map[string]any
+		if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
+			importsLog.Printf("Skipping malformed safe-outputs config: %v", err)
+			continue
+		}
+
+		for _, key := range typeKeys {
+			importedConfig, imported := config[key]
+			if _, defined := mainOutputs[key]; !defined || !imported {
+				continue
+			}
+			if merged == nil {
+				merged = maps.Clone(mainOutputs)
+			}
+			importsLog.Printf("Merging imported safe-output %s into main workflow definition", key)
+			merged[key] = c.mergeSafeOutputTypeConfigs(key, merged[key], importedConfig)
+		}
+	}
+
+	if merged == nil {
+		return frontmatter
+	}
+	result := maps.Clone(frontmatter)
+	result["safe-outputs"] = merged
+	return result
+}
+
+// mergeSafeOutputTypeConfigs merges two definitions of the safe-output type key, where preferred
+// takes precedence over other. Conflicting scalar values are reported as warnings. Definitions that
+// are not objects cannot be merged, so preferred is kept as-is.
+func (c *Compiler) mergeSafeOutputTypeConfigs(key string, preferred, other any) any {
+	preferredMap, preferredOK := safeOutputTypeConfigMap(preferred)
+	otherMap, otherOK := safeOutputTypeConfigMap(other)
+	if !preferredOK || !otherOK {
+		importsLog.Printf("Cannot merge non-object definitions of safe-output %s, keeping the one with precedence", key)
+		return preferred
+	}
+
+	merged, conflicts := mergeSafeOutputConfigMaps("safe-outputs."+key, preferredMap, otherMap)
+	for _, conflict := range conflicts {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(conflict))
+		c.IncrementWarningCount()
+	}
+	return merged
+}
+
+// safeOutputTypeConfigMap returns the object form of a safe-output type definition, treating a
+// null definition (type enabled with defaults) as an empty object
+func safeOutputTypeConfigMap(config any) (map[string]any, bool) {
+	if config == nil {
+		return map[string]any{}, true
+	}
+	configMap, ok := config.(map[string]any)
+	return configMap, ok
+}
+
+// mergeSafeOutputConfigMaps merges other into a copy of preferred. Lists are combined without
+// duplicates, nested objects are merged recursively, and values only set in other are added.
+// Scalar values set in both keep the preferred value; a message describing each such conflict is
+// returned.
+func mergeSafeOutputConfigMaps(path string, preferred, other map[string]any) (map[string]any, []string) {
+	merged := maps.Clone(preferred)
+	var conflicts []string
+
+	keys := slices.Sorted(maps.Keys(other))
+	for _, key := range keys {
+		otherValue := other[key]
+		preferredValue, exists := merged[key]
+		if !exists {
+			merged[key] = otherValue
+			continue
+		}
+
+		fieldPath := path + "." + key
+		switch preferredTyped := preferredValue.(type) {
+		case []any:
+			if otherList, ok := otherValue.([]any); ok {
+				combined := slices.Clone(preferredTyped)
+				for _, item := range otherList {
+					if !slices.ContainsFunc(combined, func(existing any) bool { return reflect.DeepEqual(existing, item) }) {
+						combined = append(combined, item)
+					}
+				}
+				merged[key] = combined
+				continue
+			}
+		case map[string]any:
+			if otherMap, ok := otherValue.(map[string]any); ok {
+				nested, nestedConflicts := mergeSafeOutputConfigMaps(fieldPath, preferredTyped, otherMap)
+				merged[key] = nested
+				conflicts = append(conflicts, nestedConflicts...)
+				continue
+			}
+		}
+
+		if !reflect.DeepEqual(preferredValue, otherValue) {
+			conflicts = append(conflicts, fmt.Sprintf("Conflicting values for %s in imported safe-outputs: using %#v, ignoring %#v", fieldPath, preferredValue, otherValue))
+		}
+	}
+
+	return merged, conflicts
+}
+
 // hasSafeOutputType checks if a SafeOutputsConfig has a specific safe output type defined
 func hasSafeOutputType(config *SafeOutputsConfig, key string) bool {
 	if config == nil {
@@ -493,6 +632,10 @@ func hasSafeOutputType(config *SafeOutputsConfig, key string) bool {
 		return config.LinkSubIssue != nil
 	case "hide-comment":
 		return config.HideComment != nil
+	case "add-reaction":
+		return config.AddReaction != nil
+	case "push-files":
+		return config.PushFiles != nil
 	case "dispatch-workflow":
 		return config.DispatchWorkflow != nil
 	case "missing-data":
@@ -618,6 +761,12 @@ func mergeSafeOutputConfig(result *SafeOutputsConfig, config map[string]any, c *
 	if result.HideComment == nil && importedConfig.HideComment != nil {
 		result.HideComment = importedConfig.HideComment
 	}
+	if result.AddReaction == nil && importedConfig.AddReaction != nil {
+		result.AddReaction = importedConfig.AddReaction
+	}
+	if result.PushFiles == nil && importedConfig.PushFiles != nil {
+		result.PushFiles = importedConfig.PushFiles
+	}
 	if result.DispatchWorkflow == nil && importedConfig.DispatchWorkflow != nil {
 		result.DispatchWorkflow = importedConfig.DispatchWorkflow
 	}
@@ -21,8 +21,10 @@ type InputDefinition struct {
 	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
 	Required    bool     `yaml:"required,omitempty" json:"required,omitempty"`
 	Default     any      `yaml:"default,omitempty" json:"default,omitempty"` // Can be string, number, or boolean
-	Type        string   `yaml:"type,omitempty" json:"type,omitempty"`       // "string", "choice", "boolean", "number", "environment"
+	Type        string   `yaml:"type,omitempty" json:"type,omitempty"`       // "string", "choice", "boolean", "number", "integer", "environment"
 	Options     []string `yaml:"options,omitempty" json:"options,omitempty"` // Options for choice type
+	Minimum     *float64 `yaml:"minimum,omitempty" json:"minimum,omitempty"` // Lower bound for number and integer types (safe-jobs only)
+	Maximum     *float64 `yaml:"maximum,omitempty" json:"maximum,omitempty"` // Upper bound for number and integer types (safe-jobs only)
 }
 
 // ParseInputDefinition parses an input definition from a map.
@@ -70,6 +72,14 @@ func ParseInputDefinition(inputConfig map[string]any) *InputDefinition {
 		}
 	}
 
+	// Parse numeric bounds (for number and integer types)
+	if minimum, ok := parseFloatValue(inputConfig["minimum"]); ok {
+		input.Minimum = &minimum
+	}
+	if maximum, ok := parseFloatValue(inputConfig["maximum"]); ok {
+		input.Maximum = &maximum
+	}
+
 	inputsLog.Printf("Parsed input definition: type=%s, required=%t, options=%d", input.Type, input.Required, len(input.Options))
 	return input
 }
@@ -3,6 +3,7 @@
 package workflow
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -66,6 +67,21 @@ func TestParseInputDefinition(t *testing.T) {
 				Default:     100,
 			},
 		},
+		{
+			name: "integer type with bounds",
+			config: map[string]any{
+				"type":    "integer",
+				"default": 5,
+				"minimum": 1,
+				"maximum": 10.0,
+			},
+			expected: &InputDefinition{
+				Type:    "integer",
+				Default: 5,
+				Minimum: float64Ptr(1.0),
+				Maximum: float64Ptr(10.0),
+			},
+		},
 		{
 			name:     "empty config",
 			config:   map[string]any{},
@@ -116,6 +132,14 @@ func TestParseInputDefinition(t *testing.T) {
 				t.Errorf("Default: got %v (%T), want %v (%T)", result.Default, result.Default, tt.expected.Default, tt.expected.Default)
 			}
 
+			// Check numeric bounds
+			if !reflect.DeepEqual(result.Minimum, tt.expected.Minimum) {
+				t.Errorf("Minimum: got %v, want %v", result.Minimum, tt.expected.Minimum)
+			}
+			if !reflect.DeepEqual(result.Maximum, tt.expected.Maximum) {
+				t.Errorf("Maximum: got %v, want %v", result.Maximum, tt.expected.Maximum)
+			}
+
 			// Check options
 			if len(result.Options) != len(tt.expected.Options) {
 				t.Errorf("Options length: got %d, want %d", len(result.Options), len(tt.expected.Options))
@@ -30,6 +30,7 @@ import (
 	"unicode/utf8"
 
 	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
 )
 
 var markdownSecurityLog = logger.New("workflow:markdown_security_scanner")
@@ -122,26 +123,24 @@ func ScanMarkdownSecurity(content string) []SecurityFinding {
 	return findings
 }
 
-// stripFrontmatter removes YAML frontmatter (--- delimited) from content.
+// stripFrontmatter removes frontmatter (YAML, TOML, or JSON fenced) from content.
 // Returns the markdown body and the number of lines consumed by frontmatter
-// (including the closing --- delimiter) so callers can adjust line numbers.
+// (including the closing fence) so callers can adjust line numbers.
 func stripFrontmatter(content string) (string, int) {
 	lines := strings.Split(content, "\n")
-	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+	format, endIndex := parser.FrontmatterBounds(lines)
+	if format == "" {
 		return content, 0
 	}
 
-	// Find closing ---
-	for i := 1; i < len(lines); i++ {
-		if strings.TrimSpace(lines[i]) == "---" {
-			// Return everything after the closing ---
-			remaining := strings.Join(lines[i+1:], "\n")
-			return remaining, i + 1 // i+1 lines consumed (0-indexed i, plus the closing ---)
-		}
+	if endIndex == -1 {
+		// No closing fence found; treat as frontmatter-only with no markdown body to scan
+		return "", 0
 	}
 
-	// No closing --- found; treat as frontmatter-only with no markdown body to scan
-	return "", 0
+	// Return everything after the closing fence
+	remaining := strings.Join(lines[endIndex+1:], "\n")
+	return remaining, endIndex + 1 // endIndex+1 lines consumed (0-indexed endIndex, plus the closing fence)
 }
 
 // FormatSecurityFindings formats a list of findings into a human-readable error message
@@ -704,6 +704,18 @@ func TestStripFrontmatter(t *testing.T) {
 			expectedBody:   "# Hello\nWorld",
 			expectedOffset: 0,
 		},
+		{
+			name:           "TOML frontmatter",
+			content:        "+++\nengine = \"copilot\"\n+++\n# Hello",
+			expectedBody:   "# Hello",
+			expectedOffset: 3,
+		},
+		{
+			name:           "JSON frontmatter",
+			content:        "```json\n{\n  \"engine\": \"copilot\"\n}\n```\n# Hello",
+			expectedBody:   "# Hello",
+			expectedOffset: 5,
+		},
 		{
 			name:           "unclosed frontmatter",
 			content:        "---\nengine: copilot\n# Hello",
@@ -312,7 +312,7 @@ func renderSharedMCPConfig(yaml *strings.Builder, toolName string, toolConfig ma
 					if mountIndex > 0 {
 						yaml.WriteString(", ")
 					}
-					fmt.Fprintf(yaml, "\"%s\"", mount)
+					fmt.Fprintf(yaml, "\"%s\"", escapeMountBackslashes(guardMountEnvVars(mount)))
 				}
 				yaml.WriteString("]\n")
 			} else {
@@ -327,7 +327,7 @@ func renderSharedMCPConfig(yaml *strings.Builder, toolName string, toolConfig ma
 						mountComma = ""
 					}
 					// Replace template expressions with environment variable references
-					mountValue := mount
+					mountValue := escapeMountBackslashes(guardMountEnvVars(mount))
 					if renderer.RequiresCopilotFields {
 						mountValue = ReplaceTemplateExpressionsWithEnvVars(mountValue)
 					}
@@ -46,6 +46,9 @@ package workflow
 
 import (
 	"fmt"
+	"path"
+	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
@@ -293,9 +296,50 @@ func validateMCPRequirements(toolName string, mcpConfig map[string]any, toolConf
 	return nil
 }
 
+// protectedMCPMountDestinations lists container paths that MCP mounts may not replace wholesale
+var protectedMCPMountDestinations = []string{"/", "/etc", "/usr"}
+
+// mcpMountKind identifies the type of a custom MCP server mount
+type mcpMountKind string
+
+const (
+	// mcpMountKindBind is a host path bind mount: "/host/path:/container/path:mode"
+	mcpMountKindBind mcpMountKind = "bind"
+	// mcpMountKindVolume is a named Docker volume: "volname:/container/path:mode"
+	mcpMountKindVolume mcpMountKind = "volume"
+	// mcpMountKindTmpfs is a tmpfs mount request ("tmpfs:/container/path"), which is rejected
+	mcpMountKindTmpfs mcpMountKind = "tmpfs"
+)
+
+// namedVolumePattern matches Docker named volume names
+var namedVolumePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// classifyMCPMount determines the mount kind from the mount string's first field.
+// "tmpfs" selects a tmpfs mount, a bare name (not a path) selects a named volume,
+// and anything else is treated as a host path bind mount.
+func classifyMCPMount(mount string) mcpMountKind {
+	parts := splitMountString(mount)
+	if parts[0] == "tmpfs" {
+		return mcpMountKindTmpfs
+	}
+	if len(parts) == 3 && namedVolumePattern.MatchString(parts[0]) {
+		return mcpMountKindVolume
+	}
+	return mcpMountKindBind
+}
+
 // validateMCPMountsSyntax validates that mount strings in a custom MCP server config
 // follow the correct syntax required by MCP Gateway v0.1.5+.
-// Expected format: "source:destination:mode" where mode is either "ro" or "rw".
+// Two mount kinds are supported:
+//   - Bind mounts: "source:destination:mode" where mode is either "ro" or "rw",
+//     optionally combined with an SELinux relabel flag ("ro,z", "rw,Z").
+//     The source may reference environment variables as $VAR or ${VAR} (see mcp_mount_env.go).
+//   - Named volumes: "volname:destination:mode" where volname is a Docker volume name.
+//
+// tmpfs mounts are rejected: the gateway passes every mount to docker as a volume mount
+// (-v), so "tmpfs:/path" would silently create a named volume called "tmpfs" instead.
+//
+// The destination must be an absolute container path other than /, /etc, or /usr.
 func validateMCPMountsSyntax(toolName string, mountsRaw any) error {
 	var mounts []string
 
@@ -313,14 +357,79 @@ func validateMCPMountsSyntax(toolName string, mountsRaw any) error {
 	}
 
 	for i, mount := range mounts {
-		source, dest, mode, err := validateMountStringFormat(mount)
+		var dest string
+		var err error
+		switch kind := classifyMCPMount(mount); kind {
+		case mcpMountKindTmpfs:
+			err = rejectMCPTmpfsMount(toolName, i, mount)
+		case mcpMountKindVolume:
+			dest, err = validateMCPVolumeMount(toolName, i, mount)
+		default:
+			dest, err = validateMCPBindMount(toolName, i, mount)
+		}
 		if err != nil {
-			if source == "" && dest == "" && mode == "" {
-				return fmt.Errorf("tool '%s' mcp configuration mounts[%d] must follow 'source:destination:mode' format, got: %q.\n\nExample:\ntools:\n  %s:\n    container: \"my-registry/my-tool\"\n    mounts:\n      - \"/host/path:/container/path:ro\"\n\nSee: %s", toolName, i, mount, toolName, constants.DocsToolsURL)
-			}
-			return fmt.Errorf("tool '%s' mcp configuration mounts[%d] mode must be 'ro' or 'rw', got: %q.\n\nExample:\ntools:\n  %s:\n    container: \"my-registry/my-tool\"\n    mounts:\n      - \"/host/path:/container/path:ro\"  # read-only\n      - \"/host/path:/container/path:rw\"  # read-write\n\nSee: %s", toolName, i, mode, toolName, constants.DocsToolsURL)
+			return err
+		}
+
+		if err := validateMCPMountDestination(toolName, i, dest); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
+
+// validateMCPBindMount validates a host path bind mount and returns its destination
+func validateMCPBindMount(toolName string, i int, mount string) (string, error) {
+	source, dest, mode, err := validateMountStringFormatWithOptions(mount, true)
+	if err != nil {
+		if source == "" && dest == "" && mode == "" {
+			return "", fmt.Errorf("tool '%s' mcp configuration mounts[%d] must follow 'source:destination:mode' format, got: %q.\n\nExample:\ntools:\n  %s:\n    container: \"my-registry/my-tool\"\n    mounts:\n      - \"/host/path:/container/path:ro\"  # bind mount\n      - \"my-volume:/container/data:rw\"   # named volume\n\nSee: %s", toolName, i, mount, toolName, constants.DocsToolsURL)
+		}
+		return "", fmt.Errorf("tool '%s' mcp configuration mounts[%d] mode must be 'ro' or 'rw', optionally followed by SELinux relabel flag 'z' or 'Z', got: %q.\n\nExample:\ntools:\n  %s:\n    container: \"my-registry/my-tool\"\n    mounts:\n      - \"/host/path:/container/path:ro\"    # read-only\n      - \"/host/path:/container/path:rw\"    # read-write\n      - \"/host/path:/container/path:ro,z\"  # read-only, shared SELinux label\n\nSee: %s", toolName, i, mode, toolName, constants.DocsToolsURL)
+	}
+
+	if source == "" {
+		return "", fmt.Errorf("tool '%s' mcp configuration mounts[%d] bind mount source cannot be empty, got: %q.\n\nExample:\ntools:\n  %s:\n    container: \"my-registry/my-tool\"\n    mounts:\n      - \"/host/path:/container/path:ro\"\n\nSee: %s", toolName, i, mount, toolName, constants.DocsToolsURL)
+	}
+
+	// The source may reference environment variables that the shell expands at runtime
+	if err := validateMountSourceEnvVars(source); err != nil {
+		return "", fmt.Errorf("tool '%s' mcp configuration mounts[%d] source %q is invalid: %v.\n\nExample:\ntools:\n  %s:\n    container: \"my-registry/my-tool\"\n    mounts:\n      - \"${HOME}/.config/tool:/config:ro\"\n\nSee: %s", toolName, i, source, err, toolName, constants.DocsToolsURL)
+	}
+
+	return dest, nil
+}
+
+// validateMCPVolumeMount validates a named volume mount and returns its destination
+func validateMCPVolumeMount(toolName string, i int, mount string) (string, error) {
+	volume, dest, mode, err := validateMountStringFormatWithOptions(mount, true)
+	if err != nil {
+		return "", fmt.Errorf("tool '%s' mcp configuration mounts[%d] named volume '%s' mode must be 'ro' or 'rw', optionally followed by SELinux relabel flag 'z' or 'Z', got: %q.\n\nExample:\ntools:\n  %s:\n    container: \"my-registry/my-tool\"\n    mounts:\n      - \"%s:/container/data:rw\"\n\nSee: %s", toolName, i, volume, mode, toolName, volume, constants.DocsToolsURL)
+	}
+	return dest, nil
+}
+
+// rejectMCPTmpfsMount reports that tmpfs mounts are not supported for MCP servers
+func rejectMCPTmpfsMount(toolName string, i int, mount string) error {
+	return fmt.Errorf("tool '%s' mcp configuration mounts[%d] tmpfs mounts are not supported, got: %q. The MCP gateway passes mounts to docker as volume mounts, so 'tmpfs' would be used as a named volume. Use a named volume for scratch space instead.\n\nExample:\ntools:\n  %s:\n    container: \"my-registry/my-tool\"\n    mounts:\n      - \"my-tool-scratch:/container/scratch:rw\"\n\nSee: %s", toolName, i, mount, toolName, constants.DocsToolsURL)
+}
+
+// validateMCPMountDestination validates the container path of any mount kind
+func validateMCPMountDestination(toolName string, i int, dest string) error {
+	// The destination is a literal path inside the container and must be absolute
+	if strings.Contains(dest, "$") {
+		return fmt.Errorf("tool '%s' mcp configuration mounts[%d] destination %q must be a literal container path; environment variables are only supported in the mount source.\n\nSee: %s", toolName, i, dest, constants.DocsToolsURL)
+	}
+	if !path.IsAbs(dest) {
+		return fmt.Errorf("tool '%s' mcp configuration mounts[%d] destination must be an absolute container path, got: %q.\n\nExample:\ntools:\n  %s:\n    container: \"my-registry/my-tool\"\n    mounts:\n      - \"/host/path:/container/path:ro\"\n\nSee: %s", toolName, i, dest, toolName, constants.DocsToolsURL)
+	}
+
+	// Mounting over the container root or a core system directory replaces it wholesale
+	// and breaks the container, so reject it outright
+	if cleanDest := path.Clean(dest); slices.Contains(protectedMCPMountDestinations, cleanDest) {
+		return fmt.Errorf("tool '%s' mcp configuration mounts[%d] destination %q is not allowed: mounting over %s would replace the container's system directory. Mount into a subdirectory instead.\n\nExample:\ntools:\n  %s:\n    container: \"my-registry/my-tool\"\n    mounts:\n      - \"/host/config:/etc/my-tool:ro\"\n\nSee: %s", toolName, i, dest, cleanDest, toolName, constants.DocsToolsURL)
+	}
+
+	return nil
+}
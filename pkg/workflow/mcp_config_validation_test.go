@@ -97,6 +97,154 @@ func TestValidateMCPMountsSyntax(t *testing.T) {
 			wantErr: true,
 			errMsg:  "mounts[1]",
 		},
+		{
+			name:      "valid ro with shared SELinux relabel flag",
+			toolName:  "my-tool",
+			mountsRaw: []string{"/data:/data:ro,z"},
+			wantErr:   false,
+		},
+		{
+			name:      "valid rw with private SELinux relabel flag",
+			toolName:  "my-tool",
+			mountsRaw: []string{"/data:/data:rw,Z"},
+			wantErr:   false,
+		},
+		{
+			name:      "relabel flag without access mode",
+			toolName:  "my-tool",
+			mountsRaw: []string{"/data:/data:z"},
+			wantErr:   true,
+			errMsg:    "mode must be 'ro' or 'rw'",
+		},
+		{
+			name:      "conflicting access modes",
+			toolName:  "my-tool",
+			mountsRaw: []string{"/data:/data:ro,rw"},
+			wantErr:   true,
+			errMsg:    "mode must be 'ro' or 'rw'",
+		},
+		{
+			name:      "both relabel flags",
+			toolName:  "my-tool",
+			mountsRaw: []string{"/data:/data:ro,z,Z"},
+			wantErr:   true,
+			errMsg:    "mode must be 'ro' or 'rw'",
+		},
+		{
+			name:     "unknown mount option includes tool and index",
+			toolName: "special-tool",
+			mountsRaw: []string{
+				"/data:/data:ro,z",
+				"/data:/other:ro,shared",
+			},
+			wantErr: true,
+			errMsg:  "tool 'special-tool' mcp configuration mounts[1] mode",
+		},
+		{
+			name:      "valid source with environment variable",
+			toolName:  "my-tool",
+			mountsRaw: []string{"$HOME/.config/tool:/config:ro"},
+			wantErr:   false,
+		},
+		{
+			name:      "source with command substitution",
+			toolName:  "my-tool",
+			mountsRaw: []string{"$(id -un)/data:/data:ro"},
+			wantErr:   true,
+			errMsg:    "only $VAR and ${VAR} are supported",
+		},
+		{
+			name:      "destination with environment variable",
+			toolName:  "my-tool",
+			mountsRaw: []string{"/data:$TARGET:ro"},
+			wantErr:   true,
+			errMsg:    "must be a literal container path",
+		},
+		{
+			name:      "tmpfs mount is rejected",
+			toolName:  "my-tool",
+			mountsRaw: []string{"tmpfs:/scratch"},
+			wantErr:   true,
+			errMsg:    "tmpfs mounts are not supported",
+		},
+		{
+			name:      "tmpfs source with mode is rejected",
+			toolName:  "my-tool",
+			mountsRaw: []string{"tmpfs:/scratch:rw"},
+			wantErr:   true,
+			errMsg:    "tmpfs mounts are not supported",
+		},
+		{
+			name:      "valid named volume",
+			toolName:  "my-tool",
+			mountsRaw: []string{"tool-cache:/cache:rw"},
+			wantErr:   false,
+		},
+		{
+			name:      "named volume with invalid mode",
+			toolName:  "my-tool",
+			mountsRaw: []string{"tool-cache:/cache:rwx"},
+			wantErr:   true,
+			errMsg:    "named volume 'tool-cache' mode must be 'ro' or 'rw'",
+		},
+		{
+			name:      "named volume over protected destination",
+			toolName:  "my-tool",
+			mountsRaw: []string{"tool-etc:/etc:ro"},
+			wantErr:   true,
+			errMsg:    "is not allowed",
+		},
+		{
+			name:      "bind mount with empty source",
+			toolName:  "my-tool",
+			mountsRaw: []string{":/data:ro"},
+			wantErr:   true,
+			errMsg:    "bind mount source cannot be empty",
+		},
+		{
+			name:      "relative destination",
+			toolName:  "my-tool",
+			mountsRaw: []string{"/host/data:data:ro"},
+			wantErr:   true,
+			errMsg:    "destination must be an absolute container path",
+		},
+		{
+			name:      "root destination",
+			toolName:  "my-tool",
+			mountsRaw: []string{"/host/data:/:ro"},
+			wantErr:   true,
+			errMsg:    "is not allowed",
+		},
+		{
+			name:      "etc destination",
+			toolName:  "my-tool",
+			mountsRaw: []string{"/host/etc:/etc:ro"},
+			wantErr:   true,
+			errMsg:    "is not allowed",
+		},
+		{
+			name:      "usr destination with trailing slash",
+			toolName:  "my-tool",
+			mountsRaw: []string{"/host/usr:/usr/:ro"},
+			wantErr:   true,
+			errMsg:    "is not allowed",
+		},
+		{
+			name:     "protected destination error includes tool name and index",
+			toolName: "special-tool",
+			mountsRaw: []string{
+				"/host/data:/data:ro",
+				"/host/etc:/etc:rw",
+			},
+			wantErr: true,
+			errMsg:  "tool 'special-tool' mcp configuration mounts[1]",
+		},
+		{
+			name:      "subdirectory of protected destination is allowed",
+			toolName:  "my-tool",
+			mountsRaw: []string{"/host/config:/etc/my-tool:ro"},
+			wantErr:   false,
+		},
 		{
 			name:     "[]any with non-string items are silently skipped",
 			toolName: "my-tool",
@@ -124,3 +272,26 @@ func TestValidateMCPMountsSyntax(t *testing.T) {
 		})
 	}
 }
+
+// TestClassifyMCPMount tests that mounts are classified as bind, volume, or tmpfs.
+func TestClassifyMCPMount(t *testing.T) {
+	tests := []struct {
+		mount string
+		want  mcpMountKind
+	}{
+		{mount: "/host/data:/data:ro", want: mcpMountKindBind},
+		{mount: "$HOME/.config:/config:ro", want: mcpMountKindBind},
+		{mount: "./relative:/data:ro", want: mcpMountKindBind},
+		{mount: "tool-cache:/cache:rw", want: mcpMountKindVolume},
+		{mount: "cache_v1.2:/cache:ro,z", want: mcpMountKindVolume},
+		{mount: "tmpfs:/scratch", want: mcpMountKindTmpfs},
+		{mount: "tmpfs:/scratch:rw", want: mcpMountKindTmpfs},
+		{mount: "/invalid/mount", want: mcpMountKindBind},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mount, func(t *testing.T) {
+			assert.Equal(t, tt.want, classifyMCPMount(tt.mount), "mount kind should match")
+		})
+	}
+}
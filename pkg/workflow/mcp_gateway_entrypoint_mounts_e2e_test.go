@@ -314,3 +314,39 @@ Test that mounts with environment variables are properly handled.
 	assert.True(t, hasWorkspaceMount, "Compiled YAML should contain mount with environment variable")
 	assert.Contains(t, yamlStr, "/tmp:/tmp:rw", "Compiled YAML should contain regular mount")
 }
+
+// TestMCPServerMountSourcesWithColonsE2E tests that mount sources containing colons, such as
+// Windows drive paths and GitHub Actions expressions, pass schema validation and compile
+func TestMCPServerMountSourcesWithColonsE2E(t *testing.T) {
+	markdown := `---
+on: workflow_dispatch
+engine: claude
+mcp-servers:
+  my-tool:
+    container: my-registry/my-tool
+    mounts:
+      - 'C:\tools\data:/data:ro'
+      - "${{ runner.os == 'Windows' && 'C:/cache' || '/tmp/cache' }}/tool:/cache:rw,z"
+      - ${RUNNER_TEMP}/tool:/scratch:rw
+---
+
+# Test Workflow
+
+Test that mount sources with colons are accepted.
+`
+
+	tmpDir := testutil.TempDir(t, "colon-mounts-test")
+	testFile := filepath.Join(tmpDir, "test-colon-mounts.md")
+	require.NoError(t, os.WriteFile(testFile, []byte(markdown), 0644), "Failed to write test file")
+
+	compiler := NewCompiler()
+	require.NoError(t, compiler.CompileWorkflow(testFile), "Compilation should succeed")
+
+	result, err := os.ReadFile(stringutil.MarkdownToLockFile(testFile))
+	require.NoError(t, err, "Failed to read lock file")
+	yamlStr := string(result)
+
+	assert.Contains(t, yamlStr, `C:\\tools\\data:/data:ro`, "Compiled YAML should contain the Windows drive mount")
+	assert.Contains(t, yamlStr, ":/cache:rw,z", "Compiled YAML should contain the expression mount")
+	assert.Contains(t, yamlStr, ":/scratch:rw", "Compiled YAML should contain the environment variable mount")
+}
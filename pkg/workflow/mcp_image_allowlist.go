@@ -0,0 +1,91 @@
+// This file provides the compile-time allowlist of MCP container images.
+//
+// # Container Image Allowlist
+//
+// Administrators can restrict which container images custom MCP servers may
+// run with --allowed-mcp-image. Each entry is either an exact image or a
+// registry/namespace prefix ending in "/":
+//
+//	--allowed-mcp-image ghcr.io/myorg/              # any image under ghcr.io/myorg/
+//	--allowed-mcp-image docker.io/mcp/fetch         # this image, any tag
+//	--allowed-mcp-image docker.io/mcp/time:v1.2.0   # this tag only
+//
+// The image of a tool is its 'container' field, with 'version' appended as the
+// tag. A tool whose image matches no entry fails compilation. An empty
+// allowlist allows every image.
+
+package workflow
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var mcpImageAllowlistLog = logger.New("workflow:mcp_image_allowlist")
+
+// validateMCPContainerImages returns an error naming the first tool whose container image is
+// not allowed by the allowlist. An empty allowlist allows every image.
+func validateMCPContainerImages(tools map[string]any, allowed []string) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	for _, toolName := range slices.Sorted(maps.Keys(tools)) {
+		toolConfig, ok := tools[toolName].(map[string]any)
+		if !ok {
+			continue
+		}
+		container, ok := toolConfig["container"].(string)
+		if !ok || container == "" {
+			continue
+		}
+		image := container
+		if version, ok := toolConfig["version"].(string); ok && version != "" {
+			image = container + ":" + version
+		}
+		if isMCPImageAllowed(image, allowed) {
+			mcpImageAllowlistLog.Printf("Container image %s of tool %s is allowed", image, toolName)
+			continue
+		}
+		return fmt.Errorf("tool '%s' mcp configuration uses container image %q, which is not in the allowed images list (%s). Use an allowed image or ask an administrator to allow it.\n\nSee: %s", toolName, image, strings.Join(allowed, ", "), constants.DocsToolsURL)
+	}
+	return nil
+}
+
+// isMCPImageAllowed reports whether image matches an allowlist entry. Entries ending in "/"
+// match any image under that prefix. Other entries match the full reference, or the image
+// name regardless of tag and digest.
+func isMCPImageAllowed(image string, allowed []string) bool {
+	name := mcpImageName(image)
+	for _, entry := range allowed {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if strings.HasSuffix(entry, "/") {
+			if strings.HasPrefix(image, entry) {
+				return true
+			}
+			continue
+		}
+		if image == entry || name == entry {
+			return true
+		}
+	}
+	return false
+}
+
+// mcpImageName strips the tag and digest from an image reference. A colon before the last
+// "/" is a registry port, not a tag.
+func mcpImageName(image string) string {
+	name, _, _ := strings.Cut(image, "@")
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name = name[:i]
+	}
+	return name
+}
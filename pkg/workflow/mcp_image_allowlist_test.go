@@ -0,0 +1,61 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateMCPContainerImages(t *testing.T) {
+	tools := map[string]any{
+		"github": map[string]any{"toolsets": []any{"default"}},
+		"fetch":  map[string]any{"container": "ghcr.io/myorg/fetch", "version": "v1.2.0"},
+		"local":  map[string]any{"command": "node", "args": []any{"server.js"}},
+	}
+
+	tests := []struct {
+		name    string
+		allowed []string
+		wantErr bool
+	}{
+		{name: "empty allowlist allows all", allowed: nil},
+		{name: "prefix match", allowed: []string{"ghcr.io/myorg/"}},
+		{name: "image name matches any tag", allowed: []string{"ghcr.io/myorg/fetch"}},
+		{name: "exact tag match", allowed: []string{"docker.io/mcp/time", "ghcr.io/myorg/fetch:v1.2.0"}},
+		{name: "other tag is rejected", allowed: []string{"ghcr.io/myorg/fetch:v2.0.0"}, wantErr: true},
+		{name: "prefix requires a path boundary", allowed: []string{"ghcr.io/my"}, wantErr: true},
+		{name: "other registry is rejected", allowed: []string{"docker.io/myorg/"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMCPContainerImages(tools, tt.allowed)
+			if !tt.wantErr {
+				require.NoError(t, err, "image should be allowed")
+				return
+			}
+			require.Error(t, err, "image should be rejected")
+			assert.Contains(t, err.Error(), "tool 'fetch'", "error should name the tool")
+			assert.Contains(t, err.Error(), `"ghcr.io/myorg/fetch:v1.2.0"`, "error should name the image")
+		})
+	}
+}
+
+func TestMCPImageName(t *testing.T) {
+	tests := map[string]string{
+		"ghcr.io/myorg/fetch":                 "ghcr.io/myorg/fetch",
+		"ghcr.io/myorg/fetch:v1":              "ghcr.io/myorg/fetch",
+		"ghcr.io/myorg/fetch@sha256:abc":      "ghcr.io/myorg/fetch",
+		"ghcr.io/myorg/fetch:v1@sha256:abc":   "ghcr.io/myorg/fetch",
+		"localhost:5000/fetch":                "localhost:5000/fetch",
+		"localhost:5000/fetch:latest":         "localhost:5000/fetch",
+		"localhost:5000/fetch@sha256:abc":     "localhost:5000/fetch",
+		"localhost:5000/fetch:v1@sha256:abcd": "localhost:5000/fetch",
+	}
+	for image, want := range tests {
+		assert.Equal(t, want, mcpImageName(image), "image name of %s", image)
+	}
+}
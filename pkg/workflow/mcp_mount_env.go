@@ -0,0 +1,90 @@
+// This file provides environment variable support for MCP mount source paths.
+//
+// # Mount Source Environment Variables
+//
+// Custom MCP server mounts may reference environment variables in their source
+// (host) path so that configurations stay portable across runners:
+//
+//	tools:
+//	  my-tool:
+//	    container: "my-registry/my-tool"
+//	    mounts:
+//	      - "$HOME/.config/tool:/config:ro"
+//	      - "${RUNNER_TEMP}/cache:/cache:rw"
+//
+// Expansion happens at runtime: the MCP configuration is written through an
+// unquoted shell heredoc, so the runner's shell expands $VAR and ${VAR}. At
+// compile time we only validate the reference syntax, and at render time each
+// reference is rewritten to ${VAR:?...} so that an unset variable fails the
+// step with a clear message instead of silently producing a broken path.
+//
+// Only plain $VAR and ${VAR} references (and GitHub Actions ${{ }} expressions)
+// are supported. Anything else after a '$' (command substitution, arithmetic,
+// parameter operators) is rejected, since it would otherwise be evaluated by
+// the shell.
+
+package workflow
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// mountEnvVarPattern matches $VAR and ${VAR} references in a mount source
+var mountEnvVarPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// validateMountSourceEnvVars checks that every '$' in a mount source starts a
+// well-formed $VAR or ${VAR} reference.
+func validateMountSourceEnvVars(source string) error {
+	for i := 0; i < len(source); i++ {
+		if source[i] != '$' {
+			continue
+		}
+		// GitHub Actions expressions (${{ ... }}) are handled separately at render time
+		if strings.HasPrefix(source[i:], "${{") {
+			end := strings.Index(source[i:], "}}")
+			if end == -1 {
+				return fmt.Errorf("unclosed GitHub Actions expression at %q", source[i:])
+			}
+			i += end + 1
+			continue
+		}
+		loc := mountEnvVarPattern.FindStringIndex(source[i:])
+		if loc == nil || loc[0] != 0 {
+			return fmt.Errorf("invalid environment variable reference at %q: only $VAR and ${VAR} are supported", source[i:])
+		}
+		i += loc[1] - 1
+	}
+	return nil
+}
+
+// guardMountEnvVars rewrites $VAR and ${VAR} references in the source part of a
+// mount string to ${VAR:?...} so the shell aborts with a descriptive error when
+// the variable is unset. Mounts without references are returned unchanged.
+func guardMountEnvVars(mount string) string {
+	if !strings.Contains(mount, "$") {
+		return mount
+	}
+
+	parts := splitMountString(mount)
+	if len(parts) != 3 {
+		return mount
+	}
+
+	parts[0] = mountEnvVarPattern.ReplaceAllStringFunc(parts[0], func(ref string) string {
+		matches := mountEnvVarPattern.FindStringSubmatch(ref)
+		name := matches[1]
+		if name == "" {
+			name = matches[2]
+		}
+		return fmt.Sprintf("${%s:?environment variable %s used in MCP mount source is not set}", name, name)
+	})
+	return strings.Join(parts, ":")
+}
+
+// escapeMountBackslashes escapes the backslashes of a mount string, such as those of a
+// Windows drive path, so that it can be written inside a JSON or TOML string literal
+func escapeMountBackslashes(mount string) string {
+	return strings.ReplaceAll(mount, `\`, `\\`)
+}
@@ -0,0 +1,116 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateMountSourceEnvVars(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		wantErr bool
+	}{
+		{name: "literal path", source: "/host/data", wantErr: false},
+		{name: "dollar variable", source: "$HOME/.config/tool", wantErr: false},
+		{name: "braced variable", source: "${RUNNER_TEMP}/cache", wantErr: false},
+		{name: "multiple variables", source: "${HOME}/$TOOL_DIR/data", wantErr: false},
+		{name: "github expression", source: "${{ github.workspace }}/data", wantErr: false},
+		{name: "command substitution", source: "$(whoami)/data", wantErr: true},
+		{name: "parameter operator", source: "${HOME:-/tmp}/data", wantErr: true},
+		{name: "unclosed brace", source: "${HOME/data", wantErr: true},
+		{name: "empty braces", source: "${}/data", wantErr: true},
+		{name: "trailing dollar", source: "/data$", wantErr: true},
+		{name: "invalid variable name", source: "$1/data", wantErr: true},
+		{name: "unclosed github expression", source: "${{ github.workspace /data", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMountSourceEnvVars(tt.source)
+			if tt.wantErr {
+				assert.Error(t, err, "expected an error for source %q", tt.source)
+			} else {
+				assert.NoError(t, err, "unexpected error for source %q", tt.source)
+			}
+		})
+	}
+}
+
+func TestGuardMountEnvVars(t *testing.T) {
+	tests := []struct {
+		name     string
+		mount    string
+		expected string
+	}{
+		{
+			name:     "no variables",
+			mount:    "/host/data:/data:ro",
+			expected: "/host/data:/data:ro",
+		},
+		{
+			name:     "dollar variable",
+			mount:    "$HOME/.config/tool:/config:ro",
+			expected: "${HOME:?environment variable HOME used in MCP mount source is not set}/.config/tool:/config:ro",
+		},
+		{
+			name:     "braced variable",
+			mount:    "${RUNNER_TEMP}/cache:/cache:rw,z",
+			expected: "${RUNNER_TEMP:?environment variable RUNNER_TEMP used in MCP mount source is not set}/cache:/cache:rw,z",
+		},
+		{
+			name:     "github expression is left untouched",
+			mount:    "${{ github.workspace }}/data:/data:ro",
+			expected: "${{ github.workspace }}/data:/data:ro",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, guardMountEnvVars(tt.mount), "guarded mount should match")
+		})
+	}
+}
+
+func TestSplitMountString(t *testing.T) {
+	tests := []struct {
+		name     string
+		mount    string
+		expected []string
+	}{
+		{
+			name:     "simple mount",
+			mount:    "/host:/container:ro",
+			expected: []string{"/host", "/container", "ro"},
+		},
+		{
+			name:     "colon inside variable reference",
+			mount:    "${HOME:?not set}/x:/container:ro",
+			expected: []string{"${HOME:?not set}/x", "/container", "ro"},
+		},
+		{
+			name:     "windows drive letter with backslash",
+			mount:    `C:\Users\me:/data:ro`,
+			expected: []string{`C:\Users\me`, "/data", "ro"},
+		},
+		{
+			name:     "windows drive letter with slash",
+			mount:    "d:/data:/data:rw",
+			expected: []string{"d:/data", "/data", "rw"},
+		},
+		{
+			name:     "too many parts are preserved",
+			mount:    "/a:/b:ro:extra",
+			expected: []string{"/a", "/b", "ro", "extra"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, splitMountString(tt.mount), "split parts should match")
+		})
+	}
+}
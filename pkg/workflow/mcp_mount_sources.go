@@ -0,0 +1,105 @@
+// This file provides the optional compile-time check of MCP bind mount sources.
+//
+// # Mount Source Existence
+//
+// validateMCPMountsSyntax only checks the shape of a mount string, so a typo in
+// a host path surfaces when the MCP container fails to start. With
+// --check-mount-sources the compiler also stats the source of every bind mount
+// on the machine that compiles the workflow:
+//
+//	tools:
+//	  my-tool:
+//	    container: "my-registry/my-tool"
+//	    mounts:
+//	      - "${HOME}/.config/tool:/config:ro"   # checked after expanding HOME
+//	      - "my-volume:/data:rw"                 # named volume, not checked
+//
+// A missing source is a warning, or an error in strict mode. Sources that are
+// only known on the runner are skipped: those referencing GitHub Actions
+// expressions or environment variables unset at compile time, and relative
+// paths.
+
+package workflow
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var mcpMountSourcesLog = logger.New("workflow:mcp_mount_sources")
+
+// checkMCPMountSources reports bind mounts of custom MCP servers whose host source does not
+// exist. It warns, or returns an error in strict mode. It does nothing unless enabled with
+// WithCheckMountSources.
+func (c *Compiler) checkMCPMountSources(tools map[string]any) error {
+	if !c.checkMountSources {
+		return nil
+	}
+
+	for _, toolName := range slices.Sorted(maps.Keys(tools)) {
+		toolConfig, ok := tools[toolName].(map[string]any)
+		if !ok {
+			continue
+		}
+		var mounts []string
+		switch v := toolConfig["mounts"].(type) {
+		case []any:
+			for _, item := range v {
+				if mount, ok := item.(string); ok {
+					mounts = append(mounts, mount)
+				}
+			}
+		case []string:
+			mounts = v
+		}
+		for i, mount := range mounts {
+			if classifyMCPMount(mount) != mcpMountKindBind {
+				continue
+			}
+			source, ok := expandMountSource(splitMountString(mount)[0])
+			if !ok {
+				mcpMountSourcesLog.Printf("Skipping runtime-only source of %s mounts[%d]", toolName, i)
+				continue
+			}
+			if _, err := os.Stat(source); err == nil || !os.IsNotExist(err) {
+				continue
+			}
+
+			message := fmt.Sprintf("tool '%s' mcp configuration mounts[%d] source %q does not exist", toolName, i, source)
+			if c.strictMode {
+				return fmt.Errorf("%s. Create it or fix the path; strict mode does not allow missing mount sources", message)
+			}
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(message+". The MCP server container will fail to start if it is also missing on the runner"))
+			c.IncrementWarningCount()
+		}
+	}
+	return nil
+}
+
+// expandMountSource expands $VAR and ${VAR} references in a bind mount source from the
+// compile-time environment. It returns false for sources that can only be resolved on the
+// runner: those with GitHub Actions expressions, unset variables, or a relative path.
+func expandMountSource(source string) (string, bool) {
+	if strings.Contains(source, "${{") {
+		return "", false
+	}
+	resolved := true
+	expanded := os.Expand(source, func(name string) string {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			resolved = false
+		}
+		return value
+	})
+	if !resolved || !filepath.IsAbs(expanded) {
+		return "", false
+	}
+	return expanded, true
+}
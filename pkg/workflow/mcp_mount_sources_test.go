@@ -0,0 +1,58 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckMCPMountSources(t *testing.T) {
+	existing := t.TempDir()
+	missing := filepath.Join(existing, "missing")
+	t.Setenv("GH_AW_TEST_MOUNT_DIR", existing)
+
+	tools := func(mounts ...any) map[string]any {
+		return map[string]any{
+			"my-tool": map[string]any{"container": "my-registry/my-tool", "mounts": mounts},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		mounts   []any
+		warnings int
+	}{
+		{name: "existing source", mounts: []any{existing + ":/data:ro"}},
+		{name: "existing source after env expansion", mounts: []any{"${GH_AW_TEST_MOUNT_DIR}:/data:ro", "$GH_AW_TEST_MOUNT_DIR:/other:rw"}},
+		{name: "missing source", mounts: []any{missing + ":/data:ro"}, warnings: 1},
+		{name: "missing source after env expansion", mounts: []any{"${GH_AW_TEST_MOUNT_DIR}/missing:/data:ro"}, warnings: 1},
+		{name: "named volumes are skipped", mounts: []any{"my-volume:/data:rw"}},
+		{name: "runtime-only sources are skipped", mounts: []any{"${GH_AW_TEST_UNSET_MOUNT_DIR}/x:/data:ro", "${{ runner.temp }}/x:/tmp/x:rw", "relative/dir:/data:ro"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compiler := NewCompiler(WithCheckMountSources(true))
+			err := compiler.checkMCPMountSources(tools(tt.mounts...))
+			require.NoError(t, err, "missing sources should only warn outside strict mode")
+			assert.Equal(t, tt.warnings, compiler.GetWarningCount(), "warning count should match the missing sources")
+		})
+	}
+
+	t.Run("strict mode makes a missing source an error", func(t *testing.T) {
+		compiler := NewCompiler(WithCheckMountSources(true), WithStrictMode(true))
+		err := compiler.checkMCPMountSources(tools(missing + ":/data:ro"))
+		require.Error(t, err, "missing source should fail in strict mode")
+		assert.Contains(t, err.Error(), "mounts[0] source \""+missing+"\" does not exist", "error should name the mount and source")
+	})
+
+	t.Run("check is disabled by default", func(t *testing.T) {
+		compiler := NewCompiler(WithStrictMode(true))
+		require.NoError(t, compiler.checkMCPMountSources(tools(missing+":/data:ro")), "sources should not be checked unless enabled")
+		assert.Zero(t, compiler.GetWarningCount(), "no warning should be emitted")
+	})
+}
@@ -0,0 +1,189 @@
+//go:build !js && !wasm
+
+// This file provides compile-time resolution of the safe-outputs mentions allowlist.
+//
+// # Mentions Allowlist Validation
+//
+// When validation is enabled and a GitHub token is available, the compiler checks the
+// safe-outputs.mentions configuration against GitHub:
+//   - Every login in mentions.allowed must resolve to a GitHub user or bot; unresolvable
+//     logins are reported as warnings, since they can never match a mention.
+//   - When allow-team-members is true, members of the configured teams are folded into
+//     mentions.allowed, so the compiled allowlist is enforced even if the runtime team
+//     lookup is unavailable. Members listed in mentions.denied are left out. Teams that
+//     cannot be read are reported as warnings and are still expanded at runtime.
+//
+// Without a token the configuration is compiled as written. Lookups are cached per login
+// and per team to avoid repeated API calls when compiling many workflows.
+
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var mentionsValidationLog = logger.New("workflow:mentions_validation")
+
+// teamMembersPageSize is the number of team members requested per page
+const teamMembersPageSize = 100
+
+// Lookups against the GitHub API, replaceable in tests
+var (
+	lookupGitHubUser      = lookupGitHubUserFromAPI
+	listGitHubTeamMembers = listGitHubTeamMembersFromAPI
+)
+
+var (
+	githubUserCache  = sync.Map{} // login (lowercase) -> bool
+	teamMembersCache = sync.Map{} // org/team (lowercase) -> []string
+)
+
+// resolveMentionsAllowlist validates the logins in safe-outputs.mentions.allowed and folds in
+// team members when allow-team-members is enabled. Problems are reported as warnings.
+func (c *Compiler) resolveMentionsAllowlist(workflowData *WorkflowData, markdownPath string) {
+	if workflowData.SafeOutputs == nil || workflowData.SafeOutputs.Mentions == nil {
+		return
+	}
+	mentions := workflowData.SafeOutputs.Mentions
+	foldTeams := mentions.AllowTeamMembers != nil && *mentions.AllowTeamMembers && len(mentions.Teams) > 0
+	if len(mentions.Allowed) == 0 && !foldTeams {
+		return
+	}
+
+	if !hasGitHubToken() {
+		mentionsValidationLog.Print("No GitHub token available, skipping mentions allowlist resolution")
+		return
+	}
+	mentionsValidationLog.Printf("Resolving mentions allowlist: allowed=%d, teams=%d, fold_teams=%v", len(mentions.Allowed), len(mentions.Teams), foldTeams)
+
+	warn := func(message string) {
+		fmt.Fprintln(os.Stderr, formatCompilerMessage(markdownPath, "warning", message))
+		c.IncrementWarningCount()
+	}
+
+	allowed := make([]string, 0, len(mentions.Allowed))
+	seen := make(map[string]bool)
+	for _, login := range mentions.Allowed {
+		seen[strings.ToLower(login)] = true
+		allowed = append(allowed, login)
+
+		exists, err := lookupGitHubUser(login)
+		if err != nil {
+			mentionsValidationLog.Printf("Could not look up %s: %v", login, err)
+			continue
+		}
+		if !exists {
+			warn(fmt.Sprintf("safe-outputs.mentions.allowed: %q is not a GitHub user or bot and will never match a mention", login))
+		}
+	}
+
+	if foldTeams {
+		// Denied logins are never folded in, even for members of an allowed team
+		denied := make(map[string]bool, len(mentions.Denied))
+		for _, login := range mentions.Denied {
+			denied[strings.ToLower(login)] = true
+		}
+		for _, team := range mentions.Teams {
+			org, slug, ok := strings.Cut(team, "/")
+			if !ok || org == "" || slug == "" {
+				warn(fmt.Sprintf("safe-outputs.mentions.teams: %q is not an org/team slug", team))
+				continue
+			}
+			members, err := listGitHubTeamMembers(org, slug)
+			if err != nil {
+				warn(fmt.Sprintf("safe-outputs.mentions.teams: could not list members of %s (%v); its members will only be resolved at runtime", team, err))
+				continue
+			}
+			added := 0
+			for _, member := range members {
+				if !seen[strings.ToLower(member)] && !denied[strings.ToLower(member)] {
+					seen[strings.ToLower(member)] = true
+					allowed = append(allowed, member)
+					added++
+				}
+			}
+			mentionsValidationLog.Printf("Folded %d members of %s into the mentions allowlist", added, team)
+		}
+	}
+
+	mentions.Allowed = allowed
+}
+
+// hasGitHubToken reports whether a token is available for GitHub API requests
+func hasGitHubToken() bool {
+	if os.Getenv("GH_TOKEN") != "" || os.Getenv("GITHUB_TOKEN") != "" {
+		return true
+	}
+	_, err := api.DefaultRESTClient()
+	return err == nil
+}
+
+// lookupGitHubUserFromAPI reports whether login is an existing GitHub user or bot (with caching)
+func lookupGitHubUserFromAPI(login string) (bool, error) {
+	key := strings.ToLower(login)
+	if cached, ok := githubUserCache.Load(key); ok {
+		return cached.(bool), nil
+	}
+
+	client, err := api.DefaultRESTClient()
+	if err != nil {
+		return false, fmt.Errorf("failed to create REST client: %w", err)
+	}
+
+	var response struct {
+		Login string `json:"login"`
+	}
+	exists := true
+	if err := client.Get("users/"+url.PathEscape(login), &response); err != nil {
+		var httpErr *api.HTTPError
+		if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusNotFound {
+			return false, fmt.Errorf("failed to query user: %w", err)
+		}
+		exists = false
+	}
+
+	githubUserCache.Store(key, exists)
+	return exists, nil
+}
+
+// listGitHubTeamMembersFromAPI returns the logins of the members of org/team (with caching)
+func listGitHubTeamMembersFromAPI(org, team string) ([]string, error) {
+	key := strings.ToLower(org + "/" + team)
+	if cached, ok := teamMembersCache.Load(key); ok {
+		return cached.([]string), nil
+	}
+
+	client, err := api.DefaultRESTClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create REST client: %w", err)
+	}
+
+	var logins []string
+	for page := 1; ; page++ {
+		var members []struct {
+			Login string `json:"login"`
+		}
+		path := fmt.Sprintf("orgs/%s/teams/%s/members?per_page=%d&page=%d", url.PathEscape(org), url.PathEscape(team), teamMembersPageSize, page)
+		if err := client.Get(path, &members); err != nil {
+			return nil, fmt.Errorf("failed to list team members: %w", err)
+		}
+		for _, member := range members {
+			logins = append(logins, member.Login)
+		}
+		if len(members) < teamMembersPageSize {
+			break
+		}
+	}
+
+	teamMembersCache.Store(key, logins)
+	return logins, nil
+}
@@ -0,0 +1,79 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveMentionsAllowlist(t *testing.T) {
+	originalUser, originalTeam := lookupGitHubUser, listGitHubTeamMembers
+	t.Cleanup(func() { lookupGitHubUser, listGitHubTeamMembers = originalUser, originalTeam })
+	t.Setenv("GH_TOKEN", "test-token")
+
+	lookupGitHubUser = func(login string) (bool, error) {
+		return login != "ghost-user", nil
+	}
+	listGitHubTeamMembers = func(org, team string) ([]string, error) {
+		if team == "reviewers" {
+			return []string{"alice", "Octocat", "bob"}, nil
+		}
+		return nil, errors.New("HTTP 404")
+	}
+
+	allowTeamMembers := true
+	newData := func() *WorkflowData {
+		return &WorkflowData{SafeOutputs: &SafeOutputsConfig{Mentions: &MentionsConfig{
+			AllowTeamMembers: &allowTeamMembers,
+			Allowed:          []string{"octocat", "ghost-user"},
+			Teams:            []string{"acme/reviewers", "acme/missing"},
+		}}}
+	}
+
+	t.Run("folds team members and warns about unresolvable entries", func(t *testing.T) {
+		compiler := NewCompiler()
+		data := newData()
+		compiler.resolveMentionsAllowlist(data, "test.md")
+
+		assert.Equal(t, []string{"octocat", "ghost-user", "alice", "bob"}, data.SafeOutputs.Mentions.Allowed,
+			"team members should be appended once, ignoring case")
+		assert.Equal(t, 2, compiler.GetWarningCount(), "unknown login and unreadable team should warn")
+	})
+
+	t.Run("denied team members are not folded", func(t *testing.T) {
+		compiler := NewCompiler()
+		data := newData()
+		data.SafeOutputs.Mentions.Denied = []string{"Alice"}
+		compiler.resolveMentionsAllowlist(data, "test.md")
+
+		assert.Equal(t, []string{"octocat", "ghost-user", "bob"}, data.SafeOutputs.Mentions.Allowed,
+			"denied logins should be left out, ignoring case")
+	})
+
+	t.Run("teams are not folded without allow-team-members", func(t *testing.T) {
+		compiler := NewCompiler()
+		data := newData()
+		data.SafeOutputs.Mentions.AllowTeamMembers = nil
+		compiler.resolveMentionsAllowlist(data, "test.md")
+
+		assert.Equal(t, []string{"octocat", "ghost-user"}, data.SafeOutputs.Mentions.Allowed, "allowlist should be unchanged")
+		assert.Equal(t, 1, compiler.GetWarningCount(), "only the unknown login should warn")
+	})
+
+	t.Run("lookup failures do not warn", func(t *testing.T) {
+		lookupGitHubUser = func(login string) (bool, error) {
+			return false, errors.New("network unavailable")
+		}
+		compiler := NewCompiler()
+		data := newData()
+		data.SafeOutputs.Mentions.Teams = nil
+		compiler.resolveMentionsAllowlist(data, "test.md")
+
+		require.Equal(t, []string{"octocat", "ghost-user"}, data.SafeOutputs.Mentions.Allowed, "allowlist should be unchanged")
+		assert.Zero(t, compiler.GetWarningCount(), "logins that could not be checked should not warn")
+	})
+}
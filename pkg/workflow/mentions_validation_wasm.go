@@ -0,0 +1,5 @@
+//go:build js || wasm
+
+package workflow
+
+func (c *Compiler) resolveMentionsAllowlist(workflowData *WorkflowData, markdownPath string) {}
@@ -16,6 +16,41 @@ type MissingToolConfig struct {
 	CreateIssue          bool     `yaml:"create-issue,omitempty"` // Whether to create/update issues for missing tools (default: true)
 	TitlePrefix          string   `yaml:"title-prefix,omitempty"` // Prefix for issue titles (default: "[missing tool]")
 	Labels               []string `yaml:"labels,omitempty"`       // Labels to add to created issues
+	MaxIssues            *int     `yaml:"max-issues,omitempty"`   // Maximum number of issues to create per run (default: 1, must not exceed max)
+}
+
+// defaultMissingToolMaxIssues is the number of missing-tool issues created per run when max-issues is not set
+const defaultMissingToolMaxIssues = 1
+
+// missingToolIssueMax returns the configured number of missing-tool issues, or the default when unset
+func missingToolIssueMax(config *MissingToolConfig) int {
+	if config.MaxIssues != nil {
+		return *config.MaxIssues
+	}
+	return defaultMissingToolMaxIssues
+}
+
+// validateMissingToolMaxIssues validates that missing-tool max-issues is positive and
+// does not exceed the missing-tool max (when max is a literal integer)
+func validateMissingToolMaxIssues(config *SafeOutputsConfig) error {
+	if config == nil || config.MissingTool == nil || config.MissingTool.MaxIssues == nil {
+		return nil
+	}
+
+	maxIssues := *config.MissingTool.MaxIssues
+	missingToolLog.Printf("Validating missing-tool max-issues: %d", maxIssues)
+	if maxIssues < 1 {
+		return fmt.Errorf("safe-outputs.missing-tool.max-issues must be at least 1, got %d", maxIssues)
+	}
+	if !config.MissingTool.CreateIssue {
+		return errors.New("safe-outputs.missing-tool.max-issues requires create-issue to be enabled")
+	}
+
+	// Expressions are only known at runtime, so only literal limits can be compared
+	if maxTools := templatableIntValue(config.MissingTool.Max); maxTools > 0 && maxIssues > maxTools {
+		return fmt.Errorf("safe-outputs.missing-tool.max-issues (%d) cannot exceed safe-outputs.missing-tool.max (%d)", maxIssues, maxTools)
+	}
+	return nil
 }
 
 // buildCreateOutputMissingToolJob creates the missing_tool job
@@ -138,6 +173,14 @@ func (c *Compiler) parseMissingToolConfig(outputMap map[string]any) *MissingTool
 				missingToolConfig.TitlePrefix = "[missing tool]"
 			}
 
+			// Parse max-issues field, left unset to use the default
+			if maxIssues, exists := configMap["max-issues"]; exists {
+				if maxIssuesInt, ok := parseIntValue(maxIssues); ok {
+					missingToolConfig.MaxIssues = &maxIssuesInt
+					missingToolLog.Printf("max-issues: %d", maxIssuesInt)
+				}
+			}
+
 			// Parse labels field, default to empty array if not specified
 			if labels, exists := configMap["labels"]; exists {
 				if labelsArray, ok := labels.([]any); ok {
@@ -358,3 +358,106 @@ func TestMissingToolConfigParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestMissingToolMaxIssuesParsing(t *testing.T) {
+	compiler := NewCompiler()
+
+	config := compiler.parseMissingToolConfig(map[string]any{
+		"missing-tool": map[string]any{"max": 5, "max-issues": 3},
+	})
+	if config == nil || config.MaxIssues == nil {
+		t.Fatal("Expected max-issues to be parsed")
+	}
+	if *config.MaxIssues != 3 {
+		t.Errorf("Expected max-issues 3, got %d", *config.MaxIssues)
+	}
+	if got := missingToolIssueMax(config); got != 3 {
+		t.Errorf("Expected issue max 3, got %d", got)
+	}
+
+	config = compiler.parseMissingToolConfig(map[string]any{"missing-tool": nil})
+	if got := missingToolIssueMax(config); got != defaultMissingToolMaxIssues {
+		t.Errorf("Expected default issue max %d, got %d", defaultMissingToolMaxIssues, got)
+	}
+}
+
+func TestValidateMissingToolMaxIssues(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+
+	tests := []struct {
+		name        string
+		config      *SafeOutputsConfig
+		errContains string
+	}{
+		{
+			name:   "nil config",
+			config: nil,
+		},
+		{
+			name:   "max-issues unset",
+			config: &SafeOutputsConfig{MissingTool: &MissingToolConfig{CreateIssue: true}},
+		},
+		{
+			name: "max-issues within max",
+			config: &SafeOutputsConfig{MissingTool: &MissingToolConfig{
+				BaseSafeOutputConfig: BaseSafeOutputConfig{Max: strPtr("3")},
+				CreateIssue:          true,
+				MaxIssues:            intPtr(3),
+			}},
+		},
+		{
+			name: "max-issues with unlimited max",
+			config: &SafeOutputsConfig{MissingTool: &MissingToolConfig{
+				CreateIssue: true,
+				MaxIssues:   intPtr(10),
+			}},
+		},
+		{
+			name: "max-issues with expression max",
+			config: &SafeOutputsConfig{MissingTool: &MissingToolConfig{
+				BaseSafeOutputConfig: BaseSafeOutputConfig{Max: strPtr("${{ inputs.max }}")},
+				CreateIssue:          true,
+				MaxIssues:            intPtr(10),
+			}},
+		},
+		{
+			name: "max-issues exceeds max",
+			config: &SafeOutputsConfig{MissingTool: &MissingToolConfig{
+				BaseSafeOutputConfig: BaseSafeOutputConfig{Max: strPtr("3")},
+				CreateIssue:          true,
+				MaxIssues:            intPtr(4),
+			}},
+			errContains: "cannot exceed safe-outputs.missing-tool.max (3)",
+		},
+		{
+			name: "max-issues below one",
+			config: &SafeOutputsConfig{MissingTool: &MissingToolConfig{
+				CreateIssue: true,
+				MaxIssues:   intPtr(0),
+			}},
+			errContains: "must be at least 1",
+		},
+		{
+			name: "max-issues without create-issue",
+			config: &SafeOutputsConfig{MissingTool: &MissingToolConfig{
+				MaxIssues: intPtr(2),
+			}},
+			errContains: "requires create-issue",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMissingToolMaxIssues(tt.config)
+			if tt.errContains == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("Expected error containing %q, got %v", tt.errContains, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var pushFilesLog = logger.New("workflow:push_files")
+
+// pushFilesProtectedBranches lists branch names push-files may never target, whatever the
+// repository's default branch is. The default branch and branches with protection rules are
+// also rejected at runtime.
+var pushFilesProtectedBranches = []string{"main", "master"}
+
+// PushFilesConfig holds configuration for committing files to a branch from agent output,
+// without opening a pull request
+type PushFilesConfig struct {
+	BaseSafeOutputConfig `yaml:",inline"`
+	AllowedBranches      []string `yaml:"allowed-branches,omitempty"` // Glob patterns of branches the agent may push to (e.g. "agent/*"); required
+	AllowedPaths         []string `yaml:"allowed-paths,omitempty"`    // Path prefixes the pushed files must be under (default: any path outside .github/)
+}
+
+// parsePushFilesConfig handles push-files configuration
+func (c *Compiler) parsePushFilesConfig(outputMap map[string]any) *PushFilesConfig {
+	pushFilesLog.Print("Parsing push-files configuration")
+	configData, exists := outputMap["push-files"]
+	if !exists {
+		return nil
+	}
+
+	pushFilesConfig := &PushFilesConfig{}
+	if configMap, ok := configData.(map[string]any); ok {
+		pushFilesConfig.AllowedBranches = ParseStringArrayFromConfig(configMap, "allowed-branches", pushFilesLog)
+		pushFilesConfig.AllowedPaths = ParseStringArrayFromConfig(configMap, "allowed-paths", pushFilesLog)
+
+		// Parse common base fields with default max of 1
+		c.parseBaseSafeOutputConfig(configMap, &pushFilesConfig.BaseSafeOutputConfig, 1)
+
+		pushFilesLog.Printf("Parsed push-files config: allowed_branches=%v, allowed_paths=%v",
+			pushFilesConfig.AllowedBranches, pushFilesConfig.AllowedPaths)
+	} else {
+		// If configData is nil or not a map, still set the default max
+		pushFilesConfig.Max = defaultIntStr(1)
+	}
+
+	return pushFilesConfig
+}
+
+// validatePushFilesConfig ensures push-files names the branches it may push to, that none of
+// them can match a protected branch, and that allowed paths stay inside the repository
+func validatePushFilesConfig(config *SafeOutputsConfig) error {
+	if config == nil || config.PushFiles == nil {
+		return nil
+	}
+
+	if len(config.PushFiles.AllowedBranches) == 0 {
+		return errors.New("safe-outputs.push-files requires 'allowed-branches'. List the branch patterns the agent may push to, e.g. [\"agent/*\"]")
+	}
+
+	for i, pattern := range config.PushFiles.AllowedBranches {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("safe-outputs.push-files.allowed-branches[%d] has invalid pattern %q: %w", i, pattern, err)
+		}
+		for _, protected := range pushFilesProtectedBranches {
+			if matched, _ := path.Match(pattern, protected); matched {
+				return fmt.Errorf("safe-outputs.push-files.allowed-branches[%d] pattern %q matches protected branch %q. Use a dedicated prefix such as \"agent/*\"", i, pattern, protected)
+			}
+		}
+	}
+
+	for i, prefix := range config.PushFiles.AllowedPaths {
+		if prefix == "" || strings.HasPrefix(prefix, "/") || path.Clean(prefix) != strings.TrimSuffix(prefix, "/") || strings.HasPrefix(path.Clean(prefix), "..") {
+			return fmt.Errorf("safe-outputs.push-files.allowed-paths[%d] has invalid value %q. Expected a relative path prefix inside the repository, e.g. \"generated/\"", i, prefix)
+		}
+	}
+
+	pushFilesLog.Printf("Validated push-files config: %d branch pattern(s), %d path prefix(es)",
+		len(config.PushFiles.AllowedBranches), len(config.PushFiles.AllowedPaths))
+	return nil
+}
@@ -0,0 +1,112 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePushFilesConfig(t *testing.T) {
+	compiler := NewCompiler()
+
+	config := compiler.parsePushFilesConfig(map[string]any{
+		"push-files": map[string]any{
+			"allowed-branches": []any{"agent/*"},
+			"allowed-paths":    []any{"generated/"},
+			"max":              2,
+		},
+	})
+	require.NotNil(t, config, "Config should be parsed")
+	assert.Equal(t, []string{"agent/*"}, config.AllowedBranches, "allowed-branches should be parsed")
+	assert.Equal(t, []string{"generated/"}, config.AllowedPaths, "allowed-paths should be parsed")
+	require.NotNil(t, config.Max, "max should be parsed")
+	assert.Equal(t, "2", *config.Max, "max should be parsed")
+
+	config = compiler.parsePushFilesConfig(map[string]any{"push-files": nil})
+	require.NotNil(t, config, "Null config should enable the output so validation can report it")
+	require.NotNil(t, config.Max, "Default max should be set")
+	assert.Equal(t, "1", *config.Max, "Default max should be 1")
+
+	assert.Nil(t, compiler.parsePushFilesConfig(map[string]any{}), "Missing key should not enable the output")
+}
+
+func TestValidatePushFilesConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *SafeOutputsConfig
+		wantErr string
+	}{
+		{
+			name:   "nil safe outputs",
+			config: nil,
+		},
+		{
+			name:   "branch prefix and path prefixes",
+			config: &SafeOutputsConfig{PushFiles: &PushFilesConfig{AllowedBranches: []string{"agent/*", "docs-bot"}, AllowedPaths: []string{"generated/", "docs/api"}}},
+		},
+		{
+			name:    "missing allowed-branches",
+			config:  &SafeOutputsConfig{PushFiles: &PushFilesConfig{}},
+			wantErr: "requires 'allowed-branches'",
+		},
+		{
+			name:    "wildcard matches main",
+			config:  &SafeOutputsConfig{PushFiles: &PushFilesConfig{AllowedBranches: []string{"agent/*", "*"}}},
+			wantErr: `allowed-branches[1] pattern "*" matches protected branch "main"`,
+		},
+		{
+			name:    "literal master",
+			config:  &SafeOutputsConfig{PushFiles: &PushFilesConfig{AllowedBranches: []string{"master"}}},
+			wantErr: `matches protected branch "master"`,
+		},
+		{
+			name:    "malformed pattern",
+			config:  &SafeOutputsConfig{PushFiles: &PushFilesConfig{AllowedBranches: []string{"agent/["}}},
+			wantErr: `allowed-branches[0] has invalid pattern "agent/["`,
+		},
+		{
+			name:    "path escaping the repository",
+			config:  &SafeOutputsConfig{PushFiles: &PushFilesConfig{AllowedBranches: []string{"agent/*"}, AllowedPaths: []string{"../outside"}}},
+			wantErr: `allowed-paths[0] has invalid value "../outside"`,
+		},
+		{
+			name:    "absolute path",
+			config:  &SafeOutputsConfig{PushFiles: &PushFilesConfig{AllowedBranches: []string{"agent/*"}, AllowedPaths: []string{"/etc"}}},
+			wantErr: `allowed-paths[0] has invalid value "/etc"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePushFilesConfig(tt.config)
+			if tt.wantErr == "" {
+				assert.NoError(t, err, "Validation should pass")
+				return
+			}
+			require.Error(t, err, "Validation should fail")
+			assert.Contains(t, err.Error(), tt.wantErr, "Error message mismatch")
+		})
+	}
+}
+
+func TestPushFilesSafeOutputsConfig(t *testing.T) {
+	data := &WorkflowData{
+		SafeOutputs: &SafeOutputsConfig{
+			PushFiles: &PushFilesConfig{
+				AllowedBranches: []string{"agent/*"},
+				AllowedPaths:    []string{"generated/"},
+			},
+		},
+	}
+
+	config := generateSafeOutputsConfig(data)
+	assert.Contains(t, config, `"push_files":{"allowed_branches":["agent/*"],"allowed_paths":["generated/"],"max":1}`, "Safe outputs config should include push_files")
+
+	permissions := ComputePermissionsForSafeOutputs(data.SafeOutputs)
+	level, ok := permissions.Get(PermissionContents)
+	assert.True(t, ok, "contents permission should be set")
+	assert.Equal(t, PermissionWrite, level, "contents permission should be write")
+}
@@ -3,6 +3,8 @@ package workflow
 import (
 	"fmt"
 	"maps"
+	"math"
+	"slices"
 	"strings"
 
 	"github.com/github/gh-aw/pkg/constants"
@@ -35,6 +37,75 @@ func HasSafeJobsEnabled(safeJobs map[string]*SafeJobConfig) bool {
 	return len(safeJobs) > 0
 }
 
+// validateSafeJobInputs rejects choice inputs without options, whose tool schema would accept
+// no value at all, and numeric bounds or defaults that do not fit the input type
+func validateSafeJobInputs(config *SafeOutputsConfig) error {
+	if config == nil {
+		return nil
+	}
+
+	for _, jobName := range slices.Sorted(maps.Keys(config.Jobs)) {
+		job := config.Jobs[jobName]
+		if job == nil {
+			continue
+		}
+		for _, inputName := range slices.Sorted(maps.Keys(job.Inputs)) {
+			input := job.Inputs[inputName]
+			if input == nil {
+				continue
+			}
+			if input.Type == "choice" && len(input.Options) == 0 {
+				return fmt.Errorf("safe-outputs.jobs.%s.inputs.%s is a choice input but has no options. Add the allowed values under 'options'", jobName, inputName)
+			}
+			if err := validateNumericInput(input); err != nil {
+				return fmt.Errorf("safe-outputs.jobs.%s.inputs.%s %w", jobName, inputName, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateNumericInput checks the minimum, maximum, and default of a number or integer input
+func validateNumericInput(input *InputDefinition) error {
+	isNumeric := input.Type == "number" || input.Type == "integer"
+	if !isNumeric {
+		if input.Minimum != nil || input.Maximum != nil {
+			return fmt.Errorf("sets minimum or maximum but has type %q. Bounds are only supported for number and integer inputs", input.Type)
+		}
+		return nil
+	}
+	if input.Type == "integer" {
+		if input.Minimum != nil && *input.Minimum != math.Trunc(*input.Minimum) {
+			return fmt.Errorf("is an integer input but its minimum %v is not a whole number", *input.Minimum)
+		}
+		if input.Maximum != nil && *input.Maximum != math.Trunc(*input.Maximum) {
+			return fmt.Errorf("is an integer input but its maximum %v is not a whole number", *input.Maximum)
+		}
+	}
+	if input.Minimum != nil && input.Maximum != nil && *input.Minimum > *input.Maximum {
+		return fmt.Errorf("has minimum %v greater than maximum %v", *input.Minimum, *input.Maximum)
+	}
+	if input.Default == nil {
+		return nil
+	}
+
+	value, ok := parseFloatValue(input.Default)
+	if input.Type == "integer" && (!ok || value != math.Trunc(value)) {
+		return fmt.Errorf("is an integer input but its default %v is not a whole number", input.Default)
+	}
+	if !ok {
+		// Number defaults given as strings are passed through unchecked
+		return nil
+	}
+	if input.Minimum != nil && value < *input.Minimum {
+		return fmt.Errorf("has default %v below its minimum %v", input.Default, *input.Minimum)
+	}
+	if input.Maximum != nil && value > *input.Maximum {
+		return fmt.Errorf("has default %v above its maximum %v", input.Default, *input.Maximum)
+	}
+	return nil
+}
+
 // parseSafeJobsConfig parses safe-jobs configuration from a jobs map.
 // This function expects a map of job configurations directly (from safe-outputs.jobs).
 // The top-level "safe-jobs" key is NOT supported - only "safe-outputs.jobs" is valid.
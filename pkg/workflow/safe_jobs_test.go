@@ -165,6 +165,110 @@ func TestHasSafeJobsEnabled(t *testing.T) {
 	}
 }
 
+func TestValidateSafeJobInputs(t *testing.T) {
+	tests := []struct {
+		name    string
+		jobs    map[string]*SafeJobConfig
+		wantErr string
+	}{
+		{
+			name: "choice input with options",
+			jobs: map[string]*SafeJobConfig{
+				"deploy": {Inputs: map[string]*InputDefinition{"env": {Type: "choice", Options: []string{"staging", "production"}}}},
+			},
+		},
+		{
+			name: "non-choice inputs without options",
+			jobs: map[string]*SafeJobConfig{
+				"deploy": {Inputs: map[string]*InputDefinition{"note": {Type: "string"}, "dry_run": {Type: "boolean"}}},
+			},
+		},
+		{
+			name: "choice input without options",
+			jobs: map[string]*SafeJobConfig{
+				"deploy": {Inputs: map[string]*InputDefinition{
+					"env":    {Type: "choice"},
+					"region": {Type: "choice", Options: []string{"eu"}},
+				}},
+			},
+			wantErr: "safe-outputs.jobs.deploy.inputs.env is a choice input but has no options",
+		},
+		{
+			name: "integer input with whole default inside its bounds",
+			jobs: map[string]*SafeJobConfig{
+				"retry": {Inputs: map[string]*InputDefinition{"count": {Type: "integer", Default: 3, Minimum: float64Ptr(1.0), Maximum: float64Ptr(5.0)}}},
+			},
+		},
+		{
+			name: "number input with string default",
+			jobs: map[string]*SafeJobConfig{
+				"retry": {Inputs: map[string]*InputDefinition{"ratio": {Type: "number", Default: "0.5"}}},
+			},
+		},
+		{
+			name: "integer input with fractional default",
+			jobs: map[string]*SafeJobConfig{
+				"retry": {Inputs: map[string]*InputDefinition{"count": {Type: "integer", Default: 2.5}}},
+			},
+			wantErr: "safe-outputs.jobs.retry.inputs.count is an integer input but its default 2.5 is not a whole number",
+		},
+		{
+			name: "integer input with string default",
+			jobs: map[string]*SafeJobConfig{
+				"retry": {Inputs: map[string]*InputDefinition{"count": {Type: "integer", Default: "3"}}},
+			},
+			wantErr: "default 3 is not a whole number",
+		},
+		{
+			name: "integer input with fractional bound",
+			jobs: map[string]*SafeJobConfig{
+				"retry": {Inputs: map[string]*InputDefinition{"count": {Type: "integer", Maximum: float64Ptr(4.5)}}},
+			},
+			wantErr: "maximum 4.5 is not a whole number",
+		},
+		{
+			name: "default outside bounds",
+			jobs: map[string]*SafeJobConfig{
+				"retry": {Inputs: map[string]*InputDefinition{"count": {Type: "integer", Default: 9, Maximum: float64Ptr(5.0)}}},
+			},
+			wantErr: "has default 9 above its maximum 5",
+		},
+		{
+			name: "minimum greater than maximum",
+			jobs: map[string]*SafeJobConfig{
+				"retry": {Inputs: map[string]*InputDefinition{"ratio": {Type: "number", Minimum: float64Ptr(2.0), Maximum: float64Ptr(1.0)}}},
+			},
+			wantErr: "has minimum 2 greater than maximum 1",
+		},
+		{
+			name: "bounds on a string input",
+			jobs: map[string]*SafeJobConfig{
+				"retry": {Inputs: map[string]*InputDefinition{"note": {Type: "string", Minimum: float64Ptr(1.0)}}},
+			},
+			wantErr: "Bounds are only supported for number and integer inputs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSafeJobInputs(&SafeOutputsConfig{Jobs: tt.jobs})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if err := validateSafeJobInputs(nil); err != nil {
+		t.Errorf("Expected no error without safe-outputs, got: %v", err)
+	}
+}
+
 func TestBuildSafeJobs(t *testing.T) {
 	c := NewCompiler()
 
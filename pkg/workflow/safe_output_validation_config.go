@@ -342,6 +342,20 @@ var ValidationConfig = map[string]TypeValidationConfig{
 			"repo":       {Type: "string", MaxLength: 256}, // Optional: target repository in format "owner/repo"
 		},
 	},
+	"add_reaction": {
+		DefaultMax: 1,
+		Fields: map[string]FieldValidation{
+			"reaction": {Required: true, Type: "string", Enum: []string{"+1", "-1", "laugh", "confused", "heart", "hooray", "rocket", "eyes"}},
+		},
+	},
+	"push_files": {
+		DefaultMax: 1,
+		Fields: map[string]FieldValidation{
+			"branch":  {Required: true, Type: "string", MaxLength: 256},
+			"message": {Required: true, Type: "string", Sanitize: true, MaxLength: MaxBodyLength},
+			"files":   {Required: true, Type: "array"},
+		},
+	},
 	"missing_data": {
 		DefaultMax: 20,
 		Fields: map[string]FieldValidation{
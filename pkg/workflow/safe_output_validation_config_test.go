@@ -51,6 +51,8 @@ func TestGetValidationConfigJSON(t *testing.T) {
 		"remove_labels",
 		"unassign_from_user",
 		"hide_comment",
+		"add_reaction",
+		"push_files",
 		"missing_data",
 		"autofix_code_scanning_alert",
 		"mark_pull_request_as_ready_for_review",
@@ -191,6 +193,8 @@ func TestGetDefaultMaxForType(t *testing.T) {
 		{"autofix_code_scanning_alert", 10},
 		{"link_sub_issue", 5},
 		{"hide_comment", 5},
+		{"add_reaction", 1},
+		{"push_files", 1},
 		{"remove_labels", 5},
 		{"update_discussion", 1},
 		{"unassign_from_user", 1},
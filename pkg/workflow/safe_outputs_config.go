@@ -47,6 +47,12 @@ var safeOutputsConfigLog = logger.New("workflow:safe_outputs_config")
 // 3. Automated validation in CI (regression prevention)
 //
 
+// ExtractSafeOutputsConfig extracts the safe-outputs configuration from frontmatter without
+// compiling the workflow, e.g. to check it with ValidateSafeOutputs
+func (c *Compiler) ExtractSafeOutputsConfig(frontmatter map[string]any) *SafeOutputsConfig {
+	return c.extractSafeOutputsConfig(frontmatter)
+}
+
 // extractSafeOutputsConfig extracts output configuration from frontmatter
 func (c *Compiler) extractSafeOutputsConfig(frontmatter map[string]any) *SafeOutputsConfig {
 	safeOutputsConfigLog.Print("Extracting safe-outputs configuration from frontmatter")
@@ -56,7 +62,7 @@ func (c *Compiler) extractSafeOutputsConfig(frontmatter map[string]any) *SafeOut
 	if output, exists := frontmatter["safe-outputs"]; exists {
 		if outputMap, ok := output.(map[string]any); ok {
 			safeOutputsConfigLog.Printf("Processing safe-outputs configuration with %d top-level keys", len(outputMap))
-			config = &SafeOutputsConfig{}
+			config = &SafeOutputsConfig{source: outputMap}
 
 			// Handle create-issue
 			issuesConfig := c.parseIssuesConfig(outputMap)
@@ -285,6 +291,18 @@ func (c *Compiler) extractSafeOutputsConfig(frontmatter map[string]any) *SafeOut
 				config.HideComment = hideCommentConfig
 			}
 
+			// Handle add-reaction
+			addReactionConfig := c.parseAddReactionConfig(outputMap)
+			if addReactionConfig != nil {
+				config.AddReaction = addReactionConfig
+			}
+
+			// Handle push-files
+			pushFilesConfig := c.parsePushFilesConfig(outputMap)
+			if pushFilesConfig != nil {
+				config.PushFiles = pushFilesConfig
+			}
+
 			// Handle dispatch-workflow
 			dispatchWorkflowConfig := c.parseDispatchWorkflowConfig(outputMap)
 			if dispatchWorkflowConfig != nil {
@@ -473,6 +491,9 @@ func (c *Compiler) extractSafeOutputsConfig(frontmatter map[string]any) *SafeOut
 					config.App = parseAppConfig(appMap)
 				}
 			}
+
+			// Keep fields added by newer versions of the schema so they are not dropped
+			config.Passthrough = unknownSafeOutputFields(outputMap)
 		}
 	}
 
@@ -36,6 +36,11 @@ func generateCustomJobToolDefinition(jobName string, jobConfig *SafeJobConfig) m
 		"properties": make(map[string]any),
 	}
 
+	// Describe the schema itself so MCP clients can label the input form
+	if jobConfig.Description != "" {
+		inputSchema["description"] = jobConfig.Description
+	}
+
 	// Track required fields
 	var requiredFields []string
 
@@ -44,7 +49,9 @@ func generateCustomJobToolDefinition(jobName string, jobConfig *SafeJobConfig) m
 		properties := inputSchema["properties"].(map[string]any)
 
 		for inputName, inputDef := range jobConfig.Inputs {
-			property := map[string]any{}
+			property := map[string]any{
+				"title": inputTitle(inputName),
+			}
 
 			// Add description
 			if inputDef.Description != "" {
@@ -63,6 +70,8 @@ func generateCustomJobToolDefinition(jobName string, jobConfig *SafeJobConfig) m
 				property["type"] = "boolean"
 			case "number":
 				property["type"] = "number"
+			case "integer":
+				property["type"] = "integer"
 			case "string", "":
 				// Default to string if type is not specified
 				property["type"] = "string"
@@ -76,6 +85,14 @@ func generateCustomJobToolDefinition(jobName string, jobConfig *SafeJobConfig) m
 				property["default"] = inputDef.Default
 			}
 
+			// Add numeric bounds if present
+			if inputDef.Minimum != nil {
+				property["minimum"] = *inputDef.Minimum
+			}
+			if inputDef.Maximum != nil {
+				property["maximum"] = *inputDef.Maximum
+			}
+
 			// Track required fields
 			if inputDef.Required {
 				requiredFields = append(requiredFields, inputName)
@@ -102,6 +119,17 @@ func generateCustomJobToolDefinition(jobName string, jobConfig *SafeJobConfig) m
 	return tool
 }
 
+// inputTitle derives a human-readable title from an input key, e.g. "dry_run" → "Dry run"
+func inputTitle(inputName string) string {
+	title := strings.Join(strings.FieldsFunc(inputName, func(r rune) bool {
+		return r == '_' || r == '-'
+	}), " ")
+	if title == "" {
+		return inputName
+	}
+	return strings.ToUpper(title[:1]) + title[1:]
+}
+
 func populateDispatchWorkflowFiles(data *WorkflowData, markdownPath string) {
 	if data.SafeOutputs == nil || data.SafeOutputs.DispatchWorkflow == nil {
 		return
@@ -117,8 +145,16 @@ func populateDispatchWorkflowFiles(data *WorkflowData, markdownPath string) {
 	if data.SafeOutputs.DispatchWorkflow.WorkflowFiles == nil {
 		data.SafeOutputs.DispatchWorkflow.WorkflowFiles = make(map[string]string)
 	}
+	if data.SafeOutputs.DispatchWorkflow.WorkflowInputs == nil {
+		data.SafeOutputs.DispatchWorkflow.WorkflowInputs = make(map[string]map[string]any)
+	}
 
 	for _, workflowName := range data.SafeOutputs.DispatchWorkflow.Workflows {
+		// Declared input schemas take precedence over the target's workflow_dispatch inputs
+		if declared, ok := data.SafeOutputs.DispatchWorkflow.Inputs[workflowName]; ok {
+			data.SafeOutputs.DispatchWorkflow.WorkflowInputs[workflowName] = dispatchInputsRuntimeSchema(declared)
+		}
+
 		// Find the workflow file
 		fileResult, err := findWorkflowFile(workflowName, markdownPath)
 		if err != nil {
@@ -127,10 +163,13 @@ func populateDispatchWorkflowFiles(data *WorkflowData, markdownPath string) {
 		}
 
 		// Determine which file to use - priority: .lock.yml > .yml
+		var workflowPath string
 		var extension string
 		if fileResult.lockExists {
+			workflowPath = fileResult.lockPath
 			extension = ".lock.yml"
 		} else if fileResult.ymlExists {
+			workflowPath = fileResult.ymlPath
 			extension = ".yml"
 		} else {
 			safeOutputsConfigLog.Printf("Warning: workflow file not found for %s (only .md exists, needs compilation)", workflowName)
@@ -140,6 +179,16 @@ func populateDispatchWorkflowFiles(data *WorkflowData, markdownPath string) {
 		// Store the file extension for runtime use
 		data.SafeOutputs.DispatchWorkflow.WorkflowFiles[workflowName] = extension
 		safeOutputsConfigLog.Printf("Mapped workflow %s to extension %s", workflowName, extension)
+
+		if _, declared := data.SafeOutputs.DispatchWorkflow.WorkflowInputs[workflowName]; declared {
+			continue
+		}
+		workflowInputs, err := extractWorkflowDispatchInputs(workflowPath)
+		if err != nil {
+			safeOutputsConfigLog.Printf("Warning: failed to extract inputs for workflow %s from %s: %v", workflowName, workflowPath, err)
+			continue
+		}
+		data.SafeOutputs.DispatchWorkflow.WorkflowInputs[workflowName] = dispatchInputsRuntimeSchema(workflowInputs)
 	}
 }
 
@@ -179,6 +228,9 @@ func generateSafeOutputsConfig(data *WorkflowData) string {
 		}
 		if data.SafeOutputs.AddComments != nil {
 			additionalFields := make(map[string]any)
+			if len(data.SafeOutputs.AddComments.AllowedDomains) > 0 {
+				additionalFields["allowed_domains"] = data.SafeOutputs.AddComments.AllowedDomains
+			}
 			// Note: AddCommentsConfig has Target, TargetRepoSlug, AllowedRepos but not embedded SafeOutputTargetConfig
 			// So we need to construct the target config manually
 			targetConfig := SafeOutputTargetConfig{
@@ -237,6 +289,8 @@ func generateSafeOutputsConfig(data *WorkflowData) string {
 				data.SafeOutputs.CreatePullRequests.AllowEmpty,
 				data.SafeOutputs.CreatePullRequests.AutoMerge,
 				data.SafeOutputs.CreatePullRequests.Expires,
+				data.SafeOutputs.CreatePullRequests.Draft,
+				data.SafeOutputs.CreatePullRequests.Reviewers,
 			)
 		}
 		if data.SafeOutputs.CreatePullRequestReviewComments != nil {
@@ -246,10 +300,12 @@ func generateSafeOutputsConfig(data *WorkflowData) string {
 			)
 		}
 		if data.SafeOutputs.SubmitPullRequestReview != nil {
-			safeOutputsConfig["submit_pull_request_review"] = generateMaxConfig(
+			reviewConfig := generateMaxConfig(
 				data.SafeOutputs.SubmitPullRequestReview.Max,
 				1, // default max
 			)
+			reviewConfig["allowed_events"] = data.SafeOutputs.SubmitPullRequestReview.effectiveAllowedEvents()
+			safeOutputsConfig["submit_pull_request_review"] = reviewConfig
 		}
 		if data.SafeOutputs.ResolvePullRequestReviewThread != nil {
 			safeOutputsConfig["resolve_pull_request_review_thread"] = generateMaxConfig(
@@ -274,6 +330,9 @@ func generateSafeOutputsConfig(data *WorkflowData) string {
 			if len(data.SafeOutputs.AddLabels.Allowed) > 0 {
 				additionalFields["allowed"] = data.SafeOutputs.AddLabels.Allowed
 			}
+			if len(data.SafeOutputs.AddLabels.Blocked) > 0 {
+				additionalFields["blocked"] = data.SafeOutputs.AddLabels.Blocked
+			}
 			safeOutputsConfig["add_labels"] = generateTargetConfigWithRepos(
 				data.SafeOutputs.AddLabels.SafeOutputTargetConfig,
 				data.SafeOutputs.AddLabels.Max,
@@ -282,10 +341,18 @@ func generateSafeOutputsConfig(data *WorkflowData) string {
 			)
 		}
 		if data.SafeOutputs.RemoveLabels != nil {
-			safeOutputsConfig["remove_labels"] = generateMaxWithAllowedConfig(
+			additionalFields := make(map[string]any)
+			if len(data.SafeOutputs.RemoveLabels.Allowed) > 0 {
+				additionalFields["allowed"] = data.SafeOutputs.RemoveLabels.Allowed
+			}
+			if len(data.SafeOutputs.RemoveLabels.Blocked) > 0 {
+				additionalFields["blocked"] = data.SafeOutputs.RemoveLabels.Blocked
+			}
+			safeOutputsConfig["remove_labels"] = generateTargetConfigWithRepos(
+				data.SafeOutputs.RemoveLabels.SafeOutputTargetConfig,
 				data.SafeOutputs.RemoveLabels.Max,
 				3, // default max
-				data.SafeOutputs.RemoveLabels.Allowed,
+				additionalFields,
 			)
 		}
 		if data.SafeOutputs.AddReviewer != nil {
@@ -377,7 +444,7 @@ func generateSafeOutputsConfig(data *WorkflowData) string {
 			// Add issue creation config if enabled
 			if data.SafeOutputs.MissingTool.CreateIssue {
 				createIssueConfig := make(map[string]any)
-				createIssueConfig["max"] = 1 // Only create one issue per workflow run
+				createIssueConfig["max"] = missingToolIssueMax(data.SafeOutputs.MissingTool) // One issue per workflow run unless max-issues is set
 
 				if data.SafeOutputs.MissingTool.TitlePrefix != "" {
 					createIssueConfig["title_prefix"] = data.SafeOutputs.MissingTool.TitlePrefix
@@ -471,6 +538,21 @@ func generateSafeOutputsConfig(data *WorkflowData) string {
 				data.SafeOutputs.HideComment.AllowedReasons,
 			)
 		}
+		if data.SafeOutputs.AddReaction != nil {
+			safeOutputsConfig["add_reaction"] = generateAddReactionConfig(
+				data.SafeOutputs.AddReaction.Max,
+				1, // default max
+				data.SafeOutputs.AddReaction.AllowedReactions,
+			)
+		}
+		if data.SafeOutputs.PushFiles != nil {
+			safeOutputsConfig["push_files"] = generatePushFilesConfig(
+				data.SafeOutputs.PushFiles.Max,
+				1, // default max
+				data.SafeOutputs.PushFiles.AllowedBranches,
+				data.SafeOutputs.PushFiles.AllowedPaths,
+			)
+		}
 	}
 
 	// Add safe-jobs configuration from SafeOutputs.Jobs
@@ -505,6 +587,12 @@ func generateSafeOutputsConfig(data *WorkflowData) string {
 					if len(inputDef.Options) > 0 {
 						inputConfig["options"] = inputDef.Options
 					}
+					if inputDef.Minimum != nil {
+						inputConfig["minimum"] = *inputDef.Minimum
+					}
+					if inputDef.Maximum != nil {
+						inputConfig["maximum"] = *inputDef.Maximum
+					}
 					inputsConfig[inputName] = inputConfig
 				}
 				safeJobConfig["inputs"] = inputsConfig
@@ -538,6 +626,16 @@ func generateSafeOutputsConfig(data *WorkflowData) string {
 			mentionsConfig["allowed"] = data.SafeOutputs.Mentions.Allowed
 		}
 
+		// Handle denied list
+		if len(data.SafeOutputs.Mentions.Denied) > 0 {
+			mentionsConfig["denied"] = data.SafeOutputs.Mentions.Denied
+		}
+
+		// Handle teams list (expanded to member logins at runtime)
+		if len(data.SafeOutputs.Mentions.Teams) > 0 {
+			mentionsConfig["teams"] = data.SafeOutputs.Mentions.Teams
+		}
+
 		// Handle max
 		if data.SafeOutputs.Mentions.Max != nil {
 			mentionsConfig["max"] = *data.SafeOutputs.Mentions.Max
@@ -563,6 +661,11 @@ func generateSafeOutputsConfig(data *WorkflowData) string {
 			dispatchWorkflowConfig["workflow_files"] = data.SafeOutputs.DispatchWorkflow.WorkflowFiles
 		}
 
+		// Include input schemas so the handler can validate agent-supplied inputs
+		if len(data.SafeOutputs.DispatchWorkflow.WorkflowInputs) > 0 {
+			dispatchWorkflowConfig["workflow_inputs"] = data.SafeOutputs.DispatchWorkflow.WorkflowInputs
+		}
+
 		// Include max count
 		dispatchWorkflowConfig["max"] = resolveMaxForConfig(data.SafeOutputs.DispatchWorkflow.Max, 1)
 
@@ -582,6 +685,9 @@ func generateSafeOutputsConfig(data *WorkflowData) string {
 		}
 	}
 
+	// Re-emit fields this version does not know about, e.g. from a newer schema
+	addPassthroughSafeOutputFields(safeOutputsConfig, data.SafeOutputs.Passthrough)
+
 	configJSON, _ := json.Marshal(safeOutputsConfig)
 	safeOutputsConfigLog.Printf("Safe outputs config generation complete: %d tool types configured", len(safeOutputsConfig))
 	return string(configJSON)
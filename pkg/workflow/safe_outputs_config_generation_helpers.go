@@ -131,10 +131,10 @@ func generateAssignToAgentConfig(max *string, defaultMax int, defaultAgent strin
 	return config
 }
 
-// generatePullRequestConfig creates a config with max, allowed_labels, allow_empty, auto_merge, and expires
-func generatePullRequestConfig(max *string, defaultMax int, allowedLabels []string, allowEmpty *string, autoMerge *string, expires int) map[string]any {
-	safeOutputsConfigGenLog.Printf("Generating pull request config: max=%v, allowEmpty=%v, autoMerge=%v, expires=%d, labels_count=%d",
-		max, allowEmpty, autoMerge, expires, len(allowedLabels))
+// generatePullRequestConfig creates a config with max, allowed_labels, allow_empty, auto_merge, expires, draft, and reviewers
+func generatePullRequestConfig(max *string, defaultMax int, allowedLabels []string, allowEmpty *string, autoMerge *string, expires int, draft *string, reviewers []string) map[string]any {
+	safeOutputsConfigGenLog.Printf("Generating pull request config: max=%v, allowEmpty=%v, autoMerge=%v, expires=%d, labels_count=%d, draft=%v, reviewers_count=%d",
+		max, allowEmpty, autoMerge, expires, len(allowedLabels), draft, len(reviewers))
 	config := generateMaxConfig(max, defaultMax)
 	if len(allowedLabels) > 0 {
 		config["allowed_labels"] = allowedLabels
@@ -151,6 +151,13 @@ func generatePullRequestConfig(max *string, defaultMax int, allowedLabels []stri
 	if expires > 0 {
 		config["expires"] = expires
 	}
+	// Pass draft flag like the handler config does: literal booleans as booleans, expressions
+	// as strings that GitHub Actions evaluates at runtime
+	(&handlerConfigBuilder{config: config}).AddTemplatableBool("draft", draft)
+	// Pass reviewers (users and org/team slugs) requested on the created pull request
+	if len(reviewers) > 0 {
+		config["reviewers"] = reviewers
+	}
 	return config
 }
 
@@ -163,6 +170,27 @@ func generateHideCommentConfig(max *string, defaultMax int, allowedReasons []str
 	return config
 }
 
+// generateAddReactionConfig creates a config with max and optional allowed_reactions
+func generateAddReactionConfig(max *string, defaultMax int, allowedReactions []string) map[string]any {
+	config := generateMaxConfig(max, defaultMax)
+	if len(allowedReactions) > 0 {
+		config["allowed_reactions"] = allowedReactions
+	}
+	return config
+}
+
+// generatePushFilesConfig creates a config with max, allowed_branches, and optional allowed_paths
+func generatePushFilesConfig(max *string, defaultMax int, allowedBranches, allowedPaths []string) map[string]any {
+	config := generateMaxConfig(max, defaultMax)
+	if len(allowedBranches) > 0 {
+		config["allowed_branches"] = allowedBranches
+	}
+	if len(allowedPaths) > 0 {
+		config["allowed_paths"] = allowedPaths
+	}
+	return config
+}
+
 // generateTargetConfigWithRepos creates a config with target, target-repo, allowed_repos, and optional fields.
 // Note on naming conventions:
 // - "target-repo" uses hyphen to match frontmatter YAML format (key in config.json)
@@ -87,6 +87,30 @@ func TestGenerateSafeOutputsConfigMissingToolWithIssue(t *testing.T) {
 	assert.InDelta(t, float64(1), createMissingIssue["max"], 0.0001, "max for issue creation should be 1")
 }
 
+// TestGenerateSafeOutputsConfigMissingToolMaxIssues tests that max-issues sets the
+// create_missing_tool_issue max.
+func TestGenerateSafeOutputsConfigMissingToolMaxIssues(t *testing.T) {
+	maxIssues := 2
+	data := &WorkflowData{
+		SafeOutputs: &SafeOutputsConfig{
+			MissingTool: &MissingToolConfig{
+				BaseSafeOutputConfig: BaseSafeOutputConfig{Max: strPtr("3")},
+				CreateIssue:          true,
+				MaxIssues:            &maxIssues,
+			},
+		},
+	}
+
+	result := generateSafeOutputsConfig(data)
+
+	var parsed map[string]any
+	require.NoError(t, json.Unmarshal([]byte(result), &parsed), "Result must be valid JSON")
+
+	createMissingIssue, ok := parsed["create_missing_tool_issue"].(map[string]any)
+	require.True(t, ok, "Expected create_missing_tool_issue key in config")
+	assert.InDelta(t, float64(2), createMissingIssue["max"], 0.0001, "max for issue creation should follow max-issues")
+}
+
 // TestGenerateSafeOutputsConfigMentions tests the mentions configuration generation.
 func TestGenerateSafeOutputsConfigMentions(t *testing.T) {
 	enabled := true
@@ -117,6 +141,112 @@ func TestGenerateSafeOutputsConfigMentions(t *testing.T) {
 	assert.InDelta(t, float64(5), mentions["max"], 0.0001, "max should be 5")
 }
 
+// TestGenerateSafeOutputsConfigAddCommentAllowedDomains tests that the add_comment config
+// includes the link allowed domains only when they are configured.
+func TestGenerateSafeOutputsConfigAddCommentAllowedDomains(t *testing.T) {
+	data := &WorkflowData{
+		SafeOutputs: &SafeOutputsConfig{
+			AddComments: &AddCommentsConfig{AllowedDomains: []string{"docs.example.com", "*.example.org"}},
+		},
+	}
+
+	var parsed map[string]any
+	require.NoError(t, json.Unmarshal([]byte(generateSafeOutputsConfig(data)), &parsed), "Result must be valid JSON")
+	commentConfig, ok := parsed["add_comment"].(map[string]any)
+	require.True(t, ok, "Expected add_comment key in config")
+	assert.Equal(t, []any{"docs.example.com", "*.example.org"}, commentConfig["allowed_domains"], "allowed_domains should match")
+
+	data.SafeOutputs.AddComments.AllowedDomains = nil
+	require.NoError(t, json.Unmarshal([]byte(generateSafeOutputsConfig(data)), &parsed), "Result must be valid JSON")
+	commentConfig, ok = parsed["add_comment"].(map[string]any)
+	require.True(t, ok, "Expected add_comment key in config")
+	assert.NotContains(t, commentConfig, "allowed_domains", "allowed_domains should be omitted when not configured")
+}
+
+// TestGenerateSafeOutputsConfigCreatePullRequestDraftAndReviewers tests that the create_pull_request
+// config includes the draft flag and reviewers.
+func TestGenerateSafeOutputsConfigCreatePullRequestDraftAndReviewers(t *testing.T) {
+	data := &WorkflowData{
+		SafeOutputs: &SafeOutputsConfig{
+			CreatePullRequests: &CreatePullRequestsConfig{
+				Draft:     strPtr("true"),
+				Reviewers: []string{"octocat", "octo-org/maintainers"},
+			},
+		},
+	}
+
+	result := generateSafeOutputsConfig(data)
+	require.NotEmpty(t, result, "Expected non-empty config")
+
+	var parsed map[string]any
+	require.NoError(t, json.Unmarshal([]byte(result), &parsed), "Result must be valid JSON")
+
+	prConfig, ok := parsed["create_pull_request"].(map[string]any)
+	require.True(t, ok, "Expected create_pull_request key in config")
+	assert.Equal(t, true, prConfig["draft"], "draft should be true")
+	assert.Equal(t, []any{"octocat", "octo-org/maintainers"}, prConfig["reviewers"], "reviewers should match")
+}
+
+// TestGenerateSafeOutputsConfigCreatePullRequestDraftExpression tests that expression draft values
+// are passed through as strings for GitHub Actions to evaluate.
+func TestGenerateSafeOutputsConfigCreatePullRequestDraftExpression(t *testing.T) {
+	data := &WorkflowData{
+		SafeOutputs: &SafeOutputsConfig{
+			CreatePullRequests: &CreatePullRequestsConfig{
+				Draft: strPtr("${{ inputs.draft }}"),
+			},
+		},
+	}
+
+	result := generateSafeOutputsConfig(data)
+
+	var parsed map[string]any
+	require.NoError(t, json.Unmarshal([]byte(result), &parsed), "Result must be valid JSON")
+
+	prConfig, ok := parsed["create_pull_request"].(map[string]any)
+	require.True(t, ok, "Expected create_pull_request key in config")
+	assert.Equal(t, "${{ inputs.draft }}", prConfig["draft"], "expression draft should be passed through")
+	assert.NotContains(t, prConfig, "reviewers", "reviewers should be omitted when empty")
+}
+
+// TestGenerateSafeOutputsConfigLabels tests that add_labels and remove_labels configs include
+// the allowed and blocked label lists along with target settings.
+func TestGenerateSafeOutputsConfigLabels(t *testing.T) {
+	data := &WorkflowData{
+		SafeOutputs: &SafeOutputsConfig{
+			AddLabels: &AddLabelsConfig{
+				BaseSafeOutputConfig: BaseSafeOutputConfig{Max: strPtr("5")},
+				Allowed:              []string{"bug", "enhancement"},
+				Blocked:              []string{"~*"},
+			},
+			RemoveLabels: &RemoveLabelsConfig{
+				SafeOutputTargetConfig: SafeOutputTargetConfig{Target: "*"},
+				Allowed:                []string{"needs-triage"},
+				Blocked:                []string{"security"},
+			},
+		},
+	}
+
+	result := generateSafeOutputsConfig(data)
+	require.NotEmpty(t, result, "Expected non-empty config")
+
+	var parsed map[string]any
+	require.NoError(t, json.Unmarshal([]byte(result), &parsed), "Result must be valid JSON")
+
+	addLabels, ok := parsed["add_labels"].(map[string]any)
+	require.True(t, ok, "Expected add_labels key in config")
+	assert.InDelta(t, float64(5), addLabels["max"], 0.0001, "add_labels max should be 5")
+	assert.Equal(t, []any{"bug", "enhancement"}, addLabels["allowed"], "add_labels allowed should match")
+	assert.Equal(t, []any{"~*"}, addLabels["blocked"], "add_labels blocked should match")
+
+	removeLabels, ok := parsed["remove_labels"].(map[string]any)
+	require.True(t, ok, "Expected remove_labels key in config")
+	assert.InDelta(t, float64(3), removeLabels["max"], 0.0001, "remove_labels max should default to 3")
+	assert.Equal(t, "*", removeLabels["target"], "remove_labels target should match")
+	assert.Equal(t, []any{"needs-triage"}, removeLabels["allowed"], "remove_labels allowed should match")
+	assert.Equal(t, []any{"security"}, removeLabels["blocked"], "remove_labels blocked should match")
+}
+
 // TestPopulateDispatchWorkflowFilesNoSafeOutputs tests that the function handles nil SafeOutputs gracefully.
 func TestPopulateDispatchWorkflowFilesNoSafeOutputs(t *testing.T) {
 	data := &WorkflowData{SafeOutputs: nil}
@@ -166,6 +296,50 @@ func TestPopulateDispatchWorkflowFilesFindsLockFile(t *testing.T) {
 		"Should prefer .lock.yml over .yml")
 }
 
+// TestPopulateDispatchWorkflowInputs tests that input schemas are derived from the target's
+// workflow_dispatch inputs unless declared, and emitted into the generated config.
+func TestPopulateDispatchWorkflowInputs(t *testing.T) {
+	tmpDir := t.TempDir()
+	workflowsDir := filepath.Join(tmpDir, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(workflowsDir, 0755), "Failed to create workflows dir")
+
+	deploy := `on:
+  workflow_dispatch:
+    inputs:
+      environment:
+        description: Target environment
+        type: choice
+        required: true
+        options: [staging, production]
+`
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "deploy.lock.yml"), []byte(deploy), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "notify.yml"), []byte(deploy), 0644))
+
+	data := &WorkflowData{
+		SafeOutputs: &SafeOutputsConfig{
+			DispatchWorkflow: &DispatchWorkflowConfig{
+				Workflows: []string{"deploy", "notify"},
+				Inputs: map[string]map[string]any{
+					"notify": {"environment": map[string]any{"type": "string", "description": "Ignored at runtime"}},
+				},
+			},
+		},
+	}
+
+	populateDispatchWorkflowFiles(data, filepath.Join(tmpDir, ".github", "aw", "test.md"))
+
+	inputs := data.SafeOutputs.DispatchWorkflow.WorkflowInputs
+	assert.Equal(t, map[string]any{
+		"environment": map[string]any{"type": "choice", "required": true, "options": []any{"staging", "production"}},
+	}, inputs["deploy"], "Schema should be derived from the target without descriptions")
+	assert.Equal(t, map[string]any{
+		"environment": map[string]any{"type": "string"},
+	}, inputs["notify"], "Declared schema should take precedence over the target")
+
+	config := generateSafeOutputsConfig(data)
+	assert.Contains(t, config, `"workflow_inputs":{"deploy":{"environment":{"options":["staging","production"],"required":true,"type":"choice"}}`, "Config should include the input schemas")
+}
+
 // TestGenerateCustomJobToolDefinition tests that generateCustomJobToolDefinition produces
 // valid MCP tool definitions from SafeJobConfig input definitions.
 func TestGenerateCustomJobToolDefinition(t *testing.T) {
@@ -243,6 +417,27 @@ func TestGenerateCustomJobToolDefinition(t *testing.T) {
 				assert.Equal(t, "number", countProp["type"], "count type should be number")
 			},
 		},
+		{
+			name:    "integer input with bounds",
+			jobName: "int_job",
+			jobConfig: &SafeJobConfig{
+				Inputs: map[string]*InputDefinition{
+					"retries": {
+						Type:    "integer",
+						Minimum: float64Ptr(0.0),
+						Maximum: float64Ptr(5.0),
+					},
+				},
+			},
+			check: func(t *testing.T, result map[string]any) {
+				schema := result["inputSchema"].(map[string]any)
+				props := schema["properties"].(map[string]any)
+				retriesProp := props["retries"].(map[string]any)
+				assert.Equal(t, "integer", retriesProp["type"], "retries type should be integer")
+				assert.InDelta(t, 0.0, retriesProp["minimum"], 0, "minimum should be set")
+				assert.InDelta(t, 5.0, retriesProp["maximum"], 0, "maximum should be set")
+			},
+		},
 		{
 			name:    "choice input with enum",
 			jobName: "choice_job",
@@ -290,6 +485,39 @@ func TestGenerateCustomJobToolDefinition(t *testing.T) {
 				assert.Contains(t, desc.(string), "nodesc_job", "default description should include job name")
 			},
 		},
+		{
+			name:    "schema description and property titles",
+			jobName: "deploy",
+			jobConfig: &SafeJobConfig{
+				Description: "Deploy the application",
+				Inputs: map[string]*InputDefinition{
+					"dry_run":    {Type: "boolean"},
+					"target-env": {Type: "string"},
+					"version":    {Type: "string"},
+				},
+			},
+			check: func(t *testing.T, result map[string]any) {
+				schema := result["inputSchema"].(map[string]any)
+				assert.Equal(t, "Deploy the application", schema["description"], "schema should carry the job description")
+				props := schema["properties"].(map[string]any)
+				assert.Equal(t, "Dry run", props["dry_run"].(map[string]any)["title"], "title should be derived from the snake_case key")
+				assert.Equal(t, "Target env", props["target-env"].(map[string]any)["title"], "title should be derived from the kebab-case key")
+				assert.Equal(t, "Version", props["version"].(map[string]any)["title"], "title should capitalize the key")
+			},
+		},
+		{
+			name:    "no job description leaves schema undescribed",
+			jobName: "quiet_job",
+			jobConfig: &SafeJobConfig{
+				Inputs: map[string]*InputDefinition{
+					"x": {Type: "string"},
+				},
+			},
+			check: func(t *testing.T, result map[string]any) {
+				schema := result["inputSchema"].(map[string]any)
+				assert.NotContains(t, schema, "description", "schema description should only come from the job description")
+			},
+		},
 	}
 
 	for _, tt := range tests {
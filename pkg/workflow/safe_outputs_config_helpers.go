@@ -47,6 +47,8 @@ var safeOutputFieldMapping = map[string]string{
 	"CreateProjectStatusUpdates":      "create_project_status_update",
 	"LinkSubIssue":                    "link_sub_issue",
 	"HideComment":                     "hide_comment",
+	"AddReaction":                     "add_reaction",
+	"PushFiles":                       "push_files",
 	"DispatchWorkflow":                "dispatch_workflow",
 	"MissingTool":                     "missing_tool",
 	"NoOp":                            "noop",
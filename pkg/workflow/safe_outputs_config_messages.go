@@ -3,6 +3,7 @@ package workflow
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/github/gh-aw/pkg/logger"
 )
@@ -57,7 +58,7 @@ func parseMessagesConfig(messagesMap map[string]any) *SafeOutputMessagesConfig {
 // Mentions can be:
 // - false: always escapes mentions
 // - true: always allows mentions (error in strict mode)
-// - object: detailed configuration with allow-team-members, allow-context, allowed, max
+// - object: detailed configuration with allow-team-members, allow-context, allowed, denied, teams, max
 func parseMentionsConfig(mentions any) *MentionsConfig {
 	safeOutputMessagesLog.Printf("Parsing mentions configuration: type=%T", mentions)
 	config := &MentionsConfig{}
@@ -104,6 +105,38 @@ func parseMentionsConfig(mentions any) *MentionsConfig {
 			}
 		}
 
+		// Parse denied list
+		if denied, exists := mentionsMap["denied"]; exists {
+			if deniedArray, ok := denied.([]any); ok {
+				var deniedStrings []string
+				for _, item := range deniedArray {
+					if str, ok := item.(string); ok {
+						// Normalize username by removing '@' prefix if present
+						deniedStrings = append(deniedStrings, strings.TrimPrefix(str, "@"))
+					}
+				}
+				config.Denied = deniedStrings
+			}
+		}
+
+		// Parse teams list
+		if teams, exists := mentionsMap["teams"]; exists {
+			if teamsArray, ok := teams.([]any); ok {
+				var teamStrings []string
+				for _, item := range teamsArray {
+					if str, ok := item.(string); ok {
+						// Normalize team slug by removing '@' prefix if present
+						normalized := str
+						if len(str) > 0 && str[0] == '@' {
+							normalized = str[1:]
+						}
+						teamStrings = append(teamStrings, normalized)
+					}
+				}
+				config.Teams = teamStrings
+			}
+		}
+
 		// Parse max
 		if maxVal, exists := mentionsMap["max"]; exists {
 			switch v := maxVal.(type) {
@@ -137,6 +170,25 @@ func parseMentionsConfig(mentions any) *MentionsConfig {
 	return config
 }
 
+// validateMentionsConfig rejects logins listed in both safe-outputs.mentions.allowed and
+// safe-outputs.mentions.denied, since it is unclear whether they should be mentioned
+func validateMentionsConfig(config *SafeOutputsConfig) error {
+	if config == nil || config.Mentions == nil || len(config.Mentions.Denied) == 0 {
+		return nil
+	}
+
+	denied := make(map[string]bool, len(config.Mentions.Denied))
+	for _, login := range config.Mentions.Denied {
+		denied[strings.ToLower(login)] = true
+	}
+	for _, login := range config.Mentions.Allowed {
+		if denied[strings.ToLower(login)] {
+			return fmt.Errorf("safe-outputs.mentions: %q is listed in both 'allowed' and 'denied'. Remove it from one of the lists", login)
+		}
+	}
+	return nil
+}
+
 // serializeMessagesConfig converts SafeOutputMessagesConfig to JSON for passing as environment variable
 func serializeMessagesConfig(messages *SafeOutputMessagesConfig) (string, error) {
 	if messages == nil {
@@ -55,15 +55,23 @@ func isEcosystemIdentifier(domain string) bool {
 var domainPattern = regexp.MustCompile(`^(\*\.)?[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
 
 // validateSafeOutputsAllowedDomains validates the allowed-domains configuration in safe-outputs
+// and the link allowed-domains of add-comment
 func (c *Compiler) validateSafeOutputsAllowedDomains(config *SafeOutputsConfig) error {
-	if config == nil || len(config.AllowedDomains) == 0 {
+	return validateSafeOutputsDomains(config, c.failFast)
+}
+
+// validateSafeOutputsDomains validates the safe-outputs domain patterns, stopping at the first
+// invalid pattern when failFast is set
+func validateSafeOutputsDomains(config *SafeOutputsConfig, failFast bool) error {
+	if config == nil {
 		return nil
 	}
 
-	safeOutputsDomainsValidationLog.Printf("Validating %d allowed domains", len(config.AllowedDomains))
-
-	collector := NewErrorCollector(c.failFast)
+	collector := NewErrorCollector(failFast)
 
+	if len(config.AllowedDomains) > 0 {
+		safeOutputsDomainsValidationLog.Printf("Validating %d allowed domains", len(config.AllowedDomains))
+	}
 	for i, domain := range config.AllowedDomains {
 		if err := validateDomainPattern(domain); err != nil {
 			wrappedErr := fmt.Errorf("safe-outputs.allowed-domains[%d]: %w", i, err)
@@ -73,9 +81,35 @@ func (c *Compiler) validateSafeOutputsAllowedDomains(config *SafeOutputsConfig)
 		}
 	}
 
+	if config.AddComments != nil && len(config.AddComments.AllowedDomains) > 0 {
+		safeOutputsDomainsValidationLog.Printf("Validating %d add-comment allowed domains", len(config.AddComments.AllowedDomains))
+		for i, domain := range config.AddComments.AllowedDomains {
+			if err := validateLinkDomainPattern(domain); err != nil {
+				wrappedErr := fmt.Errorf("safe-outputs.add-comment.allowed-domains[%d]: %w", i, err)
+				if returnErr := collector.Add(wrappedErr); returnErr != nil {
+					return returnErr // Fail-fast mode
+				}
+			}
+		}
+	}
+
 	return collector.Error()
 }
 
+// validateLinkDomainPattern validates a domain pattern that links are matched against by host name,
+// so unlike network domains it cannot carry a protocol prefix
+func validateLinkDomainPattern(domain string) error {
+	if strings.Contains(domain, "://") {
+		return NewValidationError(
+			"domain",
+			domain,
+			"link domain pattern cannot include a protocol",
+			"Remove the protocol prefix. Examples:\n  - 'docs.example.com'\n  - '*.example.com'",
+		)
+	}
+	return validateDomainPattern(domain)
+}
+
 // validateDomainPattern validates a single domain pattern
 func validateDomainPattern(domain string) error {
 	// Check for empty domain
@@ -180,6 +180,29 @@ func TestValidateSafeOutputsAllowedDomains(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "valid - add-comment link domains",
+			config: &SafeOutputsConfig{
+				AddComments: &AddCommentsConfig{AllowedDomains: []string{"docs.example.com", "*.example.org"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid - add-comment link domain",
+			config: &SafeOutputsConfig{
+				AddComments: &AddCommentsConfig{AllowedDomains: []string{"docs.example.com", "not a domain"}},
+			},
+			wantErr: true,
+			errMsg:  "safe-outputs.add-comment.allowed-domains[1]",
+		},
+		{
+			name: "invalid - add-comment link domain with protocol",
+			config: &SafeOutputsConfig{
+				AddComments: &AddCommentsConfig{AllowedDomains: []string{"https://docs.example.com"}},
+			},
+			wantErr: true,
+			errMsg:  "cannot include a protocol",
+		},
 	}
 
 	for _, tt := range tests {
@@ -203,8 +203,8 @@ safe-outputs:
 	assert.Equal(t, "[main] ", workflowData.SafeOutputs.CreateIssues.TitlePrefix, "Main workflow's title-prefix should override imported")
 }
 
-// TestSafeOutputsImportConflictBetweenImports tests that a conflict error is returned when the same safe-output type is defined in multiple imported workflows
-func TestSafeOutputsImportConflictBetweenImports(t *testing.T) {
+// TestSafeOutputsImportMergeBetweenImports tests that the same safe-output type defined in multiple imported workflows is merged, with the first import taking precedence
+func TestSafeOutputsImportMergeBetweenImports(t *testing.T) {
 	compiler := NewCompilerWithVersion("1.0.0")
 
 	// Create a temporary directory for test files
@@ -218,6 +218,7 @@ func TestSafeOutputsImportConflictBetweenImports(t *testing.T) {
 safe-outputs:
   create-issue:
     title-prefix: "[shared1] "
+    labels: [automation]
 ---
 
 # Shared Create Issue 1
@@ -227,11 +228,12 @@ safe-outputs:
 	err = os.WriteFile(sharedFile1, []byte(sharedWorkflow1), 0644)
 	require.NoError(t, err, "Failed to write shared file 1")
 
-	// Create second shared workflow with create-issue (conflict)
+	// Create second shared workflow with create-issue (conflicting title-prefix)
 	sharedWorkflow2 := `---
 safe-outputs:
   create-issue:
     title-prefix: "[shared2] "
+    labels: [automation, triage]
 ---
 
 # Shared Create Issue 2
@@ -241,7 +243,7 @@ safe-outputs:
 	err = os.WriteFile(sharedFile2, []byte(sharedWorkflow2), 0644)
 	require.NoError(t, err, "Failed to write shared file 2")
 
-	// Create main workflow that imports both (conflict between imports)
+	// Create main workflow that imports both
 	mainWorkflow := `---
 on: issues
 permissions:
@@ -251,7 +253,7 @@ imports:
   - ./shared-create-issue2.md
 ---
 
-# Main Workflow with Import Conflict
+# Main Workflow with Overlapping Imports
 `
 
 	mainFile := filepath.Join(workflowsDir, "main.md")
@@ -265,11 +267,68 @@ imports:
 	require.NoError(t, err, "Failed to change directory")
 	defer func() { _ = os.Chdir(oldDir) }()
 
-	// Parse the main workflow - should fail with conflict error
-	_, err = compiler.ParseWorkflowFile("main.md")
-	require.Error(t, err, "Expected conflict error")
-	assert.Contains(t, err.Error(), "safe-outputs conflict")
-	assert.Contains(t, err.Error(), "create-issue")
+	workflowData, err := compiler.ParseWorkflowFile("main.md")
+	require.NoError(t, err, "Overlapping imports should be merged")
+
+	require.NotNil(t, workflowData.SafeOutputs, "SafeOutputs should be present")
+	require.NotNil(t, workflowData.SafeOutputs.CreateIssues, "CreateIssues should be present")
+	assert.Equal(t, "[shared1] ", workflowData.SafeOutputs.CreateIssues.TitlePrefix, "First import's title-prefix should win")
+	assert.Equal(t, []string{"automation", "triage"}, workflowData.SafeOutputs.CreateIssues.Labels, "Labels should be combined without duplicates")
+	assert.Equal(t, 1, compiler.GetWarningCount(), "Conflicting title-prefix should be reported")
+}
+
+// TestSafeOutputsImportMergeWithMain tests that an imported safe-output type is merged into the main workflow's definition of the same type
+func TestSafeOutputsImportMergeWithMain(t *testing.T) {
+	compiler := NewCompilerWithVersion("1.0.0")
+
+	tmpDir := t.TempDir()
+	workflowsDir := filepath.Join(tmpDir, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(workflowsDir, 0755), "Failed to create workflows directory")
+
+	sharedWorkflow := `---
+safe-outputs:
+  add-labels:
+    allowed: [bug, enhancement]
+    max: 5
+---
+
+# Shared Label Policy
+`
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "shared-labels.md"), []byte(sharedWorkflow), 0644), "Failed to write shared file")
+
+	mainWorkflow := `---
+on: issues
+permissions:
+  contents: read
+imports:
+  - ./shared-labels.md
+safe-outputs:
+  add-labels:
+    allowed: [triage]
+    blocked: ["~*"]
+    max: 2
+---
+
+# Main Workflow
+`
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "main.md"), []byte(mainWorkflow), 0644), "Failed to write main file")
+
+	oldDir, err := os.Getwd()
+	require.NoError(t, err, "Failed to get current directory")
+	require.NoError(t, os.Chdir(workflowsDir), "Failed to change directory")
+	defer func() { _ = os.Chdir(oldDir) }()
+
+	workflowData, err := compiler.ParseWorkflowFile("main.md")
+	require.NoError(t, err, "Import should be merged into the main definition")
+
+	require.NotNil(t, workflowData.SafeOutputs, "SafeOutputs should be present")
+	addLabels := workflowData.SafeOutputs.AddLabels
+	require.NotNil(t, addLabels, "AddLabels should be present")
+	assert.Equal(t, []string{"triage", "bug", "enhancement"}, addLabels.Allowed, "Allowed labels should be combined, main first")
+	assert.Equal(t, []string{"~*"}, addLabels.Blocked, "Main-only fields should be kept")
+	require.NotNil(t, addLabels.Max, "Max should be set")
+	assert.Equal(t, "2", *addLabels.Max, "Main workflow's max should take precedence")
+	assert.Equal(t, 1, compiler.GetWarningCount(), "Conflicting max should be reported")
 }
 
 // TestSafeOutputsImportNoConflictDifferentTypes tests that importing different safe-output types does not cause a conflict
@@ -452,14 +511,14 @@ func TestMergeSafeOutputsUnit(t *testing.T) {
 			expectedTypes: []string{"create-issue"},
 		},
 		{
-			name:      "conflict: same type in multiple imports",
+			name:      "merge: same type in multiple imports",
 			topConfig: nil,
 			importedJSON: []string{
 				`{"create-issue":{"title-prefix":"[import1] "}}`,
 				`{"create-issue":{"title-prefix":"[import2] "}}`,
 			},
-			expectError:   true,
-			errorContains: "safe-outputs conflict",
+			expectError:   false,
+			expectedTypes: []string{"create-issue"},
 		},
 		{
 			name: "no conflict: different types",
@@ -1376,13 +1435,12 @@ func TestMergeSafeOutputsErrorPropagation(t *testing.T) {
 			expectError: false, // Malformed JSON is skipped, not an error
 		},
 		{
-			name: "conflicting safe-output types should error",
+			name: "same safe-output type in multiple imports should merge",
 			importedJSON: []string{
 				`{"create-issue":{"title-prefix":"[import1] "}}`,
 				`{"create-issue":{"title-prefix":"[import2] "}}`,
 			},
-			expectError:   true,
-			errorContains: "safe-outputs conflict",
+			expectError: false, // Conflicting scalars are reported as warnings, not errors
 		},
 	}
 
@@ -1823,3 +1881,60 @@ safe-outputs:
 	assert.Equal(t, "Shared started", workflowData.SafeOutputs.Messages.RunStarted, "RunStarted should come from shared")
 	assert.Equal(t, "Shared failure", workflowData.SafeOutputs.Messages.RunFailure, "RunFailure should come from shared")
 }
+
+// TestMergeSafeOutputConfigMaps tests field-level merging of two definitions of a safe-output type
+func TestMergeSafeOutputConfigMaps(t *testing.T) {
+	preferred := map[string]any{
+		"labels": []any{"bug"},
+		"max":    float64(1),
+		"footer": map[string]any{"text": "main"},
+	}
+	other := map[string]any{
+		"labels":       []any{"bug", "triage"},
+		"max":          float64(3),
+		"title-prefix": "[bot] ",
+		"footer":       map[string]any{"text": "import", "link": true},
+	}
+
+	merged, conflicts := mergeSafeOutputConfigMaps("safe-outputs.create-issue", preferred, other)
+
+	assert.Equal(t, map[string]any{
+		"labels":       []any{"bug", "triage"},
+		"max":          float64(1),
+		"title-prefix": "[bot] ",
+		"footer":       map[string]any{"text": "main", "link": true},
+	}, merged, "Lists should be combined, nested objects merged, and preferred scalars kept")
+	assert.Equal(t, []string{
+		`Conflicting values for safe-outputs.create-issue.footer.text in imported safe-outputs: using "main", ignoring "import"`,
+		"Conflicting values for safe-outputs.create-issue.max in imported safe-outputs: using 1, ignoring 3",
+	}, conflicts, "Each conflicting scalar should be reported with its path")
+	assert.Equal(t, []any{"bug"}, preferred["labels"], "Preferred map should not be modified")
+}
+
+// TestMergeImportedSafeOutputTypes tests that imported definitions are merged into the main workflow's definitions of the same types before parsing
+func TestMergeImportedSafeOutputTypes(t *testing.T) {
+	compiler := NewCompilerWithVersion("1.0.0")
+
+	frontmatter := map[string]any{
+		"on": "issues",
+		"safe-outputs": map[string]any{
+			"add-labels": map[string]any{"allowed": []any{"triage"}, "max": 2},
+		},
+	}
+	imported := []string{
+		`{"add-labels":{"allowed":["bug"]},"create-issue":{}}`,
+		`{"add-labels":{"allowed":["enhancement"],"max":5}}`,
+	}
+
+	merged := compiler.mergeImportedSafeOutputTypes(frontmatter, imported)
+
+	assert.Equal(t, map[string]any{
+		"add-labels": map[string]any{"allowed": []any{"triage", "bug", "enhancement"}, "max": 2},
+	}, merged["safe-outputs"], "Only types the main workflow defines should be merged, main first")
+	assert.Equal(t, "issues", merged["on"], "Other frontmatter should be kept")
+	assert.Equal(t, map[string]any{"allowed": []any{"triage"}, "max": 2}, frontmatter["safe-outputs"].(map[string]any)["add-labels"], "The original frontmatter should not be modified")
+	assert.Equal(t, 1, compiler.GetWarningCount(), "Conflicting max should be reported")
+
+	unchanged := compiler.mergeImportedSafeOutputTypes(frontmatter, []string{`{"create-issue":{}}`})
+	assert.Equal(t, frontmatter, unchanged, "Frontmatter should be unchanged when no types overlap")
+}
@@ -4,6 +4,7 @@ package workflow
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 )
 
@@ -98,6 +99,24 @@ func TestParseMentionsConfig_Object(t *testing.T) {
 				Allowed: []string{"pelikhan", "bot1", "user2"},
 			},
 		},
+		{
+			name: "teams list with @ prefix - should normalize",
+			input: map[string]any{
+				"teams": []any{"@octo-org/maintainers", "octo-org/triage"},
+			},
+			expected: &MentionsConfig{
+				Teams: []string{"octo-org/maintainers", "octo-org/triage"},
+			},
+		},
+		{
+			name: "denied list with @ prefix - should normalize",
+			input: map[string]any{
+				"denied": []any{"@dependabot", "renovate"},
+			},
+			expected: &MentionsConfig{
+				Denied: []string{"dependabot", "renovate"},
+			},
+		},
 		{
 			name: "max as float",
 			input: map[string]any{
@@ -155,6 +174,32 @@ func TestParseMentionsConfig_Object(t *testing.T) {
 				}
 			}
 
+			// Check Denied
+			if len(tt.expected.Denied) > 0 {
+				if len(result.Denied) != len(tt.expected.Denied) {
+					t.Errorf("Expected Denied length %d, got %d", len(tt.expected.Denied), len(result.Denied))
+				} else {
+					for i, expected := range tt.expected.Denied {
+						if result.Denied[i] != expected {
+							t.Errorf("Expected Denied[%d] to be %q, got %q", i, expected, result.Denied[i])
+						}
+					}
+				}
+			}
+
+			// Check Teams
+			if len(tt.expected.Teams) > 0 {
+				if len(result.Teams) != len(tt.expected.Teams) {
+					t.Errorf("Expected Teams length %d, got %d", len(tt.expected.Teams), len(result.Teams))
+				} else {
+					for i, expected := range tt.expected.Teams {
+						if result.Teams[i] != expected {
+							t.Errorf("Expected Teams[%d] to be %q, got %q", i, expected, result.Teams[i])
+						}
+					}
+				}
+			}
+
 			// Check Max
 			if tt.expected.Max != nil {
 				if result.Max == nil {
@@ -206,6 +251,24 @@ func TestGenerateSafeOutputsConfig_WithMentions(t *testing.T) {
 				"max":              20,
 			},
 		},
+		{
+			name: "mentions config with teams",
+			config: &MentionsConfig{
+				Teams: []string{"octo-org/maintainers"},
+			},
+			expected: map[string]any{
+				"teams": []string{"octo-org/maintainers"},
+			},
+		},
+		{
+			name: "mentions config with denied list",
+			config: &MentionsConfig{
+				Denied: []string{"dependabot"},
+			},
+			expected: map[string]any{
+				"denied": []string{"dependabot"},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -411,3 +474,41 @@ func TestExtractSafeOutputsConfig_WithMentions(t *testing.T) {
 func intPtr(i int) *int {
 	return &i
 }
+
+func TestValidateMentionsConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *SafeOutputsConfig
+		expectError bool
+	}{
+		{name: "nil config", config: nil},
+		{name: "no mentions", config: &SafeOutputsConfig{}},
+		{
+			name:   "disjoint lists",
+			config: &SafeOutputsConfig{Mentions: &MentionsConfig{Allowed: []string{"octocat"}, Denied: []string{"dependabot"}}},
+		},
+		{
+			name:        "login in both lists, different case",
+			config:      &SafeOutputsConfig{Mentions: &MentionsConfig{Allowed: []string{"Octocat"}, Denied: []string{"octocat"}}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMentionsConfig(tt.config)
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("Expected an error for overlapping lists, got nil")
+				}
+				if !strings.Contains(err.Error(), "both 'allowed' and 'denied'") {
+					t.Errorf("Expected error to name both lists, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+		})
+	}
+}
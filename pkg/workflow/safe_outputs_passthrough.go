@@ -0,0 +1,151 @@
+package workflow
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var safeOutputsPassthroughLog = logger.New("workflow:safe_outputs_passthrough")
+
+// This file carries safe-outputs fields that this version of the schema does not define
+// through compilation. Workflows written for a newer gh-aw keep their configuration when
+// recompiled by an older binary: the unknown fields are re-emitted in the generated safe
+// outputs config instead of being dropped. Strict mode still rejects them.
+
+// unknownSafeOutputFields returns the fields of a safe-outputs section that the schema does not
+// define, keyed by their dotted path relative to safe-outputs (e.g. "create-widget" or
+// "create-issue.new-field"). Fields inside lists are not carried through.
+func unknownSafeOutputFields(safeOutputs map[string]any) map[string]any {
+	unknown, err := parser.FindUnknownFrontmatterFields(map[string]any{"safe-outputs": safeOutputs})
+	if err != nil {
+		safeOutputsPassthroughLog.Printf("Unknown safe-outputs field check skipped: %v", err)
+		return nil
+	}
+
+	var fields map[string]any
+	for _, field := range unknown {
+		fieldPath, ok := strings.CutPrefix(field.Path, "safe-outputs.")
+		if !ok || strings.Contains(fieldPath, "[") {
+			continue
+		}
+		value, ok := lookupSafeOutputField(safeOutputs, strings.Split(fieldPath, "."))
+		if !ok {
+			continue
+		}
+		if fields == nil {
+			fields = make(map[string]any)
+		}
+		fields[fieldPath] = value
+	}
+	return fields
+}
+
+// lookupSafeOutputField returns the value at the given path of nested maps
+func lookupSafeOutputField(section map[string]any, path []string) (any, bool) {
+	value, ok := section[path[0]]
+	if !ok || len(path) == 1 {
+		return value, ok
+	}
+	nested, isMap := value.(map[string]any)
+	if !isMap {
+		return nil, false
+	}
+	return lookupSafeOutputField(nested, path[1:])
+}
+
+// withoutSafeOutputFields returns a copy of frontmatter whose safe-outputs section omits the
+// given fields. Only the maps along the removed paths are copied; the input is not modified.
+func withoutSafeOutputFields(frontmatter map[string]any, fields map[string]any) map[string]any {
+	safeOutputs, ok := frontmatter["safe-outputs"].(map[string]any)
+	if !ok || len(fields) == 0 {
+		return frontmatter
+	}
+
+	stripped := maps.Clone(safeOutputs)
+	for fieldPath := range fields {
+		removeSafeOutputField(stripped, strings.Split(fieldPath, "."))
+	}
+	result := maps.Clone(frontmatter)
+	result["safe-outputs"] = stripped
+	return result
+}
+
+// removeSafeOutputField deletes the value at the given path, cloning the nested maps it
+// descends into so maps shared with the original frontmatter are left untouched
+func removeSafeOutputField(section map[string]any, path []string) {
+	if len(path) == 1 {
+		delete(section, path[0])
+		return
+	}
+	nested, ok := section[path[0]].(map[string]any)
+	if !ok {
+		return
+	}
+	nested = maps.Clone(nested)
+	section[path[0]] = nested
+	removeSafeOutputField(nested, path[1:])
+}
+
+// passThroughUnknownSafeOutputFields warns about each safe-outputs field the schema does not
+// define and returns frontmatter without them, so schema validation accepts fields added by
+// newer versions of gh-aw. Strict mode, the default unless the workflow sets strict: false,
+// keeps the frontmatter unchanged so schema validation rejects the fields.
+func (c *Compiler) passThroughUnknownSafeOutputFields(frontmatter map[string]any, markdownPath string) map[string]any {
+	safeOutputs, ok := frontmatter["safe-outputs"].(map[string]any)
+	if !ok {
+		return frontmatter
+	}
+	// Same precedence as the strict mode checks: CLI flag > frontmatter > default (true)
+	strict := c.strictMode
+	if !strict {
+		strictValue, exists := frontmatter["strict"]
+		strictBool, isBool := strictValue.(bool)
+		strict = !exists || (isBool && strictBool)
+	}
+	if strict {
+		return frontmatter
+	}
+
+	fields := unknownSafeOutputFields(safeOutputs)
+	for _, fieldPath := range slices.Sorted(maps.Keys(fields)) {
+		message := fmt.Sprintf("unknown field 'safe-outputs.%s' is passed through to the safe outputs config unchanged (strict mode rejects it)", fieldPath)
+		fmt.Fprintln(os.Stderr, formatCompilerMessage(markdownPath, "warning", message))
+		c.IncrementWarningCount()
+	}
+	return withoutSafeOutputFields(frontmatter, fields)
+}
+
+// addPassthroughSafeOutputFields re-emits unknown safe-outputs fields in the generated config,
+// converting the dashes of their keys to underscores like the known fields. Known fields that
+// were generated take precedence, and fields of safe output types that produced no config
+// are dropped along with their type.
+func addPassthroughSafeOutputFields(config map[string]any, passthrough map[string]any) {
+	for _, fieldPath := range slices.Sorted(maps.Keys(passthrough)) {
+		path := strings.Split(strings.ReplaceAll(fieldPath, "-", "_"), ".")
+		target := config
+		for _, key := range path[:len(path)-1] {
+			nested, ok := target[key].(map[string]any)
+			if !ok {
+				target = nil
+				break
+			}
+			target = nested
+		}
+		key := path[len(path)-1]
+		if target == nil {
+			safeOutputsPassthroughLog.Printf("Dropping unknown safe-outputs field %s: its parent has no config", fieldPath)
+			continue
+		}
+		if _, exists := target[key]; exists {
+			continue
+		}
+		safeOutputsPassthroughLog.Printf("Passing through unknown safe-outputs field %s", fieldPath)
+		target[key] = passthrough[fieldPath]
+	}
+}
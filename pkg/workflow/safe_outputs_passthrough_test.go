@@ -0,0 +1,53 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSafeOutputsPassthrough(t *testing.T) {
+	frontmatter := map[string]any{
+		"on":     "push",
+		"strict": false,
+		"safe-outputs": map[string]any{
+			"create-issue":  map[string]any{"max": 2, "new-field": "hello"},
+			"create-widget": map[string]any{"color-mode": "blue"},
+		},
+	}
+
+	compiler := NewCompiler()
+	config := compiler.ExtractSafeOutputsConfig(frontmatter)
+	require.NotNil(t, config, "safe-outputs should be parsed")
+	assert.Equal(t, map[string]any{
+		"create-issue.new-field": "hello",
+		"create-widget":          map[string]any{"color-mode": "blue"},
+	}, config.Passthrough, "unknown fields should be kept")
+
+	var generated map[string]any
+	require.NoError(t, json.Unmarshal([]byte(generateSafeOutputsConfig(&WorkflowData{SafeOutputs: config})), &generated), "generated config should be JSON")
+	assert.Equal(t, map[string]any{"color-mode": "blue"}, generated["create_widget"], "unknown types should be re-emitted")
+	createIssue, ok := generated["create_issue"].(map[string]any)
+	require.True(t, ok, "create_issue should be generated")
+	assert.Equal(t, "hello", createIssue["new_field"], "unknown fields of known types should be re-emitted")
+	assert.InDelta(t, 2, createIssue["max"], 0, "known fields should be kept")
+
+	t.Run("non-strict validation copy", func(t *testing.T) {
+		stripped := compiler.passThroughUnknownSafeOutputFields(frontmatter, "test.md")
+		safeOutputs := stripped["safe-outputs"].(map[string]any)
+		assert.NotContains(t, safeOutputs, "create-widget", "unknown types should be removed for schema validation")
+		assert.NotContains(t, safeOutputs["create-issue"], "new-field", "unknown fields should be removed for schema validation")
+		assert.Contains(t, safeOutputs["create-issue"], "max", "known fields should be kept")
+		assert.Contains(t, frontmatter["safe-outputs"], "create-widget", "original frontmatter should not be modified")
+		assert.Contains(t, frontmatter["safe-outputs"].(map[string]any)["create-issue"], "new-field", "original frontmatter should not be modified")
+	})
+
+	t.Run("strict mode", func(t *testing.T) {
+		strict := map[string]any{"on": "push", "safe-outputs": frontmatter["safe-outputs"]}
+		assert.Equal(t, strict, compiler.passThroughUnknownSafeOutputFields(strict, "test.md"), "strict mode is the default and should keep unknown fields for schema validation")
+	})
+}
@@ -113,6 +113,15 @@ func ComputePermissionsForSafeOutputs(safeOutputs *SafeOutputsConfig) *Permissio
 			permissions.Merge(NewPermissionsContentsReadIssuesWriteDiscussionsWrite())
 		}
 	}
+	if safeOutputs.AddReaction != nil {
+		safeOutputsPermissionsLog.Print("Adding permissions for add-reaction")
+		// The triggering item may be an issue, pull request review comment, or discussion
+		permissions.Merge(NewPermissionsContentsReadIssuesWritePRWriteDiscussionsWrite())
+	}
+	if safeOutputs.PushFiles != nil {
+		safeOutputsPermissionsLog.Print("Adding permissions for push-files")
+		permissions.Merge(NewPermissionsContentsWrite())
+	}
 	if safeOutputs.DispatchWorkflow != nil {
 		safeOutputsPermissionsLog.Print("Adding permissions for dispatch-workflow")
 		permissions.Merge(NewPermissionsActionsWrite())
@@ -238,6 +247,10 @@ func SafeOutputsConfigFromKeys(keys []string) *SafeOutputsConfig {
 			config.UpdateRelease = &UpdateReleaseConfig{}
 		case "hide-comment":
 			config.HideComment = &HideCommentConfig{}
+		case "add-reaction":
+			config.AddReaction = &AddReactionConfig{}
+		case "push-files":
+			config.PushFiles = &PushFilesConfig{}
 		case "link-sub-issue":
 			config.LinkSubIssue = &LinkSubIssueConfig{}
 		case "update-project":
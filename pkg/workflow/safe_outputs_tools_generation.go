@@ -165,6 +165,12 @@ func generateFilteredToolsJSON(data *WorkflowData, markdownPath string) (string,
 	if data.SafeOutputs.HideComment != nil {
 		enabledTools["hide_comment"] = true
 	}
+	if data.SafeOutputs.AddReaction != nil {
+		enabledTools["add_reaction"] = true
+	}
+	if data.SafeOutputs.PushFiles != nil {
+		enabledTools["push_files"] = true
+	}
 	if data.SafeOutputs.UpdateProjects != nil {
 		enabledTools["update_project"] = true
 	}
@@ -275,12 +281,15 @@ func generateFilteredToolsJSON(data *WorkflowData, markdownPath string) (string,
 			// Store the file extension for runtime use
 			data.SafeOutputs.DispatchWorkflow.WorkflowFiles[workflowName] = extension
 
-			// Extract workflow_dispatch inputs
-			workflowInputs, err := extractWorkflowDispatchInputs(workflowPath)
-			if err != nil {
-				safeOutputsConfigLog.Printf("Warning: failed to extract inputs for workflow %s from %s: %v", workflowName, workflowPath, err)
-				// Continue with empty inputs
-				workflowInputs = make(map[string]any)
+			// Use the declared input schema, or extract the workflow_dispatch inputs
+			workflowInputs, declared := data.SafeOutputs.DispatchWorkflow.Inputs[workflowName]
+			if !declared {
+				workflowInputs, err = extractWorkflowDispatchInputs(workflowPath)
+				if err != nil {
+					safeOutputsConfigLog.Printf("Warning: failed to extract inputs for workflow %s from %s: %v", workflowName, workflowPath, err)
+					// Continue with empty inputs
+					workflowInputs = make(map[string]any)
+				}
 			}
 
 			// Generate tool schema
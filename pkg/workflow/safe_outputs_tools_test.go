@@ -334,6 +334,8 @@ func TestGetSafeOutputsToolsJSON(t *testing.T) {
 		"update_release",
 		"link_sub_issue",
 		"hide_comment",
+		"add_reaction",
+		"push_files",
 		"update_project",
 		"create_project",
 		"create_project_status_update",
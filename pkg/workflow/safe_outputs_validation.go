@@ -0,0 +1,188 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var safeOutputsValidationLog = logger.New("workflow:safe_outputs_validation")
+
+// maxLabelLength is the longest label name GitHub accepts
+const maxLabelLength = 50
+
+// safeOutputsConfigChecks are the structural checks of a safe-outputs configuration that need
+// nothing but the configuration itself. The compiler runs them on every workflow and
+// ValidateSafeOutputs runs them on their own.
+var safeOutputsConfigChecks = []struct {
+	name  string
+	check func(*SafeOutputsConfig) error
+}{
+	{"target fields", validateSafeOutputsTarget},
+	{"max limits", validateSafeOutputsMaxes},
+	{"labels", validateSafeOutputsLabels},
+	{"missing-tool max-issues", validateMissingToolMaxIssues},
+	{"create-pull-request reviewers", validateCreatePullRequestReviewers},
+	{"submit-pull-request-review events", validateSubmitPullRequestReviewEvents},
+	{"job inputs", validateSafeJobInputs},
+	{"mentions", validateMentionsConfig},
+	{"push-files allowed branches and paths", validatePushFilesConfig},
+	{"add-reaction allowed reactions", validateAddReactionTypes},
+}
+
+// ValidateSafeOutputs runs the structural checks of a safe-outputs configuration without
+// compiling the rest of the workflow: known keys, coherent max limits, well-formed labels,
+// mentions, domains, and dispatch-workflow names. It returns every problem found, or nil.
+// Whether dispatch-workflow targets exist and accept workflow_dispatch is checked at compile time.
+func ValidateSafeOutputs(config *SafeOutputsConfig) []error {
+	if config == nil {
+		return nil
+	}
+
+	var errs []error
+	if err := validateSafeOutputsKeys(config); err != nil {
+		errs = append(errs, err)
+	}
+	for _, c := range safeOutputsConfigChecks {
+		safeOutputsValidationLog.Printf("Validating safe-outputs %s", c.name)
+		if err := c.check(config); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := validateSafeOutputsDomains(config, false); err != nil {
+		errs = append(errs, err)
+	}
+	if config.DispatchWorkflow != nil {
+		if len(config.DispatchWorkflow.Workflows) == 0 {
+			errs = append(errs, errors.New("dispatch-workflow: must specify at least one workflow in the list"))
+		}
+		errs = append(errs, dispatchWorkflowNameErrors(config.DispatchWorkflow)...)
+	}
+
+	safeOutputsValidationLog.Printf("Safe-outputs validation found %d problem(s)", len(errs))
+	return errs
+}
+
+// validateSafeOutputsKeys refuses keys under safe-outputs that the schema does not define
+func validateSafeOutputsKeys(config *SafeOutputsConfig) error {
+	if len(config.source) == 0 {
+		return nil
+	}
+
+	knownKeys, err := parser.GetSafeOutputKeys()
+	if err != nil {
+		safeOutputsValidationLog.Printf("Failed to get safe-outputs keys: %v", err)
+		return nil
+	}
+
+	var messages []string
+	for _, key := range slices.Sorted(maps.Keys(config.source)) {
+		if slices.Contains(knownKeys, key) {
+			continue
+		}
+		message := fmt.Sprintf("unknown safe-outputs key '%s'", key)
+		if matches := parser.FindClosestMatches(key, knownKeys, 1); len(matches) > 0 {
+			message += fmt.Sprintf(" (did you mean '%s'?)", matches[0])
+		}
+		messages = append(messages, message)
+	}
+	if len(messages) > 0 {
+		return errors.New(strings.Join(messages, "\n"))
+	}
+	return nil
+}
+
+// forEachSafeOutputType calls fn with the frontmatter key and configuration of every enabled
+// safe output type (the configurations embedding BaseSafeOutputConfig)
+func forEachSafeOutputType(config *SafeOutputsConfig, fn func(key string, value reflect.Value)) {
+	baseType := reflect.TypeFor[BaseSafeOutputConfig]()
+	val := reflect.ValueOf(config).Elem()
+	for i := range val.NumField() {
+		field := val.Type().Field(i)
+		if field.Type.Kind() != reflect.Pointer || field.Type.Elem().Kind() != reflect.Struct {
+			continue
+		}
+		if base, ok := field.Type.Elem().FieldByName("BaseSafeOutputConfig"); !ok || !base.Anonymous || base.Type != baseType {
+			continue
+		}
+		if val.Field(i).IsNil() {
+			continue
+		}
+		key, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		fn(key, val.Field(i).Elem())
+	}
+}
+
+// validateSafeOutputsMaxes checks that every literal max limit is a positive count or -1
+// (unlimited). Expression limits are evaluated at runtime and are not checked.
+func validateSafeOutputsMaxes(config *SafeOutputsConfig) error {
+	if config == nil {
+		return nil
+	}
+
+	var messages []string
+	checkMax := func(name string, max *string) {
+		if max == nil || strings.HasPrefix(*max, "${{") {
+			return
+		}
+		if n := templatableIntValue(max); n == 0 || n < -1 {
+			messages = append(messages, fmt.Sprintf("%s: max must be a positive number or -1 for unlimited, got %s", name, *max))
+		}
+	}
+	forEachSafeOutputType(config, func(key string, value reflect.Value) {
+		base := value.FieldByName("BaseSafeOutputConfig").Interface().(BaseSafeOutputConfig)
+		checkMax(key, base.Max)
+	})
+	if config.MaxBotMentions != nil && !strings.HasPrefix(*config.MaxBotMentions, "${{") && templatableIntValue(config.MaxBotMentions) < 0 {
+		messages = append(messages, "max-bot-mentions must not be negative, got "+*config.MaxBotMentions)
+	}
+
+	if len(messages) > 0 {
+		return errors.New(strings.Join(messages, "\n"))
+	}
+	return nil
+}
+
+// validateSafeOutputsLabels checks the label lists of every safe output type (labels,
+// allowed-labels, required-labels, ...): labels must be non-empty, trimmed, and within
+// GitHub's length limit. Expressions are not checked.
+func validateSafeOutputsLabels(config *SafeOutputsConfig) error {
+	if config == nil {
+		return nil
+	}
+
+	var messages []string
+	forEachSafeOutputType(config, func(key string, value reflect.Value) {
+		for _, field := range reflect.VisibleFields(value.Type()) {
+			name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if !strings.HasSuffix(name, "labels") || field.Type != reflect.TypeFor[[]string]() {
+				continue
+			}
+			fieldValue, err := value.FieldByIndexErr(field.Index)
+			if err != nil {
+				continue
+			}
+			for i, label := range fieldValue.Interface().([]string) {
+				switch {
+				case strings.TrimSpace(label) == "":
+					messages = append(messages, fmt.Sprintf("%s.%s[%d] is empty", key, name, i))
+				case strings.TrimSpace(label) != label:
+					messages = append(messages, fmt.Sprintf("%s.%s[%d] has leading or trailing whitespace: %q", key, name, i, label))
+				case !strings.HasPrefix(label, "${{") && len([]rune(label)) > maxLabelLength:
+					messages = append(messages, fmt.Sprintf("%s.%s[%d] is longer than %d characters: %q", key, name, i, maxLabelLength, label))
+				}
+			}
+		}
+	})
+
+	if len(messages) > 0 {
+		return errors.New(strings.Join(messages, "\n"))
+	}
+	return nil
+}
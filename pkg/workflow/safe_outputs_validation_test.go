@@ -0,0 +1,98 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSafeOutputs(t *testing.T) {
+	tests := []struct {
+		name        string
+		safeOutputs map[string]any
+		wantErrors  []string
+	}{
+		{
+			name: "valid configuration",
+			safeOutputs: map[string]any{
+				"create-issue":      map[string]any{"max": 3, "labels": []any{"bug", "triage"}},
+				"add-labels":        map[string]any{"allowed": []any{"bug"}, "max": "${{ inputs.max }}"},
+				"dispatch-workflow": map[string]any{"workflows": []any{"ci-doctor"}},
+				"add-comment":       map[string]any{"max": -1},
+			},
+		},
+		{
+			name:        "unknown key",
+			safeOutputs: map[string]any{"create-isue": map[string]any{}},
+			wantErrors:  []string{"unknown safe-outputs key 'create-isue' (did you mean 'create-issue'?)"},
+		},
+		{
+			name: "incoherent max limits",
+			safeOutputs: map[string]any{
+				"create-issue": map[string]any{"max": 0},
+				"add-comment":  map[string]any{"max": -2},
+			},
+			wantErrors: []string{
+				"add-comment: max must be a positive number or -1 for unlimited, got -2",
+				"create-issue: max must be a positive number or -1 for unlimited, got 0",
+			},
+		},
+		{
+			name: "malformed labels",
+			safeOutputs: map[string]any{
+				"create-issue": map[string]any{"labels": []any{" bug", strings.Repeat("x", 51)}},
+			},
+			wantErrors: []string{
+				`create-issue.labels[0] has leading or trailing whitespace: " bug"`,
+				"create-issue.labels[1] is longer than 50 characters",
+			},
+		},
+		{
+			name: "malformed dispatch targets",
+			safeOutputs: map[string]any{
+				"dispatch-workflow": map[string]any{
+					"workflows": []any{"ci-doctor.md", "../other", "deploy"},
+					"inputs":    map[string]any{"cleanup": map[string]any{}},
+				},
+			},
+			wantErrors: []string{
+				"dispatch-workflow: invalid workflow name 'ci-doctor.md'",
+				"dispatch-workflow: invalid workflow name '../other'",
+				"dispatch-workflow: inputs declared for workflow 'cleanup', which is not in the workflows list",
+			},
+		},
+		{
+			name:        "invalid target",
+			safeOutputs: map[string]any{"close-issue": map[string]any{"target": "next"}},
+			wantErrors:  []string{"close-issue"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compiler := NewCompiler()
+			config := compiler.ExtractSafeOutputsConfig(map[string]any{"safe-outputs": tt.safeOutputs})
+			require.NotNil(t, config, "safe-outputs should be parsed")
+
+			errs := ValidateSafeOutputs(config)
+			if len(tt.wantErrors) == 0 {
+				assert.Empty(t, errs, "configuration should be valid")
+				return
+			}
+			var messages []string
+			for _, err := range errs {
+				messages = append(messages, err.Error())
+			}
+			joined := strings.Join(messages, "\n")
+			for _, want := range tt.wantErrors {
+				assert.Contains(t, joined, want, "errors should report the problem")
+			}
+		})
+	}
+
+	assert.Nil(t, ValidateSafeOutputs(nil), "a missing configuration should be valid")
+}
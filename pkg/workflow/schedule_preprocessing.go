@@ -3,6 +3,7 @@ package workflow
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/github/gh-aw/pkg/console"
@@ -94,6 +95,12 @@ func (c *Compiler) normalizeScheduleString(scheduleStr string, itemIndex int) (p
 		}
 		return "", "", fmt.Errorf("invalid cron expression '%s': must have exactly 5 fields (minute hour day-of-month month day-of-week)", parsedCron)
 	}
+	if err := parser.ValidateCronExpression(parsedCron); err != nil {
+		if itemIndex >= 0 {
+			return "", "", fmt.Errorf("invalid cron expression '%s' in item %d: %w", parsedCron, itemIndex, err)
+		}
+		return "", "", fmt.Errorf("invalid cron expression '%s': %w", parsedCron, err)
+	}
 
 	return parsedCron, original, nil
 }
@@ -302,38 +309,34 @@ func (c *Compiler) preprocessScheduleFields(frontmatter map[string]any, markdown
 	return nil
 }
 
+// triggerKeyPatterns match the line declaring the "on" key in each frontmatter format,
+// capturing the key
+var triggerKeyPatterns = map[parser.FrontmatterFormat]*regexp.Regexp{
+	parser.FrontmatterFormatYAML: regexp.MustCompile(`^\s*(on:)`),
+	parser.FrontmatterFormatTOML: regexp.MustCompile(`^\s*(on\s*=|\[on[\].])`),
+	parser.FrontmatterFormatJSON: regexp.MustCompile(`("on"\s*:)`),
+}
+
 // createTriggerParseError creates a detailed error for trigger parsing issues with source location
 func (c *Compiler) createTriggerParseError(filePath, content, triggerStr string, err error) error {
 	schedulePreprocessingLog.Printf("Creating trigger parse error for: %s", triggerStr)
 
 	lines := strings.Split(content, "\n")
 
-	// Find the line where "on:" appears in the frontmatter
+	// Find the line where the "on" key appears in the frontmatter
 	var onLine int
 	var onColumn int
-	inFrontmatter := false
-
-	for i, line := range lines {
-		lineNum := i + 1
 
-		// Check for frontmatter delimiter
-		if strings.TrimSpace(line) == "---" {
-			if !inFrontmatter {
-				inFrontmatter = true
-			} else {
-				// End of frontmatter
-				break
-			}
-			continue
-		}
-
-		if inFrontmatter {
-			// Look for "on:" field
-			trimmed := strings.TrimSpace(line)
-			if strings.HasPrefix(trimmed, "on:") {
-				onLine = lineNum
-				// Find the column where "on:" starts
-				onColumn = strings.Index(line, "on:") + 1
+	format, endIndex := parser.FrontmatterBounds(lines)
+	if endIndex == -1 {
+		endIndex = len(lines)
+	}
+	if keyPattern, ok := triggerKeyPatterns[format]; ok {
+		for i := 1; i < endIndex; i++ {
+			if match := keyPattern.FindStringSubmatchIndex(lines[i]); match != nil {
+				onLine = i + 1
+				// Point at the column where the key starts
+				onColumn = match[2] + 1
 				break
 			}
 		}
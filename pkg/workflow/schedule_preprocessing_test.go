@@ -3,6 +3,7 @@
 package workflow
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -480,6 +481,36 @@ func TestSchedulePreprocessing(t *testing.T) {
 			},
 			expectedCron: "0 9 * * 1",
 		},
+		{
+			name: "cron with named abbreviations and step ranges",
+			frontmatter: map[string]any{
+				"on": map[string]any{
+					"schedule": []any{
+						map[string]any{
+							"cron": "0-30/15 9 * JAN-JUN MON-FRI",
+						},
+					},
+				},
+			},
+			expectedCron: "0-30/15 9 * JAN-JUN MON-FRI",
+		},
+		{
+			name: "out of range cron in second schedule",
+			frontmatter: map[string]any{
+				"on": map[string]any{
+					"schedule": []any{
+						map[string]any{
+							"cron": "0 9 * * 1",
+						},
+						map[string]any{
+							"cron": "0 25 * * *",
+						},
+					},
+				},
+			},
+			expectedError:  true,
+			errorSubstring: "invalid cron expression '0 25 * * *' in item 1: field 2 (hour) \"25\": value 25 out of range 0-23",
+		},
 		{
 			name: "shorthand string format - invalid",
 			frontmatter: map[string]any{
@@ -1553,3 +1584,39 @@ func TestFuzzyScheduleDevModeDifferentFromReleaseMode(t *testing.T) {
 	t.Logf("Dev mode result: %s", devResult)
 	t.Logf("Release mode result: %s", releaseResult)
 }
+
+// TestCreateTriggerParseErrorPosition verifies that trigger errors point at the "on" key in
+// YAML, TOML, and JSON frontmatter
+func TestCreateTriggerParseErrorPosition(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		position string
+	}{
+		{
+			name:     "YAML",
+			content:  "---\nengine: copilot\non: daily at noon\n---\n# Body",
+			position: "test.md:3:1:",
+		},
+		{
+			name:     "TOML",
+			content:  "+++\nengine = \"copilot\"\n[on]\nschedule = \"daily at noon\"\n+++\n# Body",
+			position: "test.md:3:1:",
+		},
+		{
+			name:     "JSON",
+			content:  "```json\n{\n  \"engine\": \"copilot\",\n  \"on\": \"daily at noon\"\n}\n```\n# Body",
+			position: "test.md:4:3:",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compiler := NewCompiler()
+			err := compiler.createTriggerParseError("test.md", tt.content, "daily at noon", errors.New("invalid schedule"))
+			if !strings.Contains(err.Error(), tt.position) {
+				t.Errorf("Expected error at %s, got: %s", tt.position, err.Error())
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@
 //  2. validateStrictPermissions() - Refuses write permissions on sensitive scopes
 //  3. validateStrictNetwork() - Requires explicit network configuration
 //  4. validateStrictMCPNetwork() - Requires top-level network config for container-based MCP servers
+//  5. validateStrictSafeOutputKeys() - Refuses unknown safe-outputs keys, including imported ones
 //
 // # Integration with Security Scanners
 //
@@ -40,10 +41,12 @@
 package workflow
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"slices"
+	"sort"
 	"strings"
 
 	"github.com/github/gh-aw/pkg/console"
@@ -246,6 +249,65 @@ func (c *Compiler) validateStrictDeprecatedFields(frontmatter map[string]any) er
 	return nil
 }
 
+// validateStrictSafeOutputKeys refuses unknown keys under safe-outputs in strict mode.
+// Safe-outputs in the main workflow are already covered by schema validation, but
+// configurations merged from imported workflows are not, so a typo such as
+// 'create-isue' in a shared file would otherwise be dropped silently.
+func (c *Compiler) validateStrictSafeOutputKeys(frontmatter map[string]any, importedSafeOutputsJSON []string) error {
+	if !c.strictMode {
+		strictModeValidationLog.Printf("Strict mode disabled, skipping safe-outputs key validation")
+		return nil
+	}
+
+	knownKeys, err := parser.GetSafeOutputKeys()
+	if err != nil {
+		strictModeValidationLog.Printf("Failed to get safe-outputs keys: %v", err)
+		// Don't fail compilation if we can't load the known keys list
+		return nil
+	}
+
+	var configs []map[string]any
+	if safeOutputs, ok := frontmatter["safe-outputs"].(map[string]any); ok {
+		configs = append(configs, safeOutputs)
+	}
+	for _, configJSON := range importedSafeOutputsJSON {
+		var config map[string]any
+		if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
+			strictModeValidationLog.Printf("Skipping malformed imported safe-outputs config: %v", err)
+			continue
+		}
+		configs = append(configs, config)
+	}
+
+	var errorMessages []string
+	for _, config := range configs {
+		keys := make([]string, 0, len(config))
+		for key := range config {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if slices.Contains(knownKeys, key) {
+				continue
+			}
+			message := fmt.Sprintf("Unknown safe-outputs key '%s'", key)
+			if matches := parser.FindClosestMatches(key, knownKeys, 1); len(matches) > 0 {
+				message += fmt.Sprintf(". Did you mean '%s'?", matches[0])
+			}
+			errorMessages = append(errorMessages, message)
+		}
+	}
+
+	if len(errorMessages) > 0 {
+		strictModeValidationLog.Printf("Unknown safe-outputs keys found: %v", errorMessages)
+		return fmt.Errorf("strict mode: unknown safe-outputs keys are not allowed. %s", strings.Join(errorMessages, " "))
+	}
+
+	strictModeValidationLog.Printf("All safe-outputs keys are known")
+	return nil
+}
+
 // validateEnvSecrets detects secrets in the top-level env section and the engine.env section,
 // raising an error in strict mode or a warning in non-strict mode. Secrets in env will be
 // leaked to the agent container.
@@ -662,3 +662,73 @@ func TestValidateStrictCacheMemoryScope(t *testing.T) {
 		})
 	}
 }
+
+// TestValidateStrictSafeOutputKeys tests that unknown safe-outputs keys are refused in strict mode
+func TestValidateStrictSafeOutputKeys(t *testing.T) {
+	tests := []struct {
+		name        string
+		frontmatter map[string]any
+		imported    []string
+		strictMode  bool
+		expectError bool
+		errorMsg    string
+	}{
+		{
+			name: "known safe-outputs keys are allowed",
+			frontmatter: map[string]any{
+				"safe-outputs": map[string]any{
+					"create-issue": map[string]any{},
+					"staged":       true,
+				},
+			},
+			imported:    []string{`{"add-comment":{}}`},
+			strictMode:  true,
+			expectError: false,
+		},
+		{
+			name: "unknown key in frontmatter is refused with suggestion",
+			frontmatter: map[string]any{
+				"safe-outputs": map[string]any{
+					"create-isue": map[string]any{},
+				},
+			},
+			strictMode:  true,
+			expectError: true,
+			errorMsg:    "Unknown safe-outputs key 'create-isue'. Did you mean 'create-issue'?",
+		},
+		{
+			name:        "unknown key in imported config is refused",
+			frontmatter: map[string]any{},
+			imported:    []string{`{"add-coment":{}}`},
+			strictMode:  true,
+			expectError: true,
+			errorMsg:    "Unknown safe-outputs key 'add-coment'. Did you mean 'add-comment'?",
+		},
+		{
+			name:        "unknown key is allowed when strict mode is disabled",
+			frontmatter: map[string]any{},
+			imported:    []string{`{"add-coment":{}}`},
+			strictMode:  false,
+			expectError: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compiler := NewCompiler()
+			compiler.strictMode = tt.strictMode
+
+			err := compiler.validateStrictSafeOutputKeys(tt.frontmatter, tt.imported)
+
+			if tt.expectError && err == nil {
+				t.Error("Expected validation to fail but it succeeded")
+			} else if !tt.expectError && err != nil {
+				t.Errorf("Expected validation to succeed but it failed: %v", err)
+			} else if tt.expectError && err != nil && tt.errorMsg != "" {
+				if !strings.Contains(err.Error(), tt.errorMsg) {
+					t.Errorf("Expected error containing '%s', got '%s'", tt.errorMsg, err.Error())
+				}
+			}
+		})
+	}
+}
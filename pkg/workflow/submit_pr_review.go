@@ -1,6 +1,11 @@
 package workflow
 
 import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
 	"github.com/github/gh-aw/pkg/logger"
 )
 
@@ -12,8 +17,26 @@ var submitPRReviewLog = logger.New("workflow:submit_pr_review")
 // If this safe output type is not configured, review comments default to event: "COMMENT".
 type SubmitPullRequestReviewConfig struct {
 	BaseSafeOutputConfig `yaml:",inline"`
-	Target               string  `yaml:"target,omitempty"` // Target PR: "triggering" (default), "*" (use message.pull_request_number), or explicit number e.g. ${{ github.event.inputs.pr_number }}
-	Footer               *string `yaml:"footer,omitempty"` // Controls when to show footer in PR review body: "always" (default), "none", or "if-body" (only when review has body text)
+	Target               string   `yaml:"target,omitempty"`         // Target PR: "triggering" (default), "*" (use message.pull_request_number), or explicit number e.g. ${{ github.event.inputs.pr_number }}
+	Footer               *string  `yaml:"footer,omitempty"`         // Controls when to show footer in PR review body: "always" (default), "none", or "if-body" (only when review has body text)
+	AllowedEvents        []string `yaml:"allowed-events,omitempty"` // Review events the agent may submit (default: COMMENT and REQUEST_CHANGES)
+	AllowApprove         bool     `yaml:"allow-approve,omitempty"`  // Permits APPROVE reviews; off by default so agents cannot approve on their own
+}
+
+// submitReviewEvents lists the review events supported by GitHub
+var submitReviewEvents = []string{"APPROVE", "REQUEST_CHANGES", "COMMENT"}
+
+// effectiveAllowedEvents returns the review events the agent may submit.
+// Without allowed-events, COMMENT and REQUEST_CHANGES are allowed, plus APPROVE when allow-approve is set.
+func (c *SubmitPullRequestReviewConfig) effectiveAllowedEvents() []string {
+	if len(c.AllowedEvents) > 0 {
+		return c.AllowedEvents
+	}
+	events := []string{"COMMENT", "REQUEST_CHANGES"}
+	if c.AllowApprove {
+		events = append(events, "APPROVE")
+	}
+	return events
 }
 
 // parseSubmitPullRequestReviewConfig handles submit-pull-request-review configuration
@@ -39,6 +62,14 @@ func (c *Compiler) parseSubmitPullRequestReviewConfig(outputMap map[string]any)
 			}
 		}
 
+		// Parse allowed review events (normalized to upper case) and the APPROVE opt-in
+		if events := ParseStringArrayFromConfig(configMap, "allowed-events", submitPRReviewLog); len(events) > 0 {
+			for _, event := range events {
+				config.AllowedEvents = append(config.AllowedEvents, strings.ToUpper(event))
+			}
+		}
+		config.AllowApprove = ParseBoolFromConfig(configMap, "allow-approve", submitPRReviewLog)
+
 		// Parse footer configuration (string: "always"/"none"/"if-body", or bool for backward compat)
 		if footer, exists := configMap["footer"]; exists {
 			switch f := footer.(type) {
@@ -69,3 +100,24 @@ func (c *Compiler) parseSubmitPullRequestReviewConfig(outputMap map[string]any)
 
 	return config
 }
+
+// validateSubmitPullRequestReviewEvents validates the allowed-events of the submit-pull-request-review
+// safe output. Every event must be a GitHub review event, and APPROVE requires allow-approve: true.
+func validateSubmitPullRequestReviewEvents(config *SafeOutputsConfig) error {
+	if config == nil || config.SubmitPullRequestReview == nil {
+		return nil
+	}
+
+	review := config.SubmitPullRequestReview
+	for i, event := range review.AllowedEvents {
+		if !slices.Contains(submitReviewEvents, event) {
+			return fmt.Errorf("safe-outputs.submit-pull-request-review.allowed-events[%d] has invalid value %q. Expected one of: %s", i, event, strings.Join(submitReviewEvents, ", "))
+		}
+		if event == "APPROVE" && !review.AllowApprove {
+			return errors.New("safe-outputs.submit-pull-request-review.allowed-events includes APPROVE but allow-approve is not enabled. Set 'allow-approve: true' to let the agent approve pull requests")
+		}
+	}
+
+	submitPRReviewLog.Printf("Allowed review events: %v", review.effectiveAllowedEvents())
+	return nil
+}
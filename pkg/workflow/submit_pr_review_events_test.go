@@ -0,0 +1,87 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmitPRReviewAllowedEventsParsing(t *testing.T) {
+	compiler := NewCompiler()
+
+	config := compiler.parseSubmitPullRequestReviewConfig(map[string]any{
+		"submit-pull-request-review": map[string]any{
+			"allowed-events": []any{"comment", "APPROVE"},
+			"allow-approve":  true,
+		},
+	})
+	require.NotNil(t, config, "Config should be parsed")
+	assert.Equal(t, []string{"COMMENT", "APPROVE"}, config.AllowedEvents, "Events should be normalized to upper case")
+	assert.True(t, config.AllowApprove, "allow-approve should be parsed")
+
+	config = compiler.parseSubmitPullRequestReviewConfig(map[string]any{"submit-pull-request-review": nil})
+	require.NotNil(t, config, "Null config should enable the output")
+	assert.Equal(t, []string{"COMMENT", "REQUEST_CHANGES"}, config.effectiveAllowedEvents(), "APPROVE should be excluded by default")
+}
+
+func TestEffectiveAllowedEvents(t *testing.T) {
+	assert.Equal(t, []string{"COMMENT", "REQUEST_CHANGES", "APPROVE"},
+		(&SubmitPullRequestReviewConfig{AllowApprove: true}).effectiveAllowedEvents(),
+		"allow-approve should add APPROVE to the defaults")
+	assert.Equal(t, []string{"COMMENT"},
+		(&SubmitPullRequestReviewConfig{AllowedEvents: []string{"COMMENT"}}).effectiveAllowedEvents(),
+		"explicit allowed-events should be used as-is")
+}
+
+func TestValidateSubmitPullRequestReviewEvents(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *SafeOutputsConfig
+		wantErr string
+	}{
+		{
+			name:   "nil safe outputs",
+			config: nil,
+		},
+		{
+			name:   "defaults",
+			config: &SafeOutputsConfig{SubmitPullRequestReview: &SubmitPullRequestReviewConfig{}},
+		},
+		{
+			name: "approve with allow-approve",
+			config: &SafeOutputsConfig{SubmitPullRequestReview: &SubmitPullRequestReviewConfig{
+				AllowedEvents: []string{"APPROVE", "COMMENT"},
+				AllowApprove:  true,
+			}},
+		},
+		{
+			name: "approve without allow-approve",
+			config: &SafeOutputsConfig{SubmitPullRequestReview: &SubmitPullRequestReviewConfig{
+				AllowedEvents: []string{"COMMENT", "APPROVE"},
+			}},
+			wantErr: "allow-approve is not enabled",
+		},
+		{
+			name: "unknown event",
+			config: &SafeOutputsConfig{SubmitPullRequestReview: &SubmitPullRequestReviewConfig{
+				AllowedEvents: []string{"DISMISS"},
+			}},
+			wantErr: `allowed-events[0] has invalid value "DISMISS"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSubmitPullRequestReviewEvents(tt.config)
+			if tt.wantErr == "" {
+				assert.NoError(t, err, "Validation should pass")
+				return
+			}
+			require.Error(t, err, "Validation should fail")
+			assert.Contains(t, err.Error(), tt.wantErr, "Error message mismatch")
+		})
+	}
+}
@@ -12,6 +12,12 @@ func strPtr(s string) *string {
 	return &s
 }
 
+// float64Ptr returns a pointer to a float64 value.
+// This is a shared helper used by tests to create numeric input bounds.
+func float64Ptr(f float64) *float64 {
+	return &f
+}
+
 // mockValidationError helps create validation errors for testing.
 // This is a shared helper used by both unit and integration tests.
 type mockValidationError struct {
@@ -166,6 +166,7 @@ func enhanceToolDescription(toolName, baseDescription string, safeOutputs *SafeO
 			if templatableIntValue(config.Max) > 0 {
 				constraints = append(constraints, fmt.Sprintf("Maximum %d review(s) can be submitted.", templatableIntValue(config.Max)))
 			}
+			constraints = append(constraints, fmt.Sprintf("Allowed review events: %s.", strings.Join(config.effectiveAllowedEvents(), ", ")))
 		}
 
 	case "reply_to_pull_request_review_comment":
@@ -293,6 +294,31 @@ func enhanceToolDescription(toolName, baseDescription string, safeOutputs *SafeO
 			}
 		}
 
+	case "add_reaction":
+		if config := safeOutputs.AddReaction; config != nil {
+			if templatableIntValue(config.Max) > 0 {
+				constraints = append(constraints, fmt.Sprintf("Maximum %d reaction(s) can be added.", templatableIntValue(config.Max)))
+			}
+			if len(config.AllowedReactions) > 0 {
+				constraints = append(constraints, fmt.Sprintf("Only these reactions are allowed: %v.", config.AllowedReactions))
+			}
+		}
+
+	case "push_files":
+		if config := safeOutputs.PushFiles; config != nil {
+			if templatableIntValue(config.Max) > 0 {
+				constraints = append(constraints, fmt.Sprintf("Maximum %d push(es) can be made.", templatableIntValue(config.Max)))
+			}
+			if len(config.AllowedBranches) > 0 {
+				constraints = append(constraints, fmt.Sprintf("Only branches matching these patterns are allowed: %v.", config.AllowedBranches))
+			}
+			if len(config.AllowedPaths) > 0 {
+				constraints = append(constraints, fmt.Sprintf("Files must be under these paths: %v.", config.AllowedPaths))
+			} else {
+				constraints = append(constraints, "Files under .github/ cannot be pushed.")
+			}
+		}
+
 	case "assign_milestone":
 		if config := safeOutputs.AssignMilestone; config != nil {
 			if templatableIntValue(config.Max) > 0 {
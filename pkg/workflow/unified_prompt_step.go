@@ -696,6 +696,12 @@ func buildSafeOutputsSections(safeOutputs *SafeOutputsConfig) []PromptSection {
 	if safeOutputs.HideComment != nil {
 		tools = append(tools, "hide_comment")
 	}
+	if safeOutputs.AddReaction != nil {
+		tools = append(tools, "add_reaction")
+	}
+	if safeOutputs.PushFiles != nil {
+		tools = append(tools, "push_files")
+	}
 	if safeOutputs.DispatchWorkflow != nil {
 		tools = append(tools, "dispatch_workflow")
 	}
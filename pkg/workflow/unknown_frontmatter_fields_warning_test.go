@@ -0,0 +1,97 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+)
+
+// TestUnknownFrontmatterFieldsWarning tests that keys not defined by the schema are reported as warnings
+func TestUnknownFrontmatterFieldsWarning(t *testing.T) {
+	tests := []struct {
+		name            string
+		inputFields     string
+		expectWarnings  int
+		expectedPhrases []string
+	}{
+		{
+			name: "misspelled workflow_call input field",
+			inputFields: `        descripton: Target to process
+        requird: true`,
+			expectWarnings: 2,
+			expectedPhrases: []string{
+				"unknown field 'descripton' at 'on.workflow_call.inputs.target.descripton'. Did you mean 'description'?",
+				"unknown field 'requird' at 'on.workflow_call.inputs.target.requird'. Did you mean 'required'?",
+			},
+		},
+		{
+			name: "valid workflow_call input fields",
+			inputFields: `        description: Target to process
+        required: true`,
+			expectWarnings: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := testutil.TempDir(t, "unknown-fields-warning-test")
+
+			content := `---
+on:
+  workflow_call:
+    inputs:
+      target:
+` + tt.inputFields + `
+        type: string
+permissions:
+  contents: read
+engine: copilot
+---
+
+# Test Workflow
+`
+			testFile := filepath.Join(tmpDir, "test-workflow.md")
+			if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			// Capture stderr to check for warnings
+			oldStderr := os.Stderr
+			r, w, _ := os.Pipe()
+			os.Stderr = w
+
+			compiler := NewCompiler()
+			err := compiler.CompileWorkflow(testFile)
+
+			// Restore stderr
+			w.Close()
+			os.Stderr = oldStderr
+			var buf bytes.Buffer
+			io.Copy(&buf, r)
+			stderrOutput := buf.String()
+
+			if err != nil {
+				t.Fatalf("Expected compilation to succeed but it failed: %v", err)
+			}
+
+			for _, phrase := range tt.expectedPhrases {
+				if !strings.Contains(stderrOutput, phrase) {
+					t.Errorf("Expected warning to contain %q, got stderr:\n%s", phrase, stderrOutput)
+				}
+			}
+			if tt.expectWarnings == 0 && strings.Contains(stderrOutput, "unknown field") {
+				t.Errorf("Did not expect unknown field warnings, got stderr:\n%s", stderrOutput)
+			}
+			if got := compiler.GetWarningCount(); got < tt.expectWarnings {
+				t.Errorf("Expected at least %d warnings, got %d", tt.expectWarnings, got)
+			}
+		})
+	}
+}
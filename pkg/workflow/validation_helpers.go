@@ -14,6 +14,7 @@
 //   - ValidatePositiveInt() - Validates that a value is a positive integer
 //   - ValidateNonNegativeInt() - Validates that a value is a non-negative integer
 //   - validateMountStringFormat() - Parses and validates a "source:dest:mode" mount string
+//   - validateMountMode() - Validates a mount mode, optionally allowing SELinux relabel flags
 //
 // # Design Rationale
 //
@@ -155,13 +156,87 @@ func ValidateNonNegativeInt(field string, value int) error {
 // The error message describes which aspect of the format is invalid.
 // Callers are responsible for wrapping the error with context-appropriate error types.
 func validateMountStringFormat(mount string) (source, dest, mode string, err error) {
-	parts := strings.Split(mount, ":")
+	return validateMountStringFormatWithOptions(mount, false)
+}
+
+// validateMountStringFormatWithOptions is like validateMountStringFormat but, when
+// allowRelabel is true, also accepts SELinux relabel flags in the mode field
+// (e.g. "ro,z" or "rw,Z").
+func validateMountStringFormatWithOptions(mount string, allowRelabel bool) (source, dest, mode string, err error) {
+	parts := splitMountString(mount)
 	if len(parts) != 3 {
 		return "", "", "", errors.New("must follow 'source:destination:mode' format with exactly 3 colon-separated parts")
 	}
-	mode = parts[2]
-	if mode != "ro" && mode != "rw" {
-		return parts[0], parts[1], parts[2], fmt.Errorf("mode must be 'ro' or 'rw', got %q", mode)
+	if err := validateMountMode(parts[2], allowRelabel); err != nil {
+		return parts[0], parts[1], parts[2], err
 	}
 	return parts[0], parts[1], parts[2], nil
 }
+
+// splitMountString splits a mount string on its field-separating colons.
+// Colons inside ${...} variable references and a leading Windows drive letter
+// in the source (e.g. "C:\data" or "C:/data") are not treated as separators.
+func splitMountString(mount string) []string {
+	var parts []string
+	start := 0
+	depth := 0
+
+	i := 0
+	if isWindowsDrivePrefix(mount) {
+		i = 2 // Skip the drive letter and its colon
+	}
+	for ; i < len(mount); i++ {
+		switch {
+		case mount[i] == '$' && i+1 < len(mount) && mount[i+1] == '{':
+			depth++
+			i++
+		case mount[i] == '}' && depth > 0:
+			depth--
+		case mount[i] == ':' && depth == 0:
+			parts = append(parts, mount[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, mount[start:])
+}
+
+// isWindowsDrivePrefix reports whether s starts with a Windows drive path such as "C:\" or "C:/"
+func isWindowsDrivePrefix(s string) bool {
+	if len(s) < 3 || s[1] != ':' || (s[2] != '\\' && s[2] != '/') {
+		return false
+	}
+	c := s[0]
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// validateMountMode validates the mode field of a mount string.
+// The mode is a comma-separated list of options containing exactly one access mode
+// ("ro" or "rw"). When allowRelabel is true, one SELinux relabel flag ("z" for a shared
+// label or "Z" for a private label) may also be given.
+func validateMountMode(mode string, allowRelabel bool) error {
+	if !allowRelabel {
+		if mode != "ro" && mode != "rw" {
+			return fmt.Errorf("mode must be 'ro' or 'rw', got %q", mode)
+		}
+		return nil
+	}
+
+	var accessModes, relabelFlags int
+	for option := range strings.SplitSeq(mode, ",") {
+		switch option {
+		case "ro", "rw":
+			accessModes++
+		case "z", "Z":
+			relabelFlags++
+		default:
+			return fmt.Errorf("mode must be 'ro' or 'rw' with optional SELinux relabel flag 'z' or 'Z', got unknown option %q in %q", option, mode)
+		}
+	}
+	if accessModes != 1 {
+		return fmt.Errorf("mode must be 'ro' or 'rw' with optional SELinux relabel flag 'z' or 'Z', got %q (exactly one access mode is required)", mode)
+	}
+	if relabelFlags > 1 {
+		return fmt.Errorf("mode must be 'ro' or 'rw' with optional SELinux relabel flag 'z' or 'Z', got %q (at most one relabel flag is allowed)", mode)
+	}
+	return nil
+}
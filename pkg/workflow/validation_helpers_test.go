@@ -826,3 +826,36 @@ func TestValidateMountStringFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateMountMode(t *testing.T) {
+	tests := []struct {
+		name         string
+		mode         string
+		allowRelabel bool
+		wantErr      bool
+	}{
+		{name: "ro", mode: "ro", wantErr: false},
+		{name: "rw", mode: "rw", wantErr: false},
+		{name: "relabel rejected when not allowed", mode: "ro,z", wantErr: true},
+		{name: "ro,z", mode: "ro,z", allowRelabel: true, wantErr: false},
+		{name: "rw,Z", mode: "rw,Z", allowRelabel: true, wantErr: false},
+		{name: "z,ro order does not matter", mode: "z,ro", allowRelabel: true, wantErr: false},
+		{name: "plain ro with relabel allowed", mode: "ro", allowRelabel: true, wantErr: false},
+		{name: "missing access mode", mode: "Z", allowRelabel: true, wantErr: true},
+		{name: "two access modes", mode: "ro,rw", allowRelabel: true, wantErr: true},
+		{name: "two relabel flags", mode: "rw,z,Z", allowRelabel: true, wantErr: true},
+		{name: "unknown option", mode: "ro,shared", allowRelabel: true, wantErr: true},
+		{name: "empty option", mode: "ro,", allowRelabel: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMountMode(tt.mode, tt.allowRelabel)
+			if tt.wantErr {
+				assert.Error(t, err, "expected an error for mode %q", tt.mode)
+			} else {
+				assert.NoError(t, err, "unexpected error for mode %q", tt.mode)
+			}
+		})
+	}
+}
@@ -55,6 +55,7 @@ on:
   workflow_run:
     workflows: ["build"]
     types: [completed]
+engine: copilot
 tools:
   github:
     toolsets: [repos]
@@ -102,6 +103,7 @@ on:
     types: [completed]
     branches:
       - main
+engine: copilot
 tools:
   github:
     toolsets: [repos]